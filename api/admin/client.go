@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/rpcdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
@@ -22,6 +23,7 @@ type Client interface {
 	StopCPUProfiler(context.Context, ...rpc.Option) error
 	MemoryProfile(context.Context, ...rpc.Option) error
 	LockProfile(context.Context, ...rpc.Option) error
+	ListProfiles(context.Context, ...rpc.Option) ([]Profile, error)
 	Alias(ctx context.Context, endpoint string, alias string, options ...rpc.Option) error
 	AliasChain(ctx context.Context, chainID string, alias string, options ...rpc.Option) error
 	GetChainAliases(ctx context.Context, chainID string, options ...rpc.Option) ([]string, error)
@@ -31,6 +33,7 @@ type Client interface {
 	GetLoggerLevel(ctx context.Context, loggerName string, options ...rpc.Option) (map[string]LogAndDisplayLevels, error)
 	GetConfig(ctx context.Context, options ...rpc.Option) (interface{}, error)
 	DBGet(ctx context.Context, key []byte, options ...rpc.Option) ([]byte, error)
+	ExportPChainState(ctx context.Context, format string, height *uint64, options ...rpc.Option) (*ExportPChainStateReply, error)
 }
 
 // Client implementation for the Avalanche Platform Info API Endpoint
@@ -61,6 +64,12 @@ func (c *client) LockProfile(ctx context.Context, options ...rpc.Option) error {
 	return c.requester.SendRequest(ctx, "admin.lockProfile", struct{}{}, &api.EmptyReply{}, options...)
 }
 
+func (c *client) ListProfiles(ctx context.Context, options ...rpc.Option) ([]Profile, error) {
+	res := &ListProfilesReply{}
+	err := c.requester.SendRequest(ctx, "admin.listProfiles", struct{}{}, res, options...)
+	return res.Profiles, err
+}
+
 func (c *client) Alias(ctx context.Context, endpoint, alias string, options ...rpc.Option) error {
 	return c.requester.SendRequest(ctx, "admin.alias", &AliasArgs{
 		Endpoint: endpoint,
@@ -163,3 +172,16 @@ func (c *client) DBGet(ctx context.Context, key []byte, options ...rpc.Option) (
 	}
 	return formatting.Decode(formatting.HexNC, res.Value)
 }
+
+func (c *client) ExportPChainState(ctx context.Context, format string, height *uint64, options ...rpc.Option) (*ExportPChainStateReply, error) {
+	args := &ExportPChainStateArgs{
+		Format: format,
+	}
+	if height != nil {
+		h := json.Uint64(*height)
+		args.Height = &h
+	}
+	res := &ExportPChainStateReply{}
+	err := c.requester.SendRequest(ctx, "admin.exportPChainState", args, res, options...)
+	return res, err
+}
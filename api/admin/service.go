@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"path"
 	"sync"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 	"go.uber.org/zap"
@@ -26,6 +27,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/platformvm/export"
 	"github.com/ava-labs/avalanchego/vms/registry"
 
 	rpcdbpb "github.com/ava-labs/avalanchego/proto/pb/rpcdb"
@@ -39,10 +41,18 @@ const (
 )
 
 var (
-	errAliasTooLong = errors.New("alias length is too long")
-	errNoLogLevel   = errors.New("need to specify either displayLevel or logLevel")
+	errAliasTooLong          = errors.New("alias length is too long")
+	errNoLogLevel            = errors.New("need to specify either displayLevel or logLevel")
+	errNoPChainStateExporter = errors.New("P-chain state exporter isn't available")
 )
 
+// PChainStateExporter exports the state of the P-chain to a directory
+type PChainStateExporter interface {
+	// ExportState exports the state at [height], or at the last accepted
+	// block if [height] is nil.
+	ExportState(format export.Format, height *uint64) (string, *export.Metadata, error)
+}
+
 type Config struct {
 	Log          logging.Logger
 	ProfileDir   string
@@ -53,6 +63,8 @@ type Config struct {
 	HTTPServer   server.PathAdderWithReadLock
 	VMRegistry   registry.VMRegistry
 	VMManager    vms.Manager
+	// PChainStateExporter is optional
+	PChainStateExporter PChainStateExporter
 }
 
 // Admin is the API service for node admin management
@@ -130,6 +142,46 @@ func (a *Admin) LockProfile(_ *http.Request, _ *struct{}, _ *api.EmptyReply) err
 	return a.profiler.LockProfile()
 }
 
+// Profile is a profile written to the profile directory
+type Profile struct {
+	// Path of the profile, relative to the profile directory
+	Path     string      `json:"path"`
+	Size     json.Uint64 `json:"size"`
+	Modified time.Time   `json:"modified"`
+}
+
+// ListProfilesReply are the profiles in the profile directory
+type ListProfilesReply struct {
+	Profiles []Profile `json:"profiles"`
+}
+
+// ListProfiles returns the profiles written by the admin API, the continuous
+// profiler and the triggered profiler
+func (a *Admin) ListProfiles(_ *http.Request, _ *struct{}, reply *ListProfilesReply) error {
+	a.Log.Debug("API called",
+		zap.String("service", "admin"),
+		zap.String("method", "listProfiles"),
+	)
+
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	profiles, err := profiler.List(a.ProfileDir)
+	if err != nil {
+		return err
+	}
+
+	reply.Profiles = make([]Profile, len(profiles))
+	for i, profile := range profiles {
+		reply.Profiles[i] = Profile{
+			Path:     profile.Path,
+			Size:     json.Uint64(profile.Size),
+			Modified: profile.ModTime,
+		}
+	}
+	return nil
+}
+
 // AliasArgs are the arguments for calling Alias
 type AliasArgs struct {
 	Endpoint string `json:"endpoint"`
@@ -355,6 +407,77 @@ func (a *Admin) LoadVMs(r *http.Request, _ *struct{}, reply *LoadVMsReply) error
 	return err
 }
 
+// ExportPChainStateArgs are the arguments for calling ExportPChainState
+type ExportPChainStateArgs struct {
+	// Format of the exported files, either "json" or "csv". Defaults to
+	// "json".
+	Format string `json:"format"`
+	// Height of the exported state. Only the validator sets are exported at
+	// heights prior to the last accepted height. Defaults to the last accepted
+	// height.
+	Height *json.Uint64 `json:"height,omitempty"`
+}
+
+// ExportPChainStateReply describes the export written by ExportPChainState
+type ExportPChainStateReply struct {
+	// Dir is the directory the export was written to
+	Dir        string      `json:"dir"`
+	Height     json.Uint64 `json:"height"`
+	BlockID    ids.ID      `json:"blockID"`
+	Timestamp  json.Uint64 `json:"timestamp"`
+	Format     string      `json:"format"`
+	NumStakers json.Uint64 `json:"numStakers"`
+	NumSubnets json.Uint64 `json:"numSubnets"`
+	NumChains  json.Uint64 `json:"numChains"`
+	NumUTXOs   json.Uint64 `json:"numUTXOs"`
+	// ValidatorsOnly is true if only the validator sets were exported
+	ValidatorsOnly bool `json:"validatorsOnly"`
+}
+
+// ExportPChainState writes the stakers, subnets, chains and UTXOs of the
+// P-chain at its last accepted block, or the validator sets of the P-chain at
+// a prior height, to files in the chain data directory.
+func (a *Admin) ExportPChainState(_ *http.Request, args *ExportPChainStateArgs, reply *ExportPChainStateReply) error {
+	a.Log.Debug("API called",
+		zap.String("service", "admin"),
+		zap.String("method", "exportPChainState"),
+		logging.UserString("format", args.Format),
+	)
+
+	if a.PChainStateExporter == nil {
+		return errNoPChainStateExporter
+	}
+	format, err := export.ParseFormat(args.Format)
+	if err != nil {
+		return err
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	var height *uint64
+	if args.Height != nil {
+		h := uint64(*args.Height)
+		height = &h
+	}
+	dir, metadata, err := a.PChainStateExporter.ExportState(format, height)
+	if err != nil {
+		return err
+	}
+
+	reply.Dir = dir
+	reply.Height = json.Uint64(metadata.Height)
+	reply.BlockID = metadata.BlockID
+	reply.Timestamp = json.Uint64(metadata.Timestamp)
+	reply.Format = string(metadata.Format)
+	reply.NumStakers = json.Uint64(metadata.NumStakers)
+	reply.NumSubnets = json.Uint64(metadata.NumSubnets)
+	reply.NumChains = json.Uint64(metadata.NumChains)
+	reply.NumUTXOs = json.Uint64(metadata.NumUTXOs)
+	reply.ValidatorsOnly = metadata.ValidatorsOnly
+	return nil
+}
+
 func (a *Admin) getLoggerNames(loggerName string) []string {
 	if len(loggerName) == 0 {
 		// Empty name means all loggers
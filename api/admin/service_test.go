@@ -5,6 +5,8 @@ package admin
 
 import (
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -13,8 +15,11 @@ import (
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/platformvm/export"
 	"github.com/ava-labs/avalanchego/vms/registry"
 
 	rpcdbpb "github.com/ava-labs/avalanchego/proto/pb/rpcdb"
@@ -167,3 +172,95 @@ func TestServiceDBGet(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceListProfiles(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	a := &Admin{Config: Config{
+		Log:        logging.NoLog{},
+		ProfileDir: dir,
+	}}
+
+	reply := &ListProfilesReply{}
+	require.NoError(a.ListProfiles(nil, nil, reply))
+	require.Empty(reply.Profiles)
+
+	triggeredDir := filepath.Join(dir, "triggered", "20240101T000000Z-cpu")
+	require.NoError(os.MkdirAll(triggeredDir, perms.ReadWriteExecute))
+	require.NoError(perms.WriteFile(filepath.Join(triggeredDir, "cpu.profile"), make([]byte, 10), perms.ReadWrite))
+	require.NoError(perms.WriteFile(filepath.Join(dir, "mem.profile"), make([]byte, 5), perms.ReadWrite))
+
+	require.NoError(a.ListProfiles(nil, nil, reply))
+	require.Len(reply.Profiles, 2)
+	require.Equal("mem.profile", reply.Profiles[0].Path)
+	require.Equal(json.Uint64(5), reply.Profiles[0].Size)
+	require.Equal("triggered/20240101T000000Z-cpu/cpu.profile", reply.Profiles[1].Path)
+	require.Equal(json.Uint64(10), reply.Profiles[1].Size)
+}
+
+type testPChainStateExporter struct {
+	dir      string
+	metadata *export.Metadata
+	format   export.Format
+	height   *uint64
+}
+
+func (e *testPChainStateExporter) ExportState(format export.Format, height *uint64) (string, *export.Metadata, error) {
+	e.format = format
+	e.height = height
+	return e.dir, e.metadata, nil
+}
+
+func TestServiceExportPChainState(t *testing.T) {
+	require := require.New(t)
+
+	a := &Admin{Config: Config{
+		Log: logging.NoLog{},
+	}}
+	err := a.ExportPChainState(nil, &ExportPChainStateArgs{}, &ExportPChainStateReply{})
+	require.ErrorIs(err, errNoPChainStateExporter)
+
+	exporter := &testPChainStateExporter{
+		dir: "exports/5-csv",
+		metadata: &export.Metadata{
+			Height:     5,
+			BlockID:    ids.GenerateTestID(),
+			Timestamp:  10,
+			Format:     export.CSV,
+			NumStakers: 1,
+			NumSubnets: 2,
+			NumChains:  3,
+			NumUTXOs:   4,
+		},
+	}
+	a.PChainStateExporter = exporter
+
+	err = a.ExportPChainState(nil, &ExportPChainStateArgs{Format: "xml"}, &ExportPChainStateReply{})
+	require.ErrorIs(err, export.ErrUnknownFormat)
+
+	reply := &ExportPChainStateReply{}
+	require.NoError(a.ExportPChainState(nil, &ExportPChainStateArgs{Format: "csv"}, reply))
+	require.Equal(export.CSV, exporter.format)
+	require.Nil(exporter.height)
+	require.Equal(
+		&ExportPChainStateReply{
+			Dir:        exporter.dir,
+			Height:     5,
+			BlockID:    exporter.metadata.BlockID,
+			Timestamp:  10,
+			Format:     "csv",
+			NumStakers: 1,
+			NumSubnets: 2,
+			NumChains:  3,
+			NumUTXOs:   4,
+		},
+		reply,
+	)
+
+	height := json.Uint64(5)
+	require.NoError(a.ExportPChainState(nil, &ExportPChainStateArgs{Height: &height}, &ExportPChainStateReply{}))
+	require.Equal(export.JSON, exporter.format)
+	require.NotNil(exporter.height)
+	require.Equal(uint64(5), *exporter.height)
+}
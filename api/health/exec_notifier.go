@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxExecOutputLen is the maximum length of the output of an exec hook that is
+// included in an error.
+const maxExecOutputLen = 1024
+
+var _ Notifier = (*execNotifier)(nil)
+
+type execNotifier struct {
+	path    string
+	timeout time.Duration
+}
+
+// NewExecNotifier returns a Notifier that runs the executable at [path] for
+// each notification. The notification is written as JSON to the standard
+// input of the executable and is also provided in the HEALTH_* environment
+// variables. The executable is killed if it doesn't exit within [timeout].
+func NewExecNotifier(path string, timeout time.Duration) Notifier {
+	return &execNotifier{
+		path:    path,
+		timeout: timeout,
+	}
+}
+
+func (n *execNotifier) Notify(ctx context.Context, notification Notification) error {
+	stdin, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	var errString string
+	if notification.Error != nil {
+		errString = *notification.Error
+	}
+
+	cmd := exec.CommandContext(ctx, n.path)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Env = append(os.Environ(),
+		"HEALTH_NAMESPACE="+notification.Namespace,
+		"HEALTH_CHECK="+notification.Check,
+		"HEALTH_TAGS="+strings.Join(notification.Tags, ","),
+		"HEALTH_HEALTHY="+strconv.FormatBool(notification.Healthy),
+		"HEALTH_FLAPPING="+strconv.FormatBool(notification.Flapping),
+		"HEALTH_ERROR="+errString,
+		"HEALTH_TIMESTAMP="+notification.Timestamp.Format(time.RFC3339Nano),
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if len(output) > maxExecOutputLen {
+			output = output[:maxExecOutputLen]
+		}
+		return fmt.Errorf("%w: %q", err, output)
+	}
+	return nil
+}
@@ -63,9 +63,12 @@ type health struct {
 	readiness *worker
 	health    *worker
 	liveness  *worker
+	notifiers []*notifierQueue
 }
 
-func New(log logging.Logger, registerer prometheus.Registerer) (Health, error) {
+// New returns a Health that sends a Notification to each of [notifiers] when a
+// check changes state.
+func New(log logging.Logger, registerer prometheus.Registerer, notifiers ...Notifier) (Health, error) {
 	failingChecks := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "checks_failing",
@@ -73,12 +76,17 @@ func New(log logging.Logger, registerer prometheus.Registerer) (Health, error) {
 		},
 		[]string{CheckLabel, TagLabel},
 	)
-	return &health{
+	h := &health{
 		log:       log,
-		readiness: newWorker(log, "readiness", failingChecks),
-		health:    newWorker(log, "health", failingChecks),
-		liveness:  newWorker(log, "liveness", failingChecks),
-	}, registerer.Register(failingChecks)
+		notifiers: make([]*notifierQueue, len(notifiers)),
+	}
+	for i, notifier := range notifiers {
+		h.notifiers[i] = newNotifierQueue(log, notifier)
+	}
+	h.readiness = newWorker(log, "readiness", failingChecks, h.notify)
+	h.health = newWorker(log, "health", failingChecks, h.notify)
+	h.liveness = newWorker(log, "liveness", failingChecks, h.notify)
+	return h, registerer.Register(failingChecks)
 }
 
 func (h *health) RegisterReadinessCheck(name string, checker Checker, tags ...string) error {
@@ -127,6 +135,9 @@ func (h *health) Liveness(tags ...string) (map[string]Result, bool) {
 }
 
 func (h *health) Start(ctx context.Context, freq time.Duration) {
+	for _, notifier := range h.notifiers {
+		notifier.Start(ctx)
+	}
 	h.readiness.Start(ctx, freq)
 	h.health.Start(ctx, freq)
 	h.liveness.Start(ctx, freq)
@@ -136,4 +147,13 @@ func (h *health) Stop() {
 	h.readiness.Stop()
 	h.health.Stop()
 	h.liveness.Stop()
+	for _, notifier := range h.notifiers {
+		notifier.Stop()
+	}
+}
+
+func (h *health) notify(notification Notification) {
+	for _, notifier := range h.notifiers {
+		notifier.enqueue(notification)
+	}
 }
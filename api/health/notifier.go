@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// maxPendingNotifications is the number of notifications that may be queued
+// for a Notifier before new notifications are dropped.
+const maxPendingNotifications = 1024
+
+// Notification is sent when a check starts passing, starts failing, starts
+// flapping or stops flapping. While a check is flapping, no notifications are
+// sent when it starts passing or failing.
+type Notification struct {
+	// Namespace of the check. One of "readiness", "health" or "liveness".
+	Namespace string `json:"namespace"`
+	// Check is the name of the check.
+	Check string `json:"check"`
+	// Tags the check was registered with.
+	Tags []string `json:"tags"`
+	// Healthy is true if the check passed.
+	Healthy bool `json:"healthy"`
+	// Flapping is true if the check is flapping.
+	Flapping bool `json:"flapping"`
+	// Error returned by the check. The value is nil if the check passed.
+	Error *string `json:"error,omitempty"`
+	// Timestamp of the result that triggered the notification.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notifier delivers notifications to an external sink.
+type Notifier interface {
+	// Notify delivers [notification]. Notifications are delivered to a
+	// Notifier one at a time and in order, so Notify may block until
+	// [notification] was delivered or [ctx] is cancelled.
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// notifierQueue decouples the health checks from a Notifier, so that a slow
+// sink doesn't delay the health checks or the other sinks.
+type notifierQueue struct {
+	log      logging.Logger
+	notifier Notifier
+	pending  chan Notification
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+	cancel    context.CancelFunc
+}
+
+func newNotifierQueue(log logging.Logger, notifier Notifier) *notifierQueue {
+	return &notifierQueue{
+		log:      log,
+		notifier: notifier,
+		pending:  make(chan Notification, maxPendingNotifications),
+		cancel:   func() {},
+	}
+}
+
+// enqueue never blocks. If the queue is full, [notification] is dropped.
+func (q *notifierQueue) enqueue(notification Notification) {
+	select {
+	case q.pending <- notification:
+	default:
+		q.log.Warn("dropping health notification",
+			zap.String("reason", "too many pending notifications"),
+			zap.String("namespace", notification.Namespace),
+			zap.String("check", notification.Check),
+			zap.Bool("healthy", notification.Healthy),
+		)
+	}
+}
+
+func (q *notifierQueue) Start(ctx context.Context) {
+	q.startOnce.Do(func() {
+		ctx, q.cancel = context.WithCancel(context.WithoutCancel(ctx))
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+
+			for {
+				select {
+				case notification := <-q.pending:
+					if err := q.notifier.Notify(ctx, notification); err != nil {
+						q.log.Warn("failed to deliver health notification",
+							zap.String("namespace", notification.Namespace),
+							zap.String("check", notification.Check),
+							zap.Bool("healthy", notification.Healthy),
+							zap.Error(err),
+						)
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	})
+}
+
+// Stop cancels the delivery of the current notification and drops the
+// pending notifications.
+func (q *notifierQueue) Stop() {
+	q.closeOnce.Do(func() {
+		q.cancel()
+		q.wg.Wait()
+	})
+}
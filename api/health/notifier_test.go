@@ -0,0 +1,301 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type notificationRecorder struct {
+	notifications chan Notification
+}
+
+func (r *notificationRecorder) Notify(_ context.Context, notification Notification) error {
+	r.notifications <- notification
+	return nil
+}
+
+func TestNotifications(t *testing.T) {
+	require := require.New(t)
+
+	var checkErr utils.Atomic[error]
+	checkErr.Set(errUnhealthy)
+	check := CheckerFunc(func(context.Context) (interface{}, error) {
+		return nil, checkErr.Get()
+	})
+
+	recorder := &notificationRecorder{
+		notifications: make(chan Notification, maxPendingNotifications),
+	}
+	h, err := New(logging.NoLog{}, prometheus.NewRegistry(), recorder)
+	require.NoError(err)
+	require.NoError(h.RegisterHealthCheck("check", check, "tag"))
+
+	// Run the checks manually to control their results.
+	w := h.(*health).health
+	runCheck := func(err error) Result {
+		checkErr.Set(err)
+		w.runChecks(context.Background())
+		results, _ := h.Health()
+		return results["check"]
+	}
+	h.(*health).notifiers[0].Start(context.Background())
+	defer h.Stop()
+
+	result := runCheck(errUnhealthy)
+	require.Len(result.History, 1)
+	require.False(result.Flapping)
+
+	notification := <-recorder.notifications
+	require.Equal("health", notification.Namespace)
+	require.Equal("check", notification.Check)
+	require.Equal([]string{"tag"}, notification.Tags)
+	require.False(notification.Healthy)
+	require.False(notification.Flapping)
+	require.Equal(errUnhealthy.Error(), *notification.Error)
+
+	// Contiguous failures aren't reported.
+	runCheck(errUnhealthy)
+
+	result = runCheck(nil)
+	require.Len(result.History, 3)
+	notification = <-recorder.notifications
+	require.True(notification.Healthy)
+	require.Nil(notification.Error)
+
+	// The check starts flapping after [flapThreshold] transitions.
+	var lastErr error
+	toggle := func() error {
+		if lastErr == nil {
+			lastErr = errUnhealthy
+		} else {
+			lastErr = nil
+		}
+		return lastErr
+	}
+	for i := 1; i < flapThreshold-1; i++ {
+		result = runCheck(toggle())
+		require.False(result.Flapping)
+		notification = <-recorder.notifications
+		require.Equal(lastErr == nil, notification.Healthy)
+	}
+	result = runCheck(toggle())
+	require.True(result.Flapping)
+	notification = <-recorder.notifications
+	require.Equal(lastErr == nil, notification.Healthy)
+	require.True(notification.Flapping)
+
+	// Changes of a flapping check aren't reported until it stops flapping.
+	for i := 0; i < historySize; i++ {
+		result = runCheck(nil)
+	}
+	require.False(result.Flapping)
+	require.Len(result.History, historySize)
+
+	notification = <-recorder.notifications
+	require.True(notification.Healthy)
+	require.False(notification.Flapping)
+	require.Empty(recorder.notifications)
+}
+
+func TestShouldNotify(t *testing.T) {
+	errString := errUnhealthy.Error()
+	history := []HistoricalResult{{}}
+	tests := []struct {
+		name       string
+		prevResult Result
+		result     Result
+		expected   bool
+	}{
+		{
+			name:       "first result passing",
+			prevResult: notYetRunResult,
+			result:     Result{},
+		},
+		{
+			name:       "first result failing",
+			prevResult: notYetRunResult,
+			result:     Result{Error: &errString},
+			expected:   true,
+		},
+		{
+			name:       "still passing",
+			prevResult: Result{History: history},
+			result:     Result{},
+		},
+		{
+			name:       "started failing",
+			prevResult: Result{History: history},
+			result:     Result{Error: &errString},
+			expected:   true,
+		},
+		{
+			name:       "started passing",
+			prevResult: Result{Error: &errString, History: history},
+			result:     Result{},
+			expected:   true,
+		},
+		{
+			name:       "started flapping",
+			prevResult: Result{Error: &errString, History: history},
+			result:     Result{Flapping: true},
+			expected:   true,
+		},
+		{
+			name:       "flapping",
+			prevResult: Result{Error: &errString, Flapping: true, History: history},
+			result:     Result{Flapping: true},
+		},
+		{
+			name:       "stopped flapping",
+			prevResult: Result{Flapping: true, History: history},
+			result:     Result{},
+			expected:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, shouldNotify(test.prevResult, test.result))
+		})
+	}
+}
+
+func TestNotifierQueueDropsNotifications(t *testing.T) {
+	require := require.New(t)
+
+	q := newNotifierQueue(logging.NoLog{}, &notificationRecorder{})
+	for i := 0; i <= maxPendingNotifications; i++ {
+		q.enqueue(Notification{})
+	}
+	require.Len(q.pending, maxPendingNotifications)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	errString := errUnhealthy.Error()
+	notification := Notification{
+		Namespace: "health",
+		Check:     "check",
+		Tags:      []string{"tag"},
+		Error:     &errString,
+		Timestamp: time.Unix(1, 0).UTC(),
+	}
+
+	tests := []struct {
+		name             string
+		maxAttempts      int
+		numFailures      int
+		expectedErr      error
+		expectedAttempts int
+	}{
+		{
+			name:             "first attempt succeeds",
+			maxAttempts:      3,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "retry succeeds",
+			maxAttempts:      3,
+			numFailures:      2,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "all attempts fail",
+			maxAttempts:      3,
+			numFailures:      3,
+			expectedErr:      errUnexpectedStatus,
+			expectedAttempts: 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			var (
+				lock     sync.Mutex
+				attempts int
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				lock.Lock()
+				defer lock.Unlock()
+
+				attempts++
+				var got Notification
+				require.NoError(json.NewDecoder(r.Body).Decode(&got))
+				require.Equal(notification, got)
+				if attempts <= test.numFailures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			n := NewWebhookNotifier(server.URL, test.maxAttempts, time.Second).(*webhookNotifier)
+			n.retryDelay = time.Millisecond
+
+			err := n.Notify(context.Background(), notification)
+			require.ErrorIs(err, test.expectedErr)
+			require.Equal(test.expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestExecNotifier(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("exec hook test requires a POSIX shell")
+	}
+	require := require.New(t)
+
+	var (
+		dir        = t.TempDir()
+		scriptPath = filepath.Join(dir, "hook.sh")
+		stdinPath  = filepath.Join(dir, "stdin")
+		envPath    = filepath.Join(dir, "env")
+	)
+	script := "#!/bin/sh\n" +
+		"cat > " + stdinPath + "\n" +
+		"echo \"$HEALTH_NAMESPACE $HEALTH_CHECK $HEALTH_TAGS $HEALTH_HEALTHY $HEALTH_FLAPPING $HEALTH_ERROR\" > " + envPath + "\n"
+	require.NoError(os.WriteFile(scriptPath, []byte(script), 0o700))
+
+	errString := errUnhealthy.Error()
+	notification := Notification{
+		Namespace: "liveness",
+		Check:     "check",
+		Tags:      []string{"a", "b"},
+		Flapping:  true,
+		Error:     &errString,
+		Timestamp: time.Unix(1, 0).UTC(),
+	}
+	n := NewExecNotifier(scriptPath, 10*time.Second)
+	require.NoError(n.Notify(context.Background(), notification))
+
+	stdin, err := os.ReadFile(stdinPath)
+	require.NoError(err)
+	var got Notification
+	require.NoError(json.Unmarshal(stdin, &got))
+	require.Equal(notification, got)
+
+	env, err := os.ReadFile(envPath)
+	require.NoError(err)
+	require.Equal("liveness check a,b false true unhealthy\n", string(env))
+
+	// Failing hooks report their output.
+	failingPath := filepath.Join(dir, "failing.sh")
+	require.NoError(os.WriteFile(failingPath, []byte("#!/bin/sh\necho oops\nexit 1\n"), 0o700))
+	err = NewExecNotifier(failingPath, 10*time.Second).Notify(context.Background(), notification)
+	require.ErrorContains(err, "oops")
+}
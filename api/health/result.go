@@ -5,6 +5,15 @@ package health
 
 import "time"
 
+const (
+	// historySize is the number of recent results of a HealthCheck that are
+	// kept.
+	historySize = 20
+	// flapThreshold is the number of times a HealthCheck must have changed
+	// between passing and failing within its history to be flapping.
+	flapThreshold = 5
+)
+
 // notYetRunResult is the result that is returned when a HealthCheck hasn't been
 // run yet.
 var notYetRunResult Result
@@ -35,4 +44,49 @@ type Result struct {
 
 	// TimeOfFirstFailure of the HealthCheck,
 	TimeOfFirstFailure *time.Time `json:"timeOfFirstFailure,omitempty"`
+
+	// History of the most recent results of the HealthCheck, oldest first.
+	// Includes the last result.
+	History []HistoricalResult `json:"history,omitempty"`
+
+	// Flapping is true if the HealthCheck frequently changed between passing
+	// and failing within its History.
+	Flapping bool `json:"flapping,omitempty"`
+}
+
+// HistoricalResult is a summary of a past result of a HealthCheck.
+type HistoricalResult struct {
+	// Error is the string representation of the error returned by the failing
+	// HealthCheck. The value is nil if the check passed.
+	Error *string `json:"error,omitempty"`
+
+	// Timestamp of the HealthCheck.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Duration is the amount of time the HealthCheck took to evaluate.
+	Duration time.Duration `json:"duration"`
+}
+
+// appendHistory returns [history] with [result] appended, keeping at most
+// [historySize] results. [history] is not modified, so that it can be shared
+// with previously returned Results.
+func appendHistory(history []HistoricalResult, result HistoricalResult) []HistoricalResult {
+	if len(history) >= historySize {
+		history = history[len(history)-historySize+1:]
+	}
+	newHistory := make([]HistoricalResult, len(history), len(history)+1)
+	copy(newHistory, history)
+	return append(newHistory, result)
+}
+
+// isFlapping returns true if [history] changed between passing and failing at
+// least [flapThreshold] times.
+func isFlapping(history []HistoricalResult) bool {
+	var numTransitions int
+	for i := 1; i < len(history); i++ {
+		if (history[i-1].Error == nil) != (history[i].Error == nil) {
+			numTransitions++
+		}
+	}
+	return numTransitions >= flapThreshold
 }
@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendHistory(t *testing.T) {
+	require := require.New(t)
+
+	var history []HistoricalResult
+	for i := 0; i < historySize; i++ {
+		history = appendHistory(history, HistoricalResult{
+			Timestamp: time.Unix(int64(i), 0),
+		})
+	}
+	require.Len(history, historySize)
+
+	newHistory := appendHistory(history, HistoricalResult{
+		Timestamp: time.Unix(historySize, 0),
+	})
+	require.Len(newHistory, historySize)
+	require.Equal(time.Unix(1, 0), newHistory[0].Timestamp)
+	require.Equal(time.Unix(historySize, 0), newHistory[historySize-1].Timestamp)
+
+	// The previous history isn't modified.
+	require.Equal(time.Unix(0, 0), history[0].Timestamp)
+}
+
+func TestIsFlapping(t *testing.T) {
+	errString := errUnhealthy.Error()
+	passing := HistoricalResult{}
+	failing := HistoricalResult{Error: &errString}
+
+	tests := []struct {
+		name     string
+		history  []HistoricalResult
+		expected bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:    "stable",
+			history: []HistoricalResult{failing, failing, passing, passing, passing, passing},
+		},
+		{
+			name:    "below threshold",
+			history: []HistoricalResult{passing, failing, passing, failing, passing},
+		},
+		{
+			name:     "at threshold",
+			history:  []HistoricalResult{passing, failing, passing, failing, passing, failing},
+			expected: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, isFlapping(test.history))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// initialWebhookRetryDelay is the delay before the first retry of a failed
+// webhook call. The delay doubles after every attempt.
+const initialWebhookRetryDelay = time.Second
+
+var (
+	_ Notifier = (*webhookNotifier)(nil)
+
+	errUnexpectedStatus = errors.New("unexpected status")
+)
+
+type webhookNotifier struct {
+	url         string
+	client      *http.Client
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+// NewWebhookNotifier returns a Notifier that POSTs each notification as JSON
+// to [url]. Each attempt must complete within [timeout]. Failed attempts are
+// retried with an exponential backoff until [maxAttempts] attempts were made.
+func NewWebhookNotifier(url string, maxAttempts int, timeout time.Duration) Notifier {
+	return &webhookNotifier{
+		url: url,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+		maxAttempts: maxAttempts,
+		retryDelay:  initialWebhookRetryDelay,
+	}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	var (
+		retryDelay = n.retryDelay
+		errs       []error
+	)
+	for attempt := 1; ; attempt++ {
+		err := n.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("attempt %d: %w", attempt, err))
+		if attempt >= n.maxAttempts {
+			return errors.Join(errs...)
+		}
+
+		timer := time.NewTimer(retryDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+		retryDelay *= 2
+	}
+}
+
+func (n *webhookNotifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s", errUnexpectedStatus, resp.Status)
+	}
+	return nil
+}
@@ -31,8 +31,11 @@ type worker struct {
 	log           logging.Logger
 	name          string
 	failingChecks *prometheus.GaugeVec
-	checksLock    sync.RWMutex
-	checks        map[string]*taggedChecker
+	// notify is called with the resultsLock held and must not block.
+	notify func(Notification)
+
+	checksLock sync.RWMutex
+	checks     map[string]*taggedChecker
 
 	resultsLock                 sync.RWMutex
 	results                     map[string]Result
@@ -55,6 +58,7 @@ func newWorker(
 	log logging.Logger,
 	name string,
 	failingChecks *prometheus.GaugeVec,
+	notify func(Notification),
 ) *worker {
 	// Initialize the number of failing checks to 0 for all checks
 	for _, tag := range []string{AllTag, ApplicationTag} {
@@ -67,6 +71,7 @@ func newWorker(
 		log:           log,
 		name:          name,
 		failingChecks: failingChecks,
+		notify:        notify,
 		checks:        make(map[string]*taggedChecker),
 		results:       make(map[string]Result),
 		closer:        make(chan struct{}),
@@ -265,7 +270,45 @@ func (w *worker) runCheck(ctx context.Context, wg *sync.WaitGroup, name string,
 		)
 		w.updateMetrics(check, true /*=healthy*/, false /*=register*/)
 	}
+
+	result.History = appendHistory(prevResult.History, HistoricalResult{
+		Error:     result.Error,
+		Timestamp: end,
+		Duration:  result.Duration,
+	})
+	result.Flapping = isFlapping(result.History)
 	w.results[name] = result
+
+	if shouldNotify(prevResult, result) {
+		w.notify(Notification{
+			Namespace: w.name,
+			Check:     name,
+			Tags:      check.tags,
+			Healthy:   err == nil,
+			Flapping:  result.Flapping,
+			Error:     result.Error,
+			Timestamp: end,
+		})
+	}
+}
+
+// shouldNotify returns true if a notification should be sent when a check
+// reports [result] after having reported [prevResult].
+func shouldNotify(prevResult, result Result) bool {
+	switch {
+	case result.Flapping != prevResult.Flapping:
+		return true
+	case result.Flapping:
+		// Changes of a flapping check are only reported once it stops
+		// flapping.
+		return false
+	case len(prevResult.History) == 0:
+		// Every check is failing before it is first run, so only report the
+		// first result if it failed.
+		return result.Error != nil
+	default:
+		return (prevResult.Error == nil) != (result.Error == nil)
+	}
 }
 
 // updateMetrics updates the metrics for the given check. If [healthy] is true,
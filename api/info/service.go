@@ -17,6 +17,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils"
@@ -44,6 +45,7 @@ type Info struct {
 	chainManager chains.Manager
 	vmManager    vms.Manager
 	benchlist    benchlist.Manager
+	reputation   reputation.Tracker
 }
 
 type Parameters struct {
@@ -64,6 +66,7 @@ func NewService(
 	myIP *utils.Atomic[netip.AddrPort],
 	network network.Network,
 	benchlist benchlist.Manager,
+	reputation reputation.Tracker,
 ) (http.Handler, error) {
 	server := rpc.NewServer()
 	codec := json.NewCodec()
@@ -79,6 +82,7 @@ func NewService(
 			myIP:         myIP,
 			networking:   network,
 			benchlist:    benchlist,
+			reputation:   reputation,
 		},
 		"info",
 	)
@@ -209,7 +213,8 @@ type PeersArgs struct {
 type Peer struct {
 	peer.Info
 
-	Benched []string `json:"benched"`
+	Benched    []string         `json:"benched"`
+	Reputation reputation.Score `json:"reputation"`
 }
 
 // PeersReply are the results from calling Peers
@@ -240,8 +245,9 @@ func (i *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply) error
 			benchedAliases[idx] = alias
 		}
 		peerInfo[index] = Peer{
-			Info:    peer,
-			Benched: benchedAliases,
+			Info:       peer,
+			Benched:    benchedAliases,
+			Reputation: i.reputation.Get(peer.ID),
 		}
 	}
 
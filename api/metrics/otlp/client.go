@@ -0,0 +1,176 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/trace"
+
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+)
+
+const (
+	// httpPath is the default path of the OTLP/HTTP metrics endpoint.
+	httpPath = "/v1/metrics"
+
+	// maxErrorBodyLen is the maximum length of a response body that is
+	// included in an error.
+	maxErrorBodyLen = 1024
+)
+
+var (
+	_ client = (*grpcClient)(nil)
+	_ client = (*httpClient)(nil)
+
+	errUnknownExporterType = errors.New("unknown exporter type")
+	errUnexpectedStatus    = errors.New("unexpected status")
+	errPartialSuccess      = errors.New("metrics partially rejected")
+)
+
+// client sends metrics to an OTLP endpoint.
+type client interface {
+	export(ctx context.Context, request *collectormetricspb.ExportMetricsServiceRequest) error
+	close() error
+}
+
+func newClient(config trace.ExporterConfig) (client, error) {
+	switch config.Type {
+	case trace.GRPC:
+		return newGRPCClient(config)
+	case trace.HTTP:
+		return newHTTPClient(config), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownExporterType, config.Type)
+	}
+}
+
+type grpcClient struct {
+	conn    *grpc.ClientConn
+	client  collectormetricspb.MetricsServiceClient
+	headers metadata.MD
+}
+
+func newGRPCClient(config trace.ExporterConfig) (*grpcClient, error) {
+	creds := insecure.NewCredentials()
+	if !config.Insecure {
+		creds = credentials.NewTLS(&tls.Config{
+			MinVersion: tls.VersionTLS12,
+		})
+	}
+	// Dialing doesn't block, so an unavailable endpoint only causes exports
+	// to fail.
+	conn, err := grpc.Dial(config.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	return &grpcClient{
+		conn:    conn,
+		client:  collectormetricspb.NewMetricsServiceClient(conn),
+		headers: metadata.New(config.Headers),
+	}, nil
+}
+
+func (c *grpcClient) export(ctx context.Context, request *collectormetricspb.ExportMetricsServiceRequest) error {
+	ctx = metadata.NewOutgoingContext(ctx, c.headers)
+	response, err := c.client.Export(ctx, request)
+	if err != nil {
+		return err
+	}
+	return checkPartialSuccess(response)
+}
+
+func (c *grpcClient) close() error {
+	return c.conn.Close()
+}
+
+type httpClient struct {
+	url     string
+	headers map[string]string
+	client  http.Client
+}
+
+func newHTTPClient(config trace.ExporterConfig) *httpClient {
+	scheme := "https"
+	if config.Insecure {
+		scheme = "http"
+	}
+	u := url.URL{
+		Scheme: scheme,
+		Host:   config.Endpoint,
+		Path:   httpPath,
+	}
+	return &httpClient{
+		url:     u.String(),
+		headers: config.Headers,
+	}
+}
+
+func (c *httpClient) export(ctx context.Context, request *collectormetricspb.ExportMetricsServiceRequest) error {
+	body, err := proto.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if len(responseBody) > maxErrorBodyLen {
+			responseBody = responseBody[:maxErrorBodyLen]
+		}
+		return fmt.Errorf("%w: %s: %q", errUnexpectedStatus, resp.Status, responseBody)
+	}
+
+	response := &collectormetricspb.ExportMetricsServiceResponse{}
+	if err := proto.Unmarshal(responseBody, response); err != nil {
+		return err
+	}
+	return checkPartialSuccess(response)
+}
+
+func (*httpClient) close() error {
+	return nil
+}
+
+func checkPartialSuccess(response *collectormetricspb.ExportMetricsServiceResponse) error {
+	partialSuccess := response.GetPartialSuccess()
+	if partialSuccess.GetRejectedDataPoints() == 0 && partialSuccess.GetErrorMessage() == "" {
+		return nil
+	}
+	return fmt.Errorf("%w: %d data points rejected: %s",
+		errPartialSuccess,
+		partialSuccess.GetRejectedDataPoints(),
+		partialSuccess.GetErrorMessage(),
+	)
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package otlp
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/ava-labs/avalanchego/trace"
+)
+
+var errNonPositiveFrequency = errors.New("export frequency must be positive")
+
+// Config of the OTLP metrics exporter.
+type Config struct {
+	// The exporter is configured in the same way as the trace exporter.
+	trace.ExporterConfig `json:"exporterConfig"`
+
+	// Used to flag if metrics should be exported
+	Enabled bool `json:"enabled"`
+
+	// Frequency at which metrics are exported
+	Frequency time.Duration `json:"frequency"`
+
+	// Include are patterns of the metric families to export. If empty, every
+	// metric family is exported. Patterns are matched with [path.Match].
+	Include []string `json:"include"`
+
+	// Exclude are patterns of the metric families to not export. Exclude takes
+	// precedence over Include.
+	Exclude []string `json:"exclude"`
+
+	AppName string `json:"appName"`
+	Version string `json:"version"`
+}
+
+func (c *Config) Verify() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Frequency <= 0 {
+		return errNonPositiveFrequency
+	}
+	for _, patterns := range [][]string{c.Include, c.Exclude} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%w: %q", err, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// exports returns true if the metric family [name] should be exported.
+func (c *Config) exports(name string) bool {
+	if matchesAny(c.Exclude, name) {
+		return false
+	}
+	return len(c.Include) == 0 || matchesAny(c.Include, name)
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		// Patterns are verified, so the error can be ignored.
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
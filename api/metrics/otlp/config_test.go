@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package otlp
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name: "valid",
+			config: Config{
+				Enabled:   true,
+				Frequency: time.Second,
+				Include:   []string{"avalanche_P_*"},
+				Exclude:   []string{"avalanche_P_vm_*"},
+			},
+		},
+		{
+			name: "non-positive frequency",
+			config: Config{
+				Enabled: true,
+			},
+			expectedErr: errNonPositiveFrequency,
+		},
+		{
+			name: "invalid include pattern",
+			config: Config{
+				Enabled:   true,
+				Frequency: time.Second,
+				Include:   []string{"["},
+			},
+			expectedErr: path.ErrBadPattern,
+		},
+		{
+			name: "invalid exclude pattern",
+			config: Config{
+				Enabled:   true,
+				Frequency: time.Second,
+				Exclude:   []string{"["},
+			},
+			expectedErr: path.ErrBadPattern,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+func TestConfigExports(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		family   string
+		expected bool
+	}{
+		{
+			name:     "no filters",
+			family:   "avalanche_P_blks_accepted_count",
+			expected: true,
+		},
+		{
+			name: "included",
+			config: Config{
+				Include: []string{"avalanche_X_*", "avalanche_P_*"},
+			},
+			family:   "avalanche_P_blks_accepted_count",
+			expected: true,
+		},
+		{
+			name: "not included",
+			config: Config{
+				Include: []string{"avalanche_X_*"},
+			},
+			family: "avalanche_P_blks_accepted_count",
+		},
+		{
+			name: "excluded",
+			config: Config{
+				Exclude: []string{"avalanche_P_blks_*"},
+			},
+			family: "avalanche_P_blks_accepted_count",
+		},
+		{
+			name: "exclude takes precedence",
+			config: Config{
+				Include: []string{"avalanche_P_*"},
+				Exclude: []string{"avalanche_P_blks_*"},
+			},
+			family: "avalanche_P_blks_accepted_count",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.config.exports(test.family))
+		})
+	}
+}
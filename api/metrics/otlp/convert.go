@@ -0,0 +1,159 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package otlp
+
+import (
+	"math"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+// convert returns the OTLP representation of [families]. Prometheus labels,
+// such as the chain label, are converted to attributes.
+//
+// Counters, histograms and summaries are cumulative since [startTime]. Gauge
+// histograms and metric families of unknown types are not converted.
+func convert(families []*dto.MetricFamily, startTime time.Time, now time.Time) []*metricspb.Metric {
+	var (
+		startTimeUnixNano = uint64(startTime.UnixNano())
+		nowUnixNano       = uint64(now.UnixNano())
+		metrics           = make([]*metricspb.Metric, 0, len(families))
+	)
+	for _, family := range families {
+		metric := &metricspb.Metric{
+			Name:        family.GetName(),
+			Description: family.GetHelp(),
+		}
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			sum := &metricspb.Sum{
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				IsMonotonic:            true,
+				DataPoints:             make([]*metricspb.NumberDataPoint, len(family.Metric)),
+			}
+			for i, m := range family.Metric {
+				sum.DataPoints[i] = &metricspb.NumberDataPoint{
+					Attributes:        attributes(m.Label),
+					StartTimeUnixNano: startTimeUnixNano,
+					TimeUnixNano:      timestamp(m, nowUnixNano),
+					Value: &metricspb.NumberDataPoint_AsDouble{
+						AsDouble: m.GetCounter().GetValue(),
+					},
+				}
+			}
+			metric.Data = &metricspb.Metric_Sum{Sum: sum}
+		case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+			gauge := &metricspb.Gauge{
+				DataPoints: make([]*metricspb.NumberDataPoint, len(family.Metric)),
+			}
+			for i, m := range family.Metric {
+				value := m.GetGauge().GetValue()
+				if family.GetType() == dto.MetricType_UNTYPED {
+					value = m.GetUntyped().GetValue()
+				}
+				gauge.DataPoints[i] = &metricspb.NumberDataPoint{
+					Attributes:   attributes(m.Label),
+					TimeUnixNano: timestamp(m, nowUnixNano),
+					Value: &metricspb.NumberDataPoint_AsDouble{
+						AsDouble: value,
+					},
+				}
+			}
+			metric.Data = &metricspb.Metric_Gauge{Gauge: gauge}
+		case dto.MetricType_HISTOGRAM:
+			histogram := &metricspb.Histogram{
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				DataPoints:             make([]*metricspb.HistogramDataPoint, len(family.Metric)),
+			}
+			for i, m := range family.Metric {
+				histogram.DataPoints[i] = convertHistogram(m, startTimeUnixNano, nowUnixNano)
+			}
+			metric.Data = &metricspb.Metric_Histogram{Histogram: histogram}
+		case dto.MetricType_SUMMARY:
+			summary := &metricspb.Summary{
+				DataPoints: make([]*metricspb.SummaryDataPoint, len(family.Metric)),
+			}
+			for i, m := range family.Metric {
+				s := m.GetSummary()
+				dataPoint := &metricspb.SummaryDataPoint{
+					Attributes:        attributes(m.Label),
+					StartTimeUnixNano: startTimeUnixNano,
+					TimeUnixNano:      timestamp(m, nowUnixNano),
+					Count:             s.GetSampleCount(),
+					Sum:               s.GetSampleSum(),
+					QuantileValues:    make([]*metricspb.SummaryDataPoint_ValueAtQuantile, len(s.Quantile)),
+				}
+				for j, q := range s.Quantile {
+					dataPoint.QuantileValues[j] = &metricspb.SummaryDataPoint_ValueAtQuantile{
+						Quantile: q.GetQuantile(),
+						Value:    q.GetValue(),
+					}
+				}
+				summary.DataPoints[i] = dataPoint
+			}
+			metric.Data = &metricspb.Metric_Summary{Summary: summary}
+		default:
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+// convertHistogram converts the cumulative buckets of a Prometheus histogram
+// into the per-bucket counts of an OTLP histogram.
+func convertHistogram(m *dto.Metric, startTimeUnixNano uint64, nowUnixNano uint64) *metricspb.HistogramDataPoint {
+	var (
+		h       = m.GetHistogram()
+		buckets = h.Bucket
+		sum     = h.GetSampleSum()
+	)
+	// The +Inf bucket is implicit in OTLP.
+	if len(buckets) > 0 && math.IsInf(buckets[len(buckets)-1].GetUpperBound(), 1) {
+		buckets = buckets[:len(buckets)-1]
+	}
+
+	dataPoint := &metricspb.HistogramDataPoint{
+		Attributes:        attributes(m.Label),
+		StartTimeUnixNano: startTimeUnixNano,
+		TimeUnixNano:      timestamp(m, nowUnixNano),
+		Count:             h.GetSampleCount(),
+		Sum:               &sum,
+		BucketCounts:      make([]uint64, len(buckets)+1),
+		ExplicitBounds:    make([]float64, len(buckets)),
+	}
+	var prevCount uint64
+	for i, bucket := range buckets {
+		count := bucket.GetCumulativeCount()
+		dataPoint.ExplicitBounds[i] = bucket.GetUpperBound()
+		dataPoint.BucketCounts[i] = count - prevCount
+		prevCount = count
+	}
+	dataPoint.BucketCounts[len(buckets)] = h.GetSampleCount() - prevCount
+	return dataPoint
+}
+
+func attributes(labels []*dto.LabelPair) []*commonpb.KeyValue {
+	if len(labels) == 0 {
+		return nil
+	}
+	attributes := make([]*commonpb.KeyValue, len(labels))
+	for i, label := range labels {
+		attributes[i] = stringKeyValue(label.GetName(), label.GetValue())
+	}
+	return attributes
+}
+
+// timestamp returns the time at which [m] was observed. Most metrics don't
+// specify a timestamp, in which case they were observed when they were
+// gathered.
+func timestamp(m *dto.Metric, nowUnixNano uint64) uint64 {
+	if m.TimestampMs == nil {
+		return nowUnixNano
+	}
+	return uint64(m.GetTimestampMs()) * uint64(time.Millisecond)
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package otlp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+func TestConvert(t *testing.T) {
+	require := require.New(t)
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "counter",
+			Help: "counter help",
+		},
+		[]string{"chain"},
+	)
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gauge",
+		Help: "gauge help",
+	})
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "histogram",
+		Help:    "histogram help",
+		Buckets: []float64{1, 10},
+	})
+	summary := prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "summary",
+		Help:       "summary help",
+		Objectives: map[float64]float64{0.5: 0.05},
+	})
+	require.NoError(registry.Register(counter))
+	require.NoError(registry.Register(gauge))
+	require.NoError(registry.Register(histogram))
+	require.NoError(registry.Register(summary))
+
+	counter.WithLabelValues("P").Add(2)
+	gauge.Set(3)
+	histogram.Observe(0.5)
+	histogram.Observe(5)
+	histogram.Observe(50)
+	summary.Observe(4)
+
+	families, err := registry.Gather()
+	require.NoError(err)
+
+	var (
+		startTime = time.Unix(1, 0)
+		now       = time.Unix(2, 0)
+		histSum   = 55.5
+	)
+	expected := []*metricspb.Metric{
+		{
+			Name:        "counter",
+			Description: "counter help",
+			Data: &metricspb.Metric_Sum{
+				Sum: &metricspb.Sum{
+					AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+					IsMonotonic:            true,
+					DataPoints: []*metricspb.NumberDataPoint{
+						{
+							Attributes: []*commonpb.KeyValue{
+								stringKeyValue("chain", "P"),
+							},
+							StartTimeUnixNano: uint64(startTime.UnixNano()),
+							TimeUnixNano:      uint64(now.UnixNano()),
+							Value:             &metricspb.NumberDataPoint_AsDouble{AsDouble: 2},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "gauge",
+			Description: "gauge help",
+			Data: &metricspb.Metric_Gauge{
+				Gauge: &metricspb.Gauge{
+					DataPoints: []*metricspb.NumberDataPoint{
+						{
+							TimeUnixNano: uint64(now.UnixNano()),
+							Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: 3},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "histogram",
+			Description: "histogram help",
+			Data: &metricspb.Metric_Histogram{
+				Histogram: &metricspb.Histogram{
+					AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+					DataPoints: []*metricspb.HistogramDataPoint{
+						{
+							StartTimeUnixNano: uint64(startTime.UnixNano()),
+							TimeUnixNano:      uint64(now.UnixNano()),
+							Count:             3,
+							Sum:               &histSum,
+							BucketCounts:      []uint64{1, 1, 1},
+							ExplicitBounds:    []float64{1, 10},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "summary",
+			Description: "summary help",
+			Data: &metricspb.Metric_Summary{
+				Summary: &metricspb.Summary{
+					DataPoints: []*metricspb.SummaryDataPoint{
+						{
+							StartTimeUnixNano: uint64(startTime.UnixNano()),
+							TimeUnixNano:      uint64(now.UnixNano()),
+							Count:             1,
+							Sum:               4,
+							QuantileValues: []*metricspb.SummaryDataPoint_ValueAtQuantile{
+								{
+									Quantile: 0.5,
+									Value:    4,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	metrics := convert(families, startTime, now)
+	require.Len(metrics, len(expected))
+	for i, metric := range metrics {
+		require.True(proto.Equal(expected[i], metric), "expected %s but got %s", expected[i], metric)
+	}
+}
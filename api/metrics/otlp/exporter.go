@@ -0,0 +1,165 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package otlp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	dto "github.com/prometheus/client_model/go"
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+const (
+	exportTimeout = 10 * time.Second
+
+	serviceNameKey = "service.name"
+	versionKey     = "version"
+)
+
+var _ io.Closer = (*exporter)(nil)
+
+// New returns an exporter that periodically pushes the metrics gathered from
+// [gatherer] to an OTLP endpoint. Closing the exporter pushes the metrics one
+// last time.
+func New(log logging.Logger, gatherer prometheus.Gatherer, config Config) (io.Closer, error) {
+	if !config.Enabled {
+		return noopCloser{}, nil
+	}
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+
+	client, err := newClient(config.ExporterConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &exporter{
+		log:       log,
+		gatherer:  gatherer,
+		config:    config,
+		client:    client,
+		startTime: time.Now(),
+		resource: &resourcepb.Resource{
+			Attributes: []*commonpb.KeyValue{
+				stringKeyValue(serviceNameKey, config.AppName),
+				stringKeyValue(versionKey, config.Version),
+			},
+		},
+		closer: make(chan struct{}),
+	}
+	e.wg.Add(1)
+	go e.dispatch()
+	return e, nil
+}
+
+type exporter struct {
+	log       logging.Logger
+	gatherer  prometheus.Gatherer
+	config    Config
+	client    client
+	startTime time.Time
+	resource  *resourcepb.Resource
+
+	closeOnce sync.Once
+	closer    chan struct{}
+	wg        sync.WaitGroup
+}
+
+func (e *exporter) dispatch() {
+	ticker := time.NewTicker(e.config.Frequency)
+	defer func() {
+		ticker.Stop()
+		e.wg.Done()
+	}()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := e.export(); err != nil {
+				e.log.Warn("failed to export metrics",
+					zap.String("endpoint", e.config.Endpoint),
+					zap.Error(err),
+				)
+			}
+		case <-e.closer:
+			return
+		}
+	}
+}
+
+func (e *exporter) export() error {
+	// Gather may return the metric families that were successfully gathered
+	// along with an error, in which case those metric families are still
+	// exported.
+	families, gatherErr := e.gatherer.Gather()
+
+	exported := make([]*dto.MetricFamily, 0, len(families))
+	for _, family := range families {
+		if e.config.exports(family.GetName()) {
+			exported = append(exported, family)
+		}
+	}
+
+	request := &collectormetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{
+			{
+				Resource: e.resource,
+				ScopeMetrics: []*metricspb.ScopeMetrics{
+					{
+						Scope: &commonpb.InstrumentationScope{
+							Name:    e.config.AppName,
+							Version: e.config.Version,
+						},
+						Metrics: convert(exported, e.startTime, time.Now()),
+					},
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	return errors.Join(gatherErr, e.client.export(ctx, request))
+}
+
+func (e *exporter) Close() error {
+	var err error
+	e.closeOnce.Do(func() {
+		close(e.closer)
+		e.wg.Wait()
+
+		err = errors.Join(e.export(), e.client.close())
+	})
+	return err
+}
+
+func stringKeyValue(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key: key,
+		Value: &commonpb.AnyValue{
+			Value: &commonpb.AnyValue_StringValue{
+				StringValue: value,
+			},
+		},
+	}
+}
+
+type noopCloser struct{}
+
+func (noopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package otlp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+)
+
+const (
+	testHeaderKey   = "authorization"
+	testHeaderValue = "secret"
+)
+
+// newTestGatherer returns a gatherer with an included and an excluded
+// metric family.
+func newTestGatherer(t *testing.T) prometheus.Gatherer {
+	registry := prometheus.NewRegistry()
+	for _, name := range []string{"included", "excluded"} {
+		require.NoError(t, registry.Register(prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: name,
+			Help: name,
+		})))
+	}
+	return registry
+}
+
+func newTestConfig(exporterType trace.ExporterType, endpoint string) Config {
+	return Config{
+		ExporterConfig: trace.ExporterConfig{
+			Type:     exporterType,
+			Endpoint: endpoint,
+			Headers: map[string]string{
+				testHeaderKey: testHeaderValue,
+			},
+			Insecure: true,
+		},
+		Enabled: true,
+		// Only the final export on Close is expected to happen.
+		Frequency: time.Hour,
+		Exclude:   []string{"excl*"},
+		AppName:   "avalanchego",
+		Version:   "v1.0.0",
+	}
+}
+
+func requireExportedRequest(t *testing.T, request *collectormetricspb.ExportMetricsServiceRequest) {
+	require := require.New(t)
+
+	require.Len(request.ResourceMetrics, 1)
+	resourceMetrics := request.ResourceMetrics[0]
+	require.True(proto.Equal(stringKeyValue(serviceNameKey, "avalanchego"), resourceMetrics.Resource.Attributes[0]))
+	require.Len(resourceMetrics.ScopeMetrics, 1)
+	metrics := resourceMetrics.ScopeMetrics[0].Metrics
+	require.Len(metrics, 1)
+	require.Equal("included", metrics[0].Name)
+}
+
+func TestHTTPExporter(t *testing.T) {
+	require := require.New(t)
+
+	requests := make(chan *collectormetricspb.ExportMetricsServiceRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(httpPath, r.URL.Path)
+		require.Equal(testHeaderValue, r.Header.Get(testHeaderKey))
+		require.Equal("application/x-protobuf", r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(err)
+		request := &collectormetricspb.ExportMetricsServiceRequest{}
+		require.NoError(proto.Unmarshal(body, request))
+		requests <- request
+
+		response, err := proto.Marshal(&collectormetricspb.ExportMetricsServiceResponse{})
+		require.NoError(err)
+		_, err = w.Write(response)
+		require.NoError(err)
+	}))
+	defer server.Close()
+
+	endpoint := strings.TrimPrefix(server.URL, "http://")
+	exporter, err := New(logging.NoLog{}, newTestGatherer(t), newTestConfig(trace.HTTP, endpoint))
+	require.NoError(err)
+	require.NoError(exporter.Close())
+
+	requireExportedRequest(t, <-requests)
+}
+
+func TestHTTPExporterUnexpectedStatus(t *testing.T) {
+	require := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	endpoint := strings.TrimPrefix(server.URL, "http://")
+	exporter, err := New(logging.NoLog{}, newTestGatherer(t), newTestConfig(trace.HTTP, endpoint))
+	require.NoError(err)
+	err = exporter.Close()
+	require.ErrorIs(err, errUnexpectedStatus)
+}
+
+type testMetricsServer struct {
+	collectormetricspb.UnimplementedMetricsServiceServer
+
+	t        *testing.T
+	requests chan *collectormetricspb.ExportMetricsServiceRequest
+}
+
+func (s *testMetricsServer) Export(ctx context.Context, request *collectormetricspb.ExportMetricsServiceRequest) (*collectormetricspb.ExportMetricsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	require.Equal(s.t, []string{testHeaderValue}, md.Get(testHeaderKey))
+
+	s.requests <- request
+	return &collectormetricspb.ExportMetricsServiceResponse{
+		PartialSuccess: &collectormetricspb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: 1,
+			ErrorMessage:       "rejected",
+		},
+	}, nil
+}
+
+func TestGRPCExporter(t *testing.T) {
+	require := require.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+
+	metricsServer := &testMetricsServer{
+		t:        t,
+		requests: make(chan *collectormetricspb.ExportMetricsServiceRequest, 1),
+	}
+	server := grpc.NewServer()
+	collectormetricspb.RegisterMetricsServiceServer(server, metricsServer)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	exporter, err := New(logging.NoLog{}, newTestGatherer(t), newTestConfig(trace.GRPC, listener.Addr().String()))
+	require.NoError(err)
+	err = exporter.Close()
+	require.ErrorIs(err, errPartialSuccess)
+
+	requireExportedRequest(t, <-metricsServer.requests)
+}
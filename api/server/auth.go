@@ -0,0 +1,324 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+const (
+	bearerPrefix = "Bearer "
+
+	// maxAuthBodySize is the maximum size of a request body that is inspected
+	// for JSON-RPC methods.
+	maxAuthBodySize = 16 * 1024 * 1024
+)
+
+var (
+	_ http.Handler = (*authHandler)(nil)
+
+	errMissingIdentityName   = errors.New("identity is missing a name")
+	errDuplicateIdentityName = errors.New("duplicate identity name")
+	errInvalidTokenHash      = errors.New("invalid token hash")
+	errDuplicateToken        = errors.New("token hash is used by multiple identities")
+	errDuplicateCommonName   = errors.New("certificate common name is used by multiple identities")
+)
+
+// AuthPolicy maps the identities that are allowed to call the API server to
+// the routes and JSON-RPC methods they are allowed to call.
+type AuthPolicy struct {
+	Identities []Identity `json:"identities"`
+	// Anonymous are the permissions of requests that don't authenticate. If
+	// nil, unauthenticated requests are rejected.
+	Anonymous *Permissions `json:"anonymous"`
+}
+
+// Identity is a caller of the API server.
+type Identity struct {
+	Name string `json:"name"`
+	// TokenHashes are the hex encoded SHA-256 hashes of the bearer tokens
+	// that authenticate as this identity.
+	TokenHashes []string `json:"tokenHashes"`
+	// CertificateCommonNames are the subject common names of the TLS client
+	// certificates that authenticate as this identity. Client certificates
+	// must be signed by the configured client CA.
+	CertificateCommonNames []string `json:"certificateCommonNames"`
+	Permissions
+}
+
+// Permissions specify the calls an identity is allowed to make.
+//
+// Patterns either match exactly or, if they end with "*", match every string
+// with the preceding prefix. For example, "health.*" matches every method of
+// the health API.
+type Permissions struct {
+	// Routes are patterns of the request paths that may be called, such as
+	// "/ext/health" or "/ext/bc/*".
+	Routes []string `json:"routes"`
+	// Methods are patterns of the JSON-RPC methods that may be called, such
+	// as "admin.*". JSON-RPC requests must match both a route and a method.
+	Methods []string `json:"methods"`
+}
+
+func (p *Permissions) allowsRoute(route string) bool {
+	return matchesAny(p.Routes, route)
+}
+
+func (p *Permissions) allowsMethod(method string) bool {
+	return matchesAny(p.Methods, method)
+}
+
+func matchesAny(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if matches(pattern, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(pattern string, s string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(s, prefix)
+	}
+	return pattern == s
+}
+
+// ParseAuthPolicy parses and verifies a JSON encoded AuthPolicy.
+func ParseAuthPolicy(policyBytes []byte) (AuthPolicy, error) {
+	var policy AuthPolicy
+	if err := json.Unmarshal(policyBytes, &policy); err != nil {
+		return AuthPolicy{}, err
+	}
+	_, err := newAuthenticator(policy)
+	return policy, err
+}
+
+// authenticator maps credentials to identities.
+type authenticator struct {
+	tokenHashes map[[sha256.Size]byte]*Identity
+	commonNames map[string]*Identity
+}
+
+func newAuthenticator(policy AuthPolicy) (*authenticator, error) {
+	a := &authenticator{
+		tokenHashes: make(map[[sha256.Size]byte]*Identity),
+		commonNames: make(map[string]*Identity),
+	}
+	names := set.NewSet[string](len(policy.Identities))
+	for i := range policy.Identities {
+		identity := &policy.Identities[i]
+		switch {
+		case identity.Name == "":
+			return nil, errMissingIdentityName
+		case names.Contains(identity.Name):
+			return nil, fmt.Errorf("%w: %q", errDuplicateIdentityName, identity.Name)
+		}
+		names.Add(identity.Name)
+
+		for _, tokenHashStr := range identity.TokenHashes {
+			tokenHashBytes, err := hex.DecodeString(tokenHashStr)
+			if err != nil || len(tokenHashBytes) != sha256.Size {
+				return nil, fmt.Errorf("%w for identity %q", errInvalidTokenHash, identity.Name)
+			}
+			tokenHash := [sha256.Size]byte(tokenHashBytes)
+			if _, ok := a.tokenHashes[tokenHash]; ok {
+				return nil, fmt.Errorf("%w: %q", errDuplicateToken, identity.Name)
+			}
+			a.tokenHashes[tokenHash] = identity
+		}
+		for _, commonName := range identity.CertificateCommonNames {
+			if _, ok := a.commonNames[commonName]; ok {
+				return nil, fmt.Errorf("%w: %q", errDuplicateCommonName, commonName)
+			}
+			a.commonNames[commonName] = identity
+		}
+	}
+	return a, nil
+}
+
+// authenticate returns the identity that [r] authenticates as. Bearer tokens
+// take precedence over client certificates. Returns nil if [r] doesn't provide
+// credentials and an error if the provided credentials are unknown.
+func (a *authenticator) authenticate(r *http.Request) (*Identity, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		token, ok := strings.CutPrefix(header, bearerPrefix)
+		if !ok {
+			return nil, errors.New("unsupported authorization scheme")
+		}
+		identity, ok := a.tokenHashes[sha256.Sum256([]byte(token))]
+		if !ok {
+			return nil, errors.New("unknown bearer token")
+		}
+		return identity, nil
+	}
+
+	// VerifiedChains is only populated if the client certificate was signed
+	// by the configured client CA.
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		commonName := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		identity, ok := a.commonNames[commonName]
+		if !ok {
+			return nil, fmt.Errorf("unknown certificate common name %q", commonName)
+		}
+		return identity, nil
+	}
+	return nil, nil
+}
+
+// newAuthHandler returns a handler that only passes requests to [handler] if
+// they are allowed by [policy]. Rejected requests are logged to [auditLog].
+func newAuthHandler(handler http.Handler, policy AuthPolicy, auditLog logging.Logger) (http.Handler, error) {
+	authenticator, err := newAuthenticator(policy)
+	if err != nil {
+		return nil, err
+	}
+	return &authHandler{
+		handler:       handler,
+		authenticator: authenticator,
+		anonymous:     policy.Anonymous,
+		auditLog:      auditLog,
+	}, nil
+}
+
+type authHandler struct {
+	handler       http.Handler
+	authenticator *authenticator
+	anonymous     *Permissions
+	auditLog      logging.Logger
+}
+
+func (a *authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	identity, err := a.authenticator.authenticate(r)
+	if err != nil {
+		a.deny(w, r, http.StatusUnauthorized, "", nil, err.Error())
+		return
+	}
+
+	name := "anonymous"
+	permissions := a.anonymous
+	if identity != nil {
+		name = identity.Name
+		permissions = &identity.Permissions
+	}
+	if permissions == nil {
+		a.deny(w, r, http.StatusUnauthorized, name, nil, "missing credentials")
+		return
+	}
+
+	if !permissions.allowsRoute(r.URL.Path) {
+		a.deny(w, r, http.StatusForbidden, name, nil, "route not allowed")
+		return
+	}
+
+	methods, err := jsonRPCMethods(r)
+	if err != nil {
+		a.deny(w, r, http.StatusBadRequest, name, nil, err.Error())
+		return
+	}
+	for _, method := range methods {
+		if !permissions.allowsMethod(method) {
+			a.deny(w, r, http.StatusForbidden, name, methods, "method not allowed")
+			return
+		}
+	}
+
+	if identity != nil {
+		r = r.WithContext(context.WithValue(r.Context(), identityContextKey{}, identity.Name))
+	}
+	a.handler.ServeHTTP(w, r)
+}
+
+type identityContextKey struct{}
+
+// identityFromContext returns the name of the identity that the request with
+// [ctx] authenticated as.
+func identityFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(identityContextKey{}).(string)
+	return name, ok
+}
+
+func (a *authHandler) deny(
+	w http.ResponseWriter,
+	r *http.Request,
+	statusCode int,
+	identity string,
+	methods []string,
+	reason string,
+) {
+	a.auditLog.Warn("denied API call",
+		zap.String("identity", identity),
+		zap.String("remoteAddr", r.RemoteAddr),
+		zap.String("httpMethod", r.Method),
+		zap.String("path", r.URL.Path),
+		zap.Strings("methods", methods),
+		zap.String("reason", reason),
+	)
+	http.Error(w, http.StatusText(statusCode), statusCode)
+}
+
+type jsonRPCRequest struct {
+	Method string `json:"method"`
+}
+
+// jsonRPCMethods returns the JSON-RPC methods called by [r]. The body of [r]
+// is restored so that it can be read by the next handler.
+//
+// Requests that aren't POST requests with a JSON object or array body aren't
+// JSON-RPC requests, so no methods are returned for them.
+func jsonRPCMethods(r *http.Request) ([]string, error) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAuthBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	if len(body) > maxAuthBodySize {
+		return nil, errors.New("body too large")
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	var requests []jsonRPCRequest
+	switch trimmed[0] {
+	case '{':
+		var request jsonRPCRequest
+		if err := json.Unmarshal(trimmed, &request); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC request: %w", err)
+		}
+		requests = []jsonRPCRequest{request}
+	case '[':
+		if err := json.Unmarshal(trimmed, &requests); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC batch: %w", err)
+		}
+	default:
+		return nil, nil
+	}
+
+	methods := make([]string, len(requests))
+	for i, request := range requests {
+		methods[i] = request.Method
+	}
+	return methods, nil
+}
@@ -0,0 +1,302 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	monitoringToken = "monitoring-token"
+	opsCommonName   = "ops.example.com"
+)
+
+func tokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func testAuthPolicy() AuthPolicy {
+	return AuthPolicy{
+		Identities: []Identity{
+			{
+				Name:        "monitoring",
+				TokenHashes: []string{tokenHash(monitoringToken)},
+				Permissions: Permissions{
+					Routes:  []string{"/ext/health*", "/ext/info"},
+					Methods: []string{"health.*", "info.*"},
+				},
+			},
+			{
+				Name:                   "ops",
+				CertificateCommonNames: []string{opsCommonName},
+				Permissions: Permissions{
+					Routes:  []string{"/ext/*"},
+					Methods: []string{"*"},
+				},
+			},
+		},
+		Anonymous: &Permissions{
+			Routes: []string{"/ext/health/liveness"},
+		},
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func TestAuthHandler(t *testing.T) {
+	tests := []struct {
+		name               string
+		policy             AuthPolicy
+		path               string
+		httpMethod         string
+		body               string
+		token              string
+		commonName         string
+		expectedStatusCode int
+	}{
+		{
+			name:               "token allowed",
+			policy:             testAuthPolicy(),
+			path:               "/ext/info",
+			httpMethod:         http.MethodPost,
+			body:               `{"jsonrpc":"2.0","id":1,"method":"info.getNodeID"}`,
+			token:              monitoringToken,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "token method not allowed",
+			policy:             testAuthPolicy(),
+			path:               "/ext/info",
+			httpMethod:         http.MethodPost,
+			body:               `{"jsonrpc":"2.0","id":1,"method":"admin.stopCPUProfiler"}`,
+			token:              monitoringToken,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "token route not allowed",
+			policy:             testAuthPolicy(),
+			path:               "/ext/admin",
+			httpMethod:         http.MethodPost,
+			body:               `{"jsonrpc":"2.0","id":1,"method":"info.getNodeID"}`,
+			token:              monitoringToken,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "unknown token",
+			policy:             testAuthPolicy(),
+			path:               "/ext/info",
+			httpMethod:         http.MethodPost,
+			body:               `{"jsonrpc":"2.0","id":1,"method":"info.getNodeID"}`,
+			token:              "unknown-token",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "certificate allowed",
+			policy:             testAuthPolicy(),
+			path:               "/ext/admin",
+			httpMethod:         http.MethodPost,
+			body:               `{"jsonrpc":"2.0","id":1,"method":"admin.stopCPUProfiler"}`,
+			commonName:         opsCommonName,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "unknown certificate",
+			policy:             testAuthPolicy(),
+			path:               "/ext/admin",
+			httpMethod:         http.MethodPost,
+			body:               `{"jsonrpc":"2.0","id":1,"method":"admin.stopCPUProfiler"}`,
+			commonName:         "evil.example.com",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "token takes precedence over certificate",
+			policy:             testAuthPolicy(),
+			path:               "/ext/admin",
+			httpMethod:         http.MethodPost,
+			body:               `{"jsonrpc":"2.0","id":1,"method":"admin.stopCPUProfiler"}`,
+			token:              monitoringToken,
+			commonName:         opsCommonName,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "batch allowed",
+			policy:             testAuthPolicy(),
+			path:               "/ext/info",
+			httpMethod:         http.MethodPost,
+			body:               `[{"method":"info.getNodeID"},{"method":"info.peers"}]`,
+			token:              monitoringToken,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "batch with method not allowed",
+			policy:             testAuthPolicy(),
+			path:               "/ext/info",
+			httpMethod:         http.MethodPost,
+			body:               `[{"method":"info.getNodeID"},{"method":"admin.stopCPUProfiler"}]`,
+			token:              monitoringToken,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "invalid JSON-RPC request",
+			policy:             testAuthPolicy(),
+			path:               "/ext/info",
+			httpMethod:         http.MethodPost,
+			body:               `{"method":`,
+			token:              monitoringToken,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "anonymous allowed",
+			policy:             testAuthPolicy(),
+			path:               "/ext/health/liveness",
+			httpMethod:         http.MethodGet,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "anonymous method not allowed",
+			policy:             testAuthPolicy(),
+			path:               "/ext/health/liveness",
+			httpMethod:         http.MethodPost,
+			body:               `{"jsonrpc":"2.0","id":1,"method":"health.health"}`,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "anonymous route not allowed",
+			policy:             testAuthPolicy(),
+			path:               "/ext/admin",
+			httpMethod:         http.MethodGet,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name: "anonymous not permitted",
+			policy: AuthPolicy{
+				Identities: testAuthPolicy().Identities,
+			},
+			path:               "/ext/health/liveness",
+			httpMethod:         http.MethodGet,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			var (
+				auditLogs   bytes.Buffer
+				auditLog    = logging.NewLogger("", logging.NewWrappedCore(logging.Info, nopWriteCloser{&auditLogs}, logging.JSON.FileEncoder()))
+				baseHandler = &bodyRecordingHandler{}
+			)
+			handler, err := newAuthHandler(baseHandler, test.policy, auditLog)
+			require.NoError(err)
+
+			r := httptest.NewRequest(test.httpMethod, test.path, strings.NewReader(test.body))
+			if test.token != "" {
+				r.Header.Set("Authorization", bearerPrefix+test.token)
+			}
+			if test.commonName != "" {
+				r.TLS = &tls.ConnectionState{
+					VerifiedChains: [][]*x509.Certificate{{{
+						Subject: pkix.Name{CommonName: test.commonName},
+					}}},
+				}
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			require.Equal(test.expectedStatusCode, w.Code)
+			if test.expectedStatusCode != http.StatusOK {
+				require.False(baseHandler.called)
+				require.Contains(auditLogs.String(), "denied API call")
+				return
+			}
+
+			// The body must still be readable by the wrapped handler.
+			require.True(baseHandler.called)
+			require.Equal(test.body, baseHandler.body)
+			require.Empty(auditLogs.String())
+		})
+	}
+}
+
+type bodyRecordingHandler struct {
+	called bool
+	body   string
+}
+
+func (h *bodyRecordingHandler) ServeHTTP(_ http.ResponseWriter, r *http.Request) {
+	h.called = true
+	body, _ := io.ReadAll(r.Body)
+	h.body = string(body)
+}
+
+func TestParseAuthPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			policy: `{
+				"identities": [
+					{"name": "a", "tokenHashes": ["` + tokenHash("a") + `"], "routes": ["/ext/*"], "methods": ["*"]},
+					{"name": "b", "certificateCommonNames": ["b"], "routes": ["/ext/info"]}
+				],
+				"anonymous": {"routes": ["/ext/health"]}
+			}`,
+		},
+		{
+			name:        "missing name",
+			policy:      `{"identities": [{"routes": ["/ext/*"]}]}`,
+			expectedErr: errMissingIdentityName,
+		},
+		{
+			name:        "duplicate name",
+			policy:      `{"identities": [{"name": "a"}, {"name": "a"}]}`,
+			expectedErr: errDuplicateIdentityName,
+		},
+		{
+			name:        "invalid token hash",
+			policy:      `{"identities": [{"name": "a", "tokenHashes": ["abcd"]}]}`,
+			expectedErr: errInvalidTokenHash,
+		},
+		{
+			name:        "duplicate token hash",
+			policy:      `{"identities": [{"name": "a", "tokenHashes": ["` + tokenHash("a") + `"]}, {"name": "b", "tokenHashes": ["` + tokenHash("a") + `"]}]}`,
+			expectedErr: errDuplicateToken,
+		},
+		{
+			name:        "duplicate common name",
+			policy:      `{"identities": [{"name": "a", "certificateCommonNames": ["a"]}, {"name": "b", "certificateCommonNames": ["a"]}]}`,
+			expectedErr: errDuplicateCommonName,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseAuthPolicy([]byte(test.policy))
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+const (
+	// maxRateLimitedClients is the maximum number of clients whose budgets are
+	// tracked. If exceeded, the least recently seen client is forgotten.
+	maxRateLimitedClients = 1 << 16
+	// ipv6PrefixLen is the length of the prefix that identifies IPv6 clients.
+	// Hosts are usually assigned a whole /64, so clients can't evade their
+	// limits by using many addresses.
+	ipv6PrefixLen = 64
+
+	rateThrottleReason        = "rate"
+	concurrencyThrottleReason = "concurrency"
+)
+
+var (
+	_ http.Handler = (*rateLimitHandler)(nil)
+
+	errInvalidBurst        = errors.New("burst must be positive")
+	errInvalidCost         = errors.New("cost must be positive")
+	errCostExceedsBurst    = errors.New("cost exceeds burst")
+	errNegativeConcurrency = errors.New("max concurrent requests must be non-negative")
+)
+
+// RateLimitConfig limits the calls made by each client of the API server.
+// Clients that authenticated are identified by their identity, other clients
+// are identified by their IP.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the rate at which the budget of a client is
+	// refilled. If <= 0, calls aren't rate limited.
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	// Burst is the maximum budget of a client.
+	Burst int `json:"burst"`
+	// MaxConcurrentRequests is the maximum number of calls a client may make
+	// concurrently. If 0, concurrent calls aren't limited.
+	MaxConcurrentRequests int `json:"maxConcurrentRequests"`
+	// Costs maps patterns to the budget consumed by a call. Patterns that
+	// start with "/" match the request path, other patterns match JSON-RPC
+	// methods. The cost of a JSON-RPC call is the sum of the costs of its
+	// methods, where methods without a matching pattern cost the same as the
+	// request path. Request paths without a matching pattern cost 1.
+	//
+	// Patterns match in the same way as in AuthPolicy. If multiple patterns
+	// match, an exact match is preferred over the longest prefix match.
+	Costs map[string]int `json:"costs"`
+}
+
+// Enabled returns true if either the rate or the concurrency of calls is
+// limited.
+func (c *RateLimitConfig) Enabled() bool {
+	return c.rateLimited() || c.MaxConcurrentRequests > 0
+}
+
+func (c *RateLimitConfig) rateLimited() bool {
+	return c.RequestsPerSecond > 0
+}
+
+func (c *RateLimitConfig) Verify() error {
+	if c.MaxConcurrentRequests < 0 {
+		return errNegativeConcurrency
+	}
+	if !c.rateLimited() {
+		return nil
+	}
+	if c.Burst <= 0 {
+		return errInvalidBurst
+	}
+	for pattern, cost := range c.Costs {
+		switch {
+		case cost <= 0:
+			return fmt.Errorf("%w: %q costs %d", errInvalidCost, pattern, cost)
+		case cost > c.Burst:
+			return fmt.Errorf("%w: %q costs %d > %d", errCostExceedsBurst, pattern, cost, c.Burst)
+		}
+	}
+	return nil
+}
+
+// cost returns the budget consumed by a call to [methods] on [path].
+func (c *RateLimitConfig) cost(path string, methods []string) int {
+	routeCost, ok := c.lookupCost(path)
+	if !ok {
+		routeCost = 1
+	}
+	if len(methods) == 0 {
+		return routeCost
+	}
+
+	var cost int
+	for _, method := range methods {
+		methodCost, ok := c.lookupCost(method)
+		if !ok {
+			methodCost = routeCost
+		}
+		cost += methodCost
+	}
+	return cost
+}
+
+// lookupCost returns the cost of the pattern that exactly matches [s] or, if
+// there is none, the cost of the longest prefix pattern that matches [s].
+func (c *RateLimitConfig) lookupCost(s string) (int, bool) {
+	if cost, ok := c.Costs[s]; ok {
+		return cost, true
+	}
+
+	var (
+		cost       int
+		longestLen = -1
+	)
+	for pattern, patternCost := range c.Costs {
+		if len(pattern) > longestLen && matches(pattern, s) {
+			cost = patternCost
+			longestLen = len(pattern)
+		}
+	}
+	return cost, longestLen >= 0
+}
+
+type rateLimitMetrics struct {
+	throttled *prometheus.CounterVec
+}
+
+func newRateLimitMetrics(registerer prometheus.Registerer) (*rateLimitMetrics, error) {
+	m := &rateLimitMetrics{
+		throttled: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "calls_throttled",
+				Help: "The number of calls rejected because the client exceeded its rate or concurrency limit",
+			},
+			[]string{"reason"},
+		),
+	}
+	return m, registerer.Register(m.throttled)
+}
+
+// clientKey identifies a client. Identities and IPs are distinguished so that
+// an identity can't consume the budget of an IP with the same name.
+type clientKey struct {
+	authenticated bool
+	name          string
+}
+
+type rateLimitedClient struct {
+	// limiter is nil if calls aren't rate limited
+	limiter    *rate.Limiter
+	processing atomic.Int64
+}
+
+// newRateLimitHandler returns a handler that rejects calls with
+// 429 Too Many Requests if their client exceeded its limits.
+func newRateLimitHandler(
+	handler http.Handler,
+	config RateLimitConfig,
+	registerer prometheus.Registerer,
+) (http.Handler, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	metrics, err := newRateLimitMetrics(registerer)
+	if err != nil {
+		return nil, err
+	}
+	return &rateLimitHandler{
+		handler: handler,
+		config:  config,
+		metrics: metrics,
+		clients: &cache.LRU[clientKey, *rateLimitedClient]{Size: maxRateLimitedClients},
+	}, nil
+}
+
+type rateLimitHandler struct {
+	handler http.Handler
+	config  RateLimitConfig
+	metrics *rateLimitMetrics
+	clock   mockable.Clock
+
+	// clients is internally synchronized, so [getClient] only needs to
+	// guard against concurrent insertions of the same client.
+	clientsLock sync.Mutex
+	clients     *cache.LRU[clientKey, *rateLimitedClient]
+}
+
+func (h *rateLimitHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The limits of the client are checked before the body is read, so that
+	// clients that exceeded them can't make the server read large bodies.
+	client := h.getClient(getClientKey(r))
+	if h.config.MaxConcurrentRequests > 0 {
+		defer client.processing.Add(-1)
+		if client.processing.Add(1) > int64(h.config.MaxConcurrentRequests) {
+			h.throttle(w, concurrencyThrottleReason, time.Second)
+			return
+		}
+	}
+	if client.limiter == nil {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// Every call costs at least 1, so calls from clients without a full unit
+	// of budget are rejected without computing their cost.
+	if tokens := client.limiter.TokensAt(h.clock.Time()); tokens < 1 {
+		delay := time.Duration((1 - tokens) / h.config.RequestsPerSecond * float64(time.Second))
+		h.throttle(w, rateThrottleReason, delay)
+		return
+	}
+
+	methods, err := jsonRPCMethods(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		now         = h.clock.Time()
+		cost        = h.config.cost(r.URL.Path, methods)
+		reservation = client.limiter.ReserveN(now, cost)
+	)
+	// A JSON-RPC batch may cost more than the burst, in which case the
+	// reservation can never be satisfied and retrying won't help.
+	if !reservation.OK() {
+		h.metrics.throttled.WithLabelValues(rateThrottleReason).Inc()
+		http.Error(
+			w,
+			fmt.Sprintf("call costs %d, which exceeds the burst of %d", cost, h.config.Burst),
+			http.StatusRequestEntityTooLarge,
+		)
+		return
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		h.throttle(w, rateThrottleReason, delay)
+		return
+	}
+
+	h.handler.ServeHTTP(w, r)
+}
+
+func (h *rateLimitHandler) getClient(key clientKey) *rateLimitedClient {
+	if client, ok := h.clients.Get(key); ok {
+		return client
+	}
+
+	h.clientsLock.Lock()
+	defer h.clientsLock.Unlock()
+
+	if client, ok := h.clients.Get(key); ok {
+		return client
+	}
+	client := &rateLimitedClient{}
+	if h.config.rateLimited() {
+		client.limiter = rate.NewLimiter(rate.Limit(h.config.RequestsPerSecond), h.config.Burst)
+	}
+	h.clients.Put(key, client)
+	return client
+}
+
+// throttle rejects a call. The client is told to retry after [retryAfter],
+// rounded up to a whole number of seconds.
+func (h *rateLimitHandler) throttle(w http.ResponseWriter, reason string, retryAfter time.Duration) {
+	h.metrics.throttled.WithLabelValues(reason).Inc()
+	seconds := max(math.Ceil(retryAfter.Seconds()), 1)
+	w.Header().Set("Retry-After", strconv.FormatFloat(seconds, 'f', 0, 64))
+	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+}
+
+func getClientKey(r *http.Request) clientKey {
+	if identity, ok := identityFromContext(r.Context()); ok {
+		return clientKey{
+			authenticated: true,
+			name:          identity,
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		prefix := ip.Mask(net.CIDRMask(ipv6PrefixLen, 8*net.IPv6len))
+		host = fmt.Sprintf("%s/%d", prefix, ipv6PrefixLen)
+	}
+	return clientKey{
+		name: host,
+	}
+}
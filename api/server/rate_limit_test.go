@@ -0,0 +1,422 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      RateLimitConfig
+		expectedErr error
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name: "valid",
+			config: RateLimitConfig{
+				RequestsPerSecond: 1,
+				Burst:             10,
+				Costs: map[string]int{
+					"avm.getAddressTxs": 10,
+				},
+			},
+		},
+		{
+			name: "only concurrency limited",
+			config: RateLimitConfig{
+				MaxConcurrentRequests: 1,
+			},
+		},
+		{
+			name: "invalid burst",
+			config: RateLimitConfig{
+				RequestsPerSecond: 1,
+			},
+			expectedErr: errInvalidBurst,
+		},
+		{
+			name: "negative concurrency",
+			config: RateLimitConfig{
+				RequestsPerSecond:     1,
+				Burst:                 1,
+				MaxConcurrentRequests: -1,
+			},
+			expectedErr: errNegativeConcurrency,
+		},
+		{
+			name: "negative concurrency without rate limit",
+			config: RateLimitConfig{
+				MaxConcurrentRequests: -1,
+			},
+			expectedErr: errNegativeConcurrency,
+		},
+		{
+			name: "invalid cost",
+			config: RateLimitConfig{
+				RequestsPerSecond: 1,
+				Burst:             1,
+				Costs: map[string]int{
+					"/ext/info": 0,
+				},
+			},
+			expectedErr: errInvalidCost,
+		},
+		{
+			name: "cost exceeds burst",
+			config: RateLimitConfig{
+				RequestsPerSecond: 1,
+				Burst:             1,
+				Costs: map[string]int{
+					"/ext/info": 2,
+				},
+			},
+			expectedErr: errCostExceedsBurst,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+func TestRateLimitConfigEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   RateLimitConfig
+		expected bool
+	}{
+		{
+			name: "no limits",
+		},
+		{
+			name: "rate limited",
+			config: RateLimitConfig{
+				RequestsPerSecond: 1,
+				Burst:             1,
+			},
+			expected: true,
+		},
+		{
+			name: "concurrency limited",
+			config: RateLimitConfig{
+				MaxConcurrentRequests: 1,
+			},
+			expected: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.config.Enabled())
+		})
+	}
+}
+
+func TestRateLimitConfigCost(t *testing.T) {
+	config := RateLimitConfig{
+		Costs: map[string]int{
+			"/ext/bc/*":                     2,
+			"/ext/bc/X":                     3,
+			"avm.*":                         4,
+			"avm.getAddressTxs":             10,
+			"platform.getCurrentValidators": 8,
+		},
+	}
+	tests := []struct {
+		name         string
+		path         string
+		methods      []string
+		expectedCost int
+	}{
+		{
+			name:         "default",
+			path:         "/ext/info",
+			expectedCost: 1,
+		},
+		{
+			name:         "route prefix",
+			path:         "/ext/bc/P",
+			expectedCost: 2,
+		},
+		{
+			name:         "longest route",
+			path:         "/ext/bc/X",
+			expectedCost: 3,
+		},
+		{
+			name:         "method without cost uses route cost",
+			path:         "/ext/bc/P",
+			methods:      []string{"platform.getHeight"},
+			expectedCost: 2,
+		},
+		{
+			name:         "method",
+			path:         "/ext/bc/P",
+			methods:      []string{"platform.getCurrentValidators"},
+			expectedCost: 8,
+		},
+		{
+			name:         "longest method",
+			path:         "/ext/bc/X",
+			methods:      []string{"avm.getAddressTxs"},
+			expectedCost: 10,
+		},
+		{
+			name:         "batch",
+			path:         "/ext/bc/X",
+			methods:      []string{"avm.getAddressTxs", "avm.getTx", "xsvm.unknown"},
+			expectedCost: 10 + 4 + 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expectedCost, config.cost(test.path, test.methods))
+		})
+	}
+}
+
+func newTestRateLimitHandler(t *testing.T, handler http.Handler, config RateLimitConfig) (*rateLimitHandler, *rateLimitMetrics) {
+	h, err := newRateLimitHandler(handler, config, prometheus.NewRegistry())
+	require.NoError(t, err)
+	rateLimitHandler := h.(*rateLimitHandler)
+	rateLimitHandler.clock.Set(time.Unix(0, 0))
+	return rateLimitHandler, rateLimitHandler.metrics
+}
+
+func newRateLimitTestRequest(remoteAddr string, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/ext/bc/X", strings.NewReader(body))
+	r.RemoteAddr = remoteAddr
+	return r
+}
+
+func TestRateLimitHandlerRate(t *testing.T) {
+	require := require.New(t)
+
+	baseHandler := &bodyRecordingHandler{}
+	h, metrics := newTestRateLimitHandler(t, baseHandler, RateLimitConfig{
+		RequestsPerSecond: 1,
+		Burst:             10,
+		Costs: map[string]int{
+			"avm.getAddressTxs": 10,
+		},
+	})
+
+	const (
+		body       = `{"jsonrpc":"2.0","id":1,"method":"avm.getAddressTxs"}`
+		remoteAddr = "192.0.2.1:1234"
+	)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest(remoteAddr, body))
+	require.Equal(http.StatusOK, w.Code)
+	require.Equal(body, baseHandler.body)
+
+	// The budget is exhausted. The call is rejected before its cost is known,
+	// so the client is told to retry once the minimum cost is refilled.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest(remoteAddr, body))
+	require.Equal(http.StatusTooManyRequests, w.Code)
+	require.Equal("1", w.Header().Get("Retry-After"))
+	require.Equal(1.0, testutil.ToFloat64(metrics.throttled.WithLabelValues(rateThrottleReason)))
+
+	// Once the minimum cost is refilled, the call is rejected until its full
+	// cost is refilled.
+	h.clock.Set(h.clock.Time().Add(time.Second))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest(remoteAddr, body))
+	require.Equal(http.StatusTooManyRequests, w.Code)
+	require.Equal("9", w.Header().Get("Retry-After"))
+
+	// Other clients have their own budget.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest("192.0.2.2:1234", body))
+	require.Equal(http.StatusOK, w.Code)
+
+	// Rejected calls don't consume the budget.
+	h.clock.Set(h.clock.Time().Add(9 * time.Second))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest(remoteAddr, body))
+	require.Equal(http.StatusOK, w.Code)
+
+	// Cheaper calls can be made as soon as enough budget is refilled.
+	h.clock.Set(h.clock.Time().Add(time.Second))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest(remoteAddr, `{"method":"avm.getTx"}`))
+	require.Equal(http.StatusOK, w.Code)
+
+	// Batches that cost more than the burst are never allowed.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest("192.0.2.3:1234", `[{"method":"avm.getAddressTxs"},{"method":"avm.getTx"}]`))
+	require.Equal(http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestRateLimitHandlerIPv6Prefix(t *testing.T) {
+	require := require.New(t)
+
+	h, _ := newTestRateLimitHandler(t, &bodyRecordingHandler{}, RateLimitConfig{
+		RequestsPerSecond: 1,
+		Burst:             1,
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest("[2001:db8:0:1::1]:1234", ""))
+	require.Equal(http.StatusOK, w.Code)
+
+	// Addresses in the same /64 share a budget.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest("[2001:db8:0:1::2]:1234", ""))
+	require.Equal(http.StatusTooManyRequests, w.Code)
+	require.Equal("1", w.Header().Get("Retry-After"))
+
+	// Other /64s have their own budget.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest("[2001:db8:0:2::1]:1234", ""))
+	require.Equal(http.StatusOK, w.Code)
+}
+
+// unreadableBody records whether it was read.
+type unreadableBody struct {
+	read bool
+}
+
+func (b *unreadableBody) Read([]byte) (int, error) {
+	b.read = true
+	return 0, io.EOF
+}
+
+func TestRateLimitHandlerThrottledBodyNotRead(t *testing.T) {
+	require := require.New(t)
+
+	h, _ := newTestRateLimitHandler(t, &bodyRecordingHandler{}, RateLimitConfig{
+		RequestsPerSecond: 1,
+		Burst:             1,
+	})
+
+	const remoteAddr = "192.0.2.1:1234"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest(remoteAddr, `{"method":"avm.getTx"}`))
+	require.Equal(http.StatusOK, w.Code)
+
+	// The budget is exhausted, so the call is rejected before its cost is
+	// computed from its body.
+	body := &unreadableBody{}
+	r := httptest.NewRequest(http.MethodPost, "/ext/bc/X", body)
+	r.RemoteAddr = remoteAddr
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(http.StatusTooManyRequests, w.Code)
+	require.Equal("1", w.Header().Get("Retry-After"))
+	require.False(body.read)
+}
+
+func TestRateLimitHandlerIdentity(t *testing.T) {
+	require := require.New(t)
+
+	h, _ := newTestRateLimitHandler(t, &bodyRecordingHandler{}, RateLimitConfig{
+		RequestsPerSecond: 1,
+		Burst:             1,
+	})
+
+	const remoteAddr = "192.0.2.1:1234"
+	withIdentity := func(r *http.Request, identity string) *http.Request {
+		return r.WithContext(context.WithValue(r.Context(), identityContextKey{}, identity))
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, withIdentity(newRateLimitTestRequest(remoteAddr, ""), "a"))
+	require.Equal(http.StatusOK, w.Code)
+
+	// Identities are limited independently of their IP.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, withIdentity(newRateLimitTestRequest(remoteAddr, ""), "b"))
+	require.Equal(http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRateLimitTestRequest(remoteAddr, ""))
+	require.Equal(http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, withIdentity(newRateLimitTestRequest("192.0.2.2:1234", ""), "a"))
+	require.Equal(http.StatusTooManyRequests, w.Code)
+}
+
+type blockingHandler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *blockingHandler) ServeHTTP(http.ResponseWriter, *http.Request) {
+	h.started <- struct{}{}
+	<-h.release
+}
+
+func TestRateLimitHandlerConcurrency(t *testing.T) {
+	tests := []struct {
+		name   string
+		config RateLimitConfig
+	}{
+		{
+			name: "rate limited",
+			config: RateLimitConfig{
+				RequestsPerSecond:     1,
+				Burst:                 10,
+				MaxConcurrentRequests: 1,
+			},
+		},
+		{
+			name: "not rate limited",
+			config: RateLimitConfig{
+				MaxConcurrentRequests: 1,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			baseHandler := &blockingHandler{
+				started: make(chan struct{}),
+				release: make(chan struct{}),
+			}
+			h, metrics := newTestRateLimitHandler(t, baseHandler, test.config)
+
+			const remoteAddr = "192.0.2.1:1234"
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				h.ServeHTTP(httptest.NewRecorder(), newRateLimitTestRequest(remoteAddr, ""))
+			}()
+			<-baseHandler.started
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, newRateLimitTestRequest(remoteAddr, ""))
+			require.Equal(http.StatusTooManyRequests, w.Code)
+			require.Equal("1", w.Header().Get("Retry-After"))
+			require.Equal(1.0, testutil.ToFloat64(metrics.throttled.WithLabelValues(concurrencyThrottleReason)))
+
+			close(baseHandler.release)
+			<-done
+
+			go func() {
+				<-baseHandler.started
+			}()
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, newRateLimitTestRequest(remoteAddr, ""))
+			require.Equal(http.StatusOK, w.Code)
+		})
+	}
+}
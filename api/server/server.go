@@ -30,6 +30,7 @@ import (
 const (
 	baseURL              = "/ext"
 	maxConcurrentStreams = 64
+	auditLogName         = "http-audit"
 )
 
 var (
@@ -74,6 +75,8 @@ type HTTPConfig struct {
 	ReadHeaderTimeout time.Duration `json:"readHeaderTimeout"`
 	WriteTimeout      time.Duration `json:"writeHeaderTimeout"`
 	IdleTimeout       time.Duration `json:"idleTimeout"`
+
+	RateLimit RateLimitConfig `json:"rateLimit"`
 }
 
 type server struct {
@@ -111,6 +114,7 @@ func New(
 	registerer prometheus.Registerer,
 	httpConfig HTTPConfig,
 	allowedHosts []string,
+	authPolicy *AuthPolicy,
 ) (Server, error) {
 	m, err := newMetrics(registerer)
 	if err != nil {
@@ -118,7 +122,26 @@ func New(
 	}
 
 	router := newRouter()
-	allowedHostsHandler := filterInvalidHosts(router, allowedHosts)
+	var routerHandler http.Handler = router
+	// Rate limiting is applied after authentication so that authenticated
+	// clients are limited by their identity rather than their IP.
+	if httpConfig.RateLimit.Enabled() {
+		routerHandler, err = newRateLimitHandler(routerHandler, httpConfig.RateLimit, registerer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit config: %w", err)
+		}
+	}
+	if authPolicy != nil {
+		auditLog, err := factory.Make(auditLogName)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create audit log: %w", err)
+		}
+		routerHandler, err = newAuthHandler(routerHandler, *authPolicy, auditLog)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth policy: %w", err)
+		}
+	}
+	allowedHostsHandler := filterInvalidHosts(routerHandler, allowedHosts)
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins,
 		AllowCredentials: true,
@@ -148,6 +171,8 @@ func New(
 
 	log.Info("API created",
 		zap.Strings("allowedOrigins", allowedOrigins),
+		zap.Bool("authEnabled", authPolicy != nil),
+		zap.Bool("rateLimitEnabled", httpConfig.RateLimit.Enabled()),
 	)
 
 	return &server{
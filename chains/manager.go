@@ -27,6 +27,7 @@ import (
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/p2p"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/bootstrap/queue"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/state"
@@ -207,10 +208,11 @@ type ManagerConfig struct {
 	Keystore                  keystore.Keystore
 	AtomicMemory              *atomic.Memory
 	AVAXAssetID               ids.ID
-	XChainID                  ids.ID          // ID of the X-Chain,
-	CChainID                  ids.ID          // ID of the C-Chain,
-	CriticalChains            set.Set[ids.ID] // Chains that can't exit gracefully
-	TimeoutManager            timeout.Manager // Manages request timeouts when sending messages to other validators
+	XChainID                  ids.ID            // ID of the X-Chain,
+	CChainID                  ids.ID            // ID of the C-Chain,
+	CriticalChains            set.Set[ids.ID]   // Chains that can't exit gracefully
+	TimeoutManager            timeout.Manager   // Manages request timeouts when sending messages to other validators
+	Reputation                reputation.Scorer // Reports the reputation of peers
 	Health                    health.Registerer
 	SubnetConfigs             map[ids.ID]subnets.Config // ID -> SubnetConfig
 	ChainConfigs              map[string]ChainConfig    // alias -> ChainConfig
@@ -872,6 +874,7 @@ func (m *manager) createAvalancheChain(
 		p2pReg,
 		set.Of(ctx.NodeID),
 		nil,
+		m.Reputation,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating peer tracker: %w", err)
@@ -1268,6 +1271,7 @@ func (m *manager) createSnowmanChain(
 		p2pReg,
 		set.Of(ctx.NodeID),
 		nil,
+		m.Reputation,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating peer tracker: %w", err)
@@ -11,19 +11,24 @@ import (
 	"fmt"
 	"io/fs"
 	"math"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 
+	"github.com/ava-labs/avalanchego/api/metrics/otlp"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/node"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
@@ -37,6 +42,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/dynamicip"
 	"github.com/ava-labs/avalanchego/utils/ips"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/perms"
@@ -170,12 +176,57 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 		}
 	}
 
+	var httpsClientCA []byte
+	if v.IsSet(HTTPSClientCAFileKey) {
+		if !v.GetBool(HTTPSEnabledKey) {
+			return node.HTTPConfig{}, fmt.Errorf("%s requires %s", HTTPSClientCAFileKey, HTTPSEnabledKey)
+		}
+		httpsClientCAFilepath := GetExpandedArg(v, HTTPSClientCAFileKey)
+		httpsClientCA, err = os.ReadFile(filepath.Clean(httpsClientCAFilepath))
+		if err != nil {
+			return node.HTTPConfig{}, err
+		}
+	}
+
+	var authPolicy *server.AuthPolicy
+	if v.IsSet(HTTPAuthPolicyFileKey) {
+		authPolicyFilepath := GetExpandedArg(v, HTTPAuthPolicyFileKey)
+		authPolicyBytes, err := os.ReadFile(filepath.Clean(authPolicyFilepath))
+		if err != nil {
+			return node.HTTPConfig{}, err
+		}
+		policy, err := server.ParseAuthPolicy(authPolicyBytes)
+		if err != nil {
+			return node.HTTPConfig{}, fmt.Errorf("invalid %s: %w", HTTPAuthPolicyFileKey, err)
+		}
+		authPolicy = &policy
+	}
+
+	rateLimitCosts := make(map[string]int)
+	for pattern, costStr := range v.GetStringMapString(HTTPRateLimitCostsKey) {
+		cost, err := strconv.Atoi(costStr)
+		if err != nil {
+			return node.HTTPConfig{}, fmt.Errorf("invalid %s cost of %q: %w", HTTPRateLimitCostsKey, pattern, err)
+		}
+		rateLimitCosts[pattern] = cost
+	}
+	rateLimitConfig := server.RateLimitConfig{
+		RequestsPerSecond:     v.GetFloat64(HTTPRateLimitRequestsPerSecondKey),
+		Burst:                 v.GetInt(HTTPRateLimitBurstKey),
+		MaxConcurrentRequests: v.GetInt(HTTPRateLimitMaxConcurrentRequestsKey),
+		Costs:                 rateLimitCosts,
+	}
+	if err := rateLimitConfig.Verify(); err != nil {
+		return node.HTTPConfig{}, fmt.Errorf("invalid HTTP rate limit config: %w", err)
+	}
+
 	return node.HTTPConfig{
 		HTTPConfig: server.HTTPConfig{
 			ReadTimeout:       v.GetDuration(HTTPReadTimeoutKey),
 			ReadHeaderTimeout: v.GetDuration(HTTPReadHeaderTimeoutKey),
 			WriteTimeout:      v.GetDuration(HTTPWriteTimeoutKey),
 			IdleTimeout:       v.GetDuration(HTTPIdleTimeoutKey),
+			RateLimit:         rateLimitConfig,
 		},
 		APIConfig: node.APIConfig{
 			APIIndexerConfig: node.APIIndexerConfig{
@@ -193,6 +244,8 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 		HTTPSEnabled:       v.GetBool(HTTPSEnabledKey),
 		HTTPSKey:           httpsKey,
 		HTTPSCert:          httpsCert,
+		HTTPSClientCA:      httpsClientCA,
+		HTTPAuthPolicy:     authPolicy,
 		HTTPAllowedOrigins: v.GetStringSlice(HTTPAllowedOrigins),
 		HTTPAllowedHosts:   v.GetStringSlice(HTTPAllowedHostsKey),
 		ShutdownTimeout:    v.GetDuration(HTTPShutdownTimeoutKey),
@@ -219,6 +272,33 @@ func getRouterHealthConfig(v *viper.Viper, halflife time.Duration) (router.Healt
 	return config, nil
 }
 
+func getHealthNotificationConfig(v *viper.Viper) (node.HealthNotificationConfig, error) {
+	config := node.HealthNotificationConfig{
+		WebhookURLs:        v.GetStringSlice(HealthWebhookURLsKey),
+		WebhookMaxAttempts: v.GetInt(HealthWebhookMaxAttemptsKey),
+		Timeout:            v.GetDuration(HealthHookTimeoutKey),
+	}
+	if v.GetString(HealthExecHookKey) != "" {
+		config.ExecHook = GetExpandedArg(v, HealthExecHookKey)
+	}
+	switch {
+	case config.WebhookMaxAttempts < 1:
+		return node.HealthNotificationConfig{}, fmt.Errorf("%q must be positive", HealthWebhookMaxAttemptsKey)
+	case config.Timeout <= 0:
+		return node.HealthNotificationConfig{}, fmt.Errorf("%q must be positive", HealthHookTimeoutKey)
+	}
+	for _, webhookURL := range config.WebhookURLs {
+		u, err := url.Parse(webhookURL)
+		if err != nil {
+			return node.HealthNotificationConfig{}, fmt.Errorf("invalid %q: %w", HealthWebhookURLsKey, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return node.HealthNotificationConfig{}, fmt.Errorf("%q must be http or https URLs but got %q", HealthWebhookURLsKey, webhookURL)
+		}
+	}
+	return config, nil
+}
+
 func getAdaptiveTimeoutConfig(v *viper.Viper) (timer.AdaptiveTimeoutConfig, error) {
 	config := timer.AdaptiveTimeoutConfig{
 		InitialTimeout:     v.GetDuration(NetworkInitialTimeoutKey),
@@ -261,6 +341,19 @@ func getNetworkConfig(
 		return network.Config{}, err
 	}
 
+	compressionDictionaries, err := getCompressionDictionaries(v)
+	if err != nil {
+		return network.Config{}, err
+	}
+	if compressionType == compression.TypeZstdDictionary && len(compressionDictionaries) == 0 {
+		return network.Config{}, fmt.Errorf("%q requires at least one dictionary in %q", compression.TypeZstdDictionary, NetworkCompressionDictionaryDirKey)
+	}
+
+	var compressionSamplesFile string
+	if v.IsSet(NetworkCompressionSamplesFileKey) {
+		compressionSamplesFile = GetExpandedArg(v, NetworkCompressionSamplesFileKey)
+	}
+
 	allowPrivateIPs := !constants.ProductionNetworkIDs.Contains(networkID)
 	if v.IsSet(NetworkAllowPrivateIPsKey) {
 		allowPrivateIPs = v.GetBool(NetworkAllowPrivateIPsKey)
@@ -377,9 +470,40 @@ func getNetworkConfig(
 		SupportedACPs: supportedACPs,
 		ObjectedACPs:  objectedACPs,
 
+		CompressionDictionaries:   compressionDictionaries,
+		CompressionSamplesFile:    compressionSamplesFile,
+		CompressionSamplesMaxSize: int64(v.GetUint64(NetworkCompressionSamplesMaxSizeKey)),
+
 		RequireValidatorToConnect: v.GetBool(NetworkRequireValidatorToConnectKey),
 		PeerReadBufferSize:        int(v.GetUint(NetworkPeerReadBufferSizeKey)),
 		PeerWriteBufferSize:       int(v.GetUint(NetworkPeerWriteBufferSizeKey)),
+
+		MessageQueueConfig: peer.MessageQueueConfig{
+			Consensus: peer.PriorityConfig{
+				Weight:   v.GetUint64(NetworkOutboundQueueConsensusWeightKey),
+				MaxBytes: v.GetUint64(NetworkOutboundQueueConsensusMaxBytesKey),
+			},
+			Bootstrap: peer.PriorityConfig{
+				Weight:   v.GetUint64(NetworkOutboundQueueBootstrapWeightKey),
+				MaxBytes: v.GetUint64(NetworkOutboundQueueBootstrapMaxBytesKey),
+			},
+			AppRequest: peer.PriorityConfig{
+				Weight:   v.GetUint64(NetworkOutboundQueueAppRequestWeightKey),
+				MaxBytes: v.GetUint64(NetworkOutboundQueueAppRequestMaxBytesKey),
+			},
+			AppGossip: peer.PriorityConfig{
+				Weight:   v.GetUint64(NetworkOutboundQueueAppGossipWeightKey),
+				MaxBytes: v.GetUint64(NetworkOutboundQueueAppGossipMaxBytesKey),
+			},
+			PeerManagement: peer.PriorityConfig{
+				Weight:   v.GetUint64(NetworkOutboundQueuePeerManagementWeightKey),
+				MaxBytes: v.GetUint64(NetworkOutboundQueuePeerManagementMaxBytesKey),
+			},
+		},
+	}
+
+	if err := config.MessageQueueConfig.Verify(); err != nil {
+		return network.Config{}, err
 	}
 
 	switch {
@@ -421,6 +545,41 @@ func getNetworkConfig(
 	return config, nil
 }
 
+// getCompressionDictionaries reads every file in the compression dictionary
+// directory as a zstd dictionary.
+func getCompressionDictionaries(v *viper.Viper) ([]*compression.Dictionary, error) {
+	if !v.IsSet(NetworkCompressionDictionaryDirKey) {
+		return nil, nil
+	}
+	dictionaryDir, err := getPathFromDirKey(v, NetworkCompressionDictionaryDirKey)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dictionaryDir)
+	if err != nil {
+		return nil, err
+	}
+
+	dictionaries := make([]*compression.Dictionary, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		dictionaryPath := filepath.Join(dictionaryDir, entry.Name())
+		dictionaryBytes, err := os.ReadFile(dictionaryPath)
+		if err != nil {
+			return nil, err
+		}
+		dictionary, err := compression.ParseDictionary(dictionaryBytes)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse compression dictionary %q: %w", dictionaryPath, err)
+		}
+		dictionaries = append(dictionaries, dictionary)
+	}
+	return dictionaries, nil
+}
+
 func getBenchlistConfig(v *viper.Viper, consensusParameters snowball.Parameters) (benchlist.Config, error) {
 	// AlphaConfidence is used here to ensure that benching can't cause a
 	// liveness failure. If AlphaPreference were used, the benchlist may grow to
@@ -442,6 +601,20 @@ func getBenchlistConfig(v *viper.Viper, consensusParameters snowball.Parameters)
 	return config, nil
 }
 
+func getReputationConfig(v *viper.Viper) (reputation.Config, error) {
+	config := reputation.Config{
+		Halflife:                 v.GetDuration(ReputationHalflifeKey),
+		MaxLatency:               v.GetDuration(ReputationMaxLatencyKey),
+		InvalidMessagePenalty:    v.GetFloat64(ReputationInvalidMessagePenaltyKey),
+		ProtocolViolationPenalty: v.GetFloat64(ReputationProtocolViolationPenaltyKey),
+		MinScore:                 v.GetFloat64(ReputationMinScoreKey),
+	}
+	if err := config.Verify(); err != nil {
+		return reputation.Config{}, fmt.Errorf("invalid reputation config: %w", err)
+	}
+	return config, nil
+}
+
 func getStateSyncConfig(v *viper.Viper) (node.StateSyncConfig, error) {
 	var (
 		config       = node.StateSyncConfig{}
@@ -546,11 +719,13 @@ func getBootstrapConfig(v *viper.Viper, networkID uint32) (node.BootstrapConfig,
 
 func getIPConfig(v *viper.Viper) (node.IPConfig, error) {
 	ipConfig := node.IPConfig{
-		PublicIP:                  v.GetString(PublicIPKey),
-		PublicIPResolutionService: v.GetString(PublicIPResolutionServiceKey),
-		PublicIPResolutionFreq:    v.GetDuration(PublicIPResolutionFreqKey),
-		ListenHost:                v.GetString(StakingHostKey),
-		ListenPort:                uint16(v.GetUint(StakingPortKey)),
+		PublicIP:                      v.GetString(PublicIPKey),
+		PublicIPResolutionService:     v.GetString(PublicIPResolutionServiceKey),
+		PublicIPResolutionFreq:        v.GetDuration(PublicIPResolutionFreqKey),
+		PublicIPResolutionSTUNServers: v.GetStringSlice(PublicIPResolutionSTUNServersKey),
+		PublicIPResolutionSTUNQuorum:  v.GetInt(PublicIPResolutionSTUNQuorumKey),
+		ListenHost:                    v.GetString(StakingHostKey),
+		ListenPort:                    uint16(v.GetUint(StakingPortKey)),
 	}
 	if ipConfig.PublicIPResolutionFreq <= 0 {
 		return node.IPConfig{}, fmt.Errorf("%q must be > 0", PublicIPResolutionFreqKey)
@@ -558,6 +733,11 @@ func getIPConfig(v *viper.Viper) (node.IPConfig, error) {
 	if ipConfig.PublicIP != "" && ipConfig.PublicIPResolutionService != "" {
 		return node.IPConfig{}, fmt.Errorf("only one of --%s and --%s can be given", PublicIPKey, PublicIPResolutionServiceKey)
 	}
+	if strings.EqualFold(ipConfig.PublicIPResolutionService, dynamicip.STUNName) {
+		if _, err := dynamicip.NewSTUNResolver(ipConfig.PublicIPResolutionSTUNServers, ipConfig.PublicIPResolutionSTUNQuorum); err != nil {
+			return node.IPConfig{}, fmt.Errorf("invalid --%s or --%s: %w", PublicIPResolutionSTUNServersKey, PublicIPResolutionSTUNQuorumKey, err)
+		}
+	}
 	return ipConfig, nil
 }
 
@@ -567,10 +747,22 @@ func getProfilerConfig(v *viper.Viper) (profiler.Config, error) {
 		Enabled:     v.GetBool(ProfileContinuousEnabledKey),
 		Freq:        v.GetDuration(ProfileContinuousFreqKey),
 		MaxNumFiles: v.GetInt(ProfileContinuousMaxFilesKey),
+		Trigger: profiler.TriggerConfig{
+			Enabled:            v.GetBool(ProfileTriggeredEnabledKey),
+			CheckFreq:          v.GetDuration(ProfileTriggeredCheckFreqKey),
+			CPUThreshold:       v.GetFloat64(ProfileTriggeredCPUThresholdKey),
+			GoroutineThreshold: v.GetInt(ProfileTriggeredGoroutineThresholdKey),
+			Duration:           v.GetDuration(ProfileTriggeredDurationKey),
+			Cooldown:           v.GetDuration(ProfileTriggeredCooldownKey),
+			MaxSize:            v.GetUint64(ProfileTriggeredMaxSizeKey),
+		},
 	}
 	if config.Freq < 0 {
 		return profiler.Config{}, fmt.Errorf("%s must be >= 0", ProfileContinuousFreqKey)
 	}
+	if err := config.Trigger.Verify(); err != nil {
+		return profiler.Config{}, fmt.Errorf("invalid triggered profiler config: %w", err)
+	}
 	return config, nil
 }
 
@@ -792,6 +984,7 @@ func getTxFeeConfig(v *viper.Viper, networkID uint32) genesis.TxFeeConfig {
 				MinGasPrice:              feecomponent.GasPrice(v.GetUint64(DynamicFeesMinGasPriceKey)),
 				ExcessConversionConstant: feecomponent.Gas(v.GetUint64(DynamicFeesExcessConversionConstantKey)),
 			},
+			ValidatorFeeRate: v.GetUint64(ValidatorFeeRateKey),
 		}
 	}
 	return genesis.GetTxFeeConfig(networkID)
@@ -1196,6 +1389,26 @@ func getDiskTargeterConfig(v *viper.Viper) (tracker.TargeterConfig, error) {
 	}
 }
 
+func getExporterConfig(v *viper.Viper) (trace.ExporterConfig, error) {
+	exporterTypeStr := v.GetString(TracingExporterTypeKey)
+	exporterType, err := trace.ExporterTypeFromString(exporterTypeStr)
+	if err != nil {
+		return trace.ExporterConfig{}, err
+	}
+
+	endpoint := v.GetString(TracingEndpointKey)
+	if endpoint == "" {
+		return trace.ExporterConfig{}, errTracingEndpointEmpty
+	}
+
+	return trace.ExporterConfig{
+		Type:     exporterType,
+		Endpoint: endpoint,
+		Insecure: v.GetBool(TracingInsecureKey),
+		Headers:  v.GetStringMapString(TracingHeadersKey),
+	}, nil
+}
+
 func getTraceConfig(v *viper.Viper) (trace.Config, error) {
 	enabled := v.GetBool(TracingEnabledKey)
 	if !enabled {
@@ -1204,24 +1417,13 @@ func getTraceConfig(v *viper.Viper) (trace.Config, error) {
 		}, nil
 	}
 
-	exporterTypeStr := v.GetString(TracingExporterTypeKey)
-	exporterType, err := trace.ExporterTypeFromString(exporterTypeStr)
+	exporterConfig, err := getExporterConfig(v)
 	if err != nil {
 		return trace.Config{}, err
 	}
 
-	endpoint := v.GetString(TracingEndpointKey)
-	if endpoint == "" {
-		return trace.Config{}, errTracingEndpointEmpty
-	}
-
 	return trace.Config{
-		ExporterConfig: trace.ExporterConfig{
-			Type:     exporterType,
-			Endpoint: endpoint,
-			Insecure: v.GetBool(TracingInsecureKey),
-			Headers:  v.GetStringMapString(TracingHeadersKey),
-		},
+		ExporterConfig:  exporterConfig,
 		Enabled:         true,
 		TraceSampleRate: v.GetFloat64(TracingSampleRateKey),
 		AppName:         constants.AppName,
@@ -1229,6 +1431,31 @@ func getTraceConfig(v *viper.Viper) (trace.Config, error) {
 	}, nil
 }
 
+func getMetricsExportConfig(v *viper.Viper) (otlp.Config, error) {
+	enabled := v.GetBool(MetricsOTLPEnabledKey)
+	if !enabled {
+		return otlp.Config{
+			Enabled: false,
+		}, nil
+	}
+
+	exporterConfig, err := getExporterConfig(v)
+	if err != nil {
+		return otlp.Config{}, err
+	}
+
+	config := otlp.Config{
+		ExporterConfig: exporterConfig,
+		Enabled:        true,
+		Frequency:      v.GetDuration(MetricsOTLPFrequencyKey),
+		Include:        v.GetStringSlice(MetricsOTLPIncludeKey),
+		Exclude:        v.GetStringSlice(MetricsOTLPExcludeKey),
+		AppName:        constants.AppName,
+		Version:        version.Current.String(),
+	}
+	return config, config.Verify()
+}
+
 // Returns the path to the directory that contains VM binaries.
 func getPluginDir(v *viper.Viper) (string, error) {
 	pluginDir := GetExpandedString(v, v.GetString(PluginDirKey))
@@ -1329,6 +1556,10 @@ func GetNodeConfig(v *viper.Viper) (node.Config, error) {
 	if nodeConfig.HealthCheckFreq < 0 {
 		return node.Config{}, fmt.Errorf("%s must be positive", HealthCheckFreqKey)
 	}
+	nodeConfig.HealthNotificationConfig, err = getHealthNotificationConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
 	// Halflife of continuous averager used in health checks
 	healthCheckAveragerHalflife := v.GetDuration(HealthCheckAveragerHalflifeKey)
 	if healthCheckAveragerHalflife <= 0 {
@@ -1387,6 +1618,12 @@ func GetNodeConfig(v *viper.Viper) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Peer Reputation
+	nodeConfig.ReputationConfig, err = getReputationConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// File Descriptor Limit
 	nodeConfig.FdLimit = v.GetUint64(FdLimitKey)
 
@@ -1460,6 +1697,11 @@ func GetNodeConfig(v *viper.Viper) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	nodeConfig.MetricsExportConfig, err = getMetricsExportConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	nodeConfig.ChainDataDir = GetExpandedArg(v, ChainDataDirKey)
 
 	nodeConfig.ProcessContextFilePath = GetExpandedArg(v, ProcessContextFileKey)
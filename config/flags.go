@@ -125,6 +125,7 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Uint64(AddPrimaryNetworkDelegatorFeeKey, genesis.LocalParams.StaticFeeConfig.AddPrimaryNetworkDelegatorFee, "Transaction fee, in nAVAX, for transactions that add new primary network delegators")
 	fs.Uint64(AddSubnetValidatorFeeKey, genesis.LocalParams.StaticFeeConfig.AddSubnetValidatorFee, "Transaction fee, in nAVAX, for transactions that add new subnet validators")
 	fs.Uint64(AddSubnetDelegatorFeeKey, genesis.LocalParams.StaticFeeConfig.AddSubnetDelegatorFee, "Transaction fee, in nAVAX, for transactions that add new subnet delegators")
+	fs.Uint64(ValidatorFeeRateKey, genesis.LocalParams.ValidatorFeeRate, "Fee, in nAVAX per second, paid by each active subnet-only validator")
 
 	// Database
 	fs.String(DBTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Must be one of {%s, %s, %s}", leveldb.Name, memdb.Name, pebbledb.Name))
@@ -152,7 +153,9 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	// Public IP Resolution
 	fs.String(PublicIPKey, "", "Public IP of this node for P2P communication")
 	fs.Duration(PublicIPResolutionFreqKey, 5*time.Minute, "Frequency at which this node resolves/updates its public IP and renew NAT mappings, if applicable")
-	fs.String(PublicIPResolutionServiceKey, "", fmt.Sprintf("Only acceptable values are %q, %q or %q. When provided, the node will use that service to periodically resolve/update its public IP", dynamicip.OpenDNSName, dynamicip.IFConfigCoName, dynamicip.IFConfigMeName))
+	fs.String(PublicIPResolutionServiceKey, "", fmt.Sprintf("Only acceptable values are %q, %q, %q or %q. When provided, the node will use that service to periodically resolve/update its public IP", dynamicip.OpenDNSName, dynamicip.IFConfigCoName, dynamicip.IFConfigMeName, dynamicip.STUNName))
+	fs.StringSlice(PublicIPResolutionSTUNServersKey, dynamicip.DefaultSTUNServers, fmt.Sprintf("STUN servers, as host:port, queried to resolve the public IP if %s is %q", PublicIPResolutionServiceKey, dynamicip.STUNName))
+	fs.Int(PublicIPResolutionSTUNQuorumKey, dynamicip.DefaultSTUNQuorum, fmt.Sprintf("Number of STUN servers that must report the same public IP if %s is %q", PublicIPResolutionServiceKey, dynamicip.STUNName))
 
 	// Inbound Connection Throttling
 	fs.Duration(NetworkInboundConnUpgradeThrottlerCooldownKey, constants.DefaultInboundConnUpgradeThrottlerCooldown, "Upgrade an inbound connection from a given IP at most once per this duration. If 0, don't rate-limit inbound connection upgrades")
@@ -171,7 +174,10 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Duration(NetworkPingTimeoutKey, constants.DefaultPingPongTimeout, "Timeout value for Ping-Pong with a peer")
 	fs.Duration(NetworkPingFrequencyKey, constants.DefaultPingFrequency, "Frequency of pinging other peers")
 
-	fs.String(NetworkCompressionTypeKey, constants.DefaultNetworkCompressionType.String(), fmt.Sprintf("Compression type for outbound messages. Must be one of [%s, %s]", compression.TypeZstd, compression.TypeNone))
+	fs.String(NetworkCompressionTypeKey, constants.DefaultNetworkCompressionType.String(), fmt.Sprintf("Compression type for outbound messages. Must be one of [%s, %s, %s]", compression.TypeZstd, compression.TypeZstdDictionary, compression.TypeNone))
+	fs.String(NetworkCompressionDictionaryDirKey, "", "Directory containing trained zstd dictionaries. Every file in the directory is loaded as a dictionary. Required if the compression type is "+compression.TypeZstdDictionary.String())
+	fs.String(NetworkCompressionSamplesFileKey, "", "If non-empty, uncompressed outbound messages are appended to this file to be used to train zstd dictionaries")
+	fs.Uint64(NetworkCompressionSamplesMaxSizeKey, constants.DefaultNetworkCompressionSamplesMaxSize, "Maximum number of bytes to record to the compression samples file")
 
 	fs.Duration(NetworkMaxClockDifferenceKey, constants.DefaultNetworkMaxClockDifference, "Max allowed clock difference value between this node and peers")
 	// Note: The default value is set to false here because the default
@@ -181,6 +187,16 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Bool(NetworkRequireValidatorToConnectKey, constants.DefaultNetworkRequireValidatorToConnect, "If true, this node will only maintain a connection with another node if this node is a validator, the other node is a validator, or the other node is a beacon")
 	fs.Uint(NetworkPeerReadBufferSizeKey, constants.DefaultNetworkPeerReadBufferSize, "Size, in bytes, of the buffer that we read peer messages into (there is one buffer per peer)")
 	fs.Uint(NetworkPeerWriteBufferSizeKey, constants.DefaultNetworkPeerWriteBufferSize, "Size, in bytes, of the buffer that we write peer messages into (there is one buffer per peer)")
+	fs.Uint64(NetworkOutboundQueueConsensusWeightKey, constants.DefaultNetworkOutboundQueueConsensusWeight, "Relative share of each peer's outbound bandwidth given to consensus messages. Must be > 0")
+	fs.Uint64(NetworkOutboundQueueConsensusMaxBytesKey, constants.DefaultNetworkOutboundQueueConsensusMaxBytes, "Max number of bytes of consensus messages queued for each peer. If 0, only the outbound throttler applies")
+	fs.Uint64(NetworkOutboundQueueBootstrapWeightKey, constants.DefaultNetworkOutboundQueueBootstrapWeight, "Relative share of each peer's outbound bandwidth given to bootstrapping and state sync messages. Must be > 0")
+	fs.Uint64(NetworkOutboundQueueBootstrapMaxBytesKey, constants.DefaultNetworkOutboundQueueBootstrapMaxBytes, "Max number of bytes of bootstrapping and state sync messages queued for each peer. If 0, only the outbound throttler applies")
+	fs.Uint64(NetworkOutboundQueueAppRequestWeightKey, constants.DefaultNetworkOutboundQueueAppRequestWeight, "Relative share of each peer's outbound bandwidth given to app requests and responses. Must be > 0")
+	fs.Uint64(NetworkOutboundQueueAppRequestMaxBytesKey, constants.DefaultNetworkOutboundQueueAppRequestMaxBytes, "Max number of bytes of app requests and responses queued for each peer. If 0, only the outbound throttler applies")
+	fs.Uint64(NetworkOutboundQueueAppGossipWeightKey, constants.DefaultNetworkOutboundQueueAppGossipWeight, "Relative share of each peer's outbound bandwidth given to app gossip. Must be > 0")
+	fs.Uint64(NetworkOutboundQueueAppGossipMaxBytesKey, constants.DefaultNetworkOutboundQueueAppGossipMaxBytes, "Max number of bytes of app gossip queued for each peer. If 0, only the outbound throttler applies")
+	fs.Uint64(NetworkOutboundQueuePeerManagementWeightKey, constants.DefaultNetworkOutboundQueuePeerManagementWeight, "Relative share of each peer's outbound bandwidth given to handshake and peer list messages. Must be > 0")
+	fs.Uint64(NetworkOutboundQueuePeerManagementMaxBytesKey, constants.DefaultNetworkOutboundQueuePeerManagementMaxBytes, "Max number of bytes of handshake and peer list messages queued for each peer. If 0, only the outbound throttler applies")
 
 	fs.Bool(NetworkTCPProxyEnabledKey, constants.DefaultNetworkTCPProxyEnabled, "Require all P2P connections to be initiated with a TCP proxy header")
 	// The PROXY protocol specification recommends setting this value to be at
@@ -197,6 +213,13 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Duration(BenchlistDurationKey, constants.DefaultBenchlistDuration, "Max amount of time a peer is benchlisted after surpassing the threshold")
 	fs.Duration(BenchlistMinFailingDurationKey, constants.DefaultBenchlistMinFailingDuration, "Minimum amount of time messages to a peer must be failing before the peer is benched")
 
+	// Peer Reputation
+	fs.Duration(ReputationHalflifeKey, constants.DefaultReputationHalflife, "Halflife of the events used to calculate the reputation of peers")
+	fs.Duration(ReputationMaxLatencyKey, constants.DefaultReputationMaxLatency, "Average response latency at which a peer's reputation is maximally penalized for being slow")
+	fs.Float64(ReputationInvalidMessagePenaltyKey, constants.DefaultReputationInvalidMessagePenalty, "Penalty applied to a peer's reputation for each unparsable message it sends")
+	fs.Float64(ReputationProtocolViolationPenaltyKey, constants.DefaultReputationProtocolViolationPenalty, "Penalty applied to a peer's reputation for each protocol violation it commits")
+	fs.Float64(ReputationMinScoreKey, constants.DefaultReputationMinScore, "Reputation score in [0, 1] below which a peer is benched, deprioritized for requests and, if not a validator, disconnected")
+
 	// Router
 	fs.Uint(ConsensusAppConcurrencyKey, constants.DefaultConsensusAppConcurrency, "Maximum number of goroutines to use when handling App messages on a chain")
 	fs.Duration(ConsensusShutdownTimeoutKey, constants.DefaultConsensusShutdownTimeout, "Timeout before killing an unresponsive chain")
@@ -225,6 +248,8 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.String(HTTPSKeyContentKey, "", "Specifies base64 encoded TLS private key for the HTTPs server")
 	fs.String(HTTPSCertFileKey, "", fmt.Sprintf("TLS certificate file for the HTTPs server. Ignored if %s is specified", HTTPSCertContentKey))
 	fs.String(HTTPSCertContentKey, "", "Specifies base64 encoded TLS certificate for the HTTPs server")
+	fs.String(HTTPSClientCAFileKey, "", fmt.Sprintf("PEM encoded CA certificates used to verify TLS client certificates. Clients may authenticate with a certificate signed by one of these CAs. Requires %s", HTTPSEnabledKey))
+	fs.String(HTTPAuthPolicyFileKey, "", "JSON file that maps bearer tokens and TLS client certificates to the routes and JSON-RPC methods they may call. If not specified, API calls aren't authenticated")
 	fs.String(HTTPAllowedOrigins, "*", "Origins to allow on the HTTP port. Defaults to * which allows all origins. Example: https://*.avax.network https://*.avax-test.network")
 	fs.StringSlice(HTTPAllowedHostsKey, []string{"localhost"}, "List of acceptable host names in API requests. Provide the wildcard ('*') to accept requests from all hosts. API requests where the Host field is empty or an IP address will always be accepted. An API call whose HTTP Host field isn't acceptable will receive a 403 error code")
 	fs.Duration(HTTPShutdownWaitKey, 0, "Duration to wait after receiving SIGTERM or SIGINT before initiating shutdown. The /health endpoint will return unhealthy during this duration")
@@ -233,6 +258,10 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Duration(HTTPReadHeaderTimeoutKey, 30*time.Second, fmt.Sprintf("Maximum duration to read request headers. The connection's read deadline is reset after reading the headers. If %s is zero, the value of %s is used. If both are zero, there is no timeout.", HTTPReadHeaderTimeoutKey, HTTPReadTimeoutKey))
 	fs.Duration(HTTPWriteTimeoutKey, 30*time.Second, "Maximum duration before timing out writes of the response. It is reset whenever a new request's header is read. A zero or negative value means there will be no timeout.")
 	fs.Duration(HTTPIdleTimeoutKey, 120*time.Second, fmt.Sprintf("Maximum duration to wait for the next request when keep-alives are enabled. If %s is zero, the value of %s is used. If both are zero, there is no timeout.", HTTPIdleTimeoutKey, HTTPReadTimeoutKey))
+	fs.Float64(HTTPRateLimitRequestsPerSecondKey, 0, "Rate at which the API call budget of each client is refilled. Clients are identified by their authenticated identity or, if they didn't authenticate, by their IP. If 0, API calls aren't rate limited")
+	fs.Int(HTTPRateLimitBurstKey, 100, "Maximum API call budget of each client")
+	fs.Int(HTTPRateLimitMaxConcurrentRequestsKey, 0, "Maximum number of API calls each client may make concurrently. If 0, concurrent API calls aren't limited")
+	fs.StringToString(HTTPRateLimitCostsKey, map[string]string{}, "Budget consumed by API calls. Keys starting with '/' match request paths and other keys match JSON-RPC methods. Keys ending with '*' match by prefix. Calls that don't match cost 1")
 
 	// Enable/Disable APIs
 	fs.Bool(AdminAPIEnabledKey, false, "If true, this node exposes the Admin API")
@@ -244,6 +273,10 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	// Health Checks
 	fs.Duration(HealthCheckFreqKey, 30*time.Second, "Time between health checks")
 	fs.Duration(HealthCheckAveragerHalflifeKey, constants.DefaultHealthCheckAveragerHalflife, "Halflife of averager when calculating a running average in a health check")
+	fs.StringSlice(HealthWebhookURLsKey, nil, "URLs that are sent a POST request when a health check changes state")
+	fs.Int(HealthWebhookMaxAttemptsKey, 5, "Maximum number of attempts to notify a health webhook")
+	fs.String(HealthExecHookKey, "", "Path to an executable that is run when a health check changes state")
+	fs.Duration(HealthHookTimeoutKey, 10*time.Second, "Timeout of a health webhook attempt or of running the health exec hook")
 	// Network Layer Health
 	fs.Duration(NetworkHealthMaxTimeSinceMsgSentKey, constants.DefaultNetworkHealthMaxTimeSinceMsgSent, "Network layer returns unhealthy if haven't sent a message for at least this much time")
 	fs.Duration(NetworkHealthMaxTimeSinceMsgReceivedKey, constants.DefaultNetworkHealthMaxTimeSinceMsgReceived, "Network layer returns unhealthy if haven't received a message for at least this much time")
@@ -341,6 +374,13 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Bool(ProfileContinuousEnabledKey, false, "Whether the app should continuously produce performance profiles")
 	fs.Duration(ProfileContinuousFreqKey, 15*time.Minute, "How frequently to rotate performance profiles")
 	fs.Int(ProfileContinuousMaxFilesKey, 5, "Maximum number of historical profiles to keep")
+	fs.Bool(ProfileTriggeredEnabledKey, false, "Whether the app should capture performance profiles when a resource threshold is exceeded")
+	fs.Duration(ProfileTriggeredCheckFreqKey, 5*time.Second, "How frequently to check the resource thresholds of the triggered profiler")
+	fs.Float64(ProfileTriggeredCPUThresholdKey, 0, "Process CPU usage, in percent of a core, above which a profile is captured. If 0, CPU usage doesn't trigger captures")
+	fs.Int(ProfileTriggeredGoroutineThresholdKey, 0, "Number of goroutines above which a profile is captured. If 0, the number of goroutines doesn't trigger captures")
+	fs.Duration(ProfileTriggeredDurationKey, 30*time.Second, "Duration of the CPU profile and execution trace of a triggered capture")
+	fs.Duration(ProfileTriggeredCooldownKey, 10*time.Minute, "Minimum time between the end of a triggered capture and the start of the next one")
+	fs.Uint64(ProfileTriggeredMaxSizeKey, 512*units.MiB, "Maximum number of bytes used by triggered captures. When exceeded, the oldest captures are removed")
 
 	// Aliasing
 	fs.String(VMAliasesFileKey, defaultVMAliasFilePath, fmt.Sprintf("Specifies a JSON file that maps vmIDs with custom aliases. Ignored if %s is specified", VMAliasesContentKey))
@@ -378,6 +418,12 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Float64(TracingSampleRateKey, 0.1, "The fraction of traces to sample. If >= 1, always sample. If <= 0, never sample")
 	fs.StringToString(TracingHeadersKey, map[string]string{}, "The headers to provide the trace indexer")
 
+	// Opentelemetry metrics
+	fs.Bool(MetricsOTLPEnabledKey, false, "If true, push metrics to the OTLP endpoint configured by the tracing flags")
+	fs.Duration(MetricsOTLPFrequencyKey, 30*time.Second, "Time between pushes of metrics to the OTLP endpoint")
+	fs.StringSlice(MetricsOTLPIncludeKey, nil, "Patterns of the metric families to push to the OTLP endpoint. If empty, all metric families are pushed")
+	fs.StringSlice(MetricsOTLPExcludeKey, nil, "Patterns of the metric families to not push to the OTLP endpoint")
+
 	fs.String(ProcessContextFileKey, defaultProcessContextPath, "The path to write process context to (including PID, API URI, and staking address).")
 }
 
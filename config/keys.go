@@ -39,6 +39,7 @@ const (
 	AddPrimaryNetworkDelegatorFeeKey       = "add-primary-network-delegator-fee"
 	AddSubnetValidatorFeeKey               = "add-subnet-validator-fee"
 	AddSubnetDelegatorFeeKey               = "add-subnet-delegator-fee"
+	ValidatorFeeRateKey                    = "validator-fee-rate"
 	UptimeRequirementKey                   = "uptime-requirement"
 	MinValidatorStakeKey                   = "min-validator-stake"
 	MaxValidatorStakeKey                   = "max-validator-stake"
@@ -58,6 +59,8 @@ const (
 	PublicIPKey                            = "public-ip"
 	PublicIPResolutionFreqKey              = "public-ip-resolution-frequency"
 	PublicIPResolutionServiceKey           = "public-ip-resolution-service"
+	PublicIPResolutionSTUNServersKey       = "public-ip-resolution-stun-servers"
+	PublicIPResolutionSTUNQuorumKey        = "public-ip-resolution-stun-quorum"
 	HTTPHostKey                            = "http-host"
 	HTTPPortKey                            = "http-port"
 	HTTPSEnabledKey                        = "http-tls-enabled"
@@ -65,6 +68,8 @@ const (
 	HTTPSKeyContentKey                     = "http-tls-key-file-content"
 	HTTPSCertFileKey                       = "http-tls-cert-file"
 	HTTPSCertContentKey                    = "http-tls-cert-file-content"
+	HTTPSClientCAFileKey                   = "http-tls-client-ca-file"
+	HTTPAuthPolicyFileKey                  = "http-auth-policy-file"
 
 	HTTPAllowedOrigins       = "http-allowed-origins"
 	HTTPAllowedHostsKey      = "http-allowed-hosts"
@@ -73,6 +78,11 @@ const (
 	HTTPReadTimeoutKey       = "http-read-timeout"
 	HTTPReadHeaderTimeoutKey = "http-read-header-timeout"
 
+	HTTPRateLimitRequestsPerSecondKey     = "http-rate-limit-requests-per-second"
+	HTTPRateLimitBurstKey                 = "http-rate-limit-burst"
+	HTTPRateLimitMaxConcurrentRequestsKey = "http-rate-limit-max-concurrent-requests"
+	HTTPRateLimitCostsKey                 = "http-rate-limit-costs"
+
 	HTTPIdleTimeoutKey                                 = "http-idle-timeout"
 	StateSyncIPsKey                                    = "state-sync-ips"
 	StateSyncIDsKey                                    = "state-sync-ids"
@@ -111,11 +121,24 @@ const (
 	NetworkPingFrequencyKey                            = "network-ping-frequency"
 	NetworkMaxReconnectDelayKey                        = "network-max-reconnect-delay"
 	NetworkCompressionTypeKey                          = "network-compression-type"
+	NetworkCompressionDictionaryDirKey                 = "network-compression-dictionary-dir"
+	NetworkCompressionSamplesFileKey                   = "network-compression-samples-file"
+	NetworkCompressionSamplesMaxSizeKey                = "network-compression-samples-max-size"
 	NetworkMaxClockDifferenceKey                       = "network-max-clock-difference"
 	NetworkAllowPrivateIPsKey                          = "network-allow-private-ips"
 	NetworkRequireValidatorToConnectKey                = "network-require-validator-to-connect"
 	NetworkPeerReadBufferSizeKey                       = "network-peer-read-buffer-size"
 	NetworkPeerWriteBufferSizeKey                      = "network-peer-write-buffer-size"
+	NetworkOutboundQueueConsensusWeightKey             = "network-outbound-queue-consensus-weight"
+	NetworkOutboundQueueConsensusMaxBytesKey           = "network-outbound-queue-consensus-max-bytes"
+	NetworkOutboundQueueBootstrapWeightKey             = "network-outbound-queue-bootstrap-weight"
+	NetworkOutboundQueueBootstrapMaxBytesKey           = "network-outbound-queue-bootstrap-max-bytes"
+	NetworkOutboundQueueAppRequestWeightKey            = "network-outbound-queue-app-request-weight"
+	NetworkOutboundQueueAppRequestMaxBytesKey          = "network-outbound-queue-app-request-max-bytes"
+	NetworkOutboundQueueAppGossipWeightKey             = "network-outbound-queue-app-gossip-weight"
+	NetworkOutboundQueueAppGossipMaxBytesKey           = "network-outbound-queue-app-gossip-max-bytes"
+	NetworkOutboundQueuePeerManagementWeightKey        = "network-outbound-queue-peer-management-weight"
+	NetworkOutboundQueuePeerManagementMaxBytesKey      = "network-outbound-queue-peer-management-max-bytes"
 	NetworkTCPProxyEnabledKey                          = "network-tcp-proxy-enabled"
 	NetworkTCPProxyReadTimeoutKey                      = "network-tcp-proxy-read-timeout"
 	NetworkTLSKeyLogFileKey                            = "network-tls-key-log-file-unsafe"
@@ -126,6 +149,11 @@ const (
 	BenchlistFailThresholdKey                          = "benchlist-fail-threshold"
 	BenchlistDurationKey                               = "benchlist-duration"
 	BenchlistMinFailingDurationKey                     = "benchlist-min-failing-duration"
+	ReputationHalflifeKey                              = "reputation-halflife"
+	ReputationMaxLatencyKey                            = "reputation-max-latency"
+	ReputationInvalidMessagePenaltyKey                 = "reputation-invalid-message-penalty"
+	ReputationProtocolViolationPenaltyKey              = "reputation-protocol-violation-penalty"
+	ReputationMinScoreKey                              = "reputation-min-score"
 	LogsDirKey                                         = "log-dir"
 	LogLevelKey                                        = "log-level"
 	LogDisplayLevelKey                                 = "log-display-level"
@@ -163,6 +191,10 @@ const (
 	RouterHealthMaxOutstandingRequestsKey              = "router-health-max-outstanding-requests"
 	HealthCheckFreqKey                                 = "health-check-frequency"
 	HealthCheckAveragerHalflifeKey                     = "health-check-averager-halflife"
+	HealthWebhookURLsKey                               = "health-webhook-urls"
+	HealthWebhookMaxAttemptsKey                        = "health-webhook-max-attempts"
+	HealthExecHookKey                                  = "health-exec-hook"
+	HealthHookTimeoutKey                               = "health-hook-timeout"
 	PluginDirKey                                       = "plugin-dir"
 	BootstrapBeaconConnectionTimeoutKey                = "bootstrap-beacon-connection-timeout"
 	BootstrapMaxTimeGetAncestorsKey                    = "bootstrap-max-time-get-ancestors"
@@ -177,6 +209,13 @@ const (
 	ProfileContinuousEnabledKey                        = "profile-continuous-enabled"
 	ProfileContinuousFreqKey                           = "profile-continuous-freq"
 	ProfileContinuousMaxFilesKey                       = "profile-continuous-max-files"
+	ProfileTriggeredEnabledKey                         = "profile-triggered-enabled"
+	ProfileTriggeredCheckFreqKey                       = "profile-triggered-check-freq"
+	ProfileTriggeredCPUThresholdKey                    = "profile-triggered-cpu-threshold"
+	ProfileTriggeredGoroutineThresholdKey              = "profile-triggered-goroutine-threshold"
+	ProfileTriggeredDurationKey                        = "profile-triggered-duration"
+	ProfileTriggeredCooldownKey                        = "profile-triggered-cooldown"
+	ProfileTriggeredMaxSizeKey                         = "profile-triggered-max-size"
 	InboundThrottlerAtLargeAllocSizeKey                = "throttler-inbound-at-large-alloc-size"
 	InboundThrottlerVdrAllocSizeKey                    = "throttler-inbound-validator-alloc-size"
 	InboundThrottlerNodeMaxAtLargeBytesKey             = "throttler-inbound-node-max-at-large-bytes"
@@ -211,5 +250,9 @@ const (
 	TracingSampleRateKey                               = "tracing-sample-rate"
 	TracingExporterTypeKey                             = "tracing-exporter-type"
 	TracingHeadersKey                                  = "tracing-headers"
+	MetricsOTLPEnabledKey                              = "metrics-otlp-enabled"
+	MetricsOTLPFrequencyKey                            = "metrics-otlp-frequency"
+	MetricsOTLPIncludeKey                              = "metrics-otlp-include"
+	MetricsOTLPExcludeKey                              = "metrics-otlp-exclude"
 	ProcessContextFileKey                              = "process-context-file"
 )
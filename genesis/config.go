@@ -69,6 +69,10 @@ type Staker struct {
 	RewardAddress ids.ShortID               `json:"rewardAddress"`
 	DelegationFee uint32                    `json:"delegationFee"`
 	Signer        *signer.ProofOfPossession `json:"signer,omitempty"`
+	// Weight is the amount of the initially staked funds staked by this
+	// staker. If no initial staker specifies its weight, the initially staked
+	// funds are split evenly across the initial stakers.
+	Weight uint64 `json:"weight,omitempty"`
 }
 
 func (s Staker) Unparse(networkID uint32) (UnparsedStaker, error) {
@@ -82,6 +86,7 @@ func (s Staker) Unparse(networkID uint32) (UnparsedStaker, error) {
 		RewardAddress: avaxAddr,
 		DelegationFee: s.DelegationFee,
 		Signer:        s.Signer,
+		Weight:        s.Weight,
 	}, err
 }
 
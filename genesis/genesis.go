@@ -14,6 +14,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/vms/avm"
 	"github.com/ava-labs/avalanchego/vms/avm/fxs"
@@ -46,6 +47,8 @@ var (
 	errFutureStartTime                 = errors.New("startTime cannot be in the future")
 	errInitialStakeDurationTooLow      = errors.New("initial stake duration is too low")
 	errOverridesStandardNetworkConfig  = errors.New("overrides standard network genesis config")
+	errMissingStakerWeight             = errors.New("initial staker weight missing")
+	errStakerWeightsTooHigh            = errors.New("initial staker weights exceed initially staked funds")
 )
 
 // validateInitialStakedFunds ensures all staked
@@ -113,6 +116,50 @@ func validateInitialStakedFunds(config *Config) error {
 	return nil
 }
 
+// validateInitialStakerWeights ensures that either no initial staker or every
+// initial staker specifies its weight, and that the specified weights don't
+// exceed the initially staked funds.
+func validateInitialStakerWeights(config *Config) error {
+	var totalWeight uint64
+	for _, staker := range config.InitialStakers {
+		if staker.Weight == 0 {
+			continue
+		}
+		var err error
+		totalWeight, err = math.Add(totalWeight, staker.Weight)
+		if err != nil {
+			return err
+		}
+	}
+	if totalWeight == 0 {
+		return nil
+	}
+	for _, staker := range config.InitialStakers {
+		if staker.Weight == 0 {
+			return fmt.Errorf("%w: %s", errMissingStakerWeight, staker.NodeID)
+		}
+	}
+
+	initiallyStaked := set.Of(config.InitialStakedFunds...)
+	var stakedFunds uint64
+	for _, allocation := range config.Allocations {
+		if !initiallyStaked.Contains(allocation.AVAXAddr) {
+			continue
+		}
+		for _, unlock := range allocation.UnlockSchedule {
+			var err error
+			stakedFunds, err = math.Add(stakedFunds, unlock.Amount)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if totalWeight > stakedFunds {
+		return fmt.Errorf("%w: %d > %d", errStakerWeightsTooHigh, totalWeight, stakedFunds)
+	}
+	return nil
+}
+
 // validateConfig returns an error if the provided
 // *Config is not considered valid.
 func validateConfig(networkID uint32, config *Config, stakingCfg *StakingConfig) error {
@@ -173,6 +220,10 @@ func validateConfig(networkID uint32, config *Config, stakingCfg *StakingConfig)
 		return fmt.Errorf("initial staked funds validation failed: %w", err)
 	}
 
+	if err := validateInitialStakerWeights(config); err != nil {
+		return fmt.Errorf("initial staker weights validation failed: %w", err)
+	}
+
 	if len(config.CChainGenesis) == 0 {
 		return errNoCChainGenesis
 	}
@@ -381,7 +432,7 @@ func FromConfig(config *Config) ([]byte, ids.ID, error) {
 		}
 	}
 
-	allNodeAllocations := splitAllocations(skippedAllocations, len(config.InitialStakers))
+	allNodeAllocations := splitAllocations(skippedAllocations, initialStakerWeights(config.InitialStakers, skippedAllocations))
 	endStakingTime := genesisTime.Add(time.Duration(config.InitialStakeDuration) * time.Second)
 	stakingOffset := time.Duration(0)
 	for i, staker := range config.InitialStakers {
@@ -435,6 +486,33 @@ func FromConfig(config *Config) ([]byte, ids.ID, error) {
 		)
 	}
 
+	// The initially staked funds that aren't staked by any initial staker are
+	// allocated as UTXOs.
+	for _, nodeAllocations := range allNodeAllocations[len(config.InitialStakers):] {
+		for _, allocation := range nodeAllocations {
+			addr, err := address.FormatBech32(hrp, allocation.AVAXAddr.Bytes())
+			if err != nil {
+				return nil, ids.Empty, err
+			}
+			for _, unlock := range allocation.UnlockSchedule {
+				if unlock.Amount == 0 {
+					continue
+				}
+				msgStr, err := formatting.Encode(defaultEncoding, allocation.ETHAddr.Bytes())
+				if err != nil {
+					return nil, ids.Empty, fmt.Errorf("couldn't encode message: %w", err)
+				}
+				platformvmArgs.UTXOs = append(platformvmArgs.UTXOs, api.UTXO{
+					Locktime: json.Uint64(unlock.Locktime),
+					Amount:   json.Uint64(unlock.Amount),
+					Address:  addr,
+					Message:  msgStr,
+				})
+				amount += unlock.Amount
+			}
+		}
+	}
+
 	// Specify the chains that exist upon this network's creation
 	genesisStr, err := formatting.Encode(defaultEncoding, []byte(config.CChainGenesis))
 	if err != nil {
@@ -474,7 +552,11 @@ func FromConfig(config *Config) ([]byte, ids.ID, error) {
 	return genesisBytes, avaxAssetID, nil
 }
 
-func splitAllocations(allocations []Allocation, numSplits int) [][]Allocation {
+// initialStakerWeights returns the amount of [allocations] staked by each of
+// the [stakers]. If the stakers don't specify their weights, the allocations
+// are split evenly across the stakers. Otherwise, the amount that isn't staked
+// by any staker is returned as an additional weight.
+func initialStakerWeights(stakers []Staker, allocations []Allocation) []uint64 {
 	totalAmount := uint64(0)
 	for _, allocation := range allocations {
 		for _, unlock := range allocation.UnlockSchedule {
@@ -482,7 +564,26 @@ func splitAllocations(allocations []Allocation, numSplits int) [][]Allocation {
 		}
 	}
 
-	nodeWeight := totalAmount / uint64(numSplits)
+	weights := make([]uint64, len(stakers), len(stakers)+1)
+	if len(stakers) == 0 || stakers[0].Weight == 0 {
+		for i := range weights {
+			weights[i] = totalAmount / uint64(len(stakers))
+		}
+		return weights
+	}
+
+	remainingAmount := totalAmount
+	for i, staker := range stakers {
+		weights[i] = staker.Weight
+		remainingAmount -= staker.Weight
+	}
+	return append(weights, remainingAmount)
+}
+
+// splitAllocations splits [allocations] into one split per entry of
+// [nodeWeights]. The last split receives all the remaining allocations.
+func splitAllocations(allocations []Allocation, nodeWeights []uint64) [][]Allocation {
+	numSplits := len(nodeWeights)
 	allNodeAllocations := make([][]Allocation, 0, numSplits)
 
 	currentNodeAllocation := []Allocation(nil)
@@ -496,15 +597,19 @@ func splitAllocations(allocations []Allocation, numSplits int) [][]Allocation {
 
 		for _, unlock := range allocation.UnlockSchedule {
 			unlock := unlock
-			for currentNodeAmount+unlock.Amount > nodeWeight && len(allNodeAllocations) < numSplits-1 {
-				amountToAdd := nodeWeight - currentNodeAmount
-				currentAllocation.UnlockSchedule = append(currentAllocation.UnlockSchedule, LockedAmount{
-					Amount:   amountToAdd,
-					Locktime: unlock.Locktime,
-				})
-				unlock.Amount -= amountToAdd
+			for len(allNodeAllocations) < numSplits-1 && currentNodeAmount+unlock.Amount > nodeWeights[len(allNodeAllocations)] {
+				amountToAdd := nodeWeights[len(allNodeAllocations)] - currentNodeAmount
+				if amountToAdd > 0 {
+					currentAllocation.UnlockSchedule = append(currentAllocation.UnlockSchedule, LockedAmount{
+						Amount:   amountToAdd,
+						Locktime: unlock.Locktime,
+					})
+					unlock.Amount -= amountToAdd
+				}
 
-				currentNodeAllocation = append(currentNodeAllocation, currentAllocation)
+				if len(currentAllocation.UnlockSchedule) > 0 {
+					currentNodeAllocation = append(currentNodeAllocation, currentAllocation)
+				}
 
 				allNodeAllocations = append(allNodeAllocations, currentNodeAllocation)
 
@@ -47,6 +47,7 @@ var (
 				MinGasPrice:              1,
 				ExcessConversionConstant: 1,
 			},
+			ValidatorFeeRate: 512,
 		},
 		StakingConfig: StakingConfig{
 			UptimeRequirement: .8, // 80%
@@ -65,6 +65,7 @@ var (
 				MinGasPrice:              1,
 				ExcessConversionConstant: 1,
 			},
+			ValidatorFeeRate: 512,
 		},
 		StakingConfig: StakingConfig{
 			UptimeRequirement: .8, // 80%
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
@@ -21,7 +22,11 @@ import (
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/platformvm/genesis"
+
+	pchaintxs "github.com/ava-labs/avalanchego/vms/platformvm/txs"
 )
 
 var (
@@ -143,6 +148,26 @@ func TestValidateConfig(t *testing.T) {
 			}(),
 			expectedErr: errNoAllocationToStake,
 		},
+		"missing initial staker weight": {
+			networkID: 12345,
+			config: func() *Config {
+				thisConfig := LocalConfig
+				thisConfig.InitialStakers = slices.Clone(thisConfig.InitialStakers)
+				thisConfig.InitialStakers[0].Weight = units.KiloAvax
+				return &thisConfig
+			}(),
+			expectedErr: errMissingStakerWeight,
+		},
+		"initial staker weights exceed initially staked funds": {
+			networkID:   12345,
+			config:      withInitialStakerWeights(LocalConfig, 1<<60),
+			expectedErr: errStakerWeightsTooHigh,
+		},
+		"initial staker weights": {
+			networkID:   12345,
+			config:      withInitialStakerWeights(LocalConfig, units.KiloAvax),
+			expectedErr: nil,
+		},
 		"empty C-Chain genesis": {
 			networkID: 12345,
 			config: func() *Config {
@@ -171,6 +196,63 @@ func TestValidateConfig(t *testing.T) {
 	}
 }
 
+func TestGenesisInitialStakerWeights(t *testing.T) {
+	require := require.New(t)
+
+	const weight = units.MegaAvax
+	config := withInitialStakerWeights(unmodifiedLocalConfig, weight)
+	genesisBytes, _, err := FromConfig(config)
+	require.NoError(err)
+	gen, err := genesis.Parse(genesisBytes)
+	require.NoError(err)
+
+	require.Len(gen.Validators, len(config.InitialStakers))
+	for _, tx := range gen.Validators {
+		validatorTx, ok := tx.Unsigned.(pchaintxs.ValidatorTx)
+		require.True(ok)
+		require.Equal(uint64(weight), validatorTx.Weight())
+	}
+
+	// The initially staked funds that aren't staked by any initial staker are
+	// allocated as UTXOs.
+	evenGenesisBytes, _, err := FromConfig(&unmodifiedLocalConfig)
+	require.NoError(err)
+	evenGen, err := genesis.Parse(evenGenesisBytes)
+	require.NoError(err)
+	require.Equal(
+		utxosAmount(t, evenGen)+stakedAmount(t, evenGen),
+		utxosAmount(t, gen)+stakedAmount(t, gen),
+	)
+}
+
+func withInitialStakerWeights(config Config, weight uint64) *Config {
+	config.InitialStakers = slices.Clone(config.InitialStakers)
+	for i := range config.InitialStakers {
+		config.InitialStakers[i].Weight = weight
+	}
+	return &config
+}
+
+func utxosAmount(t *testing.T, gen *genesis.Genesis) uint64 {
+	var amount uint64
+	for _, utxo := range gen.UTXOs {
+		out, ok := utxo.Out.(avax.Amounter)
+		require.True(t, ok)
+		amount += out.Amount()
+	}
+	return amount
+}
+
+func stakedAmount(t *testing.T, gen *genesis.Genesis) uint64 {
+	var amount uint64
+	for _, tx := range gen.Validators {
+		validatorTx, ok := tx.Unsigned.(pchaintxs.ValidatorTx)
+		require.True(t, ok)
+		amount += validatorTx.Weight()
+	}
+	return amount
+}
+
 func TestGenesisFromFile(t *testing.T) {
 	tests := map[string]struct {
 		networkID       uint32
@@ -40,6 +40,9 @@ type TxFeeConfig struct {
 	CreateAssetTxFee uint64              `json:"createAssetTxFee"`
 	StaticFeeConfig  txfee.StaticConfig  `json:"staticFeeConfig"`
 	DynamicFeeConfig feecomponent.Config `json:"dynamicFeeConfig"`
+	// ValidatorFeeRate is the fee, in nAVAX per second, that each active
+	// subnet-only validator pays out of its balance after Etna.
+	ValidatorFeeRate uint64 `json:"validatorFeeRate"`
 }
 
 type Params struct {
@@ -59,6 +59,7 @@ type UnparsedStaker struct {
 	RewardAddress string                    `json:"rewardAddress"`
 	DelegationFee uint32                    `json:"delegationFee"`
 	Signer        *signer.ProofOfPossession `json:"signer,omitempty"`
+	Weight        uint64                    `json:"weight,omitempty"`
 }
 
 func (us UnparsedStaker) Parse() (Staker, error) {
@@ -66,6 +67,7 @@ func (us UnparsedStaker) Parse() (Staker, error) {
 		NodeID:        us.NodeID,
 		DelegationFee: us.DelegationFee,
 		Signer:        us.Signer,
+		Weight:        us.Weight,
 	}
 
 	_, _, avaxAddrBytes, err := address.Parse(us.RewardAddress)
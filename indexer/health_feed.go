@@ -0,0 +1,145 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+const (
+	// defaultHealthPollFrequency is how often the health of the chain is
+	// checked for transitions.
+	defaultHealthPollFrequency = time.Second
+	// maxPendingHealthTransitions is the number of transitions that may be
+	// queued for a subscriber before it is dropped.
+	maxPendingHealthTransitions = 16
+)
+
+// healthFeed polls the health of a chain and fans out its transitions to
+// subscribers.
+//
+// The health is only polled while there are subscribers, and a single poller
+// is shared by every subscriber. Subscribers that don't keep up are dropped
+// rather than blocking the poller. Dropped subscribers can resubscribe to
+// receive the current health.
+type healthFeed struct {
+	reporter      health.Reporter
+	chainName     string
+	pollFrequency time.Duration
+
+	lock sync.Mutex
+	// last is the most recently polled health, or nil if the health hasn't
+	// been polled since the poller was started.
+	last        *HealthTransition
+	subscribers set.Set[*healthSubscriber]
+	// stopPolling is non-nil while the poller is running
+	stopPolling context.CancelFunc
+}
+
+type healthSubscriber struct {
+	// transitions is closed if the subscriber is dropped
+	transitions chan HealthTransition
+}
+
+func newHealthFeed(reporter health.Reporter, chainName string) *healthFeed {
+	return &healthFeed{
+		reporter:      reporter,
+		chainName:     chainName,
+		pollFrequency: defaultHealthPollFrequency,
+	}
+}
+
+// subscribe returns a subscriber that receives the current health of the
+// chain followed by every later transition.
+func (f *healthFeed) subscribe() *healthSubscriber {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	subscriber := &healthSubscriber{
+		transitions: make(chan HealthTransition, maxPendingHealthTransitions),
+	}
+	if f.last != nil {
+		subscriber.transitions <- *f.last
+	}
+	f.subscribers.Add(subscriber)
+
+	if f.stopPolling == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		f.stopPolling = cancel
+		go f.poll(ctx)
+	}
+	return subscriber
+}
+
+// unsubscribe removes [subscriber]. Once the last subscriber is removed, the
+// health is no longer polled.
+func (f *healthFeed) unsubscribe(subscriber *healthSubscriber) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.subscribers.Remove(subscriber)
+	if f.subscribers.Len() > 0 || f.stopPolling == nil {
+		return
+	}
+	f.stopPolling()
+	f.stopPolling = nil
+	f.last = nil
+}
+
+// poll checks the health of the chain until [ctx] is cancelled.
+func (f *healthFeed) poll(ctx context.Context) {
+	ticker := time.NewTicker(f.pollFrequency)
+	defer ticker.Stop()
+
+	for {
+		f.check(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check sends the health of the chain to every subscriber if it changed since
+// the last check.
+func (f *healthFeed) check(ctx context.Context) {
+	results, _ := f.reporter.Health()
+	result, ok := results[f.chainName]
+	if !ok {
+		return
+	}
+	healthy := result.Error == nil
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	// The poller may have been stopped while the health was being checked.
+	if ctx.Err() != nil {
+		return
+	}
+	if f.last != nil && f.last.Healthy == healthy {
+		return
+	}
+
+	f.last = &HealthTransition{
+		Healthy:   healthy,
+		Error:     result.Error,
+		Timestamp: result.Timestamp,
+	}
+	for subscriber := range f.subscribers {
+		select {
+		case subscriber.transitions <- *f.last:
+		default:
+			close(subscriber.transitions)
+			f.subscribers.Remove(subscriber)
+		}
+	}
+}
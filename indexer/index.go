@@ -54,6 +54,8 @@ type index struct {
 	// Container ID --> Index
 	containerToIndex database.Database
 	log              logging.Logger
+	// accepted is closed, and replaced, whenever a container is indexed
+	accepted chan struct{}
 }
 
 // Create a new thread-safe index.
@@ -75,6 +77,7 @@ func newIndex(
 		indexToContainer: indexToContainer,
 		containerToIndex: containerToIndex,
 		log:              log,
+		accepted:         make(chan struct{}),
 	}
 
 	// Get next accepted index from db
@@ -158,7 +161,23 @@ func (i *index) Accept(ctx *snow.ConsensusContext, containerID ids.ID, container
 	}
 
 	// Atomically commit [i.vDB], [i.indexToContainer], [i.containerToIndex] to [i.baseDB]
-	return i.vDB.Commit()
+	if err := i.vDB.Commit(); err != nil {
+		return err
+	}
+
+	// Notify subscribers of the newly indexed container
+	close(i.accepted)
+	i.accepted = make(chan struct{})
+	return nil
+}
+
+// NextAccepted returns the index that the next accepted container will have
+// and a channel that is closed once it is accepted.
+func (i *index) NextAccepted() (uint64, <-chan struct{}) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.nextAcceptedIndex, i.accepted
 }
 
 // Returns the ID of the [index]th accepted container and the container itself.
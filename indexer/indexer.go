@@ -6,11 +6,13 @@ package indexer
 import (
 	"fmt"
 	"io"
+	"path"
 	"sync"
 
 	"github.com/gorilla/rpc/v2"
 	"go.uber.org/zap"
 
+	"github.com/ava-labs/avalanchego/api/health"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/database"
@@ -20,6 +22,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -53,6 +56,16 @@ type Config struct {
 	VertexAcceptorGroup  snow.AcceptorGroup
 	APIServer            server.PathAdder
 	ShutdownF            func()
+
+	// AllowedOrigins are the origins that browsers may open subscriptions
+	// from. They should be the origins allowed by [APIServer].
+	AllowedOrigins []string
+
+	// Validators, if non-nil, are streamed to subscribers of the P-chain.
+	Validators validators.Manager
+	// Health, if non-nil, is used to stream the health of chains to
+	// subscribers.
+	Health health.Reporter
 }
 
 // Indexer causes accepted containers for a given chain
@@ -78,8 +91,12 @@ func NewIndexer(config Config) (Indexer, error) {
 		txIndices:            map[ids.ID]*index{},
 		vtxIndices:           map[ids.ID]*index{},
 		blockIndices:         map[ids.ID]*index{},
+		subscriptionServers:  map[ids.ID]*subscriptionServer{},
 		pathAdder:            config.APIServer,
 		shutdownF:            config.ShutdownF,
+		allowedOrigins:       config.AllowedOrigins,
+		validators:           config.Validators,
+		health:               config.Health,
 	}
 
 	hasRun, err := indexer.hasRun()
@@ -105,6 +122,8 @@ type indexer struct {
 
 	// Used to add API endpoint for new indices
 	pathAdder server.PathAdder
+	// Origins that browsers may open subscriptions from
+	allowedOrigins []string
 
 	// If true, allow running in such a way that could allow the creation
 	// of an index which could be missing accepted containers.
@@ -119,6 +138,13 @@ type indexer struct {
 	vtxIndices map[ids.ID]*index
 	// Chain ID --> index of txs of that chain (if applicable)
 	txIndices map[ids.ID]*index
+	// Chain ID --> server of subscriptions to that chain
+	subscriptionServers map[ids.ID]*subscriptionServer
+
+	// Streamed to subscribers of the P-chain. May be nil.
+	validators validators.Manager
+	// Streamed to subscribers of every chain. May be nil.
+	health health.Reporter
 
 	// Notifies of newly accepted blocks
 	blockAcceptorGroup snow.AcceptorGroup
@@ -303,9 +329,51 @@ func (i *indexer) RegisterChain(chainName string, ctx *snow.ConsensusContext, vm
 				zap.Error(err),
 			)
 		}
+		return
+	}
+
+	if err := i.registerSubscriptionServer(chainName, chainID); err != nil {
+		i.log.Fatal("couldn't create subscription server",
+			zap.String("chainName", chainName),
+			zap.Error(err),
+		)
+		if err := i.close(); err != nil {
+			i.log.Error("failed to close indexer",
+				zap.Error(err),
+			)
+		}
 	}
 }
 
+// registerSubscriptionServer exposes the indices of [chainID] to subscribers
+// on the chain's API endpoint.
+func (i *indexer) registerSubscriptionServer(chainName string, chainID ids.ID) error {
+	var feed *validatorFeed
+	if chainID == constants.PlatformChainID && i.validators != nil {
+		feed = newValidatorFeed()
+	}
+
+	subscriptionServer := newSubscriptionServer(
+		i.log,
+		chainName,
+		i.allowedOrigins,
+		i.blockIndices[chainID],
+		i.txIndices[chainID],
+		feed,
+		i.health,
+	)
+	base := path.Join(constants.ChainAliasPrefix, chainID.String())
+	if err := i.pathAdder.AddRoute(subscriptionServer, base, subscriptionEndpoint); err != nil {
+		subscriptionServer.close()
+		return err
+	}
+	if feed != nil {
+		i.validators.RegisterCallbackListener(feed)
+	}
+	i.subscriptionServers[chainID] = subscriptionServer
+	return nil
+}
+
 func (i *indexer) registerChainHelper(
 	chainID ids.ID,
 	prefixEnd byte,
@@ -361,6 +429,10 @@ func (i *indexer) close() error {
 	}
 	i.closed = true
 
+	for _, subscriptionServer := range i.subscriptionServers {
+		subscriptionServer.close()
+	}
+
 	errs := &wrappers.Errs{}
 	for chainID, txIndex := range i.txIndices {
 		errs.Add(
@@ -170,9 +170,12 @@ func TestIndexer(t *testing.T) {
 	previouslyIndexed, err = idxr.previouslyIndexed(chain1Ctx.ChainID)
 	require.NoError(err)
 	require.True(previouslyIndexed)
-	require.Equal(1, server.timesCalled)
+	require.Equal(2, server.timesCalled)
 	require.Equal("index/chain1", server.bases[0])
 	require.Equal("/block", server.endpoints[0])
+	require.Equal("bc/"+chain1Ctx.ChainID.String(), server.bases[1])
+	require.Equal(subscriptionEndpoint, server.endpoints[1])
+	require.Len(idxr.subscriptionServers, 1)
 	require.Len(idxr.blockIndices, 1)
 	require.Empty(idxr.txIndices)
 	require.Empty(idxr.vtxIndices)
@@ -253,8 +256,9 @@ func TestIndexer(t *testing.T) {
 	container, err = blkIdx.GetLastAccepted()
 	require.NoError(err)
 	require.Equal(blkID, container.ID)
-	require.Equal(1, server.timesCalled) // block index for chain
+	require.Equal(2, server.timesCalled) // block index and subscriptions for chain
 	require.Contains(server.endpoints, "/block")
+	require.Contains(server.endpoints, subscriptionEndpoint)
 
 	// Register a DAG chain
 	snow2Ctx := snowtest.Context(t, snowtest.XChainID)
@@ -268,8 +272,9 @@ func TestIndexer(t *testing.T) {
 	dagVM := vertex.NewMockLinearizableVM(ctrl)
 	idxr.RegisterChain("chain2", chain2Ctx, dagVM)
 	require.NoError(err)
-	require.Equal(4, server.timesCalled) // block index for chain, block index for dag, vtx index, tx index
+	require.Equal(6, server.timesCalled) // block index and subscriptions for chain, block index for dag, vtx index, tx index, subscriptions for dag
 	require.Contains(server.bases, "index/chain2")
+	require.Contains(server.bases, "bc/"+chain2Ctx.ChainID.String())
 	require.Contains(server.endpoints, "/block")
 	require.Contains(server.endpoints, "/vtx")
 	require.Contains(server.endpoints, "/tx")
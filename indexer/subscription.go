@@ -0,0 +1,558 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	subscriptionEndpoint = "/subscribe"
+
+	// BlocksTopic streams the blocks accepted by the chain.
+	BlocksTopic = "blocks"
+	// TxsTopic streams the transactions accepted by a DAG chain.
+	TxsTopic = "txs"
+	// ValidatorsTopic streams the changes of the validator sets tracked by the
+	// P-chain.
+	ValidatorsTopic = "validators"
+	// HealthTopic streams the transitions of the health of the chain.
+	HealthTopic = "health"
+
+	subscriptionReadBufferSize  = units.KiB
+	subscriptionWriteBufferSize = units.KiB
+	// Time allowed to write a message to the client.
+	subscriptionWriteWait = 10 * time.Second
+	// Time allowed to read the next pong message from the client.
+	subscriptionPongWait = 60 * time.Second
+	// Send pings to the client with this period. Must be less than
+	// [subscriptionPongWait].
+	subscriptionPingPeriod = (subscriptionPongWait * 9) / 10
+	// Maximum size of a request from the client.
+	maxSubscriptionRequestSize = 10 * units.KiB
+	// Maximum number of messages queued to be written to a client.
+	maxPendingSubscriptionMessages = 1024
+
+	// allowAllOrigins allows subscriptions from every origin.
+	allowAllOrigins = "*"
+)
+
+var (
+	errUnknownTopic        = errors.New("unknown topic")
+	errUnsupportedTopic    = errors.New("topic isn't supported by this chain")
+	errAlreadySubscribed   = errors.New("already subscribed to topic")
+	errNotSubscribed       = errors.New("not subscribed to topic")
+	errInvalidSubscription = errors.New("request must either subscribe or unsubscribe")
+	errFellBehind          = errors.New("subscription fell behind and was dropped, resubscribe to receive a new snapshot")
+)
+
+// SubscriptionRequest is sent by a client to start or stop a subscription.
+// Exactly one of [Subscribe] and [Unsubscribe] must be set.
+type SubscriptionRequest struct {
+	Subscribe   *Subscription `json:"subscribe,omitempty"`
+	Unsubscribe *Subscription `json:"unsubscribe,omitempty"`
+}
+
+// Subscription to a topic. Each topic can be subscribed to at most once per
+// connection.
+type Subscription struct {
+	Topic string `json:"topic"`
+
+	// FromIndex is the index of the first container to send on the blocks and
+	// txs topics. Clients that reconnect should set it to one more than the
+	// index of the last container they received, so no containers are
+	// missed. If nil, only containers accepted after subscribing are sent.
+	FromIndex *json.Uint64 `json:"fromIndex,omitempty"`
+	// Encoding of the containers sent on the blocks and txs topics.
+	Encoding formatting.Encoding `json:"encoding"`
+
+	// SubnetIDs are the subnets whose validator set changes are sent on the
+	// validators topic. If empty, the changes of every subnet are sent.
+	SubnetIDs []ids.ID `json:"subnetIDs,omitempty"`
+}
+
+// SubscriptionMessage is sent by the server for a topic.
+type SubscriptionMessage struct {
+	Topic string `json:"topic"`
+
+	// Container is set on the blocks and txs topics. Its index is the cursor
+	// to resume the subscription from.
+	Container *FormattedContainer `json:"container,omitempty"`
+
+	// Validators is set on the validators topic. The first message after
+	// subscribing has [Snapshot] set and contains every current validator.
+	// Every later message contains a single change.
+	Validators []ValidatorChange `json:"validators,omitempty"`
+	Snapshot   bool              `json:"snapshot,omitempty"`
+
+	// Health is set on the health topic. The first message after subscribing
+	// contains the current health of the chain.
+	Health *HealthTransition `json:"health,omitempty"`
+
+	// Error is set if a request failed or if a subscription was ended by the
+	// server. Once an error is sent for a topic, the topic must be subscribed
+	// to again to receive further messages.
+	Error string `json:"error,omitempty"`
+}
+
+// ValidatorChange is a change of the weight of a validator. A validator was
+// added if [PreviousWeight] is 0 and was removed if [Weight] is 0.
+type ValidatorChange struct {
+	SubnetID       ids.ID      `json:"subnetID"`
+	NodeID         ids.NodeID  `json:"nodeID"`
+	PreviousWeight json.Uint64 `json:"previousWeight"`
+	Weight         json.Uint64 `json:"weight"`
+}
+
+// HealthTransition reports the health of the chain.
+type HealthTransition struct {
+	Healthy   bool      `json:"healthy"`
+	Error     *string   `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// subscriptionServer serves subscriptions to the events of a chain over
+// websockets.
+type subscriptionServer struct {
+	log       logging.Logger
+	chainName string
+
+	upgrader websocket.Upgrader
+
+	// [txIndex], [validators] and [health] are nil if the chain doesn't
+	// support their topics.
+	blockIndex *index
+	txIndex    *index
+	validators *validatorFeed
+	health     *healthFeed
+
+	// ctx is cancelled when the server is closed
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// newSubscriptionServer returns a server that accepts connections from
+// browsers whose origin is in [allowedOrigins] or is the same as the host of
+// the server. [healthReporter] may be nil if the health topic isn't supported.
+func newSubscriptionServer(
+	log logging.Logger,
+	chainName string,
+	allowedOrigins []string,
+	blockIndex *index,
+	txIndex *index,
+	validators *validatorFeed,
+	healthReporter health.Reporter,
+) *subscriptionServer {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &subscriptionServer{
+		log:       log,
+		chainName: chainName,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  subscriptionReadBufferSize,
+			WriteBufferSize: subscriptionWriteBufferSize,
+			CheckOrigin:     newOriginChecker(allowedOrigins),
+		},
+		blockIndex: blockIndex,
+		txIndex:    txIndex,
+		validators: validators,
+
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	if healthReporter != nil {
+		s.health = newHealthFeed(healthReporter, chainName)
+	}
+	return s
+}
+
+// newOriginChecker returns a function that allows requests without an origin,
+// such as requests made by non-browser clients, requests whose origin matches
+// one of [allowedOrigins] and requests whose origin is the same as their host.
+//
+// Origins are matched in the same way as by the CORS handler of the API
+// server: "*" matches every origin and an origin may contain one "*" that
+// matches any substring.
+func newOriginChecker(allowedOrigins []string) func(*http.Request) bool {
+	var patterns []string
+	for _, origin := range allowedOrigins {
+		if origin == allowAllOrigins {
+			return func(*http.Request) bool {
+				return true
+			}
+		}
+		patterns = append(patterns, strings.ToLower(origin))
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		origin = strings.ToLower(origin)
+		for _, pattern := range patterns {
+			prefix, suffix, ok := strings.Cut(pattern, "*")
+			if !ok && origin == pattern {
+				return true
+			}
+			if ok && len(origin) >= len(prefix)+len(suffix) &&
+				strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+// close ends every subscription and rejects new connections.
+func (s *subscriptionServer) close() {
+	s.cancel()
+}
+
+// ServeHTTP upgrades the request to a websocket connection and blocks until
+// the connection is closed.
+func (s *subscriptionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.ctx.Err() != nil {
+		http.Error(w, "indexer is closed", http.StatusServiceUnavailable)
+		return
+	}
+
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.log.Debug("failed to upgrade subscription connection",
+			zap.String("chainName", s.chainName),
+			zap.Error(err),
+		)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	c := &subscriptionConn{
+		server:        s,
+		ws:            ws,
+		ctx:           ctx,
+		cancel:        cancel,
+		send:          make(chan *SubscriptionMessage, maxPendingSubscriptionMessages),
+		subscriptions: make(map[string]*activeSubscription),
+	}
+	go c.readPump()
+	c.writePump()
+
+	// Subscriptions are only started while [c.lock] is held and [c.ctx] isn't
+	// cancelled, so no subscriptions can be started after this.
+	c.lock.Lock()
+	c.cancel()
+	c.lock.Unlock()
+
+	_ = ws.Close()
+	c.subscriptionsWG.Wait()
+}
+
+type activeSubscription struct {
+	cancel context.CancelFunc
+}
+
+// subscriptionConn is a websocket connection of a client.
+type subscriptionConn struct {
+	server *subscriptionServer
+	ws     *websocket.Conn
+
+	// ctx is cancelled when the connection is closed
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// send queues messages to be written by [writePump]
+	send chan *SubscriptionMessage
+
+	lock sync.Mutex
+	// topic -> active subscription
+	subscriptions   map[string]*activeSubscription
+	subscriptionsWG sync.WaitGroup
+}
+
+// readPump handles the requests of the client until the connection is closed.
+func (c *subscriptionConn) readPump() {
+	defer c.cancel()
+
+	c.ws.SetReadLimit(maxSubscriptionRequestSize)
+	if err := c.ws.SetReadDeadline(time.Now().Add(subscriptionPongWait)); err != nil {
+		return
+	}
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(subscriptionPongWait))
+	})
+
+	for {
+		var request SubscriptionRequest
+		if err := c.ws.ReadJSON(&request); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				c.server.log.Debug("unexpected close of subscription connection",
+					zap.String("chainName", c.server.chainName),
+					zap.Error(err),
+				)
+			}
+			return
+		}
+
+		var (
+			topic string
+			err   error
+		)
+		switch {
+		case request.Subscribe != nil && request.Unsubscribe == nil:
+			topic = request.Subscribe.Topic
+			err = c.subscribe(request.Subscribe)
+		case request.Unsubscribe != nil && request.Subscribe == nil:
+			topic = request.Unsubscribe.Topic
+			err = c.unsubscribe(topic)
+		default:
+			err = errInvalidSubscription
+		}
+		if err != nil {
+			c.sendError(c.ctx, topic, err)
+		}
+	}
+}
+
+// writePump writes queued messages and pings to the client until the
+// connection is closed.
+func (c *subscriptionConn) writePump() {
+	ticker := time.NewTicker(subscriptionPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg := <-c.send:
+			if err := c.ws.SetWriteDeadline(time.Now().Add(subscriptionWriteWait)); err != nil {
+				return
+			}
+			if err := c.ws.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.SetWriteDeadline(time.Now().Add(subscriptionWriteWait)); err != nil {
+				return
+			}
+			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.ctx.Done():
+			_ = c.ws.SetWriteDeadline(time.Now().Add(subscriptionWriteWait))
+			_ = c.ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+			return
+		}
+	}
+}
+
+func (c *subscriptionConn) subscribe(subscription *Subscription) error {
+	var stream func(context.Context, *Subscription) error
+	switch subscription.Topic {
+	case BlocksTopic:
+		stream = c.containerStream(subscription, c.server.blockIndex)
+	case TxsTopic:
+		if c.server.txIndex == nil {
+			return fmt.Errorf("%w: %s", errUnsupportedTopic, subscription.Topic)
+		}
+		stream = c.containerStream(subscription, c.server.txIndex)
+	case ValidatorsTopic:
+		if c.server.validators == nil {
+			return fmt.Errorf("%w: %s", errUnsupportedTopic, subscription.Topic)
+		}
+		stream = c.streamValidators
+	case HealthTopic:
+		if c.server.health == nil {
+			return fmt.Errorf("%w: %s", errUnsupportedTopic, subscription.Topic)
+		}
+		stream = c.streamHealth
+	default:
+		return fmt.Errorf("%w: %q", errUnknownTopic, subscription.Topic)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+	if _, ok := c.subscriptions[subscription.Topic]; ok {
+		return fmt.Errorf("%w: %s", errAlreadySubscribed, subscription.Topic)
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	active := &activeSubscription{
+		cancel: cancel,
+	}
+	c.subscriptions[subscription.Topic] = active
+	c.subscriptionsWG.Add(1)
+	go func() {
+		defer c.subscriptionsWG.Done()
+
+		err := stream(ctx, subscription)
+
+		c.lock.Lock()
+		if c.subscriptions[subscription.Topic] == active {
+			delete(c.subscriptions, subscription.Topic)
+		}
+		c.lock.Unlock()
+
+		if err != nil && ctx.Err() == nil {
+			c.sendError(c.ctx, subscription.Topic, err)
+		}
+		cancel()
+	}()
+	return nil
+}
+
+func (c *subscriptionConn) unsubscribe(topic string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	active, ok := c.subscriptions[topic]
+	if !ok {
+		return fmt.Errorf("%w: %s", errNotSubscribed, topic)
+	}
+	active.cancel()
+	delete(c.subscriptions, topic)
+	return nil
+}
+
+// containerStream returns a stream of the containers of [index], starting
+// from [subscription.FromIndex]. If [subscription.FromIndex] is nil, the stream
+// starts from the next container to be accepted at the time of subscribing.
+func (c *subscriptionConn) containerStream(subscription *Subscription, index *index) func(context.Context, *Subscription) error {
+	next, _ := index.NextAccepted()
+	if subscription.FromIndex != nil {
+		next = uint64(*subscription.FromIndex)
+	}
+	return func(ctx context.Context, subscription *Subscription) error {
+		return c.streamContainers(ctx, subscription, index, next)
+	}
+}
+
+// streamContainers sends the containers of [index] in the order they were
+// accepted, starting from [next], until [ctx] is cancelled.
+func (c *subscriptionConn) streamContainers(ctx context.Context, subscription *Subscription, index *index, next uint64) error {
+	for {
+		nextAccepted, accepted := index.NextAccepted()
+		for next < nextAccepted {
+			containers, err := index.GetContainerRange(next, MaxFetchedByRange)
+			if err != nil {
+				return err
+			}
+			for _, container := range containers {
+				formatted, err := newFormattedContainer(container, next, subscription.Encoding)
+				if err != nil {
+					return err
+				}
+				if !c.sendMessage(ctx, &SubscriptionMessage{
+					Topic:     subscription.Topic,
+					Container: &formatted,
+				}) {
+					return nil
+				}
+				next++
+			}
+		}
+
+		select {
+		case <-accepted:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// streamValidators sends a snapshot of the current validators followed by
+// every change until [ctx] is cancelled.
+func (c *subscriptionConn) streamValidators(ctx context.Context, subscription *Subscription) error {
+	snapshot, subscriber := c.server.validators.subscribe(set.Of(subscription.SubnetIDs...))
+	defer c.server.validators.unsubscribe(subscriber)
+
+	if !c.sendMessage(ctx, &SubscriptionMessage{
+		Topic:      subscription.Topic,
+		Validators: snapshot,
+		Snapshot:   true,
+	}) {
+		return nil
+	}
+
+	for {
+		select {
+		case change, ok := <-subscriber.changes:
+			if !ok {
+				return errFellBehind
+			}
+			if !c.sendMessage(ctx, &SubscriptionMessage{
+				Topic:      subscription.Topic,
+				Validators: []ValidatorChange{change},
+			}) {
+				return nil
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// streamHealth sends the current health of the chain followed by every
+// transition until [ctx] is cancelled.
+func (c *subscriptionConn) streamHealth(ctx context.Context, subscription *Subscription) error {
+	subscriber := c.server.health.subscribe()
+	defer c.server.health.unsubscribe(subscriber)
+
+	for {
+		select {
+		case transition, ok := <-subscriber.transitions:
+			if !ok {
+				return errFellBehind
+			}
+			if !c.sendMessage(ctx, &SubscriptionMessage{
+				Topic:  subscription.Topic,
+				Health: &transition,
+			}) {
+				return nil
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// sendMessage queues [msg] to be written to the client. Returns false if
+// [ctx] was cancelled first.
+func (c *subscriptionConn) sendMessage(ctx context.Context, msg *SubscriptionMessage) bool {
+	select {
+	case c.send <- msg:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (c *subscriptionConn) sendError(ctx context.Context, topic string, err error) {
+	c.sendMessage(ctx, &SubscriptionMessage{
+		Topic: topic,
+		Error: err.Error(),
+	})
+}
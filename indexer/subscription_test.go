@@ -0,0 +1,479 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/snowtest"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+const testChainName = "X"
+
+var errTest = errors.New("non-nil error")
+
+// testHealthReporter reports the health of [testChainName].
+type testHealthReporter struct {
+	lock   sync.Mutex
+	result health.Result
+}
+
+func (r *testHealthReporter) set(err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.result = health.Result{
+		Timestamp: time.Now(),
+	}
+	if err != nil {
+		errStr := err.Error()
+		r.result.Error = &errStr
+	}
+}
+
+func (*testHealthReporter) Readiness(...string) (map[string]health.Result, bool) {
+	return nil, true
+}
+
+func (r *testHealthReporter) Health(...string) (map[string]health.Result, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return map[string]health.Result{
+		testChainName: r.result,
+	}, r.result.Error == nil
+}
+
+func (*testHealthReporter) Liveness(...string) (map[string]health.Result, bool) {
+	return nil, true
+}
+
+func newTestSubscriptionServer(
+	t *testing.T,
+	blockIndex *index,
+	txIndex *index,
+	validators *validatorFeed,
+	health health.Reporter,
+) string {
+	s := newSubscriptionServer(logging.NoLog{}, testChainName, nil, blockIndex, txIndex, validators, health)
+	if s.health != nil {
+		s.health.pollFrequency = 10 * time.Millisecond
+	}
+	httpServer := httptest.NewServer(s)
+	t.Cleanup(func() {
+		s.close()
+		httpServer.Close()
+	})
+	return "ws" + strings.TrimPrefix(httpServer.URL, "http")
+}
+
+func dialSubscriptionServer(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+func readSubscriptionMessage(t *testing.T, conn *websocket.Conn) SubscriptionMessage {
+	require := require.New(t)
+
+	require.NoError(conn.SetReadDeadline(time.Now().Add(5 * time.Second)))
+	var msg SubscriptionMessage
+	require.NoError(conn.ReadJSON(&msg))
+	return msg
+}
+
+func newTestIndex(t *testing.T) *index {
+	idx, err := newIndex(memdb.New(), logging.NoLog{}, mockable.Clock{})
+	require.NoError(t, err)
+	return idx
+}
+
+func TestSubscriptionContainers(t *testing.T) {
+	require := require.New(t)
+
+	snowCtx := snowtest.Context(t, snowtest.XChainID)
+	ctx := snowtest.ConsensusContext(snowCtx)
+	blockIndex := newTestIndex(t)
+
+	var blkIDs []ids.ID
+	accept := func() {
+		blkID := ids.GenerateTestID()
+		blkIDs = append(blkIDs, blkID)
+		require.NoError(blockIndex.Accept(ctx, blkID, utils.RandomBytes(32)))
+	}
+	for i := 0; i < 3; i++ {
+		accept()
+	}
+
+	url := newTestSubscriptionServer(t, blockIndex, nil, nil, nil)
+
+	// Resume from a cursor
+	resumed := dialSubscriptionServer(t, url)
+	fromIndex := json.Uint64(1)
+	require.NoError(resumed.WriteJSON(SubscriptionRequest{
+		Subscribe: &Subscription{
+			Topic:     BlocksTopic,
+			FromIndex: &fromIndex,
+			Encoding:  formatting.Hex,
+		},
+	}))
+	for i := 1; i < 3; i++ {
+		msg := readSubscriptionMessage(t, resumed)
+		require.Equal(BlocksTopic, msg.Topic)
+		require.Empty(msg.Error)
+		require.Equal(blkIDs[i], msg.Container.ID)
+		require.Equal(json.Uint64(i), msg.Container.Index)
+		require.Equal(formatting.Hex, msg.Container.Encoding)
+	}
+
+	// Only receive newly accepted containers
+	live := dialSubscriptionServer(t, url)
+	require.NoError(live.WriteJSON(SubscriptionRequest{
+		Subscribe: &Subscription{
+			Topic: BlocksTopic,
+		},
+	}))
+	// Make sure the subscription was started before accepting a container.
+	require.NoError(live.WriteJSON(SubscriptionRequest{
+		Subscribe: &Subscription{
+			Topic: BlocksTopic,
+		},
+	}))
+	msg := readSubscriptionMessage(t, live)
+	require.Contains(msg.Error, errAlreadySubscribed.Error())
+
+	accept()
+	for _, conn := range []*websocket.Conn{resumed, live} {
+		msg := readSubscriptionMessage(t, conn)
+		require.Equal(blkIDs[3], msg.Container.ID)
+		require.Equal(json.Uint64(3), msg.Container.Index)
+	}
+
+	// After unsubscribing, the topic can be subscribed to again.
+	require.NoError(live.WriteJSON(SubscriptionRequest{
+		Unsubscribe: &Subscription{
+			Topic: BlocksTopic,
+		},
+	}))
+	require.NoError(live.WriteJSON(SubscriptionRequest{
+		Subscribe: &Subscription{
+			Topic:     BlocksTopic,
+			FromIndex: &fromIndex,
+		},
+	}))
+	msg = readSubscriptionMessage(t, live)
+	require.Empty(msg.Error)
+	require.Equal(blkIDs[1], msg.Container.ID)
+}
+
+func TestSubscriptionErrors(t *testing.T) {
+	url := newTestSubscriptionServer(t, newTestIndex(t), nil, nil, nil)
+
+	tests := []struct {
+		name        string
+		request     SubscriptionRequest
+		expectedErr error
+	}{
+		{
+			name:        "empty request",
+			expectedErr: errInvalidSubscription,
+		},
+		{
+			name: "unknown topic",
+			request: SubscriptionRequest{
+				Subscribe: &Subscription{
+					Topic: "unknown",
+				},
+			},
+			expectedErr: errUnknownTopic,
+		},
+		{
+			name: "txs not supported",
+			request: SubscriptionRequest{
+				Subscribe: &Subscription{
+					Topic: TxsTopic,
+				},
+			},
+			expectedErr: errUnsupportedTopic,
+		},
+		{
+			name: "validators not supported",
+			request: SubscriptionRequest{
+				Subscribe: &Subscription{
+					Topic: ValidatorsTopic,
+				},
+			},
+			expectedErr: errUnsupportedTopic,
+		},
+		{
+			name: "not subscribed",
+			request: SubscriptionRequest{
+				Unsubscribe: &Subscription{
+					Topic: BlocksTopic,
+				},
+			},
+			expectedErr: errNotSubscribed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			conn := dialSubscriptionServer(t, url)
+			require.NoError(conn.WriteJSON(test.request))
+			msg := readSubscriptionMessage(t, conn)
+			require.Contains(msg.Error, test.expectedErr.Error())
+		})
+	}
+}
+
+func TestSubscriptionValidators(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetID = ids.GenerateTestID()
+		nodeID0  = ids.GenerateTestNodeID()
+		nodeID1  = ids.GenerateTestNodeID()
+		feed     = newValidatorFeed()
+	)
+	feed.OnValidatorAdded(constants.PrimaryNetworkID, nodeID0, nil, ids.Empty, 10)
+	feed.OnValidatorAdded(subnetID, nodeID0, nil, ids.Empty, 20)
+
+	url := newTestSubscriptionServer(t, newTestIndex(t), nil, feed, nil)
+	conn := dialSubscriptionServer(t, url)
+	require.NoError(conn.WriteJSON(SubscriptionRequest{
+		Subscribe: &Subscription{
+			Topic:     ValidatorsTopic,
+			SubnetIDs: []ids.ID{subnetID},
+		},
+	}))
+
+	msg := readSubscriptionMessage(t, conn)
+	require.True(msg.Snapshot)
+	require.Equal([]ValidatorChange{
+		{
+			SubnetID: subnetID,
+			NodeID:   nodeID0,
+			Weight:   20,
+		},
+	}, msg.Validators)
+
+	// Changes of other subnets are filtered out.
+	feed.OnValidatorAdded(constants.PrimaryNetworkID, nodeID1, nil, ids.Empty, 30)
+	feed.OnValidatorAdded(subnetID, nodeID1, nil, ids.Empty, 40)
+	feed.OnValidatorWeightChanged(subnetID, nodeID1, 40, 50)
+	feed.OnValidatorRemoved(subnetID, nodeID0, 20)
+
+	expectedChanges := []ValidatorChange{
+		{
+			SubnetID: subnetID,
+			NodeID:   nodeID1,
+			Weight:   40,
+		},
+		{
+			SubnetID:       subnetID,
+			NodeID:         nodeID1,
+			PreviousWeight: 40,
+			Weight:         50,
+		},
+		{
+			SubnetID:       subnetID,
+			NodeID:         nodeID0,
+			PreviousWeight: 20,
+		},
+	}
+	for _, expectedChange := range expectedChanges {
+		msg := readSubscriptionMessage(t, conn)
+		require.False(msg.Snapshot)
+		require.Equal([]ValidatorChange{expectedChange}, msg.Validators)
+	}
+}
+
+func TestValidatorFeedDropsSlowSubscribers(t *testing.T) {
+	require := require.New(t)
+
+	feed := newValidatorFeed()
+	_, subscriber := feed.subscribe(nil)
+	for i := 0; i <= maxPendingValidatorChanges; i++ {
+		feed.OnValidatorAdded(constants.PrimaryNetworkID, ids.GenerateTestNodeID(), nil, ids.Empty, 1)
+	}
+	require.Empty(feed.subscribers)
+
+	for i := 0; i < maxPendingValidatorChanges; i++ {
+		_, ok := <-subscriber.changes
+		require.True(ok)
+	}
+	_, ok := <-subscriber.changes
+	require.False(ok)
+
+	// A new subscriber receives the full validator set.
+	snapshot, _ := feed.subscribe(set.Of(constants.PrimaryNetworkID))
+	require.Len(snapshot, maxPendingValidatorChanges+1)
+}
+
+func TestSubscriptionHealth(t *testing.T) {
+	require := require.New(t)
+
+	reporter := &testHealthReporter{}
+	reporter.set(nil)
+
+	url := newTestSubscriptionServer(t, newTestIndex(t), nil, nil, reporter)
+	conn := dialSubscriptionServer(t, url)
+	require.NoError(conn.WriteJSON(SubscriptionRequest{
+		Subscribe: &Subscription{
+			Topic: HealthTopic,
+		},
+	}))
+
+	msg := readSubscriptionMessage(t, conn)
+	require.True(msg.Health.Healthy)
+	require.Nil(msg.Health.Error)
+
+	reporter.set(errTest)
+	msg = readSubscriptionMessage(t, conn)
+	require.False(msg.Health.Healthy)
+	require.Equal(errTest.Error(), *msg.Health.Error)
+
+	reporter.set(nil)
+	msg = readSubscriptionMessage(t, conn)
+	require.True(msg.Health.Healthy)
+}
+
+func TestSubscriptionHealthSharedPoller(t *testing.T) {
+	require := require.New(t)
+
+	reporter := &testHealthReporter{}
+	reporter.set(nil)
+
+	feed := newHealthFeed(reporter, testChainName)
+	feed.pollFrequency = 10 * time.Millisecond
+
+	first := feed.subscribe()
+	transition := <-first.transitions
+	require.True(transition.Healthy)
+
+	// Later subscribers share the poller and immediately receive the current
+	// health.
+	second := feed.subscribe()
+	transition = <-second.transitions
+	require.True(transition.Healthy)
+
+	reporter.set(errTest)
+	for _, subscriber := range []*healthSubscriber{first, second} {
+		transition := <-subscriber.transitions
+		require.False(transition.Healthy)
+	}
+
+	// The poller is stopped once every subscriber is removed.
+	feed.unsubscribe(first)
+	require.NotNil(feed.stopPolling)
+	feed.unsubscribe(second)
+	require.Nil(feed.stopPolling)
+	require.Nil(feed.last)
+}
+
+func TestSubscriptionOrigin(t *testing.T) {
+	tests := []struct {
+		name           string
+		allowedOrigins []string
+		host           string
+		origin         string
+		allowed        bool
+	}{
+		{
+			name:    "no origin",
+			host:    "127.0.0.1:9650",
+			allowed: true,
+		},
+		{
+			name:           "wildcard",
+			allowedOrigins: []string{"*"},
+			host:           "127.0.0.1:9650",
+			origin:         "https://example.com",
+			allowed:        true,
+		},
+		{
+			name:           "allowed origin",
+			allowedOrigins: []string{"https://example.com"},
+			host:           "127.0.0.1:9650",
+			origin:         "https://EXAMPLE.com",
+			allowed:        true,
+		},
+		{
+			name:           "allowed origin pattern",
+			allowedOrigins: []string{"https://*.example.com"},
+			host:           "127.0.0.1:9650",
+			origin:         "https://app.example.com",
+			allowed:        true,
+		},
+		{
+			name:           "same origin",
+			allowedOrigins: []string{"https://example.com"},
+			host:           "127.0.0.1:9650",
+			origin:         "http://127.0.0.1:9650",
+			allowed:        true,
+		},
+		{
+			name:           "disallowed origin",
+			allowedOrigins: []string{"https://example.com"},
+			host:           "127.0.0.1:9650",
+			origin:         "https://attacker.com",
+		},
+		{
+			name:           "disallowed origin pattern",
+			allowedOrigins: []string{"https://*.example.com"},
+			host:           "127.0.0.1:9650",
+			origin:         "https://example.com.attacker.com",
+		},
+		{
+			name:   "no allowed origins",
+			host:   "127.0.0.1:9650",
+			origin: "https://example.com",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Host = test.host
+			if test.origin != "" {
+				r.Header.Set("Origin", test.origin)
+			}
+			require.Equal(t, test.allowed, newOriginChecker(test.allowedOrigins)(r))
+		})
+	}
+}
+
+func TestSubscriptionDisallowedOrigin(t *testing.T) {
+	require := require.New(t)
+
+	url := newTestSubscriptionServer(t, newTestIndex(t), nil, nil, nil)
+	header := http.Header{}
+	header.Set("Origin", "https://attacker.com")
+	_, response, err := websocket.DefaultDialer.Dial(url, header)
+	require.ErrorIs(err, websocket.ErrBadHandshake)
+	require.Equal(http.StatusForbidden, response.StatusCode)
+	require.NoError(response.Body.Close())
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+// maxPendingValidatorChanges is the number of changes that may be queued for
+// a subscriber before it is dropped.
+const maxPendingValidatorChanges = 1024
+
+var _ validators.ManagerCallbackListener = (*validatorFeed)(nil)
+
+// validatorFeed tracks the validator sets of every subnet and fans out their
+// changes to subscribers.
+//
+// Callbacks are invoked while the validator manager is locked, so subscribers
+// that don't keep up are dropped rather than blocking the callbacks. Dropped
+// subscribers can resubscribe to receive a new snapshot.
+type validatorFeed struct {
+	lock sync.Mutex
+	// subnetID -> nodeID -> weight
+	weights     map[ids.ID]map[ids.NodeID]uint64
+	subscribers set.Set[*validatorSubscriber]
+}
+
+type validatorSubscriber struct {
+	// subnetIDs to send changes of. If empty, changes of every subnet are
+	// sent.
+	subnetIDs set.Set[ids.ID]
+	// changes is closed if the subscriber is dropped
+	changes chan ValidatorChange
+}
+
+func newValidatorFeed() *validatorFeed {
+	return &validatorFeed{
+		weights: make(map[ids.ID]map[ids.NodeID]uint64),
+	}
+}
+
+func (f *validatorFeed) OnValidatorAdded(subnetID ids.ID, nodeID ids.NodeID, _ *bls.PublicKey, _ ids.ID, weight uint64) {
+	f.update(subnetID, nodeID, 0, weight)
+}
+
+func (f *validatorFeed) OnValidatorRemoved(subnetID ids.ID, nodeID ids.NodeID, weight uint64) {
+	f.update(subnetID, nodeID, weight, 0)
+}
+
+func (f *validatorFeed) OnValidatorWeightChanged(subnetID ids.ID, nodeID ids.NodeID, oldWeight, newWeight uint64) {
+	f.update(subnetID, nodeID, oldWeight, newWeight)
+}
+
+func (f *validatorFeed) update(subnetID ids.ID, nodeID ids.NodeID, oldWeight, newWeight uint64) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	subnetWeights, ok := f.weights[subnetID]
+	if !ok {
+		subnetWeights = make(map[ids.NodeID]uint64)
+		f.weights[subnetID] = subnetWeights
+	}
+	if newWeight == 0 {
+		delete(subnetWeights, nodeID)
+		if len(subnetWeights) == 0 {
+			delete(f.weights, subnetID)
+		}
+	} else {
+		subnetWeights[nodeID] = newWeight
+	}
+
+	change := ValidatorChange{
+		SubnetID:       subnetID,
+		NodeID:         nodeID,
+		PreviousWeight: json.Uint64(oldWeight),
+		Weight:         json.Uint64(newWeight),
+	}
+	for subscriber := range f.subscribers {
+		if subscriber.subnetIDs.Len() > 0 && !subscriber.subnetIDs.Contains(subnetID) {
+			continue
+		}
+		select {
+		case subscriber.changes <- change:
+		default:
+			close(subscriber.changes)
+			f.subscribers.Remove(subscriber)
+		}
+	}
+}
+
+// subscribe returns the current validators of [subnetIDs] and a subscriber
+// that receives every later change to them. If [subnetIDs] is empty, every
+// subnet is included.
+func (f *validatorFeed) subscribe(subnetIDs set.Set[ids.ID]) ([]ValidatorChange, *validatorSubscriber) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var snapshot []ValidatorChange
+	for subnetID, subnetWeights := range f.weights {
+		if subnetIDs.Len() > 0 && !subnetIDs.Contains(subnetID) {
+			continue
+		}
+		for nodeID, weight := range subnetWeights {
+			snapshot = append(snapshot, ValidatorChange{
+				SubnetID: subnetID,
+				NodeID:   nodeID,
+				Weight:   json.Uint64(weight),
+			})
+		}
+	}
+
+	subscriber := &validatorSubscriber{
+		subnetIDs: subnetIDs,
+		changes:   make(chan ValidatorChange, maxPendingValidatorChanges),
+	}
+	f.subscribers.Add(subscriber)
+	return snapshot, subscriber
+}
+
+func (f *validatorFeed) unsubscribe(subscriber *validatorSubscriber) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.subscribers.Remove(subscriber)
+}
@@ -4,6 +4,7 @@
 package message
 
 import (
+	"errors"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -12,7 +13,11 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
-var _ Creator = (*creator)(nil)
+var (
+	_ Creator = (*creator)(nil)
+
+	errMissingDictionary = errors.New("dictionary compression requires at least one dictionary")
+)
 
 type Creator interface {
 	OutboundMsgBuilder
@@ -28,11 +33,19 @@ func NewCreator(
 	log logging.Logger,
 	metrics prometheus.Registerer,
 	compressionType compression.Type,
+	dictionaries []*compression.Dictionary,
+	samples *compression.SampleWriter,
 	maxMessageTimeout time.Duration,
 ) (Creator, error) {
+	if compressionType == compression.TypeZstdDictionary && len(dictionaries) == 0 {
+		return nil, errMissingDictionary
+	}
+
 	builder, err := newMsgBuilder(
 		log,
 		metrics,
+		dictionaries,
+		samples,
 		maxMessageTimeout,
 	)
 	if err != nil {
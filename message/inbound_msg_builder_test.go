@@ -24,6 +24,8 @@ func Test_newMsgBuilder(t *testing.T) {
 	mb, err := newMsgBuilder(
 		logging.NoLog{},
 		prometheus.NewRegistry(),
+		nil,
+		nil,
 		10*time.Second,
 	)
 	require.NoError(err)
@@ -393,6 +395,8 @@ func TestAppError(t *testing.T) {
 	mb, err := newMsgBuilder(
 		logging.NoLog{},
 		prometheus.NewRegistry(),
+		nil,
+		nil,
 		time.Second,
 	)
 	require.NoError(err)
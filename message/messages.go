@@ -6,9 +6,11 @@ package message
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -35,6 +37,8 @@ var (
 	metricLabels = []string{typeLabel, opLabel, directionLabel}
 
 	errUnknownCompressionType = errors.New("message is compressed with an unknown compression type")
+	errUnknownDictionary      = errors.New("message is compressed with an unknown dictionary")
+	errDuplicateDictionary    = errors.New("duplicate dictionary")
 )
 
 // InboundMessage represents a set of fields for an inbound message
@@ -55,6 +59,9 @@ type InboundMessage interface {
 	// BytesSavedCompression returns the number of bytes that this message saved
 	// due to being compressed
 	BytesSavedCompression() int
+	// TraceContext returns the trace context of the span that sent this
+	// message, or nil if the sender didn't provide one
+	TraceContext() *p2p.TraceContext
 }
 
 type inboundMessage struct {
@@ -64,6 +71,7 @@ type inboundMessage struct {
 	expiration            time.Time
 	onFinishedHandling    func()
 	bytesSavedCompression int
+	traceContext          *p2p.TraceContext
 }
 
 func (m *inboundMessage) NodeID() ids.NodeID {
@@ -92,6 +100,10 @@ func (m *inboundMessage) BytesSavedCompression() int {
 	return m.bytesSavedCompression
 }
 
+func (m *inboundMessage) TraceContext() *p2p.TraceContext {
+	return m.traceContext
+}
+
 func (m *inboundMessage) String() string {
 	return fmt.Sprintf("%s Op: %s Message: %s",
 		m.nodeID, m.op, m.message)
@@ -110,6 +122,12 @@ type OutboundMessage interface {
 	// BytesSavedCompression returns the number of bytes that this message saved
 	// due to being compressed
 	BytesSavedCompression() int
+	// BytesFor returns the bytes that will be sent to a peer that negotiated
+	// the zstd dictionary [dictionaryID], where 0 means that no dictionary was
+	// negotiated. If the message was compressed with a different dictionary,
+	// the message compressed without a dictionary is returned. Otherwise, this
+	// returns the same bytes as Bytes.
+	BytesFor(dictionaryID uint32) []byte
 }
 
 type outboundMessage struct {
@@ -117,6 +135,14 @@ type outboundMessage struct {
 	op                    Op
 	bytes                 []byte
 	bytesSavedCompression int
+
+	// dictionaryID is the ID of the dictionary that [bytes] was compressed
+	// with, or 0 if a dictionary wasn't used.
+	dictionaryID uint32
+	// fallbackBytes is the message compressed without a dictionary, which is
+	// sent to peers that didn't negotiate [dictionaryID]. Only set if
+	// [dictionaryID] != 0.
+	fallbackBytes []byte
 }
 
 func (m *outboundMessage) BypassThrottling() bool {
@@ -135,13 +161,76 @@ func (m *outboundMessage) BytesSavedCompression() int {
 	return m.bytesSavedCompression
 }
 
+func (m *outboundMessage) BytesFor(dictionaryID uint32) []byte {
+	if m.dictionaryID == 0 || m.dictionaryID == dictionaryID {
+		return m.bytes
+	}
+	return m.fallbackBytes
+}
+
+var _ OutboundMessage = (*tracedOutboundMessage)(nil)
+
+// tracedOutboundMessage is an outbound message with a trace context.
+type tracedOutboundMessage struct {
+	OutboundMessage
+
+	bytes []byte
+	// traceContextBytes is the encoding of a p2p.Message that only sets the
+	// trace context.
+	traceContextBytes []byte
+}
+
+// WithTraceContext returns [msg] with [traceContext] added to it.
+//
+// Concatenated protobuf messages are decoded as if they were merged, so the
+// trace context is appended to the bytes of [msg] rather than rebuilding
+// [msg]. This allows a message that is sent to many peers to be built and
+// compressed once.
+func WithTraceContext(msg OutboundMessage, traceContext *p2p.TraceContext) (OutboundMessage, error) {
+	traceContextBytes, err := proto.Marshal(&p2p.Message{
+		TraceContext: traceContext,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tracedOutboundMessage{
+		OutboundMessage:   msg,
+		bytes:             withSuffix(msg.Bytes(), traceContextBytes),
+		traceContextBytes: traceContextBytes,
+	}, nil
+}
+
+func (m *tracedOutboundMessage) Bytes() []byte {
+	return m.bytes
+}
+
+func (m *tracedOutboundMessage) BytesFor(dictionaryID uint32) []byte {
+	return withSuffix(m.OutboundMessage.BytesFor(dictionaryID), m.traceContextBytes)
+}
+
+func withSuffix(b []byte, suffix []byte) []byte {
+	newB := make([]byte, len(b), len(b)+len(suffix))
+	copy(newB, b)
+	return append(newB, suffix...)
+}
+
 // TODO: add other compression algorithms with extended interface
 type msgBuilder struct {
 	log logging.Logger
 
 	zstdCompressor compression.Compressor
-	count          *prometheus.CounterVec // type + op + direction
-	duration       *prometheus.GaugeVec   // type + op + direction
+	// Dictionary ID --> compressor using the dictionary
+	zstdDictionaryCompressors map[uint32]compression.Compressor
+	// IDs of the supported dictionaries in increasing order
+	zstdDictionaryIDs []uint32
+	// Hashes of the supported dictionaries, advertised in the Handshake
+	zstdDictionaryHashes [][]byte
+	// If non-nil, uncompressed outbound messages that support compression are
+	// recorded to be used to train new dictionaries.
+	samples *compression.SampleWriter
+
+	count    *prometheus.CounterVec // type + op + direction
+	duration *prometheus.GaugeVec   // type + op + direction
 
 	maxMessageTimeout time.Duration
 }
@@ -149,6 +238,8 @@ type msgBuilder struct {
 func newMsgBuilder(
 	log logging.Logger,
 	metrics prometheus.Registerer,
+	dictionaries []*compression.Dictionary,
+	samples *compression.SampleWriter,
 	maxMessageTimeout time.Duration,
 ) (*msgBuilder, error) {
 	zstdCompressor, err := compression.NewZstdCompressor(constants.DefaultMaxMessageSize)
@@ -156,10 +247,33 @@ func newMsgBuilder(
 		return nil, err
 	}
 
+	zstdDictionaryCompressors := make(map[uint32]compression.Compressor, len(dictionaries))
+	var (
+		zstdDictionaryIDs    = make([]uint32, 0, len(dictionaries))
+		zstdDictionaryHashes = make([][]byte, 0, len(dictionaries))
+	)
+	for _, dictionary := range dictionaries {
+		if _, ok := zstdDictionaryCompressors[dictionary.ID]; ok {
+			return nil, fmt.Errorf("%w: %d", errDuplicateDictionary, dictionary.ID)
+		}
+		compressor, err := compression.NewZstdDictionaryCompressor(constants.DefaultMaxMessageSize, dictionary)
+		if err != nil {
+			return nil, err
+		}
+		zstdDictionaryCompressors[dictionary.ID] = compressor
+		zstdDictionaryIDs = append(zstdDictionaryIDs, dictionary.ID)
+		zstdDictionaryHashes = append(zstdDictionaryHashes, dictionary.Hash[:])
+	}
+	slices.Sort(zstdDictionaryIDs)
+
 	mb := &msgBuilder{
 		log: log,
 
-		zstdCompressor: zstdCompressor,
+		zstdCompressor:            zstdCompressor,
+		zstdDictionaryCompressors: zstdDictionaryCompressors,
+		zstdDictionaryIDs:         zstdDictionaryIDs,
+		zstdDictionaryHashes:      zstdDictionaryHashes,
+		samples:                   samples,
 		count: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "codec_compressed_count",
@@ -183,20 +297,35 @@ func newMsgBuilder(
 	)
 }
 
-func (mb *msgBuilder) marshal(
-	uncompressedMsg *p2p.Message,
-	compressionType compression.Type,
-) ([]byte, int, Op, error) {
+// preferredDictionaryID returns the ID of the dictionary used to compress
+// outbound messages, or 0 if there are no dictionaries.
+func (mb *msgBuilder) preferredDictionaryID() uint32 {
+	if len(mb.zstdDictionaryIDs) == 0 {
+		return 0
+	}
+	return mb.zstdDictionaryIDs[len(mb.zstdDictionaryIDs)-1]
+}
+
+func (mb *msgBuilder) marshal(uncompressedMsg *p2p.Message) ([]byte, Op, error) {
 	uncompressedMsgBytes, err := proto.Marshal(uncompressedMsg)
 	if err != nil {
-		return nil, 0, 0, err
+		return nil, 0, err
 	}
 
 	op, err := ToOp(uncompressedMsg)
-	if err != nil {
-		return nil, 0, 0, err
-	}
+	return uncompressedMsgBytes, op, err
+}
 
+// compress returns [uncompressedMsgBytes] compressed with [compressionType]
+// and the number of bytes saved by compressing the message. If
+// [compressionType] is [compression.TypeZstdDictionary], [dictionaryID]
+// specifies the dictionary to use.
+func (mb *msgBuilder) compress(
+	uncompressedMsgBytes []byte,
+	op Op,
+	compressionType compression.Type,
+	dictionaryID uint32,
+) ([]byte, int, error) {
 	// If compression is enabled, we marshal twice:
 	// 1. the original message
 	// 2. the message with compressed bytes
@@ -209,24 +338,38 @@ func (mb *msgBuilder) marshal(
 	)
 	switch compressionType {
 	case compression.TypeNone:
-		return uncompressedMsgBytes, 0, op, nil
+		return uncompressedMsgBytes, 0, nil
 	case compression.TypeZstd:
 		compressedBytes, err := mb.zstdCompressor.Compress(uncompressedMsgBytes)
 		if err != nil {
-			return nil, 0, 0, err
+			return nil, 0, err
 		}
 		compressedMsg = p2p.Message{
 			Message: &p2p.Message_CompressedZstd{
 				CompressedZstd: compressedBytes,
 			},
 		}
+	case compression.TypeZstdDictionary:
+		compressor, ok := mb.zstdDictionaryCompressors[dictionaryID]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: %d", errUnknownDictionary, dictionaryID)
+		}
+		compressedBytes, err := compressor.Compress(uncompressedMsgBytes)
+		if err != nil {
+			return nil, 0, err
+		}
+		compressedMsg = p2p.Message{
+			Message: &p2p.Message_CompressedZstdDictionary{
+				CompressedZstdDictionary: compressedBytes,
+			},
+		}
 	default:
-		return nil, 0, 0, errUnknownCompressionType
+		return nil, 0, errUnknownCompressionType
 	}
 
 	compressedMsgBytes, err := proto.Marshal(&compressedMsg)
 	if err != nil {
-		return nil, 0, 0, err
+		return nil, 0, err
 	}
 	compressTook := time.Since(startTime)
 
@@ -239,7 +382,7 @@ func (mb *msgBuilder) marshal(
 	mb.duration.With(labels).Add(float64(compressTook))
 
 	bytesSaved := len(uncompressedMsgBytes) - len(compressedMsgBytes)
-	return compressedMsgBytes, bytesSaved, op, nil
+	return compressedMsgBytes, bytesSaved, nil
 }
 
 func (mb *msgBuilder) unmarshal(b []byte) (*p2p.Message, int, Op, error) {
@@ -247,17 +390,35 @@ func (mb *msgBuilder) unmarshal(b []byte) (*p2p.Message, int, Op, error) {
 	if err := proto.Unmarshal(b, m); err != nil {
 		return nil, 0, 0, err
 	}
+	// The trace context isn't compressed, so it must be restored after the
+	// message is decompressed.
+	traceContext := m.TraceContext
 
 	// Figure out what compression type, if any, was used to compress the message.
 	var (
-		compressor      compression.Compressor
-		compressedBytes []byte
-		zstdCompressed  = m.GetCompressedZstd()
+		compressionType          compression.Type
+		compressor               compression.Compressor
+		compressedBytes          []byte
+		zstdCompressed           = m.GetCompressedZstd()
+		zstdDictionaryCompressed = m.GetCompressedZstdDictionary()
 	)
 	switch {
 	case len(zstdCompressed) > 0:
+		compressionType = compression.TypeZstd
 		compressor = mb.zstdCompressor
 		compressedBytes = zstdCompressed
+	case len(zstdDictionaryCompressed) > 0:
+		dictionaryID, err := compression.FrameDictionaryID(zstdDictionaryCompressed)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		var ok bool
+		compressor, ok = mb.zstdDictionaryCompressors[dictionaryID]
+		if !ok {
+			return nil, 0, 0, fmt.Errorf("%w: %d", errUnknownDictionary, dictionaryID)
+		}
+		compressionType = compression.TypeZstdDictionary
+		compressedBytes = zstdDictionaryCompressed
 	default:
 		// The message wasn't compressed
 		op, err := ToOp(m)
@@ -276,6 +437,7 @@ func (mb *msgBuilder) unmarshal(b []byte) (*p2p.Message, int, Op, error) {
 		return nil, 0, 0, err
 	}
 	decompressTook := time.Since(startTime)
+	m.TraceContext = traceContext
 
 	// Record decompression time metric
 	op, err := ToOp(m)
@@ -284,7 +446,7 @@ func (mb *msgBuilder) unmarshal(b []byte) (*p2p.Message, int, Op, error) {
 	}
 
 	labels := prometheus.Labels{
-		typeLabel:      compression.TypeZstd.String(),
+		typeLabel:      compressionType.String(),
 		opLabel:        op.String(),
 		directionLabel: decompressionLabel,
 	}
@@ -295,16 +457,68 @@ func (mb *msgBuilder) unmarshal(b []byte) (*p2p.Message, int, Op, error) {
 }
 
 func (mb *msgBuilder) createOutbound(m *p2p.Message, compressionType compression.Type, bypassThrottling bool) (*outboundMessage, error) {
-	b, saved, op, err := mb.marshal(m, compressionType)
+	uncompressedMsgBytes, op, err := mb.marshal(m)
 	if err != nil {
 		return nil, err
 	}
 
+	if mb.samples != nil && compressionType != compression.TypeNone {
+		mb.samples.Write(uncompressedMsgBytes)
+	}
+
+	if compressionType == compression.TypeZstdDictionary {
+		return mb.createDictionaryOutbound(uncompressedMsgBytes, op, bypassThrottling)
+	}
+
+	b, saved, err := mb.compress(uncompressedMsgBytes, op, compressionType, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &outboundMessage{
+		bypassThrottling:      bypassThrottling,
+		op:                    op,
+		bytes:                 b,
+		bytesSavedCompression: saved,
+	}, nil
+}
+
+// createDictionaryOutbound compresses [uncompressedMsgBytes] with the
+// preferred dictionary and without a dictionary, so that the message can be
+// sent to peers that didn't negotiate the preferred dictionary without
+// recompressing it. If compressing with the dictionary fails, the message is
+// only compressed without a dictionary.
+func (mb *msgBuilder) createDictionaryOutbound(
+	uncompressedMsgBytes []byte,
+	op Op,
+	bypassThrottling bool,
+) (*outboundMessage, error) {
+	fallbackBytes, fallbackSaved, err := mb.compress(uncompressedMsgBytes, op, compression.TypeZstd, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	dictionaryID := mb.preferredDictionaryID()
+	b, saved, err := mb.compress(uncompressedMsgBytes, op, compression.TypeZstdDictionary, dictionaryID)
+	if err != nil {
+		mb.log.Debug("failed to compress message with dictionary",
+			zap.Stringer("op", op),
+			zap.Uint32("dictionaryID", dictionaryID),
+			zap.Error(err),
+		)
+		return &outboundMessage{
+			bypassThrottling:      bypassThrottling,
+			op:                    op,
+			bytes:                 fallbackBytes,
+			bytesSavedCompression: fallbackSaved,
+		}, nil
+	}
 	return &outboundMessage{
 		bypassThrottling:      bypassThrottling,
 		op:                    op,
 		bytes:                 b,
 		bytesSavedCompression: saved,
+		dictionaryID:          dictionaryID,
+		fallbackBytes:         fallbackBytes,
 	}, nil
 }
 
@@ -336,5 +550,6 @@ func (mb *msgBuilder) parseInbound(
 		expiration:            expiration,
 		onFinishedHandling:    onFinishedHandling,
 		bytesSavedCompression: bytesSavedCompression,
+		traceContext:          m.TraceContext,
 	}, nil
 }
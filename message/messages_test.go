@@ -18,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
 )
 
 func TestMessage(t *testing.T) {
@@ -26,6 +27,8 @@ func TestMessage(t *testing.T) {
 	mb, err := newMsgBuilder(
 		logging.NoLog{},
 		prometheus.NewRegistry(),
+		nil,
+		nil,
 		5*time.Second,
 	)
 	require.NoError(t, err)
@@ -668,6 +671,8 @@ func TestInboundMessageToString(t *testing.T) {
 	mb, err := newMsgBuilder(
 		logging.NoLog{},
 		prometheus.NewRegistry(),
+		nil,
+		nil,
 		5*time.Second,
 	)
 	require.NoError(err)
@@ -698,6 +703,8 @@ func TestEmptyInboundMessage(t *testing.T) {
 	mb, err := newMsgBuilder(
 		logging.NoLog{},
 		prometheus.NewRegistry(),
+		nil,
+		nil,
 		5*time.Second,
 	)
 	require.NoError(err)
@@ -718,6 +725,8 @@ func TestNilInboundMessage(t *testing.T) {
 	mb, err := newMsgBuilder(
 		logging.NoLog{},
 		prometheus.NewRegistry(),
+		nil,
+		nil,
 		5*time.Second,
 	)
 	require.NoError(err)
@@ -737,3 +746,262 @@ func TestNilInboundMessage(t *testing.T) {
 	pingMsg := parsedMsg.message.(*p2p.Ping)
 	require.NotNil(pingMsg)
 }
+
+func newTestDictionary(t *testing.T, id uint32) *compression.Dictionary {
+	samples := make([][]byte, 1024)
+	for i := range samples {
+		chainID := ids.GenerateTestID()
+		sample, err := proto.Marshal(&p2p.Message{
+			Message: &p2p.Message_AppGossip{
+				AppGossip: &p2p.AppGossip{
+					ChainId:  chainID[:],
+					AppBytes: bytes.Repeat([]byte{byte(i)}, i%64),
+				},
+			},
+		})
+		require.NoError(t, err)
+		samples[i] = sample
+	}
+	dictionary, err := compression.TrainZstdDictionary(id, samples, 4*units.KiB)
+	require.NoError(t, err)
+	return dictionary
+}
+
+func TestDictionaryCompression(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	dictionary1 := newTestDictionary(t, 1)
+	dictionary2 := newTestDictionary(t, 2)
+
+	mb, err := newMsgBuilder(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		[]*compression.Dictionary{dictionary2, dictionary1},
+		nil,
+		5*time.Second,
+	)
+	require.NoError(err)
+	require.Equal([]uint32{1, 2}, mb.zstdDictionaryIDs)
+
+	// mb1 only supports the older dictionary.
+	mb1, err := newMsgBuilder(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		[]*compression.Dictionary{dictionary1},
+		nil,
+		5*time.Second,
+	)
+	require.NoError(err)
+
+	chainID := ids.GenerateTestID()
+	msg := &p2p.Message{
+		Message: &p2p.Message_AppGossip{
+			AppGossip: &p2p.AppGossip{
+				ChainId:  chainID[:],
+				AppBytes: bytes.Repeat([]byte{1}, 32),
+			},
+		},
+	}
+	outboundMsg, err := mb.createOutbound(msg, compression.TypeZstdDictionary, false)
+	require.NoError(err)
+
+	// The newest dictionary is used by default.
+	msgBytes := outboundMsg.BytesFor(2)
+	require.Equal(outboundMsg.Bytes(), msgBytes)
+	parsedMsg, err := mb.parseInbound(msgBytes, ids.EmptyNodeID, func() {})
+	require.NoError(err)
+	require.Equal(AppGossipOp, parsedMsg.Op())
+
+	_, err = mb1.parseInbound(outboundMsg.Bytes(), ids.EmptyNodeID, func() {})
+	require.ErrorIs(err, errUnknownDictionary)
+
+	// Peers that negotiated another dictionary, or didn't negotiate a
+	// dictionary, should receive the message compressed without a dictionary.
+	for _, dictionaryID := range []uint32{0, 1, 3} {
+		msgBytes := outboundMsg.BytesFor(dictionaryID)
+		m := new(p2p.Message)
+		require.NoError(proto.Unmarshal(msgBytes, m))
+		require.NotEmpty(m.GetCompressedZstd())
+
+		parsedMsg, err := mb1.parseInbound(msgBytes, ids.EmptyNodeID, func() {})
+		require.NoError(err)
+		require.Equal(AppGossipOp, parsedMsg.Op())
+	}
+}
+
+func TestDictionaryCompressionFallback(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	dictionary := newTestDictionary(t, 1)
+	mb, err := newMsgBuilder(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		[]*compression.Dictionary{dictionary},
+		nil,
+		5*time.Second,
+	)
+	require.NoError(err)
+
+	// Compressing with a dictionary that isn't supported fails, so the
+	// message should fall back to being compressed without a dictionary.
+	mb.zstdDictionaryIDs = []uint32{2}
+
+	chainID := ids.GenerateTestID()
+	msg := &p2p.Message{
+		Message: &p2p.Message_AppGossip{
+			AppGossip: &p2p.AppGossip{
+				ChainId:  chainID[:],
+				AppBytes: bytes.Repeat([]byte{1}, 32),
+			},
+		},
+	}
+	outboundMsg, err := mb.createOutbound(msg, compression.TypeZstdDictionary, false)
+	require.NoError(err)
+
+	for _, dictionaryID := range []uint32{0, 1, 2} {
+		msgBytes := outboundMsg.BytesFor(dictionaryID)
+		require.Equal(outboundMsg.Bytes(), msgBytes)
+
+		m := new(p2p.Message)
+		require.NoError(proto.Unmarshal(msgBytes, m))
+		require.NotEmpty(m.GetCompressedZstd())
+	}
+}
+
+func TestDuplicateDictionary(t *testing.T) {
+	t.Parallel()
+
+	dictionary := newTestDictionary(t, 1)
+	_, err := newMsgBuilder(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		[]*compression.Dictionary{dictionary, dictionary},
+		nil,
+		5*time.Second,
+	)
+	require.ErrorIs(t, err, errDuplicateDictionary)
+}
+
+func TestDictionaryCompressionRequiresDictionary(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		compression.TypeZstdDictionary,
+		nil,
+		nil,
+		5*time.Second,
+	)
+	require.ErrorIs(t, err, errMissingDictionary)
+}
+
+func TestRecordSamples(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	var (
+		buffer  bytes.Buffer
+		samples = compression.NewSampleWriter(&buffer, units.MiB)
+	)
+	mb, err := newMsgBuilder(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		nil,
+		samples,
+		5*time.Second,
+	)
+	require.NoError(err)
+
+	chainID := ids.GenerateTestID()
+	msg := &p2p.Message{
+		Message: &p2p.Message_AppGossip{
+			AppGossip: &p2p.AppGossip{
+				ChainId: chainID[:],
+			},
+		},
+	}
+	_, err = mb.createOutbound(msg, compression.TypeZstd, false)
+	require.NoError(err)
+
+	// Messages that aren't compressed aren't recorded.
+	_, err = mb.createOutbound(msg, compression.TypeNone, false)
+	require.NoError(err)
+
+	require.NoError(samples.Close())
+	recorded, err := compression.ReadSamples(&buffer, units.MiB)
+	require.NoError(err)
+	require.Len(recorded, 1)
+
+	parsedMsg := new(p2p.Message)
+	require.NoError(proto.Unmarshal(recorded[0], parsedMsg))
+	require.True(proto.Equal(msg, parsedMsg))
+}
+
+func TestWithTraceContext(t *testing.T) {
+	t.Parallel()
+
+	dictionary := newTestDictionary(t, 1)
+	mb, err := newMsgBuilder(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		[]*compression.Dictionary{dictionary},
+		nil,
+		5*time.Second,
+	)
+	require.NoError(t, err)
+
+	traceContext := &p2p.TraceContext{
+		Traceparent: "00-01000000000000000000000000000000-0200000000000000-01",
+		Tracestate:  "vendor=value",
+	}
+	chainID := ids.GenerateTestID()
+	for _, compressionType := range []compression.Type{
+		compression.TypeNone,
+		compression.TypeZstd,
+		compression.TypeZstdDictionary,
+	} {
+		t.Run(compressionType.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			msg := &p2p.Message{
+				Message: &p2p.Message_AppGossip{
+					AppGossip: &p2p.AppGossip{
+						ChainId:  chainID[:],
+						AppBytes: bytes.Repeat([]byte{1}, 32),
+					},
+				},
+			}
+			outboundMsg, err := mb.createOutbound(msg, compressionType, false)
+			require.NoError(err)
+
+			parsedMsg, err := mb.parseInbound(outboundMsg.Bytes(), ids.EmptyNodeID, func() {})
+			require.NoError(err)
+			require.Nil(parsedMsg.TraceContext())
+
+			tracedMsg, err := WithTraceContext(outboundMsg, traceContext)
+			require.NoError(err)
+			require.Equal(AppGossipOp, tracedMsg.Op())
+
+			for _, dictionaryID := range []uint32{0, 1} {
+				msgBytes := tracedMsg.BytesFor(dictionaryID)
+
+				parsedMsg, err := mb.parseInbound(msgBytes, ids.EmptyNodeID, func() {})
+				require.NoError(err)
+				require.Equal(AppGossipOp, parsedMsg.Op())
+				require.True(proto.Equal(traceContext, parsedMsg.TraceContext()))
+				require.True(proto.Equal(msg.GetAppGossip(), parsedMsg.Message().(*p2p.AppGossip)))
+			}
+
+			// The original message is not modified.
+			parsedMsg, err = mb.parseInbound(outboundMsg.Bytes(), ids.EmptyNodeID, func() {})
+			require.NoError(err)
+			require.Nil(parsedMsg.TraceContext())
+		})
+	}
+}
@@ -66,6 +66,20 @@ func (mr *MockOutboundMessageMockRecorder) Bytes() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bytes", reflect.TypeOf((*MockOutboundMessage)(nil).Bytes))
 }
 
+// BytesFor mocks base method.
+func (m *MockOutboundMessage) BytesFor(arg0 uint32) []byte {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BytesFor", arg0)
+	ret0, _ := ret[0].([]byte)
+	return ret0
+}
+
+// BytesFor indicates an expected call of BytesFor.
+func (mr *MockOutboundMessageMockRecorder) BytesFor(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BytesFor", reflect.TypeOf((*MockOutboundMessage)(nil).BytesFor), arg0)
+}
+
 // BytesSavedCompression mocks base method.
 func (m *MockOutboundMessage) BytesSavedCompression() int {
 	m.ctrl.T.Helper()
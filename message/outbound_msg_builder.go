@@ -270,7 +270,8 @@ func (b *outMsgBuilder) Handshake(
 						Filter: knownPeersFilter,
 						Salt:   knownPeersSalt,
 					},
-					IpBlsSig: ipBLSSig,
+					IpBlsSig:                      ipBLSSig,
+					SupportedZstdDictionaryHashes: b.builder.zstdDictionaryHashes,
 				},
 			},
 		},
@@ -21,6 +21,8 @@ func Test_newOutboundBuilder(t *testing.T) {
 	mb, err := newMsgBuilder(
 		logging.NoLog{},
 		prometheus.NewRegistry(),
+		nil,
+		nil,
 		10*time.Second,
 	)
 	require.NoError(t, err)
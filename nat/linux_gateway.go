@@ -0,0 +1,21 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build linux
+// +build linux
+
+package nat
+
+import (
+	"net/netip"
+	"os"
+)
+
+// discoverIPv6Gateway returns the next hop of the IPv6 default route.
+func discoverIPv6Gateway() (netip.Addr, error) {
+	routes, err := os.ReadFile("/proc/net/ipv6_route")
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return parseIPv6Route(routes)
+}
@@ -37,6 +37,9 @@ func GetRouter() Router {
 	if r := getUPnPRouter(); r != nil {
 		return r
 	}
+	if r := getPCPRouter(); r != nil {
+		return r
+	}
 	if r := getPMPRouter(); r != nil {
 		return r
 	}
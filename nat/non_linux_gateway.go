@@ -0,0 +1,14 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !linux
+// +build !linux
+
+package nat
+
+import "net/netip"
+
+// discoverIPv6Gateway is only supported on Linux.
+func discoverIPv6Gateway() (netip.Addr, error) {
+	return netip.Addr{}, errNoIPv6Gateway
+}
@@ -0,0 +1,451 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nat
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackpal/gateway"
+)
+
+// PCP is specified in RFC 6887: https://www.rfc-editor.org/rfc/rfc6887
+const (
+	pcpPort    = 5351
+	pcpVersion = 2
+
+	pcpOpAnnounce = 0
+	pcpOpMap      = 1
+	pcpOpResponse = 0x80
+
+	pcpHeaderLen   = 24
+	pcpMapLen      = 36
+	pcpNonceLen    = 12
+	pcpMaxMsgLen   = 1100
+	pcpProtocolTCP = 6
+
+	pcpResultSuccess = 0
+
+	pcpClientTimeout = 500 * time.Millisecond
+	pcpMaxAttempts   = 3
+
+	// pcpProbePort is the internal port of the zero-lifetime MAP request
+	// that is sent to discover the external address when no other port is
+	// mapped. It is the port of the discard service, which is never mapped.
+	pcpProbePort = 9
+
+	// Fields of /proc/net/ipv6_route
+	ipv6RouteDestination  = 0
+	ipv6RoutePrefixLen    = 1
+	ipv6RouteNextHop      = 4
+	ipv6RouteMetric       = 5
+	ipv6RouteFlags        = 8
+	ipv6RouteDevice       = 9
+	ipv6RouteNumFields    = 10
+	ipv6RouteFlagsGateway = 0x3 // RTF_UP | RTF_GATEWAY
+)
+
+var (
+	_ Router = (*pcpRouter)(nil)
+
+	errPCPInvalidResponse = errors.New("invalid PCP response")
+	errPCPResult          = errors.New("PCP request failed")
+	errNoIPv6Gateway      = errors.New("no IPv6 default gateway")
+
+	pcpResultNames = map[byte]string{
+		1:  "UNSUPP_VERSION",
+		2:  "NOT_AUTHORIZED",
+		3:  "MALFORMED_REQUEST",
+		4:  "UNSUPP_OPCODE",
+		5:  "UNSUPP_OPTION",
+		6:  "MALFORMED_OPTION",
+		7:  "NETWORK_FAILURE",
+		8:  "NO_RESOURCES",
+		9:  "UNSUPP_PROTOCOL",
+		10: "USER_EX_QUOTA",
+		11: "CANNOT_PROVIDE_EXTERNAL",
+		12: "ADDRESS_MISMATCH",
+		13: "EXCESSIVE_REMOTE_PEERS",
+	}
+)
+
+// pcpRouter maps ports with the Port Control Protocol.
+//
+// If the client address is an IPv6 address, the PCP server opens a pinhole in
+// its firewall rather than translating the address.
+//
+// The PCP server may grant a shorter lifetime than the one requested. In that
+// case, the router renews the mapping until the requested lifetime has passed
+// or the port is unmapped.
+type pcpRouter struct {
+	server   netip.AddrPort
+	clientIP netip.Addr
+	nonce    [pcpNonceLen]byte
+	timeout  time.Duration
+
+	lock sync.Mutex
+	// internal port -> mapping
+	mappings map[uint16]*pcpMapping
+}
+
+type pcpMapping struct {
+	externalPort uint16
+	externalIP   netip.Addr
+	closer       chan struct{}
+}
+
+// pcpMapResult is the mapping granted by the PCP server.
+type pcpMapResult struct {
+	lifetime     time.Duration
+	externalPort uint16
+	externalIP   netip.Addr
+}
+
+func newPCPRouter(server netip.AddrPort, timeout time.Duration) (*pcpRouter, error) {
+	// The PCP server verifies that the client address matches the source
+	// address of the request, so use the address that the OS routes through.
+	conn, err := net.DialUDP("udp", nil, net.UDPAddrFromAddrPort(server))
+	if err != nil {
+		return nil, err
+	}
+	localAddr := conn.LocalAddr()
+	if err := conn.Close(); err != nil {
+		return nil, err
+	}
+	udpAddr, ok := localAddr.(*net.UDPAddr)
+	if !ok {
+		return nil, errFetchingIP
+	}
+
+	r := &pcpRouter{
+		server:   server,
+		clientIP: udpAddr.AddrPort().Addr().Unmap(),
+		timeout:  timeout,
+		mappings: make(map[uint16]*pcpMapping),
+	}
+	if _, err := rand.Read(r.nonce[:]); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (*pcpRouter) SupportsNAT() bool {
+	return true
+}
+
+func (r *pcpRouter) MapPort(intPort, extPort uint16, _ string, duration time.Duration) error {
+	if duration < 0 || duration.Seconds() > math.MaxUint32 {
+		return errInvalidLifetime
+	}
+
+	result, err := r.requestMapping(intPort, extPort, duration)
+	if err != nil {
+		return err
+	}
+
+	mapping := &pcpMapping{
+		externalPort: result.externalPort,
+		externalIP:   result.externalIP,
+		closer:       make(chan struct{}),
+	}
+
+	r.lock.Lock()
+	if oldMapping, ok := r.mappings[intPort]; ok {
+		close(oldMapping.closer)
+	}
+	r.mappings[intPort] = mapping
+	r.lock.Unlock()
+
+	if result.lifetime < duration {
+		go r.renew(intPort, mapping, time.Now().Add(duration), result.lifetime)
+	}
+	return nil
+}
+
+// renew the mapping of [intPort] before each granted [lifetime] expires, until
+// [expiry]. Stops once [mapping] is replaced or removed.
+func (r *pcpRouter) renew(intPort uint16, mapping *pcpMapping, expiry time.Time, lifetime time.Duration) {
+	for {
+		timer := time.NewTimer(lifetime / 2)
+		select {
+		case <-timer.C:
+		case <-mapping.closer:
+			timer.Stop()
+			return
+		}
+
+		remaining := time.Until(expiry)
+		if remaining <= 0 {
+			return
+		}
+
+		result, err := r.requestMapping(intPort, mapping.externalPort, remaining)
+		if err != nil {
+			// Retry before the current mapping expires.
+			lifetime /= 2
+			if lifetime < 2*r.timeout {
+				return
+			}
+			continue
+		}
+
+		r.lock.Lock()
+		select {
+		case <-mapping.closer:
+			r.lock.Unlock()
+			return
+		default:
+		}
+		mapping.externalPort = result.externalPort
+		mapping.externalIP = result.externalIP
+		r.lock.Unlock()
+
+		if result.lifetime >= remaining {
+			return
+		}
+		lifetime = result.lifetime
+	}
+}
+
+func (r *pcpRouter) UnmapPort(intPort, _ uint16) error {
+	r.lock.Lock()
+	if mapping, ok := r.mappings[intPort]; ok {
+		close(mapping.closer)
+		delete(r.mappings, intPort)
+	}
+	r.lock.Unlock()
+
+	// A lifetime of 0 deletes the mapping.
+	_, err := r.requestMapping(intPort, 0, 0)
+	return err
+}
+
+// ExternalIP returns the external address of the most recent mapping. If no
+// ports are mapped, the external address is read from the response to a
+// zero-lifetime MAP request, which doesn't create a mapping.
+func (r *pcpRouter) ExternalIP() (netip.Addr, error) {
+	r.lock.Lock()
+	for _, mapping := range r.mappings {
+		if mapping.externalIP.IsValid() {
+			r.lock.Unlock()
+			return mapping.externalIP, nil
+		}
+	}
+	r.lock.Unlock()
+
+	result, err := r.requestMapping(pcpProbePort, 0, 0)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	if !result.externalIP.IsValid() || result.externalIP.IsUnspecified() {
+		return netip.Addr{}, errFetchingIP
+	}
+	return result.externalIP, nil
+}
+
+// announce verifies that the server supports PCP.
+func (r *pcpRouter) announce() error {
+	_, _, err := r.request(pcpOpAnnounce, 0, nil)
+	return err
+}
+
+// requestMapping requests a TCP mapping from [intPort] to [extPort] for
+// [lifetime]. [extPort] is only a suggestion and may be 0 to let the server
+// choose.
+func (r *pcpRouter) requestMapping(intPort, extPort uint16, lifetime time.Duration) (pcpMapResult, error) {
+	payload := make([]byte, pcpMapLen)
+	copy(payload, r.nonce[:])
+	payload[pcpNonceLen] = pcpProtocolTCP
+	binary.BigEndian.PutUint16(payload[16:], intPort)
+	binary.BigEndian.PutUint16(payload[18:], extPort)
+	// The suggested external address is left as the unspecified IPv6 address
+	// when the client is an IPv6 address and as the unspecified IPv4 address
+	// otherwise.
+	suggestedIP := netip.IPv6Unspecified()
+	if r.clientIP.Is4() {
+		suggestedIP = netip.IPv4Unspecified()
+	}
+	putPCPAddr(payload[20:], suggestedIP)
+
+	responseLifetime, responsePayload, err := r.request(pcpOpMap, uint32(lifetime.Seconds()), payload)
+	if err != nil {
+		return pcpMapResult{}, err
+	}
+	// The response must be for the mapping that was requested, otherwise it
+	// may be a stale response to a previous request.
+	if len(responsePayload) < pcpMapLen ||
+		!bytes.Equal(responsePayload[:pcpNonceLen], r.nonce[:]) ||
+		responsePayload[pcpNonceLen] != pcpProtocolTCP ||
+		binary.BigEndian.Uint16(responsePayload[16:]) != intPort {
+		return pcpMapResult{}, fmt.Errorf("%w: mismatched mapping", errPCPInvalidResponse)
+	}
+
+	return pcpMapResult{
+		lifetime:     time.Duration(responseLifetime) * time.Second,
+		externalPort: binary.BigEndian.Uint16(responsePayload[18:]),
+		externalIP:   netip.AddrFrom16([16]byte(responsePayload[20:36])).Unmap(),
+	}, nil
+}
+
+// request sends a request with [opcode] to the server and returns the lifetime
+// and opcode-specific payload of the response. The request is retransmitted
+// with an exponentially increasing timeout.
+func (r *pcpRouter) request(opcode byte, lifetime uint32, payload []byte) (uint32, []byte, error) {
+	request := make([]byte, pcpHeaderLen+len(payload))
+	request[0] = pcpVersion
+	request[1] = opcode
+	binary.BigEndian.PutUint32(request[4:], lifetime)
+	putPCPAddr(request[8:], r.clientIP)
+	copy(request[pcpHeaderLen:], payload)
+
+	conn, err := net.DialUDP("udp", nil, net.UDPAddrFromAddrPort(r.server))
+	if err != nil {
+		return 0, nil, err
+	}
+	defer conn.Close()
+
+	var (
+		timeout  = r.timeout
+		response = make([]byte, pcpMaxMsgLen)
+	)
+	for attempt := 0; attempt < pcpMaxAttempts; attempt++ {
+		if _, err = conn.Write(request); err != nil {
+			return 0, nil, err
+		}
+		if err = conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return 0, nil, err
+		}
+		timeout *= 2
+
+		var n int
+		n, err = conn.Read(response)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return 0, nil, err
+		}
+		return parsePCPResponse(opcode, response[:n])
+	}
+	return 0, nil, err
+}
+
+func parsePCPResponse(opcode byte, response []byte) (uint32, []byte, error) {
+	switch {
+	case len(response) < pcpHeaderLen:
+		return 0, nil, fmt.Errorf("%w: too short", errPCPInvalidResponse)
+	case response[0] != pcpVersion:
+		return 0, nil, fmt.Errorf("%w: unexpected version %d", errPCPInvalidResponse, response[0])
+	case response[1] != opcode|pcpOpResponse:
+		return 0, nil, fmt.Errorf("%w: unexpected opcode %d", errPCPInvalidResponse, response[1])
+	case response[3] != pcpResultSuccess:
+		name, ok := pcpResultNames[response[3]]
+		if !ok {
+			name = fmt.Sprintf("result code %d", response[3])
+		}
+		return 0, nil, fmt.Errorf("%w: %s", errPCPResult, name)
+	}
+	lifetime := binary.BigEndian.Uint32(response[4:])
+	return lifetime, response[pcpHeaderLen:], nil
+}
+
+// putPCPAddr writes [addr] to [dst] as a 16 byte address. IPv4 addresses are
+// written as IPv4-mapped IPv6 addresses.
+func putPCPAddr(dst []byte, addr netip.Addr) {
+	bytes := addr.As16()
+	copy(dst, bytes[:])
+}
+
+// discoverIPv4Gateway returns the IPv4 default gateway.
+func discoverIPv4Gateway() (netip.Addr, error) {
+	gatewayIP, err := gateway.DiscoverGateway()
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	gatewayAddr, ok := netip.AddrFromSlice(gatewayIP)
+	if !ok {
+		return netip.Addr{}, errFetchingIP
+	}
+	return gatewayAddr.Unmap(), nil
+}
+
+// parseIPv6Route returns the next hop of the default route with the lowest
+// metric in [routes], which is formatted as /proc/net/ipv6_route. Link-local
+// next hops are zoned to the device of the route.
+func parseIPv6Route(routes []byte) (netip.Addr, error) {
+	var (
+		bestAddr   netip.Addr
+		bestMetric uint64
+	)
+	for _, line := range strings.Split(string(routes), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < ipv6RouteNumFields ||
+			fields[ipv6RouteDestination] != strings.Repeat("0", 2*net.IPv6len) ||
+			fields[ipv6RoutePrefixLen] != "00" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[ipv6RouteFlags], 16, 32)
+		if err != nil || flags&ipv6RouteFlagsGateway != ipv6RouteFlagsGateway {
+			continue
+		}
+		metric, err := strconv.ParseUint(fields[ipv6RouteMetric], 16, 32)
+		if err != nil {
+			continue
+		}
+		nextHop, err := hex.DecodeString(fields[ipv6RouteNextHop])
+		if err != nil || len(nextHop) != net.IPv6len {
+			continue
+		}
+
+		addr := netip.AddrFrom16([net.IPv6len]byte(nextHop))
+		if addr.IsUnspecified() || (bestAddr.IsValid() && metric >= bestMetric) {
+			continue
+		}
+		if addr.IsLinkLocalUnicast() {
+			addr = addr.WithZone(fields[ipv6RouteDevice])
+		}
+		bestAddr = addr
+		bestMetric = metric
+	}
+	if !bestAddr.IsValid() {
+		return netip.Addr{}, errNoIPv6Gateway
+	}
+	return bestAddr, nil
+}
+
+// getPCPRouter returns a router for the first default gateway that supports
+// PCP. The IPv4 gateway is preferred over the IPv6 gateway.
+func getPCPRouter() *pcpRouter {
+	for _, discoverGateway := range []func() (netip.Addr, error){
+		discoverIPv4Gateway,
+		discoverIPv6Gateway,
+	} {
+		gatewayAddr, err := discoverGateway()
+		if err != nil {
+			continue
+		}
+
+		pcp, err := newPCPRouter(netip.AddrPortFrom(gatewayAddr, pcpPort), pcpClientTimeout)
+		if err != nil {
+			continue
+		}
+		if err := pcp.announce(); err != nil {
+			continue
+		}
+		return pcp
+	}
+	return nil
+}
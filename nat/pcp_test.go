@@ -0,0 +1,321 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nat
+
+import (
+	"encoding/binary"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pcpMapRequest is a MAP request received by a fakePCPServer.
+type pcpMapRequest struct {
+	lifetime     uint32
+	internalPort uint16
+	externalPort uint16
+}
+
+// fakePCPServer responds to PCP requests on the loopback interface.
+type fakePCPServer struct {
+	conn *net.UDPConn
+	// externalIP is assigned to every mapping
+	externalIP netip.Addr
+	// maxLifetime caps the lifetime of every mapping
+	maxLifetime uint32
+	// result is the result code of every response
+	result byte
+	// mapRequests receives every MAP request
+	mapRequests chan pcpMapRequest
+}
+
+func newFakePCPServer(t *testing.T, externalIP netip.Addr, maxLifetime uint32, result byte) *fakePCPServer {
+	conn, err := net.ListenUDP("udp", net.UDPAddrFromAddrPort(netip.MustParseAddrPort("127.0.0.1:0")))
+	require.NoError(t, err)
+
+	s := &fakePCPServer{
+		conn:        conn,
+		externalIP:  externalIP,
+		maxLifetime: maxLifetime,
+		result:      result,
+		mapRequests: make(chan pcpMapRequest, 16),
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.serve()
+	}()
+	t.Cleanup(func() {
+		_ = conn.Close()
+		<-done
+	})
+	return s
+}
+
+func (s *fakePCPServer) addr() netip.AddrPort {
+	return s.conn.LocalAddr().(*net.UDPAddr).AddrPort()
+}
+
+func (s *fakePCPServer) serve() {
+	request := make([]byte, pcpMaxMsgLen)
+	for {
+		n, addr, err := s.conn.ReadFromUDPAddrPort(request)
+		if err != nil {
+			return
+		}
+		if n < pcpHeaderLen || request[0] != pcpVersion {
+			continue
+		}
+
+		opcode := request[1]
+		lifetime := min(binary.BigEndian.Uint32(request[4:]), s.maxLifetime)
+		payload := request[pcpHeaderLen:n]
+
+		response := make([]byte, pcpHeaderLen, pcpHeaderLen+pcpMapLen)
+		response[0] = pcpVersion
+		response[1] = opcode | pcpOpResponse
+		response[3] = s.result
+		binary.BigEndian.PutUint32(response[4:], lifetime)
+
+		if opcode == pcpOpMap && len(payload) >= pcpMapLen {
+			internalPort := binary.BigEndian.Uint16(payload[16:])
+			externalPort := binary.BigEndian.Uint16(payload[18:])
+			s.mapRequests <- pcpMapRequest{
+				lifetime:     binary.BigEndian.Uint32(request[4:]),
+				internalPort: internalPort,
+				externalPort: externalPort,
+			}
+
+			if externalPort == 0 {
+				externalPort = internalPort
+			}
+			mapResponse := make([]byte, pcpMapLen)
+			copy(mapResponse, payload[:pcpMapLen])
+			binary.BigEndian.PutUint16(mapResponse[18:], externalPort)
+			putPCPAddr(mapResponse[20:], s.externalIP)
+			response = append(response, mapResponse...)
+		}
+
+		_, _ = s.conn.WriteToUDPAddrPort(response, addr)
+	}
+}
+
+func TestPCPRouterMapPort(t *testing.T) {
+	require := require.New(t)
+
+	externalIP := netip.MustParseAddr("203.0.113.1")
+	server := newFakePCPServer(t, externalIP, 3600, pcpResultSuccess)
+
+	r, err := newPCPRouter(server.addr(), pcpClientTimeout)
+	require.NoError(err)
+	require.NoError(r.announce())
+
+	require.NoError(r.MapPort(9651, 9651, "", time.Minute))
+	require.Equal(pcpMapRequest{
+		lifetime:     60,
+		internalPort: 9651,
+		externalPort: 9651,
+	}, <-server.mapRequests)
+
+	// The external address is known from the mapping.
+	ip, err := r.ExternalIP()
+	require.NoError(err)
+	require.Equal(externalIP, ip)
+
+	require.NoError(r.UnmapPort(9651, 9651))
+	require.Equal(pcpMapRequest{
+		internalPort: 9651,
+	}, <-server.mapRequests)
+	require.Empty(r.mappings)
+}
+
+func TestPCPRouterExternalIP(t *testing.T) {
+	require := require.New(t)
+
+	externalIP := netip.MustParseAddr("2001:db8::1")
+	server := newFakePCPServer(t, externalIP, 3600, pcpResultSuccess)
+
+	r, err := newPCPRouter(server.addr(), pcpClientTimeout)
+	require.NoError(err)
+
+	// Without any mappings, the external address is discovered with a
+	// zero-lifetime request, so no mapping is created.
+	ip, err := r.ExternalIP()
+	require.NoError(err)
+	require.Equal(externalIP, ip)
+	require.Equal(pcpMapRequest{
+		internalPort: pcpProbePort,
+	}, <-server.mapRequests)
+	require.Empty(server.mapRequests)
+}
+
+func TestPCPRouterRenewal(t *testing.T) {
+	require := require.New(t)
+
+	// The server grants a shorter lifetime than requested.
+	server := newFakePCPServer(t, netip.MustParseAddr("203.0.113.1"), 1, pcpResultSuccess)
+
+	r, err := newPCPRouter(server.addr(), pcpClientTimeout)
+	require.NoError(err)
+
+	require.NoError(r.MapPort(9651, 9651, "", time.Minute))
+	request := <-server.mapRequests
+	require.Equal(uint32(60), request.lifetime)
+
+	// The mapping is renewed before the granted lifetime expires.
+	request = <-server.mapRequests
+	require.Equal(uint16(9651), request.internalPort)
+	require.Equal(uint16(9651), request.externalPort)
+	require.Positive(request.lifetime)
+
+	// Once unmapped, the mapping is no longer renewed.
+	require.NoError(r.UnmapPort(9651, 9651))
+	for request := range server.mapRequests {
+		if request.lifetime == 0 {
+			break
+		}
+	}
+	select {
+	case request := <-server.mapRequests:
+		require.FailNow("unexpected renewal", "%+v", request)
+	case <-time.After(time.Second):
+	}
+}
+
+func TestPCPRouterErrors(t *testing.T) {
+	require := require.New(t)
+
+	// NOT_AUTHORIZED
+	server := newFakePCPServer(t, netip.MustParseAddr("203.0.113.1"), 3600, 2)
+
+	r, err := newPCPRouter(server.addr(), pcpClientTimeout)
+	require.NoError(err)
+
+	err = r.MapPort(9651, 9651, "", time.Minute)
+	require.ErrorIs(err, errPCPResult)
+	require.Empty(r.mappings)
+
+	require.ErrorIs(r.MapPort(9651, 9651, "", -time.Minute), errInvalidLifetime)
+}
+
+func TestPCPRouterMismatchedMapping(t *testing.T) {
+	require := require.New(t)
+
+	conn, err := net.ListenUDP("udp", net.UDPAddrFromAddrPort(netip.MustParseAddrPort("127.0.0.1:0")))
+	require.NoError(err)
+	defer conn.Close()
+
+	// Respond to a MAP request with a mapping of a different internal port.
+	go func() {
+		request := make([]byte, pcpMaxMsgLen)
+		n, addr, err := conn.ReadFromUDPAddrPort(request)
+		if err != nil || n < pcpHeaderLen+pcpMapLen {
+			return
+		}
+
+		response := make([]byte, pcpHeaderLen+pcpMapLen)
+		response[0] = pcpVersion
+		response[1] = request[1] | pcpOpResponse
+		copy(response[4:8], request[4:8])
+		copy(response[pcpHeaderLen:], request[pcpHeaderLen:n])
+		binary.BigEndian.PutUint16(response[pcpHeaderLen+16:], 9652)
+		_, _ = conn.WriteToUDPAddrPort(response, addr)
+	}()
+
+	r, err := newPCPRouter(conn.LocalAddr().(*net.UDPAddr).AddrPort(), pcpClientTimeout)
+	require.NoError(err)
+
+	err = r.MapPort(9651, 9651, "", time.Minute)
+	require.ErrorIs(err, errPCPInvalidResponse)
+	require.Empty(r.mappings)
+}
+
+func TestParseIPv6Route(t *testing.T) {
+	tests := []struct {
+		name     string
+		routes   string
+		expected netip.Addr
+		err      error
+	}{
+		{
+			name: "no default route",
+			routes: `fe800000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0
+00000000000000000000000000000001 80 00000000000000000000000000000000 00 00000000000000000000000000000000 00000000 00000002 00000000 80200001       lo
+`,
+			err: errNoIPv6Gateway,
+		},
+		{
+			name: "unreachable default route",
+			routes: `00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo
+`,
+			err: errNoIPv6Gateway,
+		},
+		{
+			name: "link-local gateway",
+			routes: `fe800000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 fe80000000000000021122fffe334455 00000400 00000001 00000000 00000003     eth0
+`,
+			expected: netip.MustParseAddr("fe80::211:22ff:fe33:4455%eth0"),
+		},
+		{
+			name: "lowest metric",
+			routes: `00000000000000000000000000000000 00 00000000000000000000000000000000 00 20010db8000000000000000000000001 00000400 00000001 00000000 00000003     eth0
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 20010db8000000000000000000000002 00000100 00000001 00000000 00000003    wlan0
+`,
+			expected: netip.MustParseAddr("2001:db8::2"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			addr, err := parseIPv6Route([]byte(test.routes))
+			require.ErrorIs(err, test.err)
+			require.Equal(test.expected, addr)
+		})
+	}
+}
+
+func TestParsePCPResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		err      error
+	}{
+		{
+			name:     "too short",
+			response: make([]byte, pcpHeaderLen-1),
+			err:      errPCPInvalidResponse,
+		},
+		{
+			// A NAT-PMP server responds with version 0
+			name:     "unsupported version",
+			response: append([]byte{0, pcpOpMap | pcpOpResponse, 0, 1}, make([]byte, pcpHeaderLen-4)...),
+			err:      errPCPInvalidResponse,
+		},
+		{
+			name:     "unexpected opcode",
+			response: append([]byte{pcpVersion, pcpOpAnnounce | pcpOpResponse}, make([]byte, pcpHeaderLen-2)...),
+			err:      errPCPInvalidResponse,
+		},
+		{
+			name:     "result code",
+			response: append([]byte{pcpVersion, pcpOpMap | pcpOpResponse, 0, 8}, make([]byte, pcpHeaderLen-4)...),
+			err:      errPCPResult,
+		},
+		{
+			name:     "success",
+			response: append([]byte{pcpVersion, pcpOpMap | pcpOpResponse}, make([]byte, pcpHeaderLen-2)...),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := parsePCPResponse(pcpOpMap, test.response)
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
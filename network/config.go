@@ -11,6 +11,8 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/uptime"
@@ -124,6 +126,17 @@ type Config struct {
 	// The compression type to use when compressing outbound messages.
 	// Assumes all peers support this compression type.
 	CompressionType compression.Type `json:"compressionType"`
+	// Trained zstd dictionaries that this node supports. Dictionaries are
+	// advertised in the Handshake message so that peers may compress
+	// messages with them. If CompressionType is
+	// [compression.TypeZstdDictionary], outbound messages are compressed with
+	// the newest dictionary supported by the receiving peer.
+	CompressionDictionaries []*compression.Dictionary `json:"-"`
+	// If non-empty, uncompressed outbound messages are recorded to this file
+	// so that they can be used to train new dictionaries.
+	CompressionSamplesFile string `json:"compressionSamplesFile"`
+	// The maximum number of bytes to record to CompressionSamplesFile.
+	CompressionSamplesMaxSize int64 `json:"compressionSamplesMaxSize"`
 
 	// TLSKey is this node's TLS key that is used to sign IPs.
 	TLSKey crypto.Signer `json:"-"`
@@ -166,9 +179,17 @@ type Config struct {
 	// (there is one buffer per peer)
 	PeerWriteBufferSize int `json:"peerWriteBufferSize"`
 
+	// Scheduling of the priority classes of each peer's outbound message
+	// queue.
+	MessageQueueConfig peer.MessageQueueConfig `json:"messageQueueConfig"`
+
 	// Tracks the CPU/disk usage caused by processing messages of each peer.
 	ResourceTracker tracker.ResourceTracker `json:"-"`
 
+	// Tracks the reputation of each peer. Connections to peers with a poor
+	// reputation are only retained if the connection is desired.
+	Reputation reputation.Tracker `json:"-"`
+
 	// Specifies how much CPU usage each peer can cause before
 	// we rate-limit them.
 	CPUTargeter tracker.Targeter `json:"-"`
@@ -258,23 +258,25 @@ func NewNetwork(
 		Metrics:         peerMetrics,
 		MessageCreator:  msgCreator,
 
-		Log:                  log,
-		InboundMsgThrottler:  inboundMsgThrottler,
-		Network:              nil, // This is set below.
-		Router:               router,
-		VersionCompatibility: version.GetCompatibility(minCompatibleTime),
-		MySubnets:            config.TrackedSubnets,
-		Beacons:              config.Beacons,
-		Validators:           config.Validators,
-		NetworkID:            config.NetworkID,
-		PingFrequency:        config.PingFrequency,
-		PongTimeout:          config.PingPongTimeout,
-		MaxClockDifference:   config.MaxClockDifference,
-		SupportedACPs:        config.SupportedACPs.List(),
-		ObjectedACPs:         config.ObjectedACPs.List(),
-		ResourceTracker:      config.ResourceTracker,
-		UptimeCalculator:     config.UptimeCalculator,
-		IPSigner:             peer.NewIPSigner(config.MyIPPort, config.TLSKey, config.BLSKey),
+		Log:                     log,
+		InboundMsgThrottler:     inboundMsgThrottler,
+		Network:                 nil, // This is set below.
+		Router:                  router,
+		VersionCompatibility:    version.GetCompatibility(minCompatibleTime),
+		MySubnets:               config.TrackedSubnets,
+		Beacons:                 config.Beacons,
+		Validators:              config.Validators,
+		NetworkID:               config.NetworkID,
+		PingFrequency:           config.PingFrequency,
+		PongTimeout:             config.PingPongTimeout,
+		MaxClockDifference:      config.MaxClockDifference,
+		SupportedACPs:           config.SupportedACPs.List(),
+		ObjectedACPs:            config.ObjectedACPs.List(),
+		CompressionDictionaries: config.CompressionDictionaries,
+		ResourceTracker:         config.ResourceTracker,
+		Reputation:              config.Reputation,
+		UptimeCalculator:        config.UptimeCalculator,
+		IPSigner:                peer.NewIPSigner(config.MyIPPort, config.TLSKey, config.BLSKey),
 	}
 
 	onCloseCtx, cancel := context.WithCancel(context.Background())
@@ -473,12 +475,19 @@ func (n *network) Connected(nodeID ids.NodeID) {
 // provided nodeID. If the node is attempting to connect to the minimum number
 // of peers, then it should only connect if this node is a validator, or the
 // peer is a validator/beacon.
+//
+// Connections to peers with a poor reputation are only allowed if the
+// connection is desired, e.g. because the peer is a validator.
 func (n *network) AllowConnection(nodeID ids.NodeID) bool {
+	wantsConnection := n.ipTracker.WantsConnection(nodeID)
+	if !wantsConnection && n.config.Reputation.IsPoor(nodeID) {
+		return false
+	}
 	if !n.config.RequireValidatorToConnect {
 		return true
 	}
 	_, iAmAValidator := n.config.Validators.GetValidator(constants.PrimaryNetworkID, n.config.MyNodeID)
-	return iAmAValidator || n.ipTracker.WantsConnection(nodeID)
+	return iAmAValidator || wantsConnection
 }
 
 func (n *network) Track(claimedIPPorts []*ips.ClaimedIPPort) error {
@@ -1049,6 +1058,8 @@ func (n *network) upgrade(conn net.Conn, upgrader peer.Upgrader) error {
 			nodeID,
 			n.peerConfig.Log,
 			n.outboundMsgThrottler,
+			n.peerConfig.Metrics,
+			n.config.MessageQueueConfig,
 		),
 	)
 	n.connectingPeers.Add(peer)
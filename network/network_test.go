@@ -18,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network/dialer"
 	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
@@ -120,7 +121,9 @@ var (
 		RequireValidatorToConnect: false,
 
 		MaximumInboundMessageTimeout: 30 * time.Second,
+		MessageQueueConfig:           peer.DefaultMessageQueueConfig,
 		ResourceTracker:              newDefaultResourceTracker(),
+		Reputation:                   reputation.NoTracker{},
 		CPUTargeter:                  nil, // Set in init
 		DiskTargeter:                 nil, // Set in init
 	}
@@ -198,6 +201,8 @@ func newMessageCreator(t *testing.T) message.Creator {
 		logging.NoLog{},
 		prometheus.NewRegistry(),
 		constants.DefaultNetworkCompressionType,
+		nil,
+		nil,
 		10*time.Second,
 	)
 	require.NoError(t, err)
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -88,37 +89,54 @@ func (c *Client) AppRequest(
 
 	appRequestBytes = PrefixMessage(c.handlerPrefix, appRequestBytes)
 	for nodeID := range nodeIDs {
-		requestID := c.router.requestID
-		if _, ok := c.router.pendingAppRequests[requestID]; ok {
-			return fmt.Errorf(
-				"failed to issue request with request id %d: %w",
-				requestID,
-				ErrRequestPending,
-			)
+		if err := c.appRequest(ctxWithoutCancel, nodeID, appRequestBytes, onResponse); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
 
-		if err := c.sender.SendAppRequest(
-			ctxWithoutCancel,
-			set.Of(nodeID),
+// appRequest issues the already prefixed [appRequestBytes] to [nodeID] with
+// the next request ID.
+//
+// Invariant: Assumes [c.router.lock] is held.
+func (c *Client) appRequest(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	appRequestBytes []byte,
+	onResponse AppResponseCallback,
+) error {
+	requestID := c.router.requestID
+	if _, ok := c.router.pendingAppRequests[requestID]; ok {
+		return fmt.Errorf(
+			"failed to issue request with request id %d: %w",
 			requestID,
-			appRequestBytes,
-		); err != nil {
-			c.router.log.Error("unexpected error when sending message",
-				zap.Stringer("op", message.AppRequestOp),
-				zap.Stringer("nodeID", nodeID),
-				zap.Uint32("requestID", requestID),
-				zap.Error(err),
-			)
-			return err
-		}
+			ErrRequestPending,
+		)
+	}
 
-		c.router.pendingAppRequests[requestID] = pendingAppRequest{
-			handlerID: c.handlerIDStr,
-			callback:  onResponse,
-		}
-		c.router.requestID += 2
+	if err := c.sender.SendAppRequest(
+		ctx,
+		set.Of(nodeID),
+		requestID,
+		appRequestBytes,
+	); err != nil {
+		c.router.log.Error("unexpected error when sending message",
+			zap.Stringer("op", message.AppRequestOp),
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Error(err),
+		)
+		return err
 	}
 
+	c.router.pendingAppRequests[requestID] = pendingAppRequest{
+		handlerID: c.handlerIDStr,
+		callback:  onResponse,
+		start:     time.Now(),
+	}
+	c.router.requestID += 2
 	return nil
 }
 
@@ -30,4 +30,16 @@ var (
 		Code:    -4,
 		Message: "throttled",
 	}
+	// ErrTooManyStreams should be used to indicate that a request failed due
+	// to the requesting peer having too many open streams
+	ErrTooManyStreams = &common.AppError{
+		Code:    -5,
+		Message: "too many streams",
+	}
+	// ErrInvalidRequest should be used to indicate that a request failed due to
+	// the request being malformed
+	ErrInvalidRequest = &common.AppError{
+		Code:    -6,
+		Message: "invalid request",
+	}
 )
@@ -16,6 +16,7 @@ import (
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network/p2p"
+	"github.com/ava-labs/avalanchego/proto/pb/sdk"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/bloom"
 	"github.com/ava-labs/avalanchego/utils/buffer"
@@ -192,7 +193,7 @@ func NewPullGossiper[T Gossipable](
 		log:        log,
 		marshaller: marshaller,
 		set:        set,
-		client:     client,
+		client:     p2p.NewTypedClient[*sdk.PullGossipRequest, *sdk.PullGossipResponse](client),
 		metrics:    metrics,
 		pollSize:   pollSize,
 	}
@@ -202,19 +203,20 @@ type PullGossiper[T Gossipable] struct {
 	log        logging.Logger
 	marshaller Marshaller[T]
 	set        Set[T]
-	client     *p2p.Client
+	client     *p2p.TypedClient[*sdk.PullGossipRequest, *sdk.PullGossipResponse]
 	metrics    Metrics
 	pollSize   int
 }
 
 func (p *PullGossiper[_]) Gossip(ctx context.Context) error {
-	msgBytes, err := MarshalAppRequest(p.set.GetFilter())
-	if err != nil {
-		return err
+	filter, salt := p.set.GetFilter()
+	request := &sdk.PullGossipRequest{
+		Filter: filter,
+		Salt:   salt,
 	}
 
 	for i := 0; i < p.pollSize; i++ {
-		err := p.client.AppRequestAny(ctx, msgBytes, p.handleResponse)
+		err := p.client.AppRequestAny(ctx, request, p.handleResponse)
 		if err != nil && !errors.Is(err, p2p.ErrNoPeers) {
 			return err
 		}
@@ -226,7 +228,7 @@ func (p *PullGossiper[_]) Gossip(ctx context.Context) error {
 func (p *PullGossiper[_]) handleResponse(
 	_ context.Context,
 	nodeID ids.NodeID,
-	responseBytes []byte,
+	response *sdk.PullGossipResponse,
 	err error,
 ) {
 	if err != nil {
@@ -238,19 +240,25 @@ func (p *PullGossiper[_]) handleResponse(
 		return
 	}
 
-	gossip, err := ParseAppResponse(responseBytes)
-	if err != nil {
-		p.log.Debug("failed to unmarshal gossip response", zap.Error(err))
-		return
-	}
+	addPulledGossip(p.log, p.marshaller, p.set, p.metrics, nodeID, response.Gossip)
+}
 
+// addPulledGossip adds the [gossip] pulled from [nodeID] to [set].
+func addPulledGossip[T Gossipable](
+	log logging.Logger,
+	marshaller Marshaller[T],
+	set Set[T],
+	metrics Metrics,
+	nodeID ids.NodeID,
+	gossip [][]byte,
+) {
 	receivedBytes := 0
 	for _, bytes := range gossip {
 		receivedBytes += len(bytes)
 
-		gossipable, err := p.marshaller.UnmarshalGossip(bytes)
+		gossipable, err := marshaller.UnmarshalGossip(bytes)
 		if err != nil {
-			p.log.Debug(
+			log.Debug(
 				"failed to unmarshal gossip",
 				zap.Stringer("nodeID", nodeID),
 				zap.Error(err),
@@ -259,13 +267,13 @@ func (p *PullGossiper[_]) handleResponse(
 		}
 
 		gossipID := gossipable.GossipID()
-		p.log.Debug(
+		log.Debug(
 			"received gossip",
 			zap.Stringer("nodeID", nodeID),
 			zap.Stringer("id", gossipID),
 		)
-		if err := p.set.Add(gossipable); err != nil {
-			p.log.Debug(
+		if err := set.Add(gossipable); err != nil {
+			log.Debug(
 				"failed to add gossip to the known set",
 				zap.Stringer("nodeID", nodeID),
 				zap.Stringer("id", gossipID),
@@ -275,8 +283,8 @@ func (p *PullGossiper[_]) handleResponse(
 		}
 	}
 
-	if err := p.metrics.observeMessage(receivedPullLabels, len(gossip), receivedBytes); err != nil {
-		p.log.Error("failed to update metrics",
+	if err := metrics.observeMessage(receivedPullLabels, len(gossip), receivedBytes); err != nil {
+		log.Error("failed to update metrics",
 			zap.Error(err),
 		)
 	}
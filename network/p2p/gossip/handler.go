@@ -11,12 +11,16 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network/p2p"
+	"github.com/ava-labs/avalanchego/proto/pb/sdk"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/bloom"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
-var _ p2p.Handler = (*Handler[*testTx])(nil)
+var (
+	_ p2p.Handler                                                       = (*Handler[*testTx])(nil)
+	_ p2p.TypedHandler[*sdk.PullGossipRequest, *sdk.PullGossipResponse] = (*pullHandler[*testTx])(nil)
+)
 
 func NewHandler[T Gossipable](
 	log logging.Logger,
@@ -26,33 +30,60 @@ func NewHandler[T Gossipable](
 	targetResponseSize int,
 ) *Handler[T] {
 	return &Handler[T]{
-		Handler:            p2p.NoOpHandler{},
-		log:                log,
-		marshaller:         marshaller,
-		set:                set,
-		metrics:            metrics,
-		targetResponseSize: targetResponseSize,
+		Handler: p2p.NewTypedHandler[*sdk.PullGossipRequest, *sdk.PullGossipResponse](
+			&pullHandler[T]{
+				marshaller:         marshaller,
+				set:                set,
+				metrics:            metrics,
+				targetResponseSize: targetResponseSize,
+			},
+			log,
+		),
+		log:        log,
+		marshaller: marshaller,
+		set:        set,
+		metrics:    metrics,
 	}
 }
 
+// Handler responds to pull gossip requests and adds pushed gossip to the
+// known set.
 type Handler[T Gossipable] struct {
 	p2p.Handler
+	marshaller Marshaller[T]
+	log        logging.Logger
+	set        Set[T]
+	metrics    Metrics
+}
+
+// pullHandler responds to pull gossip requests with the gossip that the
+// requester doesn't know about.
+type pullHandler[T Gossipable] struct {
 	marshaller         Marshaller[T]
-	log                logging.Logger
 	set                Set[T]
 	metrics            Metrics
 	targetResponseSize int
 }
 
-func (h Handler[T]) AppRequest(_ context.Context, _ ids.NodeID, _ time.Time, requestBytes []byte) ([]byte, *common.AppError) {
-	filter, salt, err := ParseAppRequest(requestBytes)
+func (p *pullHandler[T]) AppRequest(
+	_ context.Context,
+	_ ids.NodeID,
+	_ time.Time,
+	request *sdk.PullGossipRequest,
+) (*sdk.PullGossipResponse, *common.AppError) {
+	salt, err := ids.ToID(request.Salt)
 	if err != nil {
-		return nil, p2p.ErrUnexpected
+		return nil, p2p.ErrInvalidRequest
+	}
+
+	filter, err := bloom.Parse(request.Filter)
+	if err != nil {
+		return nil, p2p.ErrInvalidRequest
 	}
 
 	responseSize := 0
 	gossipBytes := make([][]byte, 0)
-	h.set.Iterate(func(gossipable T) bool {
+	p.set.Iterate(func(gossipable T) bool {
 		gossipID := gossipable.GossipID()
 
 		// filter out what the requesting peer already knows about
@@ -61,7 +92,7 @@ func (h Handler[T]) AppRequest(_ context.Context, _ ids.NodeID, _ time.Time, req
 		}
 
 		var bytes []byte
-		bytes, err = h.marshaller.MarshalGossip(gossipable)
+		bytes, err = p.marshaller.MarshalGossip(gossipable)
 		if err != nil {
 			return false
 		}
@@ -71,22 +102,19 @@ func (h Handler[T]) AppRequest(_ context.Context, _ ids.NodeID, _ time.Time, req
 		gossipBytes = append(gossipBytes, bytes)
 		responseSize += len(bytes)
 
-		return responseSize <= h.targetResponseSize
+		return responseSize <= p.targetResponseSize
 	})
 	if err != nil {
 		return nil, p2p.ErrUnexpected
 	}
 
-	if err := h.metrics.observeMessage(sentPullLabels, len(gossipBytes), responseSize); err != nil {
-		return nil, p2p.ErrUnexpected
-	}
-
-	response, err := MarshalAppResponse(gossipBytes)
-	if err != nil {
+	if err := p.metrics.observeMessage(sentPullLabels, len(gossipBytes), responseSize); err != nil {
 		return nil, p2p.ErrUnexpected
 	}
 
-	return response, nil
+	return &sdk.PullGossipResponse{
+		Gossip: gossipBytes,
+	}, nil
 }
 
 func (h Handler[_]) AppGossip(_ context.Context, nodeID ids.NodeID, gossipBytes []byte) {
@@ -11,6 +11,9 @@ import (
 	"github.com/ava-labs/avalanchego/utils/bloom"
 )
 
+// Deprecated: Pull gossip requests are sent with a p2p.TypedClient of
+// sdk.PullGossipRequest. MarshalAppRequest will be removed in a future
+// release.
 func MarshalAppRequest(filter, salt []byte) ([]byte, error) {
 	request := &sdk.PullGossipRequest{
 		Filter: filter,
@@ -19,6 +22,8 @@ func MarshalAppRequest(filter, salt []byte) ([]byte, error) {
 	return proto.Marshal(request)
 }
 
+// Deprecated: Pull gossip requests are handled by a p2p.TypedHandler of
+// sdk.PullGossipRequest. ParseAppRequest will be removed in a future release.
 func ParseAppRequest(bytes []byte) (*bloom.ReadFilter, ids.ID, error) {
 	request := &sdk.PullGossipRequest{}
 	if err := proto.Unmarshal(bytes, request); err != nil {
@@ -34,12 +39,17 @@ func ParseAppRequest(bytes []byte) (*bloom.ReadFilter, ids.ID, error) {
 	return filter, salt, err
 }
 
+// Deprecated: Pull gossip responses are sent by a p2p.TypedHandler of
+// sdk.PullGossipRequest. MarshalAppResponse will be removed in a future
+// release.
 func MarshalAppResponse(gossip [][]byte) ([]byte, error) {
 	return proto.Marshal(&sdk.PullGossipResponse{
 		Gossip: gossip,
 	})
 }
 
+// Deprecated: Pull gossip responses are received by a p2p.TypedClient of
+// sdk.PullGossipRequest. ParseAppResponse will be removed in a future release.
 func ParseAppResponse(bytes []byte) ([][]byte, error) {
 	response := &sdk.PullGossipResponse{}
 	err := proto.Unmarshal(bytes, response)
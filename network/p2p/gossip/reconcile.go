@@ -0,0 +1,252 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossip
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/p2p"
+	"github.com/ava-labs/avalanchego/proto/pb/sdk"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/iblt"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+const (
+	// minReconcileTableSize is the number of cells in the first table sent by
+	// a ReconcilePullGossiper, and the smallest table it will ever send.
+	minReconcileTableSize = 192
+	// maxReconcileTableSize bounds the size of the tables sent by a
+	// ReconcilePullGossiper to roughly 700 KiB.
+	maxReconcileTableSize = 1 << 14
+	// reconcileTableOverhead is the number of cells allocated per expected
+	// difference between the sets.
+	reconcileTableOverhead = 4
+)
+
+var (
+	_ Gossiper = (*ReconcilePullGossiper[*testTx])(nil)
+
+	_ p2p.TypedHandler[*sdk.ReconcileGossipRequest, *sdk.ReconcileGossipResponse] = (*reconcileHandler[*testTx])(nil)
+)
+
+// NewReconcilePullGossiper returns a gossiper that pulls gossip using set
+// reconciliation. Peers must serve the protocol with a handler returned by
+// NewReconcileHandler.
+//
+// Unlike PullGossiper, which sends a bloom filter of the known gossip,
+// ReconcilePullGossiper sends an invertible bloom lookup table. Peers decode the
+// difference between their set and the table, so exactly the missing gossip is
+// sent back. The size of the table is adapted to the size of the differences
+// observed in previous rounds.
+func NewReconcilePullGossiper[T Gossipable](
+	log logging.Logger,
+	marshaller Marshaller[T],
+	set Set[T],
+	client *p2p.Client,
+	metrics Metrics,
+	pollSize int,
+) *ReconcilePullGossiper[T] {
+	return &ReconcilePullGossiper[T]{
+		log:        log,
+		marshaller: marshaller,
+		set:        set,
+		client:     p2p.NewTypedClient[*sdk.ReconcileGossipRequest, *sdk.ReconcileGossipResponse](client),
+		metrics:    metrics,
+		pollSize:   pollSize,
+		tableSize:  minReconcileTableSize,
+	}
+}
+
+type ReconcilePullGossiper[T Gossipable] struct {
+	log        logging.Logger
+	marshaller Marshaller[T]
+	set        Set[T]
+	client     *p2p.TypedClient[*sdk.ReconcileGossipRequest, *sdk.ReconcileGossipResponse]
+	metrics    Metrics
+	pollSize   int
+
+	lock sync.Mutex
+	// tableSize is the number of cells in the next table to send
+	tableSize int
+}
+
+func (r *ReconcilePullGossiper[T]) Gossip(ctx context.Context) error {
+	var salt ids.ID
+	if _, err := rand.Read(salt[:]); err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	tableSize := r.tableSize
+	r.lock.Unlock()
+
+	table, err := iblt.New(tableSize, salt)
+	if err != nil {
+		return err
+	}
+	r.set.Iterate(func(gossipable T) bool {
+		table.Add(gossipable.GossipID())
+		return true
+	})
+
+	request := &sdk.ReconcileGossipRequest{
+		Table: table.Marshal(),
+	}
+	onResponse := func(ctx context.Context, nodeID ids.NodeID, response *sdk.ReconcileGossipResponse, err error) {
+		r.handleResponse(ctx, nodeID, tableSize, response, err)
+	}
+	for i := 0; i < r.pollSize; i++ {
+		err := r.client.AppRequestAny(ctx, request, onResponse)
+		if err != nil && !errors.Is(err, p2p.ErrNoPeers) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ReconcilePullGossiper[_]) handleResponse(
+	_ context.Context,
+	nodeID ids.NodeID,
+	tableSize int,
+	response *sdk.ReconcileGossipResponse,
+	err error,
+) {
+	if err != nil {
+		r.log.Debug(
+			"failed gossip request",
+			zap.Stringer("nodeID", nodeID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	r.lock.Lock()
+	if response.Decoded {
+		// Size the next table for the observed difference, so the table
+		// shrinks as the sets converge.
+		r.tableSize = int(response.NumDifferences) * reconcileTableOverhead
+	} else {
+		// The table was too small to decode the difference, so the responder
+		// could only send some of the missing gossip.
+		r.tableSize = max(r.tableSize, 2*tableSize)
+	}
+	r.tableSize = min(max(r.tableSize, minReconcileTableSize), maxReconcileTableSize)
+	r.lock.Unlock()
+
+	addPulledGossip(r.log, r.marshaller, r.set, r.metrics, nodeID, response.Gossip)
+}
+
+// NewReconcileHandler returns a handler that responds to requests sent by a
+// ReconcilePullGossiper and adds pushed gossip to the known set.
+func NewReconcileHandler[T Gossipable](
+	log logging.Logger,
+	marshaller Marshaller[T],
+	set Set[T],
+	metrics Metrics,
+	targetResponseSize int,
+) *Handler[T] {
+	return &Handler[T]{
+		Handler: p2p.NewTypedHandler[*sdk.ReconcileGossipRequest, *sdk.ReconcileGossipResponse](
+			&reconcileHandler[T]{
+				marshaller:         marshaller,
+				set:                set,
+				metrics:            metrics,
+				targetResponseSize: targetResponseSize,
+			},
+			log,
+		),
+		log:        log,
+		marshaller: marshaller,
+		set:        set,
+		metrics:    metrics,
+	}
+}
+
+// reconcileHandler responds to reconciliation requests with the gossip that
+// the requester doesn't know about.
+type reconcileHandler[T Gossipable] struct {
+	marshaller         Marshaller[T]
+	set                Set[T]
+	metrics            Metrics
+	targetResponseSize int
+}
+
+func (r *reconcileHandler[T]) AppRequest(
+	_ context.Context,
+	_ ids.NodeID,
+	_ time.Time,
+	request *sdk.ReconcileGossipRequest,
+) (*sdk.ReconcileGossipResponse, *common.AppError) {
+	remote, err := iblt.Parse(request.Table)
+	if err != nil {
+		return nil, p2p.ErrInvalidRequest
+	}
+
+	local, err := iblt.New(remote.Len(), remote.Salt())
+	if err != nil {
+		return nil, p2p.ErrUnexpected
+	}
+	r.set.Iterate(func(gossipable T) bool {
+		local.Add(gossipable.GossipID())
+		return true
+	})
+	if err := local.Subtract(remote); err != nil {
+		return nil, p2p.ErrUnexpected
+	}
+
+	// [missing] is the gossip that we know about and the requester doesn't.
+	// If the difference couldn't be fully decoded, it is a subset of the
+	// missing gossip.
+	missing, extra, decoded := local.Decode()
+	missingSet := set.Of(missing...)
+
+	responseSize := 0
+	gossipBytes := make([][]byte, 0, len(missing))
+	if len(missing) > 0 {
+		r.set.Iterate(func(gossipable T) bool {
+			if !missingSet.Contains(gossipable.GossipID()) {
+				return true
+			}
+
+			var bytes []byte
+			bytes, err = r.marshaller.MarshalGossip(gossipable)
+			if err != nil {
+				return false
+			}
+
+			// check that this doesn't exceed our maximum configured target
+			// response size
+			gossipBytes = append(gossipBytes, bytes)
+			responseSize += len(bytes)
+
+			return responseSize <= r.targetResponseSize && len(gossipBytes) < len(missing)
+		})
+		if err != nil {
+			return nil, p2p.ErrUnexpected
+		}
+	}
+
+	if err := r.metrics.observeMessage(sentPullLabels, len(gossipBytes), responseSize); err != nil {
+		return nil, p2p.ErrUnexpected
+	}
+
+	response := &sdk.ReconcileGossipResponse{
+		Gossip:  gossipBytes,
+		Decoded: decoded,
+	}
+	if decoded {
+		response.NumDifferences = uint32(len(missing) + len(extra))
+	}
+	return response, nil
+}
@@ -0,0 +1,298 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossip
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/p2p"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+// pullGossipTest connects a requester to a responder that serves pull gossip
+// with [handler].
+type pullGossipTest struct {
+	requestSender   *common.FakeSender
+	requestNetwork  *p2p.Network
+	responseSender  *common.FakeSender
+	responseNetwork *p2p.Network
+	requestID       uint32
+}
+
+func newPullGossipTest(t testing.TB, handler p2p.Handler) *pullGossipTest {
+	require := require.New(t)
+
+	responseSender := &common.FakeSender{
+		SentAppResponse: make(chan []byte, 1),
+	}
+	responseNetwork, err := p2p.NewNetwork(logging.NoLog{}, responseSender, prometheus.NewRegistry(), "")
+	require.NoError(err)
+	require.NoError(responseNetwork.AddHandler(0x0, handler))
+
+	requestSender := &common.FakeSender{
+		SentAppRequest: make(chan []byte, 1),
+	}
+	requestNetwork, err := p2p.NewNetwork(logging.NoLog{}, requestSender, prometheus.NewRegistry(), "")
+	require.NoError(err)
+	require.NoError(requestNetwork.Connected(context.Background(), ids.EmptyNodeID, nil))
+
+	return &pullGossipTest{
+		requestSender:   requestSender,
+		requestNetwork:  requestNetwork,
+		responseSender:  responseSender,
+		responseNetwork: responseNetwork,
+		requestID:       1,
+	}
+}
+
+// round runs a single round of [gossiper], which must poll exactly one peer.
+// Returns the size of the request.
+func (p *pullGossipTest) round(t testing.TB, gossiper Gossiper) int {
+	require := require.New(t)
+	ctx := context.Background()
+
+	require.NoError(gossiper.Gossip(ctx))
+	request := <-p.requestSender.SentAppRequest
+	require.NoError(p.responseNetwork.AppRequest(ctx, ids.EmptyNodeID, p.requestID, time.Time{}, request))
+	require.NoError(p.requestNetwork.AppResponse(ctx, ids.EmptyNodeID, p.requestID, <-p.responseSender.SentAppResponse))
+
+	// The sdk uses odd-numbered requestIDs
+	p.requestID += 2
+	return len(request)
+}
+
+func newTestSet(t testing.TB, txs []*testTx) *testSet {
+	require := require.New(t)
+
+	bloom, err := NewBloomFilter(prometheus.NewRegistry(), "", max(len(txs), 1000), 0.01, 0.05)
+	require.NoError(err)
+	s := &testSet{
+		txs:   make(map[ids.ID]*testTx),
+		bloom: bloom,
+	}
+	for _, tx := range txs {
+		require.NoError(s.Add(tx))
+	}
+	return s
+}
+
+func TestReconcilePullGossiperGossip(t *testing.T) {
+	tests := []struct {
+		name               string
+		targetResponseSize int
+		requester          []*testTx // what we have
+		responder          []*testTx // what the peer we're requesting gossip from has
+		expected           []*testTx // what we should have after gossiping
+	}{
+		{
+			name: "no gossip - no one knows anything",
+		},
+		{
+			name:               "no gossip - requester knows more than responder",
+			targetResponseSize: 1024,
+			requester:          []*testTx{{id: ids.ID{0}}},
+			expected:           []*testTx{{id: ids.ID{0}}},
+		},
+		{
+			name:               "no gossip - requester knows everything responder knows",
+			targetResponseSize: 1024,
+			requester:          []*testTx{{id: ids.ID{0}}},
+			responder:          []*testTx{{id: ids.ID{0}}},
+			expected:           []*testTx{{id: ids.ID{0}}},
+		},
+		{
+			name:               "gossip - requester knows nothing",
+			targetResponseSize: 1024,
+			responder:          []*testTx{{id: ids.ID{0}}},
+			expected:           []*testTx{{id: ids.ID{0}}},
+		},
+		{
+			name:               "gossip - requester knows less than responder",
+			targetResponseSize: 1024,
+			requester:          []*testTx{{id: ids.ID{0}}, {id: ids.ID{2}}},
+			responder:          []*testTx{{id: ids.ID{0}}, {id: ids.ID{1}}},
+			expected:           []*testTx{{id: ids.ID{0}}, {id: ids.ID{1}}, {id: ids.ID{2}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			metrics, err := NewMetrics(prometheus.NewRegistry(), "")
+			require.NoError(err)
+			handler := NewReconcileHandler[*testTx](
+				logging.NoLog{},
+				testMarshaller{},
+				newTestSet(t, tt.responder),
+				metrics,
+				tt.targetResponseSize,
+			)
+			test := newPullGossipTest(t, handler)
+
+			requestSet := newTestSet(t, tt.requester)
+			gossiper := NewReconcilePullGossiper[*testTx](
+				logging.NoLog{},
+				testMarshaller{},
+				requestSet,
+				test.requestNetwork.NewClient(0x0),
+				metrics,
+				1,
+			)
+			received := set.Set[*testTx]{}
+			requestSet.onAdd = func(tx *testTx) {
+				received.Add(tx)
+			}
+
+			test.round(t, gossiper)
+			require.ElementsMatch(tt.expected, maps.Values(requestSet.txs))
+
+			// we should not receive anything that we already had before we
+			// requested the gossip
+			for _, tx := range tt.requester {
+				require.NotContains(received, tx)
+			}
+		})
+	}
+}
+
+func TestReconcilePullGossiperTableSize(t *testing.T) {
+	require := require.New(t)
+
+	// The responder knows about far more gossip than fits in the smallest
+	// table.
+	responder := make([]*testTx, 4*minReconcileTableSize)
+	for i := range responder {
+		responder[i] = &testTx{id: ids.GenerateTestID()}
+	}
+
+	metrics, err := NewMetrics(prometheus.NewRegistry(), "")
+	require.NoError(err)
+	handler := NewReconcileHandler[*testTx](
+		logging.NoLog{},
+		testMarshaller{},
+		newTestSet(t, responder),
+		metrics,
+		units.MiB,
+	)
+	test := newPullGossipTest(t, handler)
+
+	requestSet := newTestSet(t, nil)
+	gossiper := NewReconcilePullGossiper[*testTx](
+		logging.NoLog{},
+		testMarshaller{},
+		requestSet,
+		test.requestNetwork.NewClient(0x0),
+		metrics,
+		1,
+	)
+
+	// The table grows until the difference can be decoded.
+	for len(requestSet.txs) < len(responder) {
+		previousSize := gossiper.tableSize
+		test.round(t, gossiper)
+		if len(requestSet.txs) < len(responder) {
+			require.Greater(gossiper.tableSize, previousSize)
+		}
+	}
+	require.ElementsMatch(responder, maps.Values(requestSet.txs))
+
+	// Once the sets have converged, the table shrinks back down.
+	test.round(t, gossiper)
+	require.Equal(minReconcileTableSize, gossiper.tableSize)
+}
+
+// benchmarkSet is a set that doesn't change when gossip is added, so that
+// every round of gossip sees the same difference between the sets.
+type benchmarkSet struct {
+	*testSet
+	received int
+}
+
+func (b *benchmarkSet) Add(*testTx) error {
+	b.received++
+	return nil
+}
+
+// BenchmarkPullGossip compares pulling gossip with a bloom filter to pulling
+// gossip with set reconciliation, when the requester is missing a small amount
+// of the responder's gossip.
+//
+// The "missed" metric is the fraction of the missing gossip that was not
+// received. Pulling with a bloom filter misses gossip that collides with the
+// filter, while set reconciliation receives exactly the missing gossip.
+func BenchmarkPullGossip(b *testing.B) {
+	const numMissing = 64
+
+	for _, size := range []int{10_000, 100_000} {
+		responder := make([]*testTx, size)
+		for i := range responder {
+			responder[i] = &testTx{id: ids.GenerateTestID()}
+		}
+		requester := responder[numMissing:]
+
+		protocols := []struct {
+			name        string
+			newHandler  func(Set[*testTx], Metrics) p2p.Handler
+			newGossiper func(Set[*testTx], *p2p.Client, Metrics) Gossiper
+		}{
+			{
+				name: "bloom",
+				newHandler: func(s Set[*testTx], metrics Metrics) p2p.Handler {
+					return NewHandler[*testTx](logging.NoLog{}, testMarshaller{}, s, metrics, units.MiB)
+				},
+				newGossiper: func(s Set[*testTx], client *p2p.Client, metrics Metrics) Gossiper {
+					return NewPullGossiper[*testTx](logging.NoLog{}, testMarshaller{}, s, client, metrics, 1)
+				},
+			},
+			{
+				name: "reconcile",
+				newHandler: func(s Set[*testTx], metrics Metrics) p2p.Handler {
+					return NewReconcileHandler[*testTx](logging.NoLog{}, testMarshaller{}, s, metrics, units.MiB)
+				},
+				newGossiper: func(s Set[*testTx], client *p2p.Client, metrics Metrics) Gossiper {
+					return NewReconcilePullGossiper[*testTx](logging.NoLog{}, testMarshaller{}, s, client, metrics, 1)
+				},
+			},
+		}
+
+		for _, protocol := range protocols {
+			b.Run(fmt.Sprintf("%s/mempool=%d", protocol.name, size), func(b *testing.B) {
+				metrics, err := NewMetrics(prometheus.NewRegistry(), "")
+				require.NoError(b, err)
+				test := newPullGossipTest(b, protocol.newHandler(newTestSet(b, responder), metrics))
+
+				requestSet := &benchmarkSet{
+					testSet: newTestSet(b, requester),
+				}
+				gossiper := protocol.newGossiper(requestSet, test.requestNetwork.NewClient(0x0), metrics)
+
+				// Let the protocol adapt to the difference between the sets.
+				test.round(b, gossiper)
+				requestSet.received = 0
+
+				b.ResetTimer()
+				requestBytes := 0
+				for i := 0; i < b.N; i++ {
+					requestBytes += test.round(b, gossiper)
+				}
+				b.StopTimer()
+
+				b.ReportMetric(float64(requestBytes)/float64(b.N), "request-bytes/op")
+				b.ReportMetric(1-float64(requestSet.received)/float64(numMissing*b.N), "missed")
+			})
+		}
+	}
+}
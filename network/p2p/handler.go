@@ -21,6 +21,9 @@ const (
 	AtomicTxGossipHandlerID
 	// SignatureRequestHandlerID is specified in ACP-118: https://github.com/avalanche-foundation/ACPs/tree/main/ACPs/118-warp-signature-request
 	SignatureRequestHandlerID
+	// StreamChunkHandlerID is reserved for pushing the chunks of streams opened
+	// by Client.AppRequestStream to the requester
+	StreamChunkHandlerID
 )
 
 var (
@@ -116,15 +119,17 @@ func (v ValidatorHandler) CrossChainAppRequest(ctx context.Context, chainID ids.
 // responder automatically sends the response for a given request
 type responder struct {
 	Handler
-	handlerID uint64
-	log       logging.Logger
-	sender    common.AppSender
+	handlerID    uint64
+	handlerIDStr string
+	log          logging.Logger
+	sender       common.AppSender
+	metrics      metrics
 }
 
 // AppRequest calls the underlying handler and sends back the response to nodeID
 func (r *responder) AppRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, deadline time.Time, request []byte) error {
-	appResponse, err := r.Handler.AppRequest(ctx, nodeID, deadline, request)
-	if err != nil {
+	appResponse, appErr := r.Handler.AppRequest(ctx, nodeID, deadline, request)
+	if appErr != nil {
 		r.log.Debug("failed to handle message",
 			zap.Stringer("messageOp", message.AppRequestOp),
 			zap.Stringer("nodeID", nodeID),
@@ -132,9 +137,12 @@ func (r *responder) AppRequest(ctx context.Context, nodeID ids.NodeID, requestID
 			zap.Time("deadline", deadline),
 			zap.Uint64("handlerID", r.handlerID),
 			zap.Binary("message", request),
-			zap.Error(err),
+			zap.Error(appErr),
 		)
-		return r.sender.SendAppError(ctx, nodeID, requestID, err.Code, err.Message)
+		if err := r.metrics.observeAppError(sentDirection, r.handlerIDStr, appErr.Code); err != nil {
+			return err
+		}
+		return r.sender.SendAppError(ctx, nodeID, requestID, appErr.Code, appErr.Message)
 	}
 
 	return r.sender.SendAppResponse(ctx, nodeID, requestID, appResponse)
@@ -26,9 +26,15 @@ var (
 	_ common.AppHandler    = (*Network)(nil)
 	_ NodeSampler          = (*peerSampler)(nil)
 
-	opLabel      = "op"
-	handlerLabel = "handlerID"
-	labelNames   = []string{opLabel, handlerLabel}
+	opLabel         = "op"
+	handlerLabel    = "handlerID"
+	directionLabel  = "direction"
+	codeLabel       = "code"
+	labelNames      = []string{opLabel, handlerLabel}
+	errorLabelNames = []string{directionLabel, handlerLabel, codeLabel}
+
+	sentDirection     = "sent"
+	receivedDirection = "received"
 )
 
 // ClientOption configures Client
@@ -79,11 +85,40 @@ func NewNetwork(
 			},
 			labelNames,
 		),
+		msgSize: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "msg_size",
+				Help:      "size of received messages (bytes)",
+				Buckets:   prometheus.ExponentialBuckets(64, 4, 8),
+			},
+			labelNames,
+		),
+		requestLatency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "request_latency",
+				Help:      "time between sending a request and receiving its response or failure (s)",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{handlerLabel},
+		),
+		appErrorCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "app_error_count",
+				Help:      "application errors sent and received (n)",
+			},
+			errorLabelNames,
+		),
 	}
 
 	err := errors.Join(
 		registerer.Register(metrics.msgTime),
 		registerer.Register(metrics.msgCount),
+		registerer.Register(metrics.msgSize),
+		registerer.Register(metrics.requestLatency),
+		registerer.Register(metrics.appErrorCount),
 	)
 	if err != nil {
 		return nil, err
@@ -230,7 +230,8 @@ func TestAppRequestFailed(t *testing.T) {
 	sender := common.FakeSender{
 		SentAppRequest: make(chan []byte, 1),
 	}
-	network, err := NewNetwork(logging.NoLog{}, sender, prometheus.NewRegistry(), "")
+	registry := prometheus.NewRegistry()
+	network, err := NewNetwork(logging.NoLog{}, sender, registry, "")
 	require.NoError(err)
 	client := network.NewClient(handlerID)
 
@@ -250,6 +251,17 @@ func TestAppRequestFailed(t *testing.T) {
 
 	require.NoError(network.AppRequestFailed(ctx, wantNodeID, 1, errFoo))
 	<-done
+
+	// The latency of failed requests is recorded.
+	families, err := registry.Gather()
+	require.NoError(err)
+	var numLatencySamples uint64
+	for _, family := range families {
+		if family.GetName() == "request_latency" {
+			numLatencySamples = family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	require.Equal(uint64(1), numLatencySamples)
 }
 
 // Tests that the Client callback is called on a successful response
@@ -14,6 +14,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/utils/heap"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
@@ -37,7 +38,8 @@ const (
 
 // Tracks the bandwidth of responses coming from peers,
 // preferring to contact peers with known good bandwidth, connecting
-// to new peers with an exponentially decaying probability.
+// to new peers with an exponentially decaying probability. Peers with a poor
+// reputation are only selected if no other peers are available.
 type PeerTracker struct {
 	// Lock to protect concurrent access to the peer tracker
 	lock sync.RWMutex
@@ -63,6 +65,7 @@ type PeerTracker struct {
 	log          logging.Logger
 	ignoredNodes set.Set[ids.NodeID]
 	minVersion   *version.Application
+	reputation   reputation.Scorer
 	metrics      peerTrackerMetrics
 }
 
@@ -78,6 +81,7 @@ func NewPeerTracker(
 	registerer prometheus.Registerer,
 	ignoredNodes set.Set[ids.NodeID],
 	minVersion *version.Application,
+	reputation reputation.Scorer,
 ) (*PeerTracker, error) {
 	t := &PeerTracker{
 		peerBandwidth: make(map[ids.NodeID]safemath.Averager),
@@ -88,6 +92,7 @@ func NewPeerTracker(
 		log:              log,
 		ignoredNodes:     ignoredNodes,
 		minVersion:       minVersion,
+		reputation:       reputation,
 		metrics: peerTrackerMetrics{
 			numTrackedPeers: prometheus.NewGauge(
 				prometheus.GaugeOpts{
@@ -160,13 +165,17 @@ func (p *PeerTracker) shouldSelectUntrackedPeer() bool {
 // With probability [1-randomPeerProbability] returns the peer in
 // [p.bandwidthHeap] with the highest bandwidth.
 //
+// If the peer chosen by the above strategy has a poor reputation, a random
+// tracked peer without a poor reputation is returned instead. Peers with a poor
+// reputation are only returned if there are no other peers.
+//
 // Returns false if there are no connected peers.
 func (p *PeerTracker) SelectPeer() (ids.NodeID, bool) {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 
 	if p.shouldSelectUntrackedPeer() {
-		if nodeID, ok := p.untrackedPeers.Peek(); ok {
+		if nodeID, ok := p.untrackedPeers.Peek(); ok && !p.reputation.IsPoor(nodeID) {
 			p.log.Debug("selecting peer",
 				zap.String("reason", "untracked"),
 				zap.Stringer("nodeID", nodeID),
@@ -179,7 +188,7 @@ func (p *PeerTracker) SelectPeer() (ids.NodeID, bool) {
 
 	useBandwidthHeap := rand.Float64() > randomPeerProbability // #nosec G404
 	if useBandwidthHeap {
-		if nodeID, bandwidth, ok := p.bandwidthHeap.Peek(); ok {
+		if nodeID, bandwidth, ok := p.bandwidthHeap.Peek(); ok && !p.reputation.IsPoor(nodeID) {
 			p.log.Debug("selecting peer",
 				zap.String("reason", "bandwidth"),
 				zap.Stringer("nodeID", nodeID),
@@ -188,7 +197,7 @@ func (p *PeerTracker) SelectPeer() (ids.NodeID, bool) {
 			return nodeID, true
 		}
 	} else {
-		if nodeID, ok := p.responsivePeers.Peek(); ok {
+		if nodeID, ok := p.responsivePeers.Peek(); ok && !p.reputation.IsPoor(nodeID) {
 			p.log.Debug("selecting peer",
 				zap.String("reason", "responsive"),
 				zap.Stringer("nodeID", nodeID),
@@ -197,7 +206,10 @@ func (p *PeerTracker) SelectPeer() (ids.NodeID, bool) {
 		}
 	}
 
-	if nodeID, ok := p.trackedPeers.Peek(); ok {
+	for nodeID := range p.trackedPeers {
+		if p.reputation.IsPoor(nodeID) {
+			continue
+		}
 		p.log.Debug("selecting peer",
 			zap.String("reason", "tracked"),
 			zap.Stringer("nodeID", nodeID),
@@ -206,6 +218,23 @@ func (p *PeerTracker) SelectPeer() (ids.NodeID, bool) {
 		return nodeID, true
 	}
 
+	// Every peer has a poor reputation, but a poor peer is still better than
+	// no peer.
+	if nodeID, ok := p.trackedPeers.Peek(); ok {
+		p.log.Debug("selecting peer",
+			zap.String("reason", "poor reputation"),
+			zap.Stringer("nodeID", nodeID),
+		)
+		return nodeID, true
+	}
+	if nodeID, ok := p.untrackedPeers.Peek(); ok {
+		p.log.Debug("selecting peer",
+			zap.String("reason", "poor reputation"),
+			zap.Stringer("nodeID", nodeID),
+		)
+		return nodeID, true
+	}
+
 	// We're not connected to any peers.
 	return ids.EmptyNodeID, false
 }
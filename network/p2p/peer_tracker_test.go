@@ -10,7 +10,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/version"
 )
 
@@ -22,6 +24,7 @@ func TestPeerTracker(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		nil,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -102,3 +105,55 @@ func TestPeerTracker(t *testing.T) {
 	require.True(ok)
 	require.Falsef(responsive, "expected connecting to a non-responsive peer, but got a peer that was responsive: peer %s", peer)
 }
+
+type poorScorer struct {
+	poor set.Set[ids.NodeID]
+}
+
+func (s poorScorer) Score(nodeID ids.NodeID) float64 {
+	if s.poor.Contains(nodeID) {
+		return 0
+	}
+	return 1
+}
+
+func (s poorScorer) IsPoor(nodeID ids.NodeID) bool {
+	return s.poor.Contains(nodeID)
+}
+
+func TestPeerTrackerPoorReputation(t *testing.T) {
+	require := require.New(t)
+
+	poorPeer := ids.GenerateTestNodeID()
+	goodPeer := ids.GenerateTestNodeID()
+	scorer := poorScorer{poor: set.Of(poorPeer)}
+	p, err := NewPeerTracker(
+		logging.NoLog{},
+		"",
+		prometheus.NewRegistry(),
+		nil,
+		nil,
+		scorer,
+	)
+	require.NoError(err)
+
+	// If the only peer has a poor reputation, it should still be selected.
+	p.Connected(poorPeer, version.CurrentApp)
+	peer, ok := p.SelectPeer()
+	require.True(ok)
+	require.Equal(poorPeer, peer)
+
+	// The poor peer has a higher bandwidth than the good peer, but should not
+	// be selected while the good peer is available.
+	p.Connected(goodPeer, version.CurrentApp)
+	p.RegisterRequest(poorPeer)
+	p.RegisterResponse(poorPeer, 100)
+	p.RegisterRequest(goodPeer)
+	p.RegisterResponse(goodPeer, 1)
+
+	for i := 0; i < 100; i++ {
+		peer, ok := p.SelectPeer()
+		require.True(ok)
+		require.Equal(goodPeer, peer)
+	}
+}
@@ -14,9 +14,11 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/proto/pb/sdk"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
@@ -31,6 +33,8 @@ var (
 type pendingAppRequest struct {
 	handlerID string
 	callback  AppResponseCallback
+	// start is the time the request was sent
+	start time.Time
 }
 
 type pendingCrossChainAppRequest struct {
@@ -45,8 +49,11 @@ type meteredHandler struct {
 }
 
 type metrics struct {
-	msgTime  *prometheus.GaugeVec
-	msgCount *prometheus.CounterVec
+	msgTime        *prometheus.GaugeVec     // op + handlerID
+	msgCount       *prometheus.CounterVec   // op + handlerID
+	msgSize        *prometheus.HistogramVec // op + handlerID
+	requestLatency *prometheus.HistogramVec // handlerID
+	appErrorCount  *prometheus.CounterVec   // direction + handlerID + code
 }
 
 func (m *metrics) observe(labels prometheus.Labels, start time.Time) error {
@@ -65,6 +72,45 @@ func (m *metrics) observe(labels prometheus.Labels, start time.Time) error {
 	return nil
 }
 
+func (m *metrics) observeSize(op message.Op, handlerID string, size int) error {
+	metricSize, err := m.msgSize.GetMetricWith(prometheus.Labels{
+		opLabel:      op.String(),
+		handlerLabel: handlerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	metricSize.Observe(float64(size))
+	return nil
+}
+
+func (m *metrics) observeLatency(handlerID string, start time.Time) error {
+	metricLatency, err := m.requestLatency.GetMetricWith(prometheus.Labels{
+		handlerLabel: handlerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	metricLatency.Observe(time.Since(start).Seconds())
+	return nil
+}
+
+func (m *metrics) observeAppError(direction string, handlerID string, code int32) error {
+	metricCount, err := m.appErrorCount.GetMetricWith(prometheus.Labels{
+		directionLabel: direction,
+		handlerLabel:   handlerID,
+		codeLabel:      strconv.FormatInt(int64(code), 10),
+	})
+	if err != nil {
+		return err
+	}
+
+	metricCount.Inc()
+	return nil
+}
+
 // router routes incoming application messages to the corresponding registered
 // app handler. App messages must be made using the registered handler's
 // corresponding Client.
@@ -78,6 +124,8 @@ type router struct {
 	pendingAppRequests           map[uint32]pendingAppRequest
 	pendingCrossChainAppRequests map[uint32]pendingCrossChainAppRequest
 	requestID                    uint32
+	// requestID -> stream opened by this node with that request
+	streams map[uint32]*ResponseIterator
 }
 
 // newRouter returns a new instance of Router
@@ -93,6 +141,7 @@ func newRouter(
 		handlers:                     make(map[uint64]*meteredHandler),
 		pendingAppRequests:           make(map[uint32]pendingAppRequest),
 		pendingCrossChainAppRequests: make(map[uint32]pendingCrossChainAppRequest),
+		streams:                      make(map[uint32]*ResponseIterator),
 		// invariant: sdk uses odd-numbered requestIDs
 		requestID: 1,
 	}
@@ -108,10 +157,12 @@ func (r *router) addHandler(handlerID uint64, handler Handler) error {
 
 	r.handlers[handlerID] = &meteredHandler{
 		responder: &responder{
-			Handler:   handler,
-			handlerID: handlerID,
-			log:       r.log,
-			sender:    r.sender,
+			Handler:      handler,
+			handlerID:    handlerID,
+			handlerIDStr: strconv.FormatUint(handlerID, 10),
+			log:          r.log,
+			sender:       r.sender,
+			metrics:      r.metrics,
 		},
 		metrics: r.metrics,
 	}
@@ -142,6 +193,10 @@ func (r *router) AppRequest(ctx context.Context, nodeID ids.NodeID, requestID ui
 		return r.sender.SendAppError(ctx, nodeID, requestID, ErrUnregisteredHandler.Code, ErrUnregisteredHandler.Message)
 	}
 
+	if err := r.metrics.observeSize(message.AppRequestOp, handlerID, len(request)); err != nil {
+		return err
+	}
+
 	// call the corresponding handler and send back a response to nodeID
 	if err := handler.AppRequest(ctx, nodeID, requestID, deadline, parsedMsg); err != nil {
 		return err
@@ -171,6 +226,14 @@ func (r *router) AppRequestFailed(ctx context.Context, nodeID ids.NodeID, reques
 
 	pending.callback(ctx, nodeID, nil, appErr)
 
+	err := errors.Join(
+		r.metrics.observeAppError(receivedDirection, pending.handlerID, appErr.Code),
+		r.metrics.observeLatency(pending.handlerID, pending.start),
+	)
+	if err != nil {
+		return err
+	}
+
 	return r.metrics.observe(
 		prometheus.Labels{
 			opLabel:      message.AppErrorOp.String(),
@@ -195,6 +258,14 @@ func (r *router) AppResponse(ctx context.Context, nodeID ids.NodeID, requestID u
 
 	pending.callback(ctx, nodeID, response, nil)
 
+	err := errors.Join(
+		r.metrics.observeSize(message.AppResponseOp, pending.handlerID, len(response)),
+		r.metrics.observeLatency(pending.handlerID, pending.start),
+	)
+	if err != nil {
+		return err
+	}
+
 	return r.metrics.observe(
 		prometheus.Labels{
 			opLabel:      message.AppResponseOp.String(),
@@ -211,6 +282,10 @@ func (r *router) AppResponse(ctx context.Context, nodeID ids.NodeID, requestID u
 // considered fatal
 func (r *router) AppGossip(ctx context.Context, nodeID ids.NodeID, gossip []byte) error {
 	start := time.Now()
+	if handlerID, msg, ok := ParseMessage(gossip); ok && handlerID == StreamChunkHandlerID {
+		return r.streamChunk(nodeID, msg, start)
+	}
+
 	parsedMsg, handler, handlerID, ok := r.parse(gossip)
 	if !ok {
 		r.log.Debug("received message for unregistered handler",
@@ -221,6 +296,10 @@ func (r *router) AppGossip(ctx context.Context, nodeID ids.NodeID, gossip []byte
 		return nil
 	}
 
+	if err := r.metrics.observeSize(message.AppGossipOp, handlerID, len(gossip)); err != nil {
+		return err
+	}
+
 	handler.AppGossip(ctx, nodeID, parsedMsg)
 
 	return r.metrics.observe(
@@ -232,6 +311,43 @@ func (r *router) AppGossip(ctx context.Context, nodeID ids.NodeID, gossip []byte
 	)
 }
 
+// streamChunk routes a chunk pushed by a StreamingHandler to the stream it
+// belongs to. The chunk is dropped if it doesn't belong to an open stream.
+func (r *router) streamChunk(nodeID ids.NodeID, chunkBytes []byte, start time.Time) error {
+	handlerID := strconv.FormatUint(StreamChunkHandlerID, 10)
+	if err := r.metrics.observeSize(message.AppGossipOp, handlerID, len(chunkBytes)); err != nil {
+		return err
+	}
+
+	chunk := &sdk.StreamChunk{}
+	if err := proto.Unmarshal(chunkBytes, chunk); err != nil {
+		r.log.Debug("failed to unmarshal stream chunk",
+			zap.Stringer("nodeID", nodeID),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	stream, ok := r.stream(nodeID, chunk.RequestId)
+	if !ok {
+		r.log.Debug("received chunk for unknown stream",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", chunk.RequestId),
+			zap.Uint32("index", chunk.Index),
+		)
+		return nil
+	}
+	stream.deliver(chunk)
+
+	return r.metrics.observe(
+		prometheus.Labels{
+			opLabel:      message.AppGossipOp.String(),
+			handlerLabel: handlerID,
+		},
+		start,
+	)
+}
+
 // CrossChainAppRequest routes a CrossChainAppRequest message to a Handler
 // based on the handler prefix. The message is dropped if no matching handler
 // can be found.
@@ -344,6 +460,29 @@ func (r *router) parse(prefixedMsg []byte) ([]byte, *meteredHandler, string, boo
 	return msg, handler, handlerStr, ok
 }
 
+// stream returns the stream opened with [nodeID] by the request with
+// [requestID].
+//
+// Invariant: Assumes [r.lock] isn't held.
+func (r *router) stream(nodeID ids.NodeID, requestID uint32) (*ResponseIterator, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	stream, ok := r.streams[requestID]
+	if !ok || stream.nodeID != nodeID {
+		return nil, false
+	}
+	return stream, true
+}
+
+// Invariant: Assumes [r.lock] isn't held.
+func (r *router) clearStream(requestID uint32) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.streams, requestID)
+}
+
 // Invariant: Assumes [r.lock] isn't held.
 func (r *router) clearAppRequest(requestID uint32) (pendingAppRequest, bool) {
 	r.lock.Lock()
@@ -0,0 +1,565 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package p2p
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/proto/pb/sdk"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+const (
+	// streamWindow is the maximum number of chunks of a stream that are pushed
+	// to the requester past the chunks it acknowledged reading.
+	streamWindow = 4
+	// streamRetryInterval is how long a requester waits for the next chunk of
+	// a stream before acknowledging the chunks it read again.
+	//
+	// Chunks and acks are gossiped, so they may be dropped. A responder that
+	// receives an ack that doesn't acknowledge any new chunks pushes all of the
+	// unacknowledged chunks again.
+	streamRetryInterval = time.Second
+)
+
+var (
+	_ Handler = (*StreamingHandler)(nil)
+
+	errCrossChainStreamsNotSupported = errors.New("cross-chain streams not supported")
+	errStreamClosed                  = errors.New("stream closed")
+)
+
+// StreamHandler is the server-side logic for application protocols that
+// respond to a request with an ordered stream of chunks.
+type StreamHandler interface {
+	// AppRequestStream is called when a peer opens a stream.
+	// Returns the stream of chunks corresponding to [requestBytes] or an
+	// application-defined error.
+	AppRequestStream(
+		ctx context.Context,
+		nodeID ids.NodeID,
+		deadline time.Time,
+		requestBytes []byte,
+	) (ResponseStream, *common.AppError)
+}
+
+// ResponseStream produces the chunks of a streamed response.
+type ResponseStream interface {
+	// Next returns the next chunk of the response and true if it is the last
+	// chunk of the response. Every chunk must fit in a single AppGossip
+	// message.
+	Next(ctx context.Context) ([]byte, bool, *common.AppError)
+	// Close is called once the stream will no longer be read from, either
+	// because it was read entirely or because it was aborted.
+	Close()
+}
+
+// NewStreamingHandler returns a Handler that serves the streams opened by
+// Client.AppRequestStream.
+//
+// A stream is opened by an AppRequest, which is answered immediately. The
+// chunks of the stream are then pushed to the requester over [network], at
+// most [streamWindow] chunks ahead of the chunks the requester acknowledged
+// reading. [throttler] is checked before every chunk is produced. A throttled
+// stream fails with ErrThrottled.
+//
+// At most [maxStreamsPerNode] streams may be open with a node at once. Streams
+// that aren't acknowledged for [streamTimeout] are closed.
+func NewStreamingHandler(
+	handler StreamHandler,
+	network *Network,
+	throttler Throttler,
+	maxStreamsPerNode int,
+	streamTimeout time.Duration,
+	log logging.Logger,
+) *StreamingHandler {
+	return &StreamingHandler{
+		handler:           handler,
+		client:            network.NewClient(StreamChunkHandlerID),
+		throttler:         throttler,
+		maxStreamsPerNode: maxStreamsPerNode,
+		streamTimeout:     streamTimeout,
+		log:               log,
+		streams:           make(map[ids.NodeID]map[uint32]*serverStream),
+	}
+}
+
+// StreamingHandler serves streamed responses produced by a StreamHandler.
+type StreamingHandler struct {
+	handler           StreamHandler
+	client            *Client
+	throttler         Throttler
+	maxStreamsPerNode int
+	streamTimeout     time.Duration
+	log               logging.Logger
+
+	lock sync.Mutex
+	// nodeID -> requestID -> stream
+	streams map[ids.NodeID]map[uint32]*serverStream
+}
+
+type serverStream struct {
+	// stream and cancel are set once the stream is created and are then only
+	// accessed by the goroutine pushing its chunks
+	stream ResponseStream
+	cancel context.CancelFunc
+	// acked is signalled whenever an ack is received for this stream
+	acked chan struct{}
+
+	lock sync.Mutex
+	// numRead is the number of chunks the requester acknowledged reading
+	numRead uint32
+	// retransmit is true if the requester asked for the unacknowledged chunks
+	// to be pushed again
+	retransmit bool
+	// closed is true if the requester will no longer read from the stream
+	closed bool
+}
+
+// AppGossip handles the acks of the requesters of open streams.
+func (s *StreamingHandler) AppGossip(_ context.Context, nodeID ids.NodeID, gossipBytes []byte) {
+	ack := &sdk.StreamAck{}
+	if err := proto.Unmarshal(gossipBytes, ack); err != nil {
+		s.log.Debug("failed to unmarshal stream ack",
+			zap.Stringer("nodeID", nodeID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	s.lock.Lock()
+	stream, ok := s.streams[nodeID][ack.RequestId]
+	s.lock.Unlock()
+	if !ok {
+		s.log.Debug("received ack for unknown stream",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", ack.RequestId),
+		)
+		return
+	}
+
+	stream.lock.Lock()
+	switch {
+	case ack.Closed:
+		stream.closed = true
+	case ack.NumRead > stream.numRead:
+		stream.numRead = ack.NumRead
+	default:
+		// The requester is still waiting for a chunk that was already pushed.
+		stream.retransmit = true
+	}
+	stream.lock.Unlock()
+
+	select {
+	case stream.acked <- struct{}{}:
+	default:
+	}
+}
+
+// AppRequest opens a stream. The chunks of the stream are pushed to [nodeID]
+// asynchronously.
+func (s *StreamingHandler) AppRequest(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	deadline time.Time,
+	requestBytes []byte,
+) ([]byte, *common.AppError) {
+	request := &sdk.StreamRequest{}
+	if err := proto.Unmarshal(requestBytes, request); err != nil {
+		s.log.Debug("failed to unmarshal stream request",
+			zap.Stringer("nodeID", nodeID),
+			zap.Error(err),
+		)
+		return nil, ErrInvalidRequest
+	}
+
+	// The slot of the stream is reserved before the stream is created so that
+	// a slow handler doesn't block the other streams.
+	stream := &serverStream{
+		acked: make(chan struct{}, 1),
+	}
+	s.lock.Lock()
+	nodeStreams := s.streams[nodeID]
+	if _, ok := nodeStreams[request.RequestId]; ok {
+		s.lock.Unlock()
+		return nil, ErrInvalidRequest
+	}
+	if len(nodeStreams) >= s.maxStreamsPerNode {
+		s.lock.Unlock()
+		return nil, ErrTooManyStreams
+	}
+	if nodeStreams == nil {
+		nodeStreams = make(map[uint32]*serverStream)
+		s.streams[nodeID] = nodeStreams
+	}
+	nodeStreams[request.RequestId] = stream
+	s.lock.Unlock()
+
+	responseStream, appErr := s.handler.AppRequestStream(ctx, nodeID, deadline, request.Request)
+	if appErr != nil {
+		s.remove(nodeID, request.RequestId)
+		return nil, appErr
+	}
+
+	// The stream outlives the request that opened it.
+	streamCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stream.stream = responseStream
+	stream.cancel = cancel
+
+	go s.push(streamCtx, nodeID, request.RequestId, stream)
+	return nil, nil
+}
+
+func (*StreamingHandler) CrossChainAppRequest(context.Context, ids.ID, time.Time, []byte) ([]byte, error) {
+	return nil, errCrossChainStreamsNotSupported
+}
+
+// push pushes the chunks of [stream] to [nodeID] until the requester read the
+// entire stream, closed it, or stopped acknowledging chunks.
+func (s *StreamingHandler) push(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	requestID uint32,
+	stream *serverStream,
+) {
+	defer s.close(nodeID, requestID, stream)
+
+	var (
+		// chunks that were pushed but not yet acknowledged
+		pending []*sdk.StreamChunk
+		// index of the first chunk in [pending]
+		acked uint32
+		// done is true once the final chunk of the stream was produced
+		done bool
+	)
+	for {
+		stream.lock.Lock()
+		numRead, retransmit, closed := stream.numRead, stream.retransmit, stream.closed
+		stream.retransmit = false
+		stream.lock.Unlock()
+
+		if closed {
+			return
+		}
+
+		// Chunks that were never pushed can't have been read.
+		numRead = min(numRead, acked+uint32(len(pending)))
+		pending = pending[numRead-acked:]
+		acked = numRead
+		if done && len(pending) == 0 {
+			return
+		}
+
+		if retransmit {
+			for _, chunk := range pending {
+				s.send(ctx, nodeID, chunk)
+			}
+		}
+		for !done && len(pending) < streamWindow {
+			chunk := s.next(ctx, nodeID, requestID, acked+uint32(len(pending)), stream.stream)
+			done = chunk.Last || chunk.Error != nil
+			pending = append(pending, chunk)
+			s.send(ctx, nodeID, chunk)
+		}
+
+		select {
+		case <-stream.acked:
+		case <-time.After(s.streamTimeout):
+			s.log.Debug("closing expired stream",
+				zap.Stringer("nodeID", nodeID),
+				zap.Uint32("requestID", requestID),
+			)
+			return
+		}
+	}
+}
+
+// next produces the chunk at [index] of [stream].
+func (s *StreamingHandler) next(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	requestID uint32,
+	index uint32,
+	stream ResponseStream,
+) *sdk.StreamChunk {
+	chunk := &sdk.StreamChunk{
+		RequestId: requestID,
+		Index:     index,
+	}
+	if !s.throttler.Handle(nodeID) {
+		chunk.Error = &sdk.StreamError{
+			Code:    ErrThrottled.Code,
+			Message: ErrThrottled.Message,
+		}
+		return chunk
+	}
+
+	chunkBytes, last, appErr := stream.Next(ctx)
+	if appErr != nil {
+		chunk.Error = &sdk.StreamError{
+			Code:    appErr.Code,
+			Message: appErr.Message,
+		}
+		return chunk
+	}
+	chunk.Chunk = chunkBytes
+	chunk.Last = last
+	return chunk
+}
+
+func (s *StreamingHandler) send(ctx context.Context, nodeID ids.NodeID, chunk *sdk.StreamChunk) {
+	chunkBytes, err := proto.Marshal(chunk)
+	if err != nil {
+		s.log.Error("failed to marshal stream chunk",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", chunk.RequestId),
+			zap.Uint32("index", chunk.Index),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if err := s.client.AppGossip(ctx, common.SendConfig{NodeIDs: set.Of(nodeID)}, chunkBytes); err != nil {
+		s.log.Debug("failed to push stream chunk",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", chunk.RequestId),
+			zap.Uint32("index", chunk.Index),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *StreamingHandler) close(nodeID ids.NodeID, requestID uint32, stream *serverStream) {
+	s.remove(nodeID, requestID)
+	stream.cancel()
+	stream.stream.Close()
+}
+
+// remove releases the slot of the stream opened by [nodeID] with [requestID].
+func (s *StreamingHandler) remove(nodeID ids.NodeID, requestID uint32) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	nodeStreams := s.streams[nodeID]
+	delete(nodeStreams, requestID)
+	if len(nodeStreams) == 0 {
+		delete(s.streams, nodeID)
+	}
+}
+
+// AppRequestStream opens a stream with [nodeID] for the response to
+// [appRequestBytes]. The server must have registered a StreamingHandler for
+// this Client's handler ID.
+//
+// The stream is read with the returned ResponseIterator. The stream is
+// aborted once [ctx] is cancelled or its deadline passes.
+func (c *Client) AppRequestStream(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	appRequestBytes []byte,
+) (*ResponseIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &ResponseIterator{
+		client:   c,
+		nodeID:   nodeID,
+		ctx:      ctx,
+		cancel:   cancel,
+		chunks:   make(chan *sdk.StreamChunk, streamWindow),
+		failed:   make(chan error, 1),
+		received: make(map[uint32]*sdk.StreamChunk),
+	}
+	// The stream is opened with an empty response, so only failures need to
+	// be handled.
+	onResponse := func(_ context.Context, _ ids.NodeID, _ []byte, err error) {
+		if err != nil {
+			it.failed <- err
+		}
+	}
+
+	c.router.lock.Lock()
+	defer c.router.lock.Unlock()
+
+	// The stream is identified by the request ID of the request that opens
+	// it.
+	it.requestID = c.router.requestID
+	requestBytes, err := proto.Marshal(&sdk.StreamRequest{
+		RequestId: it.requestID,
+		Request:   appRequestBytes,
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if err := c.appRequest(
+		context.WithoutCancel(ctx),
+		nodeID,
+		PrefixMessage(c.handlerPrefix, requestBytes),
+		onResponse,
+	); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	c.router.streams[it.requestID] = it
+	return it, nil
+}
+
+// ResponseIterator reads the chunks of a stream opened by
+// Client.AppRequestStream.
+//
+// ResponseIterator is not safe for concurrent use.
+type ResponseIterator struct {
+	client    *Client
+	nodeID    ids.NodeID
+	requestID uint32
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	// chunks pushed by the server
+	chunks chan *sdk.StreamChunk
+	// failed receives the error of the request that opened the stream
+	failed chan error
+	// chunks that were received but not yet read
+	received map[uint32]*sdk.StreamChunk
+	// index of the next chunk to read
+	nextRead uint32
+	// finished is true once the last chunk was read
+	finished bool
+	// released is true once the stream was unregistered
+	released bool
+
+	chunk []byte
+	err   error
+}
+
+// Next blocks until the next chunk of the stream is available. Returns false
+// once the stream was read entirely, failed, or was aborted.
+func (r *ResponseIterator) Next() bool {
+	for r.err == nil && !r.finished {
+		if err := r.ctx.Err(); err != nil {
+			r.err = err
+			break
+		}
+
+		if chunk, ok := r.received[r.nextRead]; ok {
+			delete(r.received, r.nextRead)
+			if chunk.Error != nil {
+				r.err = &common.AppError{
+					Code:    chunk.Error.Code,
+					Message: chunk.Error.Message,
+				}
+				break
+			}
+
+			r.chunk = chunk.Chunk
+			r.finished = chunk.Last
+			r.nextRead++
+			r.ack(false)
+			if r.finished {
+				r.release()
+			}
+			return true
+		}
+
+		select {
+		case chunk := <-r.chunks:
+			// Only chunks within the window past the next chunk to read can
+			// have been pushed by a correct server.
+			if chunk.Index >= r.nextRead && chunk.Index-r.nextRead < streamWindow {
+				r.received[chunk.Index] = chunk
+			}
+		case err := <-r.failed:
+			r.err = err
+		case <-r.ctx.Done():
+			r.err = r.ctx.Err()
+		case <-time.After(streamRetryInterval):
+			// Either the next chunk or the last ack was dropped, so the read
+			// chunks are acknowledged again to have the server push the next
+			// chunks again.
+			r.ack(false)
+		}
+	}
+	r.release()
+	r.chunk = nil
+	return false
+}
+
+// Chunk returns the chunk read by the last call to Next.
+func (r *ResponseIterator) Chunk() []byte {
+	return r.chunk
+}
+
+// Err returns the error that caused Next to return false, if any.
+func (r *ResponseIterator) Err() error {
+	return r.err
+}
+
+// Close aborts the stream.
+func (r *ResponseIterator) Close() {
+	if r.err == nil && !r.finished {
+		r.err = errStreamClosed
+	}
+	r.release()
+}
+
+// deliver is called by the router when a chunk of this stream is received.
+// The chunk is dropped if the reader is too far behind, in which case it is
+// pushed again once the reader catches up.
+func (r *ResponseIterator) deliver(chunk *sdk.StreamChunk) {
+	select {
+	case r.chunks <- chunk:
+	default:
+	}
+}
+
+// release unregisters the stream and notifies the server if the stream wasn't
+// read entirely.
+func (r *ResponseIterator) release() {
+	if r.released {
+		return
+	}
+	r.released = true
+
+	r.cancel()
+	r.client.router.clearStream(r.requestID)
+	if !r.finished {
+		r.ack(true)
+	}
+}
+
+// ack acknowledges the chunks that were read. If [closed] is true, the server
+// is notified that no more chunks will be read.
+func (r *ResponseIterator) ack(closed bool) {
+	ackBytes, err := proto.Marshal(&sdk.StreamAck{
+		RequestId: r.requestID,
+		NumRead:   r.nextRead,
+		Closed:    closed,
+	})
+	if err != nil {
+		r.client.router.log.Error("failed to marshal stream ack",
+			zap.Stringer("nodeID", r.nodeID),
+			zap.Uint32("requestID", r.requestID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if err := r.client.AppGossip(r.ctx, common.SendConfig{NodeIDs: set.Of(r.nodeID)}, ackBytes); err != nil {
+		r.client.router.log.Debug("failed to acknowledge stream chunks",
+			zap.Stringer("nodeID", r.nodeID),
+			zap.Uint32("requestID", r.requestID),
+			zap.Error(err),
+		)
+	}
+}
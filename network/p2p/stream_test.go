@@ -0,0 +1,510 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package p2p
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/proto/pb/sdk"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var (
+	_ ResponseStream   = (*testResponseStream)(nil)
+	_ common.AppSender = (*testStreamSender)(nil)
+)
+
+type testResponseStream struct {
+	chunks    [][]byte
+	err       *common.AppError
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newTestResponseStream(err *common.AppError, chunks ...[]byte) *testResponseStream {
+	return &testResponseStream{
+		chunks: chunks,
+		err:    err,
+		closed: make(chan struct{}),
+	}
+}
+
+func (t *testResponseStream) Next(context.Context) ([]byte, bool, *common.AppError) {
+	if len(t.chunks) == 0 {
+		return nil, false, t.err
+	}
+	chunk := t.chunks[0]
+	t.chunks = t.chunks[1:]
+	return chunk, len(t.chunks) == 0 && t.err == nil, nil
+}
+
+func (t *testResponseStream) Close() {
+	t.closeOnce.Do(func() {
+		close(t.closed)
+	})
+}
+
+type testStreamHandler func(ctx context.Context, nodeID ids.NodeID, deadline time.Time, requestBytes []byte) (ResponseStream, *common.AppError)
+
+func (t testStreamHandler) AppRequestStream(ctx context.Context, nodeID ids.NodeID, deadline time.Time, requestBytes []byte) (ResponseStream, *common.AppError) {
+	return t(ctx, nodeID, deadline, requestBytes)
+}
+
+// testStreamSender delivers the messages sent by [nodeID] to [peer] in order.
+// Gossip for which [drop] returns true is dropped.
+type testStreamSender struct {
+	common.FakeSender
+
+	nodeID ids.NodeID
+	peer   *Network
+	drop   func(gossipBytes []byte) bool
+	queue  chan<- func()
+}
+
+func (t *testStreamSender) SendAppRequest(_ context.Context, _ set.Set[ids.NodeID], requestID uint32, requestBytes []byte) error {
+	t.queue <- func() {
+		if err := t.peer.AppRequest(context.Background(), t.nodeID, requestID, time.Time{}, requestBytes); err != nil {
+			panic(err)
+		}
+	}
+	return nil
+}
+
+func (t *testStreamSender) SendAppResponse(_ context.Context, _ ids.NodeID, requestID uint32, responseBytes []byte) error {
+	t.queue <- func() {
+		if err := t.peer.AppResponse(context.Background(), t.nodeID, requestID, responseBytes); err != nil {
+			panic(err)
+		}
+	}
+	return nil
+}
+
+func (t *testStreamSender) SendAppError(_ context.Context, _ ids.NodeID, requestID uint32, errorCode int32, errorMessage string) error {
+	appErr := &common.AppError{
+		Code:    errorCode,
+		Message: errorMessage,
+	}
+	t.queue <- func() {
+		if err := t.peer.AppRequestFailed(context.Background(), t.nodeID, requestID, appErr); err != nil {
+			panic(err)
+		}
+	}
+	return nil
+}
+
+func (t *testStreamSender) SendAppGossip(_ context.Context, _ common.SendConfig, gossipBytes []byte) error {
+	if t.drop != nil && t.drop(gossipBytes) {
+		return nil
+	}
+	t.queue <- func() {
+		if err := t.peer.AppGossip(context.Background(), t.nodeID, gossipBytes); err != nil {
+			panic(err)
+		}
+	}
+	return nil
+}
+
+// newStreamClient returns a Client connected to the handler returned by
+// [newHandler]. Gossip sent by the server for which [drop] returns true is
+// dropped.
+func newStreamClient(t *testing.T, newHandler func(*Network) Handler, drop func([]byte) bool) *Client {
+	t.Helper()
+	require := require.New(t)
+
+	queue := make(chan func(), 1024)
+	clientSender := &testStreamSender{
+		nodeID: ids.GenerateTestNodeID(),
+		queue:  queue,
+	}
+	client, err := NewNetwork(logging.NoLog{}, clientSender, prometheus.NewRegistry(), "")
+	require.NoError(err)
+
+	// Streams are opened with [ids.EmptyNodeID], so the chunks pushed by the
+	// server must come from it.
+	serverSender := &testStreamSender{
+		nodeID: ids.EmptyNodeID,
+		peer:   client,
+		drop:   drop,
+		queue:  queue,
+	}
+	server, err := NewNetwork(logging.NoLog{}, serverSender, prometheus.NewRegistry(), "")
+	require.NoError(err)
+	require.NoError(server.AddHandler(handlerID, newHandler(server)))
+	clientSender.peer = server
+
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		close(done)
+	})
+	go func() {
+		for {
+			select {
+			case deliver := <-queue:
+				deliver()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return client.NewClient(handlerID)
+}
+
+func TestAppRequestStream(t *testing.T) {
+	tests := []struct {
+		name           string
+		stream         *testResponseStream
+		handlerErr     *common.AppError
+		throttleLimit  int
+		expectedChunks [][]byte
+		expectedErr    error
+	}{
+		{
+			name:           "single chunk",
+			stream:         newTestResponseStream(nil, []byte{0}),
+			throttleLimit:  10,
+			expectedChunks: [][]byte{{0}},
+		},
+		{
+			name: "more chunks than the window",
+			stream: newTestResponseStream(
+				nil,
+				[]byte{0}, []byte{1}, []byte{2}, []byte{3}, []byte{4},
+				[]byte{5}, []byte{6}, []byte{7}, []byte{8}, []byte{9},
+			),
+			throttleLimit:  10,
+			expectedChunks: [][]byte{{0}, {1}, {2}, {3}, {4}, {5}, {6}, {7}, {8}, {9}},
+		},
+		{
+			name:          "handler error",
+			handlerErr:    errFoo,
+			throttleLimit: 10,
+			expectedErr:   errFoo,
+		},
+		{
+			name:           "stream error",
+			stream:         newTestResponseStream(errFoo, []byte{0}, []byte{1}),
+			throttleLimit:  10,
+			expectedChunks: [][]byte{{0}, {1}},
+			expectedErr:    errFoo,
+		},
+		{
+			name:           "throttled per chunk",
+			stream:         newTestResponseStream(nil, []byte{0}, []byte{1}, []byte{2}, []byte{3}),
+			throttleLimit:  2,
+			expectedChunks: [][]byte{{0}, {1}},
+			expectedErr:    ErrThrottled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			wantRequest := []byte("request")
+			client := newStreamClient(
+				t,
+				func(network *Network) Handler {
+					return NewStreamingHandler(
+						testStreamHandler(func(_ context.Context, _ ids.NodeID, _ time.Time, requestBytes []byte) (ResponseStream, *common.AppError) {
+							require.Equal(wantRequest, requestBytes)
+							if tt.handlerErr != nil {
+								return nil, tt.handlerErr
+							}
+							return tt.stream, nil
+						}),
+						network,
+						NewSlidingWindowThrottler(time.Minute, tt.throttleLimit),
+						1,
+						time.Minute,
+						logging.NoLog{},
+					)
+				},
+				nil,
+			)
+			it, err := client.AppRequestStream(context.Background(), ids.EmptyNodeID, wantRequest)
+			require.NoError(err)
+
+			var chunks [][]byte
+			for it.Next() {
+				chunks = append(chunks, it.Chunk())
+			}
+			require.Equal(tt.expectedChunks, chunks)
+			require.ErrorIs(it.Err(), tt.expectedErr)
+			if tt.stream != nil {
+				<-tt.stream.closed
+			}
+		})
+	}
+}
+
+func TestAppRequestStreamCancelled(t *testing.T) {
+	require := require.New(t)
+
+	stream := newTestResponseStream(nil, []byte{0}, []byte{1}, []byte{2}, []byte{3}, []byte{4}, []byte{5})
+	client := newStreamClient(
+		t,
+		func(network *Network) Handler {
+			return NewStreamingHandler(
+				testStreamHandler(func(context.Context, ids.NodeID, time.Time, []byte) (ResponseStream, *common.AppError) {
+					return stream, nil
+				}),
+				network,
+				NewSlidingWindowThrottler(time.Minute, 10),
+				1,
+				time.Minute,
+				logging.NoLog{},
+			)
+		},
+		nil,
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	it, err := client.AppRequestStream(ctx, ids.EmptyNodeID, nil)
+	require.NoError(err)
+	require.True(it.Next())
+
+	cancel()
+	require.False(it.Next())
+	require.ErrorIs(it.Err(), context.Canceled)
+
+	// The server releases the stream once the client aborts it.
+	<-stream.closed
+
+	it.Close()
+	require.False(it.Next())
+}
+
+func TestAppRequestStreamDroppedChunk(t *testing.T) {
+	require := require.New(t)
+
+	var dropped bool
+	client := newStreamClient(
+		t,
+		func(network *Network) Handler {
+			return NewStreamingHandler(
+				testStreamHandler(func(context.Context, ids.NodeID, time.Time, []byte) (ResponseStream, *common.AppError) {
+					return newTestResponseStream(nil, []byte{0}, []byte{1}, []byte{2}), nil
+				}),
+				network,
+				NewSlidingWindowThrottler(time.Minute, 10),
+				1,
+				time.Minute,
+				logging.NoLog{},
+			)
+		},
+		func(gossipBytes []byte) bool {
+			// Drop the first push of the second chunk.
+			_, chunkBytes, ok := ParseMessage(gossipBytes)
+			require.True(ok)
+			chunk := &sdk.StreamChunk{}
+			require.NoError(proto.Unmarshal(chunkBytes, chunk))
+			if chunk.Index != 1 || dropped {
+				return false
+			}
+			dropped = true
+			return true
+		},
+	)
+	it, err := client.AppRequestStream(context.Background(), ids.EmptyNodeID, nil)
+	require.NoError(err)
+
+	var chunks [][]byte
+	for it.Next() {
+		chunks = append(chunks, it.Chunk())
+	}
+	require.NoError(it.Err())
+	require.Equal([][]byte{{0}, {1}, {2}}, chunks)
+	require.True(dropped)
+}
+
+func TestStreamingHandler(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	sender := &common.FakeSender{
+		SentAppGossip: make(chan []byte, 2*streamWindow),
+	}
+	network, err := NewNetwork(logging.NoLog{}, sender, prometheus.NewRegistry(), "")
+	require.NoError(err)
+
+	var streams []*testResponseStream
+	handler := NewStreamingHandler(
+		testStreamHandler(func(context.Context, ids.NodeID, time.Time, []byte) (ResponseStream, *common.AppError) {
+			stream := newTestResponseStream(nil, []byte{0}, []byte{1}, []byte{2}, []byte{3}, []byte{4}, []byte{5})
+			streams = append(streams, stream)
+			return stream, nil
+		}),
+		network,
+		NewSlidingWindowThrottler(time.Minute, 100),
+		2,
+		time.Minute,
+		logging.NoLog{},
+	)
+
+	open := func(nodeID ids.NodeID, requestID uint32) *common.AppError {
+		requestBytes, err := proto.Marshal(&sdk.StreamRequest{
+			RequestId: requestID,
+		})
+		require.NoError(err)
+
+		_, appErr := handler.AppRequest(ctx, nodeID, time.Time{}, requestBytes)
+		return appErr
+	}
+	ack := func(nodeID ids.NodeID, requestID uint32, numRead uint32, closed bool) {
+		ackBytes, err := proto.Marshal(&sdk.StreamAck{
+			RequestId: requestID,
+			NumRead:   numRead,
+			Closed:    closed,
+		})
+		require.NoError(err)
+
+		handler.AppGossip(ctx, nodeID, ackBytes)
+	}
+	pushed := func() *sdk.StreamChunk {
+		gossipBytes := <-sender.SentAppGossip
+		pushedHandlerID, chunkBytes, ok := ParseMessage(gossipBytes)
+		require.True(ok)
+		require.Equal(uint64(StreamChunkHandlerID), pushedHandlerID)
+
+		chunk := &sdk.StreamChunk{}
+		require.NoError(proto.Unmarshal(chunkBytes, chunk))
+		return chunk
+	}
+
+	nodeID := ids.GenerateTestNodeID()
+	require.Nil(open(nodeID, 1))
+
+	// Only the window of chunks past the acknowledged chunks is pushed.
+	for i := uint32(0); i < streamWindow; i++ {
+		chunk := pushed()
+		require.Equal(uint32(1), chunk.RequestId)
+		require.Equal(i, chunk.Index)
+		require.Equal([]byte{byte(i)}, chunk.Chunk)
+	}
+	select {
+	case <-sender.SentAppGossip:
+		require.FailNow("pushed chunk past the window")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	ack(nodeID, 1, 1, false)
+	require.Equal(uint32(streamWindow), pushed().Index)
+
+	// Repeating an ack pushes the unacknowledged chunks again.
+	ack(nodeID, 1, 1, false)
+	for i := uint32(1); i <= streamWindow; i++ {
+		require.Equal(i, pushed().Index)
+	}
+
+	// Streams can't be opened twice.
+	require.Equal(ErrInvalidRequest, open(nodeID, 1))
+
+	// The number of open streams per node is limited.
+	require.Nil(open(nodeID, 3))
+	require.Equal(ErrTooManyStreams, open(nodeID, 5))
+	for i := 0; i < streamWindow; i++ {
+		pushed()
+	}
+
+	// Closed streams are released.
+	ack(nodeID, 1, 0, true)
+	<-streams[0].closed
+	require.Nil(open(nodeID, 5))
+
+	// Cross-chain streams aren't supported.
+	_, err = handler.CrossChainAppRequest(ctx, ids.GenerateTestID(), time.Time{}, nil)
+	require.ErrorIs(err, errCrossChainStreamsNotSupported)
+}
+
+func TestStreamingHandlerExpiry(t *testing.T) {
+	require := require.New(t)
+
+	network, err := NewNetwork(logging.NoLog{}, &common.FakeSender{}, prometheus.NewRegistry(), "")
+	require.NoError(err)
+
+	stream := newTestResponseStream(nil, []byte{0}, []byte{1}, []byte{2}, []byte{3}, []byte{4}, []byte{5})
+	handler := NewStreamingHandler(
+		testStreamHandler(func(context.Context, ids.NodeID, time.Time, []byte) (ResponseStream, *common.AppError) {
+			return stream, nil
+		}),
+		network,
+		NewSlidingWindowThrottler(time.Minute, 100),
+		1,
+		time.Millisecond,
+		logging.NoLog{},
+	)
+
+	requestBytes, err := proto.Marshal(&sdk.StreamRequest{})
+	require.NoError(err)
+	nodeID := ids.GenerateTestNodeID()
+	_, appErr := handler.AppRequest(context.Background(), nodeID, time.Time{}, requestBytes)
+	require.Nil(appErr)
+
+	// Streams that aren't acknowledged expire.
+	<-stream.closed
+	_, appErr = handler.AppRequest(context.Background(), nodeID, time.Time{}, requestBytes)
+	require.Nil(appErr)
+}
+
+func TestStreamingHandlerSlowHandler(t *testing.T) {
+	require := require.New(t)
+
+	network, err := NewNetwork(logging.NoLog{}, &common.FakeSender{}, prometheus.NewRegistry(), "")
+	require.NoError(err)
+
+	var (
+		slowNodeID = ids.GenerateTestNodeID()
+		blocked    = make(chan struct{})
+		unblock    = make(chan struct{})
+		slowDone   = make(chan *common.AppError)
+	)
+	handler := NewStreamingHandler(
+		testStreamHandler(func(_ context.Context, nodeID ids.NodeID, _ time.Time, _ []byte) (ResponseStream, *common.AppError) {
+			if nodeID == slowNodeID {
+				close(blocked)
+				<-unblock
+				return nil, ErrUnexpected
+			}
+			return newTestResponseStream(nil, []byte{0}), nil
+		}),
+		network,
+		NewSlidingWindowThrottler(time.Minute, 100),
+		1,
+		time.Minute,
+		logging.NoLog{},
+	)
+
+	requestBytes, err := proto.Marshal(&sdk.StreamRequest{})
+	require.NoError(err)
+	go func() {
+		_, appErr := handler.AppRequest(context.Background(), slowNodeID, time.Time{}, requestBytes)
+		slowDone <- appErr
+	}()
+	<-blocked
+
+	// Streams of other nodes are opened while the handler is blocked.
+	_, appErr := handler.AppRequest(context.Background(), ids.GenerateTestNodeID(), time.Time{}, requestBytes)
+	require.Nil(appErr)
+
+	// The slot of the blocked stream is reserved.
+	_, appErr = handler.AppRequest(context.Background(), slowNodeID, time.Time{}, requestBytes)
+	require.Equal(ErrInvalidRequest, appErr)
+
+	// The reservation is released if the stream can't be created.
+	close(unblock)
+	require.Equal(ErrUnexpected, <-slowDone)
+	handler.lock.Lock()
+	require.NotContains(handler.streams, slowNodeID)
+	handler.lock.Unlock()
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var ErrInvalidResponse = errors.New("invalid response")
+
+// TypedHandler is the server-side logic for application protocols whose
+// requests and responses are protobuf messages.
+//
+// A TypedHandler is registered with a Network by wrapping it with
+// NewTypedHandler.
+type TypedHandler[Req, Resp proto.Message] interface {
+	// AppRequest is called when handling an AppRequest message.
+	// Returns the response to [request] or an application-defined error.
+	AppRequest(
+		ctx context.Context,
+		nodeID ids.NodeID,
+		deadline time.Time,
+		request Req,
+	) (Resp, *common.AppError)
+}
+
+// RequestValidator can be implemented by a TypedHandler to verify requests
+// before they are handled. Requests that fail verification are responded to
+// with ErrInvalidRequest.
+type RequestValidator[Req proto.Message] interface {
+	ValidateRequest(request Req) error
+}
+
+// NewTypedHandler returns a Handler that unmarshals requests and marshals
+// responses for [handler]. Requests that can't be unmarshalled are responded
+// to with ErrInvalidRequest.
+func NewTypedHandler[Req, Resp proto.Message](
+	handler TypedHandler[Req, Resp],
+	log logging.Logger,
+) Handler {
+	return &typedHandler[Req, Resp]{
+		handler: handler,
+		log:     log,
+	}
+}
+
+type typedHandler[Req, Resp proto.Message] struct {
+	NoOpHandler
+	handler TypedHandler[Req, Resp]
+	log     logging.Logger
+}
+
+func (t *typedHandler[Req, _]) AppRequest(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	deadline time.Time,
+	requestBytes []byte,
+) ([]byte, *common.AppError) {
+	request := newMessage[Req]()
+	if err := proto.Unmarshal(requestBytes, request); err != nil {
+		t.log.Debug("failed to unmarshal request",
+			zap.Stringer("nodeID", nodeID),
+			zap.Error(err),
+		)
+		return nil, ErrInvalidRequest
+	}
+
+	if validator, ok := t.handler.(RequestValidator[Req]); ok {
+		if err := validator.ValidateRequest(request); err != nil {
+			t.log.Debug("dropping invalid request",
+				zap.Stringer("nodeID", nodeID),
+				zap.Error(err),
+			)
+			return nil, ErrInvalidRequest
+		}
+	}
+
+	response, appErr := t.handler.AppRequest(ctx, nodeID, deadline, request)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	responseBytes, err := proto.Marshal(response)
+	if err != nil {
+		t.log.Error("failed to marshal response",
+			zap.Stringer("nodeID", nodeID),
+			zap.Error(err),
+		)
+		return nil, ErrUnexpected
+	}
+	return responseBytes, nil
+}
+
+// TypedAppResponseCallback is called upon receiving the response to an
+// AppRequest issued by TypedClient.
+// Callers should check [err] to see whether the AppRequest failed or not.
+// Errors sent by the peer are reported as a *common.AppError and responses
+// that can't be unmarshalled are reported as ErrInvalidResponse.
+type TypedAppResponseCallback[Resp proto.Message] func(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	response Resp,
+	err error,
+)
+
+// NewTypedClient returns a client that marshals requests and unmarshals
+// responses for the protocol of [client].
+func NewTypedClient[Req, Resp proto.Message](client *Client) *TypedClient[Req, Resp] {
+	return &TypedClient[Req, Resp]{
+		client: client,
+	}
+}
+
+// TypedClient issues requests for application protocols whose requests and
+// responses are protobuf messages.
+type TypedClient[Req, Resp proto.Message] struct {
+	client *Client
+}
+
+// AppRequestAny issues an AppRequest to an arbitrary node decided by the
+// underlying Client.
+// See Client.AppRequestAny for more docs.
+func (t *TypedClient[Req, Resp]) AppRequestAny(
+	ctx context.Context,
+	request Req,
+	onResponse TypedAppResponseCallback[Resp],
+) error {
+	requestBytes, err := proto.Marshal(request)
+	if err != nil {
+		return err
+	}
+	return t.client.AppRequestAny(ctx, requestBytes, t.callback(onResponse))
+}
+
+// AppRequest issues [request] to [nodeIDs].
+// See Client.AppRequest for more docs.
+func (t *TypedClient[Req, Resp]) AppRequest(
+	ctx context.Context,
+	nodeIDs set.Set[ids.NodeID],
+	request Req,
+	onResponse TypedAppResponseCallback[Resp],
+) error {
+	requestBytes, err := proto.Marshal(request)
+	if err != nil {
+		return err
+	}
+	return t.client.AppRequest(ctx, nodeIDs, requestBytes, t.callback(onResponse))
+}
+
+func (*TypedClient[_, Resp]) callback(onResponse TypedAppResponseCallback[Resp]) AppResponseCallback {
+	return func(ctx context.Context, nodeID ids.NodeID, responseBytes []byte, err error) {
+		var response Resp
+		if err != nil {
+			onResponse(ctx, nodeID, response, err)
+			return
+		}
+
+		response = newMessage[Resp]()
+		if err := proto.Unmarshal(responseBytes, response); err != nil {
+			var empty Resp
+			onResponse(ctx, nodeID, empty, fmt.Errorf("%w: %w", ErrInvalidResponse, err))
+			return
+		}
+		onResponse(ctx, nodeID, response, nil)
+	}
+}
+
+// newMessage returns a new, empty message of type [T].
+func newMessage[T proto.Message]() T {
+	var msg T
+	return msg.ProtoReflect().New().Interface().(T)
+}
@@ -0,0 +1,208 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package p2p
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/proto/pb/sdk"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var (
+	_ TypedHandler[*sdk.PullGossipRequest, *sdk.PullGossipResponse] = (*testTypedHandler)(nil)
+	_ RequestValidator[*sdk.PullGossipRequest]                      = (*testTypedHandler)(nil)
+
+	errEmptySalt = errors.New("empty salt")
+)
+
+type testTypedHandler struct {
+	validate   bool
+	appRequest func(request *sdk.PullGossipRequest) (*sdk.PullGossipResponse, *common.AppError)
+}
+
+func (t *testTypedHandler) ValidateRequest(request *sdk.PullGossipRequest) error {
+	if t.validate && len(request.Salt) == 0 {
+		return errEmptySalt
+	}
+	return nil
+}
+
+func (t *testTypedHandler) AppRequest(
+	_ context.Context,
+	_ ids.NodeID,
+	_ time.Time,
+	request *sdk.PullGossipRequest,
+) (*sdk.PullGossipResponse, *common.AppError) {
+	return t.appRequest(request)
+}
+
+func TestTypedHandler(t *testing.T) {
+	echo := func(request *sdk.PullGossipRequest) (*sdk.PullGossipResponse, *common.AppError) {
+		return &sdk.PullGossipResponse{
+			Gossip: [][]byte{request.Salt},
+		}, nil
+	}
+
+	tests := []struct {
+		name             string
+		handler          *testTypedHandler
+		requestBytes     []byte
+		expectedResponse *sdk.PullGossipResponse
+		expectedErr      *common.AppError
+	}{
+		{
+			name: "valid request",
+			handler: &testTypedHandler{
+				validate:   true,
+				appRequest: echo,
+			},
+			requestBytes: mustMarshal(t, &sdk.PullGossipRequest{
+				Salt: []byte("salt"),
+			}),
+			expectedResponse: &sdk.PullGossipResponse{
+				Gossip: [][]byte{[]byte("salt")},
+			},
+		},
+		{
+			name: "malformed request",
+			handler: &testTypedHandler{
+				appRequest: echo,
+			},
+			requestBytes: []byte{0xff},
+			expectedErr:  ErrInvalidRequest,
+		},
+		{
+			name: "request fails validation",
+			handler: &testTypedHandler{
+				validate:   true,
+				appRequest: echo,
+			},
+			requestBytes: mustMarshal(t, &sdk.PullGossipRequest{}),
+			expectedErr:  ErrInvalidRequest,
+		},
+		{
+			name: "handler error",
+			handler: &testTypedHandler{
+				appRequest: func(*sdk.PullGossipRequest) (*sdk.PullGossipResponse, *common.AppError) {
+					return nil, errFoo
+				},
+			},
+			requestBytes: mustMarshal(t, &sdk.PullGossipRequest{}),
+			expectedErr:  errFoo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			handler := NewTypedHandler(tt.handler, logging.NoLog{})
+			responseBytes, appErr := handler.AppRequest(context.Background(), ids.EmptyNodeID, time.Time{}, tt.requestBytes)
+			require.Equal(tt.expectedErr, appErr)
+			if tt.expectedErr != nil {
+				return
+			}
+
+			response := &sdk.PullGossipResponse{}
+			require.NoError(proto.Unmarshal(responseBytes, response))
+			require.True(proto.Equal(tt.expectedResponse, response))
+		})
+	}
+}
+
+func TestTypedClient(t *testing.T) {
+	tests := []struct {
+		name             string
+		handler          Handler
+		expectedResponse *sdk.PullGossipResponse
+		expectedErr      error
+	}{
+		{
+			name: "response",
+			handler: NewTypedHandler[*sdk.PullGossipRequest, *sdk.PullGossipResponse](
+				&testTypedHandler{
+					appRequest: func(request *sdk.PullGossipRequest) (*sdk.PullGossipResponse, *common.AppError) {
+						return &sdk.PullGossipResponse{
+							Gossip: [][]byte{request.Salt},
+						}, nil
+					},
+				},
+				logging.NoLog{},
+			),
+			expectedResponse: &sdk.PullGossipResponse{
+				Gossip: [][]byte{[]byte("salt")},
+			},
+		},
+		{
+			name: "app error",
+			handler: NewTypedHandler[*sdk.PullGossipRequest, *sdk.PullGossipResponse](
+				&testTypedHandler{
+					appRequest: func(*sdk.PullGossipRequest) (*sdk.PullGossipResponse, *common.AppError) {
+						return nil, errFoo
+					},
+				},
+				logging.NoLog{},
+			),
+			expectedErr: errFoo,
+		},
+		{
+			name: "malformed response",
+			handler: &TestHandler{
+				AppRequestF: func(context.Context, ids.NodeID, time.Time, []byte) ([]byte, *common.AppError) {
+					return []byte{0xff}, nil
+				},
+			},
+			expectedErr: ErrInvalidResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			client := NewTypedClient[*sdk.PullGossipRequest, *sdk.PullGossipResponse](
+				newStreamClient(
+					t,
+					func(*Network) Handler {
+						return tt.handler
+					},
+					nil,
+				),
+			)
+
+			done := make(chan struct{})
+			onResponse := func(_ context.Context, _ ids.NodeID, response *sdk.PullGossipResponse, err error) {
+				defer close(done)
+
+				require.ErrorIs(err, tt.expectedErr)
+				if tt.expectedErr != nil {
+					return
+				}
+				require.True(proto.Equal(tt.expectedResponse, response))
+			}
+
+			request := &sdk.PullGossipRequest{
+				Salt: []byte("salt"),
+			}
+			require.NoError(client.AppRequest(context.Background(), set.Of(ids.EmptyNodeID), request, onResponse))
+			<-done
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, msg proto.Message) []byte {
+	bytes, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return bytes
+}
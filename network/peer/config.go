@@ -8,11 +8,13 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/uptime"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
@@ -45,6 +47,9 @@ type Config struct {
 	SupportedACPs []uint32
 	ObjectedACPs  []uint32
 
+	// zstd dictionaries that MessageCreator supports
+	CompressionDictionaries []*compression.Dictionary
+
 	// Unix time of the last message sent and received respectively
 	// Must only be accessed atomically
 	LastSent, LastReceived int64
@@ -52,6 +57,9 @@ type Config struct {
 	// Tracks CPU/disk usage caused by each peer.
 	ResourceTracker tracker.ResourceTracker
 
+	// Tracks invalid messages and protocol violations of each peer.
+	Reputation reputation.Tracker
+
 	// Calculates uptime of peers
 	UptimeCalculator uptime.Calculator
 
@@ -6,6 +6,7 @@ package peer
 import (
 	"context"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -14,9 +15,16 @@ import (
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/utils/buffer"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
 )
 
-const initialQueueSize = 64
+const (
+	initialQueueSize = 64
+
+	// laneQuantum is the number of bytes a lane may send per unit of weight in
+	// each round of the scheduler.
+	laneQuantum = 16 * units.KiB
+)
 
 var (
 	_ MessageQueue = (*throttledMessageQueue)(nil)
@@ -54,6 +62,7 @@ type MessageQueue interface {
 
 type throttledMessageQueue struct {
 	onFailed SendFailedCallback
+	metrics  *Metrics
 	// [id] of the peer we're sending messages to
 	id                   ids.NodeID
 	log                  logging.Logger
@@ -67,9 +76,32 @@ type throttledMessageQueue struct {
 	// [cond.L] must be held while accessing [closed].
 	closed bool
 
-	// queue of the messages
-	// [cond.L] must be held while accessing [queue].
-	queue buffer.Deque[message.OutboundMessage]
+	// lanes of queued messages, indexed by priority.
+	// [cond.L] must be held while accessing [lanes], [size], [current] and
+	// [credited].
+	lanes [numPriorities]*lane
+	// size is the total number of messages across all lanes
+	size int
+	// current is the index of the lane that is currently being serviced by the
+	// deficit round robin scheduler
+	current int
+	// credited is true if [current] has already been given its quantum for
+	// this round
+	credited bool
+}
+
+type lane struct {
+	config  PriorityConfig
+	queue   buffer.Deque[*queuedMessage]
+	bytes   uint64
+	deficit uint64
+}
+
+type queuedMessage struct {
+	msg      message.OutboundMessage
+	size     uint64
+	priority Priority
+	pushTime time.Time
 }
 
 func NewThrottledMessageQueue(
@@ -77,15 +109,24 @@ func NewThrottledMessageQueue(
 	id ids.NodeID,
 	log logging.Logger,
 	outboundMsgThrottler throttling.OutboundMsgThrottler,
+	metrics *Metrics,
+	config MessageQueueConfig,
 ) MessageQueue {
-	return &throttledMessageQueue{
+	q := &throttledMessageQueue{
 		onFailed:             onFailed,
+		metrics:              metrics,
 		id:                   id,
 		log:                  log,
 		outboundMsgThrottler: outboundMsgThrottler,
 		cond:                 sync.NewCond(&sync.Mutex{}),
-		queue:                buffer.NewUnboundedDeque[message.OutboundMessage](initialQueueSize),
 	}
+	for i, priorityConfig := range config.priorities() {
+		q.lanes[i] = &lane{
+			config: priorityConfig,
+			queue:  buffer.NewUnboundedDeque[*queuedMessage](initialQueueSize),
+		}
+	}
+	return q
 }
 
 func (q *throttledMessageQueue) Push(ctx context.Context, msg message.OutboundMessage) bool {
@@ -131,7 +172,33 @@ func (q *throttledMessageQueue) Push(ctx context.Context, msg message.OutboundMe
 		return false
 	}
 
-	q.queue.PushRight(msg)
+	var (
+		priority = PriorityOf(msg.Op())
+		l        = q.lanes[priority]
+		size     = uint64(len(msg.Bytes()))
+	)
+	if l.config.MaxBytes != 0 && l.bytes+size > l.config.MaxBytes {
+		q.log.Debug(
+			"dropping outgoing message",
+			zap.String("reason", "priority queue full"),
+			zap.Stringer("messageOp", msg.Op()),
+			zap.Stringer("priority", priority),
+			zap.Stringer("nodeID", q.id),
+		)
+		q.outboundMsgThrottler.Release(msg, q.id)
+		q.metrics.QueueFull(priority)
+		q.onFailed.SendFailed(msg)
+		return false
+	}
+
+	l.queue.PushRight(&queuedMessage{
+		msg:      msg,
+		size:     size,
+		priority: priority,
+		pushTime: time.Now(),
+	})
+	l.bytes += size
+	q.size++
 	q.cond.Signal()
 	return true
 }
@@ -144,7 +211,7 @@ func (q *throttledMessageQueue) Pop() (message.OutboundMessage, bool) {
 		if q.closed {
 			return nil, false
 		}
-		if q.queue.Len() > 0 {
+		if q.size > 0 {
 			// There is a message
 			break
 		}
@@ -159,7 +226,7 @@ func (q *throttledMessageQueue) PopNow() (message.OutboundMessage, bool) {
 	q.cond.L.Lock()
 	defer q.cond.L.Unlock()
 
-	if q.closed || q.queue.Len() == 0 {
+	if q.closed || q.size == 0 {
 		// There isn't a message
 		return nil, false
 	}
@@ -167,11 +234,35 @@ func (q *throttledMessageQueue) PopNow() (message.OutboundMessage, bool) {
 	return q.pop(), true
 }
 
+// pop removes the next message using deficit round robin over the lanes.
+//
+// Invariant: [q.size] > 0.
 func (q *throttledMessageQueue) pop() message.OutboundMessage {
-	msg, _ := q.queue.PopLeft()
+	for {
+		l := q.lanes[q.current]
+		if next, ok := l.queue.PeekLeft(); ok {
+			if !q.credited {
+				l.deficit += l.config.Weight * laneQuantum
+				q.credited = true
+			}
+			if next.size <= l.deficit {
+				_, _ = l.queue.PopLeft()
+				l.deficit -= next.size
+				l.bytes -= next.size
+				q.size--
+
+				q.outboundMsgThrottler.Release(next.msg, q.id)
+				q.metrics.Dequeued(next.priority, time.Since(next.pushTime))
+				return next.msg
+			}
+		} else {
+			// Empty lanes don't accumulate credit.
+			l.deficit = 0
+		}
 
-	q.outboundMsgThrottler.Release(msg, q.id)
-	return msg
+		q.current = (q.current + 1) % numPriorities
+		q.credited = false
+	}
 }
 
 func (q *throttledMessageQueue) Close() {
@@ -184,12 +275,16 @@ func (q *throttledMessageQueue) Close() {
 
 	q.closed = true
 
-	for q.queue.Len() > 0 {
-		msg, _ := q.queue.PopLeft()
-		q.outboundMsgThrottler.Release(msg, q.id)
-		q.onFailed.SendFailed(msg)
+	for _, l := range q.lanes {
+		for l.queue.Len() > 0 {
+			next, _ := l.queue.PopLeft()
+			q.outboundMsgThrottler.Release(next.msg, q.id)
+			q.onFailed.SendFailed(next.msg)
+		}
+		l.queue = nil
+		l.bytes = 0
 	}
-	q.queue = nil
+	q.size = 0
 
 	q.cond.Broadcast()
 }
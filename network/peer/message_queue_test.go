@@ -7,11 +7,15 @@ import (
 	"context"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
@@ -87,3 +91,167 @@ func TestMessageQueue(t *testing.T) {
 	_, ok = q.Pop()
 	require.False(ok)
 }
+
+func TestThrottledMessageQueuePriority(t *testing.T) {
+	require := require.New(t)
+
+	metrics, err := NewMetrics(prometheus.NewRegistry())
+	require.NoError(err)
+
+	q := NewThrottledMessageQueue(
+		metrics,
+		ids.EmptyNodeID,
+		logging.NoLog{},
+		throttling.NewNoOutboundThrottler(),
+		metrics,
+		DefaultMessageQueueConfig,
+	)
+
+	mc := newMessageCreator(t)
+	gossipMsgs := make([]message.OutboundMessage, 3)
+	for i := range gossipMsgs {
+		gossipMsgs[i], err = mc.AppGossip(ids.GenerateTestID(), []byte{byte(i)})
+		require.NoError(err)
+		require.True(q.Push(context.Background(), gossipMsgs[i]))
+	}
+
+	chitsMsg, err := mc.Chits(
+		ids.GenerateTestID(),
+		1,
+		ids.GenerateTestID(),
+		ids.GenerateTestID(),
+		ids.GenerateTestID(),
+	)
+	require.NoError(err)
+	require.True(q.Push(context.Background(), chitsMsg))
+
+	// The consensus message should be sent before the previously queued
+	// gossip.
+	msg, ok := q.PopNow()
+	require.True(ok)
+	require.Equal(chitsMsg, msg)
+
+	// Messages within the same priority class are sent in order.
+	for _, expected := range gossipMsgs {
+		msg, ok := q.PopNow()
+		require.True(ok)
+		require.Equal(expected, msg)
+	}
+
+	_, ok = q.PopNow()
+	require.False(ok)
+
+	consensusLabels := prometheus.Labels{priorityLabel: ConsensusPriority.String()}
+	gossipLabels := prometheus.Labels{priorityLabel: AppGossipPriority.String()}
+	require.Equal(1., testutil.ToFloat64(metrics.QueuedMessages.With(consensusLabels)))
+	require.Equal(3., testutil.ToFloat64(metrics.QueuedMessages.With(gossipLabels)))
+
+	q.Close()
+}
+
+func TestThrottledMessageQueueWeights(t *testing.T) {
+	require := require.New(t)
+
+	metrics, err := NewMetrics(prometheus.NewRegistry())
+	require.NoError(err)
+
+	config := DefaultMessageQueueConfig
+	config.Consensus.Weight = 1
+	config.AppGossip.Weight = 1
+	q := NewThrottledMessageQueue(
+		metrics,
+		ids.EmptyNodeID,
+		logging.NoLog{},
+		throttling.NewNoOutboundThrottler(),
+		metrics,
+		config,
+	)
+
+	// Each message is larger than a single quantum so that every round only
+	// allows a single message from each lane.
+	mc := newMessageCreator(t)
+	payload := utils.RandomBytes(laneQuantum)
+
+	const numMsgs = 3
+	var (
+		chainID    = ids.GenerateTestID()
+		gossipMsgs = make([]message.OutboundMessage, numMsgs)
+		putMsgs    = make([]message.OutboundMessage, numMsgs)
+	)
+	for i := 0; i < numMsgs; i++ {
+		gossipMsgs[i], err = mc.AppGossip(chainID, payload)
+		require.NoError(err)
+		require.True(q.Push(context.Background(), gossipMsgs[i]))
+	}
+	for i := 0; i < numMsgs; i++ {
+		putMsgs[i], err = mc.Put(chainID, uint32(i), payload)
+		require.NoError(err)
+		require.True(q.Push(context.Background(), putMsgs[i]))
+	}
+
+	// With equal weights, the lanes should be serviced alternately even
+	// though all of the gossip was queued first.
+	var ops []message.Op
+	for i := 0; i < 2*numMsgs; i++ {
+		msg, ok := q.PopNow()
+		require.True(ok)
+		ops = append(ops, msg.Op())
+	}
+	require.Equal(
+		[]message.Op{
+			message.PutOp,
+			message.AppGossipOp,
+			message.PutOp,
+			message.AppGossipOp,
+			message.PutOp,
+			message.AppGossipOp,
+		},
+		ops,
+	)
+
+	q.Close()
+}
+
+func TestThrottledMessageQueueMaxBytes(t *testing.T) {
+	require := require.New(t)
+
+	metrics, err := NewMetrics(prometheus.NewRegistry())
+	require.NoError(err)
+
+	mc := newMessageCreator(t)
+	msg, err := mc.AppGossip(ids.GenerateTestID(), []byte{1, 2, 3})
+	require.NoError(err)
+
+	config := DefaultMessageQueueConfig
+	config.AppGossip.MaxBytes = uint64(len(msg.Bytes()))
+	q := NewThrottledMessageQueue(
+		metrics,
+		ids.EmptyNodeID,
+		logging.NoLog{},
+		throttling.NewNoOutboundThrottler(),
+		metrics,
+		config,
+	)
+
+	require.True(q.Push(context.Background(), msg))
+	require.False(q.Push(context.Background(), msg))
+
+	gossipLabels := prometheus.Labels{priorityLabel: AppGossipPriority.String()}
+	require.Equal(1., testutil.ToFloat64(metrics.QueueFullDrops.With(gossipLabels)))
+
+	// Other priority classes are unaffected by the full gossip lane.
+	pingMsg, err := mc.Ping(0, nil)
+	require.NoError(err)
+	require.True(q.Push(context.Background(), pingMsg))
+
+	// Popping the gossip frees up space in its lane.
+	popped, ok := q.PopNow()
+	require.True(ok)
+	require.Equal(msg, popped)
+	require.True(q.Push(context.Background(), msg))
+
+	q.Close()
+
+	_, ok = q.PopNow()
+	require.False(ok)
+}
@@ -6,6 +6,7 @@ package peer
 import (
 	"errors"
 	"strconv"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -16,6 +17,7 @@ const (
 	ioLabel         = "io"
 	opLabel         = "op"
 	compressedLabel = "compressed"
+	priorityLabel   = "priority"
 
 	sentLabel     = "sent"
 	receivedLabel = "received"
@@ -25,6 +27,7 @@ var (
 	opLabels             = []string{opLabel}
 	ioOpLabels           = []string{ioLabel, opLabel}
 	ioOpCompressedLabels = []string{ioLabel, opLabel, compressedLabel}
+	priorityLabels       = []string{priorityLabel}
 )
 
 type Metrics struct {
@@ -37,6 +40,10 @@ type Metrics struct {
 	Messages   *prometheus.CounterVec // io + op + compressed
 	Bytes      *prometheus.CounterVec // io + op
 	BytesSaved *prometheus.GaugeVec   // io + op
+
+	QueuedMessages *prometheus.CounterVec // priority
+	QueueTime      *prometheus.CounterVec // priority
+	QueueFullDrops *prometheus.CounterVec // priority
 }
 
 func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
@@ -81,6 +88,27 @@ func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
 			},
 			ioOpLabels,
 		),
+		QueuedMessages: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "msgs_dequeued",
+				Help: "number of outbound messages removed from the send queue to be sent",
+			},
+			priorityLabels,
+		),
+		QueueTime: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "msgs_queue_time",
+				Help: "total time dequeued outbound messages spent in the send queue (ns)",
+			},
+			priorityLabels,
+		),
+		QueueFullDrops: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "msgs_dropped_queue_full",
+				Help: "number of outbound messages dropped because their priority class of the send queue was full",
+			},
+			priorityLabels,
+		),
 	}
 	return m, errors.Join(
 		registerer.Register(m.ClockSkewCount),
@@ -90,6 +118,9 @@ func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
 		registerer.Register(m.Messages),
 		registerer.Register(m.Bytes),
 		registerer.Register(m.BytesSaved),
+		registerer.Register(m.QueuedMessages),
+		registerer.Register(m.QueueTime),
+		registerer.Register(m.QueueFullDrops),
 	)
 }
 
@@ -128,6 +159,24 @@ func (m *Metrics) SendFailed(msg message.OutboundMessage) {
 	}).Inc()
 }
 
+// Dequeued updates the metrics for having removed a message of [priority] from
+// the send queue after it was queued for [queueTime].
+func (m *Metrics) Dequeued(priority Priority, queueTime time.Duration) {
+	labels := prometheus.Labels{
+		priorityLabel: priority.String(),
+	}
+	m.QueuedMessages.With(labels).Inc()
+	m.QueueTime.With(labels).Add(float64(queueTime))
+}
+
+// QueueFull updates the metrics for having dropped a message of [priority]
+// because its send queue was full.
+func (m *Metrics) QueueFull(priority Priority) {
+	m.QueueFullDrops.With(prometheus.Labels{
+		priorityLabel: priority.String(),
+	}).Inc()
+}
+
 func (m *Metrics) Received(msg message.InboundMessage, msgLen uint32) {
 	op := msg.Op().String()
 	saved := msg.BytesSavedCompression()
@@ -23,6 +23,7 @@ import (
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/bloom"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/ips"
@@ -149,6 +150,9 @@ type peer struct {
 	// options of ACPs provided in the Handshake message.
 	supportedACPs set.Set[uint32]
 	objectedACPs  set.Set[uint32]
+	// compressionDictionary is the ID of the zstd dictionary negotiated in the
+	// Handshake message, or 0 if no dictionary is supported by both peers.
+	compressionDictionary utils.Atomic[uint32]
 
 	// txIDOfVerifiedBLSKey is the txID that added the BLS key that was most
 	// recently verified to have signed the IP.
@@ -488,6 +492,7 @@ func (p *peer) readMessages() {
 			)
 
 			p.Metrics.NumFailedToParse.Inc()
+			p.Reputation.RegisterInvalidMessage(p.id)
 
 			// Couldn't parse the message. Read the next one.
 			onFinishedHandling()
@@ -590,7 +595,7 @@ func (p *peer) writeMessages() {
 }
 
 func (p *peer) writeMessage(writer io.Writer, msg message.OutboundMessage) {
-	msgBytes := msg.Bytes()
+	msgBytes := msg.BytesFor(p.compressionDictionary.Get())
 	p.Log.Verbo("sending message",
 		zap.Stringer("nodeID", p.id),
 		zap.Binary("messageBytes", msgBytes),
@@ -734,6 +739,26 @@ func (p *peer) shouldDisconnect() bool {
 	return false
 }
 
+// protocolViolation logs that the peer sent a malformed [op] message and
+// records the protocol violation against the peer's reputation.
+func (p *peer) protocolViolation(op message.Op, fields ...zap.Field) {
+	p.protocolViolationWithLog(p.Log.Debug, op, fields...)
+}
+
+// protocolViolationWithLog is protocolViolation with the log level chosen by
+// the caller.
+func (p *peer) protocolViolationWithLog(
+	log func(msg string, fields ...zap.Field),
+	op message.Op,
+	fields ...zap.Field,
+) {
+	log(malformedMessageLog, append([]zap.Field{
+		zap.Stringer("nodeID", p.id),
+		zap.Stringer("messageOp", op),
+	}, fields...)...)
+	p.Reputation.RegisterProtocolViolation(p.id)
+}
+
 func (p *peer) handle(msg message.InboundMessage) {
 	switch m := msg.Message().(type) { // Network-related message types
 	case *p2p.Ping:
@@ -773,9 +798,7 @@ func (p *peer) handle(msg message.InboundMessage) {
 
 func (p *peer) handlePing(msg *p2p.Ping) {
 	if msg.Uptime > 100 {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.PingOp),
+		p.protocolViolation(message.PingOp,
 			zap.Stringer("subnetID", constants.PrimaryNetworkID),
 			zap.Uint32("uptime", msg.Uptime),
 		)
@@ -787,9 +810,7 @@ func (p *peer) handlePing(msg *p2p.Ping) {
 	for _, subnetUptime := range msg.SubnetUptimes {
 		subnetID, err := ids.ToID(subnetUptime.SubnetId)
 		if err != nil {
-			p.Log.Debug(malformedMessageLog,
-				zap.Stringer("nodeID", p.id),
-				zap.Stringer("messageOp", message.PingOp),
+			p.protocolViolation(message.PingOp,
 				zap.String("field", "subnetID"),
 				zap.Error(err),
 			)
@@ -798,9 +819,7 @@ func (p *peer) handlePing(msg *p2p.Ping) {
 		}
 
 		if !p.MySubnets.Contains(subnetID) {
-			p.Log.Debug(malformedMessageLog,
-				zap.Stringer("nodeID", p.id),
-				zap.Stringer("messageOp", message.PingOp),
+			p.protocolViolation(message.PingOp,
 				zap.Stringer("subnetID", subnetID),
 				zap.String("reason", "not tracking subnet"),
 			)
@@ -810,9 +829,7 @@ func (p *peer) handlePing(msg *p2p.Ping) {
 
 		uptime := subnetUptime.Uptime
 		if uptime > 100 {
-			p.Log.Debug(malformedMessageLog,
-				zap.Stringer("nodeID", p.id),
-				zap.Stringer("messageOp", message.PingOp),
+			p.protocolViolation(message.PingOp,
 				zap.Stringer("subnetID", subnetID),
 				zap.Uint32("uptime", uptime),
 			)
@@ -891,9 +908,7 @@ func (p *peer) observeUptime(subnetID ids.ID, uptime uint32) {
 
 func (p *peer) handleHandshake(msg *p2p.Handshake) {
 	if p.gotHandshake.Get() {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.HandshakeOp),
+		p.protocolViolation(message.HandshakeOp,
 			zap.String("reason", "already received handshake"),
 		)
 		p.StartClose()
@@ -901,9 +916,7 @@ func (p *peer) handleHandshake(msg *p2p.Handshake) {
 	}
 
 	if msg.NetworkId != p.NetworkID {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.HandshakeOp),
+		p.protocolViolation(message.HandshakeOp,
 			zap.String("field", "networkID"),
 			zap.Uint32("peerNetworkID", msg.NetworkId),
 			zap.Uint32("ourNetworkID", p.NetworkID),
@@ -955,9 +968,7 @@ func (p *peer) handleHandshake(msg *p2p.Handshake) {
 
 	// handle subnet IDs
 	if numTrackedSubnets := len(msg.TrackedSubnets); numTrackedSubnets > maxNumTrackedSubnets {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.HandshakeOp),
+		p.protocolViolation(message.HandshakeOp,
 			zap.String("field", "trackedSubnets"),
 			zap.Int("numTrackedSubnets", numTrackedSubnets),
 		)
@@ -969,9 +980,7 @@ func (p *peer) handleHandshake(msg *p2p.Handshake) {
 	for _, subnetIDBytes := range msg.TrackedSubnets {
 		subnetID, err := ids.ToID(subnetIDBytes)
 		if err != nil {
-			p.Log.Debug(malformedMessageLog,
-				zap.Stringer("nodeID", p.id),
-				zap.Stringer("messageOp", message.HandshakeOp),
+			p.protocolViolation(message.HandshakeOp,
 				zap.String("field", "trackedSubnets"),
 				zap.Error(err),
 			)
@@ -993,9 +1002,7 @@ func (p *peer) handleHandshake(msg *p2p.Handshake) {
 	}
 
 	if p.supportedACPs.Overlaps(p.objectedACPs) {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.HandshakeOp),
+		p.protocolViolation(message.HandshakeOp,
 			zap.String("field", "acps"),
 			zap.Reflect("supportedACPs", p.supportedACPs),
 			zap.Reflect("objectedACPs", p.objectedACPs),
@@ -1004,6 +1011,11 @@ func (p *peer) handleHandshake(msg *p2p.Handshake) {
 		return
 	}
 
+	p.compressionDictionary.Set(compression.SelectDictionary(
+		p.CompressionDictionaries,
+		msg.SupportedZstdDictionaryHashes,
+	))
+
 	var (
 		knownPeers = bloom.EmptyFilter
 		salt       []byte
@@ -1012,9 +1024,7 @@ func (p *peer) handleHandshake(msg *p2p.Handshake) {
 		var err error
 		knownPeers, err = bloom.Parse(msg.KnownPeers.Filter)
 		if err != nil {
-			p.Log.Debug(malformedMessageLog,
-				zap.Stringer("nodeID", p.id),
-				zap.Stringer("messageOp", message.HandshakeOp),
+			p.protocolViolation(message.HandshakeOp,
 				zap.String("field", "knownPeers.filter"),
 				zap.Error(err),
 			)
@@ -1024,9 +1034,7 @@ func (p *peer) handleHandshake(msg *p2p.Handshake) {
 
 		salt = msg.KnownPeers.Salt
 		if saltLen := len(salt); saltLen > maxBloomSaltLen {
-			p.Log.Debug(malformedMessageLog,
-				zap.Stringer("nodeID", p.id),
-				zap.Stringer("messageOp", message.HandshakeOp),
+			p.protocolViolation(message.HandshakeOp,
 				zap.String("field", "knownPeers.salt"),
 				zap.Int("saltLen", saltLen),
 			)
@@ -1037,9 +1045,7 @@ func (p *peer) handleHandshake(msg *p2p.Handshake) {
 
 	addr, ok := ips.AddrFromSlice(msg.IpAddr)
 	if !ok {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.HandshakeOp),
+		p.protocolViolation(message.HandshakeOp,
 			zap.String("field", "ip"),
 			zap.Int("ipLen", len(msg.IpAddr)),
 		)
@@ -1049,9 +1055,7 @@ func (p *peer) handleHandshake(msg *p2p.Handshake) {
 
 	port := uint16(msg.IpPort)
 	if msg.IpPort == 0 {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.HandshakeOp),
+		p.protocolViolation(message.HandshakeOp,
 			zap.String("field", "port"),
 			zap.Uint16("port", port),
 		)
@@ -1075,24 +1079,19 @@ func (p *peer) handleHandshake(msg *p2p.Handshake) {
 		if _, ok := p.Beacons.GetValidator(constants.PrimaryNetworkID, p.id); ok {
 			log = p.Log.Warn
 		}
-		log(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.HandshakeOp),
+		p.protocolViolationWithLog(log, message.HandshakeOp,
 			zap.String("field", "tlsSignature"),
 			zap.Uint64("peerTime", msg.MyTime),
 			zap.Uint64("localTime", localUnixTime),
 			zap.Error(err),
 		)
-
 		p.StartClose()
 		return
 	}
 
 	signature, err := bls.SignatureFromBytes(msg.IpBlsSig)
 	if err != nil {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.HandshakeOp),
+		p.protocolViolation(message.HandshakeOp,
 			zap.String("field", "blsSignature"),
 			zap.Error(err),
 		)
@@ -1142,9 +1141,7 @@ func (p *peer) handleHandshake(msg *p2p.Handshake) {
 
 func (p *peer) handleGetPeerList(msg *p2p.GetPeerList) {
 	if !p.finishedHandshake.Get() {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.GetPeerListOp),
+		p.protocolViolation(message.GetPeerListOp,
 			zap.String("reason", "not finished handshake"),
 		)
 		return
@@ -1153,9 +1150,7 @@ func (p *peer) handleGetPeerList(msg *p2p.GetPeerList) {
 	knownPeersMsg := msg.GetKnownPeers()
 	filter, err := bloom.Parse(knownPeersMsg.GetFilter())
 	if err != nil {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.GetPeerListOp),
+		p.protocolViolation(message.GetPeerListOp,
 			zap.String("field", "knownPeers.filter"),
 			zap.Error(err),
 		)
@@ -1165,9 +1160,7 @@ func (p *peer) handleGetPeerList(msg *p2p.GetPeerList) {
 
 	salt := knownPeersMsg.GetSalt()
 	if saltLen := len(salt); saltLen > maxBloomSaltLen {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.GetPeerListOp),
+		p.protocolViolation(message.GetPeerListOp,
 			zap.String("field", "knownPeers.salt"),
 			zap.Int("saltLen", saltLen),
 		)
@@ -1213,9 +1206,7 @@ func (p *peer) handlePeerList(msg *p2p.PeerList) {
 	for i, claimedIPPort := range msg.ClaimedIpPorts {
 		tlsCert, err := staking.ParseCertificate(claimedIPPort.X509Certificate)
 		if err != nil {
-			p.Log.Debug(malformedMessageLog,
-				zap.Stringer("nodeID", p.id),
-				zap.Stringer("messageOp", message.PeerListOp),
+			p.protocolViolation(message.PeerListOp,
 				zap.String("field", "cert"),
 				zap.Error(err),
 			)
@@ -1225,9 +1216,7 @@ func (p *peer) handlePeerList(msg *p2p.PeerList) {
 
 		addr, ok := ips.AddrFromSlice(claimedIPPort.IpAddr)
 		if !ok {
-			p.Log.Debug(malformedMessageLog,
-				zap.Stringer("nodeID", p.id),
-				zap.Stringer("messageOp", message.PeerListOp),
+			p.protocolViolation(message.PeerListOp,
 				zap.String("field", "ip"),
 				zap.Int("ipLen", len(claimedIPPort.IpAddr)),
 			)
@@ -1237,9 +1226,7 @@ func (p *peer) handlePeerList(msg *p2p.PeerList) {
 
 		port := uint16(claimedIPPort.IpPort)
 		if port == 0 {
-			p.Log.Debug(malformedMessageLog,
-				zap.Stringer("nodeID", p.id),
-				zap.Stringer("messageOp", message.PeerListOp),
+			p.protocolViolation(message.PeerListOp,
 				zap.String("field", "port"),
 				zap.Uint16("port", port),
 			)
@@ -1259,9 +1246,7 @@ func (p *peer) handlePeerList(msg *p2p.PeerList) {
 	}
 
 	if err := p.Network.Track(discoveredIPs); err != nil {
-		p.Log.Debug(malformedMessageLog,
-			zap.Stringer("nodeID", p.id),
-			zap.Stringer("messageOp", message.PeerListOp),
+		p.protocolViolation(message.PeerListOp,
 			zap.String("field", "claimedIP"),
 			zap.Error(err),
 		)
@@ -4,6 +4,7 @@
 package peer
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"net"
@@ -13,9 +14,11 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/proto/pb/p2p"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
@@ -25,12 +28,14 @@ import (
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/upgrade"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/math/meter"
 	"github.com/ava-labs/avalanchego/utils/resource"
 	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/version"
 )
 
@@ -53,6 +58,8 @@ func newMessageCreator(t *testing.T) message.Creator {
 		logging.NoLog{},
 		prometheus.NewRegistry(),
 		constants.DefaultNetworkCompressionType,
+		nil,
+		nil,
 		10*time.Second,
 	)
 	require.NoError(t, err)
@@ -93,6 +100,7 @@ func newConfig(t *testing.T) Config {
 		PongTimeout:          constants.DefaultPingPongTimeout,
 		MaxClockDifference:   time.Minute,
 		ResourceTracker:      resourceTracker,
+		Reputation:           reputation.NoTracker{},
 		UptimeCalculator:     uptime.NoOpCalculator,
 		IPSigner:             nil,
 	}
@@ -143,6 +151,8 @@ func startTestPeer(self *rawTestPeer, peer *rawTestPeer, conn net.Conn) *testPee
 				peer.nodeID,
 				logging.NoLog{},
 				throttling.NewNoOutboundThrottler(),
+				self.config.Metrics,
+				DefaultMessageQueueConfig,
 			),
 		),
 		inboundMsgChan: self.inboundMsgChan,
@@ -211,6 +221,98 @@ func TestSend(t *testing.T) {
 	require.NoError(peer1.AwaitClosed(context.Background()))
 }
 
+func newTestDictionary(t *testing.T, id uint32) *compression.Dictionary {
+	t.Helper()
+
+	samples := make([][]byte, 1024)
+	for i := range samples {
+		chainID := ids.GenerateTestID()
+		sample, err := proto.Marshal(&p2p.Message{
+			Message: &p2p.Message_AppGossip{
+				AppGossip: &p2p.AppGossip{
+					ChainId:  chainID[:],
+					AppBytes: bytes.Repeat([]byte{byte(i)}, i%64),
+				},
+			},
+		})
+		require.NoError(t, err)
+		samples[i] = sample
+	}
+	dictionary, err := compression.TrainZstdDictionary(id, samples, 4*units.KiB)
+	require.NoError(t, err)
+	return dictionary
+}
+
+func TestSendCompressionDictionary(t *testing.T) {
+	dictionary1 := newTestDictionary(t, 1)
+	dictionary2 := newTestDictionary(t, 2)
+
+	tests := []struct {
+		name                 string
+		dictionaries0        []*compression.Dictionary
+		dictionaries1        []*compression.Dictionary
+		expectedDictionaryID uint32
+	}{
+		{
+			name:                 "highest common dictionary",
+			dictionaries0:        []*compression.Dictionary{dictionary1, dictionary2},
+			dictionaries1:        []*compression.Dictionary{dictionary1},
+			expectedDictionaryID: 1,
+		},
+		{
+			name:                 "no common dictionary",
+			dictionaries0:        []*compression.Dictionary{dictionary2},
+			dictionaries1:        []*compression.Dictionary{dictionary1},
+			expectedDictionaryID: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			newDictionaryConfig := func(dictionaries []*compression.Dictionary) Config {
+				config := newConfig(t)
+				mc, err := message.NewCreator(
+					logging.NoLog{},
+					prometheus.NewRegistry(),
+					compression.TypeZstdDictionary,
+					dictionaries,
+					nil,
+					10*time.Second,
+				)
+				require.NoError(err)
+				config.MessageCreator = mc
+				config.CompressionDictionaries = dictionaries
+				return config
+			}
+			config0 := newDictionaryConfig(test.dictionaries0)
+			config1 := newDictionaryConfig(test.dictionaries1)
+
+			rawPeer0 := newRawTestPeer(t, config0)
+			rawPeer1 := newRawTestPeer(t, config1)
+
+			peer0, peer1 := startTestPeers(rawPeer0, rawPeer1)
+			awaitReady(t, peer0, peer1)
+
+			require.Equal(test.expectedDictionaryID, peer0.Peer.(*peer).compressionDictionary.Get())
+			require.Equal(test.expectedDictionaryID, peer1.Peer.(*peer).compressionDictionary.Get())
+
+			appBytes := bytes.Repeat([]byte{1}, 64)
+			outboundMsg, err := config0.MessageCreator.AppGossip(ids.Empty, appBytes)
+			require.NoError(err)
+			require.True(peer0.Send(context.Background(), outboundMsg))
+
+			inboundMsg := <-peer1.inboundMsgChan
+			require.Equal(message.AppGossipOp, inboundMsg.Op())
+			require.Equal(appBytes, inboundMsg.Message().(*p2p.AppGossip).AppBytes)
+
+			peer1.StartClose()
+			require.NoError(peer0.AwaitClosed(context.Background()))
+			require.NoError(peer1.AwaitClosed(context.Background()))
+		})
+	}
+}
+
 func TestPingUptimes(t *testing.T) {
 	trackedSubnetID := ids.GenerateTestID()
 	untrackedSubnetID := ids.GenerateTestID()
@@ -0,0 +1,148 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+const (
+	// ConsensusPriority is used for latency sensitive consensus messages.
+	ConsensusPriority Priority = iota
+	// BootstrapPriority is used for bootstrapping and state sync messages.
+	BootstrapPriority
+	// AppRequestPriority is used for application requests and responses.
+	AppRequestPriority
+	// AppGossipPriority is used for application gossip.
+	AppGossipPriority
+	// PeerManagementPriority is used for handshake and peer list messages.
+	PeerManagementPriority
+
+	numPriorities = int(PeerManagementPriority) + 1
+)
+
+var (
+	errZeroPriorityWeight = errors.New("priority weight must be > 0")
+
+	DefaultMessageQueueConfig = MessageQueueConfig{
+		Consensus: PriorityConfig{
+			Weight:   constants.DefaultNetworkOutboundQueueConsensusWeight,
+			MaxBytes: constants.DefaultNetworkOutboundQueueConsensusMaxBytes,
+		},
+		Bootstrap: PriorityConfig{
+			Weight:   constants.DefaultNetworkOutboundQueueBootstrapWeight,
+			MaxBytes: constants.DefaultNetworkOutboundQueueBootstrapMaxBytes,
+		},
+		AppRequest: PriorityConfig{
+			Weight:   constants.DefaultNetworkOutboundQueueAppRequestWeight,
+			MaxBytes: constants.DefaultNetworkOutboundQueueAppRequestMaxBytes,
+		},
+		AppGossip: PriorityConfig{
+			Weight:   constants.DefaultNetworkOutboundQueueAppGossipWeight,
+			MaxBytes: constants.DefaultNetworkOutboundQueueAppGossipMaxBytes,
+		},
+		PeerManagement: PriorityConfig{
+			Weight:   constants.DefaultNetworkOutboundQueuePeerManagementWeight,
+			MaxBytes: constants.DefaultNetworkOutboundQueuePeerManagementMaxBytes,
+		},
+	}
+)
+
+// Priority is the class an outbound message is queued under.
+type Priority byte
+
+func (p Priority) String() string {
+	switch p {
+	case ConsensusPriority:
+		return "consensus"
+	case BootstrapPriority:
+		return "bootstrap"
+	case AppRequestPriority:
+		return "app_request"
+	case AppGossipPriority:
+		return "app_gossip"
+	case PeerManagementPriority:
+		return "peer_management"
+	default:
+		return fmt.Sprintf("unknown_priority_%d", p)
+	}
+}
+
+// PriorityOf returns the priority class that messages with [op] are queued
+// under.
+func PriorityOf(op message.Op) Priority {
+	switch op {
+	case message.GetOp,
+		message.PutOp,
+		message.PushQueryOp,
+		message.PullQueryOp,
+		message.ChitsOp:
+		return ConsensusPriority
+	case message.GetStateSummaryFrontierOp,
+		message.StateSummaryFrontierOp,
+		message.GetAcceptedStateSummaryOp,
+		message.AcceptedStateSummaryOp,
+		message.GetAcceptedFrontierOp,
+		message.AcceptedFrontierOp,
+		message.GetAcceptedOp,
+		message.AcceptedOp,
+		message.GetAncestorsOp,
+		message.AncestorsOp:
+		return BootstrapPriority
+	case message.AppRequestOp,
+		message.AppResponseOp,
+		message.AppErrorOp:
+		return AppRequestPriority
+	case message.AppGossipOp:
+		return AppGossipPriority
+	default:
+		return PeerManagementPriority
+	}
+}
+
+// PriorityConfig describes how outbound messages of a single priority class
+// are scheduled.
+type PriorityConfig struct {
+	// Weight is the relative share of the outbound bandwidth given to this
+	// class when multiple classes have queued messages. Must be > 0.
+	Weight uint64 `json:"weight"`
+
+	// MaxBytes is the maximum number of bytes of this class that may be queued
+	// for a peer at once. If 0, the class is only bounded by the outbound
+	// message throttler.
+	MaxBytes uint64 `json:"maxBytes"`
+}
+
+// MessageQueueConfig describes the scheduling of each priority class of the
+// outbound message queue.
+type MessageQueueConfig struct {
+	Consensus      PriorityConfig `json:"consensus"`
+	Bootstrap      PriorityConfig `json:"bootstrap"`
+	AppRequest     PriorityConfig `json:"appRequest"`
+	AppGossip      PriorityConfig `json:"appGossip"`
+	PeerManagement PriorityConfig `json:"peerManagement"`
+}
+
+func (c *MessageQueueConfig) Verify() error {
+	for i, config := range c.priorities() {
+		if config.Weight == 0 {
+			return fmt.Errorf("%w: %s", errZeroPriorityWeight, Priority(i))
+		}
+	}
+	return nil
+}
+
+func (c *MessageQueueConfig) priorities() [numPriorities]PriorityConfig {
+	return [numPriorities]PriorityConfig{
+		ConsensusPriority:      c.Consensus,
+		BootstrapPriority:      c.Bootstrap,
+		AppRequestPriority:     c.AppRequest,
+		AppGossipPriority:      c.AppGossip,
+		PeerManagementPriority: c.PeerManagement,
+	}
+}
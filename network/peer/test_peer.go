@@ -14,6 +14,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
@@ -79,6 +80,8 @@ func StartTestPeer(
 		logging.NoLog{},
 		prometheus.NewRegistry(),
 		constants.DefaultNetworkCompressionType,
+		nil,
+		nil,
 		10*time.Second,
 	)
 	if err != nil {
@@ -123,6 +126,7 @@ func StartTestPeer(
 			PongTimeout:          constants.DefaultPingPongTimeout,
 			MaxClockDifference:   time.Minute,
 			ResourceTracker:      resourceTracker,
+			Reputation:           reputation.NoTracker{},
 			UptimeCalculator:     uptime.NoOpCalculator,
 			IPSigner: NewIPSigner(
 				utils.NewAtomic(netip.AddrPortFrom(
@@ -0,0 +1,94 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reputation
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	_ Tracker = NoTracker{}
+
+	noScore = Score{
+		ResponseRate: 1,
+		Score:        1,
+	}
+)
+
+// Scorer reports the reputation of peers.
+type Scorer interface {
+	// Score returns the current score of [nodeID] in [0, 1]. Peers that have
+	// never been observed have a score of 1.
+	Score(nodeID ids.NodeID) float64
+
+	// IsPoor returns true if the current score of [nodeID] is below the
+	// configured minimum score.
+	IsPoor(nodeID ids.NodeID) bool
+}
+
+// Tracker combines the behavior of peers into a decaying score per NodeID.
+type Tracker interface {
+	Scorer
+
+	// RegisterResponse records that [nodeID] responded to a request after
+	// [latency].
+	RegisterResponse(nodeID ids.NodeID, latency time.Duration)
+
+	// RegisterFailure records that [nodeID] failed to respond to a request
+	// before it timed out.
+	RegisterFailure(nodeID ids.NodeID)
+
+	// RegisterInvalidMessage records that [nodeID] sent a message that
+	// couldn't be parsed.
+	RegisterInvalidMessage(nodeID ids.NodeID)
+
+	// RegisterProtocolViolation records that [nodeID] sent a well formed
+	// message that violated the p2p protocol.
+	RegisterProtocolViolation(nodeID ids.NodeID)
+
+	// Get returns the detailed reputation of [nodeID].
+	Get(nodeID ids.NodeID) Score
+}
+
+// Score is the detailed reputation of a peer at a point in time.
+type Score struct {
+	// ResponseRate is the decaying portion of requests the peer responded to.
+	ResponseRate float64 `json:"responseRate"`
+	// Latency is the decaying average latency of the peer's responses.
+	Latency time.Duration `json:"latency"`
+	// InvalidMessages is the decaying number of unparsable messages sent by
+	// the peer.
+	InvalidMessages float64 `json:"invalidMessages"`
+	// ProtocolViolations is the decaying number of protocol violations
+	// committed by the peer.
+	ProtocolViolations float64 `json:"protocolViolations"`
+	// Score combines all of the above into a value in [0, 1].
+	Score float64 `json:"score"`
+}
+
+// NoTracker is a Tracker that doesn't record any events and reports every peer
+// as having a perfect score.
+type NoTracker struct{}
+
+func (NoTracker) Score(ids.NodeID) float64 {
+	return 1
+}
+
+func (NoTracker) IsPoor(ids.NodeID) bool {
+	return false
+}
+
+func (NoTracker) RegisterResponse(ids.NodeID, time.Duration) {}
+
+func (NoTracker) RegisterFailure(ids.NodeID) {}
+
+func (NoTracker) RegisterInvalidMessage(ids.NodeID) {}
+
+func (NoTracker) RegisterProtocolViolation(ids.NodeID) {}
+
+func (NoTracker) Get(ids.NodeID) Score {
+	return noScore
+}
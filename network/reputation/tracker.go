@@ -0,0 +1,316 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reputation
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+const (
+	eventLabel = "event"
+
+	responseEvent          = "response"
+	failureEvent           = "failure"
+	invalidMessageEvent    = "invalid_message"
+	protocolViolationEvent = "protocol_violation"
+
+	// latencyWeight is the maximum portion of a peer's score that can be lost
+	// due to slow responses. Slow peers are still preferable to peers that
+	// don't respond at all.
+	latencyWeight = .5
+
+	// responseWeight is the portion of a peer's response rate that is
+	// determined by the outcome of a single request. A single failure can't
+	// make a peer poor, however long ago its previous failures were.
+	responseWeight = .2
+
+	// Peers that haven't been observed for [pruneHalflives] halflives are
+	// indistinguishable from new peers and are removed.
+	pruneHalflives = 8
+	// The tracker checks for peers to prune after every [pruneFrequency]
+	// events.
+	pruneFrequency = 1024
+)
+
+var (
+	_ Tracker = (*tracker)(nil)
+
+	errNonPositiveHalflife       = errors.New("halflife must be > 0")
+	errNonPositiveMaxLatency     = errors.New("max latency must be > 0")
+	errNegativePenalty           = errors.New("penalty must be >= 0")
+	errMinScoreOutOfRange        = errors.New("min score must be in [0, 1]")
+	convertEToBase2              = math.Log(2)
+	eventLabels                  = []string{eventLabel}
+	responseEventLabels          = prometheus.Labels{eventLabel: responseEvent}
+	failureEventLabels           = prometheus.Labels{eventLabel: failureEvent}
+	invalidMessageEventLabels    = prometheus.Labels{eventLabel: invalidMessageEvent}
+	protocolViolationEventLabels = prometheus.Labels{eventLabel: protocolViolationEvent}
+)
+
+type Config struct {
+	// Halflife is the amount of time it takes for the effect of an event on a
+	// peer's score to halve.
+	Halflife time.Duration `json:"halflife"`
+
+	// MaxLatency is the average response latency at which a peer's score is
+	// maximally penalized for being slow.
+	MaxLatency time.Duration `json:"maxLatency"`
+
+	// InvalidMessagePenalty is the penalty applied per unparsable message. A
+	// peer with a total penalty of p has its score scaled by e^-p.
+	InvalidMessagePenalty float64 `json:"invalidMessagePenalty"`
+
+	// ProtocolViolationPenalty is the penalty applied per protocol violation.
+	ProtocolViolationPenalty float64 `json:"protocolViolationPenalty"`
+
+	// MinScore is the score below which a peer is considered to be poor.
+	MinScore float64 `json:"minScore"`
+}
+
+func (c *Config) Verify() error {
+	switch {
+	case c.Halflife <= 0:
+		return errNonPositiveHalflife
+	case c.MaxLatency <= 0:
+		return errNonPositiveMaxLatency
+	case c.InvalidMessagePenalty < 0 || c.ProtocolViolationPenalty < 0:
+		return errNegativePenalty
+	case c.MinScore < 0 || c.MinScore > 1:
+		return errMinScoreOutOfRange
+	default:
+		return nil
+	}
+}
+
+type tracker struct {
+	config Config
+	// Tells the time. Can be faked for testing.
+	clock mockable.Clock
+
+	trackedPeers prometheus.Gauge
+	events       *prometheus.CounterVec
+
+	lock sync.RWMutex
+	// NodeID --> observed behavior of the peer
+	peers map[ids.NodeID]*peerReputation
+	// Number of events since the last prune
+	numEvents int
+}
+
+type peerReputation struct {
+	responseRate       decayingRate
+	latency            safemath.Averager
+	invalidMessages    decayingCounter
+	protocolViolations decayingCounter
+	lastUpdated        time.Time
+}
+
+// NewTracker returns a new Tracker.
+func NewTracker(config Config, reg prometheus.Registerer) (Tracker, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+
+	t := &tracker{
+		config: config,
+		trackedPeers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "tracked_peers",
+			Help: "number of peers with a reputation",
+		}),
+		events: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "events",
+				Help: "number of events that affected the reputation of peers",
+			},
+			eventLabels,
+		),
+		peers: make(map[ids.NodeID]*peerReputation),
+	}
+	err := errors.Join(
+		reg.Register(t.trackedPeers),
+		reg.Register(t.events),
+	)
+	return t, err
+}
+
+func (t *tracker) RegisterResponse(nodeID ids.NodeID, latency time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	p := t.getOrCreate(nodeID, now)
+	p.responseRate.Observe(1, now)
+	p.latency.Observe(float64(latency), now)
+	t.events.With(responseEventLabels).Inc()
+	t.onEvent(now)
+}
+
+func (t *tracker) RegisterFailure(nodeID ids.NodeID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	p := t.getOrCreate(nodeID, now)
+	p.responseRate.Observe(0, now)
+	t.events.With(failureEventLabels).Inc()
+	t.onEvent(now)
+}
+
+func (t *tracker) RegisterInvalidMessage(nodeID ids.NodeID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	p := t.getOrCreate(nodeID, now)
+	p.invalidMessages.Add(now)
+	t.events.With(invalidMessageEventLabels).Inc()
+	t.onEvent(now)
+}
+
+func (t *tracker) RegisterProtocolViolation(nodeID ids.NodeID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	p := t.getOrCreate(nodeID, now)
+	p.protocolViolations.Add(now)
+	t.events.With(protocolViolationEventLabels).Inc()
+	t.onEvent(now)
+}
+
+func (t *tracker) Score(nodeID ids.NodeID) float64 {
+	return t.Get(nodeID).Score
+}
+
+func (t *tracker) IsPoor(nodeID ids.NodeID) bool {
+	return t.Score(nodeID) < t.config.MinScore
+}
+
+func (t *tracker) Get(nodeID ids.NodeID) Score {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	p, ok := t.peers[nodeID]
+	if !ok {
+		return noScore
+	}
+
+	var (
+		now                = t.clock.Time()
+		responseRate       = p.responseRate.Read(now)
+		latency            = p.latency.Read()
+		invalidMessages    = p.invalidMessages.Read(now)
+		protocolViolations = p.protocolViolations.Read(now)
+
+		latencyPortion = math.Min(latency/float64(t.config.MaxLatency), 1)
+		penalty        = t.config.InvalidMessagePenalty*invalidMessages +
+			t.config.ProtocolViolationPenalty*protocolViolations
+	)
+	return Score{
+		ResponseRate:       responseRate,
+		Latency:            time.Duration(latency),
+		InvalidMessages:    invalidMessages,
+		ProtocolViolations: protocolViolations,
+		Score:              responseRate * (1 - latencyWeight*latencyPortion) * math.Exp(-penalty),
+	}
+}
+
+// Assumes [t.lock] is held.
+func (t *tracker) getOrCreate(nodeID ids.NodeID, now time.Time) *peerReputation {
+	p, ok := t.peers[nodeID]
+	if !ok {
+		p = &peerReputation{
+			// New peers are assumed to be responsive until shown otherwise.
+			responseRate: decayingRate{
+				halflife: float64(t.config.Halflife) / convertEToBase2,
+				value:    1,
+			},
+			latency: safemath.NewUninitializedAverager(t.config.Halflife),
+			invalidMessages: decayingCounter{
+				halflife: float64(t.config.Halflife) / convertEToBase2,
+			},
+			protocolViolations: decayingCounter{
+				halflife: float64(t.config.Halflife) / convertEToBase2,
+			},
+		}
+		t.peers[nodeID] = p
+		t.trackedPeers.Set(float64(len(t.peers)))
+	}
+	p.lastUpdated = now
+	return p
+}
+
+// onEvent periodically removes peers that haven't been observed in a long
+// time to bound the memory usage of the tracker.
+//
+// Assumes [t.lock] is held.
+func (t *tracker) onEvent(now time.Time) {
+	t.numEvents++
+	if t.numEvents < pruneFrequency {
+		return
+	}
+	t.numEvents = 0
+
+	pruneBefore := now.Add(-pruneHalflives * t.config.Halflife)
+	for nodeID, p := range t.peers {
+		if p.lastUpdated.Before(pruneBefore) {
+			delete(t.peers, nodeID)
+		}
+	}
+	t.trackedPeers.Set(float64(len(t.peers)))
+}
+
+// decayingCounter is a count of events where each event's contribution decays
+// exponentially over time.
+type decayingCounter struct {
+	halflife    float64
+	value       float64
+	lastUpdated time.Time
+}
+
+func (c *decayingCounter) Add(now time.Time) {
+	c.value = c.Read(now) + 1
+	c.lastUpdated = now
+}
+
+func (c *decayingCounter) Read(now time.Time) float64 {
+	elapsed := now.Sub(c.lastUpdated)
+	if elapsed <= 0 {
+		return c.value
+	}
+	return c.value * math.Exp(-float64(elapsed)/c.halflife)
+}
+
+// decayingRate is the portion of requests that succeeded. Each request moves
+// the rate by [responseWeight] and, without new requests, the rate decays
+// exponentially back to 1 so that peers recover once they stop failing.
+type decayingRate struct {
+	halflife    float64
+	value       float64
+	lastUpdated time.Time
+}
+
+func (r *decayingRate) Observe(success float64, now time.Time) {
+	value := r.Read(now)
+	r.value = value + responseWeight*(success-value)
+	r.lastUpdated = now
+}
+
+func (r *decayingRate) Read(now time.Time) float64 {
+	elapsed := now.Sub(r.lastUpdated)
+	if elapsed <= 0 {
+		return r.value
+	}
+	return 1 - (1-r.value)*math.Exp(-float64(elapsed)/r.halflife)
+}
@@ -0,0 +1,202 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reputation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var testConfig = Config{
+	Halflife:                 time.Minute,
+	MaxLatency:               time.Second,
+	InvalidMessagePenalty:    .1,
+	ProtocolViolationPenalty: .5,
+	MinScore:                 .25,
+}
+
+func newTestTracker(t *testing.T) (*tracker, time.Time) {
+	t.Helper()
+
+	trackerIntf, err := NewTracker(testConfig, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	tracker := trackerIntf.(*tracker)
+	now := time.Now()
+	tracker.clock.Set(now)
+	return tracker, now
+}
+
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		expectedErr error
+	}{
+		{
+			name:        "valid",
+			modify:      func(*Config) {},
+			expectedErr: nil,
+		},
+		{
+			name: "zero halflife",
+			modify: func(c *Config) {
+				c.Halflife = 0
+			},
+			expectedErr: errNonPositiveHalflife,
+		},
+		{
+			name: "zero max latency",
+			modify: func(c *Config) {
+				c.MaxLatency = 0
+			},
+			expectedErr: errNonPositiveMaxLatency,
+		},
+		{
+			name: "negative penalty",
+			modify: func(c *Config) {
+				c.ProtocolViolationPenalty = -1
+			},
+			expectedErr: errNegativePenalty,
+		},
+		{
+			name: "min score too large",
+			modify: func(c *Config) {
+				c.MinScore = 1.5
+			},
+			expectedErr: errMinScoreOutOfRange,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := testConfig
+			test.modify(&config)
+			err := config.Verify()
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestTrackerUnknownPeer(t *testing.T) {
+	require := require.New(t)
+
+	tracker, _ := newTestTracker(t)
+	nodeID := ids.GenerateTestNodeID()
+	require.Equal(noScore, tracker.Get(nodeID))
+	require.False(tracker.IsPoor(nodeID))
+}
+
+func TestTrackerFailures(t *testing.T) {
+	require := require.New(t)
+
+	tracker, _ := newTestTracker(t)
+	nodeID := ids.GenerateTestNodeID()
+
+	tracker.RegisterResponse(nodeID, 0)
+	require.Equal(1., tracker.Score(nodeID))
+
+	for i := 0; i < 10; i++ {
+		tracker.RegisterFailure(nodeID)
+	}
+	require.Less(tracker.Score(nodeID), testConfig.MinScore)
+	require.True(tracker.IsPoor(nodeID))
+
+	// Other peers are unaffected.
+	require.False(tracker.IsPoor(ids.GenerateTestNodeID()))
+}
+
+func TestTrackerResponseRateDecays(t *testing.T) {
+	require := require.New(t)
+
+	tracker, now := newTestTracker(t)
+	nodeID := ids.GenerateTestNodeID()
+
+	for i := 0; i < 10; i++ {
+		tracker.RegisterFailure(nodeID)
+	}
+	require.True(tracker.IsPoor(nodeID))
+
+	// After one halflife, the missed portion of the response rate should have
+	// halved.
+	missed := 1 - tracker.Get(nodeID).ResponseRate
+	tracker.clock.Set(now.Add(testConfig.Halflife))
+	require.InDelta(missed/2, 1-tracker.Get(nodeID).ResponseRate, .0001)
+
+	// Without new requests, the peer's reputation recovers.
+	tracker.clock.Set(now.Add(10 * testConfig.Halflife))
+	require.False(tracker.IsPoor(nodeID))
+
+	// A single failure doesn't make a recovered peer poor again.
+	tracker.RegisterFailure(nodeID)
+	require.False(tracker.IsPoor(nodeID))
+}
+
+func TestTrackerLatency(t *testing.T) {
+	require := require.New(t)
+
+	tracker, _ := newTestTracker(t)
+	fastNodeID := ids.GenerateTestNodeID()
+	slowNodeID := ids.GenerateTestNodeID()
+
+	tracker.RegisterResponse(fastNodeID, testConfig.MaxLatency/10)
+	tracker.RegisterResponse(slowNodeID, 10*testConfig.MaxLatency)
+
+	fastScore := tracker.Get(fastNodeID)
+	slowScore := tracker.Get(slowNodeID)
+	require.Equal(testConfig.MaxLatency/10, fastScore.Latency)
+	require.Greater(fastScore.Score, slowScore.Score)
+
+	// Being slow is penalized less than not responding.
+	require.Equal(1-latencyWeight, slowScore.Score)
+	require.False(tracker.IsPoor(slowNodeID))
+}
+
+func TestTrackerPenaltiesDecay(t *testing.T) {
+	require := require.New(t)
+
+	tracker, now := newTestTracker(t)
+	nodeID := ids.GenerateTestNodeID()
+
+	tracker.RegisterInvalidMessage(nodeID)
+	for i := 0; i < 3; i++ {
+		tracker.RegisterProtocolViolation(nodeID)
+	}
+
+	score := tracker.Get(nodeID)
+	require.Equal(1., score.InvalidMessages)
+	require.Equal(3., score.ProtocolViolations)
+	require.True(tracker.IsPoor(nodeID))
+
+	// After one halflife, the penalties should have halved.
+	tracker.clock.Set(now.Add(testConfig.Halflife))
+	score = tracker.Get(nodeID)
+	require.InDelta(.5, score.InvalidMessages, .0001)
+	require.InDelta(1.5, score.ProtocolViolations, .0001)
+
+	// Eventually the peer's reputation recovers.
+	tracker.clock.Set(now.Add(10 * testConfig.Halflife))
+	require.False(tracker.IsPoor(nodeID))
+}
+
+func TestTrackerPrune(t *testing.T) {
+	require := require.New(t)
+
+	tracker, now := newTestTracker(t)
+	staleNodeID := ids.GenerateTestNodeID()
+	tracker.RegisterFailure(staleNodeID)
+
+	tracker.clock.Set(now.Add(pruneHalflives*testConfig.Halflife + time.Second))
+	activeNodeID := ids.GenerateTestNodeID()
+	for i := 0; i < pruneFrequency; i++ {
+		tracker.RegisterResponse(activeNodeID, 0)
+	}
+
+	require.NotContains(tracker.peers, staleNodeID)
+	require.Contains(tracker.peers, activeNodeID)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network/dialer"
 	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
@@ -85,6 +86,8 @@ func NewTestNetwork(
 		logging.NoLog{},
 		metrics,
 		constants.DefaultNetworkCompressionType,
+		nil,
+		nil,
 		constants.DefaultNetworkMaximumInboundTimeout,
 	)
 	if err != nil {
@@ -195,7 +198,9 @@ func NewTestNetwork(
 			MaximumInboundMessageTimeout: constants.DefaultNetworkMaximumInboundTimeout,
 			PeerReadBufferSize:           constants.DefaultNetworkPeerReadBufferSize,
 			PeerWriteBufferSize:          constants.DefaultNetworkPeerWriteBufferSize,
+			MessageQueueConfig:           peer.DefaultMessageQueueConfig,
 			ResourceTracker:              resourceTracker,
+			Reputation:                   reputation.NoTracker{},
 			CPUTargeter: tracker.NewTargeter(
 				logging.NoLog{},
 				&tracker.TargeterConfig{
@@ -8,11 +8,13 @@ import (
 	"net/netip"
 	"time"
 
+	"github.com/ava-labs/avalanchego/api/metrics/otlp"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
@@ -31,15 +33,34 @@ type APIIndexerConfig struct {
 	IndexAllowIncomplete bool `json:"indexAllowIncomplete"`
 }
 
+// HealthNotificationConfig configures the sinks that are notified when a
+// health check changes state.
+type HealthNotificationConfig struct {
+	// WebhookURLs are sent a POST request for each notification.
+	WebhookURLs []string `json:"webhookURLs"`
+	// WebhookMaxAttempts is the maximum number of attempts to notify a
+	// webhook.
+	WebhookMaxAttempts int `json:"webhookMaxAttempts"`
+	// ExecHook is the path to an executable that is run for each
+	// notification. If empty, no executable is run.
+	ExecHook string `json:"execHook"`
+	// Timeout of a webhook attempt or of running the ExecHook.
+	Timeout time.Duration `json:"timeout"`
+}
+
 type HTTPConfig struct {
 	server.HTTPConfig
 	APIConfig `json:"apiConfig"`
 	HTTPHost  string `json:"httpHost"`
 	HTTPPort  uint16 `json:"httpPort"`
 
-	HTTPSEnabled bool   `json:"httpsEnabled"`
-	HTTPSKey     []byte `json:"-"`
-	HTTPSCert    []byte `json:"-"`
+	HTTPSEnabled  bool   `json:"httpsEnabled"`
+	HTTPSKey      []byte `json:"-"`
+	HTTPSCert     []byte `json:"-"`
+	HTTPSClientCA []byte `json:"-"`
+
+	// HTTPAuthPolicy is nil if API calls aren't authenticated.
+	HTTPAuthPolicy *server.AuthPolicy `json:"-"`
 
 	HTTPAllowedOrigins []string `json:"httpAllowedOrigins"`
 	HTTPAllowedHosts   []string `json:"httpAllowedHosts"`
@@ -60,9 +81,11 @@ type APIConfig struct {
 }
 
 type IPConfig struct {
-	PublicIP                  string        `json:"publicIP"`
-	PublicIPResolutionService string        `json:"publicIPResolutionService"`
-	PublicIPResolutionFreq    time.Duration `json:"publicIPResolutionFreq"`
+	PublicIP                      string        `json:"publicIP"`
+	PublicIPResolutionService     string        `json:"publicIPResolutionService"`
+	PublicIPResolutionFreq        time.Duration `json:"publicIPResolutionFreq"`
+	PublicIPResolutionSTUNServers []string      `json:"publicIPResolutionSTUNServers"`
+	PublicIPResolutionSTUNQuorum  int           `json:"publicIPResolutionSTUNQuorum"`
 	// The host portion of the address to listen on. The port to
 	// listen on will be sourced from IPPort.
 	//
@@ -141,7 +164,8 @@ type Config struct {
 	NetworkID uint32 `json:"networkID"`
 
 	// Health
-	HealthCheckFreq time.Duration `json:"healthCheckFreq"`
+	HealthCheckFreq          time.Duration            `json:"healthCheckFreq"`
+	HealthNotificationConfig HealthNotificationConfig `json:"healthNotificationConfig"`
 
 	// Network configuration
 	NetworkConfig network.Config `json:"networkConfig"`
@@ -150,6 +174,8 @@ type Config struct {
 
 	BenchlistConfig benchlist.Config `json:"benchlistConfig"`
 
+	ReputationConfig reputation.Config `json:"reputationConfig"`
+
 	ProfilerConfig profiler.Config `json:"profilerConfig"`
 
 	LoggingConfig logging.Config `json:"loggingConfig"`
@@ -205,6 +231,8 @@ type Config struct {
 
 	TraceConfig trace.Config `json:"traceConfig"`
 
+	MetricsExportConfig otlp.Config `json:"metricsExportConfig"`
+
 	// See comment on [UseCurrentHeight] in platformvm.Config
 	UseCurrentHeight bool `json:"useCurrentHeight"`
 
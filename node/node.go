@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,6 +32,7 @@ import (
 	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/api/keystore"
 	"github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/api/metrics/otlp"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/chains/atomic"
@@ -48,6 +51,7 @@ import (
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/dialer"
 	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
@@ -59,6 +63,7 @@ import (
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/dynamicip"
@@ -98,6 +103,7 @@ const (
 	meterDBNamespace         = constants.PlatformName + metric.NamespaceSeparator + "meterdb"
 	networkNamespace         = constants.PlatformName + metric.NamespaceSeparator + "network"
 	processNamespace         = constants.PlatformName + metric.NamespaceSeparator + "process"
+	reputationNamespace      = constants.PlatformName + metric.NamespaceSeparator + "reputation"
 	requestsNamespace        = constants.PlatformName + metric.NamespaceSeparator + "requests"
 	resourceTrackerNamespace = constants.PlatformName + metric.NamespaceSeparator + "resource_tracker"
 	responsesNamespace       = constants.PlatformName + metric.NamespaceSeparator + "responses"
@@ -112,8 +118,9 @@ var (
 	indexerDBPrefix  = []byte{0x00}
 	keystoreDBPrefix = []byte("keystore")
 
-	errInvalidTLSKey = errors.New("invalid TLS key")
-	errShuttingDown  = errors.New("server shutting down")
+	errInvalidTLSKey        = errors.New("invalid TLS key")
+	errInvalidHTTPSClientCA = errors.New("invalid HTTPS client CA")
+	errShuttingDown         = errors.New("server shutting down")
 )
 
 // New returns an instance of Node
@@ -163,6 +170,7 @@ func New(
 		}
 	}
 	n.VMManager = vms.NewManager(n.VMFactoryLog, n.VMAliaser)
+	n.pChainStateExporter = &platformvm.StateExporter{}
 
 	if err := n.initBootstrappers(); err != nil { // Configure the bootstrappers
 		return nil, fmt.Errorf("problem initializing node beacons: %w", err)
@@ -187,6 +195,12 @@ func New(
 		return nil, fmt.Errorf("couldn't initialize metrics API: %w", err)
 	}
 
+	// Start pushing metrics
+	n.metricsExporter, err = otlp.New(n.Log, n.MetricsGatherer, n.Config.MetricsExportConfig)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't initialize metrics exporter: %w", err)
+	}
+
 	if err := n.initDatabase(); err != nil { // Set up the node's database
 		return nil, fmt.Errorf("problem initializing database: %w", err)
 	}
@@ -210,10 +224,27 @@ func New(
 		return nil, err
 	}
 
+	if samplesFile := n.Config.NetworkConfig.CompressionSamplesFile; samplesFile != "" {
+		n.compressionSamplesFile, err = os.OpenFile(
+			samplesFile,
+			os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+			perms.ReadWrite,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't open compression samples file: %w", err)
+		}
+		n.compressionSamples = compression.NewSampleWriter(
+			n.compressionSamplesFile,
+			n.Config.NetworkConfig.CompressionSamplesMaxSize,
+		)
+	}
+
 	n.msgCreator, err = message.NewCreator(
 		n.Log,
 		networkRegisterer,
 		n.Config.NetworkConfig.CompressionType,
+		n.Config.NetworkConfig.CompressionDictionaries,
+		n.compressionSamples,
 		n.Config.NetworkConfig.MaximumInboundMessageTimeout,
 	)
 	if err != nil {
@@ -269,6 +300,7 @@ func New(
 
 	n.health.Start(context.TODO(), n.Config.HealthCheckFreq)
 	n.initProfiler()
+	n.initTriggeredProfiler()
 
 	// Start the Platform chain
 	if err := n.initChains(n.Config.GenesisBytes); err != nil {
@@ -302,6 +334,10 @@ type Node struct {
 	// Profiles the process. Nil if continuous profiling is disabled.
 	profiler profiler.ContinuousProfiler
 
+	// Profiles the process when a resource threshold is exceeded. Nil if
+	// triggered profiling is disabled.
+	triggeredProfiler profiler.TriggeredProfiler
+
 	// Indexes blocks, transactions and blocks
 	indexer indexer.Indexer
 
@@ -316,6 +352,10 @@ type Node struct {
 
 	// Build and parse messages, for both network layer and chain manager
 	msgCreator message.Creator
+	// Records outbound messages to train compression dictionaries into
+	// [compressionSamplesFile]. Nil if samples aren't being recorded.
+	compressionSamples     *compression.SampleWriter
+	compressionSamplesFile *os.File
 
 	// Manages network timeouts
 	timeoutManager timeout.Manager
@@ -326,6 +366,9 @@ type Node struct {
 	// Manages validator benching
 	benchlistManager benchlist.Manager
 
+	// Scores the behavior of peers
+	reputation reputation.Tracker
+
 	uptimeCalculator uptime.LockedCalculator
 
 	// dispatcher for events as they happen in consensus
@@ -378,12 +421,18 @@ type Node struct {
 	MetricsGatherer        metrics.MultiGatherer
 	MeterDBMetricsGatherer metrics.MultiGatherer
 
+	// Pushes the metrics of [MetricsGatherer] to an OTLP endpoint
+	metricsExporter io.Closer
+
 	VMAliaser ids.Aliaser
 	VMManager vms.Manager
 
 	// VM endpoint registry
 	VMRegistry registry.VMRegistry
 
+	// Exports the state of the P-chain for the admin API
+	pChainStateExporter *platformvm.StateExporter
+
 	// Manages shutdown of a VM process
 	runtimeManager runtime.Manager
 
@@ -464,7 +513,15 @@ func (n *Node) initNetworking(reg prometheus.Registerer) error {
 		n.ipUpdater = dynamicip.NewNoUpdater()
 	case n.Config.PublicIPResolutionService != "":
 		// Use dynamic IP resolution.
-		resolver, err := dynamicip.NewResolver(n.Config.PublicIPResolutionService)
+		var resolver dynamicip.Resolver
+		if strings.EqualFold(n.Config.PublicIPResolutionService, dynamicip.STUNName) {
+			resolver, err = dynamicip.NewSTUNResolver(
+				n.Config.PublicIPResolutionSTUNServers,
+				n.Config.PublicIPResolutionSTUNQuorum,
+			)
+		} else {
+			resolver, err = dynamicip.NewResolver(n.Config.PublicIPResolutionService)
+		}
 		if err != nil {
 			return fmt.Errorf("couldn't create IP resolver: %w", err)
 		}
@@ -555,8 +612,21 @@ func (n *Node) initNetworking(reg prometheus.Registerer) error {
 		n.chainRouter = router.Trace(n.chainRouter, n.tracer)
 	}
 
+	reputationReg, err := metrics.MakeAndRegister(
+		n.MetricsGatherer,
+		reputationNamespace,
+	)
+	if err != nil {
+		return err
+	}
+	n.reputation, err = reputation.NewTracker(n.Config.ReputationConfig, reputationReg)
+	if err != nil {
+		return err
+	}
+
 	// Configure benchlist
 	n.Config.BenchlistConfig.Validators = n.vdrs
+	n.Config.BenchlistConfig.Reputation = n.reputation
 	n.Config.BenchlistConfig.Benchable = n.chainRouter
 	n.Config.BenchlistConfig.BenchlistRegisterer = metrics.NewLabelGatherer(chains.ChainLabel)
 
@@ -627,6 +697,7 @@ func (n *Node) initNetworking(reg prometheus.Registerer) error {
 	n.Config.NetworkConfig.UptimeCalculator = n.uptimeCalculator
 	n.Config.NetworkConfig.UptimeRequirement = n.Config.UptimeRequirement
 	n.Config.NetworkConfig.ResourceTracker = n.resourceTracker
+	n.Config.NetworkConfig.Reputation = n.reputation
 	n.Config.NetworkConfig.CPUTargeter = n.cpuTargeter
 	n.Config.NetworkConfig.DiskTargeter = n.diskTargeter
 
@@ -911,6 +982,9 @@ func (n *Node) initIndexer() error {
 		ShutdownF: func() {
 			n.Shutdown(0) // TODO put exit code here
 		},
+		AllowedOrigins: n.Config.HTTPAllowedOrigins,
+		Validators:     n.vdrs,
+		Health:         n.health,
 	})
 	if err != nil {
 		return fmt.Errorf("couldn't create index for txs: %w", err)
@@ -954,7 +1028,7 @@ func (n *Node) initNAT() {
 	if n.Config.PublicIP == "" && n.Config.PublicIPResolutionService == "" {
 		n.router = nat.GetRouter()
 		if !n.router.SupportsNAT() {
-			n.Log.Warn("UPnP and NAT-PMP router attach failed, " +
+			n.Log.Warn("UPnP, PCP and NAT-PMP router attach failed, " +
 				"you may not be listening publicly. " +
 				"Please confirm the settings in your router")
 		}
@@ -1028,6 +1102,16 @@ func (n *Node) initAPIServer() error {
 			MinVersion:   tls.VersionTLS12,
 			Certificates: []tls.Certificate{cert},
 		}
+		if len(n.Config.HTTPSClientCA) > 0 {
+			clientCAs := x509.NewCertPool()
+			if !clientCAs.AppendCertsFromPEM(n.Config.HTTPSClientCA) {
+				return errInvalidHTTPSClientCA
+			}
+			// Clients that don't provide a certificate may still authenticate
+			// with a bearer token.
+			config.ClientCAs = clientCAs
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+		}
 		listener = tls.NewListener(listener, config)
 
 		protocol = "https"
@@ -1054,6 +1138,7 @@ func (n *Node) initAPIServer() error {
 		apiRegisterer,
 		n.Config.HTTPConfig.HTTPConfig,
 		n.Config.HTTPAllowedHosts,
+		n.Config.HTTPAuthPolicy,
 	)
 	return err
 }
@@ -1114,6 +1199,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	n.timeoutManager, err = timeout.NewManager(
 		&n.Config.AdaptiveTimeoutConfig,
 		n.benchlistManager,
+		n.reputation,
 		requestsReg,
 		responseReg,
 	)
@@ -1172,6 +1258,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 			CChainID:                                cChainID,
 			CriticalChains:                          criticalChains,
 			TimeoutManager:                          n.timeoutManager,
+			Reputation:                              n.reputation,
 			Health:                                  n.health,
 			ShutdownNodeFunc:                        n.Shutdown,
 			MeterVMEnabled:                          n.Config.MeterVMEnabled,
@@ -1218,6 +1305,7 @@ func (n *Node) initVMs() error {
 	// Register the VMs that Avalanche supports
 	err := errors.Join(
 		n.VMManager.RegisterFactory(context.TODO(), constants.PlatformVMID, &platformvm.Factory{
+			StateExporter: n.pChainStateExporter,
 			Config: platformconfig.Config{
 				Chains:                    n.chainManager,
 				Validators:                vdrs,
@@ -1228,6 +1316,7 @@ func (n *Node) initVMs() error {
 				CreateAssetTxFee:          n.Config.CreateAssetTxFee,
 				StaticFeeConfig:           n.Config.StaticFeeConfig,
 				DynamicFeeConfig:          n.Config.DynamicFeeConfig,
+				ValidatorFeeRate:          n.Config.ValidatorFeeRate,
 				UptimePercentage:          n.Config.UptimeRequirement,
 				MinValidatorStake:         n.Config.MinValidatorStake,
 				MaxValidatorStake:         n.Config.MaxValidatorStake,
@@ -1368,6 +1457,8 @@ func (n *Node) initAdminAPI() error {
 			NodeConfig:   n.Config,
 			VMManager:    n.VMManager,
 			VMRegistry:   n.VMRegistry,
+
+			PChainStateExporter: n.pChainStateExporter,
 		},
 	)
 	if err != nil {
@@ -1404,6 +1495,31 @@ func (n *Node) initProfiler() {
 	})
 }
 
+// initTriggeredProfiler initializes the profiling triggered by resource usage.
+// Assumes [n.resourceTracker] is already initialized.
+func (n *Node) initTriggeredProfiler() {
+	if !n.Config.ProfilerConfig.Trigger.Enabled {
+		n.Log.Info("skipping triggered profiler initialization because it has been disabled")
+		return
+	}
+
+	n.Log.Info("initializing triggered profiler")
+	n.triggeredProfiler = profiler.NewTriggered(
+		n.Log,
+		filepath.Join(n.Config.ProfilerConfig.Dir, "triggered"),
+		n.resourceTracker.CPUTracker(),
+		n.Config.ProfilerConfig.Trigger,
+	)
+	go n.Log.RecoverAndPanic(func() {
+		err := n.triggeredProfiler.Dispatch()
+		if err != nil {
+			n.Log.Fatal("triggered profiler failed",
+				zap.Error(err),
+			)
+		}
+	})
+}
+
 func (n *Node) initInfoAPI() error {
 	if !n.Config.InfoAPIEnabled {
 		n.Log.Info("skipping info API initialization because it has been disabled")
@@ -1428,6 +1544,7 @@ func (n *Node) initInfoAPI() error {
 		n.Config.NetworkConfig.MyIPPort,
 		n.Net,
 		n.benchlistManager,
+		n.reputation,
 	)
 	if err != nil {
 		return err
@@ -1450,7 +1567,23 @@ func (n *Node) initHealthAPI() error {
 		return err
 	}
 
-	n.health, err = health.New(n.Log, healthReg)
+	notificationConfig := n.Config.HealthNotificationConfig
+	notifiers := make([]health.Notifier, 0, len(notificationConfig.WebhookURLs)+1)
+	for _, url := range notificationConfig.WebhookURLs {
+		notifiers = append(notifiers, health.NewWebhookNotifier(
+			url,
+			notificationConfig.WebhookMaxAttempts,
+			notificationConfig.Timeout,
+		))
+	}
+	if notificationConfig.ExecHook != "" {
+		notifiers = append(notifiers, health.NewExecNotifier(
+			notificationConfig.ExecHook,
+			notificationConfig.Timeout,
+		))
+	}
+
+	n.health, err = health.New(n.Log, healthReg, notifiers...)
 	if err != nil {
 		return err
 	}
@@ -1694,9 +1827,24 @@ func (n *Node) shutdown() {
 	if n.profiler != nil {
 		n.profiler.Shutdown()
 	}
+	if n.triggeredProfiler != nil {
+		n.triggeredProfiler.Shutdown()
+	}
 	if n.Net != nil {
 		n.Net.StartClose()
 	}
+	if n.compressionSamples != nil {
+		if err := n.compressionSamples.Close(); err != nil {
+			n.Log.Debug("error writing compression samples",
+				zap.Error(err),
+			)
+		}
+		if err := n.compressionSamplesFile.Close(); err != nil {
+			n.Log.Debug("error closing compression samples file",
+				zap.Error(err),
+			)
+		}
+	}
 	if err := n.APIServer.Shutdown(); err != nil {
 		n.Log.Debug("error during API shutdown",
 			zap.Error(err),
@@ -1729,6 +1877,16 @@ func (n *Node) shutdown() {
 		}
 	}
 
+	if n.Config.MetricsExportConfig.Enabled {
+		n.Log.Info("shutting down metrics exporter")
+	}
+
+	if err := n.metricsExporter.Close(); err != nil {
+		n.Log.Warn("error during metrics exporter shutdown",
+			zap.Error(err),
+		)
+	}
+
 	if n.Config.TraceConfig.Enabled {
 		n.Log.Info("shutting down tracing")
 	}
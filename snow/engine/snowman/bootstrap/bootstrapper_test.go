@@ -17,6 +17,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network/p2p"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman/snowmantest"
@@ -84,6 +85,7 @@ func newConfig(t *testing.T) (Config, ids.NodeID, *common.SenderTest, *block.Tes
 		prometheus.NewRegistry(),
 		nil,
 		nil,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -135,6 +137,7 @@ func TestBootstrapperStartsOnlyIfEnoughStakeIsConnected(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		nil,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -665,6 +668,7 @@ func TestBootstrapNoParseOnNew(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		nil,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
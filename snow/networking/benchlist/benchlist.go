@@ -14,6 +14,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/heap"
@@ -68,6 +69,9 @@ type benchlist struct {
 	// Validator set of the network
 	vdrs validators.Manager
 
+	// Reputation of peers across all chains
+	reputation reputation.Scorer
+
 	// Validator ID --> Consecutive failure information
 	// [streaklock] must be held when touching [failureStreaks]
 	streaklock     sync.Mutex
@@ -81,7 +85,8 @@ type benchlist struct {
 
 	// A validator will be benched if [threshold] messages in a row
 	// to them time out and the first of those messages was more than
-	// [minimumFailingDuration] ago
+	// [minimumFailingDuration] ago, or if a message to them times out while
+	// their reputation is poor
 	threshold              int
 	minimumFailingDuration time.Duration
 
@@ -98,6 +103,7 @@ func NewBenchlist(
 	ctx *snow.ConsensusContext,
 	benchable Benchable,
 	validators validators.Manager,
+	reputation reputation.Scorer,
 	threshold int,
 	minimumFailingDuration,
 	duration time.Duration,
@@ -124,6 +130,7 @@ func NewBenchlist(
 		benchable:              benchable,
 		benchedHeap:            heap.NewMap[ids.NodeID, time.Time](time.Time.Before),
 		vdrs:                   validators,
+		reputation:             reputation,
 		threshold:              threshold,
 		minimumFailingDuration: minimumFailingDuration,
 		duration:               duration,
@@ -271,13 +278,17 @@ func (b *benchlist) RegisterFailure(nodeID ids.NodeID) {
 	b.streaklock.Unlock()
 
 	if failureStreak.consecutive >= b.threshold && now.After(failureStreak.firstFailure.Add(b.minimumFailingDuration)) {
-		b.bench(nodeID)
+		b.bench(nodeID, "consecutive failed queries", failureStreak.consecutive)
+		return
+	}
+	if b.reputation.IsPoor(nodeID) {
+		b.bench(nodeID, "poor reputation", failureStreak.consecutive)
 	}
 }
 
 // Assumes [b.lock] is held
 // Assumes [nodeID] is not already benched
-func (b *benchlist) bench(nodeID ids.NodeID) {
+func (b *benchlist) bench(nodeID ids.NodeID, reason string, numFailures int) {
 	validatorStake := b.vdrs.GetWeight(b.ctx.SubnetID, nodeID)
 	if validatorStake == 0 {
 		// We might want to bench a non-validator because they don't respond to
@@ -332,10 +343,12 @@ func (b *benchlist) bench(nodeID ids.NodeID) {
 	diff := maxBenchedUntil.Sub(minBenchedUntil)
 	benchedUntil := minBenchedUntil.Add(time.Duration(rand.Float64() * float64(diff))) // #nosec G404
 
-	b.ctx.Log.Debug("benching validator after consecutive failed queries",
+	b.ctx.Log.Debug("benching validator",
+		zap.String("reason", reason),
 		zap.Stringer("nodeID", nodeID),
 		zap.Duration("benchDuration", benchedUntil.Sub(now)),
-		zap.Int("numFailedQueries", b.threshold),
+		zap.Int("numFailedQueries", numFailures),
+		zap.Float64("reputation", b.reputation.Score(nodeID)),
 	)
 
 	// Add to benchlist times with randomized delay
@@ -11,8 +11,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/snow/snowtest"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/set"
 )
 
 var minimumFailingDuration = 5 * time.Minute
@@ -46,6 +48,7 @@ func TestBenchlistAdd(t *testing.T) {
 		ctx,
 		benchable,
 		vdrs,
+		reputation.NoTracker{},
 		threshold,
 		minimumFailingDuration,
 		duration,
@@ -171,6 +174,7 @@ func TestBenchlistMaxStake(t *testing.T) {
 		ctx,
 		&TestBenchable{T: t},
 		vdrs,
+		reputation.NoTracker{},
 		threshold,
 		minimumFailingDuration,
 		duration,
@@ -294,6 +298,7 @@ func TestBenchlistRemove(t *testing.T) {
 		ctx,
 		benchable,
 		vdrs,
+		reputation.NoTracker{},
 		threshold,
 		minimumFailingDuration,
 		duration,
@@ -365,3 +370,73 @@ func TestBenchlistRemove(t *testing.T) {
 
 	require.Equal(3, count)
 }
+
+type poorScorer struct {
+	poor set.Set[ids.NodeID]
+}
+
+func (s poorScorer) Score(nodeID ids.NodeID) float64 {
+	if s.poor.Contains(nodeID) {
+		return 0
+	}
+	return 1
+}
+
+func (s poorScorer) IsPoor(nodeID ids.NodeID) bool {
+	return s.poor.Contains(nodeID)
+}
+
+// Test that validators with a poor reputation are benched on their first
+// failure
+func TestBenchlistPoorReputation(t *testing.T) {
+	require := require.New(t)
+
+	snowCtx := snowtest.Context(t, snowtest.CChainID)
+	ctx := snowtest.ConsensusContext(snowCtx)
+	vdrs := validators.NewManager()
+	vdrID0 := ids.GenerateTestNodeID()
+	vdrID1 := ids.GenerateTestNodeID()
+	vdrID2 := ids.GenerateTestNodeID()
+
+	require.NoError(vdrs.AddStaker(ctx.SubnetID, vdrID0, nil, ids.Empty, 50))
+	require.NoError(vdrs.AddStaker(ctx.SubnetID, vdrID1, nil, ids.Empty, 50))
+	require.NoError(vdrs.AddStaker(ctx.SubnetID, vdrID2, nil, ids.Empty, 50))
+
+	benchable := &TestBenchable{T: t}
+	benchable.Default(true)
+
+	threshold := 3
+	duration := time.Minute
+	maxPortion := 0.5
+	benchIntf, err := NewBenchlist(
+		ctx,
+		benchable,
+		vdrs,
+		poorScorer{poor: set.Of(vdrID0)},
+		threshold,
+		minimumFailingDuration,
+		duration,
+		maxPortion,
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+	b := benchIntf.(*benchlist)
+	b.clock.Set(time.Now())
+
+	benched := false
+	benchable.BenchedF = func(ids.ID, ids.NodeID) {
+		benched = true
+	}
+
+	// A single failure of a validator with a good reputation shouldn't bench
+	// it
+	b.RegisterFailure(vdrID1)
+	require.False(benched)
+	require.Empty(b.benchlistSet)
+
+	// A single failure of a validator with a poor reputation should bench it
+	b.RegisterFailure(vdrID0)
+	require.True(benched)
+	require.Contains(b.benchlistSet, vdrID0)
+	require.Equal(1, b.benchedHeap.Len())
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/validators"
 )
@@ -42,6 +43,7 @@ type Manager interface {
 type Config struct {
 	Benchable              Benchable             `json:"-"`
 	Validators             validators.Manager    `json:"-"`
+	Reputation             reputation.Scorer     `json:"-"`
 	BenchlistRegisterer    metrics.MultiGatherer `json:"-"`
 	Threshold              int                   `json:"threshold"`
 	MinimumFailingDuration time.Duration         `json:"minimumFailingDuration"`
@@ -122,6 +124,7 @@ func (m *manager) RegisterChain(ctx *snow.ConsensusContext) error {
 		ctx,
 		m.config.Benchable,
 		m.config.Validators,
+		m.config.Reputation,
 		m.config.Threshold,
 		m.config.MinimumFailingDuration,
 		m.config.Duration,
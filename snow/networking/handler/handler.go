@@ -13,7 +13,6 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 
@@ -26,12 +25,14 @@ import (
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 
 	p2ppb "github.com/ava-labs/avalanchego/proto/pb/p2p"
 	commontracker "github.com/ava-labs/avalanchego/snow/engine/common/tracker"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -458,6 +459,7 @@ func (h *handler) dispatchChans(ctx context.Context) {
 
 // Any returned error is treated as fatal
 func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
+	ctx = withTraceContext(ctx, msg)
 	var (
 		nodeID    = msg.NodeID()
 		op        = msg.Op().String()
@@ -804,6 +806,7 @@ func (h *handler) handleAsyncMsg(ctx context.Context, msg Message) {
 
 // Any returned error is treated as fatal
 func (h *handler) executeAsyncMsg(ctx context.Context, msg Message) error {
+	ctx = withTraceContext(ctx, msg)
 	var (
 		nodeID    = msg.NodeID()
 		op        = msg.Op().String()
@@ -1016,8 +1019,8 @@ func (h *handler) popUnexpiredMsg(queue MessageQueue) (context.Context, Message,
 				zap.Stringer("nodeID", msg.NodeID()),
 				zap.String("messageOp", op),
 			)
-			span := trace.SpanFromContext(ctx)
-			span.AddEvent("dropping message", trace.WithAttributes(
+			span := oteltrace.SpanFromContext(ctx)
+			span.AddEvent("dropping message", oteltrace.WithAttributes(
 				attribute.String("reason", "timeout"),
 			))
 			h.metrics.expired.With(prometheus.Labels{
@@ -1068,3 +1071,14 @@ func (h *handler) shutdown(ctx context.Context, startClosingTime time.Time) {
 		)
 	}
 }
+
+// withTraceContext returns [ctx] with the span that sent [msg] as the parent of
+// the spans started while handling [msg], so that a trace can be followed
+// across nodes.
+func withTraceContext(ctx context.Context, msg message.InboundMessage) context.Context {
+	traceContext := msg.TraceContext()
+	if traceContext == nil {
+		return ctx
+	}
+	return trace.Extract(ctx, traceContext.Traceparent, traceContext.Tracestate)
+}
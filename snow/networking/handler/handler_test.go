@@ -17,12 +17,14 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network/p2p"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/snowtest"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/math/meter"
 	"github.com/ava-labs/avalanchego/utils/resource"
@@ -31,6 +33,7 @@ import (
 
 	p2ppb "github.com/ava-labs/avalanchego/proto/pb/p2p"
 	commontracker "github.com/ava-labs/avalanchego/snow/engine/common/tracker"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 const testThreadPoolSize = 2
@@ -63,6 +66,7 @@ func TestHandlerDropsTimedOutMessages(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -170,6 +174,7 @@ func TestHandlerClosesOnError(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -273,6 +278,7 @@ func TestHandlerDropsGossipDuringBootstrapping(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -364,6 +370,7 @@ func TestHandlerDispatchInternal(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -450,6 +457,7 @@ func TestHandlerSubnetConnector(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -632,6 +640,7 @@ func TestDynamicEngineTypeDispatch(t *testing.T) {
 				prometheus.NewRegistry(),
 				nil,
 				version.CurrentApp,
+				reputation.NoTracker{},
 			)
 			require.NoError(err)
 
@@ -715,6 +724,7 @@ func TestHandlerStartError(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -745,3 +755,40 @@ func TestHandlerStartError(t *testing.T) {
 	_, err = handler.AwaitStopped(context.Background())
 	require.NoError(err)
 }
+
+func TestWithTraceContext(t *testing.T) {
+	require := require.New(t)
+
+	mc, err := message.NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		compression.TypeZstd,
+		nil,
+		nil,
+		10*time.Second,
+	)
+	require.NoError(err)
+
+	outboundMsg, err := mc.AppGossip(ids.GenerateTestID(), []byte{1})
+	require.NoError(err)
+
+	untracedMsg, err := mc.Parse(outboundMsg.Bytes(), ids.EmptyNodeID, func() {})
+	require.NoError(err)
+	ctx := withTraceContext(context.Background(), untracedMsg)
+	require.False(oteltrace.SpanContextFromContext(ctx).IsValid())
+
+	tracedOutboundMsg, err := message.WithTraceContext(outboundMsg, &p2ppb.TraceContext{
+		Traceparent: "00-01000000000000000000000000000000-0200000000000000-01",
+	})
+	require.NoError(err)
+	tracedMsg, err := mc.Parse(tracedOutboundMsg.Bytes(), ids.EmptyNodeID, func() {})
+	require.NoError(err)
+
+	// The span that sent the message is the parent of the spans started while
+	// handling it.
+	ctx = withTraceContext(context.Background(), tracedMsg)
+	spanContext := oteltrace.SpanContextFromContext(ctx)
+	require.True(spanContext.IsRemote())
+	require.Equal(oteltrace.TraceID{1}, spanContext.TraceID())
+	require.Equal(oteltrace.SpanID{2}, spanContext.SpanID())
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network/p2p"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
@@ -61,6 +62,7 @@ func TestShutdown(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		reputation.NoTracker{},
 		prometheus.NewRegistry(),
 		prometheus.NewRegistry(),
 	)
@@ -99,6 +101,7 @@ func TestShutdown(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -225,6 +228,7 @@ func TestConnectedAfterShutdownErrorLogRegression(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -321,6 +325,7 @@ func TestShutdownTimesOut(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist,
+		reputation.NoTracker{},
 		prometheus.NewRegistry(),
 		prometheus.NewRegistry(),
 	)
@@ -358,6 +363,7 @@ func TestShutdownTimesOut(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -470,6 +476,7 @@ func TestRouterTimeout(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		reputation.NoTracker{},
 		prometheus.NewRegistry(),
 		prometheus.NewRegistry(),
 	)
@@ -528,6 +535,7 @@ func TestRouterTimeout(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -839,6 +847,7 @@ func TestRouterHonorsRequestedEngine(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		reputation.NoTracker{},
 		prometheus.NewRegistry(),
 		prometheus.NewRegistry(),
 	)
@@ -1063,6 +1072,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		reputation.NoTracker{},
 		prometheus.NewRegistry(),
 		prometheus.NewRegistry(),
 	)
@@ -1111,6 +1121,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -1224,6 +1235,7 @@ func TestConnectedSubnet(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		reputation.NoTracker{},
 		prometheus.NewRegistry(),
 		prometheus.NewRegistry(),
 	)
@@ -1340,6 +1352,7 @@ func TestValidatorOnlyAllowedNodeMessageDrops(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		reputation.NoTracker{},
 		prometheus.NewRegistry(),
 		prometheus.NewRegistry(),
 	)
@@ -1392,6 +1405,7 @@ func TestValidatorOnlyAllowedNodeMessageDrops(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -1690,6 +1704,7 @@ func newChainRouterTest(t *testing.T) (*ChainRouter, *common.EngineTest) {
 			TimeoutHalflife:    5 * time.Minute,
 		},
 		benchlist.NewNoBenchlist(),
+		reputation.NoTracker{},
 		prometheus.NewRegistry(),
 		prometheus.NewRegistry(),
 	)
@@ -1732,6 +1747,7 @@ func newChainRouterTest(t *testing.T) (*ChainRouter, *common.EngineTest) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(t, err)
 
@@ -17,6 +17,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
 )
@@ -132,7 +133,7 @@ func (s *sender) SendGetStateSummaryFrontier(ctx context.Context, nodeIDs set.Se
 	var sentTo set.Set[ids.NodeID]
 	if err == nil {
 		sentTo = s.sender.Send(
-			outMsg,
+			s.withTraceContext(ctx, outMsg),
 			common.SendConfig{
 				NodeIDs: nodeIDs,
 			},
@@ -196,7 +197,7 @@ func (s *sender) SendStateSummaryFrontier(ctx context.Context, nodeID ids.NodeID
 	// Send the message over the network.
 	nodeIDs := set.Of(nodeID)
 	sentTo := s.sender.Send(
-		outMsg,
+		s.withTraceContext(ctx, outMsg),
 		common.SendConfig{
 			NodeIDs: nodeIDs,
 		},
@@ -279,7 +280,7 @@ func (s *sender) SendGetAcceptedStateSummary(ctx context.Context, nodeIDs set.Se
 	var sentTo set.Set[ids.NodeID]
 	if err == nil {
 		sentTo = s.sender.Send(
-			outMsg,
+			s.withTraceContext(ctx, outMsg),
 			common.SendConfig{
 				NodeIDs: nodeIDs,
 			},
@@ -343,7 +344,7 @@ func (s *sender) SendAcceptedStateSummary(ctx context.Context, nodeID ids.NodeID
 	// Send the message over the network.
 	nodeIDs := set.Of(nodeID)
 	sentTo := s.sender.Send(
-		outMsg,
+		s.withTraceContext(ctx, outMsg),
 		common.SendConfig{
 			NodeIDs: nodeIDs,
 		},
@@ -415,7 +416,7 @@ func (s *sender) SendGetAcceptedFrontier(ctx context.Context, nodeIDs set.Set[id
 	var sentTo set.Set[ids.NodeID]
 	if err == nil {
 		sentTo = s.sender.Send(
-			outMsg,
+			s.withTraceContext(ctx, outMsg),
 			common.SendConfig{
 				NodeIDs: nodeIDs,
 			},
@@ -479,7 +480,7 @@ func (s *sender) SendAcceptedFrontier(ctx context.Context, nodeID ids.NodeID, re
 	// Send the message over the network.
 	nodeIDs := set.Of(nodeID)
 	sentTo := s.sender.Send(
-		outMsg,
+		s.withTraceContext(ctx, outMsg),
 		common.SendConfig{
 			NodeIDs: nodeIDs,
 		},
@@ -553,7 +554,7 @@ func (s *sender) SendGetAccepted(ctx context.Context, nodeIDs set.Set[ids.NodeID
 	var sentTo set.Set[ids.NodeID]
 	if err == nil {
 		sentTo = s.sender.Send(
-			outMsg,
+			s.withTraceContext(ctx, outMsg),
 			common.SendConfig{
 				NodeIDs: nodeIDs,
 			},
@@ -613,7 +614,7 @@ func (s *sender) SendAccepted(ctx context.Context, nodeID ids.NodeID, requestID
 	// Send the message over the network.
 	nodeIDs := set.Of(nodeID)
 	sentTo := s.sender.Send(
-		outMsg,
+		s.withTraceContext(ctx, outMsg),
 		common.SendConfig{
 			NodeIDs: nodeIDs,
 		},
@@ -698,7 +699,7 @@ func (s *sender) SendGetAncestors(ctx context.Context, nodeID ids.NodeID, reques
 	// Send the message over the network.
 	nodeIDs := set.Of(nodeID)
 	sentTo := s.sender.Send(
-		outMsg,
+		s.withTraceContext(ctx, outMsg),
 		common.SendConfig{
 			NodeIDs: nodeIDs,
 		},
@@ -719,7 +720,7 @@ func (s *sender) SendGetAncestors(ctx context.Context, nodeID ids.NodeID, reques
 	}
 }
 
-func (s *sender) SendAncestors(_ context.Context, nodeID ids.NodeID, requestID uint32, containers [][]byte) {
+func (s *sender) SendAncestors(ctx context.Context, nodeID ids.NodeID, requestID uint32, containers [][]byte) {
 	// Create the outbound message.
 	outMsg, err := s.msgCreator.Ancestors(s.ctx.ChainID, requestID, containers)
 	if err != nil {
@@ -736,7 +737,7 @@ func (s *sender) SendAncestors(_ context.Context, nodeID ids.NodeID, requestID u
 	// Send the message over the network.
 	nodeIDs := set.Of(nodeID)
 	sentTo := s.sender.Send(
-		outMsg,
+		s.withTraceContext(ctx, outMsg),
 		common.SendConfig{
 			NodeIDs: nodeIDs,
 		},
@@ -808,7 +809,7 @@ func (s *sender) SendGet(ctx context.Context, nodeID ids.NodeID, requestID uint3
 	if err == nil {
 		nodeIDs := set.Of(nodeID)
 		sentTo = s.sender.Send(
-			outMsg,
+			s.withTraceContext(ctx, outMsg),
 			common.SendConfig{
 				NodeIDs: nodeIDs,
 			},
@@ -840,7 +841,7 @@ func (s *sender) SendGet(ctx context.Context, nodeID ids.NodeID, requestID uint3
 	}
 }
 
-func (s *sender) SendPut(_ context.Context, nodeID ids.NodeID, requestID uint32, container []byte) {
+func (s *sender) SendPut(ctx context.Context, nodeID ids.NodeID, requestID uint32, container []byte) {
 	// Create the outbound message.
 	outMsg, err := s.msgCreator.Put(s.ctx.ChainID, requestID, container)
 	if err != nil {
@@ -857,7 +858,7 @@ func (s *sender) SendPut(_ context.Context, nodeID ids.NodeID, requestID uint32,
 	// Send the message over the network.
 	nodeIDs := set.Of(nodeID)
 	sentTo := s.sender.Send(
-		outMsg,
+		s.withTraceContext(ctx, outMsg),
 		common.SendConfig{
 			NodeIDs: nodeIDs,
 		},
@@ -971,7 +972,7 @@ func (s *sender) SendPushQuery(
 	var sentTo set.Set[ids.NodeID]
 	if err == nil {
 		sentTo = s.sender.Send(
-			outMsg,
+			s.withTraceContext(ctx, outMsg),
 			common.SendConfig{
 				NodeIDs: nodeIDs,
 			},
@@ -1107,7 +1108,7 @@ func (s *sender) SendPullQuery(
 	var sentTo set.Set[ids.NodeID]
 	if err == nil {
 		sentTo = s.sender.Send(
-			outMsg,
+			s.withTraceContext(ctx, outMsg),
 			common.SendConfig{
 				NodeIDs: nodeIDs,
 			},
@@ -1192,7 +1193,7 @@ func (s *sender) SendChits(
 	// Send the message over the network.
 	nodeIDs := set.Of(nodeID)
 	sentTo := s.sender.Send(
-		outMsg,
+		s.withTraceContext(ctx, outMsg),
 		common.SendConfig{
 			NodeIDs: nodeIDs,
 		},
@@ -1359,7 +1360,7 @@ func (s *sender) SendAppRequest(ctx context.Context, nodeIDs set.Set[ids.NodeID]
 	var sentTo set.Set[ids.NodeID]
 	if err == nil {
 		sentTo = s.sender.Send(
-			outMsg,
+			s.withTraceContext(ctx, outMsg),
 			common.SendConfig{
 				NodeIDs: nodeIDs,
 			},
@@ -1444,7 +1445,7 @@ func (s *sender) SendAppResponse(ctx context.Context, nodeID ids.NodeID, request
 	// Send the message over the network.
 	nodeIDs := set.Of(nodeID)
 	sentTo := s.sender.Send(
-		outMsg,
+		s.withTraceContext(ctx, outMsg),
 		common.SendConfig{
 			NodeIDs: nodeIDs,
 		},
@@ -1509,7 +1510,7 @@ func (s *sender) SendAppError(ctx context.Context, nodeID ids.NodeID, requestID
 
 	// Send the message over the network.
 	sentTo := s.sender.Send(
-		outMsg,
+		s.withTraceContext(ctx, outMsg),
 		common.SendConfig{
 			NodeIDs: set.Of(nodeID),
 		},
@@ -1541,7 +1542,7 @@ func (s *sender) SendAppError(ctx context.Context, nodeID ids.NodeID, requestID
 }
 
 func (s *sender) SendAppGossip(
-	_ context.Context,
+	ctx context.Context,
 	config common.SendConfig,
 	appGossipBytes []byte,
 ) error {
@@ -1558,7 +1559,7 @@ func (s *sender) SendAppGossip(
 	}
 
 	sentTo := s.sender.Send(
-		outMsg,
+		s.withTraceContext(ctx, outMsg),
 		config,
 		s.ctx.SubnetID,
 		s.subnet,
@@ -1579,3 +1580,25 @@ func (s *sender) SendAppGossip(
 	}
 	return nil
 }
+
+// withTraceContext returns [msg] with the trace context of [ctx], so that the
+// recipients of [msg] can continue the trace.
+func (s *sender) withTraceContext(ctx context.Context, msg message.OutboundMessage) message.OutboundMessage {
+	traceParent, traceState, ok := trace.Inject(ctx)
+	if !ok {
+		return msg
+	}
+
+	tracedMsg, err := message.WithTraceContext(msg, &p2p.TraceContext{
+		Traceparent: traceParent,
+		Tracestate:  traceState,
+	})
+	if err != nil {
+		s.ctx.Log.Debug("failed to add trace context",
+			zap.Stringer("messageOp", msg.Op()),
+			zap.Error(err),
+		)
+		return msg
+	}
+	return tracedMsg
+}
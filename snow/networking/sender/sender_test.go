@@ -12,11 +12,13 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/mock/gomock"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network/p2p"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
@@ -58,6 +60,7 @@ func TestTimeout(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		reputation.NoTracker{},
 		prometheus.NewRegistry(),
 		prometheus.NewRegistry(),
 	)
@@ -71,6 +74,8 @@ func TestTimeout(t *testing.T) {
 		logging.NoLog{},
 		metrics,
 		constants.DefaultNetworkCompressionType,
+		nil,
+		nil,
 		10*time.Second,
 	)
 	require.NoError(err)
@@ -118,6 +123,7 @@ func TestTimeout(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -334,6 +340,7 @@ func TestReliableMessages(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		reputation.NoTracker{},
 		prometheus.NewRegistry(),
 		prometheus.NewRegistry(),
 	)
@@ -348,6 +355,8 @@ func TestReliableMessages(t *testing.T) {
 		logging.NoLog{},
 		metrics,
 		constants.DefaultNetworkCompressionType,
+		nil,
+		nil,
 		10*time.Second,
 	)
 	require.NoError(err)
@@ -395,6 +404,7 @@ func TestReliableMessages(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -491,6 +501,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 		},
 		benchlist,
+		reputation.NoTracker{},
 		prometheus.NewRegistry(),
 		prometheus.NewRegistry(),
 	)
@@ -505,6 +516,8 @@ func TestReliableMessagesToMyself(t *testing.T) {
 		logging.NoLog{},
 		metrics,
 		constants.DefaultNetworkCompressionType,
+		nil,
+		nil,
 		10*time.Second,
 	)
 	require.NoError(err)
@@ -552,6 +565,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 		prometheus.NewRegistry(),
 		nil,
 		version.CurrentApp,
+		reputation.NoTracker{},
 	)
 	require.NoError(err)
 
@@ -1351,3 +1365,61 @@ func TestSender_Single_Request(t *testing.T) {
 		})
 	}
 }
+
+func TestSenderTraceContext(t *testing.T) {
+	require := require.New(t)
+
+	snowCtx := snowtest.Context(t, snowtest.CChainID)
+	ctx := snowtest.ConsensusContext(snowCtx)
+	mc, err := message.NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		constants.DefaultNetworkCompressionType,
+		nil,
+		nil,
+		10*time.Second,
+	)
+	require.NoError(err)
+
+	var sentMsgs []message.OutboundMessage
+	externalSender := &ExternalSenderTest{
+		TB: t,
+		SendF: func(msg message.OutboundMessage, config common.SendConfig, _ ids.ID, _ subnets.Allower) set.Set[ids.NodeID] {
+			sentMsgs = append(sentMsgs, msg)
+			return config.NodeIDs
+		},
+	}
+	sender, err := New(
+		ctx,
+		mc,
+		externalSender,
+		nil,
+		nil,
+		p2ppb.EngineType_ENGINE_TYPE_SNOWMAN,
+		subnets.New(ctx.NodeID, subnets.Config{}),
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	sendConfig := common.SendConfig{
+		NodeIDs: set.Of(ids.GenerateTestNodeID()),
+	}
+	require.NoError(sender.SendAppGossip(context.Background(), sendConfig, []byte{1}))
+	require.NoError(sender.SendAppGossip(trace.ContextWithSpanContext(context.Background(), spanContext), sendConfig, []byte{2}))
+	require.Len(sentMsgs, 2)
+
+	// Messages are only traced if they are sent from a span.
+	untracedMsg, err := mc.Parse(sentMsgs[0].Bytes(), ids.EmptyNodeID, func() {})
+	require.NoError(err)
+	require.Nil(untracedMsg.TraceContext())
+
+	tracedMsg, err := mc.Parse(sentMsgs[1].Bytes(), ids.EmptyNodeID, func() {})
+	require.NoError(err)
+	require.Equal(message.AppGossipOp, tracedMsg.Op())
+	require.Equal("00-01000000000000000000000000000000-0200000000000000-01", tracedMsg.TraceContext().Traceparent)
+}
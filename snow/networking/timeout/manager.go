@@ -12,6 +12,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/utils/timer"
@@ -71,6 +72,7 @@ type Manager interface {
 func NewManager(
 	timeoutConfig *timer.AdaptiveTimeoutConfig,
 	benchlistMgr benchlist.Manager,
+	reputation reputation.Tracker,
 	requestReg prometheus.Registerer,
 	responseReg prometheus.Registerer,
 ) (Manager, error) {
@@ -90,6 +92,7 @@ func NewManager(
 	return &manager{
 		tm:           tm,
 		benchlistMgr: benchlistMgr,
+		reputation:   reputation,
 		metrics:      m,
 	}, nil
 }
@@ -97,6 +100,7 @@ func NewManager(
 type manager struct {
 	tm           timer.AdaptiveTimeoutManager
 	benchlistMgr benchlist.Manager
+	reputation   reputation.Tracker
 	metrics      *timeoutMetrics
 	stopOnce     sync.Once
 }
@@ -136,6 +140,7 @@ func (m *manager) RegisterRequest(
 	timeoutHandler func(),
 ) {
 	newTimeoutHandler := func() {
+		m.reputation.RegisterFailure(nodeID)
 		if requestID.Op != byte(message.AppResponseOp) {
 			// If the request timed out and wasn't an AppRequest, tell the
 			// benchlist manager.
@@ -156,6 +161,7 @@ func (m *manager) RegisterResponse(
 	latency time.Duration,
 ) {
 	m.metrics.Observe(chainID, op, latency)
+	m.reputation.RegisterResponse(nodeID, latency)
 	m.benchlistMgr.RegisterResponse(chainID, nodeID)
 	m.tm.Remove(requestID)
 }
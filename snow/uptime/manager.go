@@ -26,6 +26,13 @@ type Tracker interface {
 	Connect(nodeID ids.NodeID, subnetID ids.ID) error
 	IsConnected(nodeID ids.NodeID, subnetID ids.ID) bool
 	Disconnect(nodeID ids.NodeID) error
+
+	// LastConnectionTimes returns the last time [nodeID] connected to
+	// [subnetID] and the last time it disconnected from [subnetID]. If an
+	// event wasn't observed, the zero time is returned for it.
+	//
+	// Disconnections are only recorded for validators.
+	LastConnectionTimes(nodeID ids.NodeID, subnetID ids.ID) (connected time.Time, disconnected time.Time)
 }
 
 type Calculator interface {
@@ -40,15 +47,22 @@ type manager struct {
 	clock *mockable.Clock
 
 	state          State
-	connections    map[ids.NodeID]map[ids.ID]time.Time // nodeID -> subnetID -> time
+	connections    map[ids.NodeID]map[ids.ID]time.Time     // nodeID -> subnetID -> time
+	disconnections map[ids.NodeID]map[ids.ID]disconnection // nodeID -> subnetID -> disconnection
 	trackedSubnets set.Set[ids.ID]
 }
 
+type disconnection struct {
+	connected    time.Time
+	disconnected time.Time
+}
+
 func NewManager(state State, clk *mockable.Clock) Manager {
 	return &manager{
-		clock:       clk,
-		state:       state,
-		connections: make(map[ids.NodeID]map[ids.ID]time.Time),
+		clock:          clk,
+		state:          state,
+		connections:    make(map[ids.NodeID]map[ids.ID]time.Time),
+		disconnections: make(map[ids.NodeID]map[ids.ID]disconnection),
 	}
 }
 
@@ -79,6 +93,10 @@ func (m *manager) StartTracking(nodeIDs []ids.NodeID, subnetID ids.ID) error {
 func (m *manager) StopTracking(nodeIDs []ids.NodeID, subnetID ids.ID) error {
 	now := m.clock.UnixTime()
 	for _, nodeID := range nodeIDs {
+		// The node is no longer tracked as a validator, so its last
+		// disconnection doesn't need to be remembered.
+		m.removeDisconnection(nodeID, subnetID)
+
 		connectedSubnets := m.connections[nodeID]
 		// If the node is already connected to this subnet, then we can just
 		// update the uptime in the state and remove the connection
@@ -128,16 +146,49 @@ func (m *manager) IsConnected(nodeID ids.NodeID, subnetID ids.ID) bool {
 }
 
 func (m *manager) Disconnect(nodeID ids.NodeID) error {
+	// Forget the disconnections from subnets that this node no longer
+	// validates.
+	for subnetID := range m.disconnections[nodeID] {
+		if _, err := m.state.GetStartTime(nodeID, subnetID); err == database.ErrNotFound {
+			m.removeDisconnection(nodeID, subnetID)
+		}
+	}
+
+	now := m.clock.UnixTime()
 	// Update every subnet that this node was connected to
-	for subnetID := range m.connections[nodeID] {
+	for subnetID, timeConnected := range m.connections[nodeID] {
 		if err := m.updateSubnetUptime(nodeID, subnetID); err != nil {
 			return err
 		}
+
+		// Only record the disconnections of validators to avoid tracking
+		// every peer that was ever connected.
+		if _, err := m.state.GetStartTime(nodeID, subnetID); err != nil {
+			continue
+		}
+
+		subnetDisconnections, ok := m.disconnections[nodeID]
+		if !ok {
+			subnetDisconnections = make(map[ids.ID]disconnection)
+			m.disconnections[nodeID] = subnetDisconnections
+		}
+		subnetDisconnections[subnetID] = disconnection{
+			connected:    timeConnected,
+			disconnected: now,
+		}
 	}
 	delete(m.connections, nodeID)
 	return nil
 }
 
+func (m *manager) LastConnectionTimes(nodeID ids.NodeID, subnetID ids.ID) (time.Time, time.Time) {
+	lastDisconnection := m.disconnections[nodeID][subnetID]
+	if timeConnected, isConnected := m.connections[nodeID][subnetID]; isConnected {
+		return timeConnected, lastDisconnection.disconnected
+	}
+	return lastDisconnection.connected, lastDisconnection.disconnected
+}
+
 func (m *manager) CalculateUptime(nodeID ids.NodeID, subnetID ids.ID) (time.Duration, time.Time, error) {
 	upDuration, lastUpdated, err := m.state.GetUptime(nodeID, subnetID)
 	if err != nil {
@@ -202,6 +253,19 @@ func (m *manager) CalculateUptimePercentFrom(nodeID ids.NodeID, subnetID ids.ID,
 	return uptime, nil
 }
 
+// removeDisconnection forgets the last disconnection of [nodeID] from
+// [subnetID].
+func (m *manager) removeDisconnection(nodeID ids.NodeID, subnetID ids.ID) {
+	subnetDisconnections, ok := m.disconnections[nodeID]
+	if !ok {
+		return
+	}
+	delete(subnetDisconnections, subnetID)
+	if len(subnetDisconnections) == 0 {
+		delete(m.disconnections, nodeID)
+	}
+}
+
 // updateSubnetUptime updates the subnet uptime of the node on the state by the amount
 // of time that the node has been connected to the subnet.
 func (m *manager) updateSubnetUptime(nodeID ids.NodeID, subnetID ids.ID) error {
@@ -410,6 +410,127 @@ func TestUnrelatedNodeDisconnect(t *testing.T) {
 	require.Equal(clk.UnixTime(), lastUpdated)
 }
 
+func TestLastConnectionTimes(t *testing.T) {
+	require := require.New(t)
+
+	nodeID0 := ids.GenerateTestNodeID()
+	subnetID := ids.GenerateTestID()
+	nodeID1 := ids.GenerateTestNodeID()
+	currentTime := time.Now()
+	startTime := currentTime
+
+	s := NewTestState()
+	s.AddNode(nodeID0, subnetID, startTime)
+
+	clk := mockable.Clock{}
+	up := NewManager(s, &clk)
+	clk.Set(currentTime)
+
+	require.NoError(up.StartTracking([]ids.NodeID{nodeID0}, subnetID))
+
+	connected, disconnected := up.LastConnectionTimes(nodeID0, subnetID)
+	require.Zero(connected)
+	require.Zero(disconnected)
+
+	firstConnected := clk.UnixTime()
+	require.NoError(up.Connect(nodeID0, subnetID))
+	require.NoError(up.Connect(nodeID1, subnetID))
+
+	connected, disconnected = up.LastConnectionTimes(nodeID0, subnetID)
+	require.Equal(firstConnected, connected)
+	require.Zero(disconnected)
+
+	currentTime = currentTime.Add(time.Second)
+	clk.Set(currentTime)
+
+	firstDisconnected := clk.UnixTime()
+	require.NoError(up.Disconnect(nodeID0))
+	require.NoError(up.Disconnect(nodeID1))
+
+	connected, disconnected = up.LastConnectionTimes(nodeID0, subnetID)
+	require.Equal(firstConnected, connected)
+	require.Equal(firstDisconnected, disconnected)
+
+	// Disconnections of non-validators aren't recorded.
+	connected, disconnected = up.LastConnectionTimes(nodeID1, subnetID)
+	require.Zero(connected)
+	require.Zero(disconnected)
+
+	currentTime = currentTime.Add(time.Second)
+	clk.Set(currentTime)
+
+	secondConnected := clk.UnixTime()
+	require.NoError(up.Connect(nodeID0, subnetID))
+
+	connected, disconnected = up.LastConnectionTimes(nodeID0, subnetID)
+	require.Equal(secondConnected, connected)
+	require.Equal(firstDisconnected, disconnected)
+}
+
+func TestStopTrackingRemovesDisconnections(t *testing.T) {
+	require := require.New(t)
+
+	nodeID0 := ids.GenerateTestNodeID()
+	subnetID := ids.GenerateTestID()
+	currentTime := time.Now()
+	startTime := currentTime
+
+	s := NewTestState()
+	s.AddNode(nodeID0, subnetID, startTime)
+
+	clk := mockable.Clock{}
+	up := NewManager(s, &clk)
+	clk.Set(currentTime)
+
+	require.NoError(up.StartTracking([]ids.NodeID{nodeID0}, subnetID))
+	require.NoError(up.Connect(nodeID0, subnetID))
+	require.NoError(up.Disconnect(nodeID0))
+
+	_, disconnected := up.LastConnectionTimes(nodeID0, subnetID)
+	require.Equal(clk.UnixTime(), disconnected)
+
+	require.NoError(up.StopTracking([]ids.NodeID{nodeID0}, subnetID))
+
+	connected, disconnected := up.LastConnectionTimes(nodeID0, subnetID)
+	require.Zero(connected)
+	require.Zero(disconnected)
+	require.Empty(up.(*manager).disconnections)
+}
+
+func TestDisconnectRemovesNonValidatorDisconnections(t *testing.T) {
+	require := require.New(t)
+
+	nodeID0 := ids.GenerateTestNodeID()
+	subnetID := ids.GenerateTestID()
+	currentTime := time.Now()
+	startTime := currentTime
+
+	s := NewTestState()
+	s.AddNode(nodeID0, subnetID, startTime)
+
+	clk := mockable.Clock{}
+	up := NewManager(s, &clk)
+	clk.Set(currentTime)
+
+	require.NoError(up.StartTracking([]ids.NodeID{nodeID0}, subnetID))
+	require.NoError(up.Connect(nodeID0, subnetID))
+	require.NoError(up.Disconnect(nodeID0))
+
+	_, disconnected := up.LastConnectionTimes(nodeID0, subnetID)
+	require.Equal(clk.UnixTime(), disconnected)
+
+	// The node stops validating the subnet.
+	delete(s.nodes, nodeID0)
+
+	require.NoError(up.Connect(nodeID0, subnetID))
+	require.NoError(up.Disconnect(nodeID0))
+
+	connected, disconnected := up.LastConnectionTimes(nodeID0, subnetID)
+	require.Zero(connected)
+	require.Zero(disconnected)
+	require.Empty(up.(*manager).disconnections)
+}
+
 func TestCalculateUptimeWhenNeverTracked(t *testing.T) {
 	require := require.New(t)
 
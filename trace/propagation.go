@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	traceParentKey = "traceparent"
+	traceStateKey  = "tracestate"
+)
+
+var propagator = propagation.TraceContext{}
+
+// Inject returns the W3C traceparent and tracestate of the span in [ctx], so
+// that the trace can be continued by another node. Returns false if [ctx]
+// doesn't contain a valid span.
+func Inject(ctx context.Context) (string, string, bool) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return "", "", false
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier[traceParentKey], carrier[traceStateKey], true
+}
+
+// Extract returns [ctx] with the remote span described by the W3C
+// [traceParent] and [traceState] as the parent of the spans started from it.
+// If [traceParent] is invalid, [ctx] is returned.
+func Extract(ctx context.Context, traceParent string, traceState string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+
+	return propagator.Extract(ctx, propagation.MapCarrier{
+		traceParentKey: traceParent,
+		traceStateKey:  traceState,
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPropagation(t *testing.T) {
+	require := require.New(t)
+
+	_, _, ok := Inject(context.Background())
+	require.False(ok)
+
+	traceState, err := trace.ParseTraceState("vendor=value")
+	require.NoError(err)
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+		TraceState: traceState,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+	traceParent, traceStateStr, ok := Inject(ctx)
+	require.True(ok)
+	require.Equal("00-01000000000000000000000000000000-0200000000000000-01", traceParent)
+	require.Equal("vendor=value", traceStateStr)
+
+	remoteCtx := Extract(context.Background(), traceParent, traceStateStr)
+	remoteSpanContext := trace.SpanContextFromContext(remoteCtx)
+	require.True(remoteSpanContext.IsRemote())
+	require.True(spanContext.Equal(remoteSpanContext.WithRemote(false)))
+
+	// Invalid trace contexts are ignored.
+	require.False(trace.SpanContextFromContext(Extract(context.Background(), "invalid", "")).IsValid())
+	require.False(trace.SpanContextFromContext(Extract(context.Background(), "", "")).IsValid())
+}
@@ -25,13 +25,17 @@ var (
 			return NewNoCompressor(), nil
 		},
 		TypeZstd: NewZstdCompressor,
+		TypeZstdDictionary: func(maxSize int64) (Compressor, error) {
+			return NewZstdDictionaryCompressor(maxSize, testDictionary)
+		},
 	}
 
 	//go:embed zstd_zip_bomb.bin
 	zstdZipBomb []byte
 
 	zipBombs = map[Type][]byte{
-		TypeZstd: zstdZipBomb,
+		TypeZstd:           zstdZipBomb,
+		TypeZstdDictionary: newZstdDictionaryZipBomb(),
 	}
 )
 
@@ -153,6 +157,10 @@ func FuzzZstdCompressor(f *testing.F) {
 	fuzzHelper(f, TypeZstd)
 }
 
+func FuzzZstdDictionaryCompressor(f *testing.F) {
+	fuzzHelper(f, TypeZstdDictionary)
+}
+
 func fuzzHelper(f *testing.F, compressionType Type) {
 	var (
 		compressor Compressor
@@ -162,6 +170,9 @@ func fuzzHelper(f *testing.F, compressionType Type) {
 	case TypeZstd:
 		compressor, err = NewZstdCompressor(maxMessageSize)
 		require.NoError(f, err)
+	case TypeZstdDictionary:
+		compressor, err = NewZstdDictionaryCompressor(maxMessageSize, testDictionary)
+		require.NoError(f, err)
 	default:
 		require.FailNow(f, "Unknown compression type")
 	}
@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	// sampleLenSize is the size of the length prefix of each sample.
+	sampleLenSize = 4
+	// sampleQueueSize is the number of samples that may be waiting to be
+	// written before additional samples are dropped.
+	sampleQueueSize = 1024
+)
+
+var errSampleTooLarge = errors.New("sample too large")
+
+// SampleWriter records samples of uncompressed messages, to be used to train a
+// dictionary with TrainZstdDictionary.
+//
+// Samples are written by a background goroutine so that recording a sample
+// never blocks on I/O. If the writer falls behind, samples are dropped.
+//
+// Each sample is written as a 4 byte big-endian length followed by the sample.
+type SampleWriter struct {
+	lock      sync.Mutex
+	closed    bool
+	remaining int64
+
+	samples chan []byte
+	// closed once all the queued samples have been written
+	done chan struct{}
+	// err is the first error returned while writing samples. It must only be
+	// read after [done] is closed.
+	err error
+}
+
+// NewSampleWriter returns a SampleWriter that writes at most [maxBytes] bytes
+// to [writer]. Close must be called to flush the recorded samples to
+// [writer].
+func NewSampleWriter(writer io.Writer, maxBytes int64) *SampleWriter {
+	s := &SampleWriter{
+		remaining: maxBytes,
+		samples:   make(chan []byte, sampleQueueSize),
+		done:      make(chan struct{}),
+	}
+	go s.write(bufio.NewWriter(writer))
+	return s
+}
+
+// Write records [sample]. If recording [sample] would exceed the maximum
+// number of bytes or too many samples are waiting to be written, the sample is
+// dropped. Returns true if the sample was recorded.
+//
+// [sample] must not be modified after calling Write.
+func (s *SampleWriter) Write(sample []byte) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	size := int64(sampleLenSize + len(sample))
+	if s.closed || size > s.remaining {
+		return false
+	}
+
+	select {
+	case s.samples <- sample:
+		s.remaining -= size
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops recording samples and returns once all the recorded samples
+// have been written. Returns the first error that occurred while writing.
+func (s *SampleWriter) Close() error {
+	s.lock.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.samples)
+	}
+	s.lock.Unlock()
+
+	<-s.done
+	return s.err
+}
+
+func (s *SampleWriter) write(writer *bufio.Writer) {
+	defer close(s.done)
+
+	var lenBytes [sampleLenSize]byte
+	for sample := range s.samples {
+		if s.err != nil {
+			// Drain the queue so that Write never blocks.
+			continue
+		}
+
+		binary.BigEndian.PutUint32(lenBytes[:], uint32(len(sample)))
+		if _, s.err = writer.Write(lenBytes[:]); s.err != nil {
+			continue
+		}
+		_, s.err = writer.Write(sample)
+	}
+	if s.err == nil {
+		s.err = writer.Flush()
+	}
+}
+
+// ReadSamples reads the samples written by a SampleWriter from [reader].
+// Samples larger than [maxSampleSize] are considered invalid.
+func ReadSamples(reader io.Reader, maxSampleSize uint32) ([][]byte, error) {
+	var (
+		bufferedReader = bufio.NewReader(reader)
+		lenBytes       [sampleLenSize]byte
+		samples        [][]byte
+	)
+	for {
+		_, err := io.ReadFull(bufferedReader, lenBytes[:])
+		if err == io.EOF {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		sampleLen := binary.BigEndian.Uint32(lenBytes[:])
+		if sampleLen > maxSampleSize {
+			return nil, fmt.Errorf("%w: (%d) > (%d)", errSampleTooLarge, sampleLen, maxSampleSize)
+		}
+		sample := make([]byte, sampleLen)
+		if _, err := io.ReadFull(bufferedReader, sample); err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+}
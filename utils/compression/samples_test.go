@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleWriter(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		buffer  bytes.Buffer
+		writer  = NewSampleWriter(&buffer, 2*sampleLenSize+5)
+		samples = [][]byte{
+			{1, 2, 3},
+			{4, 5},
+		}
+	)
+	for _, sample := range samples {
+		require.True(writer.Write(sample))
+	}
+
+	// The writer is full, so additional samples should be dropped.
+	require.False(writer.Write([]byte{6}))
+	require.NoError(writer.Close())
+
+	// Samples can't be recorded after the writer is closed.
+	require.False(writer.Write([]byte{}))
+	require.NoError(writer.Close())
+
+	readSamples, err := ReadSamples(&buffer, 3)
+	require.NoError(err)
+	require.Equal(samples, readSamples)
+}
+
+func TestReadSamplesTooLarge(t *testing.T) {
+	require := require.New(t)
+
+	var buffer bytes.Buffer
+	writer := NewSampleWriter(&buffer, 1024)
+	require.True(writer.Write([]byte{1, 2, 3}))
+	require.NoError(writer.Close())
+
+	_, err := ReadSamples(&buffer, 2)
+	require.ErrorIs(err, errSampleTooLarge)
+}
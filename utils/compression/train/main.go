@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/ava-labs/avalanchego/utils/compression"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+// This trains a zstd dictionary from the samples recorded by a node running
+// with --network-compression-samples-file.
+//
+// The resulting dictionary can be loaded by placing it in the directory
+// specified by --network-compression-dictionary-dir. Dictionaries should be
+// given increasing IDs, as nodes prefer the highest ID supported by a peer.
+func main() {
+	var (
+		samplesPath = flag.String("samples", "", "file containing the recorded samples")
+		outputPath  = flag.String("output", "", "file to write the dictionary to")
+		id          = flag.Uint("id", 0, "ID of the dictionary, must be > 0")
+		maxSize     = flag.Int("max-size", 64*units.KiB, "maximum size of the dictionary in bytes")
+	)
+	flag.Parse()
+
+	if *samplesPath == "" || *outputPath == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	samplesFile, err := os.Open(*samplesPath)
+	if err != nil {
+		log.Fatalf("failed to open samples file: %v", err)
+	}
+	defer samplesFile.Close()
+
+	samples, err := compression.ReadSamples(samplesFile, constants.DefaultMaxMessageSize)
+	if err != nil {
+		log.Fatalf("failed to read samples: %v", err)
+	}
+
+	dictionary, err := compression.TrainZstdDictionary(uint32(*id), samples, *maxSize)
+	if err != nil {
+		log.Fatalf("failed to train dictionary: %v", err)
+	}
+
+	if err := perms.WriteFile(*outputPath, dictionary.Bytes, perms.ReadOnly); err != nil {
+		log.Fatalf("failed to write dictionary: %v", err)
+	}
+	log.Printf("trained dictionary %d of %d bytes from %d samples", dictionary.ID, len(dictionary.Bytes), len(samples))
+}
@@ -15,6 +15,7 @@ type Type byte
 const (
 	TypeNone Type = iota + 1
 	TypeZstd
+	TypeZstdDictionary
 )
 
 func (t Type) String() string {
@@ -23,6 +24,8 @@ func (t Type) String() string {
 		return "none"
 	case TypeZstd:
 		return "zstd"
+	case TypeZstdDictionary:
+		return "zstd-dictionary"
 	default:
 		return "unknown"
 	}
@@ -34,6 +37,8 @@ func TypeFromString(s string) (Type, error) {
 		return TypeNone, nil
 	case TypeZstd.String():
 		return TypeZstd, nil
+	case TypeZstdDictionary.String():
+		return TypeZstdDictionary, nil
 	default:
 		return TypeNone, errUnknownCompressionType
 	}
@@ -0,0 +1,193 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/DataDog/zstd"
+
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+const (
+	zstdFrameMagic      = 0xFD2FB528
+	zstdDictionaryMagic = 0xEC30A437
+
+	// zstdDictionaryHeaderLen is the length of the magic number and the
+	// dictionary ID at the start of a zstd dictionary.
+	zstdDictionaryHeaderLen = 8
+)
+
+var (
+	_ Compressor = (*zstdDictionaryCompressor)(nil)
+
+	ErrInvalidDictionary  = errors.New("invalid zstd dictionary")
+	ErrInvalidFrame       = errors.New("invalid zstd frame")
+	ErrDictionaryMismatch = errors.New("frame was compressed with a different dictionary")
+
+	errZeroDictionaryID = errors.New("dictionary ID must be > 0")
+)
+
+// Dictionary is a trained zstd dictionary.
+//
+// The ID is the version of the dictionary. It is stored in the header of the
+// dictionary and in the header of every frame compressed with it, which allows
+// the receiver of a frame to determine which dictionary to decompress it with.
+//
+// Because independently trained dictionaries may share an ID, peers negotiate
+// dictionaries by their Hash rather than by their ID.
+type Dictionary struct {
+	ID    uint32
+	Hash  [hashing.HashLen]byte
+	Bytes []byte
+}
+
+// ParseDictionary parses a zstd dictionary, as produced by
+// TrainZstdDictionary.
+func ParseDictionary(b []byte) (*Dictionary, error) {
+	if len(b) < zstdDictionaryHeaderLen {
+		return nil, fmt.Errorf("%w: length (%d) < (%d)", ErrInvalidDictionary, len(b), zstdDictionaryHeaderLen)
+	}
+	if magic := binary.LittleEndian.Uint32(b); magic != zstdDictionaryMagic {
+		return nil, fmt.Errorf("%w: unexpected magic number 0x%x", ErrInvalidDictionary, magic)
+	}
+	id := binary.LittleEndian.Uint32(b[4:])
+	if id == 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDictionary, errZeroDictionaryID)
+	}
+	return &Dictionary{
+		ID:    id,
+		Hash:  hashing.ComputeHash256Array(b),
+		Bytes: b,
+	}, nil
+}
+
+// FrameDictionaryID returns the ID of the dictionary that [frame] was
+// compressed with. If the frame was compressed without a dictionary, 0 is
+// returned.
+func FrameDictionaryID(frame []byte) (uint32, error) {
+	// Magic_Number (4 bytes) + Frame_Header_Descriptor (1 byte)
+	if len(frame) < 5 {
+		return 0, fmt.Errorf("%w: length (%d) < (5)", ErrInvalidFrame, len(frame))
+	}
+	if magic := binary.LittleEndian.Uint32(frame); magic != zstdFrameMagic {
+		return 0, fmt.Errorf("%w: unexpected magic number 0x%x", ErrInvalidFrame, magic)
+	}
+
+	var (
+		descriptor    = frame[4]
+		singleSegment = descriptor&0x20 != 0
+		idFlag        = descriptor & 0x03
+		offset        = 5
+	)
+	if !singleSegment {
+		// Skip the Window_Descriptor
+		offset++
+	}
+
+	var idLen int
+	switch idFlag {
+	case 0:
+		return 0, nil
+	case 1:
+		idLen = 1
+	case 2:
+		idLen = 2
+	default:
+		idLen = 4
+	}
+	if len(frame) < offset+idLen {
+		return 0, fmt.Errorf("%w: truncated header", ErrInvalidFrame)
+	}
+
+	var id uint32
+	for i := idLen - 1; i >= 0; i-- {
+		id = id<<8 | uint32(frame[offset+i])
+	}
+	return id, nil
+}
+
+// SelectDictionary returns the highest ID of the dictionaries in [local] whose
+// hash is included in [remoteHashes]. If there is no such dictionary, 0 is
+// returned.
+func SelectDictionary(local []*Dictionary, remoteHashes [][]byte) uint32 {
+	var selected uint32
+	for _, dictionary := range local {
+		if dictionary.ID <= selected {
+			continue
+		}
+		for _, remoteHash := range remoteHashes {
+			if bytes.Equal(dictionary.Hash[:], remoteHash) {
+				selected = dictionary.ID
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// NewZstdDictionaryCompressor returns a zstd compressor that compresses and
+// decompresses messages with [dictionary].
+func NewZstdDictionaryCompressor(maxSize int64, dictionary *Dictionary) (Compressor, error) {
+	if maxSize == math.MaxInt64 {
+		// See NewZstdCompressor
+		return nil, ErrInvalidMaxSizeCompressor
+	}
+
+	// Digesting the dictionary is expensive, so it is only done once.
+	processor, err := zstd.NewBulkProcessor(dictionary.Bytes, zstd.DefaultCompression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDictionary, err)
+	}
+	return &zstdDictionaryCompressor{
+		maxSize:    maxSize,
+		dictionary: dictionary,
+		processor:  processor,
+	}, nil
+}
+
+type zstdDictionaryCompressor struct {
+	maxSize    int64
+	dictionary *Dictionary
+	processor  *zstd.BulkProcessor
+}
+
+func (z *zstdDictionaryCompressor) Compress(msg []byte) ([]byte, error) {
+	if int64(len(msg)) > z.maxSize {
+		return nil, fmt.Errorf("%w: (%d) > (%d)", ErrMsgTooLarge, len(msg), z.maxSize)
+	}
+	return z.processor.Compress(nil, msg)
+}
+
+func (z *zstdDictionaryCompressor) Decompress(msg []byte) ([]byte, error) {
+	id, err := FrameDictionaryID(msg)
+	if err != nil {
+		return nil, err
+	}
+	if id != z.dictionary.ID {
+		return nil, fmt.Errorf("%w: expected (%d) but got (%d)", ErrDictionaryMismatch, z.dictionary.ID, id)
+	}
+
+	// The bulk processor allocates the decompressed size claimed by the frame
+	// header, so a stream is used to protect against zip bombs.
+	reader := zstd.NewReaderDict(bytes.NewReader(msg), z.dictionary.Bytes)
+	defer reader.Close()
+
+	// See zstdCompressor.Decompress
+	limitReader := io.LimitReader(reader, z.maxSize+1)
+	decompressed, err := io.ReadAll(limitReader)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(decompressed)) > z.maxSize {
+		return nil, fmt.Errorf("%w: (%d) > (%d)", ErrDecompressedMsgTooLarge, len(decompressed), z.maxSize)
+	}
+	return decompressed, nil
+}
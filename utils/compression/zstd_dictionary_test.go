@@ -0,0 +1,247 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+var testDictionary = newTestDictionary(1)
+
+// newTestSamples returns samples with a repetitive structure, similar to
+// serialized consensus messages.
+func newTestSamples(num int) [][]byte {
+	samples := make([][]byte, num)
+	for i := range samples {
+		samples[i] = []byte(fmt.Sprintf(
+			`{"chainID":"2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM","requestID":%d,"deadline":%d,"containerID":"%x"}`,
+			i,
+			i%7*1000,
+			utils.RandomBytes(32),
+		))
+	}
+	return samples
+}
+
+func newTestDictionary(id uint32) *Dictionary {
+	dictionary, err := TrainZstdDictionary(id, newTestSamples(2048), 4*units.KiB)
+	if err != nil {
+		panic(err)
+	}
+	return dictionary
+}
+
+// newZstdDictionaryZipBomb returns a small message that decompresses to far
+// more than the max message size.
+func newZstdDictionaryZipBomb() []byte {
+	compressor, err := NewZstdDictionaryCompressor(64*maxMessageSize, testDictionary)
+	if err != nil {
+		panic(err)
+	}
+	zipBomb, err := compressor.Compress(make([]byte, 64*maxMessageSize))
+	if err != nil {
+		panic(err)
+	}
+	return zipBomb
+}
+
+func TestParseDictionary(t *testing.T) {
+	tests := []struct {
+		name        string
+		bytes       []byte
+		expectedErr error
+	}{
+		{
+			name:        "trained",
+			bytes:       testDictionary.Bytes,
+			expectedErr: nil,
+		},
+		{
+			name:        "too short",
+			bytes:       testDictionary.Bytes[:zstdDictionaryHeaderLen-1],
+			expectedErr: ErrInvalidDictionary,
+		},
+		{
+			name:        "wrong magic",
+			bytes:       append([]byte{0, 0, 0, 0}, testDictionary.Bytes[4:]...),
+			expectedErr: ErrInvalidDictionary,
+		},
+		{
+			name:        "zero ID",
+			bytes:       append(append([]byte{}, testDictionary.Bytes[:4]...), 0, 0, 0, 0),
+			expectedErr: errZeroDictionaryID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			dictionary, err := ParseDictionary(test.bytes)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr == nil {
+				require.Equal(testDictionary, dictionary)
+			}
+		})
+	}
+}
+
+func TestTrainZstdDictionary(t *testing.T) {
+	require := require.New(t)
+
+	dictionary, err := TrainZstdDictionary(5, newTestSamples(2048), 4*units.KiB)
+	require.NoError(err)
+	require.Equal(uint32(5), dictionary.ID)
+	require.LessOrEqual(len(dictionary.Bytes), 4*units.KiB)
+
+	_, err = TrainZstdDictionary(0, newTestSamples(2048), 4*units.KiB)
+	require.ErrorIs(err, errZeroDictionaryID)
+
+	_, err = TrainZstdDictionary(1, newTestSamples(minSamples-1), 4*units.KiB)
+	require.ErrorIs(err, errInsufficientSamples)
+
+	_, err = TrainZstdDictionary(1, newTestSamples(2048), minDictionarySize-1)
+	require.ErrorIs(err, errDictionaryTooSmall)
+}
+
+func TestZstdDictionaryCompressionRatio(t *testing.T) {
+	require := require.New(t)
+
+	zstdCompressor, err := NewZstdCompressor(maxMessageSize)
+	require.NoError(err)
+	dictionaryCompressor, err := NewZstdDictionaryCompressor(maxMessageSize, testDictionary)
+	require.NoError(err)
+
+	var zstdSize, dictionarySize int
+	for _, sample := range newTestSamples(128) {
+		compressed, err := zstdCompressor.Compress(sample)
+		require.NoError(err)
+		zstdSize += len(compressed)
+
+		compressed, err = dictionaryCompressor.Compress(sample)
+		require.NoError(err)
+		dictionarySize += len(compressed)
+	}
+	require.Less(dictionarySize, zstdSize)
+}
+
+func TestFrameDictionaryID(t *testing.T) {
+	require := require.New(t)
+
+	msg := newTestSamples(1)[0]
+
+	zstdCompressor, err := NewZstdCompressor(maxMessageSize)
+	require.NoError(err)
+	compressed, err := zstdCompressor.Compress(msg)
+	require.NoError(err)
+	id, err := FrameDictionaryID(compressed)
+	require.NoError(err)
+	require.Zero(id)
+
+	// Frames encode the dictionary ID in 1, 2, or 4 bytes.
+	for _, dictionaryID := range []uint32{1, 1 << 8, 1 << 16, 1 << 31} {
+		dictionaryBytes := slices.Clone(testDictionary.Bytes)
+		binary.LittleEndian.PutUint32(dictionaryBytes[4:], dictionaryID)
+		dictionary, err := ParseDictionary(dictionaryBytes)
+		require.NoError(err)
+
+		dictionaryCompressor, err := NewZstdDictionaryCompressor(maxMessageSize, dictionary)
+		require.NoError(err)
+		compressed, err = dictionaryCompressor.Compress(msg)
+		require.NoError(err)
+
+		id, err = FrameDictionaryID(compressed)
+		require.NoError(err)
+		require.Equal(dictionaryID, id)
+
+		decompressed, err := dictionaryCompressor.Decompress(compressed)
+		require.NoError(err)
+		require.Equal(msg, decompressed)
+	}
+
+	_, err = FrameDictionaryID([]byte{1, 2, 3})
+	require.ErrorIs(err, ErrInvalidFrame)
+	_, err = FrameDictionaryID(bytes.Repeat([]byte{1}, 16))
+	require.ErrorIs(err, ErrInvalidFrame)
+}
+
+func TestZstdDictionaryMismatch(t *testing.T) {
+	require := require.New(t)
+
+	compressor1, err := NewZstdDictionaryCompressor(maxMessageSize, testDictionary)
+	require.NoError(err)
+	compressor2, err := NewZstdDictionaryCompressor(maxMessageSize, newTestDictionary(2))
+	require.NoError(err)
+
+	compressed, err := compressor1.Compress(newTestSamples(1)[0])
+	require.NoError(err)
+
+	_, err = compressor2.Decompress(compressed)
+	require.ErrorIs(err, ErrDictionaryMismatch)
+}
+
+func TestSelectDictionary(t *testing.T) {
+	var (
+		dictionary1 = newTestDictionary(1)
+		dictionary2 = newTestDictionary(2)
+		dictionary3 = newTestDictionary(3)
+		// otherDictionary2 has the same ID as dictionary2 but different
+		// contents.
+		otherDictionary2 = newTestDictionary(2)
+	)
+	tests := []struct {
+		name     string
+		local    []*Dictionary
+		remote   []*Dictionary
+		expected uint32
+	}{
+		{
+			name:     "no local",
+			local:    nil,
+			remote:   []*Dictionary{dictionary1, dictionary2},
+			expected: 0,
+		},
+		{
+			name:     "no remote",
+			local:    []*Dictionary{dictionary1, dictionary2},
+			remote:   nil,
+			expected: 0,
+		},
+		{
+			name:     "disjoint",
+			local:    []*Dictionary{dictionary1, dictionary3},
+			remote:   []*Dictionary{dictionary2},
+			expected: 0,
+		},
+		{
+			name:     "highest common",
+			local:    []*Dictionary{dictionary3, dictionary1, dictionary2},
+			remote:   []*Dictionary{dictionary2, dictionary1},
+			expected: 2,
+		},
+		{
+			name:     "same ID with different contents",
+			local:    []*Dictionary{dictionary1, dictionary2},
+			remote:   []*Dictionary{dictionary1, otherDictionary2},
+			expected: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			remoteHashes := make([][]byte, len(test.remote))
+			for i, dictionary := range test.remote {
+				remoteHashes[i] = dictionary.Hash[:]
+			}
+			require.Equal(t, test.expected, SelectDictionary(test.local, remoteHashes))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+// The zdict sources are compiled as part of github.com/DataDog/zstd, which
+// doesn't expose a Go wrapper for them. The functions are declared here so that
+// they are resolved against that package when linking.
+
+/*
+#include <stddef.h>
+
+size_t ZDICT_trainFromBuffer(
+	void* dictBuffer,
+	size_t dictBufferCapacity,
+	const void* samplesBuffer,
+	const size_t* samplesSizes,
+	unsigned nbSamples
+);
+unsigned ZDICT_isError(size_t errorCode);
+const char* ZDICT_getErrorName(size_t errorCode);
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	_ "github.com/DataDog/zstd" // Provides the zdict implementation
+)
+
+const (
+	minDictionarySize = 256
+	minSamples        = 8
+)
+
+var (
+	ErrTrainingFailed = errors.New("failed to train zstd dictionary")
+
+	errNoSamples           = errors.New("no samples provided")
+	errDictionaryTooSmall  = errors.New("dictionary size is too small")
+	errInsufficientSamples = errors.New("insufficient samples")
+)
+
+// TrainZstdDictionary trains a zstd dictionary of at most [maxSize] bytes from
+// [samples]. The returned dictionary is identified by [id].
+//
+// Training works best when the samples are representative of the messages
+// that will be compressed and the total size of the samples is roughly 100x
+// the size of the dictionary.
+func TrainZstdDictionary(id uint32, samples [][]byte, maxSize int) (*Dictionary, error) {
+	switch {
+	case id == 0:
+		return nil, errZeroDictionaryID
+	case maxSize < minDictionarySize:
+		return nil, fmt.Errorf("%w: (%d) < (%d)", errDictionaryTooSmall, maxSize, minDictionarySize)
+	case len(samples) == 0:
+		return nil, errNoSamples
+	case len(samples) < minSamples:
+		return nil, fmt.Errorf("%w: (%d) < (%d)", errInsufficientSamples, len(samples), minSamples)
+	}
+
+	var (
+		totalSize int
+		sizes     = make([]C.size_t, len(samples))
+	)
+	for i, sample := range samples {
+		totalSize += len(sample)
+		sizes[i] = C.size_t(len(sample))
+	}
+	if totalSize == 0 {
+		return nil, errNoSamples
+	}
+
+	// The samples must be provided to zdict as a single contiguous buffer.
+	buffer := make([]byte, 0, totalSize)
+	for _, sample := range samples {
+		buffer = append(buffer, sample...)
+	}
+
+	dictionary := make([]byte, maxSize)
+	result := C.ZDICT_trainFromBuffer(
+		unsafe.Pointer(&dictionary[0]),
+		C.size_t(len(dictionary)),
+		unsafe.Pointer(&buffer[0]),
+		&sizes[0],
+		C.unsigned(len(sizes)),
+	)
+	if C.ZDICT_isError(result) != 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTrainingFailed, C.GoString(C.ZDICT_getErrorName(result)))
+	}
+	dictionary = dictionary[:result]
+
+	// zdict derives the dictionary ID from the dictionary content. The ID is
+	// only used to identify the dictionary, so it is safe to replace it with
+	// the requested version.
+	if len(dictionary) < zstdDictionaryHeaderLen {
+		return nil, fmt.Errorf("%w: dictionary is too short", ErrTrainingFailed)
+	}
+	binary.LittleEndian.PutUint32(dictionary[4:], id)
+	return ParseDictionary(dictionary)
+}
@@ -47,11 +47,24 @@ const (
 	DefaultNetworkReadHandshakeTimeout  = 15 * time.Second
 
 	DefaultNetworkCompressionType           = compression.TypeZstd
+	DefaultNetworkCompressionSamplesMaxSize = 64 * units.MiB
 	DefaultNetworkMaxClockDifference        = time.Minute
 	DefaultNetworkRequireValidatorToConnect = false
 	DefaultNetworkPeerReadBufferSize        = 8 * units.KiB
 	DefaultNetworkPeerWriteBufferSize       = 8 * units.KiB
 
+	// Outbound message queue priorities
+	DefaultNetworkOutboundQueueConsensusWeight        = 16
+	DefaultNetworkOutboundQueueConsensusMaxBytes      = 0
+	DefaultNetworkOutboundQueueBootstrapWeight        = 4
+	DefaultNetworkOutboundQueueBootstrapMaxBytes      = 0
+	DefaultNetworkOutboundQueueAppRequestWeight       = 4
+	DefaultNetworkOutboundQueueAppRequestMaxBytes     = 0
+	DefaultNetworkOutboundQueueAppGossipWeight        = 1
+	DefaultNetworkOutboundQueueAppGossipMaxBytes      = 8 * units.MiB
+	DefaultNetworkOutboundQueuePeerManagementWeight   = 2
+	DefaultNetworkOutboundQueuePeerManagementMaxBytes = 0
+
 	DefaultNetworkTCPProxyEnabled = false
 
 	// The PROXY protocol specification recommends setting this value to be at
@@ -66,6 +79,13 @@ const (
 	DefaultBenchlistDuration           = 15 * time.Minute
 	DefaultBenchlistMinFailingDuration = 2*time.Minute + 30*time.Second
 
+	// Peer Reputation
+	DefaultReputationHalflife                 = 5 * time.Minute
+	DefaultReputationMaxLatency               = 5 * time.Second
+	DefaultReputationInvalidMessagePenalty    = .1
+	DefaultReputationProtocolViolationPenalty = .5
+	DefaultReputationMinScore                 = .25
+
 	// Router
 	DefaultConsensusAppConcurrency  = 2
 	DefaultConsensusShutdownTimeout = time.Minute
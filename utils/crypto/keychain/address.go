@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keychain
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var (
+	_ Keychain = addressKeychain{}
+	_ Signer   = addressSigner{}
+
+	ErrAddressOnly = errors.New("address can't sign")
+)
+
+// addressKeychain returns a signer for every address without being able to
+// sign.
+type addressKeychain struct{}
+
+// NewAddressKeychain returns a keychain that returns a signer for every
+// address. The signers can't sign, which allows the signers required by a tx
+// to be determined without knowing their keys.
+func NewAddressKeychain() Keychain {
+	return addressKeychain{}
+}
+
+func (addressKeychain) Get(addr ids.ShortID) (Signer, bool) {
+	return addressSigner(addr), true
+}
+
+func (addressKeychain) Addresses() set.Set[ids.ShortID] {
+	return nil
+}
+
+type addressSigner ids.ShortID
+
+func (addressSigner) SignHash([]byte) ([]byte, error) {
+	return nil, ErrAddressOnly
+}
+
+func (addressSigner) Sign([]byte) ([]byte, error) {
+	return nil, ErrAddressOnly
+}
+
+func (s addressSigner) Address() ids.ShortID {
+	return ids.ShortID(s)
+}
@@ -421,3 +421,21 @@ func TestLedgerSignerFromIndices_SignHash(t *testing.T) {
 	require.NoError(err)
 	require.Equal(expectedSignature3, signature)
 }
+
+func TestAddressKeychain(t *testing.T) {
+	require := require.New(t)
+
+	kc := NewAddressKeychain()
+	require.Empty(kc.Addresses())
+
+	addr := ids.GenerateTestShortID()
+	s, ok := kc.Get(addr)
+	require.True(ok)
+	require.Equal(addr, s.Address())
+
+	_, err := s.Sign([]byte{1})
+	require.ErrorIs(err, ErrAddressOnly)
+
+	_, err = s.SignHash([]byte{1})
+	require.ErrorIs(err, ErrAddressOnly)
+}
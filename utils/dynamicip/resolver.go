@@ -22,6 +22,7 @@ const (
 	IFConfigName   = "ifconfig"
 	IFConfigCoName = "ifconfigco"
 	IFConfigMeName = "ifconfigme"
+	STUNName       = "stun"
 )
 
 var errUnknownResolver = errors.New("unknown resolver")
@@ -35,8 +36,11 @@ type Resolver interface {
 // Returns a new Resolver that uses the given service
 // to resolve our public IP.
 // [resolverName] must be one of:
-// [OpenDNSName], [IFConfigName], [IFConfigCoName], [IFConfigMeName].
-// If [resolverService] isn't one of the above, returns an error
+// [OpenDNSName], [IFConfigName], [IFConfigCoName], [IFConfigMeName],
+// [STUNName].
+// If [resolverService] isn't one of the above, returns an error.
+// [STUNName] queries the [DefaultSTUNServers]. Use NewSTUNResolver to query
+// other servers.
 func NewResolver(resolverName string) (Resolver, error) {
 	switch strings.ToLower(resolverName) {
 	case OpenDNSName:
@@ -45,6 +49,8 @@ func NewResolver(resolverName string) (Resolver, error) {
 		return &ifConfigResolver{url: ifConfigCoURL}, nil
 	case IFConfigMeName:
 		return &ifConfigResolver{url: ifConfigMeURL}, nil
+	case STUNName:
+		return NewSTUNResolver(DefaultSTUNServers, DefaultSTUNQuorum)
 	default:
 		return nil, fmt.Errorf("%w: %s", errUnknownResolver, resolverName)
 	}
@@ -0,0 +1,275 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+)
+
+// STUN is specified in RFC 5389: https://www.rfc-editor.org/rfc/rfc5389
+const (
+	stunHeaderLen      = 20
+	stunMagicCookie    = 0x2112A442
+	stunTxIDLen        = 12
+	stunMaxMsgLen      = 1500
+	stunBindingRequest = 0x0001
+	stunBindingSuccess = 0x0101
+
+	stunAttrMappedAddress    = 0x0001
+	stunAttrXORMappedAddress = 0x0020
+
+	stunFamilyIPv4 = 0x01
+	stunFamilyIPv6 = 0x02
+
+	// stunRetransmitTimeout is the initial timeout before a request is
+	// retransmitted. It doubles after every retransmission.
+	stunRetransmitTimeout = 500 * time.Millisecond
+	stunMaxAttempts       = 4
+
+	// DefaultSTUNQuorum is the default number of STUN servers that must
+	// report the same IP.
+	DefaultSTUNQuorum = 2
+)
+
+var (
+	_ Resolver = (*stunResolver)(nil)
+
+	// DefaultSTUNServers are the STUN servers queried by default.
+	DefaultSTUNServers = []string{
+		"stun.l.google.com:19302",
+		"stun1.l.google.com:19302",
+		"stun.cloudflare.com:3478",
+	}
+
+	errNoSTUNServers       = errors.New("no STUN servers")
+	errInvalidSTUNQuorum   = errors.New("invalid STUN quorum")
+	errSTUNNoQuorum        = errors.New("STUN servers didn't agree on an IP")
+	errInvalidSTUNResponse = errors.New("invalid STUN response")
+	errSTUNNoAddress       = errors.New("STUN response didn't include an address")
+)
+
+// stunResolver resolves our public IP by sending binding requests to STUN
+// servers. An IP is only returned once [quorum] servers report it.
+//
+// Servers are contacted over the address family of the server address, so
+// both IPv4 and IPv6 addresses can be resolved.
+type stunResolver struct {
+	servers []string
+	quorum  int
+	timeout time.Duration
+}
+
+// NewSTUNResolver returns a Resolver that queries [servers], given as
+// host:port, and requires [quorum] of them to report the same IP.
+func NewSTUNResolver(servers []string, quorum int) (Resolver, error) {
+	switch {
+	case len(servers) == 0:
+		return nil, errNoSTUNServers
+	case quorum < 1 || quorum > len(servers):
+		return nil, fmt.Errorf("%w: %d not in [1, %d]", errInvalidSTUNQuorum, quorum, len(servers))
+	}
+	return &stunResolver{
+		servers: servers,
+		quorum:  quorum,
+		timeout: stunRetransmitTimeout,
+	}, nil
+}
+
+type stunResult struct {
+	server string
+	addr   netip.Addr
+	err    error
+}
+
+func (r *stunResolver) Resolve(ctx context.Context) (netip.Addr, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan stunResult, len(r.servers))
+	for _, server := range r.servers {
+		go func(server string) {
+			addr, err := r.query(ctx, server)
+			results <- stunResult{
+				server: server,
+				addr:   addr,
+				err:    err,
+			}
+		}(server)
+	}
+
+	var (
+		counts = make(map[netip.Addr]int)
+		errs   []error
+	)
+	for range r.servers {
+		result := <-results
+		if result.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.server, result.err))
+			continue
+		}
+
+		counts[result.addr]++
+		if counts[result.addr] >= r.quorum {
+			return result.addr, nil
+		}
+	}
+	return netip.Addr{}, fmt.Errorf("%w: %v: %w", errSTUNNoQuorum, counts, errors.Join(errs...))
+}
+
+// query sends a binding request to [server] and returns the address it
+// reports.
+func (r *stunResolver) query(ctx context.Context, server string) (netip.Addr, error) {
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer conn.Close()
+
+	// Unblock reads once the context is cancelled.
+	go func() {
+		<-ctx.Done()
+		_ = conn.SetDeadline(time.Now())
+	}()
+
+	request := make([]byte, stunHeaderLen)
+	binary.BigEndian.PutUint16(request, stunBindingRequest)
+	binary.BigEndian.PutUint32(request[4:], stunMagicCookie)
+	txID := request[8:stunHeaderLen]
+	if _, err := rand.Read(txID); err != nil {
+		return netip.Addr{}, err
+	}
+
+	var (
+		timeout  = r.timeout
+		response = make([]byte, stunMaxMsgLen)
+	)
+	for attempt := 0; attempt < stunMaxAttempts; attempt++ {
+		if _, err := conn.Write(request); err != nil {
+			return netip.Addr{}, err
+		}
+		if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return netip.Addr{}, err
+		}
+		timeout *= 2
+
+		for {
+			n, err := conn.Read(response)
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return netip.Addr{}, ctxErr
+				}
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					break
+				}
+				return netip.Addr{}, err
+			}
+
+			addr, err := parseSTUNResponse(response[:n], txID)
+			if errors.Is(err, errInvalidSTUNResponse) {
+				// Ignore stray messages, such as responses to previous
+				// transmissions.
+				continue
+			}
+			return addr, err
+		}
+	}
+	return netip.Addr{}, fmt.Errorf("no response after %d attempts", stunMaxAttempts)
+}
+
+// parseSTUNResponse returns the address reported in the binding [response] to
+// the request with [txID].
+func parseSTUNResponse(response []byte, txID []byte) (netip.Addr, error) {
+	if len(response) < stunHeaderLen {
+		return netip.Addr{}, fmt.Errorf("%w: too short", errInvalidSTUNResponse)
+	}
+
+	var (
+		msgType   = binary.BigEndian.Uint16(response)
+		msgLen    = int(binary.BigEndian.Uint16(response[2:]))
+		cookie    = binary.BigEndian.Uint32(response[4:])
+		attrBytes = response[stunHeaderLen:]
+	)
+	switch {
+	case cookie != stunMagicCookie:
+		return netip.Addr{}, fmt.Errorf("%w: invalid magic cookie", errInvalidSTUNResponse)
+	case !bytes.Equal(response[8:stunHeaderLen], txID):
+		return netip.Addr{}, fmt.Errorf("%w: mismatched transaction ID", errInvalidSTUNResponse)
+	case msgType != stunBindingSuccess:
+		return netip.Addr{}, fmt.Errorf("%w: unexpected message type 0x%04x", errInvalidSTUNResponse, msgType)
+	case msgLen != len(attrBytes):
+		return netip.Addr{}, fmt.Errorf("%w: invalid length", errInvalidSTUNResponse)
+	}
+
+	var mappedAddr netip.Addr
+	for len(attrBytes) >= 4 {
+		attrType := binary.BigEndian.Uint16(attrBytes)
+		attrLen := int(binary.BigEndian.Uint16(attrBytes[2:]))
+		if len(attrBytes) < 4+attrLen {
+			return netip.Addr{}, fmt.Errorf("%w: truncated attribute", errInvalidSTUNResponse)
+		}
+		value := attrBytes[4 : 4+attrLen]
+
+		switch attrType {
+		case stunAttrXORMappedAddress:
+			// XOR-MAPPED-ADDRESS is preferred because some NATs rewrite
+			// addresses in the payload of the packets they translate.
+			return parseSTUNAddress(value, response[4:stunHeaderLen])
+		case stunAttrMappedAddress:
+			addr, err := parseSTUNAddress(value, nil)
+			if err != nil {
+				return netip.Addr{}, err
+			}
+			mappedAddr = addr
+		}
+
+		// Attributes are padded to a multiple of 4 bytes.
+		paddedLen := (attrLen + 3) &^ 3
+		attrBytes = attrBytes[min(len(attrBytes), 4+paddedLen):]
+	}
+	if !mappedAddr.IsValid() {
+		return netip.Addr{}, errSTUNNoAddress
+	}
+	return mappedAddr, nil
+}
+
+// parseSTUNAddress parses a (XOR-)MAPPED-ADDRESS attribute. If [xorKey] is
+// non-nil, the address is XORed with it.
+func parseSTUNAddress(value []byte, xorKey []byte) (netip.Addr, error) {
+	if len(value) < 4 {
+		return netip.Addr{}, fmt.Errorf("%w: address too short", errInvalidSTUNResponse)
+	}
+
+	var addrLen int
+	switch family := value[1]; family {
+	case stunFamilyIPv4:
+		addrLen = net.IPv4len
+	case stunFamilyIPv6:
+		addrLen = net.IPv6len
+	default:
+		return netip.Addr{}, fmt.Errorf("%w: unknown address family %d", errInvalidSTUNResponse, family)
+	}
+	if len(value) != 4+addrLen {
+		return netip.Addr{}, fmt.Errorf("%w: invalid address length", errInvalidSTUNResponse)
+	}
+
+	ip := make([]byte, addrLen)
+	copy(ip, value[4:])
+	if xorKey != nil {
+		for i := range ip {
+			ip[i] ^= xorKey[i]
+		}
+	}
+	addr, _ := netip.AddrFromSlice(ip)
+	return addr, nil
+}
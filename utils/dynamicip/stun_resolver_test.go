@@ -0,0 +1,302 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// stunResponder responds to binding requests with a fixed address.
+type stunResponder struct {
+	conn *net.UDPConn
+	// addr is reported to every requester
+	addr netip.Addr
+	// xor is true if addr is reported in a XOR-MAPPED-ADDRESS attribute
+	// rather than a MAPPED-ADDRESS attribute
+	xor bool
+	// drop is the number of requests to ignore before responding
+	drop int
+}
+
+func newSTUNResponder(t *testing.T, listenAddr string, addr netip.Addr, xor bool, drop int) *stunResponder {
+	conn, err := net.ListenUDP("udp", net.UDPAddrFromAddrPort(netip.MustParseAddrPort(listenAddr)))
+	if err != nil {
+		t.Skipf("failed to listen on %s: %s", listenAddr, err)
+	}
+
+	s := &stunResponder{
+		conn: conn,
+		addr: addr,
+		xor:  xor,
+		drop: drop,
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.serve()
+	}()
+	t.Cleanup(func() {
+		_ = conn.Close()
+		<-done
+	})
+	return s
+}
+
+func (s *stunResponder) address() string {
+	return s.conn.LocalAddr().String()
+}
+
+func (s *stunResponder) serve() {
+	request := make([]byte, stunMaxMsgLen)
+	for {
+		n, from, err := s.conn.ReadFromUDPAddrPort(request)
+		if err != nil {
+			return
+		}
+		if n != stunHeaderLen || binary.BigEndian.Uint16(request) != stunBindingRequest {
+			continue
+		}
+		if s.drop > 0 {
+			s.drop--
+			continue
+		}
+
+		var (
+			ip     = s.addr.AsSlice()
+			family = byte(stunFamilyIPv4)
+		)
+		if s.addr.Is6() {
+			family = stunFamilyIPv6
+		}
+		attrType := uint16(stunAttrMappedAddress)
+		if s.xor {
+			attrType = stunAttrXORMappedAddress
+			for i := range ip {
+				ip[i] ^= request[4+i]
+			}
+		}
+
+		attr := make([]byte, 8, 8+len(ip))
+		binary.BigEndian.PutUint16(attr, attrType)
+		binary.BigEndian.PutUint16(attr[2:], uint16(4+len(ip)))
+		attr[5] = family
+		binary.BigEndian.PutUint16(attr[6:], from.Port())
+		attr = append(attr, ip...)
+
+		response := make([]byte, stunHeaderLen, stunHeaderLen+len(attr))
+		binary.BigEndian.PutUint16(response, stunBindingSuccess)
+		binary.BigEndian.PutUint16(response[2:], uint16(len(attr)))
+		copy(response[4:], request[4:stunHeaderLen])
+		response = append(response, attr...)
+
+		_, _ = s.conn.WriteToUDPAddrPort(response, from)
+	}
+}
+
+func TestSTUNResolver(t *testing.T) {
+	var (
+		ipv4      = netip.MustParseAddr("203.0.113.1")
+		otherIPv4 = netip.MustParseAddr("203.0.113.2")
+		ipv6      = netip.MustParseAddr("2001:db8::1")
+	)
+
+	type responder struct {
+		listenAddr string
+		addr       netip.Addr
+		xor        bool
+		drop       int
+	}
+	tests := []struct {
+		name         string
+		responders   []responder
+		quorum       int
+		expectedAddr netip.Addr
+		expectedErr  error
+	}{
+		{
+			name: "xor mapped address",
+			responders: []responder{
+				{listenAddr: "127.0.0.1:0", addr: ipv4, xor: true},
+			},
+			quorum:       1,
+			expectedAddr: ipv4,
+		},
+		{
+			name: "mapped address",
+			responders: []responder{
+				{listenAddr: "127.0.0.1:0", addr: ipv4},
+			},
+			quorum:       1,
+			expectedAddr: ipv4,
+		},
+		{
+			name: "ipv6",
+			responders: []responder{
+				{listenAddr: "[::1]:0", addr: ipv6, xor: true},
+				{listenAddr: "[::1]:0", addr: ipv6},
+			},
+			quorum:       2,
+			expectedAddr: ipv6,
+		},
+		{
+			name: "retransmission",
+			responders: []responder{
+				{listenAddr: "127.0.0.1:0", addr: ipv4, xor: true, drop: 1},
+			},
+			quorum:       1,
+			expectedAddr: ipv4,
+		},
+		{
+			name: "quorum reached",
+			responders: []responder{
+				{listenAddr: "127.0.0.1:0", addr: ipv4, xor: true},
+				{listenAddr: "127.0.0.1:0", addr: otherIPv4, xor: true},
+				{listenAddr: "127.0.0.1:0", addr: ipv4, xor: true},
+			},
+			quorum:       2,
+			expectedAddr: ipv4,
+		},
+		{
+			name: "quorum not reached",
+			responders: []responder{
+				{listenAddr: "127.0.0.1:0", addr: ipv4, xor: true},
+				{listenAddr: "127.0.0.1:0", addr: otherIPv4, xor: true},
+			},
+			quorum:      2,
+			expectedErr: errSTUNNoQuorum,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			servers := make([]string, len(tt.responders))
+			for i, r := range tt.responders {
+				servers[i] = newSTUNResponder(t, r.listenAddr, r.addr, r.xor, r.drop).address()
+			}
+
+			resolver, err := NewSTUNResolver(servers, tt.quorum)
+			require.NoError(err)
+			resolver.(*stunResolver).timeout = 50 * time.Millisecond
+
+			addr, err := resolver.Resolve(context.Background())
+			require.ErrorIs(err, tt.expectedErr)
+			require.Equal(tt.expectedAddr, addr)
+		})
+	}
+}
+
+func TestSTUNResolverUnresponsive(t *testing.T) {
+	require := require.New(t)
+
+	// The server never responds.
+	responder := newSTUNResponder(t, "127.0.0.1:0", netip.Addr{}, false, stunMaxAttempts)
+	resolver, err := NewSTUNResolver([]string{responder.address()}, 1)
+	require.NoError(err)
+	resolver.(*stunResolver).timeout = time.Millisecond
+
+	_, err = resolver.Resolve(context.Background())
+	require.ErrorIs(err, errSTUNNoQuorum)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = resolver.Resolve(ctx)
+	require.ErrorIs(err, context.Canceled)
+}
+
+func TestNewSTUNResolverErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		servers []string
+		quorum  int
+		err     error
+	}{
+		{
+			name: "no servers",
+			err:  errNoSTUNServers,
+		},
+		{
+			name:    "quorum too small",
+			servers: DefaultSTUNServers,
+			quorum:  0,
+			err:     errInvalidSTUNQuorum,
+		},
+		{
+			name:    "quorum too large",
+			servers: DefaultSTUNServers,
+			quorum:  len(DefaultSTUNServers) + 1,
+			err:     errInvalidSTUNQuorum,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSTUNResolver(tt.servers, tt.quorum)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseSTUNResponseErrors(t *testing.T) {
+	txID := make([]byte, stunTxIDLen)
+	header := func(msgType uint16, length uint16, cookie uint32) []byte {
+		response := make([]byte, stunHeaderLen)
+		binary.BigEndian.PutUint16(response, msgType)
+		binary.BigEndian.PutUint16(response[2:], length)
+		binary.BigEndian.PutUint32(response[4:], cookie)
+		return response
+	}
+
+	tests := []struct {
+		name     string
+		response []byte
+		err      error
+	}{
+		{
+			name:     "too short",
+			response: make([]byte, stunHeaderLen-1),
+			err:      errInvalidSTUNResponse,
+		},
+		{
+			name:     "invalid magic cookie",
+			response: header(stunBindingSuccess, 0, 0),
+			err:      errInvalidSTUNResponse,
+		},
+		{
+			name:     "error response",
+			response: header(0x0111, 0, stunMagicCookie),
+			err:      errInvalidSTUNResponse,
+		},
+		{
+			name:     "invalid length",
+			response: header(stunBindingSuccess, 4, stunMagicCookie),
+			err:      errInvalidSTUNResponse,
+		},
+		{
+			name:     "no address",
+			response: header(stunBindingSuccess, 0, stunMagicCookie),
+			err:      errSTUNNoAddress,
+		},
+		{
+			name: "unknown address family",
+			response: append(
+				header(stunBindingSuccess, 12, stunMagicCookie),
+				0, stunAttrMappedAddress, 0, 8, 0, 3, 0, 0, 0, 0, 0, 0,
+			),
+			err: errInvalidSTUNResponse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSTUNResponse(tt.response, txID)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package iblt implements an invertible bloom lookup table over IDs.
+//
+// Two tables with the same size and salt can be subtracted from each other.
+// The result can be decoded into the IDs that are only in one of the tables,
+// as long as the number of differing IDs is small relative to the size of the
+// tables. This allows two parties to reconcile their sets by exchanging an
+// amount of data proportional to the size of their difference rather than to
+// the size of their sets.
+package iblt
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+const (
+	// NumHashes is the number of cells each ID is added to.
+	NumHashes = 3
+	// MinCells is the minimum number of cells in a table.
+	MinCells = NumHashes
+	// MaxCells is the maximum number of cells in a table.
+	MaxCells = 1 << 15
+
+	countLen    = 4
+	checksumLen = 8
+	cellLen     = countLen + ids.IDLen + checksumLen
+)
+
+var (
+	errTooFewCells       = errors.New("too few cells")
+	errTooManyCells      = errors.New("too many cells")
+	errInvalidNumCells   = fmt.Errorf("number of cells must be a multiple of %d", NumHashes)
+	errInvalidLength     = errors.New("invalid length")
+	errMismatchedSalt    = errors.New("mismatched salt")
+	errMismatchedNumCell = errors.New("mismatched number of cells")
+)
+
+type cell struct {
+	count    int32
+	idSum    ids.ID
+	checksum uint64
+}
+
+func (c *cell) update(id ids.ID, checksum uint64, delta int32) {
+	c.count += delta
+	for i := range c.idSum {
+		c.idSum[i] ^= id[i]
+	}
+	c.checksum ^= checksum
+}
+
+func (c *cell) isEmpty() bool {
+	return c.count == 0 && c.idSum == ids.Empty && c.checksum == 0
+}
+
+// Table is an invertible bloom lookup table.
+//
+// Table is not safe for concurrent use.
+type Table struct {
+	salt  ids.ID
+	cells []cell
+}
+
+// New returns an empty table with [numCells] cells. [salt] should be chosen
+// randomly to prevent IDs from being crafted to collide.
+//
+// [numCells] is rounded up to the nearest multiple of NumHashes.
+func New(numCells int, salt ids.ID) (*Table, error) {
+	numCells = (numCells + NumHashes - 1) / NumHashes * NumHashes
+	switch {
+	case numCells < MinCells:
+		return nil, fmt.Errorf("%w: %d < %d", errTooFewCells, numCells, MinCells)
+	case numCells > MaxCells:
+		return nil, fmt.Errorf("%w: %d > %d", errTooManyCells, numCells, MaxCells)
+	}
+	return &Table{
+		salt:  salt,
+		cells: make([]cell, numCells),
+	}, nil
+}
+
+// Parse [bytes] into a table.
+func Parse(bytes []byte) (*Table, error) {
+	if len(bytes) < ids.IDLen || (len(bytes)-ids.IDLen)%cellLen != 0 {
+		return nil, errInvalidLength
+	}
+
+	numCells := (len(bytes) - ids.IDLen) / cellLen
+	switch {
+	case numCells < MinCells:
+		return nil, fmt.Errorf("%w: %d < %d", errTooFewCells, numCells, MinCells)
+	case numCells > MaxCells:
+		return nil, fmt.Errorf("%w: %d > %d", errTooManyCells, numCells, MaxCells)
+	case numCells%NumHashes != 0:
+		return nil, fmt.Errorf("%w: %d", errInvalidNumCells, numCells)
+	}
+
+	t := &Table{
+		salt:  ids.ID(bytes[:ids.IDLen]),
+		cells: make([]cell, numCells),
+	}
+	bytes = bytes[ids.IDLen:]
+	for i := range t.cells {
+		c := &t.cells[i]
+		c.count = int32(binary.BigEndian.Uint32(bytes))
+		copy(c.idSum[:], bytes[countLen:])
+		c.checksum = binary.BigEndian.Uint64(bytes[countLen+ids.IDLen:])
+		bytes = bytes[cellLen:]
+	}
+	return t, nil
+}
+
+// Salt returns the salt used to hash IDs into the table.
+func (t *Table) Salt() ids.ID {
+	return t.salt
+}
+
+// Len returns the number of cells in the table.
+func (t *Table) Len() int {
+	return len(t.cells)
+}
+
+// Add [id] to the table.
+func (t *Table) Add(id ids.ID) {
+	t.update(id, 1)
+}
+
+// Remove [id] from the table.
+func (t *Table) Remove(id ids.ID) {
+	t.update(id, -1)
+}
+
+func (t *Table) update(id ids.ID, delta int32) {
+	checksum, indices := t.hash(id)
+	for _, index := range indices {
+		t.cells[index].update(id, checksum, delta)
+	}
+}
+
+// Subtract removes every ID in [other] from the table. Both tables must have
+// the same size and salt.
+func (t *Table) Subtract(other *Table) error {
+	switch {
+	case t.salt != other.salt:
+		return errMismatchedSalt
+	case len(t.cells) != len(other.cells):
+		return fmt.Errorf("%w: %d != %d", errMismatchedNumCell, len(t.cells), len(other.cells))
+	}
+
+	for i := range t.cells {
+		o := &other.cells[i]
+		t.cells[i].update(o.idSum, o.checksum, -o.count)
+	}
+	return nil
+}
+
+// Decode peels the IDs out of the table. IDs that were added more times than
+// they were removed are reported in [added] and IDs that were removed more
+// times than they were added are reported in [removed].
+//
+// If the table couldn't be fully decoded, [ok] is false and only a subset of
+// the IDs is reported. Tables that could not have been produced by adding and
+// removing IDs, such as tables received from a misbehaving peer, are reported
+// as not decodable.
+//
+// Decode empties the table of the reported IDs.
+func (t *Table) Decode() (added []ids.ID, removed []ids.ID, ok bool) {
+	if !t.hasConsistentCounts() {
+		return nil, nil, false
+	}
+
+	pure := make([]int, 0, len(t.cells))
+	for i := range t.cells {
+		if t.isPure(i) {
+			pure = append(pure, i)
+		}
+	}
+
+	// Every peeled ID must be distinct, so a table can't hold more IDs than
+	// it has cells. Bounding the number of peels guarantees termination even
+	// if the cells were crafted to be peeled forever.
+	peeled := set.NewSet[ids.ID](len(t.cells))
+	for len(pure) > 0 {
+		i := pure[len(pure)-1]
+		pure = pure[:len(pure)-1]
+
+		// Peeling another ID may have modified this cell since it was found
+		// to be pure.
+		if !t.isPure(i) {
+			continue
+		}
+
+		c := t.cells[i]
+		_, indices := t.hash(c.idSum)
+		if peeled.Contains(c.idSum) || !slices.Contains(indices[:], i) || peeled.Len() >= len(t.cells) {
+			return added, removed, false
+		}
+		peeled.Add(c.idSum)
+
+		if c.count > 0 {
+			added = append(added, c.idSum)
+		} else {
+			removed = append(removed, c.idSum)
+		}
+
+		for _, index := range indices {
+			t.cells[index].update(c.idSum, c.checksum, -c.count)
+			if t.isPure(index) {
+				pure = append(pure, index)
+			}
+		}
+	}
+
+	for i := range t.cells {
+		if !t.cells[i].isEmpty() {
+			return added, removed, false
+		}
+	}
+	return added, removed, true
+}
+
+// hasConsistentCounts returns false if the counts of the table could not have
+// been produced by adding and removing IDs. Every ID updates exactly one cell
+// in each partition, so the counts of each partition must sum to the same
+// value.
+func (t *Table) hasConsistentCounts() bool {
+	partitionSize := len(t.cells) / NumHashes
+	var expectedSum int64
+	for partition := 0; partition < NumHashes; partition++ {
+		var sum int64
+		for _, c := range t.cells[partition*partitionSize : (partition+1)*partitionSize] {
+			sum += int64(c.count)
+		}
+		if partition == 0 {
+			expectedSum = sum
+		} else if sum != expectedSum {
+			return false
+		}
+	}
+	return true
+}
+
+// isPure returns true if the cell at [index] contains exactly one ID.
+func (t *Table) isPure(index int) bool {
+	c := &t.cells[index]
+	if c.count != 1 && c.count != -1 {
+		return false
+	}
+	checksum, _ := t.hash(c.idSum)
+	return checksum == c.checksum
+}
+
+// Marshal returns the byte representation of the table.
+func (t *Table) Marshal() []byte {
+	bytes := make([]byte, ids.IDLen+len(t.cells)*cellLen)
+	copy(bytes, t.salt[:])
+	offset := ids.IDLen
+	for i := range t.cells {
+		c := &t.cells[i]
+		binary.BigEndian.PutUint32(bytes[offset:], uint32(c.count))
+		copy(bytes[offset+countLen:], c.idSum[:])
+		binary.BigEndian.PutUint64(bytes[offset+countLen+ids.IDLen:], c.checksum)
+		offset += cellLen
+	}
+	return bytes
+}
+
+// hash returns the checksum of [id] and the indices of the cells it is added
+// to. The table is split into NumHashes partitions and each index falls into a
+// different partition, so the indices are always distinct.
+func (t *Table) hash(id ids.ID) (uint64, [NumHashes]int) {
+	hash := sha256.New()
+	// sha256.Write never returns errors
+	_, _ = hash.Write(id[:])
+	_, _ = hash.Write(t.salt[:])
+
+	var digest [sha256.Size]byte
+	hash.Sum(digest[:0])
+
+	var (
+		checksum      = binary.BigEndian.Uint64(digest[:])
+		partitionSize = uint64(len(t.cells) / NumHashes)
+		indices       [NumHashes]int
+	)
+	for i := range indices {
+		offset := checksumLen * (i + 1)
+		partitionIndex := binary.BigEndian.Uint64(digest[offset:]) % partitionSize
+		indices[i] = i*int(partitionSize) + int(partitionIndex)
+	}
+	return checksum, indices
+}
@@ -0,0 +1,234 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iblt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		numCells int
+		err      error
+	}{
+		{
+			numCells: 0,
+			err:      errTooFewCells,
+		},
+		{
+			numCells: MaxCells + 1,
+			err:      errTooManyCells,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.err.Error(), func(t *testing.T) {
+			_, err := New(test.numCells, ids.Empty)
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		bytes []byte
+		err   error
+	}{
+		{
+			name:  "missing salt",
+			bytes: make([]byte, ids.IDLen-1),
+			err:   errInvalidLength,
+		},
+		{
+			name:  "partial cell",
+			bytes: make([]byte, ids.IDLen+MinCells*cellLen+1),
+			err:   errInvalidLength,
+		},
+		{
+			name:  "too few cells",
+			bytes: make([]byte, ids.IDLen+(MinCells-1)*cellLen),
+			err:   errTooFewCells,
+		},
+		{
+			name:  "too many cells",
+			bytes: make([]byte, ids.IDLen+(MaxCells+NumHashes)*cellLen),
+			err:   errTooManyCells,
+		},
+		{
+			name:  "invalid number of cells",
+			bytes: make([]byte, ids.IDLen+(MinCells+1)*cellLen),
+			err:   errInvalidNumCells,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.bytes)
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name       string
+		numCells   int
+		numShared  int
+		numAdded   int
+		numRemoved int
+		decoded    bool
+	}{
+		{
+			name:     "empty",
+			numCells: 30,
+			decoded:  true,
+		},
+		{
+			name:      "identical",
+			numCells:  30,
+			numShared: 1000,
+			decoded:   true,
+		},
+		{
+			name:       "small difference",
+			numCells:   600,
+			numShared:  1000,
+			numAdded:   10,
+			numRemoved: 10,
+			decoded:    true,
+		},
+		{
+			name:      "only added",
+			numCells:  600,
+			numShared: 1000,
+			numAdded:  20,
+			decoded:   true,
+		},
+		{
+			name:      "difference too large",
+			numCells:  30,
+			numShared: 1000,
+			numAdded:  1000,
+			decoded:   false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			salt := ids.GenerateTestID()
+			local, err := New(test.numCells, salt)
+			require.NoError(err)
+			remote, err := New(test.numCells, salt)
+			require.NoError(err)
+
+			for i := 0; i < test.numShared; i++ {
+				id := ids.GenerateTestID()
+				local.Add(id)
+				remote.Add(id)
+			}
+			added := make([]ids.ID, test.numAdded)
+			for i := range added {
+				added[i] = ids.GenerateTestID()
+				local.Add(added[i])
+			}
+			removed := make([]ids.ID, test.numRemoved)
+			for i := range removed {
+				removed[i] = ids.GenerateTestID()
+				remote.Add(removed[i])
+			}
+
+			// The table must survive being sent over the wire.
+			remote, err = Parse(remote.Marshal())
+			require.NoError(err)
+
+			require.NoError(local.Subtract(remote))
+			gotAdded, gotRemoved, decoded := local.Decode()
+			require.Equal(test.decoded, decoded)
+			require.Subset(added, gotAdded)
+			require.Subset(removed, gotRemoved)
+			if decoded {
+				require.ElementsMatch(added, gotAdded)
+				require.ElementsMatch(removed, gotRemoved)
+			}
+		})
+	}
+}
+
+// Tables received from peers may be crafted so that peeling an ID makes the
+// same ID pure again. Decode must terminate and report them as not decodable.
+func TestDecodeMalformed(t *testing.T) {
+	id := ids.GenerateTestID()
+	tests := []struct {
+		name     string
+		numCells int
+		populate func(t *Table)
+	}{
+		{
+			name:     "inconsistent counts",
+			numCells: MinCells,
+			populate: func(t *Table) {
+				checksum, _ := t.hash(id)
+				t.cells[0].update(id, checksum, 1)
+			},
+		},
+		{
+			name:     "pure cell not indexed by its ID",
+			numCells: 2 * MinCells,
+			populate: func(t *Table) {
+				checksum, indices := t.hash(id)
+				for _, index := range indices {
+					// Each partition has two cells, so update the one that
+					// [id] doesn't hash to.
+					t.cells[index^1].update(id, checksum, 1)
+				}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			table, err := New(test.numCells, ids.GenerateTestID())
+			require.NoError(err)
+			test.populate(table)
+
+			// The table must survive being sent over the wire.
+			table, err = Parse(table.Marshal())
+			require.NoError(err)
+
+			_, _, decoded := table.Decode()
+			require.False(decoded)
+		})
+	}
+}
+
+func TestSubtractErrors(t *testing.T) {
+	require := require.New(t)
+
+	table, err := New(MinCells, ids.Empty)
+	require.NoError(err)
+
+	differentSalt, err := New(MinCells, ids.GenerateTestID())
+	require.NoError(err)
+	require.ErrorIs(table.Subtract(differentSalt), errMismatchedSalt)
+
+	differentSize, err := New(2*MinCells, ids.Empty)
+	require.NoError(err)
+	require.ErrorIs(table.Subtract(differentSize), errMismatchedNumCell)
+}
+
+func BenchmarkAdd(b *testing.B) {
+	table, err := New(MaxCells, ids.GenerateTestID())
+	require.NoError(b, err)
+
+	id := ids.GenerateTestID()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		table.Add(id)
+	}
+}
@@ -12,12 +12,14 @@ import (
 	"github.com/ava-labs/avalanchego/utils/filesystem"
 )
 
-// Config that is used to describe the options of the continuous profiler.
+// Config that is used to describe the options of the continuous and triggered
+// profilers.
 type Config struct {
 	Dir         string        `json:"dir"`
 	Enabled     bool          `json:"enabled"`
 	Freq        time.Duration `json:"freq"`
 	MaxNumFiles int           `json:"maxNumFiles"`
+	Trigger     TriggerConfig `json:"trigger"`
 }
 
 // ContinuousProfiler periodically captures CPU, memory, and lock profiles
@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package profiler
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// Profile is a file written by one of the profilers.
+type Profile struct {
+	// Path of the file, relative to the profile directory.
+	Path    string
+	Size    uint64
+	ModTime time.Time
+}
+
+// List returns the profiles in [dir], including the profiles in its
+// subdirectories, sorted by path. If [dir] doesn't exist, no profiles are
+// returned.
+func List(dir string) ([]Profile, error) {
+	var profiles []Profile
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		switch {
+		case err != nil && path == dir && errors.Is(err, fs.ErrNotExist):
+			return fs.SkipAll
+		case err != nil || entry.IsDir():
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		profiles = append(profiles, Profile{
+			Path:    filepath.ToSlash(relPath),
+			Size:    uint64(info.Size()),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	return profiles, err
+}
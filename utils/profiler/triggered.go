@@ -0,0 +1,350 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package profiler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+const (
+	// Name of file that the goroutine dump of a triggered capture is written to
+	goroutineProfileFile = "goroutine.profile"
+	// Name of file that the execution trace of a triggered capture is written
+	// to
+	executionTraceFile = "trace.out"
+
+	// captureTimeFormat is used to name capture directories so that they sort
+	// chronologically. Nanoseconds are included so that captures made in the
+	// same second have different names.
+	captureTimeFormat = "20060102T150405.000000000Z"
+
+	CPUTrigger       = "cpu"
+	GoroutineTrigger = "goroutines"
+)
+
+var (
+	errNonPositiveCheckFreq      = errors.New("check frequency must be positive")
+	errNonPositiveCaptureLength  = errors.New("capture duration must be positive")
+	errNegativeCooldown          = errors.New("cooldown must be non-negative")
+	errNegativeThreshold         = errors.New("threshold must be non-negative")
+	errNoTriggers                = errors.New("no trigger thresholds are set")
+	errNonPositiveMaxCaptureSize = errors.New("maximum capture size must be positive")
+
+	// captureDirRegex matches the names of capture directories, so that other
+	// directories aren't pruned.
+	captureDirRegex = regexp.MustCompile(`^\d{8}T\d{6}\.\d{9}Z-[a-z]+$`)
+)
+
+// CPUTracker reports the CPU usage of this process. It is implemented by
+// [tracker.Tracker].
+type CPUTracker interface {
+	// TotalUsage returns the number of CPU cores currently in use.
+	TotalUsage() float64
+}
+
+// TriggerConfig describes when the triggered profiler captures profiles.
+type TriggerConfig struct {
+	Enabled bool `json:"enabled"`
+	// CheckFreq is how often the thresholds are checked.
+	CheckFreq time.Duration `json:"checkFreq"`
+	// CPUThreshold triggers a capture when the process CPU usage exceeds this
+	// percentage, where 100 is one fully used core. Zero disables the trigger.
+	CPUThreshold float64 `json:"cpuThreshold"`
+	// GoroutineThreshold triggers a capture when the number of goroutines
+	// exceeds this value. Zero disables the trigger.
+	GoroutineThreshold int `json:"goroutineThreshold"`
+	// Duration of the CPU profile and execution trace of a capture.
+	Duration time.Duration `json:"duration"`
+	// Cooldown is the minimum time between the end of a capture and the start
+	// of the next one.
+	Cooldown time.Duration `json:"cooldown"`
+	// MaxSize is the number of bytes the retained captures may use. When it is
+	// exceeded, the oldest captures are removed.
+	MaxSize uint64 `json:"maxSize"`
+}
+
+func (c *TriggerConfig) Verify() error {
+	switch {
+	case !c.Enabled:
+		return nil
+	case c.CheckFreq <= 0:
+		return errNonPositiveCheckFreq
+	case c.Duration <= 0:
+		return errNonPositiveCaptureLength
+	case c.Cooldown < 0:
+		return errNegativeCooldown
+	case c.CPUThreshold < 0 || c.GoroutineThreshold < 0:
+		return errNegativeThreshold
+	case c.CPUThreshold == 0 && c.GoroutineThreshold == 0:
+		return errNoTriggers
+	case c.MaxSize == 0:
+		return errNonPositiveMaxCaptureSize
+	default:
+		return nil
+	}
+}
+
+// TriggeredProfiler captures a CPU profile, a goroutine dump and an execution
+// trace when the resource usage of this process exceeds a threshold.
+type TriggeredProfiler interface {
+	Dispatch() error
+	Shutdown()
+}
+
+type triggeredProfiler struct {
+	log        logging.Logger
+	dir        string
+	cpuTracker CPUTracker
+	config     TriggerConfig
+
+	clock         mockable.Clock
+	numGoroutines func() int
+
+	// Dispatch returns when closer is closed
+	closer chan struct{}
+}
+
+// NewTriggered returns a profiler that writes each capture to a new directory
+// in [dir]. The directory is named after the time of the capture and the
+// trigger that caused it.
+func NewTriggered(
+	log logging.Logger,
+	dir string,
+	cpuTracker CPUTracker,
+	config TriggerConfig,
+) TriggeredProfiler {
+	return &triggeredProfiler{
+		log:           log,
+		dir:           dir,
+		cpuTracker:    cpuTracker,
+		config:        config,
+		numGoroutines: runtime.NumGoroutine,
+		closer:        make(chan struct{}),
+	}
+}
+
+func (p *triggeredProfiler) Dispatch() error {
+	t := time.NewTicker(p.config.CheckFreq)
+	defer t.Stop()
+
+	var lastCaptureEnd time.Time
+	for {
+		select {
+		case <-p.closer:
+			return nil
+		case <-t.C:
+		}
+
+		if p.clock.Time().Sub(lastCaptureEnd) < p.config.Cooldown {
+			continue
+		}
+
+		trigger, ok := p.trigger()
+		if !ok {
+			continue
+		}
+
+		p.log.Info("capturing triggered profile",
+			zap.String("trigger", trigger),
+			zap.Duration("duration", p.config.Duration),
+		)
+		// Failing to capture a profile shouldn't take down the node, so errors
+		// are only logged.
+		if err := p.capture(trigger); err != nil {
+			p.log.Warn("failed to capture triggered profile",
+				zap.String("trigger", trigger),
+				zap.Error(err),
+			)
+		}
+		lastCaptureEnd = p.clock.Time()
+
+		if err := prune(p.dir, p.config.MaxSize); err != nil {
+			p.log.Warn("failed to prune triggered profiles",
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// trigger returns the name of the first exceeded threshold.
+func (p *triggeredProfiler) trigger() (string, bool) {
+	if p.config.CPUThreshold > 0 {
+		if usage := 100 * p.cpuTracker.TotalUsage(); usage > p.config.CPUThreshold {
+			return CPUTrigger, true
+		}
+	}
+	if p.config.GoroutineThreshold > 0 {
+		if p.numGoroutines() > p.config.GoroutineThreshold {
+			return GoroutineTrigger, true
+		}
+	}
+	return "", false
+}
+
+// capture writes a goroutine dump and then records a CPU profile and an
+// execution trace for the configured duration. If a CPU profile or an
+// execution trace is already being recorded, for example by the continuous
+// profiler, it is skipped.
+func (p *triggeredProfiler) capture(trigger string) error {
+	if err := os.MkdirAll(p.dir, perms.ReadWriteExecute); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s", p.clock.Time().UTC().Format(captureTimeFormat), trigger)
+	dir := filepath.Join(p.dir, name)
+	// Mkdir fails if the directory already exists, so a previous capture is
+	// never overwritten.
+	if err := os.Mkdir(dir, perms.ReadWriteExecute); err != nil {
+		return err
+	}
+
+	if err := writeGoroutineProfile(filepath.Join(dir, goroutineProfileFile)); err != nil {
+		return err
+	}
+
+	cpuFile, err := perms.Create(filepath.Join(dir, cpuProfileFile), perms.ReadWrite)
+	if err != nil {
+		return err
+	}
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		p.log.Warn("skipping triggered cpu profile",
+			zap.Error(err),
+		)
+		_ = cpuFile.Close()
+		_ = os.Remove(cpuFile.Name())
+		cpuFile = nil
+	}
+
+	traceFile, err := perms.Create(filepath.Join(dir, executionTraceFile), perms.ReadWrite)
+	if err != nil {
+		_ = stopCPUProfile(cpuFile) // Return the original error
+		return err
+	}
+	if err := trace.Start(traceFile); err != nil {
+		p.log.Warn("skipping triggered execution trace",
+			zap.Error(err),
+		)
+		_ = traceFile.Close()
+		_ = os.Remove(traceFile.Name())
+		traceFile = nil
+	}
+
+	timer := time.NewTimer(p.config.Duration)
+	select {
+	case <-timer.C:
+	case <-p.closer:
+		timer.Stop()
+	}
+
+	if traceFile != nil {
+		trace.Stop()
+	}
+	return errors.Join(
+		stopCPUProfile(cpuFile),
+		closeFile(traceFile),
+	)
+}
+
+func (p *triggeredProfiler) Shutdown() {
+	close(p.closer)
+}
+
+func writeGoroutineProfile(name string) error {
+	file, err := perms.Create(name, perms.ReadWrite)
+	if err != nil {
+		return err
+	}
+	profile := pprof.Lookup("goroutine")
+	// debug=2 formats the profile like the stacktrace of a panic.
+	if err := profile.WriteTo(file, 2); err != nil {
+		_ = file.Close() // Return the original error
+		return err
+	}
+	return file.Close()
+}
+
+func stopCPUProfile(file *os.File) error {
+	if file == nil {
+		return nil
+	}
+	pprof.StopCPUProfile()
+	return file.Close()
+}
+
+func closeFile(file *os.File) error {
+	if file == nil {
+		return nil
+	}
+	return file.Close()
+}
+
+// prune removes the oldest captures in [dir] until the captures use at most
+// [maxSize] bytes. The most recent capture is never removed. Files and
+// directories that aren't captures are ignored.
+func prune(dir string, maxSize uint64) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var (
+		captures []string
+		sizes    = make(map[string]uint64)
+		total    uint64
+	)
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || !captureDirRegex.MatchString(name) {
+			continue
+		}
+		size, err := dirSize(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		captures = append(captures, name)
+		sizes[name] = size
+		total += size
+	}
+	sort.Strings(captures)
+
+	for len(captures) > 1 && total > maxSize {
+		oldest := captures[0]
+		if err := os.RemoveAll(filepath.Join(dir, oldest)); err != nil {
+			return err
+		}
+		captures = captures[1:]
+		total -= sizes[oldest]
+	}
+	return nil
+}
+
+func dirSize(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(dir, func(_ string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += uint64(info.Size())
+		return nil
+	})
+	return size, err
+}
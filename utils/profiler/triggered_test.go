@@ -0,0 +1,267 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package profiler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+type testCPUTracker float64
+
+func (t testCPUTracker) TotalUsage() float64 {
+	return float64(t)
+}
+
+func TestTriggerConfigVerify(t *testing.T) {
+	valid := TriggerConfig{
+		Enabled:            true,
+		CheckFreq:          time.Second,
+		CPUThreshold:       150,
+		GoroutineThreshold: 10_000,
+		Duration:           30 * time.Second,
+		Cooldown:           10 * time.Minute,
+		MaxSize:            1024,
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(*TriggerConfig)
+		expectedErr error
+	}{
+		{
+			name:   "valid",
+			modify: func(*TriggerConfig) {},
+		},
+		{
+			name: "disabled",
+			modify: func(c *TriggerConfig) {
+				*c = TriggerConfig{}
+			},
+		},
+		{
+			name: "zero check frequency",
+			modify: func(c *TriggerConfig) {
+				c.CheckFreq = 0
+			},
+			expectedErr: errNonPositiveCheckFreq,
+		},
+		{
+			name: "zero duration",
+			modify: func(c *TriggerConfig) {
+				c.Duration = 0
+			},
+			expectedErr: errNonPositiveCaptureLength,
+		},
+		{
+			name: "negative cooldown",
+			modify: func(c *TriggerConfig) {
+				c.Cooldown = -time.Second
+			},
+			expectedErr: errNegativeCooldown,
+		},
+		{
+			name: "negative cpu threshold",
+			modify: func(c *TriggerConfig) {
+				c.CPUThreshold = -1
+			},
+			expectedErr: errNegativeThreshold,
+		},
+		{
+			name: "no thresholds",
+			modify: func(c *TriggerConfig) {
+				c.CPUThreshold = 0
+				c.GoroutineThreshold = 0
+			},
+			expectedErr: errNoTriggers,
+		},
+		{
+			name: "zero max size",
+			modify: func(c *TriggerConfig) {
+				c.MaxSize = 0
+			},
+			expectedErr: errNonPositiveMaxCaptureSize,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := valid
+			test.modify(&config)
+			require.ErrorIs(t, config.Verify(), test.expectedErr)
+		})
+	}
+}
+
+func TestTriggeredProfilerTrigger(t *testing.T) {
+	tests := []struct {
+		name               string
+		cpuUsage           float64
+		numGoroutines      int
+		cpuThreshold       float64
+		goroutineThreshold int
+		expectedTrigger    string
+		expectedOk         bool
+	}{
+		{
+			name:               "below thresholds",
+			cpuUsage:           1,
+			numGoroutines:      10,
+			cpuThreshold:       150,
+			goroutineThreshold: 100,
+		},
+		{
+			name:               "cpu above threshold",
+			cpuUsage:           2,
+			numGoroutines:      10,
+			cpuThreshold:       150,
+			goroutineThreshold: 100,
+			expectedTrigger:    CPUTrigger,
+			expectedOk:         true,
+		},
+		{
+			name:               "goroutines above threshold",
+			cpuUsage:           1,
+			numGoroutines:      101,
+			cpuThreshold:       150,
+			goroutineThreshold: 100,
+			expectedTrigger:    GoroutineTrigger,
+			expectedOk:         true,
+		},
+		{
+			name:            "cpu trigger disabled",
+			cpuUsage:        2,
+			numGoroutines:   101,
+			expectedTrigger: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			p := NewTriggered(
+				logging.NoLog{},
+				t.TempDir(),
+				testCPUTracker(test.cpuUsage),
+				TriggerConfig{
+					CPUThreshold:       test.cpuThreshold,
+					GoroutineThreshold: test.goroutineThreshold,
+				},
+			).(*triggeredProfiler)
+			p.numGoroutines = func() int {
+				return test.numGoroutines
+			}
+
+			trigger, ok := p.trigger()
+			require.Equal(test.expectedOk, ok)
+			require.Equal(test.expectedTrigger, trigger)
+		})
+	}
+}
+
+func TestTriggeredProfilerCapture(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	p := NewTriggered(
+		logging.NoLog{},
+		dir,
+		testCPUTracker(0),
+		TriggerConfig{
+			Enabled:            true,
+			CheckFreq:          time.Millisecond,
+			GoroutineThreshold: 1,
+			Duration:           10 * time.Millisecond,
+			Cooldown:           time.Hour,
+			MaxSize:            1 << 30,
+		},
+	)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- p.Dispatch()
+	}()
+
+	var profiles []Profile
+	require.Eventually(func() bool {
+		var err error
+		profiles, err = List(dir)
+		require.NoError(err)
+		return len(profiles) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	p.Shutdown()
+	require.NoError(<-errs)
+
+	// Only a single capture is made because of the cooldown.
+	profiles, err := List(dir)
+	require.NoError(err)
+	require.Len(profiles, 3)
+
+	captureDir := filepath.Dir(profiles[0].Path)
+	require.Regexp(`^\d{8}T\d{6}\.\d{9}Z-`+GoroutineTrigger+`$`, captureDir)
+	for i, name := range []string{cpuProfileFile, goroutineProfileFile, executionTraceFile} {
+		require.Equal(captureDir+"/"+name, profiles[i].Path)
+		require.Positive(profiles[i].Size)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	for _, name := range []string{"20240102T000000.000000000Z-cpu", "20240101T000000.000000000Z-cpu", "20240103T000000.000000000Z-cpu", "other"} {
+		captureDir := filepath.Join(dir, name)
+		require.NoError(os.MkdirAll(captureDir, perms.ReadWriteExecute))
+		require.NoError(perms.WriteFile(filepath.Join(captureDir, cpuProfileFile), make([]byte, 10), perms.ReadWrite))
+	}
+
+	// The captures fit
+	require.NoError(prune(dir, 30))
+	profiles, err := List(dir)
+	require.NoError(err)
+	require.Len(profiles, 4)
+
+	// The oldest captures are removed first
+	require.NoError(prune(dir, 25))
+	profiles, err = List(dir)
+	require.NoError(err)
+	require.Equal([]string{
+		"20240102T000000.000000000Z-cpu/" + cpuProfileFile,
+		"20240103T000000.000000000Z-cpu/" + cpuProfileFile,
+		"other/" + cpuProfileFile,
+	}, profilePaths(profiles))
+
+	// The most recent capture is always kept and directories that aren't
+	// captures are never removed
+	require.NoError(prune(dir, 1))
+	profiles, err = List(dir)
+	require.NoError(err)
+	require.Equal([]string{
+		"20240103T000000.000000000Z-cpu/" + cpuProfileFile,
+		"other/" + cpuProfileFile,
+	}, profilePaths(profiles))
+}
+
+func TestListMissingDir(t *testing.T) {
+	require := require.New(t)
+
+	profiles, err := List(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(err)
+	require.Empty(profiles)
+}
+
+func profilePaths(profiles []Profile) []string {
+	paths := make([]string, len(profiles))
+	for i, profile := range profiles {
+		paths[i] = profile.Path
+	}
+	return paths
+}
@@ -4,6 +4,8 @@
 package avax
 
 import (
+	"slices"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/cache"
@@ -31,6 +33,11 @@ type UTXOState interface {
 	UTXOReader
 	UTXOWriter
 
+	// NewUTXOIterator returns an iterator over the UTXOs in the database
+	// ordered by ID. The iterator reads the UTXOs that were in the database
+	// when it was created, so the database may be modified while iterating.
+	NewUTXOIterator() UTXOIterator
+
 	// Checksum returns the current UTXOChecksum.
 	Checksum() ids.ID
 }
@@ -70,6 +77,22 @@ type UTXOWriter interface {
 	DeleteUTXO(utxoID ids.ID) error
 }
 
+// UTXOIterator iterates over UTXOs.
+type UTXOIterator interface {
+	// Next moves the iterator to the next UTXO and returns whether there is
+	// one.
+	Next() bool
+
+	// Value returns the current UTXO.
+	Value() *UTXO
+
+	// Error returns the error that stopped the iteration, if any.
+	Error() error
+
+	// Release must be called once the iterator is no longer used.
+	Release()
+}
+
 type utxoState struct {
 	codec codec.Manager
 
@@ -251,6 +274,13 @@ func (s *utxoState) UTXOIDs(addr []byte, start ids.ID, limit int) ([]ids.ID, err
 	return utxoIDs, iter.Error()
 }
 
+func (s *utxoState) NewUTXOIterator() UTXOIterator {
+	return &utxoIterator{
+		codec: s.codec,
+		iter:  s.utxoDB.NewIterator(),
+	}
+}
+
 func (s *utxoState) Checksum() ids.ID {
 	return s.checksum
 }
@@ -292,3 +322,40 @@ func (s *utxoState) updateChecksum(modifiedID ids.ID) {
 
 	s.checksum = s.checksum.XOR(modifiedID)
 }
+
+type utxoIterator struct {
+	codec codec.Manager
+	iter  database.Iterator
+	utxo  *UTXO
+	err   error
+}
+
+func (it *utxoIterator) Next() bool {
+	it.utxo = nil
+	if it.err != nil || !it.iter.Next() {
+		return false
+	}
+
+	utxo := &UTXO{}
+	if _, err := it.codec.Unmarshal(slices.Clone(it.iter.Value()), utxo); err != nil {
+		it.err = err
+		return false
+	}
+	it.utxo = utxo
+	return true
+}
+
+func (it *utxoIterator) Value() *UTXO {
+	return it.utxo
+}
+
+func (it *utxoIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.iter.Error()
+}
+
+func (it *utxoIterator) Release() {
+	it.iter.Release()
+}
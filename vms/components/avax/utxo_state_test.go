@@ -91,4 +91,16 @@ func TestUTXOState(t *testing.T) {
 	utxoIDs, err = s.UTXOIDs(addr[:], ids.Empty, 5)
 	require.NoError(err)
 	require.Equal([]ids.ID{utxoID}, utxoIDs)
+
+	it := s.NewUTXOIterator()
+	defer it.Release()
+
+	// The iterator reads the UTXOs at the time it was created.
+	require.NoError(s.DeleteUTXO(utxoID))
+
+	require.True(it.Next())
+	require.Equal(utxoID, it.Value().InputID())
+	require.Equal(utxo, it.Value())
+	require.False(it.Next())
+	require.NoError(it.Error())
 }
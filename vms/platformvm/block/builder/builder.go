@@ -20,6 +20,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/platformvm/block"
+	"github.com/ava-labs/avalanchego/vms/platformvm/block/builder/policy"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
@@ -76,6 +77,7 @@ type builder struct {
 
 	txExecutorBackend *txexecutor.Backend
 	blkManager        blockexecutor.Manager
+	policy            policy.Policy
 
 	// resetTimer is used to signal that the block builder timer should update
 	// when it will trigger building of a block.
@@ -88,11 +90,13 @@ func New(
 	mempool mempool.Mempool,
 	txExecutorBackend *txexecutor.Backend,
 	blkManager blockexecutor.Manager,
+	policy policy.Policy,
 ) Builder {
 	return &builder{
 		Mempool:           mempool,
 		txExecutorBackend: txExecutorBackend,
 		blkManager:        blkManager,
+		policy:            policy,
 		resetTimer:        make(chan struct{}, 1),
 		closed:            make(chan struct{}),
 	}
@@ -250,6 +254,7 @@ func (b *builder) PackAllBlockTxs() ([]*txs.Tx, error) {
 		b.Mempool,
 		b.txExecutorBackend,
 		b.blkManager,
+		b.policy,
 		b.txExecutorBackend.Clk.Time(),
 		math.MaxInt,
 	)
@@ -270,6 +275,7 @@ func buildBlock(
 		builder.Mempool,
 		builder.txExecutorBackend,
 		builder.blkManager,
+		builder.policy,
 		timestamp,
 		targetBlockSize,
 	)
@@ -320,8 +326,9 @@ func packBlockTxs(
 	mempool mempool.Mempool,
 	backend *txexecutor.Backend,
 	manager blockexecutor.Manager,
+	policy policy.Policy,
 	timestamp time.Time,
-	remainingSize int,
+	targetSize int,
 ) ([]*txs.Tx, error) {
 	stateDiff, err := state.NewDiffOn(parentState)
 	if err != nil {
@@ -336,23 +343,16 @@ func packBlockTxs(
 		blockTxs      []*txs.Tx
 		inputs        set.Set[ids.ID]
 		feeCalculator = state.PickFeeCalculator(backend.Config, stateDiff)
+		budget        = policy.NewBudget(targetSize)
 	)
-	for {
-		tx, exists := mempool.Peek()
-		if !exists {
-			break
-		}
-		txSize := len(tx.Bytes())
-		if txSize > remainingSize {
-			break
-		}
+	err = policy.Select(mempool, budget, func(tx *txs.Tx) error {
 		mempool.Remove(tx)
 
 		// Invariant: [tx] has already been syntactically verified.
 
 		txDiff, err := state.NewDiffOn(stateDiff)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		executor := &txexecutor.StandardTxExecutor{
@@ -366,30 +366,33 @@ func packBlockTxs(
 		if err != nil {
 			txID := tx.ID()
 			mempool.MarkDropped(txID, err)
-			continue
+			return nil
 		}
 
 		if inputs.Overlaps(executor.Inputs) {
 			txID := tx.ID()
 			mempool.MarkDropped(txID, blockexecutor.ErrConflictingBlockTxs)
-			continue
+			return nil
 		}
 		err = manager.VerifyUniqueInputs(parentID, executor.Inputs)
 		if err != nil {
 			txID := tx.ID()
 			mempool.MarkDropped(txID, err)
-			continue
+			return nil
 		}
 		inputs.Union(executor.Inputs)
 
 		txDiff.AddTx(tx, status.Committed)
-		err = txDiff.Apply(stateDiff)
-		if err != nil {
-			return nil, err
+		if err := txDiff.Apply(stateDiff); err != nil {
+			return err
 		}
 
-		remainingSize -= txSize
+		budget.Consume(tx)
 		blockTxs = append(blockTxs, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return blockTxs, nil
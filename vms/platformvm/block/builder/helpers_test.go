@@ -36,6 +36,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/platformvm/api"
+	"github.com/ava-labs/avalanchego/vms/platformvm/block/builder/policy"
 	"github.com/ava-labs/avalanchego/vms/platformvm/config"
 	"github.com/ava-labs/avalanchego/vms/platformvm/fx"
 	"github.com/ava-labs/avalanchego/vms/platformvm/metrics"
@@ -185,6 +186,7 @@ func newEnvironment(t *testing.T, f fork) *environment { //nolint:unparam
 		res.state,
 		&res.backend,
 		pvalidators.TestManager,
+		false,
 	)
 
 	txVerifier := network.NewLockedTxVerifier(&res.ctx.Lock, res.blkManager)
@@ -202,10 +204,13 @@ func newEnvironment(t *testing.T, f fork) *environment { //nolint:unparam
 	)
 	require.NoError(err)
 
+	blockBuilderPolicy, err := policy.New(policy.DefaultConfig, res.ctx.AVAXAssetID)
+	require.NoError(err)
 	res.Builder = New(
 		res.mempool,
 		&res.backend,
 		res.blkManager,
+		blockBuilderPolicy,
 	)
 	res.Builder.StartBlockTimer()
 
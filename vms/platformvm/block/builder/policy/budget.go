@@ -0,0 +1,152 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+var (
+	_ Budget = (*sizeBudget)(nil)
+	_ Policy = (*reserved)(nil)
+	_ Budget = (*reservedBudget)(nil)
+
+	ErrInvalidReservation = errors.New("invalid block space reservation")
+)
+
+// Budget tracks the space of a block that is being built.
+type Budget interface {
+	// Fits returns true if [tx] can be added to the block.
+	Fits(tx *txs.Tx) bool
+	// Consume records that [tx] was added to the block. Consume must only be
+	// called after Fits returned true for [tx].
+	Consume(tx *txs.Tx)
+	// Remaining returns the number of bytes that can still be added to the
+	// block. Once it is 0, no more txs fit into the block.
+	Remaining() int
+}
+
+// Reservation reserves a percentage of the block space for a category of txs.
+// Reserved space that isn't used by its category isn't available to other
+// categories.
+type Reservation struct {
+	// Category is one of "validator", "subnet" or "transfer"
+	Category string `json:"category"`
+	// Percent of the block space reserved for the category
+	Percent uint64 `json:"percent"`
+}
+
+// sizeBudgeter provides budgets that only limit the total size of the txs.
+type sizeBudgeter struct{}
+
+func (sizeBudgeter) NewBudget(targetSize int) Budget {
+	return &sizeBudget{
+		remaining: targetSize,
+	}
+}
+
+type sizeBudget struct {
+	remaining int
+}
+
+func (b *sizeBudget) Fits(tx *txs.Tx) bool {
+	return len(tx.Bytes()) <= b.remaining
+}
+
+func (b *sizeBudget) Consume(tx *txs.Tx) {
+	b.remaining -= len(tx.Bytes())
+}
+
+func (b *sizeBudget) Remaining() int {
+	return b.remaining
+}
+
+// reserved applies reservations to the budgets of a policy.
+type reserved struct {
+	Policy
+
+	percents map[Category]uint64
+}
+
+func newReserved(policy Policy, reservations []Reservation) (*reserved, error) {
+	var (
+		percents = make(map[Category]uint64, len(reservations))
+		total    uint64
+	)
+	for _, reservation := range reservations {
+		category, err := ParseCategory(reservation.Category)
+		if err != nil {
+			return nil, err
+		}
+		if reservation.Percent == 0 {
+			return nil, fmt.Errorf("%w: %s reserves 0%%", ErrInvalidReservation, category)
+		}
+		if _, ok := percents[category]; ok {
+			return nil, fmt.Errorf("%w: %s is reserved multiple times", ErrInvalidReservation, category)
+		}
+		total += reservation.Percent
+		if total > 100 {
+			return nil, fmt.Errorf("%w: more than 100%% is reserved", ErrInvalidReservation)
+		}
+		percents[category] = reservation.Percent
+	}
+	return &reserved{
+		Policy:   policy,
+		percents: percents,
+	}, nil
+}
+
+func (r *reserved) NewBudget(targetSize int) Budget {
+	b := &reservedBudget{
+		shared:   r.Policy.NewBudget(targetSize),
+		reserved: make(map[Category]int, len(r.percents)),
+	}
+	var totalReserved int
+	for category, percent := range r.percents {
+		// Calculated without multiplying [targetSize] by [percent] to avoid
+		// overflowing when building blocks without a target size.
+		p := int(percent)
+		reservedSize := targetSize/100*p + targetSize%100*p/100
+		b.reserved[category] = reservedSize
+		totalReserved += reservedSize
+	}
+	b.sharedRemaining = targetSize - totalReserved
+	return b
+}
+
+// reservedBudget consumes the reserved space of the category of a tx before
+// the space shared by all the categories.
+type reservedBudget struct {
+	// shared enforces the limits of the wrapped policy
+	shared          Budget
+	sharedRemaining int
+	reserved        map[Category]int
+}
+
+func (b *reservedBudget) Fits(tx *txs.Tx) bool {
+	if !b.shared.Fits(tx) {
+		return false
+	}
+	category := CategoryOf(tx.Unsigned)
+	return len(tx.Bytes()) <= b.reserved[category]+b.sharedRemaining
+}
+
+func (b *reservedBudget) Consume(tx *txs.Tx) {
+	b.shared.Consume(tx)
+
+	size := len(tx.Bytes())
+	category := CategoryOf(tx.Unsigned)
+	fromReserved := min(size, b.reserved[category])
+	b.reserved[category] -= fromReserved
+	b.sharedRemaining -= size - fromReserved
+}
+
+func (b *reservedBudget) Remaining() int {
+	// The reservations partition the space of the shared budget, so they
+	// don't change the total remaining space.
+	return b.shared.Remaining()
+}
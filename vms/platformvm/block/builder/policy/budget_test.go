@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package policy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+func TestSizeBudget(t *testing.T) {
+	require := require.New(t)
+
+	policy, err := New(DefaultConfig, avaxAssetID)
+	require.NoError(err)
+
+	budget := policy.NewBudget(250)
+	tx0 := newTx(0, 200, newBaseTx(2, 1))
+	tx1 := newTx(1, 100, newBaseTx(2, 1))
+	tx2 := newTx(2, 50, newBaseTx(2, 1))
+
+	require.True(budget.Fits(tx0))
+	budget.Consume(tx0)
+	require.False(budget.Fits(tx1))
+	require.True(budget.Fits(tx2))
+	budget.Consume(tx2)
+	require.False(budget.Fits(tx2))
+	require.Zero(budget.Remaining())
+}
+
+func TestReservedBudget(t *testing.T) {
+	require := require.New(t)
+
+	policy, err := New(Config{
+		Name: FIFO,
+		Reservations: []Reservation{
+			{
+				Category: "validator",
+				Percent:  50,
+			},
+			{
+				Category: "subnet",
+				Percent:  20,
+			},
+		},
+	}, avaxAssetID)
+	require.NoError(err)
+
+	// 500 bytes are reserved for validator txs, 200 bytes for subnet txs and
+	// 300 bytes are shared.
+	budget := policy.NewBudget(1_000)
+	var (
+		transfer0    = newTx(0, 250, newBaseTx(2, 1))
+		transfer1    = newTx(1, 100, newBaseTx(2, 1))
+		createSubnet = newTx(2, 250, &txs.CreateSubnetTx{
+			BaseTx: *newBaseTx(2, 1),
+		})
+		addValidator = newTx(3, 500, &txs.AddSubnetValidatorTx{
+			BaseTx: *newBaseTx(2, 1),
+		})
+	)
+
+	// Transfers can only use the shared space.
+	require.True(budget.Fits(transfer0))
+	budget.Consume(transfer0)
+	require.False(budget.Fits(transfer1))
+
+	// The subnet tx uses its reservation and the remaining shared space.
+	require.True(budget.Fits(createSubnet))
+	budget.Consume(createSubnet)
+
+	// The reserved space of the validator txs is still available.
+	require.True(budget.Fits(addValidator))
+	budget.Consume(addValidator)
+	require.False(budget.Fits(addValidator))
+	require.Zero(budget.Remaining())
+}
+
+func TestReservedBudgetWithoutTargetSize(t *testing.T) {
+	require := require.New(t)
+
+	policy, err := New(Config{
+		Name: FIFO,
+		Reservations: []Reservation{
+			{
+				Category: "validator",
+				Percent:  99,
+			},
+		},
+	}, avaxAssetID)
+	require.NoError(err)
+
+	budget := policy.NewBudget(math.MaxInt)
+	require.True(budget.Fits(newTx(0, 100, newBaseTx(2, 1))))
+}
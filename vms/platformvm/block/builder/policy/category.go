@@ -0,0 +1,77 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+const (
+	ValidatorCategory Category = iota
+	SubnetCategory
+	TransferCategory
+)
+
+var ErrUnknownCategory = errors.New("unknown tx category")
+
+// Category groups the txs that block space can be reserved for. Categories are
+// ordered from the highest to the lowest priority.
+type Category uint8
+
+func ParseCategory(s string) (Category, error) {
+	switch s {
+	case "validator":
+		return ValidatorCategory, nil
+	case "subnet":
+		return SubnetCategory, nil
+	case "transfer":
+		return TransferCategory, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCategory, s)
+	}
+}
+
+func (c Category) String() string {
+	switch c {
+	case ValidatorCategory:
+		return "validator"
+	case SubnetCategory:
+		return "subnet"
+	case TransferCategory:
+		return "transfer"
+	default:
+		return "unknown"
+	}
+}
+
+// CategoryOf returns the category of [tx].
+//
+//   - Validator txs modify the validator set of a subnet.
+//   - Subnet txs create or manage subnets and chains.
+//   - Transfer txs move funds.
+func CategoryOf(tx txs.UnsignedTx) Category {
+	switch tx.(type) {
+	case *txs.AddValidatorTx,
+		*txs.AddSubnetValidatorTx,
+		*txs.AddDelegatorTx,
+		*txs.RemoveSubnetValidatorTx,
+		*txs.AddPermissionlessValidatorTx,
+		*txs.AddPermissionlessDelegatorTx,
+		*txs.ConvertSubnetTx,
+		*txs.RegisterSubnetValidatorTx,
+		*txs.SetSubnetValidatorWeightTx,
+		*txs.IncreaseBalanceTx:
+		return ValidatorCategory
+	case *txs.CreateSubnetTx,
+		*txs.CreateChainTx,
+		*txs.TransformSubnetTx,
+		*txs.TransferSubnetOwnershipTx:
+		return SubnetCategory
+	default:
+		return TransferCategory
+	}
+}
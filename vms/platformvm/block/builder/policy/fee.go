@@ -0,0 +1,189 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package policy
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+var (
+	_ txs.Visitor = (*burnedVisitor)(nil)
+
+	ErrUnsupportedTx = errors.New("unsupported transaction type")
+	errProducedMore  = errors.New("tx produces more AVAX than it consumes")
+)
+
+// Burned returns the amount of AVAX burned by [tx]. AVAX that is staked or
+// added to the balance of a validator isn't considered to be burned.
+func Burned(tx txs.UnsignedTx, avaxAssetID ids.ID) (uint64, error) {
+	v := burnedVisitor{
+		avaxAssetID: avaxAssetID,
+	}
+	if err := tx.Visit(&v); err != nil {
+		return 0, err
+	}
+	if v.produced > v.consumed {
+		return 0, errProducedMore
+	}
+	return v.consumed - v.produced, nil
+}
+
+type burnedVisitor struct {
+	// inputs
+	avaxAssetID ids.ID
+
+	// outputs
+	consumed uint64
+	produced uint64
+}
+
+func (*burnedVisitor) AdvanceTimeTx(*txs.AdvanceTimeTx) error {
+	return ErrUnsupportedTx
+}
+
+func (*burnedVisitor) RewardValidatorTx(*txs.RewardValidatorTx) error {
+	return ErrUnsupportedTx
+}
+
+func (v *burnedVisitor) AddValidatorTx(tx *txs.AddValidatorTx) error {
+	return v.stakerTx(&tx.BaseTx, tx.StakeOuts)
+}
+
+func (v *burnedVisitor) AddSubnetValidatorTx(tx *txs.AddSubnetValidatorTx) error {
+	return v.baseTx(&tx.BaseTx)
+}
+
+func (v *burnedVisitor) AddDelegatorTx(tx *txs.AddDelegatorTx) error {
+	return v.stakerTx(&tx.BaseTx, tx.StakeOuts)
+}
+
+func (v *burnedVisitor) CreateChainTx(tx *txs.CreateChainTx) error {
+	return v.baseTx(&tx.BaseTx)
+}
+
+func (v *burnedVisitor) CreateSubnetTx(tx *txs.CreateSubnetTx) error {
+	return v.baseTx(&tx.BaseTx)
+}
+
+func (v *burnedVisitor) ImportTx(tx *txs.ImportTx) error {
+	if err := v.baseTx(&tx.BaseTx); err != nil {
+		return err
+	}
+	return v.consume(tx.ImportedInputs)
+}
+
+func (v *burnedVisitor) ExportTx(tx *txs.ExportTx) error {
+	if err := v.baseTx(&tx.BaseTx); err != nil {
+		return err
+	}
+	return v.produce(tx.ExportedOutputs)
+}
+
+func (v *burnedVisitor) RemoveSubnetValidatorTx(tx *txs.RemoveSubnetValidatorTx) error {
+	return v.baseTx(&tx.BaseTx)
+}
+
+func (v *burnedVisitor) TransformSubnetTx(tx *txs.TransformSubnetTx) error {
+	return v.baseTx(&tx.BaseTx)
+}
+
+func (v *burnedVisitor) AddPermissionlessValidatorTx(tx *txs.AddPermissionlessValidatorTx) error {
+	return v.stakerTx(&tx.BaseTx, tx.StakeOuts)
+}
+
+func (v *burnedVisitor) AddPermissionlessDelegatorTx(tx *txs.AddPermissionlessDelegatorTx) error {
+	return v.stakerTx(&tx.BaseTx, tx.StakeOuts)
+}
+
+func (v *burnedVisitor) TransferSubnetOwnershipTx(tx *txs.TransferSubnetOwnershipTx) error {
+	return v.baseTx(&tx.BaseTx)
+}
+
+func (v *burnedVisitor) BaseTx(tx *txs.BaseTx) error {
+	return v.baseTx(tx)
+}
+
+func (v *burnedVisitor) ConvertSubnetTx(tx *txs.ConvertSubnetTx) error {
+	if err := v.baseTx(&tx.BaseTx); err != nil {
+		return err
+	}
+	for _, vdr := range tx.Validators {
+		if err := v.addProduced(vdr.Balance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *burnedVisitor) RegisterSubnetValidatorTx(tx *txs.RegisterSubnetValidatorTx) error {
+	if err := v.baseTx(&tx.BaseTx); err != nil {
+		return err
+	}
+	return v.addProduced(tx.Balance)
+}
+
+func (v *burnedVisitor) SetSubnetValidatorWeightTx(tx *txs.SetSubnetValidatorWeightTx) error {
+	return v.baseTx(&tx.BaseTx)
+}
+
+func (v *burnedVisitor) IncreaseBalanceTx(tx *txs.IncreaseBalanceTx) error {
+	if err := v.baseTx(&tx.BaseTx); err != nil {
+		return err
+	}
+	return v.addProduced(tx.Balance)
+}
+
+func (v *burnedVisitor) stakerTx(tx *txs.BaseTx, stake []*avax.TransferableOutput) error {
+	if err := v.baseTx(tx); err != nil {
+		return err
+	}
+	return v.produce(stake)
+}
+
+func (v *burnedVisitor) baseTx(tx *txs.BaseTx) error {
+	if err := v.consume(tx.Ins); err != nil {
+		return err
+	}
+	return v.produce(tx.Outs)
+}
+
+func (v *burnedVisitor) consume(ins []*avax.TransferableInput) error {
+	for _, in := range ins {
+		if in.AssetID() != v.avaxAssetID {
+			continue
+		}
+		consumed, err := math.Add(v.consumed, in.Input().Amount())
+		if err != nil {
+			return err
+		}
+		v.consumed = consumed
+	}
+	return nil
+}
+
+func (v *burnedVisitor) produce(outs []*avax.TransferableOutput) error {
+	for _, out := range outs {
+		if out.AssetID() != v.avaxAssetID {
+			continue
+		}
+		if err := v.addProduced(out.Output().Amount()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *burnedVisitor) addProduced(amount uint64) error {
+	produced, err := math.Add(v.produced, amount)
+	if err != nil {
+		return err
+	}
+	v.produced = produced
+	return nil
+}
@@ -0,0 +1,248 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package policy defines how the block builder selects the mempool txs it
+// includes in blocks.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/heap"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+const (
+	// FIFO attempts to include txs in the order they were added to the
+	// mempool.
+	FIFO = "fifo"
+	// FeePriority attempts to include the txs paying the highest fee per byte
+	// first.
+	FeePriority = "fee-priority"
+	// ValidatorPriority attempts to include the txs modifying validator sets
+	// first, followed by the txs managing subnets and chains and then by the
+	// transfers. Txs of the same category are attempted in FIFO order.
+	ValidatorPriority = "validator-priority"
+)
+
+var (
+	_ Policy = fifo{}
+	_ Policy = (*feePriority)(nil)
+	_ Policy = validatorPriority{}
+
+	DefaultConfig = Config{
+		Name: FIFO,
+	}
+
+	ErrUnknownPolicy = errors.New("unknown block building policy")
+)
+
+// Policy selects the mempool txs that the block builder attempts to include in
+// a block.
+type Policy interface {
+	// Select calls [include] with the txs of [mempool], in the order the
+	// builder should attempt to include them in the block tracked by
+	// [budget], until no more txs fit into the block. Txs that aren't selected
+	// are left in the mempool.
+	//
+	// [include] must remove the tx from [mempool] and consume [budget] if the
+	// tx is added to the block. Selection stops if [include] errors.
+	Select(mempool Mempool, budget Budget, include func(*txs.Tx) error) error
+
+	// NewBudget returns the budget of a block that contains at most
+	// [targetSize] bytes of txs.
+	NewBudget(targetSize int) Budget
+}
+
+// Mempool is the subset of the mempool that policies select txs from.
+type Mempool interface {
+	// Peek returns the oldest tx in the mempool.
+	Peek() (tx *txs.Tx, exists bool)
+	// Iterate iterates over the txs, in the order they were added to the
+	// mempool, until f returns false.
+	Iterate(f func(tx *txs.Tx) bool)
+}
+
+// Config selects and configures the policy used by the block builder.
+type Config struct {
+	// Name of the policy ordering the txs
+	Name string `json:"name"`
+	// Reservations of block space for categories of txs. Reservations are
+	// applied on top of the ordering of the policy.
+	Reservations []Reservation `json:"reservations"`
+}
+
+// New returns the policy described by [config]. [avaxAssetID] is used to
+// calculate the fees paid by txs.
+func New(config Config, avaxAssetID ids.ID) (Policy, error) {
+	var policy Policy
+	switch config.Name {
+	case FIFO:
+		policy = fifo{}
+	case FeePriority:
+		policy = &feePriority{
+			avaxAssetID: avaxAssetID,
+		}
+	case ValidatorPriority:
+		policy = validatorPriority{}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPolicy, config.Name)
+	}
+
+	if len(config.Reservations) == 0 {
+		return policy, nil
+	}
+	return newReserved(policy, config.Reservations)
+}
+
+type fifo struct {
+	sizeBudgeter
+}
+
+// Select includes the oldest tx until the mempool is empty or the oldest tx
+// doesn't fit into the block. Skipping the oldest tx would allow newer txs to
+// be included before it.
+func (fifo) Select(mempool Mempool, budget Budget, include func(*txs.Tx) error) error {
+	for budget.Remaining() > 0 {
+		tx, exists := mempool.Peek()
+		if !exists || !budget.Fits(tx) {
+			return nil
+		}
+		if err := include(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type feePriority struct {
+	sizeBudgeter
+
+	avaxAssetID ids.ID
+}
+
+// feeRateDenominator allows fees that are smaller than the tx size to be
+// compared.
+const feeRateDenominator = 1_000
+
+func (p *feePriority) Select(mempool Mempool, budget Budget, include func(*txs.Tx) error) error {
+	return selectRanked(mempool, budget, p.rank, include)
+}
+
+// rank orders txs by decreasing fee paid per byte.
+func (p *feePriority) rank(tx *txs.Tx) uint64 {
+	// Txs whose fee can't be calculated will fail execution, so they are
+	// attempted last.
+	fee, err := Burned(tx.Unsigned, p.avaxAssetID)
+	if err != nil {
+		return math.MaxUint64
+	}
+	size := uint64(max(len(tx.Bytes()), 1))
+	feeRate := fee / size * feeRateDenominator
+	feeRate += fee % size * feeRateDenominator / size
+	return math.MaxUint64 - feeRate
+}
+
+type validatorPriority struct {
+	sizeBudgeter
+}
+
+func (validatorPriority) Select(mempool Mempool, budget Budget, include func(*txs.Tx) error) error {
+	return selectRanked(mempool, budget, rankByCategory, include)
+}
+
+func rankByCategory(tx *txs.Tx) uint64 {
+	return uint64(CategoryOf(tx.Unsigned))
+}
+
+type rankedTx struct {
+	tx   *txs.Tx
+	rank uint64
+	// index is the position of the tx in the mempool, which breaks ties
+	// between txs of the same rank.
+	index int
+}
+
+func (t rankedTx) before(o rankedTx) bool {
+	if t.rank != o.rank {
+		return t.rank < o.rank
+	}
+	return t.index < o.index
+}
+
+// selectRanked calls [include] with the txs of [mempool] in increasing order of
+// [rank]. Txs of the same rank are selected in FIFO order.
+//
+// Rather than copying the whole mempool, only the best ranked txs needed to
+// fill the remaining space of [budget] are kept while iterating over the
+// mempool. If some of them aren't added to the block, the block may be built
+// with less than its target size and the remaining txs are left for a future
+// block.
+func selectRanked(
+	mempool Mempool,
+	budget Budget,
+	rank func(*txs.Tx) uint64,
+	include func(*txs.Tx) error,
+) error {
+	remaining := budget.Remaining()
+	if remaining <= 0 {
+		return nil
+	}
+
+	var (
+		// The worst ranked candidate is at the top of the heap.
+		candidates = heap.NewQueue(func(a, b rankedTx) bool {
+			return b.before(a)
+		})
+		size  int
+		index int
+	)
+	mempool.Iterate(func(tx *txs.Tx) bool {
+		// Txs that don't fit into this block are left in the mempool for a
+		// future block.
+		if !budget.Fits(tx) {
+			return true
+		}
+
+		candidates.Push(rankedTx{
+			tx:    tx,
+			rank:  rank(tx),
+			index: index,
+		})
+		index++
+		size += len(tx.Bytes())
+
+		// Drop the worst ranked candidates that aren't needed to fill the
+		// block.
+		for {
+			worst, _ := candidates.Peek()
+			worstSize := len(worst.tx.Bytes())
+			if size-worstSize < remaining {
+				return true
+			}
+			candidates.Pop()
+			size -= worstSize
+		}
+	})
+
+	ordered := make([]*txs.Tx, candidates.Len())
+	for i := len(ordered) - 1; i >= 0; i-- {
+		candidate, _ := candidates.Pop()
+		ordered[i] = candidate.tx
+	}
+	for _, tx := range ordered {
+		if budget.Remaining() <= 0 {
+			return nil
+		}
+		if !budget.Fits(tx) {
+			continue
+		}
+		if err := include(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
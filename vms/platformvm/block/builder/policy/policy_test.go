@@ -0,0 +1,347 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package policy
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var avaxAssetID = ids.GenerateTestID()
+
+func newBaseTx(consumed, produced uint64) *txs.BaseTx {
+	return &txs.BaseTx{
+		BaseTx: avax.BaseTx{
+			Ins: []*avax.TransferableInput{{
+				Asset: avax.Asset{ID: avaxAssetID},
+				In: &secp256k1fx.TransferInput{
+					Amt: consumed,
+				},
+			}},
+			Outs: []*avax.TransferableOutput{{
+				Asset: avax.Asset{ID: avaxAssetID},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: produced,
+				},
+			}},
+		},
+	}
+}
+
+// newTx returns a tx of [size] bytes. [id] makes the bytes of txs with the
+// same size unique.
+func newTx(id byte, size int, utx txs.UnsignedTx) *txs.Tx {
+	bytes := make([]byte, size)
+	bytes[0] = id
+	tx := &txs.Tx{Unsigned: utx}
+	tx.SetBytes(bytes, bytes)
+	return tx
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name:   "default",
+			config: DefaultConfig,
+		},
+		{
+			name: "reservations",
+			config: Config{
+				Name: FeePriority,
+				Reservations: []Reservation{
+					{
+						Category: "validator",
+						Percent:  60,
+					},
+					{
+						Category: "transfer",
+						Percent:  40,
+					},
+				},
+			},
+		},
+		{
+			name: "unknown policy",
+			config: Config{
+				Name: "unknown",
+			},
+			expectedErr: ErrUnknownPolicy,
+		},
+		{
+			name: "unknown category",
+			config: Config{
+				Name: FIFO,
+				Reservations: []Reservation{
+					{
+						Category: "unknown",
+						Percent:  10,
+					},
+				},
+			},
+			expectedErr: ErrUnknownCategory,
+		},
+		{
+			name: "zero percent",
+			config: Config{
+				Name: FIFO,
+				Reservations: []Reservation{
+					{
+						Category: "subnet",
+					},
+				},
+			},
+			expectedErr: ErrInvalidReservation,
+		},
+		{
+			name: "duplicate category",
+			config: Config{
+				Name: FIFO,
+				Reservations: []Reservation{
+					{
+						Category: "subnet",
+						Percent:  10,
+					},
+					{
+						Category: "subnet",
+						Percent:  10,
+					},
+				},
+			},
+			expectedErr: ErrInvalidReservation,
+		},
+		{
+			name: "more than 100 percent",
+			config: Config{
+				Name: ValidatorPriority,
+				Reservations: []Reservation{
+					{
+						Category: "validator",
+						Percent:  60,
+					},
+					{
+						Category: "subnet",
+						Percent:  41,
+					},
+				},
+			},
+			expectedErr: ErrInvalidReservation,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(test.config, avaxAssetID)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+// testMempool is a FIFO mempool that counts the txs it was asked for.
+type testMempool struct {
+	txs      []*txs.Tx
+	accessed int
+}
+
+func (m *testMempool) Peek() (*txs.Tx, bool) {
+	if len(m.txs) == 0 {
+		return nil, false
+	}
+	m.accessed++
+	return m.txs[0], true
+}
+
+func (m *testMempool) Iterate(f func(tx *txs.Tx) bool) {
+	for _, tx := range m.txs {
+		m.accessed++
+		if !f(tx) {
+			return
+		}
+	}
+}
+
+func (m *testMempool) Remove(tx *txs.Tx) {
+	m.txs = slices.DeleteFunc(m.txs, func(t *txs.Tx) bool {
+		return t == tx
+	})
+}
+
+// selectTxs returns the txs selected by [policy] from [mempool] for a block of
+// [targetSize] bytes. Txs whose first byte is in [dropped] fail to be added to
+// the block.
+func selectTxs(
+	t *testing.T,
+	policy Policy,
+	mempool *testMempool,
+	targetSize int,
+	dropped ...byte,
+) []*txs.Tx {
+	var (
+		budget   = policy.NewBudget(targetSize)
+		selected []*txs.Tx
+	)
+	err := policy.Select(mempool, budget, func(tx *txs.Tx) error {
+		mempool.Remove(tx)
+		if slices.Contains(dropped, tx.Bytes()[0]) {
+			return nil
+		}
+		budget.Consume(tx)
+		selected = append(selected, tx)
+		return nil
+	})
+	require.NoError(t, err)
+	return selected
+}
+
+func TestSelect(t *testing.T) {
+	var (
+		// transfer paying 1 per byte
+		transfer = newTx(0, 100, newBaseTx(200, 100))
+		// subnet tx paying 2 per byte
+		createSubnet = newTx(1, 100, &txs.CreateSubnetTx{
+			BaseTx: *newBaseTx(300, 100),
+		})
+		// validator tx paying 0.5 per byte
+		addValidator = newTx(2, 200, &txs.AddValidatorTx{
+			BaseTx: *newBaseTx(1_200, 100),
+			StakeOuts: []*avax.TransferableOutput{{
+				Asset: avax.Asset{ID: avaxAssetID},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: 1_000,
+				},
+			}},
+		})
+		// tx whose fee can't be calculated
+		invalid = newTx(3, 100, newBaseTx(100, 200))
+	)
+
+	tests := []struct {
+		name       string
+		policy     string
+		mempool    []*txs.Tx
+		targetSize int
+		expected   []*txs.Tx
+	}{
+		{
+			name:       "fifo",
+			policy:     FIFO,
+			targetSize: math.MaxInt,
+			expected:   []*txs.Tx{transfer, invalid, createSubnet, addValidator},
+		},
+		{
+			name:       "fifo stops at the first tx that doesn't fit",
+			policy:     FIFO,
+			mempool:    []*txs.Tx{transfer, addValidator, createSubnet},
+			targetSize: 250,
+			expected:   []*txs.Tx{transfer},
+		},
+		{
+			name:       "fee priority",
+			policy:     FeePriority,
+			targetSize: math.MaxInt,
+			expected:   []*txs.Tx{createSubnet, transfer, addValidator, invalid},
+		},
+		{
+			name:       "fee priority skips txs that don't fit",
+			policy:     FeePriority,
+			targetSize: 250,
+			expected:   []*txs.Tx{createSubnet, transfer},
+		},
+		{
+			name:       "validator priority",
+			policy:     ValidatorPriority,
+			targetSize: math.MaxInt,
+			expected:   []*txs.Tx{addValidator, createSubnet, transfer, invalid},
+		},
+		{
+			name:       "validator priority skips txs that don't fit",
+			policy:     ValidatorPriority,
+			targetSize: 150,
+			expected:   []*txs.Tx{createSubnet},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			policy, err := New(Config{Name: test.policy}, avaxAssetID)
+			require.NoError(err)
+
+			mempool := &testMempool{
+				txs: test.mempool,
+			}
+			if mempool.txs == nil {
+				mempool.txs = []*txs.Tx{transfer, invalid, createSubnet, addValidator}
+			}
+			require.Equal(test.expected, selectTxs(t, policy, mempool, test.targetSize))
+		})
+	}
+}
+
+func TestSelectStopsOnceBlockIsFull(t *testing.T) {
+	for _, name := range []string{FIFO, FeePriority, ValidatorPriority} {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			policy, err := New(Config{Name: name}, avaxAssetID)
+			require.NoError(err)
+
+			mempool := &testMempool{}
+			for i := 0; i < 10; i++ {
+				mempool.txs = append(mempool.txs, newTx(byte(i), 100, newBaseTx(200, 100)))
+			}
+			expected := slices.Clone(mempool.txs[:2])
+
+			require.Equal(expected, selectTxs(t, policy, mempool, 200))
+			require.Len(mempool.txs, 8)
+		})
+	}
+}
+
+func TestFIFOOnlyPeeksIncludedTxs(t *testing.T) {
+	require := require.New(t)
+
+	policy, err := New(DefaultConfig, avaxAssetID)
+	require.NoError(err)
+
+	mempool := &testMempool{}
+	for i := 0; i < 10; i++ {
+		mempool.txs = append(mempool.txs, newTx(byte(i), 100, newBaseTx(200, 100)))
+	}
+
+	selectTxs(t, policy, mempool, 200)
+	require.Equal(2, mempool.accessed)
+}
+
+func TestSelectRankedOnlyKeepsNeededTxs(t *testing.T) {
+	require := require.New(t)
+
+	policy, err := New(Config{Name: FeePriority}, avaxAssetID)
+	require.NoError(err)
+
+	var (
+		// paying 3, 2 and 1 per byte
+		tx0 = newTx(0, 100, newBaseTx(300, 0))
+		tx1 = newTx(1, 100, newBaseTx(200, 0))
+		tx2 = newTx(2, 100, newBaseTx(100, 0))
+	)
+	mempool := &testMempool{
+		txs: []*txs.Tx{tx2, tx1, tx0},
+	}
+
+	// Only the txs needed to fill the block are selected. The dropped tx
+	// isn't replaced in this block.
+	require.Equal([]*txs.Tx{tx1}, selectTxs(t, policy, mempool, 200, 0))
+	require.Equal([]*txs.Tx{tx2}, mempool.txs)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/block"
 	"github.com/ava-labs/avalanchego/vms/platformvm/metrics"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/platformvm/validators"
 )
 
@@ -30,6 +31,10 @@ type acceptor struct {
 	metrics      metrics.Metrics
 	validators   validators.Manager
 	bootstrapped *utils.Atomic[bool]
+
+	// If true, the outcome of staking periods is added to the reward history
+	// of the state.
+	indexRewardHistory bool
 }
 
 func (a *acceptor) BanffAbortBlock(b *block.BanffAbortBlock) error {
@@ -140,6 +145,14 @@ func (a *acceptor) optionBlock(b block.Block, blockType string) error {
 		return err
 	}
 
+	// The reward history must be indexed prior to applying the option, as the
+	// rewarded staker is removed by the option.
+	if a.indexRewardHistory {
+		if err := a.indexReward(parentState.statelessBlock, b); err != nil {
+			return fmt.Errorf("failed to index reward of block %s: %w", blkID, err)
+		}
+	}
+
 	if parentState.onDecisionState != nil {
 		if err := parentState.onDecisionState.Apply(a.state); err != nil {
 			return err
@@ -185,6 +198,35 @@ func (a *acceptor) optionBlock(b block.Block, blockType string) error {
 	return nil
 }
 
+// indexReward adds the outcome of the staking period decided by the option
+// [b] of the proposal block [parent] to the reward history.
+func (a *acceptor) indexReward(parent block.Block, b block.Block) error {
+	parentTxs := parent.Txs()
+	if len(parentTxs) == 0 {
+		return nil
+	}
+
+	// The proposal tx is always the last tx of a proposal block.
+	proposalTx := parentTxs[len(parentTxs)-1]
+	rewardTx, ok := proposalTx.Unsigned.(*txs.RewardValidatorTx)
+	if !ok {
+		return nil
+	}
+
+	var rewarded bool
+	switch b.(type) {
+	case *block.BanffCommitBlock, *block.ApricotCommitBlock:
+		rewarded = true
+	}
+
+	record, err := state.NewRewardRecord(a.state, proposalTx.ID(), rewardTx, rewarded, b.Height())
+	if err != nil {
+		return err
+	}
+	a.state.AddRewardRecord(record)
+	return nil
+}
+
 func (a *acceptor) proposalBlock(b block.Block, blockType string) {
 	// Note that:
 	//
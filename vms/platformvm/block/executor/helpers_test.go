@@ -209,6 +209,7 @@ func newEnvironment(t *testing.T, ctrl *gomock.Controller, f fork) *environment
 			res.state,
 			res.backend,
 			pvalidators.TestManager,
+			false,
 		)
 		addSubnet(res)
 	} else {
@@ -218,6 +219,7 @@ func newEnvironment(t *testing.T, ctrl *gomock.Controller, f fork) *environment
 			res.mockedState,
 			res.backend,
 			pvalidators.TestManager,
+			false,
 		)
 		// we do not add any subnet to state, since we can mock
 		// whatever we need
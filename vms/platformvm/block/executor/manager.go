@@ -41,6 +41,12 @@ type Manager interface {
 	// preferred state. This should *not* be used to verify transactions in a block.
 	VerifyTx(tx *txs.Tx) error
 
+	// SimulateTx executes the transaction on top of the currently preferred
+	// state and returns its outcome. Neither the mempool nor the state is
+	// modified. If the transaction would fail verification, the verification
+	// error is returned.
+	SimulateTx(tx *txs.Tx) (*SimulatedTx, error)
+
 	// VerifyUniqueInputs verifies that the inputs are not duplicated in the
 	// provided blk or any of its ancestors pinned in memory.
 	VerifyUniqueInputs(blkID ids.ID, inputs set.Set[ids.ID]) error
@@ -52,6 +58,7 @@ func NewManager(
 	s state.State,
 	txExecutorBackend *executor.Backend,
 	validatorManager validators.Manager,
+	indexRewardHistory bool,
 ) Manager {
 	lastAccepted := s.GetLastAccepted()
 	backend := &backend{
@@ -69,6 +76,8 @@ func NewManager(
 			metrics:      metrics,
 			validators:   validatorManager,
 			bootstrapped: txExecutorBackend.Bootstrapped,
+
+			indexRewardHistory: indexRewardHistory,
 		},
 		rejector: &rejector{
 			backend:         backend,
@@ -118,32 +127,93 @@ func (m *manager) Preferred() ids.ID {
 }
 
 func (m *manager) VerifyTx(tx *txs.Tx) error {
+	stateDiff, err := m.newPreferredDiff()
+	if err != nil {
+		return err
+	}
+
+	feeCalculator := state.PickFeeCalculator(m.txExecutorBackend.Config, stateDiff)
+	return tx.Unsigned.Visit(&executor.StandardTxExecutor{
+		Backend:       m.txExecutorBackend,
+		State:         stateDiff,
+		FeeCalculator: feeCalculator,
+		Tx:            tx,
+	})
+}
+
+func (m *manager) SimulateTx(tx *txs.Tx) (*SimulatedTx, error) {
+	onCommitState, err := m.newPreferredDiff()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		simulatedTx   = &SimulatedTx{}
+		simulatedDiff = &simulationDiff{
+			Diff:        onCommitState,
+			simulatedTx: simulatedTx,
+		}
+		feeCalculator = state.PickFeeCalculator(m.txExecutorBackend.Config, onCommitState)
+	)
+	switch tx.Unsigned.(type) {
+	case *txs.AdvanceTimeTx, *txs.RewardValidatorTx:
+		// Proposal txs don't pay fees. The outcome is reported as if the
+		// proposal was committed.
+		onAbortState, err := m.newPreferredDiff()
+		if err != nil {
+			return nil, err
+		}
+		err = tx.Unsigned.Visit(&executor.ProposalTxExecutor{
+			Backend:       m.txExecutorBackend,
+			FeeCalculator: feeCalculator,
+			Tx:            tx,
+			OnCommitState: simulatedDiff,
+			OnAbortState:  onAbortState,
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		txExecutor := &executor.StandardTxExecutor{
+			Backend:       m.txExecutorBackend,
+			State:         simulatedDiff,
+			FeeCalculator: feeCalculator,
+			Tx:            tx,
+		}
+		if err := tx.Unsigned.Visit(txExecutor); err != nil {
+			return nil, err
+		}
+
+		simulatedTx.Fee, err = feeCalculator.CalculateFee(tx.Unsigned)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.addAtomicRequests(simulatedTx, txExecutor.AtomicRequests); err != nil {
+			return nil, err
+		}
+	}
+	return simulatedTx, nil
+}
+
+// newPreferredDiff returns a diff on top of the currently preferred state with
+// the chain time advanced to the time of the next block.
+func (m *manager) newPreferredDiff() (state.Diff, error) {
 	if !m.txExecutorBackend.Bootstrapped.Get() {
-		return ErrChainNotSynced
+		return nil, ErrChainNotSynced
 	}
 
 	stateDiff, err := state.NewDiff(m.preferred, m)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	nextBlkTime, _, err := state.NextBlockTime(stateDiff, m.txExecutorBackend.Clk)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	_, err = executor.AdvanceTimeTo(m.txExecutorBackend, stateDiff, nextBlkTime)
-	if err != nil {
-		return err
-	}
-
-	feeCalculator := state.PickFeeCalculator(m.txExecutorBackend.Config, stateDiff)
-	return tx.Unsigned.Visit(&executor.StandardTxExecutor{
-		Backend:       m.txExecutorBackend,
-		State:         stateDiff,
-		FeeCalculator: feeCalculator,
-		Tx:            tx,
-	})
+	return stateDiff, err
 }
 
 func (m *manager) VerifyUniqueInputs(blkID ids.ID, inputs set.Set[ids.ID]) error {
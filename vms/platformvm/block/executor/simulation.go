@@ -0,0 +1,176 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+var _ state.Diff = (*simulationDiff)(nil)
+
+// SimulatedTx is the outcome of executing a transaction on top of the
+// preferred state.
+type SimulatedTx struct {
+	// Fee paid by the transaction
+	Fee uint64
+	// UTXOs consumed by the transaction, including the UTXOs imported from
+	// other chains
+	ConsumedUTXOs []*avax.UTXO
+	// UTXOs produced by the transaction, including the UTXOs exported to other
+	// chains
+	ProducedUTXOs []*avax.UTXO
+	// Stakers added to the current or pending staker sets
+	AddedStakers []*state.Staker
+	// Stakers removed from the current or pending staker sets
+	RemovedStakers []*state.Staker
+	// Subnet-only validators added to the validator set
+	AddedSubnetOnlyValidators []SimulatedSubnetOnlyValidator
+	// Subnet-only validators removed from the validator set
+	RemovedSubnetOnlyValidators []SimulatedSubnetOnlyValidator
+	// Subnet-only validators whose weight or balance was modified
+	ModifiedSubnetOnlyValidators []SimulatedSubnetOnlyValidator
+}
+
+// SimulatedSubnetOnlyValidator is a subnet-only validator written by a
+// simulated transaction.
+type SimulatedSubnetOnlyValidator struct {
+	state.SubnetOnlyValidator
+
+	// PreviousWeight is the weight of the validator before the transaction
+	// was executed. It is 0 if the validator was added by the transaction.
+	PreviousWeight uint64
+	// PreviousEndAccumulatedFee is the EndAccumulatedFee of the validator
+	// before the transaction was executed. It is 0 if the validator was added
+	// by the transaction.
+	PreviousEndAccumulatedFee uint64
+}
+
+// simulationDiff records the UTXOs, stakers, and subnet-only validators
+// modified by the execution of a transaction.
+type simulationDiff struct {
+	state.Diff
+
+	simulatedTx *SimulatedTx
+}
+
+func (d *simulationDiff) PutCurrentValidator(staker *state.Staker) {
+	d.simulatedTx.AddedStakers = append(d.simulatedTx.AddedStakers, staker)
+	d.Diff.PutCurrentValidator(staker)
+}
+
+func (d *simulationDiff) DeleteCurrentValidator(staker *state.Staker) {
+	d.simulatedTx.RemovedStakers = append(d.simulatedTx.RemovedStakers, staker)
+	d.Diff.DeleteCurrentValidator(staker)
+}
+
+func (d *simulationDiff) PutCurrentDelegator(staker *state.Staker) {
+	d.simulatedTx.AddedStakers = append(d.simulatedTx.AddedStakers, staker)
+	d.Diff.PutCurrentDelegator(staker)
+}
+
+func (d *simulationDiff) DeleteCurrentDelegator(staker *state.Staker) {
+	d.simulatedTx.RemovedStakers = append(d.simulatedTx.RemovedStakers, staker)
+	d.Diff.DeleteCurrentDelegator(staker)
+}
+
+func (d *simulationDiff) PutPendingValidator(staker *state.Staker) {
+	d.simulatedTx.AddedStakers = append(d.simulatedTx.AddedStakers, staker)
+	d.Diff.PutPendingValidator(staker)
+}
+
+func (d *simulationDiff) DeletePendingValidator(staker *state.Staker) {
+	d.simulatedTx.RemovedStakers = append(d.simulatedTx.RemovedStakers, staker)
+	d.Diff.DeletePendingValidator(staker)
+}
+
+func (d *simulationDiff) PutPendingDelegator(staker *state.Staker) {
+	d.simulatedTx.AddedStakers = append(d.simulatedTx.AddedStakers, staker)
+	d.Diff.PutPendingDelegator(staker)
+}
+
+func (d *simulationDiff) DeletePendingDelegator(staker *state.Staker) {
+	d.simulatedTx.RemovedStakers = append(d.simulatedTx.RemovedStakers, staker)
+	d.Diff.DeletePendingDelegator(staker)
+}
+
+func (d *simulationDiff) AddUTXO(utxo *avax.UTXO) {
+	d.simulatedTx.ProducedUTXOs = append(d.simulatedTx.ProducedUTXOs, utxo)
+	d.Diff.AddUTXO(utxo)
+}
+
+func (d *simulationDiff) DeleteUTXO(utxoID ids.ID) {
+	// Executors only delete UTXOs that they have verified exist.
+	if utxo, err := d.Diff.GetUTXO(utxoID); err == nil {
+		d.simulatedTx.ConsumedUTXOs = append(d.simulatedTx.ConsumedUTXOs, utxo)
+	}
+	d.Diff.DeleteUTXO(utxoID)
+}
+
+func (d *simulationDiff) PutSubnetOnlyValidator(sov state.SubnetOnlyValidator) error {
+	prevSOV, err := d.Diff.GetSubnetOnlyValidator(sov.ValidationID)
+	isNew := errors.Is(err, database.ErrNotFound)
+	if err != nil && !isNew {
+		return err
+	}
+	if err := d.Diff.PutSubnetOnlyValidator(sov); err != nil {
+		return err
+	}
+
+	simulatedSOV := SimulatedSubnetOnlyValidator{
+		SubnetOnlyValidator:       sov,
+		PreviousWeight:            prevSOV.Weight,
+		PreviousEndAccumulatedFee: prevSOV.EndAccumulatedFee,
+	}
+	switch {
+	case isNew:
+		d.simulatedTx.AddedSubnetOnlyValidators = append(d.simulatedTx.AddedSubnetOnlyValidators, simulatedSOV)
+	case sov.IsRemoved():
+		d.simulatedTx.RemovedSubnetOnlyValidators = append(d.simulatedTx.RemovedSubnetOnlyValidators, simulatedSOV)
+	default:
+		d.simulatedTx.ModifiedSubnetOnlyValidators = append(d.simulatedTx.ModifiedSubnetOnlyValidators, simulatedSOV)
+	}
+	return nil
+}
+
+// addAtomicRequests records the UTXOs imported from and exported to other
+// chains by [requests].
+func (m *manager) addAtomicRequests(
+	simulatedTx *SimulatedTx,
+	requests map[ids.ID]*atomic.Requests,
+) error {
+	for chainID, chainRequests := range requests {
+		// The shared memory isn't guaranteed to be up-to-date if the other
+		// primary network chains aren't synced.
+		if len(chainRequests.RemoveRequests) > 0 && !m.txExecutorBackend.Config.PartialSyncPrimaryNetwork {
+			utxosBytes, err := m.txExecutorBackend.Ctx.SharedMemory.Get(chainID, chainRequests.RemoveRequests)
+			if err != nil {
+				return fmt.Errorf("failed to get shared memory: %w", err)
+			}
+			for _, utxoBytes := range utxosBytes {
+				utxo := &avax.UTXO{}
+				if _, err := txs.Codec.Unmarshal(utxoBytes, utxo); err != nil {
+					return fmt.Errorf("failed to unmarshal UTXO: %w", err)
+				}
+				simulatedTx.ConsumedUTXOs = append(simulatedTx.ConsumedUTXOs, utxo)
+			}
+		}
+
+		for _, element := range chainRequests.PutRequests {
+			utxo := &avax.UTXO{}
+			if _, err := txs.Codec.Unmarshal(element.Value, utxo); err != nil {
+				return fmt.Errorf("failed to unmarshal UTXO: %w", err)
+			}
+			simulatedTx.ProducedUTXOs = append(simulatedTx.ProducedUTXOs, utxo)
+		}
+	}
+	return nil
+}
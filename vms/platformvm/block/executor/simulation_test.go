@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+)
+
+var errTestPut = errors.New("test put error")
+
+func TestSimulationDiffPutSubnetOnlyValidator(t *testing.T) {
+	sov := state.SubnetOnlyValidator{
+		ValidationID:      ids.GenerateTestID(),
+		SubnetID:          ids.GenerateTestID(),
+		NodeID:            ids.GenerateTestNodeID(),
+		Weight:            10,
+		EndAccumulatedFee: 100,
+	}
+	withWeight := func(weight uint64) state.SubnetOnlyValidator {
+		sov := sov
+		sov.Weight = weight
+		return sov
+	}
+	withEndAccumulatedFee := func(endAccumulatedFee uint64) state.SubnetOnlyValidator {
+		sov := sov
+		sov.EndAccumulatedFee = endAccumulatedFee
+		return sov
+	}
+
+	tests := []struct {
+		name        string
+		prevSOV     state.SubnetOnlyValidator
+		getErr      error
+		putErr      error
+		sov         state.SubnetOnlyValidator
+		expectedErr error
+		expectedTx  SimulatedTx
+		expectPut   bool
+	}{
+		{
+			name:      "added",
+			getErr:    database.ErrNotFound,
+			sov:       sov,
+			expectPut: true,
+			expectedTx: SimulatedTx{
+				AddedSubnetOnlyValidators: []SimulatedSubnetOnlyValidator{
+					{
+						SubnetOnlyValidator: sov,
+					},
+				},
+			},
+		},
+		{
+			name:      "removed",
+			prevSOV:   sov,
+			sov:       withWeight(0),
+			expectPut: true,
+			expectedTx: SimulatedTx{
+				RemovedSubnetOnlyValidators: []SimulatedSubnetOnlyValidator{
+					{
+						SubnetOnlyValidator:       withWeight(0),
+						PreviousWeight:            sov.Weight,
+						PreviousEndAccumulatedFee: sov.EndAccumulatedFee,
+					},
+				},
+			},
+		},
+		{
+			name:      "weight modified",
+			prevSOV:   sov,
+			sov:       withWeight(20),
+			expectPut: true,
+			expectedTx: SimulatedTx{
+				ModifiedSubnetOnlyValidators: []SimulatedSubnetOnlyValidator{
+					{
+						SubnetOnlyValidator:       withWeight(20),
+						PreviousWeight:            sov.Weight,
+						PreviousEndAccumulatedFee: sov.EndAccumulatedFee,
+					},
+				},
+			},
+		},
+		{
+			name:      "balance increased",
+			prevSOV:   sov,
+			sov:       withEndAccumulatedFee(200),
+			expectPut: true,
+			expectedTx: SimulatedTx{
+				ModifiedSubnetOnlyValidators: []SimulatedSubnetOnlyValidator{
+					{
+						SubnetOnlyValidator:       withEndAccumulatedFee(200),
+						PreviousWeight:            sov.Weight,
+						PreviousEndAccumulatedFee: sov.EndAccumulatedFee,
+					},
+				},
+			},
+		},
+		{
+			name:        "get fails",
+			getErr:      database.ErrClosed,
+			sov:         sov,
+			expectedErr: database.ErrClosed,
+		},
+		{
+			name:        "put fails",
+			getErr:      database.ErrNotFound,
+			putErr:      errTestPut,
+			sov:         sov,
+			expectPut:   true,
+			expectedErr: errTestPut,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			ctrl := gomock.NewController(t)
+
+			onCommitState := state.NewMockDiff(ctrl)
+			onCommitState.EXPECT().GetSubnetOnlyValidator(sov.ValidationID).Return(test.prevSOV, test.getErr)
+			if test.expectPut {
+				onCommitState.EXPECT().PutSubnetOnlyValidator(test.sov).Return(test.putErr)
+			}
+
+			simulatedTx := &SimulatedTx{}
+			d := &simulationDiff{
+				Diff:        onCommitState,
+				simulatedTx: simulatedTx,
+			}
+			err := d.PutSubnetOnlyValidator(test.sov)
+			require.ErrorIs(err, test.expectedErr)
+			require.Equal(test.expectedTx, *simulatedTx)
+		})
+	}
+}
@@ -11,12 +11,14 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
 var _ Client = (*client)(nil)
@@ -59,6 +61,9 @@ type Client interface {
 	) ([][]byte, ids.ShortID, ids.ID, error)
 	// GetSubnet returns information about the specified subnet
 	GetSubnet(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (GetSubnetClientResponse, error)
+	// GetSubnetOnlyValidator returns the subnet-only validator with the
+	// provided [validationID]
+	GetSubnetOnlyValidator(ctx context.Context, validationID ids.ID, options ...rpc.Option) (ClientSubnetOnlyValidator, error)
 	// GetSubnets returns information about the specified subnets
 	//
 	// Deprecated: Subnets should be fetched from a dedicated indexer.
@@ -68,8 +73,25 @@ type Client interface {
 	GetStakingAssetID(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (ids.ID, error)
 	// GetCurrentValidators returns the list of current validators for subnet with ID [subnetID]
 	GetCurrentValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.NodeID, options ...rpc.Option) ([]ClientPermissionlessValidator, error)
+	// GetObservedUptimes returns the uptimes of the current validators of
+	// subnet [subnetID] as observed by the node
+	GetObservedUptimes(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (*GetObservedUptimesReply, error)
 	// GetCurrentSupply returns an upper bound on the supply of AVAX in the system along with the P-chain height
 	GetCurrentSupply(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (uint64, uint64, error)
+	// EstimateReward returns the reward that would be minted for staking
+	// [stakeAmount] for [duration] on subnet [subnetID] at the current supply.
+	// If [nodeID] is not empty, the stake is treated as a delegation to
+	// [nodeID] and the validator's delegation fee is used instead of
+	// [delegationFee].
+	EstimateReward(
+		ctx context.Context,
+		subnetID ids.ID,
+		stakeAmount uint64,
+		duration time.Duration,
+		delegationFee float32,
+		nodeID ids.NodeID,
+		options ...rpc.Option,
+	) (*EstimateRewardReply, error)
 	// SampleValidators returns the nodeIDs of a sample of [sampleSize] validators from the current validator set for subnet with ID [subnetID]
 	SampleValidators(ctx context.Context, subnetID ids.ID, sampleSize uint16, options ...rpc.Option) ([]ids.NodeID, error)
 	// GetBlockchainStatus returns the current status of blockchain with ID: [blockchainID]
@@ -84,6 +106,9 @@ type Client interface {
 	GetBlockchains(ctx context.Context, options ...rpc.Option) ([]APIBlockchain, error)
 	// IssueTx issues the transaction and returns its txID
 	IssueTx(ctx context.Context, tx []byte, options ...rpc.Option) (ids.ID, error)
+	// SimulateTx executes the transaction on top of the preferred state
+	// without issuing it and returns its outcome
+	SimulateTx(ctx context.Context, tx []byte, options ...rpc.Option) (*SimulateTxReply, error)
 	// GetTx returns the byte representation of the transaction corresponding to [txID]
 	GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error)
 	// GetTxStatus returns the status of the transaction corresponding to [txID]
@@ -98,6 +123,9 @@ type Client interface {
 		validatorsOnly bool,
 		options ...rpc.Option,
 	) (map[ids.ID]uint64, [][]byte, error)
+	// GetUnlockSchedule returns when the currently locked funds of [addrs]
+	// become spendable
+	GetUnlockSchedule(ctx context.Context, addrs []ids.ShortID, options ...rpc.Option) (*GetUnlockScheduleReply, error)
 	// GetMinStake returns the minimum staking amount in nAVAX for validators
 	// and delegators respectively
 	GetMinStake(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (uint64, uint64, error)
@@ -107,6 +135,9 @@ type Client interface {
 	//
 	// Deprecated: GetRewardUTXOs should be fetched from a dedicated indexer.
 	GetRewardUTXOs(context.Context, *api.GetTxArgs, ...rpc.Option) ([][]byte, error)
+	// GetRewardHistory returns the staking periods of a node ID, or the
+	// staking periods that rewarded an address, after the provided index.
+	GetRewardHistory(ctx context.Context, args *GetRewardHistoryArgs, options ...rpc.Option) (*GetRewardHistoryReply, error)
 	// GetTimestamp returns the current chain timestamp
 	GetTimestamp(ctx context.Context, options ...rpc.Option) (time.Time, error)
 	// GetValidatorsAt returns the weights of the validator set of a provided
@@ -117,6 +148,19 @@ type Client interface {
 		height uint64,
 		options ...rpc.Option,
 	) (map[ids.NodeID]*validators.GetValidatorOutput, error)
+	// GetValidatorSetChanges returns the changes between the validator sets of
+	// a provided subnet at [startHeight] and [endHeight]. At most [limit]
+	// changed validators with a node ID greater than [startNodeID] are
+	// returned.
+	GetValidatorSetChanges(
+		ctx context.Context,
+		subnetID ids.ID,
+		startHeight uint64,
+		endHeight uint64,
+		startNodeID ids.NodeID,
+		limit uint32,
+		options ...rpc.Option,
+	) (*GetValidatorSetChangesReply, error)
 	// GetBlock returns the block with the given id.
 	GetBlock(ctx context.Context, blockID ids.ID, options ...rpc.Option) ([]byte, error)
 	// GetBlockByHeight returns the block at the given [height].
@@ -228,6 +272,9 @@ type GetSubnetClientResponse struct {
 	Locktime    uint64
 	// subnet transformation tx ID for a permissionless subnet
 	SubnetTransformationTxID ids.ID
+	// validator manager information for a converted subnet
+	ManagerChainID ids.ID
+	ManagerAddress []byte
 }
 
 func (c *client) GetSubnet(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (GetSubnetClientResponse, error) {
@@ -249,6 +296,62 @@ func (c *client) GetSubnet(ctx context.Context, subnetID ids.ID, options ...rpc.
 		Threshold:                uint32(res.Threshold),
 		Locktime:                 uint64(res.Locktime),
 		SubnetTransformationTxID: res.SubnetTransformationTxID,
+		ManagerChainID:           res.ManagerChainID,
+		ManagerAddress:           res.ManagerAddress,
+	}, nil
+}
+
+// ClientSubnetOnlyValidator is the response from calling
+// GetSubnetOnlyValidator on the client
+type ClientSubnetOnlyValidator struct {
+	SubnetID              ids.ID
+	NodeID                ids.NodeID
+	PublicKey             *bls.PublicKey
+	RemainingBalanceOwner *secp256k1fx.OutputOwners
+	StartTime             uint64
+	Weight                uint64
+	MinNonce              uint64
+	// Balance is the remaining balance the validator can use to pay for its
+	// continuous fee
+	Balance  uint64
+	IsActive bool
+}
+
+func (c *client) GetSubnetOnlyValidator(
+	ctx context.Context,
+	validationID ids.ID,
+	options ...rpc.Option,
+) (ClientSubnetOnlyValidator, error) {
+	res := &GetSubnetOnlyValidatorReply{}
+	err := c.requester.SendRequest(ctx, "platform.getSubnetOnlyValidator", &GetSubnetOnlyValidatorArgs{
+		ValidationID: validationID,
+	}, res, options...)
+	if err != nil {
+		return ClientSubnetOnlyValidator{}, err
+	}
+
+	pk, err := bls.PublicKeyFromCompressedBytes(res.PublicKey)
+	if err != nil {
+		return ClientSubnetOnlyValidator{}, err
+	}
+	remainingBalanceOwnerAddrs, err := address.ParseToIDs(res.RemainingBalanceOwner.Addresses)
+	if err != nil {
+		return ClientSubnetOnlyValidator{}, err
+	}
+
+	return ClientSubnetOnlyValidator{
+		SubnetID:  res.SubnetID,
+		NodeID:    res.NodeID,
+		PublicKey: pk,
+		RemainingBalanceOwner: &secp256k1fx.OutputOwners{
+			Threshold: uint32(res.RemainingBalanceOwner.Threshold),
+			Addrs:     remainingBalanceOwnerAddrs,
+		},
+		StartTime: uint64(res.StartTime),
+		Weight:    uint64(res.Weight),
+		MinNonce:  uint64(res.MinNonce),
+		Balance:   uint64(res.Balance),
+		IsActive:  res.IsActive,
 	}, nil
 }
 
@@ -312,6 +415,14 @@ func (c *client) GetCurrentValidators(
 	return getClientPermissionlessValidators(res.Validators)
 }
 
+func (c *client) GetObservedUptimes(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (*GetObservedUptimesReply, error) {
+	res := &GetObservedUptimesReply{}
+	err := c.requester.SendRequest(ctx, "platform.getObservedUptimes", &GetObservedUptimesArgs{
+		SubnetID: subnetID,
+	}, res, options...)
+	return res, err
+}
+
 func (c *client) GetCurrentSupply(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (uint64, uint64, error) {
 	res := &GetCurrentSupplyReply{}
 	err := c.requester.SendRequest(ctx, "platform.getCurrentSupply", &GetCurrentSupplyArgs{
@@ -320,6 +431,26 @@ func (c *client) GetCurrentSupply(ctx context.Context, subnetID ids.ID, options
 	return uint64(res.Supply), uint64(res.Height), err
 }
 
+func (c *client) EstimateReward(
+	ctx context.Context,
+	subnetID ids.ID,
+	stakeAmount uint64,
+	duration time.Duration,
+	delegationFee float32,
+	nodeID ids.NodeID,
+	options ...rpc.Option,
+) (*EstimateRewardReply, error) {
+	res := &EstimateRewardReply{}
+	err := c.requester.SendRequest(ctx, "platform.estimateReward", &EstimateRewardArgs{
+		SubnetID:      subnetID,
+		StakeAmount:   json.Uint64(stakeAmount),
+		Duration:      json.Uint64(duration / time.Second),
+		DelegationFee: json.Float32(delegationFee),
+		NodeID:        nodeID,
+	}, res, options...)
+	return res, err
+}
+
 func (c *client) SampleValidators(ctx context.Context, subnetID ids.ID, sampleSize uint16, options ...rpc.Option) ([]ids.NodeID, error) {
 	res := &SampleValidatorsReply{}
 	err := c.requester.SendRequest(ctx, "platform.sampleValidators", &SampleValidatorsArgs{
@@ -373,6 +504,20 @@ func (c *client) IssueTx(ctx context.Context, txBytes []byte, options ...rpc.Opt
 	return res.TxID, err
 }
 
+func (c *client) SimulateTx(ctx context.Context, txBytes []byte, options ...rpc.Option) (*SimulateTxReply, error) {
+	txStr, err := formatting.Encode(formatting.Hex, txBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SimulateTxReply{}
+	err = c.requester.SendRequest(ctx, "platform.simulateTx", &api.FormattedTx{
+		Tx:       txStr,
+		Encoding: formatting.Hex,
+	}, res, options...)
+	return res, err
+}
+
 func (c *client) GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error) {
 	res := &api.FormattedTx{}
 	err := c.requester.SendRequest(ctx, "platform.getTx", &api.GetTxArgs{
@@ -433,6 +578,14 @@ func (c *client) GetStake(
 	return staked, outputs, err
 }
 
+func (c *client) GetUnlockSchedule(ctx context.Context, addrs []ids.ShortID, options ...rpc.Option) (*GetUnlockScheduleReply, error) {
+	res := &GetUnlockScheduleReply{}
+	err := c.requester.SendRequest(ctx, "platform.getUnlockSchedule", &api.JSONAddresses{
+		Addresses: ids.ShortIDsToStrings(addrs),
+	}, res, options...)
+	return res, err
+}
+
 func (c *client) GetMinStake(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (uint64, uint64, error) {
 	res := &GetMinStakeReply{}
 	err := c.requester.SendRequest(ctx, "platform.getMinStake", &GetMinStakeArgs{
@@ -472,6 +625,12 @@ func (c *client) GetRewardUTXOs(ctx context.Context, args *api.GetTxArgs, option
 	return utxos, err
 }
 
+func (c *client) GetRewardHistory(ctx context.Context, args *GetRewardHistoryArgs, options ...rpc.Option) (*GetRewardHistoryReply, error) {
+	res := &GetRewardHistoryReply{}
+	err := c.requester.SendRequest(ctx, "platform.getRewardHistory", args, res, options...)
+	return res, err
+}
+
 func (c *client) GetTimestamp(ctx context.Context, options ...rpc.Option) (time.Time, error) {
 	res := &GetTimestampReply{}
 	err := c.requester.SendRequest(ctx, "platform.getTimestamp", struct{}{}, res, options...)
@@ -492,6 +651,26 @@ func (c *client) GetValidatorsAt(
 	return res.Validators, err
 }
 
+func (c *client) GetValidatorSetChanges(
+	ctx context.Context,
+	subnetID ids.ID,
+	startHeight uint64,
+	endHeight uint64,
+	startNodeID ids.NodeID,
+	limit uint32,
+	options ...rpc.Option,
+) (*GetValidatorSetChangesReply, error) {
+	res := &GetValidatorSetChangesReply{}
+	err := c.requester.SendRequest(ctx, "platform.getValidatorSetChanges", &GetValidatorSetChangesArgs{
+		SubnetID:    subnetID,
+		StartHeight: json.Uint64(startHeight),
+		EndHeight:   json.Uint64(endHeight),
+		StartNodeID: startNodeID,
+		Limit:       json.Uint32(limit),
+	}, res, options...)
+	return res, err
+}
+
 func (c *client) GetBlock(ctx context.Context, blockID ids.ID, options ...rpc.Option) ([]byte, error) {
 	res := &api.FormattedBlock{}
 	if err := c.requester.SendRequest(ctx, "platform.getBlock", &api.GetBlockArgs{
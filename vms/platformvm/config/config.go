@@ -40,6 +40,10 @@ type Config struct {
 	// Dynamic fees are active after the E-upgrade
 	DynamicFeeConfig feecomponent.Config
 
+	// Fee, in nAVAX per second, that each active subnet-only validator pays
+	// after the E-upgrade
+	ValidatorFeeRate uint64
+
 	// Provides access to the uptime manager as a thread safe data structure
 	UptimeLockedCalculator uptime.LockedCalculator
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/platformvm/block/builder/policy"
 	"github.com/ava-labs/avalanchego/vms/platformvm/network"
 )
 
@@ -23,6 +24,8 @@ var DefaultExecutionConfig = ExecutionConfig{
 	FxOwnerCacheSize:             4 * units.MiB,
 	ChecksumsEnabled:             false,
 	MempoolPruneFrequency:        30 * time.Minute,
+	IndexRewardHistory:           false,
+	BlockBuilderPolicy:           policy.DefaultConfig,
 }
 
 // ExecutionConfig provides execution parameters of PlatformVM
@@ -38,6 +41,8 @@ type ExecutionConfig struct {
 	FxOwnerCacheSize             int            `json:"fx-owner-cache-size"`
 	ChecksumsEnabled             bool           `json:"checksums-enabled"`
 	MempoolPruneFrequency        time.Duration  `json:"mempool-prune-frequency"`
+	IndexRewardHistory           bool           `json:"index-reward-history"`
+	BlockBuilderPolicy           policy.Config  `json:"block-builder-policy"`
 }
 
 // GetExecutionConfig returns an ExecutionConfig
@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ava-labs/avalanchego/vms/platformvm/block/builder/policy"
 	"github.com/ava-labs/avalanchego/vms/platformvm/network"
 )
 
@@ -91,9 +92,20 @@ func TestExecutionConfigUnmarshal(t *testing.T) {
 			FxOwnerCacheSize:             9,
 			ChecksumsEnabled:             true,
 			MempoolPruneFrequency:        time.Minute,
+			IndexRewardHistory:           true,
+			BlockBuilderPolicy: policy.Config{
+				Name: policy.FeePriority,
+				Reservations: []policy.Reservation{
+					{
+						Category: "validator",
+						Percent:  10,
+					},
+				},
+			},
 		}
 		verifyInitializedStruct(t, *expected)
 		verifyInitializedStruct(t, expected.Network)
+		verifyInitializedStruct(t, expected.BlockBuilderPolicy)
 
 		b, err := json.Marshal(expected)
 		require.NoError(err)
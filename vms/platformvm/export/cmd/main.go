@@ -0,0 +1,158 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/avalanchego/api/admin"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/vms/platformvm/export"
+)
+
+const (
+	URIKey       = "uri"
+	FormatKey    = "format"
+	HeightKey    = "height"
+	DirKey       = "dir"
+	NetworkIDKey = "network-id"
+	NodeIDsKey   = "node-ids"
+	OutputKey    = "output"
+
+	defaultURI = "http://127.0.0.1:9650"
+)
+
+func main() {
+	cmd := &cobra.Command{
+		Use:   "pchain-export",
+		Short: "Exports the state of the P-chain and turns exports into local network genesis configs",
+	}
+	cmd.AddCommand(
+		stateCommand(),
+		genesisCommand(),
+	)
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "command failed %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func stateCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "state",
+		Short: "Exports the state of the P-chain of a running node through its admin API",
+		RunE:  stateFunc,
+	}
+	flags := c.Flags()
+	flags.String(URIKey, defaultURI, "URI of the node's API")
+	flags.String(FormatKey, string(export.JSON), fmt.Sprintf("Format of the exported files. Available values: %s or %s", export.JSON, export.CSV))
+	flags.Uint64(HeightKey, 0, "Height of the exported state. Only the validator sets are exported at heights prior to the last accepted height. If not provided, the last accepted height is used")
+	return c
+}
+
+func stateFunc(c *cobra.Command, _ []string) error {
+	flags := c.Flags()
+	uri, err := flags.GetString(URIKey)
+	if err != nil {
+		return err
+	}
+	format, err := flags.GetString(FormatKey)
+	if err != nil {
+		return err
+	}
+
+	var height *uint64
+	if flags.Changed(HeightKey) {
+		h, err := flags.GetUint64(HeightKey)
+		if err != nil {
+			return err
+		}
+		height = &h
+	}
+
+	reply, err := admin.NewClient(uri).ExportPChainState(c.Context(), format, height)
+	if err != nil {
+		return err
+	}
+	return printJSON(reply)
+}
+
+func genesisCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "genesis",
+		Short: "Converts a JSON export into the genesis config of a local network",
+		RunE:  genesisFunc,
+	}
+	flags := c.Flags()
+	flags.String(DirKey, "", "Directory of the export")
+	flags.Uint32(NetworkIDKey, constants.LocalID, "Network ID of the genesis")
+	flags.StringSlice(NodeIDsKey, nil, "Node IDs replacing the heaviest validators of the export. The weights of the kept validators are scaled to preserve the total stake. If empty, all the validators are kept")
+	flags.String(OutputKey, "", "File to write the genesis config to. If empty, the genesis config is printed to stdout")
+	return c
+}
+
+func genesisFunc(c *cobra.Command, _ []string) error {
+	flags := c.Flags()
+	dir, err := flags.GetString(DirKey)
+	if err != nil {
+		return err
+	}
+	networkID, err := flags.GetUint32(NetworkIDKey)
+	if err != nil {
+		return err
+	}
+	nodeIDStrs, err := flags.GetStringSlice(NodeIDsKey)
+	if err != nil {
+		return err
+	}
+	output, err := flags.GetString(OutputKey)
+	if err != nil {
+		return err
+	}
+
+	nodeIDs := make([]ids.NodeID, len(nodeIDStrs))
+	for i, nodeIDStr := range nodeIDStrs {
+		nodeIDs[i], err = ids.NodeIDFromString(nodeIDStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	config, err := export.GenesisConfig(dir, export.GenesisArgs{
+		NetworkID: networkID,
+		NodeIDs:   nodeIDs,
+	})
+	if err != nil {
+		return err
+	}
+	unparsedConfig, err := config.Unparse()
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		return printJSON(unparsedConfig)
+	}
+
+	configBytes, err := json.MarshalIndent(unparsedConfig, "", "  ")
+	if err != nil {
+		return err
+	}
+	return perms.WriteFile(output, configBytes, perms.ReadWrite)
+}
+
+func printJSON(v interface{}) error {
+	bytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(string(bytes))
+	return err
+}
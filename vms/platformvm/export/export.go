@@ -0,0 +1,670 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package export writes the state of the P-chain to files that can be
+// analyzed offline or turned into the genesis of a local network.
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/fx"
+	"github.com/ava-labs/avalanchego/vms/platformvm/stakeable"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+const (
+	JSON Format = "json"
+	CSV  Format = "csv"
+
+	MetadataFile = "metadata.json"
+	StakersFile  = "stakers"
+	SubnetsFile  = "subnets"
+	ChainsFile   = "chains"
+	UTXOsFile    = "utxos"
+
+	StakerStatusCurrent = "current"
+	StakerStatusPending = "pending"
+	StakerTypeValidator = "validator"
+	StakerTypeDelegator = "delegator"
+
+	// chainAlias is used to format the exported addresses
+	chainAlias = "P"
+	// listSeparator separates the elements of a list in a CSV cell
+	listSeparator = ";"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown export format")
+
+	errUnsupportedOwner  = errors.New("unsupported owner")
+	errUnsupportedOutput = errors.New("unsupported output")
+	errWrongTxType       = errors.New("wrong transaction type")
+)
+
+// Format of the exported files
+type Format string
+
+// ParseFormat returns the format named [s]. If [s] is empty, [JSON] is
+// returned.
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(strings.ToLower(s)); format {
+	case "", JSON:
+		return JSON, nil
+	case CSV:
+		return CSV, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+	}
+}
+
+// Config describes the state being exported.
+type Config struct {
+	NetworkID uint32
+	// Height and BlockID of the exported block. The exported state must be
+	// the state after this block was accepted.
+	Height  uint64
+	BlockID ids.ID
+	Format  Format
+	// Dir is the directory the files are written to. It is created if it
+	// doesn't exist.
+	Dir string
+}
+
+// Metadata describes an export. It is always written to [MetadataFile] as
+// JSON, regardless of the format of the other files.
+type Metadata struct {
+	NetworkID uint32 `json:"networkID"`
+	Height    uint64 `json:"height"`
+	BlockID   ids.ID `json:"blockID"`
+	// Timestamp of the chain, in unix seconds
+	Timestamp  uint64 `json:"timestamp"`
+	Format     Format `json:"format"`
+	NumStakers uint64 `json:"numStakers"`
+	NumSubnets uint64 `json:"numSubnets"`
+	NumChains  uint64 `json:"numChains"`
+	NumUTXOs   uint64 `json:"numUTXOs"`
+	// ValidatorsOnly is true if the export only contains the validator sets
+	// at its height. Only the validator sets are available at heights prior
+	// to the last accepted height, so the subnets, chains and UTXOs of such
+	// exports aren't written.
+	ValidatorsOnly bool `json:"validatorsOnly"`
+}
+
+// Staker is a current or pending validator or delegator of a subnet.
+type Staker struct {
+	TxID            ids.ID     `json:"txID"`
+	SubnetID        ids.ID     `json:"subnetID"`
+	NodeID          ids.NodeID `json:"nodeID"`
+	Status          string     `json:"status"`
+	Type            string     `json:"type"`
+	Weight          uint64     `json:"weight"`
+	StartTime       uint64     `json:"startTime"`
+	EndTime         uint64     `json:"endTime"`
+	PotentialReward uint64     `json:"potentialReward"`
+	// DelegationFee is only set for validators that accept delegators
+	DelegationFee uint32 `json:"delegationFee"`
+	// RewardThreshold and RewardAddresses are the owner of the validation
+	// rewards of validators and of the rewards of delegators. They are empty
+	// for permissioned subnet validators.
+	RewardThreshold uint32   `json:"rewardThreshold"`
+	RewardAddresses []string `json:"rewardAddresses"`
+	// PublicKey is the hex encoded BLS public key of the validator, if any
+	PublicKey string `json:"publicKey"`
+}
+
+func (Staker) csvHeader() []string {
+	return []string{
+		"txID",
+		"subnetID",
+		"nodeID",
+		"status",
+		"type",
+		"weight",
+		"startTime",
+		"endTime",
+		"potentialReward",
+		"delegationFee",
+		"rewardThreshold",
+		"rewardAddresses",
+		"publicKey",
+	}
+}
+
+func (s Staker) csvRow() []string {
+	return []string{
+		s.TxID.String(),
+		s.SubnetID.String(),
+		s.NodeID.String(),
+		s.Status,
+		s.Type,
+		strconv.FormatUint(s.Weight, 10),
+		strconv.FormatUint(s.StartTime, 10),
+		strconv.FormatUint(s.EndTime, 10),
+		strconv.FormatUint(s.PotentialReward, 10),
+		strconv.FormatUint(uint64(s.DelegationFee), 10),
+		strconv.FormatUint(uint64(s.RewardThreshold), 10),
+		strings.Join(s.RewardAddresses, listSeparator),
+		s.PublicKey,
+	}
+}
+
+// Subnet is the primary network or a subnet created on the P-chain.
+type Subnet struct {
+	SubnetID ids.ID `json:"subnetID"`
+	// Threshold, Locktime and Addresses are the owner of the subnet. They are
+	// empty for the primary network.
+	Threshold uint32   `json:"threshold"`
+	Locktime  uint64   `json:"locktime"`
+	Addresses []string `json:"addresses"`
+	// CurrentSupply is zero for subnets that weren't transformed into
+	// permissionless subnets.
+	CurrentSupply uint64 `json:"currentSupply"`
+}
+
+func (Subnet) csvHeader() []string {
+	return []string{
+		"subnetID",
+		"threshold",
+		"locktime",
+		"addresses",
+		"currentSupply",
+	}
+}
+
+func (s Subnet) csvRow() []string {
+	return []string{
+		s.SubnetID.String(),
+		strconv.FormatUint(uint64(s.Threshold), 10),
+		strconv.FormatUint(s.Locktime, 10),
+		strings.Join(s.Addresses, listSeparator),
+		strconv.FormatUint(s.CurrentSupply, 10),
+	}
+}
+
+// Chain is a blockchain created on the P-chain.
+type Chain struct {
+	ChainID  ids.ID   `json:"chainID"`
+	SubnetID ids.ID   `json:"subnetID"`
+	Name     string   `json:"name"`
+	VMID     ids.ID   `json:"vmID"`
+	FxIDs    []ids.ID `json:"fxIDs"`
+	// Genesis is the hex encoded genesis of the chain
+	Genesis string `json:"genesis"`
+}
+
+func (Chain) csvHeader() []string {
+	return []string{
+		"chainID",
+		"subnetID",
+		"name",
+		"vmID",
+		"fxIDs",
+		"genesis",
+	}
+}
+
+func (c Chain) csvRow() []string {
+	fxIDs := make([]string, len(c.FxIDs))
+	for i, fxID := range c.FxIDs {
+		fxIDs[i] = fxID.String()
+	}
+	return []string{
+		c.ChainID.String(),
+		c.SubnetID.String(),
+		c.Name,
+		c.VMID.String(),
+		strings.Join(fxIDs, listSeparator),
+		c.Genesis,
+	}
+}
+
+// UTXO is an unspent output of the P-chain.
+type UTXO struct {
+	TxID        ids.ID `json:"txID"`
+	OutputIndex uint32 `json:"outputIndex"`
+	AssetID     ids.ID `json:"assetID"`
+	Amount      uint64 `json:"amount"`
+	Locktime    uint64 `json:"locktime"`
+	// StakeableLocktime is zero if the output isn't stakeable locked
+	StakeableLocktime uint64   `json:"stakeableLocktime"`
+	Threshold         uint32   `json:"threshold"`
+	Addresses         []string `json:"addresses"`
+}
+
+func (UTXO) csvHeader() []string {
+	return []string{
+		"txID",
+		"outputIndex",
+		"assetID",
+		"amount",
+		"locktime",
+		"stakeableLocktime",
+		"threshold",
+		"addresses",
+	}
+}
+
+func (u UTXO) csvRow() []string {
+	return []string{
+		u.TxID.String(),
+		strconv.FormatUint(uint64(u.OutputIndex), 10),
+		u.AssetID.String(),
+		strconv.FormatUint(u.Amount, 10),
+		strconv.FormatUint(u.Locktime, 10),
+		strconv.FormatUint(u.StakeableLocktime, 10),
+		strconv.FormatUint(uint64(u.Threshold), 10),
+		strings.Join(u.Addresses, listSeparator),
+	}
+}
+
+// Export writes the stakers, subnets, chains and UTXOs of [s] to
+// [config.Dir], followed by the metadata of the export.
+//
+// Invariant: [s] must not be modified until Export returns.
+func Export(s state.State, config Config) (*Metadata, error) {
+	snapshot, err := NewSnapshot(s, config)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Write()
+}
+
+// Snapshot is the state to export, as of the block described by its config.
+type Snapshot struct {
+	config   Config
+	metadata Metadata
+	stakers  []Staker
+	subnets  []Subnet
+	chains   []Chain
+	// utxos reads the UTXOs from a snapshot of the database, because there
+	// are too many to be held in memory.
+	utxos avax.UTXOIterator
+	hrp   string
+}
+
+// NewValidatorSetsSnapshot returns a snapshot of [validatorSets], the
+// validator sets of each subnet at the height of [config], as of the provided
+// chain [timestamp]. The snapshot only contains the validators, which are
+// reported as current validators with their total weight, including the
+// weight of their delegators.
+func NewValidatorSetsSnapshot(
+	config Config,
+	timestamp uint64,
+	validatorSets map[ids.ID]map[ids.NodeID]*validators.GetValidatorOutput,
+) (*Snapshot, error) {
+	if _, err := ParseFormat(string(config.Format)); err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		config: config,
+		metadata: Metadata{
+			NetworkID:      config.NetworkID,
+			Height:         config.Height,
+			BlockID:        config.BlockID,
+			Timestamp:      timestamp,
+			Format:         config.Format,
+			ValidatorsOnly: true,
+		},
+		hrp: constants.GetHRP(config.NetworkID),
+	}
+	for subnetID, validatorSet := range validatorSets {
+		for _, validator := range validatorSet {
+			publicKey, err := formatPublicKey(validator.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.stakers = append(snapshot.stakers, Staker{
+				SubnetID:        subnetID,
+				NodeID:          validator.NodeID,
+				Status:          StakerStatusCurrent,
+				Type:            StakerTypeValidator,
+				Weight:          validator.Weight,
+				RewardAddresses: []string{},
+				PublicKey:       publicKey,
+			})
+		}
+	}
+	slices.SortFunc(snapshot.stakers, func(a, b Staker) int {
+		if subnetCmp := a.SubnetID.Compare(b.SubnetID); subnetCmp != 0 {
+			return subnetCmp
+		}
+		return a.NodeID.Compare(b.NodeID)
+	})
+	return snapshot, nil
+}
+
+// NewSnapshot reads the stakers, subnets and chains of [s] and the UTXOs that
+// are committed to [s]. Once NewSnapshot returns, [s] may be modified and the
+// snapshot can be written with Write.
+//
+// Invariant: [s] must not be modified until NewSnapshot returns and must not
+// have uncommitted UTXO modifications.
+func NewSnapshot(s state.State, config Config) (*Snapshot, error) {
+	if _, err := ParseFormat(string(config.Format)); err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		config: config,
+		metadata: Metadata{
+			NetworkID: config.NetworkID,
+			Height:    config.Height,
+			BlockID:   config.BlockID,
+			Timestamp: uint64(s.GetTimestamp().Unix()),
+			Format:    config.Format,
+		},
+		hrp: constants.GetHRP(config.NetworkID),
+	}
+
+	var err error
+	if snapshot.stakers, err = snapshot.readStakers(s); err != nil {
+		return nil, fmt.Errorf("failed to read stakers: %w", err)
+	}
+	if snapshot.subnets, snapshot.chains, err = snapshot.readSubnets(s); err != nil {
+		return nil, fmt.Errorf("failed to read subnets: %w", err)
+	}
+	snapshot.utxos = s.NewUTXOIterator()
+	return snapshot, nil
+}
+
+// Write writes the snapshot to its config's directory, followed by the
+// metadata of the export. The snapshot is released once Write returns.
+func (s *Snapshot) Write() (*Metadata, error) {
+	defer s.Release()
+
+	if err := os.MkdirAll(s.config.Dir, perms.ReadWriteExecute); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if s.metadata.NumStakers, err = writeRecords(s.config, StakersFile, s.stakers); err != nil {
+		return nil, fmt.Errorf("failed to export stakers: %w", err)
+	}
+	if s.metadata.ValidatorsOnly {
+		return s.writeMetadata()
+	}
+	if s.metadata.NumSubnets, err = writeRecords(s.config, SubnetsFile, s.subnets); err != nil {
+		return nil, fmt.Errorf("failed to export subnets: %w", err)
+	}
+	if s.metadata.NumChains, err = writeRecords(s.config, ChainsFile, s.chains); err != nil {
+		return nil, fmt.Errorf("failed to export chains: %w", err)
+	}
+	if s.metadata.NumUTXOs, err = s.writeUTXOs(); err != nil {
+		return nil, fmt.Errorf("failed to export UTXOs: %w", err)
+	}
+
+	return s.writeMetadata()
+}
+
+func (s *Snapshot) writeMetadata() (*Metadata, error) {
+	metadataBytes, err := json.MarshalIndent(s.metadata, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	metadata := s.metadata
+	return &metadata, perms.WriteFile(
+		filepath.Join(s.config.Dir, MetadataFile),
+		metadataBytes,
+		perms.ReadWrite,
+	)
+}
+
+// Release releases the snapshot without writing it. It is safe to call
+// Release multiple times.
+func (s *Snapshot) Release() {
+	if s.utxos != nil {
+		s.utxos.Release()
+		s.utxos = nil
+	}
+}
+
+func writeRecords[T record](config Config, name string, records []T) (uint64, error) {
+	w, err := newFileWriter[T](config.Dir, name, config.Format)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range records {
+		if err := w.Write(r); err != nil {
+			_ = w.Close()
+			return 0, err
+		}
+	}
+	return w.count, w.Close()
+}
+
+func (s *Snapshot) readStakers(st state.State) ([]Staker, error) {
+	currentIt, err := st.GetCurrentStakerIterator()
+	if err != nil {
+		return nil, err
+	}
+	stakers, err := s.appendStakers(nil, st, currentIt, StakerStatusCurrent)
+	currentIt.Release()
+	if err != nil {
+		return nil, err
+	}
+
+	pendingIt, err := st.GetPendingStakerIterator()
+	if err != nil {
+		return nil, err
+	}
+	stakers, err = s.appendStakers(stakers, st, pendingIt, StakerStatusPending)
+	pendingIt.Release()
+	return stakers, err
+}
+
+func (s *Snapshot) appendStakers(
+	stakers []Staker,
+	st state.State,
+	it state.StakerIterator,
+	status string,
+) ([]Staker, error) {
+	for it.Next() {
+		staker := it.Value()
+		record := Staker{
+			TxID:            staker.TxID,
+			SubnetID:        staker.SubnetID,
+			NodeID:          staker.NodeID,
+			Status:          status,
+			Type:            StakerTypeDelegator,
+			Weight:          staker.Weight,
+			StartTime:       uint64(staker.StartTime.Unix()),
+			EndTime:         uint64(staker.EndTime.Unix()),
+			PotentialReward: staker.PotentialReward,
+			RewardAddresses: []string{},
+		}
+		if staker.Priority.IsValidator() {
+			record.Type = StakerTypeValidator
+		}
+		publicKey, err := formatPublicKey(staker.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		record.PublicKey = publicKey
+
+		tx, _, err := st.GetTx(staker.TxID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get staker tx %s: %w", staker.TxID, err)
+		}
+
+		var rewardsOwner fx.Owner
+		switch utx := tx.Unsigned.(type) {
+		case txs.ValidatorTx:
+			record.DelegationFee = utx.Shares()
+			rewardsOwner = utx.ValidationRewardsOwner()
+		case txs.DelegatorTx:
+			rewardsOwner = utx.RewardsOwner()
+		}
+		if rewardsOwner != nil {
+			owner, err := toOutputOwners(rewardsOwner)
+			if err != nil {
+				return nil, err
+			}
+			record.RewardThreshold = owner.Threshold
+			record.RewardAddresses, err = s.formatAddresses(owner.Addrs)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		stakers = append(stakers, record)
+	}
+	return stakers, nil
+}
+
+// readSubnets reads the subnets and their chains.
+func (s *Snapshot) readSubnets(st state.State) ([]Subnet, []Chain, error) {
+	subnetIDs, err := st.GetSubnetIDs()
+	if err != nil {
+		return nil, nil, err
+	}
+	subnetIDs = append([]ids.ID{constants.PrimaryNetworkID}, subnetIDs...)
+
+	var (
+		subnets = make([]Subnet, 0, len(subnetIDs))
+		chains  []Chain
+	)
+	for _, subnetID := range subnetIDs {
+		record := Subnet{
+			SubnetID:  subnetID,
+			Addresses: []string{},
+		}
+		if subnetID != constants.PrimaryNetworkID {
+			subnetOwner, err := st.GetSubnetOwner(subnetID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get owner of subnet %s: %w", subnetID, err)
+			}
+			owner, err := toOutputOwners(subnetOwner)
+			if err != nil {
+				return nil, nil, err
+			}
+			record.Threshold = owner.Threshold
+			record.Locktime = owner.Locktime
+			record.Addresses, err = s.formatAddresses(owner.Addrs)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		// Only the primary network and transformed subnets have a supply.
+		if supply, err := st.GetCurrentSupply(subnetID); err == nil {
+			record.CurrentSupply = supply
+		}
+		subnets = append(subnets, record)
+
+		chainTxs, err := st.GetChains(subnetID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get chains of subnet %s: %w", subnetID, err)
+		}
+		for _, chainTx := range chainTxs {
+			utx, ok := chainTx.Unsigned.(*txs.CreateChainTx)
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: %T", errWrongTxType, chainTx.Unsigned)
+			}
+			genesis, err := formatting.Encode(formatting.HexNC, utx.GenesisData)
+			if err != nil {
+				return nil, nil, err
+			}
+			chains = append(chains, Chain{
+				ChainID:  chainTx.ID(),
+				SubnetID: utx.SubnetID,
+				Name:     utx.ChainName,
+				VMID:     utx.VMID,
+				FxIDs:    append([]ids.ID{}, utx.FxIDs...),
+				Genesis:  genesis,
+			})
+		}
+	}
+	return subnets, chains, nil
+}
+
+func (s *Snapshot) writeUTXOs() (uint64, error) {
+	w, err := newFileWriter[UTXO](s.config.Dir, UTXOsFile, s.config.Format)
+	if err != nil {
+		return 0, err
+	}
+
+	for s.utxos.Next() {
+		utxo := s.utxos.Value()
+		record := UTXO{
+			TxID:        utxo.TxID,
+			OutputIndex: utxo.OutputIndex,
+			AssetID:     utxo.AssetID(),
+		}
+
+		out := utxo.Out
+		if lockedOut, ok := out.(*stakeable.LockOut); ok {
+			record.StakeableLocktime = lockedOut.Locktime
+			out = lockedOut.TransferableOut
+		}
+		transferOut, ok := out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			_ = w.Close()
+			return 0, fmt.Errorf("%w: %T", errUnsupportedOutput, out)
+		}
+		record.Amount = transferOut.Amt
+		record.Locktime = transferOut.Locktime
+		record.Threshold = transferOut.Threshold
+		record.Addresses, err = s.formatAddresses(transferOut.Addrs)
+		if err != nil {
+			_ = w.Close()
+			return 0, err
+		}
+
+		if err := w.Write(record); err != nil {
+			_ = w.Close()
+			return 0, err
+		}
+	}
+	if err := s.utxos.Error(); err != nil {
+		_ = w.Close()
+		return 0, err
+	}
+	return w.count, w.Close()
+}
+
+// formatPublicKey returns the hex encoding of [publicKey], or an empty string
+// if [publicKey] is nil.
+func formatPublicKey(publicKey *bls.PublicKey) (string, error) {
+	if publicKey == nil {
+		return "", nil
+	}
+	return formatting.Encode(formatting.HexNC, bls.PublicKeyToCompressedBytes(publicKey))
+}
+
+func toOutputOwners(owner fx.Owner) (*secp256k1fx.OutputOwners, error) {
+	outputOwners, ok := owner.(*secp256k1fx.OutputOwners)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errUnsupportedOwner, owner)
+	}
+	return outputOwners, nil
+}
+
+func (s *Snapshot) formatAddresses(addrs []ids.ShortID) ([]string, error) {
+	formatted := make([]string, len(addrs))
+	for i, addr := range addrs {
+		addrStr, err := address.Format(chainAlias, s.hrp, addr.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		formatted[i] = addrStr
+	}
+	return formatted, nil
+}
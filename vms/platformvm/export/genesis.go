@@ -0,0 +1,279 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package export
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var (
+	ErrGenesisRequiresJSON  = errors.New("genesis can only be generated from a JSON export")
+	ErrGenesisRequiresUTXOs = errors.New("genesis can't be generated from an export that only contains validators")
+
+	errNoValidators        = errors.New("export doesn't contain any primary network validators")
+	errNotEnoughValidators = errors.New("not enough primary network validators")
+	errNoRewardAddress     = errors.New("validator doesn't have a reward address")
+)
+
+// GenesisArgs configure the genesis generated from an export. Zero values are
+// replaced by the values of [genesis.LocalConfig].
+type GenesisArgs struct {
+	NetworkID uint32
+	// NodeIDs, if non-empty, replace the node IDs of the heaviest primary
+	// network validators, in order. The remaining validators are dropped and
+	// the weights of the kept validators are scaled proportionally so that the
+	// total stake is preserved.
+	NodeIDs                    []ids.NodeID
+	StartTime                  uint64
+	InitialStakeDuration       uint64
+	InitialStakeDurationOffset uint64
+	CChainGenesis              string
+	Message                    string
+}
+
+// genesisValidator is a current primary network validator of the export.
+type genesisValidator struct {
+	nodeID        ids.NodeID
+	weight        uint64
+	delegationFee uint32
+	rewardAddr    ids.ShortID
+}
+
+// ReadMetadata reads the metadata of the export in [dir].
+func ReadMetadata(dir string) (*Metadata, error) {
+	metadataBytes, err := os.ReadFile(filepath.Join(dir, MetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	metadata := &Metadata{}
+	return metadata, json.Unmarshal(metadataBytes, metadata)
+}
+
+// GenesisConfig converts the JSON export in [dir] into the genesis of a local
+// network with the same primary network validators and P-chain balances.
+//
+// The current primary network validators become the initial stakers with
+// their weight, and their stake is allocated to their reward addresses. The
+// funds of these addresses that aren't staked by an initial staker are
+// allocated as UTXOs by the genesis.
+//
+// The UTXOs are allocated to the first address of their owner and keep their
+// locktime. The stake of delegators and pending validators is allocated to
+// their first reward address. Only the P-chain state is exported, so the
+// X-chain and C-chain don't have any allocations.
+func GenesisConfig(dir string, args GenesisArgs) (*genesis.Config, error) {
+	metadata, err := ReadMetadata(dir)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.Format != JSON {
+		return nil, fmt.Errorf("%w: export format is %q", ErrGenesisRequiresJSON, metadata.Format)
+	}
+	if metadata.ValidatorsOnly {
+		return nil, fmt.Errorf("%w: export is of height %d", ErrGenesisRequiresUTXOs, metadata.Height)
+	}
+
+	config := &genesis.Config{
+		NetworkID:                  args.NetworkID,
+		StartTime:                  args.StartTime,
+		InitialStakeDuration:       args.InitialStakeDuration,
+		InitialStakeDurationOffset: args.InitialStakeDurationOffset,
+		CChainGenesis:              args.CChainGenesis,
+		Message:                    args.Message,
+	}
+	if config.NetworkID == 0 {
+		config.NetworkID = constants.LocalID
+	}
+	if config.StartTime == 0 {
+		config.StartTime = genesis.LocalConfig.StartTime
+	}
+	if config.InitialStakeDuration == 0 {
+		config.InitialStakeDuration = genesis.LocalConfig.InitialStakeDuration
+	}
+	if config.InitialStakeDurationOffset == 0 {
+		config.InitialStakeDurationOffset = genesis.LocalConfig.InitialStakeDurationOffset
+	}
+	if config.CChainGenesis == "" {
+		config.CChainGenesis = genesis.LocalConfig.CChainGenesis
+	}
+
+	var (
+		balances   = make(balances)
+		validators []genesisValidator
+	)
+	err = readJSON(filepath.Join(dir, StakersFile+"."+string(JSON)), func(staker Staker) error {
+		if staker.SubnetID != constants.PrimaryNetworkID {
+			return nil
+		}
+		if len(staker.RewardAddresses) == 0 {
+			return fmt.Errorf("%w: %s", errNoRewardAddress, staker.TxID)
+		}
+		rewardAddr, err := address.ParseToID(staker.RewardAddresses[0])
+		if err != nil {
+			return err
+		}
+		if staker.Status != StakerStatusCurrent || staker.Type != StakerTypeValidator {
+			return balances.add(rewardAddr, 0, staker.Weight)
+		}
+		validators = append(validators, genesisValidator{
+			nodeID:        staker.NodeID,
+			weight:        staker.Weight,
+			delegationFee: staker.DelegationFee,
+			rewardAddr:    rewardAddr,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stakers: %w", err)
+	}
+
+	err = readJSON(filepath.Join(dir, UTXOsFile+"."+string(JSON)), func(utxo UTXO) error {
+		if len(utxo.Addresses) == 0 {
+			return nil
+		}
+		addr, err := address.ParseToID(utxo.Addresses[0])
+		if err != nil {
+			return err
+		}
+		return balances.add(addr, max(utxo.Locktime, utxo.StakeableLocktime), utxo.Amount)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read UTXOs: %w", err)
+	}
+
+	if len(validators) == 0 {
+		return nil, errNoValidators
+	}
+	if len(validators) < len(args.NodeIDs) {
+		return nil, fmt.Errorf("%w: export has %d but %d node IDs were provided",
+			errNotEnoughValidators,
+			len(validators),
+			len(args.NodeIDs),
+		)
+	}
+
+	// Sort the validators from the heaviest to the lightest.
+	slices.SortFunc(validators, func(a, b genesisValidator) int {
+		if weightCmp := cmp.Compare(b.weight, a.weight); weightCmp != 0 {
+			return weightCmp
+		}
+		return a.nodeID.Compare(b.nodeID)
+	})
+	var (
+		totalWeight uint64
+		stakedFunds = set.NewSet[ids.ShortID](len(validators))
+	)
+	for _, validator := range validators {
+		if err := balances.add(validator.rewardAddr, 0, validator.weight); err != nil {
+			return nil, err
+		}
+		totalWeight, err = math.Add(totalWeight, validator.weight)
+		if err != nil {
+			return nil, err
+		}
+		if !stakedFunds.Contains(validator.rewardAddr) {
+			stakedFunds.Add(validator.rewardAddr)
+			config.InitialStakedFunds = append(config.InitialStakedFunds, validator.rewardAddr)
+		}
+	}
+	if len(args.NodeIDs) > 0 {
+		validators = validators[:len(args.NodeIDs)]
+		for i, nodeID := range args.NodeIDs {
+			validators[i].nodeID = nodeID
+		}
+		scaleWeights(validators, totalWeight)
+	}
+
+	for _, validator := range validators {
+		config.InitialStakers = append(config.InitialStakers, genesis.Staker{
+			NodeID:        validator.nodeID,
+			RewardAddress: validator.rewardAddr,
+			DelegationFee: validator.delegationFee,
+			Weight:        validator.weight,
+		})
+	}
+	config.Allocations = balances.allocations()
+	return config, nil
+}
+
+// scaleWeights scales the weights of [validators] proportionally so that they
+// sum to [totalWeight]. The rounding error is allocated to the heaviest
+// validator, which must be first.
+func scaleWeights(validators []genesisValidator, totalWeight uint64) {
+	var weight uint64
+	for _, validator := range validators {
+		weight += validator.weight
+	}
+
+	var (
+		bigTotalWeight = new(big.Int).SetUint64(totalWeight)
+		bigWeight      = new(big.Int).SetUint64(weight)
+		scaledWeight   uint64
+	)
+	for i := range validators {
+		bigValidatorWeight := new(big.Int).SetUint64(validators[i].weight)
+		bigValidatorWeight.Mul(bigValidatorWeight, bigTotalWeight)
+		bigValidatorWeight.Div(bigValidatorWeight, bigWeight)
+		validators[i].weight = bigValidatorWeight.Uint64()
+		scaledWeight += validators[i].weight
+	}
+	validators[0].weight += totalWeight - scaledWeight
+}
+
+// balances maps addresses to the amount they own for each locktime.
+type balances map[ids.ShortID]map[uint64]uint64
+
+func (b balances) add(addr ids.ShortID, locktime uint64, amount uint64) error {
+	amounts, ok := b[addr]
+	if !ok {
+		amounts = make(map[uint64]uint64)
+		b[addr] = amounts
+	}
+	newAmount, err := math.Add(amounts[locktime], amount)
+	if err != nil {
+		return err
+	}
+	amounts[locktime] = newAmount
+	return nil
+}
+
+// allocations returns the balances sorted by address, with the unlock
+// schedules sorted by locktime.
+func (b balances) allocations() []genesis.Allocation {
+	allocations := make([]genesis.Allocation, 0, len(b))
+	for addr, amounts := range b {
+		allocation := genesis.Allocation{
+			AVAXAddr:       addr,
+			UnlockSchedule: make([]genesis.LockedAmount, 0, len(amounts)),
+		}
+		for locktime, amount := range amounts {
+			allocation.UnlockSchedule = append(allocation.UnlockSchedule, genesis.LockedAmount{
+				Amount:   amount,
+				Locktime: locktime,
+			})
+		}
+		slices.SortFunc(allocation.UnlockSchedule, func(a, b genesis.LockedAmount) int {
+			return cmp.Compare(a.Locktime, b.Locktime)
+		})
+		allocations = append(allocations, allocation)
+	}
+	slices.SortFunc(allocations, func(a, b genesis.Allocation) int {
+		return a.AVAXAddr.Compare(b.AVAXAddr)
+	})
+	return allocations
+}
@@ -0,0 +1,249 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package export
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+func TestGenesisConfig(t *testing.T) {
+	var (
+		nodeIDA = ids.GenerateTestNodeID()
+		nodeIDB = ids.GenerateTestNodeID()
+		nodeIDC = ids.GenerateTestNodeID()
+		nodeIDD = ids.GenerateTestNodeID()
+		nodeIDE = ids.GenerateTestNodeID()
+		addrs   = []ids.ShortID{
+			ids.GenerateTestShortID(),
+			ids.GenerateTestShortID(),
+			ids.GenerateTestShortID(),
+			ids.GenerateTestShortID(),
+		}
+		addrStrs = make([]string, len(addrs))
+	)
+	for i, addr := range addrs {
+		addrStr, err := address.Format(chainAlias, constants.LocalHRP, addr.Bytes())
+		require.NoError(t, err)
+		addrStrs[i] = addrStr
+	}
+
+	stakers := []Staker{
+		{
+			SubnetID:        constants.PrimaryNetworkID,
+			NodeID:          nodeIDB,
+			Status:          StakerStatusCurrent,
+			Type:            StakerTypeValidator,
+			Weight:          100,
+			RewardAddresses: []string{addrStrs[1]},
+		},
+		{
+			SubnetID:        constants.PrimaryNetworkID,
+			NodeID:          nodeIDA,
+			Status:          StakerStatusCurrent,
+			Type:            StakerTypeValidator,
+			Weight:          300,
+			DelegationFee:   20_000,
+			RewardAddresses: []string{addrStrs[0]},
+		},
+		{
+			SubnetID:        constants.PrimaryNetworkID,
+			NodeID:          nodeIDE,
+			Status:          StakerStatusCurrent,
+			Type:            StakerTypeValidator,
+			Weight:          1,
+			RewardAddresses: []string{addrStrs[1]},
+		},
+		{
+			SubnetID:        constants.PrimaryNetworkID,
+			NodeID:          nodeIDA,
+			Status:          StakerStatusCurrent,
+			Type:            StakerTypeDelegator,
+			Weight:          50,
+			RewardAddresses: []string{addrStrs[2]},
+		},
+		{
+			SubnetID:        constants.PrimaryNetworkID,
+			NodeID:          nodeIDC,
+			Status:          StakerStatusPending,
+			Type:            StakerTypeValidator,
+			Weight:          70,
+			RewardAddresses: []string{addrStrs[3]},
+		},
+		{
+			SubnetID:        ids.GenerateTestID(),
+			NodeID:          nodeIDA,
+			Status:          StakerStatusCurrent,
+			Type:            StakerTypeValidator,
+			Weight:          1,
+			RewardAddresses: []string{},
+		},
+	}
+	utxos := []UTXO{
+		{
+			Amount:    10,
+			Threshold: 1,
+			Addresses: []string{addrStrs[2]},
+		},
+		{
+			Amount:            5,
+			StakeableLocktime: 99,
+			Threshold:         1,
+			Addresses:         []string{addrStrs[0]},
+		},
+	}
+
+	dir := t.TempDir()
+	writeTestExport(t, dir, stakers, utxos)
+
+	unstakedAllocations := []genesis.Allocation{
+		{
+			AVAXAddr:       addrs[2],
+			UnlockSchedule: []genesis.LockedAmount{{Amount: 60}},
+		},
+		{
+			AVAXAddr:       addrs[3],
+			UnlockSchedule: []genesis.LockedAmount{{Amount: 70}},
+		},
+	}
+	stakedAllocations := append([]genesis.Allocation{
+		{
+			AVAXAddr:       addrs[0],
+			UnlockSchedule: []genesis.LockedAmount{{Amount: 300}, {Amount: 5, Locktime: 99}},
+		},
+		{
+			AVAXAddr:       addrs[1],
+			UnlockSchedule: []genesis.LockedAmount{{Amount: 101}},
+		},
+	}, unstakedAllocations...)
+	tests := []struct {
+		name                       string
+		nodeIDs                    []ids.NodeID
+		expectedStakers            []genesis.Staker
+		expectedInitialStakedFunds []ids.ShortID
+		expectedAllocations        []genesis.Allocation
+	}{
+		{
+			name: "all validators",
+			expectedStakers: []genesis.Staker{
+				{
+					NodeID:        nodeIDA,
+					RewardAddress: addrs[0],
+					DelegationFee: 20_000,
+					Weight:        300,
+				},
+				{
+					NodeID:        nodeIDB,
+					RewardAddress: addrs[1],
+					Weight:        100,
+				},
+				{
+					NodeID:        nodeIDE,
+					RewardAddress: addrs[1],
+					Weight:        1,
+				},
+			},
+			expectedInitialStakedFunds: []ids.ShortID{addrs[0], addrs[1]},
+			expectedAllocations:        stakedAllocations,
+		},
+		{
+			name:    "replaced node IDs",
+			nodeIDs: []ids.NodeID{nodeIDC},
+			expectedStakers: []genesis.Staker{
+				{
+					NodeID:        nodeIDC,
+					RewardAddress: addrs[0],
+					DelegationFee: 20_000,
+					Weight:        401,
+				},
+			},
+			expectedInitialStakedFunds: []ids.ShortID{addrs[0], addrs[1]},
+			expectedAllocations:        stakedAllocations,
+		},
+		{
+			name:    "scaled weights",
+			nodeIDs: []ids.NodeID{nodeIDC, nodeIDD},
+			expectedStakers: []genesis.Staker{
+				{
+					NodeID:        nodeIDC,
+					RewardAddress: addrs[0],
+					DelegationFee: 20_000,
+					Weight:        301,
+				},
+				{
+					NodeID:        nodeIDD,
+					RewardAddress: addrs[1],
+					Weight:        100,
+				},
+			},
+			expectedInitialStakedFunds: []ids.ShortID{addrs[0], addrs[1]},
+			expectedAllocations:        stakedAllocations,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			config, err := GenesisConfig(dir, GenesisArgs{
+				NodeIDs: test.nodeIDs,
+			})
+			require.NoError(err)
+			require.Equal(constants.LocalID, config.NetworkID)
+			require.Equal(genesis.LocalConfig.CChainGenesis, config.CChainGenesis)
+			require.Equal(test.expectedStakers, config.InitialStakers)
+			require.Equal(test.expectedInitialStakedFunds, config.InitialStakedFunds)
+
+			expectedAllocations := slices.Clone(test.expectedAllocations)
+			slices.SortFunc(expectedAllocations, func(a, b genesis.Allocation) int {
+				return a.AVAXAddr.Compare(b.AVAXAddr)
+			})
+			require.Equal(expectedAllocations, config.Allocations)
+
+			_, _, err = genesis.FromConfig(config)
+			require.NoError(err)
+		})
+	}
+
+	_, err := GenesisConfig(dir, GenesisArgs{
+		NodeIDs: []ids.NodeID{nodeIDA, nodeIDB, nodeIDC, nodeIDD},
+	})
+	require.ErrorIs(t, err, errNotEnoughValidators)
+}
+
+func writeTestExport(t *testing.T, dir string, stakers []Staker, utxos []UTXO) {
+	require := require.New(t)
+
+	stakerWriter, err := newFileWriter[Staker](dir, StakersFile, JSON)
+	require.NoError(err)
+	for _, staker := range stakers {
+		require.NoError(stakerWriter.Write(staker))
+	}
+	require.NoError(stakerWriter.Close())
+
+	utxoWriter, err := newFileWriter[UTXO](dir, UTXOsFile, JSON)
+	require.NoError(err)
+	for _, utxo := range utxos {
+		require.NoError(utxoWriter.Write(utxo))
+	}
+	require.NoError(utxoWriter.Close())
+
+	metadataBytes, err := json.Marshal(&Metadata{
+		NetworkID:  constants.MainnetID,
+		Format:     JSON,
+		NumStakers: uint64(len(stakers)),
+		NumUTXOs:   uint64(len(utxos)),
+	})
+	require.NoError(err)
+	require.NoError(perms.WriteFile(filepath.Join(dir, MetadataFile), metadataBytes, perms.ReadWrite))
+}
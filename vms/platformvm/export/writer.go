@@ -0,0 +1,146 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+var errUnexpectedToken = errors.New("unexpected JSON token")
+
+// record is a row of an exported file.
+type record interface {
+	csvHeader() []string
+	csvRow() []string
+}
+
+// fileWriter streams records to a file. JSON files contain a single array
+// with one record per line so that they can be decoded without loading the
+// whole file into memory.
+type fileWriter[T record] struct {
+	format Format
+	file   *os.File
+	buf    *bufio.Writer
+	csv    *csv.Writer
+	count  uint64
+}
+
+func newFileWriter[T record](dir string, name string, format Format) (*fileWriter[T], error) {
+	file, err := os.OpenFile(
+		filepath.Join(dir, name+"."+string(format)),
+		os.O_CREATE|os.O_TRUNC|os.O_WRONLY,
+		perms.ReadWrite,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &fileWriter[T]{
+		format: format,
+		file:   file,
+		buf:    bufio.NewWriter(file),
+	}
+	if format == CSV {
+		w.csv = csv.NewWriter(w.buf)
+		var zero T
+		if err := w.csv.Write(zero.csvHeader()); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (w *fileWriter[T]) Write(r T) error {
+	w.count++
+	if w.format == CSV {
+		return w.csv.Write(r.csvRow())
+	}
+
+	bytes, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	prefix := ",\n"
+	if w.count == 1 {
+		prefix = "[\n"
+	}
+	if _, err := w.buf.WriteString(prefix); err != nil {
+		return err
+	}
+	_, err = w.buf.Write(bytes)
+	return err
+}
+
+// Close flushes the buffered records and closes the file.
+func (w *fileWriter[T]) Close() error {
+	if err := w.finish(); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+func (w *fileWriter[T]) finish() error {
+	if w.format == CSV {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+		return w.buf.Flush()
+	}
+
+	suffix := "\n]\n"
+	if w.count == 0 {
+		suffix = "[]\n"
+	}
+	if _, err := w.buf.WriteString(suffix); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
+
+// readJSON decodes the records of a JSON file written by [fileWriter] one at
+// a time and calls [f] with each of them.
+func readJSON[T any](path string, f func(T) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	if err := expectDelim(decoder, '['); err != nil {
+		return err
+	}
+	for decoder.More() {
+		var r T
+		if err := decoder.Decode(&r); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		if err := f(r); err != nil {
+			return err
+		}
+	}
+	return expectDelim(decoder, ']')
+}
+
+func expectDelim(decoder *json.Decoder, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return fmt.Errorf("%w: expected %q but got %v", errUnexpectedToken, expected, token)
+	}
+	return nil
+}
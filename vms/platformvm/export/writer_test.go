@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestFileWriter(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	utxos := []UTXO{
+		{
+			TxID:      ids.GenerateTestID(),
+			AssetID:   ids.GenerateTestID(),
+			Amount:    1,
+			Threshold: 1,
+			Addresses: []string{"P-local1a"},
+		},
+		{
+			TxID:              ids.GenerateTestID(),
+			OutputIndex:       2,
+			AssetID:           ids.GenerateTestID(),
+			Amount:            3,
+			StakeableLocktime: 4,
+			Threshold:         2,
+			Addresses:         []string{"P-local1b", "P-local1c"},
+		},
+	}
+
+	jsonWriter, err := newFileWriter[UTXO](dir, UTXOsFile, JSON)
+	require.NoError(err)
+	csvWriter, err := newFileWriter[UTXO](dir, UTXOsFile, CSV)
+	require.NoError(err)
+	for _, utxo := range utxos {
+		require.NoError(jsonWriter.Write(utxo))
+		require.NoError(csvWriter.Write(utxo))
+	}
+	require.NoError(jsonWriter.Close())
+	require.NoError(csvWriter.Close())
+
+	var readUTXOs []UTXO
+	require.NoError(readJSON(filepath.Join(dir, "utxos.json"), func(utxo UTXO) error {
+		readUTXOs = append(readUTXOs, utxo)
+		return nil
+	}))
+	require.Equal(utxos, readUTXOs)
+
+	csvBytes, err := os.ReadFile(filepath.Join(dir, "utxos.csv"))
+	require.NoError(err)
+	require.Equal(
+		"txID,outputIndex,assetID,amount,locktime,stakeableLocktime,threshold,addresses\n"+
+			utxos[0].TxID.String()+",0,"+utxos[0].AssetID.String()+",1,0,0,1,P-local1a\n"+
+			utxos[1].TxID.String()+",2,"+utxos[1].AssetID.String()+",3,0,4,2,P-local1b;P-local1c\n",
+		string(csvBytes),
+	)
+}
+
+func TestFileWriterEmpty(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	w, err := newFileWriter[Chain](dir, ChainsFile, JSON)
+	require.NoError(err)
+	require.NoError(w.Close())
+
+	require.NoError(readJSON(filepath.Join(dir, "chains.json"), func(Chain) error {
+		require.FailNow("unexpected chain")
+		return nil
+	}))
+}
+
+func TestReadJSONUnexpectedToken(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "chains.json")
+	require.NoError(os.WriteFile(path, []byte(`{"chainID":"11111111111111111111111111111111LpoYY"}`), 0o600))
+
+	err := readJSON(path, func(Chain) error {
+		return nil
+	})
+	require.ErrorIs(err, errUnexpectedToken)
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedFormat Format
+		expectedErr    error
+	}{
+		{
+			input:          "",
+			expectedFormat: JSON,
+		},
+		{
+			input:          "json",
+			expectedFormat: JSON,
+		},
+		{
+			input:          "CSV",
+			expectedFormat: CSV,
+		},
+		{
+			input:       "xml",
+			expectedErr: ErrUnknownFormat,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			format, err := ParseFormat(test.input)
+			require.ErrorIs(t, err, test.expectedErr)
+			require.Equal(t, test.expectedFormat, format)
+		})
+	}
+}
@@ -14,9 +14,16 @@ var _ vms.Factory = (*Factory)(nil)
 // Factory can create new instances of the Platform Chain
 type Factory struct {
 	config.Config
+
+	// StateExporter, if non-nil, exports the state of the chains created by
+	// this factory once they are initialized.
+	StateExporter *StateExporter
 }
 
 // New returns a new instance of the Platform Chain
 func (f *Factory) New(logging.Logger) (interface{}, error) {
-	return &VM{Config: f.Config}, nil
+	return &VM{
+		Config:        f.Config,
+		stateExporter: f.StateExporter,
+	}, nil
 }
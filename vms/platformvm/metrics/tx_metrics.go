@@ -138,3 +138,31 @@ func (m *txMetrics) BaseTx(*txs.BaseTx) error {
 	}).Inc()
 	return nil
 }
+
+func (m *txMetrics) ConvertSubnetTx(*txs.ConvertSubnetTx) error {
+	m.numTxs.With(prometheus.Labels{
+		txLabel: "convert_subnet",
+	}).Inc()
+	return nil
+}
+
+func (m *txMetrics) RegisterSubnetValidatorTx(*txs.RegisterSubnetValidatorTx) error {
+	m.numTxs.With(prometheus.Labels{
+		txLabel: "register_subnet_validator",
+	}).Inc()
+	return nil
+}
+
+func (m *txMetrics) SetSubnetValidatorWeightTx(*txs.SetSubnetValidatorWeightTx) error {
+	m.numTxs.With(prometheus.Labels{
+		txLabel: "set_subnet_validator_weight",
+	}).Inc()
+	return nil
+}
+
+func (m *txMetrics) IncreaseBalanceTx(*txs.IncreaseBalanceTx) error {
+	m.numTxs.With(prometheus.Labels{
+		txLabel: "increase_balance",
+	}).Inc()
+	return nil
+}
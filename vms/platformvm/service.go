@@ -4,6 +4,7 @@
 package platformvm
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,6 +12,7 @@ import (
 	"maps"
 	"math"
 	"net/http"
+	"slices"
 	"time"
 
 	"go.uber.org/zap"
@@ -29,6 +31,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/keystore"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/platformvm/fx"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
@@ -37,10 +40,13 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/vms/types"
 
 	avajson "github.com/ava-labs/avalanchego/utils/json"
 	safemath "github.com/ava-labs/avalanchego/utils/math"
 	platformapi "github.com/ava-labs/avalanchego/vms/platformvm/api"
+	blockexecutor "github.com/ava-labs/avalanchego/vms/platformvm/block/executor"
+	txexecutor "github.com/ava-labs/avalanchego/vms/platformvm/txs/executor"
 )
 
 const (
@@ -59,10 +65,18 @@ const (
 )
 
 var (
-	errMissingDecisionBlock       = errors.New("should have a decision block within the past two blocks")
-	errPrimaryNetworkIsNotASubnet = errors.New("the primary network isn't a subnet")
-	errNoAddresses                = errors.New("no addresses provided")
-	errMissingBlockchainID        = errors.New("argument 'blockchainID' not given")
+	errMissingDecisionBlock         = errors.New("should have a decision block within the past two blocks")
+	errPrimaryNetworkIsNotASubnet   = errors.New("the primary network isn't a subnet")
+	errNoAddresses                  = errors.New("no addresses provided")
+	errMissingBlockchainID          = errors.New("argument 'blockchainID' not given")
+	errNoStakeAmount                = errors.New("argument 'stakeAmount' not given")
+	errInvalidDelegationFee         = errors.New("delegation fee must be between 0 and 100")
+	errInvalidStakeDuration         = errors.New("invalid stake duration")
+	errValidatorNotPermissionless   = errors.New("validator does not accept delegations")
+	errDelegationEndsAfterValidator = errors.New("delegation ends after validator")
+	errInvalidHeightRange           = errors.New("start height is after end height")
+	errUnfinalizedHeight            = errors.New("end height is after the current height")
+	errNodeIDOrAddress              = errors.New("exactly one of nodeID and address must be provided")
 )
 
 // Service defines the API calls that can be made to the platform chain
@@ -437,6 +451,9 @@ type GetSubnetResponse struct {
 	Locktime    avajson.Uint64 `json:"locktime"`
 	// subnet transformation tx ID for a permissionless subnet
 	SubnetTransformationTxID ids.ID `json:"subnetTransformationTxID"`
+	// validator manager information for a converted subnet
+	ManagerChainID ids.ID              `json:"managerChainID"`
+	ManagerAddress types.JSONByteSlice `json:"managerAddress"`
 }
 
 func (s *Service) GetSubnet(_ *http.Request, args *GetSubnetArgs, response *GetSubnetResponse) error {
@@ -485,6 +502,88 @@ func (s *Service) GetSubnet(_ *http.Request, args *GetSubnetArgs, response *GetS
 		return err
 	}
 
+	switch chainID, addr, err := s.vm.state.GetSubnetManager(args.SubnetID); err {
+	case nil:
+		response.ManagerChainID = chainID
+		response.ManagerAddress = addr
+	case database.ErrNotFound:
+		response.ManagerChainID = ids.Empty
+		response.ManagerAddress = []byte(nil)
+	default:
+		return err
+	}
+
+	return nil
+}
+
+// GetSubnetOnlyValidatorArgs are the arguments for calling
+// GetSubnetOnlyValidator
+type GetSubnetOnlyValidatorArgs struct {
+	// ID of the validation to fetch
+	ValidationID ids.ID `json:"validationID"`
+}
+
+// GetSubnetOnlyValidatorReply is the response from calling
+// GetSubnetOnlyValidator
+type GetSubnetOnlyValidatorReply struct {
+	SubnetID ids.ID     `json:"subnetID"`
+	NodeID   ids.NodeID `json:"nodeID"`
+	// PublicKey is the compressed BLS public key of the validator
+	PublicKey             types.JSONByteSlice `json:"publicKey"`
+	RemainingBalanceOwner platformapi.Owner   `json:"remainingBalanceOwner"`
+	StartTime             avajson.Uint64      `json:"startTime"`
+	Weight                avajson.Uint64      `json:"weight"`
+	MinNonce              avajson.Uint64      `json:"minNonce"`
+	// Balance is the remaining balance the validator can use to pay for its
+	// continuous fee. It is 0 if the validator is inactive or removed.
+	Balance avajson.Uint64 `json:"balance"`
+	// IsActive is true if the validator is included in the validator set
+	IsActive bool `json:"isActive"`
+}
+
+// GetSubnetOnlyValidator returns the subnet-only validator with the provided
+// validationID.
+func (s *Service) GetSubnetOnlyValidator(_ *http.Request, args *GetSubnetOnlyValidatorArgs, reply *GetSubnetOnlyValidatorReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getSubnetOnlyValidator"),
+		zap.Stringer("validationID", args.ValidationID),
+	)
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	sov, err := s.vm.state.GetSubnetOnlyValidator(args.ValidationID)
+	if err != nil {
+		return fmt.Errorf("fetching subnet-only validator %q failed: %w", args.ValidationID, err)
+	}
+
+	addrs := make([]string, len(sov.RemainingBalanceOwner.Addresses))
+	for i, addr := range sov.RemainingBalanceOwner.Addresses {
+		addrs[i], err = s.addrManager.FormatLocalAddress(addr)
+		if err != nil {
+			return fmt.Errorf("problem formatting address: %w", err)
+		}
+	}
+
+	pk := bls.PublicKeyFromValidUncompressedBytes(sov.PublicKey)
+	reply.SubnetID = sov.SubnetID
+	reply.NodeID = sov.NodeID
+	reply.PublicKey = bls.PublicKeyToCompressedBytes(pk)
+	reply.RemainingBalanceOwner = platformapi.Owner{
+		Threshold: avajson.Uint32(sov.RemainingBalanceOwner.Threshold),
+		Addresses: addrs,
+	}
+	reply.StartTime = avajson.Uint64(sov.StartTime)
+	reply.Weight = avajson.Uint64(sov.Weight)
+	reply.MinNonce = avajson.Uint64(sov.MinNonce)
+	reply.IsActive = sov.IsActive()
+	if sov.IsActive() {
+		accruedFees := s.vm.state.GetAccruedFees()
+		if sov.EndAccumulatedFee > accruedFees {
+			reply.Balance = avajson.Uint64(sov.EndAccumulatedFee - accruedFees)
+		}
+	}
 	return nil
 }
 
@@ -943,6 +1042,130 @@ func (s *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentValidato
 	return nil
 }
 
+// GetObservedUptimesArgs are the arguments for calling GetObservedUptimes
+type GetObservedUptimesArgs struct {
+	// Subnet to report the uptimes of
+	// If omitted, defaults to the primary network
+	SubnetID ids.ID `json:"subnetID"`
+}
+
+// APIObservedUptime is the uptime of a current validator as observed by this
+// node
+type APIObservedUptime struct {
+	NodeID ids.NodeID `json:"nodeID"`
+	// Percentage (0-100) of the staking period this node observed the
+	// validator to be online. Omitted if this node doesn't track the subnet.
+	Uptime    *avajson.Float32 `json:"uptime,omitempty"`
+	Connected bool             `json:"connected"`
+	// Unix time, in seconds, this node last connected to the validator. 0 if
+	// this node hasn't connected to the validator since it started.
+	LastConnected avajson.Uint64 `json:"lastConnected"`
+	// Unix time, in seconds, this node last disconnected from the validator.
+	// 0 if this node hasn't disconnected from the validator since it started.
+	LastDisconnected avajson.Uint64 `json:"lastDisconnected"`
+	// True if this node would currently vote to reward the validator. Omitted
+	// if validators of the subnet aren't rewarded.
+	MeetsRewardThreshold *bool `json:"meetsRewardThreshold,omitempty"`
+}
+
+// GetObservedUptimesReply is the response from calling GetObservedUptimes
+type GetObservedUptimesReply struct {
+	// Minimum uptime percentage (0-100) required to be rewarded. Omitted if
+	// validators of the subnet aren't rewarded.
+	UptimeRequirement *avajson.Float32 `json:"uptimeRequirement,omitempty"`
+	// The uptimes of the current validators, ordered by node ID
+	Validators []APIObservedUptime `json:"validators"`
+}
+
+// GetObservedUptimes returns the uptimes of the current validators of a subnet
+// as observed by this node.
+func (s *Service) GetObservedUptimes(_ *http.Request, args *GetObservedUptimesArgs, reply *GetObservedUptimesReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getObservedUptimes"),
+		zap.Stringer("subnetID", args.SubnetID),
+	)
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	// Validators of permissioned subnets aren't rewarded, so they don't have an
+	// uptime requirement.
+	var uptimeRequirement *float64
+	if args.SubnetID == constants.PrimaryNetworkID {
+		uptimeRequirement = &s.vm.UptimePercentage
+	} else {
+		transformSubnet, err := txexecutor.GetTransformSubnetTx(s.vm.state, args.SubnetID)
+		switch {
+		case err == nil:
+			requirement := float64(transformSubnet.UptimeRequirement) / reward.PercentDenominator
+			uptimeRequirement = &requirement
+		case !errors.Is(err, database.ErrNotFound):
+			return fmt.Errorf("couldn't get subnet transformation: %w", err)
+		}
+	}
+	if uptimeRequirement != nil {
+		apiUptimeRequirement := avajson.Float32(*uptimeRequirement * 100)
+		reply.UptimeRequirement = &apiUptimeRequirement
+	}
+
+	currentStakerIterator, err := s.vm.state.GetCurrentStakerIterator()
+	if err != nil {
+		return err
+	}
+	defer currentStakerIterator.Release()
+
+	reply.Validators = []APIObservedUptime{}
+	for currentStakerIterator.Next() {
+		staker := currentStakerIterator.Value()
+		if staker.SubnetID != args.SubnetID || !staker.Priority.IsCurrentValidator() {
+			continue
+		}
+
+		uptime, err := s.getAPIUptime(staker)
+		if err != nil {
+			return err
+		}
+
+		connected, disconnected := s.vm.uptimeManager.LastConnectionTimes(staker.NodeID, staker.SubnetID)
+		observedUptime := APIObservedUptime{
+			NodeID:    staker.NodeID,
+			Uptime:    uptime,
+			Connected: s.vm.uptimeManager.IsConnected(staker.NodeID, staker.SubnetID),
+		}
+		if !connected.IsZero() {
+			observedUptime.LastConnected = avajson.Uint64(connected.Unix())
+		}
+		if !disconnected.IsZero() {
+			observedUptime.LastDisconnected = avajson.Uint64(disconnected.Unix())
+		}
+
+		if uptimeRequirement != nil {
+			// Rewards are currently decided using the primary network uptime.
+			primaryNetworkValidator, err := s.vm.state.GetCurrentValidator(constants.PrimaryNetworkID, staker.NodeID)
+			if err != nil {
+				return fmt.Errorf("couldn't get primary network validator %s: %w", staker.NodeID, err)
+			}
+			primaryNetworkUptime, err := s.vm.uptimeManager.CalculateUptimePercentFrom(
+				staker.NodeID,
+				constants.PrimaryNetworkID,
+				primaryNetworkValidator.StartTime,
+			)
+			if err != nil {
+				return fmt.Errorf("couldn't calculate uptime of %s: %w", staker.NodeID, err)
+			}
+			meetsRewardThreshold := primaryNetworkUptime >= *uptimeRequirement
+			observedUptime.MeetsRewardThreshold = &meetsRewardThreshold
+		}
+		reply.Validators = append(reply.Validators, observedUptime)
+	}
+
+	slices.SortFunc(reply.Validators, func(a, b APIObservedUptime) int {
+		return a.NodeID.Compare(b.NodeID)
+	})
+	return nil
+}
+
 // GetCurrentSupplyArgs are the arguments for calling GetCurrentSupply
 type GetCurrentSupplyArgs struct {
 	SubnetID ids.ID `json:"subnetID"`
@@ -980,6 +1203,141 @@ func (s *Service) GetCurrentSupply(r *http.Request, args *GetCurrentSupplyArgs,
 	return nil
 }
 
+// EstimateRewardArgs are the arguments for calling EstimateReward
+type EstimateRewardArgs struct {
+	// Subnet the stake would be placed on
+	// If omitted, defaults to the primary network
+	SubnetID ids.ID `json:"subnetID"`
+	// Amount of the staking asset that would be staked
+	StakeAmount avajson.Uint64 `json:"stakeAmount"`
+	// Duration of the stake, in seconds
+	Duration avajson.Uint64 `json:"duration"`
+	// Percentage of the reward that would be paid to the validator if the
+	// stake is a delegation. Ignored if [NodeID] is provided.
+	DelegationFee avajson.Float32 `json:"delegationFee"`
+	// Validator the stake would be delegated to
+	// If provided, the delegation fee of this validator is used.
+	NodeID ids.NodeID `json:"nodeID"`
+}
+
+// EstimateRewardReply is the response from calling EstimateReward
+type EstimateRewardReply struct {
+	// Current supply of the staking asset used to estimate the reward
+	CurrentSupply avajson.Uint64 `json:"currentSupply"`
+	// Reward minted for the stake. If the stake is a validation, this is the
+	// reward of the validator.
+	Reward avajson.Uint64 `json:"reward"`
+	// Delegation fee used to split [Reward]
+	DelegationFee avajson.Float32 `json:"delegationFee"`
+	// Portion of [Reward] paid to the delegator if the stake is a delegation
+	DelegatorReward avajson.Uint64 `json:"delegatorReward"`
+	// Portion of [Reward] paid to the validator if the stake is a delegation
+	DelegateeReward avajson.Uint64 `json:"delegateeReward"`
+}
+
+// EstimateReward returns the reward that would be minted for a stake of the
+// provided amount and duration at the current supply.
+func (s *Service) EstimateReward(_ *http.Request, args *EstimateRewardArgs, reply *EstimateRewardReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "estimateReward"),
+		zap.Stringer("subnetID", args.SubnetID),
+	)
+
+	switch {
+	case args.StakeAmount == 0:
+		return errNoStakeAmount
+	case args.DelegationFee < 0 || args.DelegationFee > 100:
+		return fmt.Errorf("%w: %f", errInvalidDelegationFee, args.DelegationFee)
+	}
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	var (
+		rewardConfig     = s.vm.RewardConfig
+		minStakeDuration = s.vm.MinStakeDuration
+		maxStakeDuration = s.vm.MaxStakeDuration
+	)
+	if args.SubnetID != constants.PrimaryNetworkID {
+		transformSubnetIntf, err := s.vm.state.GetSubnetTransformation(args.SubnetID)
+		if err != nil {
+			return fmt.Errorf(
+				"failed fetching subnet transformation for %s: %w",
+				args.SubnetID,
+				err,
+			)
+		}
+		transformSubnet, ok := transformSubnetIntf.Unsigned.(*txs.TransformSubnetTx)
+		if !ok {
+			return fmt.Errorf(
+				"unexpected subnet transformation tx type fetched %T",
+				transformSubnetIntf.Unsigned,
+			)
+		}
+
+		rewardConfig = reward.Config{
+			MaxConsumptionRate: transformSubnet.MaxConsumptionRate,
+			MinConsumptionRate: transformSubnet.MinConsumptionRate,
+			MintingPeriod:      s.vm.RewardConfig.MintingPeriod,
+			SupplyCap:          transformSubnet.MaximumSupply,
+		}
+		minStakeDuration = time.Duration(transformSubnet.MinStakeDuration) * time.Second
+		maxStakeDuration = time.Duration(transformSubnet.MaxStakeDuration) * time.Second
+	}
+
+	if args.Duration > avajson.Uint64(maxStakeDuration/time.Second) {
+		return fmt.Errorf("%w: %d seconds > max %s", errInvalidStakeDuration, args.Duration, maxStakeDuration)
+	}
+	duration := time.Duration(args.Duration) * time.Second
+	if duration < minStakeDuration {
+		return fmt.Errorf("%w: %s < min %s", errInvalidStakeDuration, duration, minStakeDuration)
+	}
+
+	shares := uint32(math.Round(float64(args.DelegationFee) * reward.PercentDenominator / 100))
+	if args.NodeID != ids.EmptyNodeID {
+		validator, err := s.vm.state.GetCurrentValidator(args.SubnetID, args.NodeID)
+		if err != nil {
+			return fmt.Errorf("fetching validator %s failed: %w", args.NodeID, err)
+		}
+		if validator.Priority.IsPermissionedValidator() {
+			return fmt.Errorf("%w: %s", errValidatorNotPermissionless, args.NodeID)
+		}
+
+		endTime := s.vm.state.GetTimestamp().Add(duration)
+		if endTime.After(validator.EndTime) {
+			return fmt.Errorf(
+				"%w: delegation would end at %s but validator ends at %s",
+				errDelegationEndsAfterValidator,
+				endTime,
+				validator.EndTime,
+			)
+		}
+
+		attr, err := s.loadStakerTxAttributes(validator.TxID)
+		if err != nil {
+			return err
+		}
+		shares = attr.shares
+	}
+
+	currentSupply, err := s.vm.state.GetCurrentSupply(args.SubnetID)
+	if err != nil {
+		return fmt.Errorf("fetching current supply failed: %w", err)
+	}
+
+	calculator := reward.NewCalculator(rewardConfig)
+	potentialReward := calculator.Calculate(duration, uint64(args.StakeAmount), currentSupply)
+	delegateeReward, delegatorReward := reward.Split(potentialReward, shares)
+
+	reply.CurrentSupply = avajson.Uint64(currentSupply)
+	reply.Reward = avajson.Uint64(potentialReward)
+	reply.DelegationFee = avajson.Float32(100 * float32(shares) / float32(reward.PercentDenominator))
+	reply.DelegatorReward = avajson.Uint64(delegatorReward)
+	reply.DelegateeReward = avajson.Uint64(delegateeReward)
+	return nil
+}
+
 // SampleValidatorsArgs are the arguments for calling SampleValidators
 type SampleValidatorsArgs struct {
 	// Number of validators in the sample
@@ -1308,6 +1666,163 @@ func (s *Service) IssueTx(_ *http.Request, args *api.FormattedTx, response *api.
 	return nil
 }
 
+// APISimulatedStaker is a staker modified by a simulated transaction
+type APISimulatedStaker struct {
+	TxID            ids.ID         `json:"txID"`
+	NodeID          ids.NodeID     `json:"nodeID"`
+	SubnetID        ids.ID         `json:"subnetID"`
+	Weight          avajson.Uint64 `json:"weight"`
+	StartTime       avajson.Uint64 `json:"startTime"`
+	EndTime         avajson.Uint64 `json:"endTime"`
+	PotentialReward avajson.Uint64 `json:"potentialReward"`
+	IsValidator     bool           `json:"isValidator"`
+	IsCurrent       bool           `json:"isCurrent"`
+}
+
+// APISimulatedSubnetOnlyValidator is a subnet-only validator modified by a
+// simulated transaction
+type APISimulatedSubnetOnlyValidator struct {
+	ValidationID              ids.ID         `json:"validationID"`
+	SubnetID                  ids.ID         `json:"subnetID"`
+	NodeID                    ids.NodeID     `json:"nodeID"`
+	Weight                    avajson.Uint64 `json:"weight"`
+	PreviousWeight            avajson.Uint64 `json:"previousWeight"`
+	EndAccumulatedFee         avajson.Uint64 `json:"endAccumulatedFee"`
+	PreviousEndAccumulatedFee avajson.Uint64 `json:"previousEndAccumulatedFee"`
+}
+
+// SimulateTxReply is the response from calling SimulateTx
+type SimulateTxReply struct {
+	TxID ids.ID `json:"txID"`
+	// True if the transaction passes verification against the preferred state
+	Valid bool `json:"valid"`
+	// The reason the transaction fails verification. Empty if [Valid].
+	Error string `json:"error,omitempty"`
+	// Fee paid by the transaction
+	Fee avajson.Uint64 `json:"fee"`
+	// String representation of the UTXOs consumed and produced by the
+	// transaction, including the UTXOs imported from and exported to other
+	// chains. Each is of type avax.UTXO.
+	ConsumedUTXOs []string `json:"consumedUTXOs"`
+	ProducedUTXOs []string `json:"producedUTXOs"`
+	// Stakers added to or removed from the current and pending staker sets
+	AddedStakers   []APISimulatedStaker `json:"addedStakers"`
+	RemovedStakers []APISimulatedStaker `json:"removedStakers"`
+	// Subnet-only validators added to, removed from, or whose weight or
+	// balance was modified in the validator set
+	AddedSubnetOnlyValidators    []APISimulatedSubnetOnlyValidator `json:"addedSubnetOnlyValidators"`
+	RemovedSubnetOnlyValidators  []APISimulatedSubnetOnlyValidator `json:"removedSubnetOnlyValidators"`
+	ModifiedSubnetOnlyValidators []APISimulatedSubnetOnlyValidator `json:"modifiedSubnetOnlyValidators"`
+	// Encoding specifies the encoding format the UTXOs are returned in
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// SimulateTx executes a transaction on top of the preferred state without
+// issuing it. Neither the mempool nor the state is modified.
+func (s *Service) SimulateTx(_ *http.Request, args *api.FormattedTx, reply *SimulateTxReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "simulateTx"),
+	)
+
+	txBytes, err := formatting.Decode(args.Encoding, args.Tx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	tx, err := txs.Parse(txs.Codec, txBytes)
+	if err != nil {
+		return fmt.Errorf("couldn't parse tx: %w", err)
+	}
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	reply.TxID = tx.ID()
+	reply.Encoding = args.Encoding
+	reply.ConsumedUTXOs = []string{}
+	reply.ProducedUTXOs = []string{}
+	reply.AddedStakers = []APISimulatedStaker{}
+	reply.RemovedStakers = []APISimulatedStaker{}
+	reply.AddedSubnetOnlyValidators = []APISimulatedSubnetOnlyValidator{}
+	reply.RemovedSubnetOnlyValidators = []APISimulatedSubnetOnlyValidator{}
+	reply.ModifiedSubnetOnlyValidators = []APISimulatedSubnetOnlyValidator{}
+
+	simulatedTx, err := s.vm.manager.SimulateTx(tx)
+	if err != nil {
+		reply.Error = err.Error()
+		return nil
+	}
+
+	reply.Valid = true
+	reply.Fee = avajson.Uint64(simulatedTx.Fee)
+	reply.ConsumedUTXOs, err = encodeUTXOs(simulatedTx.ConsumedUTXOs, args.Encoding)
+	if err != nil {
+		return err
+	}
+	reply.ProducedUTXOs, err = encodeUTXOs(simulatedTx.ProducedUTXOs, args.Encoding)
+	if err != nil {
+		return err
+	}
+	for _, staker := range simulatedTx.AddedStakers {
+		reply.AddedStakers = append(reply.AddedStakers, newAPISimulatedStaker(staker))
+	}
+	for _, staker := range simulatedTx.RemovedStakers {
+		reply.RemovedStakers = append(reply.RemovedStakers, newAPISimulatedStaker(staker))
+	}
+	for _, sov := range simulatedTx.AddedSubnetOnlyValidators {
+		reply.AddedSubnetOnlyValidators = append(reply.AddedSubnetOnlyValidators, newAPISimulatedSubnetOnlyValidator(sov))
+	}
+	for _, sov := range simulatedTx.RemovedSubnetOnlyValidators {
+		reply.RemovedSubnetOnlyValidators = append(reply.RemovedSubnetOnlyValidators, newAPISimulatedSubnetOnlyValidator(sov))
+	}
+	for _, sov := range simulatedTx.ModifiedSubnetOnlyValidators {
+		reply.ModifiedSubnetOnlyValidators = append(reply.ModifiedSubnetOnlyValidators, newAPISimulatedSubnetOnlyValidator(sov))
+	}
+	return nil
+}
+
+func newAPISimulatedStaker(staker *state.Staker) APISimulatedStaker {
+	return APISimulatedStaker{
+		TxID:            staker.TxID,
+		NodeID:          staker.NodeID,
+		SubnetID:        staker.SubnetID,
+		Weight:          avajson.Uint64(staker.Weight),
+		StartTime:       avajson.Uint64(staker.StartTime.Unix()),
+		EndTime:         avajson.Uint64(staker.EndTime.Unix()),
+		PotentialReward: avajson.Uint64(staker.PotentialReward),
+		IsValidator:     staker.Priority.IsValidator(),
+		IsCurrent:       staker.Priority.IsCurrent(),
+	}
+}
+
+func newAPISimulatedSubnetOnlyValidator(sov blockexecutor.SimulatedSubnetOnlyValidator) APISimulatedSubnetOnlyValidator {
+	return APISimulatedSubnetOnlyValidator{
+		ValidationID:              sov.ValidationID,
+		SubnetID:                  sov.SubnetID,
+		NodeID:                    sov.NodeID,
+		Weight:                    avajson.Uint64(sov.Weight),
+		PreviousWeight:            avajson.Uint64(sov.PreviousWeight),
+		EndAccumulatedFee:         avajson.Uint64(sov.EndAccumulatedFee),
+		PreviousEndAccumulatedFee: avajson.Uint64(sov.PreviousEndAccumulatedFee),
+	}
+}
+
+func encodeUTXOs(utxos []*avax.UTXO, encoding formatting.Encoding) ([]string, error) {
+	utxoStrs := make([]string, len(utxos))
+	for i, utxo := range utxos {
+		utxoBytes, err := txs.GenesisCodec.Marshal(txs.CodecVersion, utxo)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't encode UTXO to bytes: %w", err)
+		}
+
+		utxoStrs[i], err = formatting.Encode(encoding, utxoBytes)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't encode utxo as %s: %w", encoding, err)
+		}
+	}
+	return utxoStrs, nil
+}
+
 func (s *Service) GetTx(_ *http.Request, args *api.GetTxArgs, response *api.GetTxReply) error {
 	s.vm.ctx.Log.Debug("API called",
 		zap.String("service", "platform"),
@@ -1514,6 +2029,227 @@ func (s *Service) GetStake(_ *http.Request, args *GetStakeArgs, response *GetSta
 	return nil
 }
 
+const (
+	unlockReasonLocktime          = "locktime"
+	unlockReasonStakeableLocktime = "stakeableLocktime"
+	unlockReasonStakingEnd        = "stakingEnd"
+)
+
+// UnlockScheduleEntry is an amount of an asset that becomes spendable by an
+// owner at a given time
+type UnlockScheduleEntry struct {
+	// Unix time, in seconds, the amount becomes spendable
+	Time avajson.Uint64 `json:"time"`
+	// What keeps the amount from being spent until [Time]. One of
+	// "locktime", "stakeableLocktime" or "stakingEnd".
+	Reason    string         `json:"reason"`
+	AssetID   ids.ID         `json:"assetID"`
+	Amount    avajson.Uint64 `json:"amount"`
+	Threshold avajson.Uint32 `json:"threshold"`
+	Addresses []string       `json:"addresses"`
+}
+
+// GetUnlockScheduleReply is the response from calling GetUnlockSchedule
+type GetUnlockScheduleReply struct {
+	// Unix time, in seconds, the schedule was computed at
+	CurrentTime avajson.Uint64 `json:"currentTime"`
+	// The locked amounts, ordered by the time they become spendable
+	Schedule []UnlockScheduleEntry `json:"schedule"`
+}
+
+type unlockScheduleKey struct {
+	time      uint64
+	reason    string
+	assetID   ids.ID
+	threshold uint32
+	addrs     string
+}
+
+func (k unlockScheduleKey) Compare(o unlockScheduleKey) int {
+	if c := cmp.Compare(k.time, o.time); c != 0 {
+		return c
+	}
+	if c := k.assetID.Compare(o.assetID); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(k.reason, o.reason); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(k.threshold, o.threshold); c != 0 {
+		return c
+	}
+	return cmp.Compare(k.addrs, o.addrs)
+}
+
+// unlockSchedule aggregates the amounts owned by [addrs] that are currently
+// locked.
+type unlockSchedule struct {
+	addrs       set.Set[ids.ShortID]
+	currentTime uint64
+	amounts     map[unlockScheduleKey]uint64
+	owners      map[unlockScheduleKey]*secp256k1fx.OutputOwners
+}
+
+// add records that [out] is spendable once [stakingEnd] has passed. If [out]
+// isn't staked, [stakingEnd] should be 0.
+func (u *unlockSchedule) add(assetID ids.ID, out verify.State, stakingEnd uint64) {
+	var stakeableLocktime uint64
+	if lockedOut, ok := out.(*stakeable.LockOut); ok {
+		stakeableLocktime = lockedOut.Locktime
+		out = lockedOut.TransferableOut
+	}
+	secpOut, ok := out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		return
+	}
+
+	// Only outputs owned by one of the given addresses are reported.
+	owned := false
+	for _, addr := range secpOut.Addrs {
+		if u.addrs.Contains(addr) {
+			owned = true
+			break
+		}
+	}
+	if !owned {
+		return
+	}
+
+	key := unlockScheduleKey{
+		time:      secpOut.Locktime,
+		reason:    unlockReasonLocktime,
+		assetID:   assetID,
+		threshold: secpOut.Threshold,
+	}
+	if stakeableLocktime > key.time {
+		key.time = stakeableLocktime
+		key.reason = unlockReasonStakeableLocktime
+	}
+	if stakingEnd > key.time {
+		key.time = stakingEnd
+		key.reason = unlockReasonStakingEnd
+	}
+	if key.time <= u.currentTime {
+		return
+	}
+
+	addrsBytes := make([]byte, 0, len(secpOut.Addrs)*ids.ShortIDLen)
+	for _, addr := range secpOut.Addrs {
+		addrsBytes = append(addrsBytes, addr[:]...)
+	}
+	key.addrs = string(addrsBytes)
+
+	newAmount, err := safemath.Add(u.amounts[key], secpOut.Amt)
+	if err != nil {
+		newAmount = math.MaxUint64
+	}
+	u.amounts[key] = newAmount
+	u.owners[key] = &secpOut.OutputOwners
+}
+
+// GetUnlockSchedule returns when the currently locked funds of [args.Addresses]
+// become spendable, either because a locktime expires or because the staking
+// period they are staked in ends.
+//
+// Potential staking rewards aren't included.
+func (s *Service) GetUnlockSchedule(_ *http.Request, args *api.JSONAddresses, reply *GetUnlockScheduleReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getUnlockSchedule"),
+		logging.UserStrings("addresses", args.Addresses),
+	)
+
+	if len(args.Addresses) == 0 {
+		return errNoAddresses
+	}
+	if len(args.Addresses) > maxGetStakeAddrs {
+		return fmt.Errorf("%d addresses provided but this method can take at most %d", len(args.Addresses), maxGetStakeAddrs)
+	}
+
+	addrs, err := avax.ParseServiceAddresses(s.addrManager, args.Addresses)
+	if err != nil {
+		return err
+	}
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	schedule := unlockSchedule{
+		addrs:       addrs,
+		currentTime: uint64(s.vm.state.GetTimestamp().Unix()),
+		amounts:     make(map[unlockScheduleKey]uint64),
+		owners:      make(map[unlockScheduleKey]*secp256k1fx.OutputOwners),
+	}
+
+	utxos, err := avax.GetAllUTXOs(s.vm.state, addrs)
+	if err != nil {
+		return fmt.Errorf("couldn't get UTXO set of %v: %w", args.Addresses, err)
+	}
+	for _, utxo := range utxos {
+		schedule.add(utxo.AssetID(), utxo.Out, 0)
+	}
+
+	currentStakerIterator, err := s.vm.state.GetCurrentStakerIterator()
+	if err != nil {
+		return err
+	}
+	defer currentStakerIterator.Release()
+
+	pendingStakerIterator, err := s.vm.state.GetPendingStakerIterator()
+	if err != nil {
+		return err
+	}
+	defer pendingStakerIterator.Release()
+
+	for _, stakerIterator := range []state.StakerIterator{currentStakerIterator, pendingStakerIterator} {
+		for stakerIterator.Next() {
+			staker := stakerIterator.Value()
+			tx, _, err := s.vm.state.GetTx(staker.TxID)
+			if err != nil {
+				return err
+			}
+
+			stakerTx, ok := tx.Unsigned.(txs.PermissionlessStaker)
+			if !ok {
+				continue
+			}
+			stakingEnd := uint64(staker.EndTime.Unix())
+			for _, out := range stakerTx.Stake() {
+				schedule.add(out.AssetID(), out.Out, stakingEnd)
+			}
+		}
+	}
+
+	sortedKeys := make([]unlockScheduleKey, 0, len(schedule.amounts))
+	for key := range schedule.amounts {
+		sortedKeys = append(sortedKeys, key)
+	}
+	slices.SortFunc(sortedKeys, unlockScheduleKey.Compare)
+
+	reply.CurrentTime = avajson.Uint64(schedule.currentTime)
+	reply.Schedule = make([]UnlockScheduleEntry, len(sortedKeys))
+	for i, key := range sortedKeys {
+		owner := schedule.owners[key]
+		addresses := make([]string, len(owner.Addrs))
+		for j, addr := range owner.Addrs {
+			addresses[j], err = s.addrManager.FormatLocalAddress(addr)
+			if err != nil {
+				return fmt.Errorf("couldn't format address: %w", err)
+			}
+		}
+
+		reply.Schedule[i] = UnlockScheduleEntry{
+			Time:      avajson.Uint64(key.time),
+			Reason:    key.reason,
+			AssetID:   key.assetID,
+			Amount:    avajson.Uint64(schedule.amounts[key]),
+			Threshold: avajson.Uint32(owner.Threshold),
+			Addresses: addresses,
+		}
+	}
+	return nil
+}
+
 // GetMinStakeArgs are the arguments for calling GetMinStake.
 type GetMinStakeArgs struct {
 	SubnetID ids.ID `json:"subnetID"`
@@ -1641,6 +2377,185 @@ func (s *Service) GetRewardUTXOs(_ *http.Request, args *api.GetTxArgs, reply *Ge
 	return nil
 }
 
+// RewardHistoryIndex is the position of a record in the reward history of a
+// node ID or address
+type RewardHistoryIndex struct {
+	Height avajson.Uint64 `json:"height"`
+	TxID   ids.ID         `json:"txID"`
+}
+
+// GetRewardHistoryArgs are the arguments for calling GetRewardHistory
+type GetRewardHistoryArgs struct {
+	// Node ID to fetch the staking periods of
+	// Exactly one of [NodeID] and [Address] must be provided.
+	NodeID ids.NodeID `json:"nodeID"`
+	// Address to fetch the rewarded staking periods of
+	// Exactly one of [NodeID] and [Address] must be provided.
+	Address string `json:"address"`
+	// The records are returned starting after [StartIndex]
+	StartIndex RewardHistoryIndex `json:"startIndex"`
+	// Max number of records to return
+	Limit avajson.Uint32 `json:"limit"`
+	// Encoding specifies the encoding format the reward UTXOs are returned in
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// APIRewardRecord is the API representation of the outcome of a staking
+// period
+type APIRewardRecord struct {
+	TxID        ids.ID         `json:"txID"`
+	RewardTxID  ids.ID         `json:"rewardTxID"`
+	Height      avajson.Uint64 `json:"height"`
+	SubnetID    ids.ID         `json:"subnetID"`
+	NodeID      ids.NodeID     `json:"nodeID"`
+	IsDelegator bool           `json:"isDelegator"`
+	StartTime   avajson.Uint64 `json:"startTime"`
+	EndTime     avajson.Uint64 `json:"endTime"`
+	Weight      avajson.Uint64 `json:"weight"`
+	Rewarded    bool           `json:"rewarded"`
+	// Reward paid to the rewards owner of the staker
+	Reward avajson.Uint64 `json:"reward"`
+	// Reward the staker would have been paid if it had been rewarded
+	ForfeitedReward avajson.Uint64 `json:"forfeitedReward"`
+	// For validators, the delegation fees earned from their delegators. For
+	// delegators, the delegation fee paid to their validator.
+	DelegationFee avajson.Uint64 `json:"delegationFee"`
+	// Addresses of the owners of the rewards of the staker
+	RewardOwners []string `json:"rewardOwners"`
+	// String representation of the reward UTXOs
+	// Each is of type avax.UTXO
+	RewardUTXOs []string `json:"rewardUTXOs"`
+}
+
+// GetRewardHistoryReply is the response from calling GetRewardHistory
+type GetRewardHistoryReply struct {
+	// Number of records returned
+	NumFetched avajson.Uint64 `json:"numFetched"`
+	// The staking periods, ordered by the height they ended at
+	Records []APIRewardRecord `json:"records"`
+	// The index of the last returned record. Can be used as the [StartIndex]
+	// of the next call to fetch the next page.
+	EndIndex RewardHistoryIndex `json:"endIndex"`
+	// True if the staking periods that ended before the reward history was
+	// enabled have been indexed. Until then, they are missing from [Records].
+	BackfillComplete bool `json:"backfillComplete"`
+	// Encoding specifies the encoding format the reward UTXOs are returned in
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetRewardHistory returns the staking periods of a node ID, or the staking
+// periods that rewarded an address, along with their reward UTXOs.
+//
+// Requires the reward history to be indexed.
+func (s *Service) GetRewardHistory(_ *http.Request, args *GetRewardHistoryArgs, reply *GetRewardHistoryReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getRewardHistory"),
+		zap.Stringer("nodeID", args.NodeID),
+		zap.String("address", args.Address),
+	)
+
+	if (args.NodeID == ids.EmptyNodeID) == (args.Address == "") {
+		return errNodeIDOrAddress
+	}
+
+	limit := int(args.Limit)
+	if limit <= 0 || limit > maxPageSize {
+		limit = maxPageSize
+	}
+	start := state.RewardHistoryIndex{
+		Height: uint64(args.StartIndex.Height),
+		TxID:   args.StartIndex.TxID,
+	}
+
+	var addr ids.ShortID
+	if args.Address != "" {
+		var err error
+		addr, err = avax.ParseServiceAddress(s.addrManager, args.Address)
+		if err != nil {
+			return fmt.Errorf("couldn't parse address %q: %w", args.Address, err)
+		}
+	}
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	var (
+		records []*state.RewardRecord
+		err     error
+	)
+	if args.Address != "" {
+		records, err = s.vm.state.GetAddressRewardHistory(addr, start, limit)
+	} else {
+		records, err = s.vm.state.GetNodeIDRewardHistory(args.NodeID, start, limit)
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't get reward history: %w", err)
+	}
+	reply.BackfillComplete, err = s.vm.state.IsRewardHistoryBackfilled()
+	if err != nil {
+		return fmt.Errorf("couldn't get whether the reward history is backfilled: %w", err)
+	}
+
+	reply.NumFetched = avajson.Uint64(len(records))
+	reply.Records = make([]APIRewardRecord, len(records))
+	for i, record := range records {
+		rewardOwners := make([]string, len(record.Addresses))
+		for j, rewardOwner := range record.Addresses {
+			rewardOwners[j], err = s.addrManager.FormatLocalAddress(rewardOwner)
+			if err != nil {
+				return fmt.Errorf("couldn't format address: %w", err)
+			}
+		}
+
+		utxos, err := s.vm.state.GetRewardUTXOs(record.TxID)
+		if err != nil {
+			return fmt.Errorf("couldn't get reward UTXOs: %w", err)
+		}
+		rewardUTXOs := make([]string, len(utxos))
+		for j, utxo := range utxos {
+			utxoBytes, err := txs.GenesisCodec.Marshal(txs.CodecVersion, utxo)
+			if err != nil {
+				return fmt.Errorf("couldn't encode UTXO to bytes: %w", err)
+			}
+
+			rewardUTXOs[j], err = formatting.Encode(args.Encoding, utxoBytes)
+			if err != nil {
+				return fmt.Errorf("couldn't encode utxo as %s: %w", args.Encoding, err)
+			}
+		}
+
+		reply.Records[i] = APIRewardRecord{
+			TxID:            record.TxID,
+			RewardTxID:      record.RewardTxID,
+			Height:          avajson.Uint64(record.Height),
+			SubnetID:        record.SubnetID,
+			NodeID:          record.NodeID,
+			IsDelegator:     record.IsDelegator,
+			StartTime:       avajson.Uint64(record.StartTime),
+			EndTime:         avajson.Uint64(record.EndTime),
+			Weight:          avajson.Uint64(record.Weight),
+			Rewarded:        record.Rewarded,
+			Reward:          avajson.Uint64(record.Reward),
+			ForfeitedReward: avajson.Uint64(record.ForfeitedReward),
+			DelegationFee:   avajson.Uint64(record.DelegationFee),
+			RewardOwners:    rewardOwners,
+			RewardUTXOs:     rewardUTXOs,
+		}
+	}
+
+	reply.EndIndex = args.StartIndex
+	if len(records) > 0 {
+		lastRecord := records[len(records)-1]
+		reply.EndIndex = RewardHistoryIndex{
+			Height: avajson.Uint64(lastRecord.Height),
+			TxID:   lastRecord.TxID,
+		}
+	}
+	reply.Encoding = args.Encoding
+	return nil
+}
+
 // GetTimestampReply is the response from GetTimestamp
 type GetTimestampReply struct {
 	// Current timestamp
@@ -1679,12 +2594,10 @@ func (v *GetValidatorsAtReply) MarshalJSON() ([]byte, error) {
 			Weight: avajson.Uint64(vdr.Weight),
 		}
 
-		if vdr.PublicKey != nil {
-			pk, err := formatting.Encode(formatting.HexNC, bls.PublicKeyToCompressedBytes(vdr.PublicKey))
-			if err != nil {
-				return nil, err
-			}
-			vdrJSON.PublicKey = &pk
+		var err error
+		vdrJSON.PublicKey, err = encodePublicKey(vdr.PublicKey)
+		if err != nil {
+			return nil, err
 		}
 
 		m[vdr.NodeID] = vdrJSON
@@ -1754,6 +2667,283 @@ func (s *Service) GetValidatorsAt(r *http.Request, args *GetValidatorsAtArgs, re
 	return nil
 }
 
+// GetValidatorSetChangesArgs are the arguments for GetValidatorSetChanges
+type GetValidatorSetChangesArgs struct {
+	SubnetID ids.ID `json:"subnetID"`
+	// Height of the validator set the changes are relative to
+	StartHeight avajson.Uint64 `json:"startHeight"`
+	// Height of the validator set after the changes
+	EndHeight avajson.Uint64 `json:"endHeight"`
+	// If provided, only validators with a node ID greater than [StartNodeID]
+	// are returned. Used for pagination.
+	StartNodeID ids.NodeID `json:"startNodeID"`
+	// Maximum number of changed validators to return
+	Limit avajson.Uint32 `json:"limit"`
+}
+
+// APIValidatorChange describes how a validator changed between two heights
+type APIValidatorChange struct {
+	NodeID            ids.NodeID     `json:"nodeID"`
+	PreviousWeight    avajson.Uint64 `json:"previousWeight"`
+	Weight            avajson.Uint64 `json:"weight"`
+	PreviousPublicKey *string        `json:"previousPublicKey"`
+	PublicKey         *string        `json:"publicKey"`
+}
+
+// GetValidatorSetChangesReply is the response from GetValidatorSetChanges
+type GetValidatorSetChangesReply struct {
+	// Validators that were not in the validator set at the start height
+	Added []APIValidatorChange `json:"added"`
+	// Validators that are not in the validator set at the end height
+	Removed []APIValidatorChange `json:"removed"`
+	// Validators whose weight or public key changed
+	Modified []APIValidatorChange `json:"modified"`
+	// Number of changed validators returned
+	NumFetched avajson.Uint64 `json:"numFetched"`
+	// Last node ID that was considered. If [NumFetched] is equal to the
+	// provided limit, the next page can be fetched by using this value as the
+	// start node ID.
+	EndNodeID ids.NodeID `json:"endNodeID"`
+}
+
+// GetValidatorSetChanges returns the changes between the validator sets of a
+// provided subnet at the start and end heights.
+func (s *Service) GetValidatorSetChanges(r *http.Request, args *GetValidatorSetChangesArgs, reply *GetValidatorSetChangesReply) error {
+	startHeight := uint64(args.StartHeight)
+	endHeight := uint64(args.EndHeight)
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getValidatorSetChanges"),
+		zap.Stringer("subnetID", args.SubnetID),
+		zap.Uint64("startHeight", startHeight),
+		zap.Uint64("endHeight", endHeight),
+	)
+
+	if startHeight > endHeight {
+		return fmt.Errorf("%w: %d > %d", errInvalidHeightRange, startHeight, endHeight)
+	}
+
+	limit := int(args.Limit)
+	if limit <= 0 || maxPageSize < limit {
+		limit = maxPageSize
+	}
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	ctx := r.Context()
+	currentHeight, err := s.vm.GetCurrentHeight(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current height: %w", err)
+	}
+	if endHeight > currentHeight {
+		return fmt.Errorf("%w: %d > %d", errUnfinalizedHeight, endHeight, currentHeight)
+	}
+
+	reply.Added = []APIValidatorChange{}
+	reply.Removed = []APIValidatorChange{}
+	reply.Modified = []APIValidatorChange{}
+	reply.EndNodeID = args.StartNodeID
+	for int(reply.NumFetched) < limit {
+		// Only the validators that may be returned are fetched, so that the
+		// validator sets don't need to be rebuilt for every page.
+		pageSize := limit - int(reply.NumFetched)
+		nodeIDs, err := s.vm.state.GetModifiedValidators(
+			ctx,
+			args.SubnetID,
+			startHeight+1,
+			endHeight,
+			reply.EndNodeID,
+			pageSize,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to get modified validators: %w", err)
+		}
+		if len(nodeIDs) == 0 {
+			return nil
+		}
+
+		changes, err := s.getValidatorChanges(ctx, args.SubnetID, nodeIDs, startHeight, endHeight, currentHeight)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			switch {
+			case change.PreviousWeight == change.Weight &&
+				equalPublicKeys(change.PreviousPublicKey, change.PublicKey):
+				// The validator was modified and then reverted to its original
+				// state.
+				continue
+			case change.PreviousWeight == 0:
+				reply.Added = append(reply.Added, change)
+			case change.Weight == 0:
+				reply.Removed = append(reply.Removed, change)
+			default:
+				reply.Modified = append(reply.Modified, change)
+			}
+			reply.NumFetched++
+		}
+		reply.EndNodeID = nodeIDs[len(nodeIDs)-1]
+
+		if len(nodeIDs) < pageSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// getValidatorChanges returns the weights and public keys on [subnetID] of the
+// nodes in [nodeIDs] at [startHeight] and [endHeight].
+//
+// The validators are rebuilt from their state at [currentHeight] by applying
+// only their own diffs.
+func (s *Service) getValidatorChanges(
+	ctx context.Context,
+	subnetID ids.ID,
+	nodeIDs []ids.NodeID,
+	startHeight uint64,
+	endHeight uint64,
+	currentHeight uint64,
+) ([]APIValidatorChange, error) {
+	var (
+		nodeIDSet         = set.Of(nodeIDs...)
+		subnetValidators  = make(map[ids.NodeID]*validators.GetValidatorOutput, len(nodeIDs))
+		primaryValidators = make(map[ids.NodeID]*validators.GetValidatorOutput, len(nodeIDs))
+	)
+	for _, nodeID := range nodeIDs {
+		vdr := &validators.GetValidatorOutput{
+			NodeID: nodeID,
+		}
+		if currentVdr, ok := s.vm.Validators.GetValidator(subnetID, nodeID); ok {
+			vdr.PublicKey = currentVdr.PublicKey
+			vdr.Weight = currentVdr.Weight
+		}
+		subnetValidators[nodeID] = vdr
+
+		if subnetID == constants.PrimaryNetworkID {
+			continue
+		}
+
+		// Legacy subnet validators don't register their own public keys, so
+		// their primary network public keys are tracked separately.
+		primaryVdr := &validators.GetValidatorOutput{
+			NodeID: nodeID,
+		}
+		if currentVdr, ok := s.vm.Validators.GetValidator(constants.PrimaryNetworkID, nodeID); ok {
+			primaryVdr.PublicKey = currentVdr.PublicKey
+		}
+		primaryValidators[nodeID] = primaryVdr
+	}
+
+	changes := make([]APIValidatorChange, len(nodeIDs))
+	err := s.rewindValidators(ctx, subnetID, nodeIDSet, subnetValidators, primaryValidators, currentHeight, endHeight)
+	if err != nil {
+		return nil, err
+	}
+	for i, nodeID := range nodeIDs {
+		vdr := subnetValidators[nodeID]
+		changes[i].NodeID = nodeID
+		changes[i].Weight = avajson.Uint64(vdr.Weight)
+		changes[i].PublicKey, err = encodePublicKey(validatorPublicKey(vdr, primaryValidators[nodeID]))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.rewindValidators(ctx, subnetID, nodeIDSet, subnetValidators, primaryValidators, endHeight, startHeight)
+	if err != nil {
+		return nil, err
+	}
+	for i, nodeID := range nodeIDs {
+		vdr := subnetValidators[nodeID]
+		changes[i].PreviousWeight = avajson.Uint64(vdr.Weight)
+		changes[i].PreviousPublicKey, err = encodePublicKey(validatorPublicKey(vdr, primaryValidators[nodeID]))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// rewindValidators converts the validators at [fromHeight] into the validators
+// at [toHeight].
+func (s *Service) rewindValidators(
+	ctx context.Context,
+	subnetID ids.ID,
+	nodeIDs set.Set[ids.NodeID],
+	subnetValidators map[ids.NodeID]*validators.GetValidatorOutput,
+	primaryValidators map[ids.NodeID]*validators.GetValidatorOutput,
+	fromHeight uint64,
+	toHeight uint64,
+) error {
+	// Because the state interface is implemented to be inclusive, we apply
+	// diffs in [toHeight + 1, fromHeight].
+	lastDiffHeight := toHeight + 1
+	weightDiffs, err := s.vm.state.GetValidatorWeightDiffs(ctx, nodeIDs, fromHeight, lastDiffHeight, subnetID)
+	if err != nil {
+		return fmt.Errorf("failed to get validator weight diffs: %w", err)
+	}
+	for nodeID, weightDiff := range weightDiffs {
+		vdr := subnetValidators[nodeID]
+		if weightDiff.Decrease {
+			// The validator's weight was decreased, so it was higher at
+			// [toHeight].
+			vdr.Weight, err = safemath.Add(vdr.Weight, weightDiff.Amount)
+		} else {
+			// The validator's weight was increased, so it was lower at
+			// [toHeight].
+			vdr.Weight, err = safemath.Sub(vdr.Weight, weightDiff.Amount)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.vm.state.ApplyValidatorPublicKeyDiffs(ctx, subnetValidators, fromHeight, lastDiffHeight, subnetID)
+	if err != nil {
+		return fmt.Errorf("failed to apply validator public key diffs: %w", err)
+	}
+	if subnetID == constants.PrimaryNetworkID {
+		return nil
+	}
+	err = s.vm.state.ApplyValidatorPublicKeyDiffs(ctx, primaryValidators, fromHeight, lastDiffHeight, constants.PrimaryNetworkID)
+	if err != nil {
+		return fmt.Errorf("failed to apply primary network public key diffs: %w", err)
+	}
+	return nil
+}
+
+// validatorPublicKey returns the public key of [subnetVdr]. If [subnetVdr] is a
+// legacy subnet validator, the public key of [primaryVdr] is used.
+func validatorPublicKey(subnetVdr, primaryVdr *validators.GetValidatorOutput) *bls.PublicKey {
+	switch {
+	case subnetVdr.Weight == 0:
+		return nil
+	case subnetVdr.PublicKey != nil || primaryVdr == nil:
+		return subnetVdr.PublicKey
+	default:
+		return primaryVdr.PublicKey
+	}
+}
+
+func encodePublicKey(pk *bls.PublicKey) (*string, error) {
+	if pk == nil {
+		return nil, nil
+	}
+	pkStr, err := formatting.Encode(formatting.HexNC, bls.PublicKeyToCompressedBytes(pk))
+	if err != nil {
+		return nil, err
+	}
+	return &pkStr, nil
+}
+
+func equalPublicKeys(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func (s *Service) GetBlock(_ *http.Request, args *api.GetBlockArgs, response *api.GetBlockResponse) error {
 	s.vm.ctx.Log.Debug("API called",
 		zap.String("service", "platform"),
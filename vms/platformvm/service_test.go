@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net/http"
+	"slices"
 	"testing"
 	"time"
 
@@ -34,7 +36,9 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/platformvm/block"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
+	"github.com/ava-labs/avalanchego/vms/platformvm/stakeable"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
@@ -592,6 +596,88 @@ func TestGetStake(t *testing.T) {
 	require.Equal(stakeAmount+oldStake, outputs[0].Out.Amount()+outputs[1].Out.Amount()+outputs[2].Out.Amount())
 }
 
+func TestGetUnlockSchedule(t *testing.T) {
+	require := require.New(t)
+	service, _, _ := defaultService(t)
+
+	var (
+		addr  = keys[0].Address()
+		owner = secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{addr},
+		}
+	)
+	addrStr, err := service.addrManager.FormatLocalAddress(addr)
+	require.NoError(err)
+
+	service.vm.ctx.Lock.Lock()
+	currentTime := uint64(service.vm.state.GetTimestamp().Unix())
+	validator, err := service.vm.state.GetCurrentValidator(constants.PrimaryNetworkID, genesisNodeIDs[0])
+	require.NoError(err)
+
+	lockedOwner := owner
+	lockedOwner.Locktime = currentTime + 1
+	service.vm.state.AddUTXO(&avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: service.vm.ctx.AVAXAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt:          1,
+			OutputOwners: lockedOwner,
+		},
+	})
+	service.vm.state.AddUTXO(&avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: service.vm.ctx.AVAXAssetID},
+		Out: &stakeable.LockOut{
+			Locktime: currentTime + 2,
+			TransferableOut: &secp256k1fx.TransferOutput{
+				Amt:          2,
+				OutputOwners: owner,
+			},
+		},
+	})
+	require.NoError(service.vm.state.Commit())
+	service.vm.ctx.Lock.Unlock()
+
+	reply := GetUnlockScheduleReply{}
+	require.NoError(service.GetUnlockSchedule(nil, &api.JSONAddresses{
+		Addresses: []string{addrStr},
+	}, &reply))
+	require.Equal(avajson.Uint64(currentTime), reply.CurrentTime)
+	require.Equal(
+		[]UnlockScheduleEntry{
+			{
+				Time:      avajson.Uint64(currentTime + 1),
+				Reason:    unlockReasonLocktime,
+				AssetID:   service.vm.ctx.AVAXAssetID,
+				Amount:    1,
+				Threshold: 1,
+				Addresses: []string{addrStr},
+			},
+			{
+				Time:      avajson.Uint64(currentTime + 2),
+				Reason:    unlockReasonStakeableLocktime,
+				AssetID:   service.vm.ctx.AVAXAssetID,
+				Amount:    2,
+				Threshold: 1,
+				Addresses: []string{addrStr},
+			},
+			{
+				Time:      avajson.Uint64(validator.EndTime.Unix()),
+				Reason:    unlockReasonStakingEnd,
+				AssetID:   service.vm.ctx.AVAXAssetID,
+				Amount:    avajson.Uint64(defaultWeight),
+				Threshold: 1,
+				Addresses: []string{addrStr},
+			},
+		},
+		reply.Schedule,
+	)
+
+	err = service.GetUnlockSchedule(nil, &api.JSONAddresses{}, &reply)
+	require.ErrorIs(err, errNoAddresses)
+}
+
 func TestGetCurrentValidators(t *testing.T) {
 	require := require.New(t)
 	service, _, factory := defaultService(t)
@@ -727,6 +813,114 @@ func TestGetCurrentValidators(t *testing.T) {
 	}
 }
 
+func TestEstimateReward(t *testing.T) {
+	service, _, _ := defaultService(t)
+
+	service.vm.ctx.Lock.Lock()
+	currentSupply, err := service.vm.state.GetCurrentSupply(constants.PrimaryNetworkID)
+	service.vm.ctx.Lock.Unlock()
+	require.NoError(t, err)
+
+	var (
+		stakeAmount    = 2 * defaultWeight
+		duration       = defaultMinStakingDuration
+		calculator     = reward.NewCalculator(service.vm.RewardConfig)
+		expectedReward = calculator.Calculate(duration, stakeAmount, currentSupply)
+	)
+
+	tests := []struct {
+		name                    string
+		args                    EstimateRewardArgs
+		expectedErr             error
+		expectedDelegationFee   avajson.Float32
+		expectedDelegateeReward uint64
+	}{
+		{
+			name: "validator",
+			args: EstimateRewardArgs{
+				StakeAmount:   avajson.Uint64(stakeAmount),
+				Duration:      avajson.Uint64(duration / time.Second),
+				DelegationFee: 2,
+			},
+			expectedDelegationFee:   2,
+			expectedDelegateeReward: expectedReward - expectedReward*98/100,
+		},
+		{
+			name: "delegation to validator",
+			args: EstimateRewardArgs{
+				StakeAmount:   avajson.Uint64(stakeAmount),
+				Duration:      avajson.Uint64(duration / time.Second),
+				DelegationFee: 2,
+				NodeID:        genesisNodeIDs[0],
+			},
+			// The genesis validators don't specify an exact delegation fee,
+			// so the provided fee is ignored in favor of the validator's 0%.
+			expectedDelegationFee:   0,
+			expectedDelegateeReward: 0,
+		},
+		{
+			name: "no stake amount",
+			args: EstimateRewardArgs{
+				Duration: avajson.Uint64(duration / time.Second),
+			},
+			expectedErr: errNoStakeAmount,
+		},
+		{
+			name: "invalid delegation fee",
+			args: EstimateRewardArgs{
+				StakeAmount:   avajson.Uint64(stakeAmount),
+				Duration:      avajson.Uint64(duration / time.Second),
+				DelegationFee: 101,
+			},
+			expectedErr: errInvalidDelegationFee,
+		},
+		{
+			name: "duration too short",
+			args: EstimateRewardArgs{
+				StakeAmount: avajson.Uint64(stakeAmount),
+				Duration:    1,
+			},
+			expectedErr: errInvalidStakeDuration,
+		},
+		{
+			name: "delegation ends after validator",
+			args: EstimateRewardArgs{
+				StakeAmount: avajson.Uint64(stakeAmount),
+				Duration:    avajson.Uint64(defaultMaxStakingDuration / time.Second),
+				NodeID:      genesisNodeIDs[0],
+			},
+			expectedErr: errDelegationEndsAfterValidator,
+		},
+		{
+			name: "unknown validator",
+			args: EstimateRewardArgs{
+				StakeAmount: avajson.Uint64(stakeAmount),
+				Duration:    avajson.Uint64(duration / time.Second),
+				NodeID:      ids.GenerateTestNodeID(),
+			},
+			expectedErr: database.ErrNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			reply := EstimateRewardReply{}
+			err := service.EstimateReward(nil, &test.args, &reply)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				return
+			}
+
+			require.Equal(avajson.Uint64(currentSupply), reply.CurrentSupply)
+			require.Equal(avajson.Uint64(expectedReward), reply.Reward)
+			require.Equal(test.expectedDelegationFee, reply.DelegationFee)
+			require.Equal(avajson.Uint64(test.expectedDelegateeReward), reply.DelegateeReward)
+			require.Equal(avajson.Uint64(expectedReward-test.expectedDelegateeReward), reply.DelegatorReward)
+		})
+	}
+}
+
 func TestGetTimestamp(t *testing.T) {
 	require := require.New(t)
 	service, _, _ := defaultService(t)
@@ -864,6 +1058,311 @@ func TestGetValidatorsAtReplyMarshalling(t *testing.T) {
 	require.Equal(reply, &parsedReply)
 }
 
+func TestGetRewardHistory(t *testing.T) {
+	service, _, _ := defaultService(t)
+
+	tests := []struct {
+		name        string
+		args        GetRewardHistoryArgs
+		expectedErr error
+	}{
+		{
+			name:        "no node ID or address",
+			args:        GetRewardHistoryArgs{},
+			expectedErr: errNodeIDOrAddress,
+		},
+		{
+			name: "node ID and address",
+			args: GetRewardHistoryArgs{
+				NodeID:  genesisNodeIDs[0],
+				Address: testAddress,
+			},
+			expectedErr: errNodeIDOrAddress,
+		},
+		{
+			name: "not indexed",
+			args: GetRewardHistoryArgs{
+				NodeID: genesisNodeIDs[0],
+			},
+			expectedErr: state.ErrRewardHistoryNotIndexed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reply := GetRewardHistoryReply{}
+			err := service.GetRewardHistory(nil, &test.args, &reply)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestGetObservedUptimes(t *testing.T) {
+	require := require.New(t)
+	service, _, _ := defaultService(t)
+
+	connectedNodeID := genesisNodeIDs[0]
+	service.vm.ctx.Lock.Lock()
+	require.NoError(service.vm.uptimeManager.Connect(connectedNodeID, constants.PrimaryNetworkID))
+	connectedTime := service.vm.clock.UnixTime()
+	service.vm.ctx.Lock.Unlock()
+
+	reply := GetObservedUptimesReply{}
+	require.NoError(service.GetObservedUptimes(nil, &GetObservedUptimesArgs{}, &reply))
+	require.NotNil(reply.UptimeRequirement)
+	require.Equal(avajson.Float32(service.vm.UptimePercentage*100), *reply.UptimeRequirement)
+	require.Len(reply.Validators, len(genesisNodeIDs))
+	require.True(slices.IsSortedFunc(reply.Validators, func(a, b APIObservedUptime) int {
+		return a.NodeID.Compare(b.NodeID)
+	}))
+
+	for _, vdr := range reply.Validators {
+		require.NotNil(vdr.Uptime)
+		require.NotNil(vdr.MeetsRewardThreshold)
+		require.Zero(vdr.LastDisconnected)
+		if vdr.NodeID == connectedNodeID {
+			require.True(vdr.Connected)
+			require.Equal(avajson.Uint64(connectedTime.Unix()), vdr.LastConnected)
+		} else {
+			require.False(vdr.Connected)
+			require.Zero(vdr.LastConnected)
+		}
+	}
+
+	// Validators of permissioned subnets aren't rewarded.
+	reply = GetObservedUptimesReply{}
+	require.NoError(service.GetObservedUptimes(nil, &GetObservedUptimesArgs{
+		SubnetID: ids.GenerateTestID(),
+	}, &reply))
+	require.Nil(reply.UptimeRequirement)
+	require.Empty(reply.Validators)
+}
+
+func TestSimulateTx(t *testing.T) {
+	require := require.New(t)
+	service, _, factory := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+
+	var (
+		startTime = service.vm.clock.Time().Add(txexecutor.SyncBound).Add(time.Second)
+		endTime   = startTime.Add(defaultMinStakingDuration)
+		nodeID    = ids.GenerateTestNodeID()
+		owner     = &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+		}
+	)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+
+	builder, txSigner := factory.NewWallet(keys[0])
+	utx, err := builder.NewAddPermissionlessValidatorTx(
+		&txs.SubnetValidator{
+			Validator: txs.Validator{
+				NodeID: nodeID,
+				Start:  uint64(startTime.Unix()),
+				End:    uint64(endTime.Unix()),
+				Wght:   service.vm.MinValidatorStake,
+			},
+			Subnet: constants.PrimaryNetworkID,
+		},
+		signer.NewProofOfPossession(sk),
+		service.vm.ctx.AVAXAssetID,
+		owner,
+		owner,
+		reward.PercentDenominator,
+	)
+	require.NoError(err)
+	tx, err := walletsigner.SignUnsigned(context.Background(), txSigner, utx)
+	require.NoError(err)
+	service.vm.ctx.Lock.Unlock()
+
+	txStr, err := formatting.Encode(formatting.Hex, tx.Bytes())
+	require.NoError(err)
+	args := &api.FormattedTx{
+		Tx:       txStr,
+		Encoding: formatting.Hex,
+	}
+
+	reply := SimulateTxReply{}
+	require.NoError(service.SimulateTx(nil, args, &reply))
+	require.True(reply.Valid, reply.Error)
+	require.Equal(tx.ID(), reply.TxID)
+	require.Equal(avajson.Uint64(service.vm.StaticFeeConfig.AddPrimaryNetworkValidatorFee), reply.Fee)
+	require.Len(reply.ConsumedUTXOs, len(utx.Ins))
+	require.Len(reply.ProducedUTXOs, len(utx.Outs))
+	require.Empty(reply.RemovedStakers)
+	require.Equal(
+		[]APISimulatedStaker{
+			{
+				TxID:            tx.ID(),
+				NodeID:          nodeID,
+				SubnetID:        constants.PrimaryNetworkID,
+				Weight:          avajson.Uint64(service.vm.MinValidatorStake),
+				StartTime:       reply.AddedStakers[0].StartTime,
+				EndTime:         avajson.Uint64(endTime.Unix()),
+				PotentialReward: reply.AddedStakers[0].PotentialReward,
+				IsValidator:     true,
+				IsCurrent:       true,
+			},
+		},
+		reply.AddedStakers,
+	)
+	require.Empty(reply.AddedSubnetOnlyValidators)
+	require.Empty(reply.RemovedSubnetOnlyValidators)
+	require.Empty(reply.ModifiedSubnetOnlyValidators)
+
+	// Simulating the tx must not modify the mempool or the state.
+	_, ok := service.vm.Builder.Get(tx.ID())
+	require.False(ok)
+	service.vm.ctx.Lock.Lock()
+	_, err = service.vm.state.GetCurrentValidator(constants.PrimaryNetworkID, nodeID)
+	service.vm.ctx.Lock.Unlock()
+	require.ErrorIs(err, database.ErrNotFound)
+
+	// After the tx is accepted, its inputs are spent.
+	require.NoError(service.vm.issueTxFromRPC(tx))
+	service.vm.ctx.Lock.Lock()
+	require.NoError(buildAndAcceptStandardBlock(service.vm))
+	service.vm.ctx.Lock.Unlock()
+
+	reply = SimulateTxReply{}
+	require.NoError(service.SimulateTx(nil, args, &reply))
+	require.False(reply.Valid)
+	require.NotEmpty(reply.Error)
+	require.Empty(reply.AddedStakers)
+}
+
+func TestGetValidatorSetChanges(t *testing.T) {
+	service, _, factory := defaultService(t)
+	service.vm.ctx.Lock.Lock()
+
+	var (
+		startTime = service.vm.clock.Time().Add(txexecutor.SyncBound).Add(time.Second)
+		endTime   = startTime.Add(defaultMinStakingDuration)
+		nodeID    = ids.GenerateTestNodeID()
+		owner     = &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+		}
+	)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	pk := bls.PublicFromSecretKey(sk)
+
+	builder, txSigner := factory.NewWallet(keys[0])
+	utx, err := builder.NewAddPermissionlessValidatorTx(
+		&txs.SubnetValidator{
+			Validator: txs.Validator{
+				NodeID: nodeID,
+				Start:  uint64(startTime.Unix()),
+				End:    uint64(endTime.Unix()),
+				Wght:   service.vm.MinValidatorStake,
+			},
+			Subnet: constants.PrimaryNetworkID,
+		},
+		signer.NewProofOfPossession(sk),
+		service.vm.ctx.AVAXAssetID,
+		owner,
+		owner,
+		reward.PercentDenominator,
+	)
+	require.NoError(t, err)
+	tx, err := walletsigner.SignUnsigned(context.Background(), txSigner, utx)
+	require.NoError(t, err)
+
+	service.vm.ctx.Lock.Unlock()
+	require.NoError(t, service.vm.issueTxFromRPC(tx))
+	service.vm.ctx.Lock.Lock()
+	require.NoError(t, buildAndAcceptStandardBlock(service.vm))
+	height, err := service.vm.GetCurrentHeight(context.Background())
+	require.NoError(t, err)
+	service.vm.ctx.Lock.Unlock()
+
+	expectedPK, err := formatting.Encode(formatting.HexNC, bls.PublicKeyToCompressedBytes(pk))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		args             GetValidatorSetChangesArgs
+		expectedErr      error
+		expectedAdded    []APIValidatorChange
+		expectedNumFetch avajson.Uint64
+	}{
+		{
+			name: "validator added",
+			args: GetValidatorSetChangesArgs{
+				SubnetID:    constants.PrimaryNetworkID,
+				StartHeight: avajson.Uint64(height - 1),
+				EndHeight:   avajson.Uint64(height),
+			},
+			expectedAdded: []APIValidatorChange{
+				{
+					NodeID:    nodeID,
+					Weight:    avajson.Uint64(service.vm.MinValidatorStake),
+					PublicKey: &expectedPK,
+				},
+			},
+			expectedNumFetch: 1,
+		},
+		{
+			name: "after start node ID",
+			args: GetValidatorSetChangesArgs{
+				SubnetID:    constants.PrimaryNetworkID,
+				StartHeight: avajson.Uint64(height - 1),
+				EndHeight:   avajson.Uint64(height),
+				StartNodeID: nodeID,
+			},
+			expectedAdded: []APIValidatorChange{},
+		},
+		{
+			name: "no changes",
+			args: GetValidatorSetChangesArgs{
+				SubnetID:    constants.PrimaryNetworkID,
+				StartHeight: avajson.Uint64(height),
+				EndHeight:   avajson.Uint64(height),
+			},
+			expectedAdded: []APIValidatorChange{},
+		},
+		{
+			name: "no changes on subnet",
+			args: GetValidatorSetChangesArgs{
+				SubnetID:    testSubnet1.ID(),
+				StartHeight: avajson.Uint64(height - 1),
+				EndHeight:   avajson.Uint64(height),
+			},
+			expectedAdded: []APIValidatorChange{},
+		},
+		{
+			name: "invalid height range",
+			args: GetValidatorSetChangesArgs{
+				SubnetID:    constants.PrimaryNetworkID,
+				StartHeight: avajson.Uint64(height),
+				EndHeight:   avajson.Uint64(height - 1),
+			},
+			expectedErr: errInvalidHeightRange,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			reply := GetValidatorSetChangesReply{}
+			err := service.GetValidatorSetChanges(&http.Request{}, &test.args, &reply)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				return
+			}
+
+			require.Equal(test.expectedAdded, reply.Added)
+			require.Empty(reply.Removed)
+			require.Empty(reply.Modified)
+			require.Equal(test.expectedNumFetch, reply.NumFetched)
+		})
+	}
+}
+
 func TestServiceGetBlockByHeight(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -6,8 +6,11 @@ package state
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"golang.org/x/exp/maps"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -34,8 +37,9 @@ type diff struct {
 	parentID      ids.ID
 	stateVersions Versions
 
-	timestamp time.Time
-	feeState  fee.State
+	timestamp   time.Time
+	feeState    fee.State
+	accruedFees uint64
 
 	// Subnet ID --> supply of native asset of the subnet
 	currentSupply map[ids.ID]uint64
@@ -45,9 +49,13 @@ type diff struct {
 	modifiedDelegateeRewards map[ids.ID]map[ids.NodeID]uint64
 	pendingStakerDiffs       diffStakers
 
+	subnetOnlyValidatorsDiff *subnetOnlyValidatorsDiff
+
 	addedSubnetIDs []ids.ID
 	// Subnet ID --> Owner of the subnet
 	subnetOwners map[ids.ID]fx.Owner
+	// Subnet ID --> Manager of the subnet
+	subnetManagers map[ids.ID]subnetManager
 	// Subnet ID --> Tx that transforms the subnet
 	transformedSubnets map[ids.ID]*txs.Tx
 
@@ -70,11 +78,14 @@ func NewDiff(
 		return nil, fmt.Errorf("%w: %s", ErrMissingParentState, parentID)
 	}
 	return &diff{
-		parentID:      parentID,
-		stateVersions: stateVersions,
-		timestamp:     parentState.GetTimestamp(),
-		feeState:      parentState.GetFeeState(),
-		subnetOwners:  make(map[ids.ID]fx.Owner),
+		parentID:                 parentID,
+		stateVersions:            stateVersions,
+		timestamp:                parentState.GetTimestamp(),
+		feeState:                 parentState.GetFeeState(),
+		accruedFees:              parentState.GetAccruedFees(),
+		subnetOnlyValidatorsDiff: newSubnetOnlyValidatorsDiff(),
+		subnetOwners:             make(map[ids.ID]fx.Owner),
+		subnetManagers:           make(map[ids.ID]subnetManager),
 	}, nil
 }
 
@@ -108,6 +119,14 @@ func (d *diff) SetFeeState(feeState fee.State) {
 	d.feeState = feeState
 }
 
+func (d *diff) GetAccruedFees() uint64 {
+	return d.accruedFees
+}
+
+func (d *diff) SetAccruedFees(accruedFees uint64) {
+	d.accruedFees = accruedFees
+}
+
 func (d *diff) GetCurrentSupply(subnetID ids.ID) (uint64, error) {
 	supply, ok := d.currentSupply[subnetID]
 	if ok {
@@ -132,6 +151,78 @@ func (d *diff) SetCurrentSupply(subnetID ids.ID, currentSupply uint64) {
 	}
 }
 
+func (d *diff) GetActiveSubnetOnlyValidators() ([]SubnetOnlyValidator, error) {
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingParentState, d.parentID)
+	}
+
+	parentActive, err := parentState.GetActiveSubnetOnlyValidators()
+	if err != nil {
+		return nil, err
+	}
+	return d.subnetOnlyValidatorsDiff.getActive(parentActive), nil
+}
+
+func (d *diff) GetSubnetOnlyValidator(validationID ids.ID) (SubnetOnlyValidator, error) {
+	if sov, modified := d.subnetOnlyValidatorsDiff.modified[validationID]; modified {
+		return sov, nil
+	}
+
+	// If the validator wasn't modified in this diff, ask the parent state.
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return SubnetOnlyValidator{}, fmt.Errorf("%w: %s", ErrMissingParentState, d.parentID)
+	}
+	return parentState.GetSubnetOnlyValidator(validationID)
+}
+
+func (d *diff) GetSubnetOnlyValidators(subnetID ids.ID) ([]SubnetOnlyValidator, error) {
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingParentState, d.parentID)
+	}
+
+	parentSOVs, err := parentState.GetSubnetOnlyValidators(subnetID)
+	if err != nil {
+		return nil, err
+	}
+	return d.subnetOnlyValidatorsDiff.getSubnet(subnetID, parentSOVs), nil
+}
+
+func (d *diff) HasSubnetOnlyValidator(subnetID ids.ID, nodeID ids.NodeID) (bool, error) {
+	if has, modified := d.subnetOnlyValidatorsDiff.hasSubnetOnlyValidator(subnetID, nodeID); modified {
+		return has, nil
+	}
+
+	// If the validator wasn't modified in this diff, ask the parent state.
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrMissingParentState, d.parentID)
+	}
+	return parentState.HasSubnetOnlyValidator(subnetID, nodeID)
+}
+
+func (d *diff) WeightOfSubnetOnlyValidators(subnetID ids.ID) (uint64, error) {
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrMissingParentState, d.parentID)
+	}
+	parentWeight, err := parentState.WeightOfSubnetOnlyValidators(subnetID)
+	if err != nil {
+		return 0, err
+	}
+	return d.subnetOnlyValidatorsDiff.weightOfSubnetOnlyValidators(subnetID, parentWeight)
+}
+
+func (d *diff) PutSubnetOnlyValidator(sov SubnetOnlyValidator) error {
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMissingParentState, d.parentID)
+	}
+	return d.subnetOnlyValidatorsDiff.putSubnetOnlyValidator(parentState, sov)
+}
+
 func (d *diff) GetCurrentValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker, error) {
 	// If the validator was modified in this diff, return the modified
 	// validator.
@@ -151,6 +242,19 @@ func (d *diff) GetCurrentValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker,
 	}
 }
 
+func (d *diff) GetCurrentValidators(subnetID ids.ID) ([]*Staker, error) {
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingParentState, d.parentID)
+	}
+
+	parentValidators, err := parentState.GetCurrentValidators(subnetID)
+	if err != nil {
+		return nil, err
+	}
+	return d.currentStakerDiffs.GetValidators(parentValidators, subnetID), nil
+}
+
 func (d *diff) SetDelegateeReward(subnetID ids.ID, nodeID ids.NodeID, amount uint64) error {
 	if d.modifiedDelegateeRewards == nil {
 		d.modifiedDelegateeRewards = make(map[ids.ID]map[ids.NodeID]uint64)
@@ -305,6 +409,27 @@ func (d *diff) SetSubnetOwner(subnetID ids.ID, owner fx.Owner) {
 	d.subnetOwners[subnetID] = owner
 }
 
+func (d *diff) GetSubnetManager(subnetID ids.ID) (ids.ID, []byte, error) {
+	if manager, exists := d.subnetManagers[subnetID]; exists {
+		return manager.ChainID, manager.Addr, nil
+	}
+
+	// If the subnet manager was not assigned in this diff, ask the parent
+	// state.
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return ids.Empty, nil, ErrMissingParentState
+	}
+	return parentState.GetSubnetManager(subnetID)
+}
+
+func (d *diff) SetSubnetManager(subnetID ids.ID, chainID ids.ID, addr []byte) {
+	d.subnetManagers[subnetID] = subnetManager{
+		ChainID: chainID,
+		Addr:    addr,
+	}
+}
+
 func (d *diff) GetSubnetTransformation(subnetID ids.ID) (*txs.Tx, error) {
 	tx, exists := d.transformedSubnets[subnetID]
 	if exists {
@@ -413,6 +538,7 @@ func (d *diff) DeleteUTXO(utxoID ids.ID) {
 func (d *diff) Apply(baseState Chain) error {
 	baseState.SetTimestamp(d.timestamp)
 	baseState.SetFeeState(d.feeState)
+	baseState.SetAccruedFees(d.accruedFees)
 	for subnetID, supply := range d.currentSupply {
 		baseState.SetCurrentSupply(subnetID, supply)
 	}
@@ -463,6 +589,23 @@ func (d *diff) Apply(baseState Chain) error {
 			}
 		}
 	}
+	// Validators must be removed before new validators with the same subnetID
+	// and nodeID are added.
+	sovs := maps.Values(d.subnetOnlyValidatorsDiff.modified)
+	slices.SortFunc(sovs, func(a, b SubnetOnlyValidator) int {
+		if a.IsRemoved() != b.IsRemoved() {
+			if a.IsRemoved() {
+				return -1
+			}
+			return 1
+		}
+		return a.ValidationID.Compare(b.ValidationID)
+	})
+	for _, sov := range sovs {
+		if err := baseState.PutSubnetOnlyValidator(sov); err != nil {
+			return err
+		}
+	}
 	for _, subnetID := range d.addedSubnetIDs {
 		baseState.AddSubnet(subnetID)
 	}
@@ -492,5 +635,8 @@ func (d *diff) Apply(baseState Chain) error {
 	for subnetID, owner := range d.subnetOwners {
 		baseState.SetSubnetOwner(subnetID, owner)
 	}
+	for subnetID, manager := range d.subnetManagers {
+		baseState.SetSubnetManager(subnetID, manager.ChainID, manager.Addr)
+	}
 	return nil
 }
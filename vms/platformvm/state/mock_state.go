@@ -19,6 +19,7 @@ import (
 	ids "github.com/ava-labs/avalanchego/ids"
 	validators "github.com/ava-labs/avalanchego/snow/validators"
 	logging "github.com/ava-labs/avalanchego/utils/logging"
+	set "github.com/ava-labs/avalanchego/utils/set"
 	avax "github.com/ava-labs/avalanchego/vms/components/avax"
 	fee "github.com/ava-labs/avalanchego/vms/components/fee"
 	block "github.com/ava-labs/avalanchego/vms/platformvm/block"
@@ -183,6 +184,35 @@ func (mr *MockChainMockRecorder) DeleteUTXO(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUTXO", reflect.TypeOf((*MockChain)(nil).DeleteUTXO), arg0)
 }
 
+// GetAccruedFees mocks base method.
+func (m *MockChain) GetAccruedFees() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccruedFees")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetAccruedFees indicates an expected call of GetAccruedFees.
+func (mr *MockChainMockRecorder) GetAccruedFees() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccruedFees", reflect.TypeOf((*MockChain)(nil).GetAccruedFees))
+}
+
+// GetActiveSubnetOnlyValidators mocks base method.
+func (m *MockChain) GetActiveSubnetOnlyValidators() ([]SubnetOnlyValidator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSubnetOnlyValidators")
+	ret0, _ := ret[0].([]SubnetOnlyValidator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSubnetOnlyValidators indicates an expected call of GetActiveSubnetOnlyValidators.
+func (mr *MockChainMockRecorder) GetActiveSubnetOnlyValidators() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSubnetOnlyValidators", reflect.TypeOf((*MockChain)(nil).GetActiveSubnetOnlyValidators))
+}
+
 // GetCurrentDelegatorIterator mocks base method.
 func (m *MockChain) GetCurrentDelegatorIterator(arg0 ids.ID, arg1 ids.NodeID) (StakerIterator, error) {
 	m.ctrl.T.Helper()
@@ -243,6 +273,21 @@ func (mr *MockChainMockRecorder) GetCurrentValidator(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentValidator", reflect.TypeOf((*MockChain)(nil).GetCurrentValidator), arg0, arg1)
 }
 
+// GetCurrentValidators mocks base method.
+func (m *MockChain) GetCurrentValidators(arg0 ids.ID) ([]*Staker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentValidators", arg0)
+	ret0, _ := ret[0].([]*Staker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentValidators indicates an expected call of GetCurrentValidators.
+func (mr *MockChainMockRecorder) GetCurrentValidators(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentValidators", reflect.TypeOf((*MockChain)(nil).GetCurrentValidators), arg0)
+}
+
 // GetDelegateeReward mocks base method.
 func (m *MockChain) GetDelegateeReward(arg0 ids.ID, arg1 ids.NodeID) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -317,6 +362,52 @@ func (mr *MockChainMockRecorder) GetPendingValidator(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingValidator", reflect.TypeOf((*MockChain)(nil).GetPendingValidator), arg0, arg1)
 }
 
+// GetSubnetManager mocks base method.
+func (m *MockChain) GetSubnetManager(arg0 ids.ID) (ids.ID, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetManager", arg0)
+	ret0, _ := ret[0].(ids.ID)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSubnetManager indicates an expected call of GetSubnetManager.
+func (mr *MockChainMockRecorder) GetSubnetManager(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetManager", reflect.TypeOf((*MockChain)(nil).GetSubnetManager), arg0)
+}
+
+// GetSubnetOnlyValidator mocks base method.
+func (m *MockChain) GetSubnetOnlyValidator(arg0 ids.ID) (SubnetOnlyValidator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetOnlyValidator", arg0)
+	ret0, _ := ret[0].(SubnetOnlyValidator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetOnlyValidator indicates an expected call of GetSubnetOnlyValidator.
+func (mr *MockChainMockRecorder) GetSubnetOnlyValidator(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetOnlyValidator", reflect.TypeOf((*MockChain)(nil).GetSubnetOnlyValidator), arg0)
+}
+
+// GetSubnetOnlyValidators mocks base method.
+func (m *MockChain) GetSubnetOnlyValidators(arg0 ids.ID) ([]SubnetOnlyValidator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetOnlyValidators", arg0)
+	ret0, _ := ret[0].([]SubnetOnlyValidator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetOnlyValidators indicates an expected call of GetSubnetOnlyValidators.
+func (mr *MockChainMockRecorder) GetSubnetOnlyValidators(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetOnlyValidators", reflect.TypeOf((*MockChain)(nil).GetSubnetOnlyValidators), arg0)
+}
+
 // GetSubnetOwner mocks base method.
 func (m *MockChain) GetSubnetOwner(arg0 ids.ID) (fx.Owner, error) {
 	m.ctrl.T.Helper()
@@ -392,6 +483,21 @@ func (mr *MockChainMockRecorder) GetUTXO(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUTXO", reflect.TypeOf((*MockChain)(nil).GetUTXO), arg0)
 }
 
+// HasSubnetOnlyValidator mocks base method.
+func (m *MockChain) HasSubnetOnlyValidator(arg0 ids.ID, arg1 ids.NodeID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasSubnetOnlyValidator", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasSubnetOnlyValidator indicates an expected call of HasSubnetOnlyValidator.
+func (mr *MockChainMockRecorder) HasSubnetOnlyValidator(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasSubnetOnlyValidator", reflect.TypeOf((*MockChain)(nil).HasSubnetOnlyValidator), arg0, arg1)
+}
+
 // PutCurrentDelegator mocks base method.
 func (m *MockChain) PutCurrentDelegator(arg0 *Staker) {
 	m.ctrl.T.Helper()
@@ -440,6 +546,32 @@ func (mr *MockChainMockRecorder) PutPendingValidator(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutPendingValidator", reflect.TypeOf((*MockChain)(nil).PutPendingValidator), arg0)
 }
 
+// PutSubnetOnlyValidator mocks base method.
+func (m *MockChain) PutSubnetOnlyValidator(arg0 SubnetOnlyValidator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutSubnetOnlyValidator", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutSubnetOnlyValidator indicates an expected call of PutSubnetOnlyValidator.
+func (mr *MockChainMockRecorder) PutSubnetOnlyValidator(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSubnetOnlyValidator", reflect.TypeOf((*MockChain)(nil).PutSubnetOnlyValidator), arg0)
+}
+
+// SetAccruedFees mocks base method.
+func (m *MockChain) SetAccruedFees(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAccruedFees", arg0)
+}
+
+// SetAccruedFees indicates an expected call of SetAccruedFees.
+func (mr *MockChainMockRecorder) SetAccruedFees(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccruedFees", reflect.TypeOf((*MockChain)(nil).SetAccruedFees), arg0)
+}
+
 // SetCurrentSupply mocks base method.
 func (m *MockChain) SetCurrentSupply(arg0 ids.ID, arg1 uint64) {
 	m.ctrl.T.Helper()
@@ -478,6 +610,18 @@ func (mr *MockChainMockRecorder) SetFeeState(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFeeState", reflect.TypeOf((*MockChain)(nil).SetFeeState), arg0)
 }
 
+// SetSubnetManager mocks base method.
+func (m *MockChain) SetSubnetManager(arg0, arg1 ids.ID, arg2 []byte) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetSubnetManager", arg0, arg1, arg2)
+}
+
+// SetSubnetManager indicates an expected call of SetSubnetManager.
+func (mr *MockChainMockRecorder) SetSubnetManager(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubnetManager", reflect.TypeOf((*MockChain)(nil).SetSubnetManager), arg0, arg1, arg2)
+}
+
 // SetSubnetOwner mocks base method.
 func (m *MockChain) SetSubnetOwner(arg0 ids.ID, arg1 fx.Owner) {
 	m.ctrl.T.Helper()
@@ -502,6 +646,21 @@ func (mr *MockChainMockRecorder) SetTimestamp(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTimestamp", reflect.TypeOf((*MockChain)(nil).SetTimestamp), arg0)
 }
 
+// WeightOfSubnetOnlyValidators mocks base method.
+func (m *MockChain) WeightOfSubnetOnlyValidators(arg0 ids.ID) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WeightOfSubnetOnlyValidators", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WeightOfSubnetOnlyValidators indicates an expected call of WeightOfSubnetOnlyValidators.
+func (mr *MockChainMockRecorder) WeightOfSubnetOnlyValidators(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WeightOfSubnetOnlyValidators", reflect.TypeOf((*MockChain)(nil).WeightOfSubnetOnlyValidators), arg0)
+}
+
 // MockDiff is a mock of Diff interface.
 type MockDiff struct {
 	ctrl     *gomock.Controller
@@ -671,6 +830,35 @@ func (mr *MockDiffMockRecorder) DeleteUTXO(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUTXO", reflect.TypeOf((*MockDiff)(nil).DeleteUTXO), arg0)
 }
 
+// GetAccruedFees mocks base method.
+func (m *MockDiff) GetAccruedFees() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccruedFees")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetAccruedFees indicates an expected call of GetAccruedFees.
+func (mr *MockDiffMockRecorder) GetAccruedFees() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccruedFees", reflect.TypeOf((*MockDiff)(nil).GetAccruedFees))
+}
+
+// GetActiveSubnetOnlyValidators mocks base method.
+func (m *MockDiff) GetActiveSubnetOnlyValidators() ([]SubnetOnlyValidator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSubnetOnlyValidators")
+	ret0, _ := ret[0].([]SubnetOnlyValidator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSubnetOnlyValidators indicates an expected call of GetActiveSubnetOnlyValidators.
+func (mr *MockDiffMockRecorder) GetActiveSubnetOnlyValidators() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSubnetOnlyValidators", reflect.TypeOf((*MockDiff)(nil).GetActiveSubnetOnlyValidators))
+}
+
 // GetCurrentDelegatorIterator mocks base method.
 func (m *MockDiff) GetCurrentDelegatorIterator(arg0 ids.ID, arg1 ids.NodeID) (StakerIterator, error) {
 	m.ctrl.T.Helper()
@@ -731,6 +919,21 @@ func (mr *MockDiffMockRecorder) GetCurrentValidator(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentValidator", reflect.TypeOf((*MockDiff)(nil).GetCurrentValidator), arg0, arg1)
 }
 
+// GetCurrentValidators mocks base method.
+func (m *MockDiff) GetCurrentValidators(arg0 ids.ID) ([]*Staker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentValidators", arg0)
+	ret0, _ := ret[0].([]*Staker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentValidators indicates an expected call of GetCurrentValidators.
+func (mr *MockDiffMockRecorder) GetCurrentValidators(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentValidators", reflect.TypeOf((*MockDiff)(nil).GetCurrentValidators), arg0)
+}
+
 // GetDelegateeReward mocks base method.
 func (m *MockDiff) GetDelegateeReward(arg0 ids.ID, arg1 ids.NodeID) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -805,6 +1008,52 @@ func (mr *MockDiffMockRecorder) GetPendingValidator(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingValidator", reflect.TypeOf((*MockDiff)(nil).GetPendingValidator), arg0, arg1)
 }
 
+// GetSubnetManager mocks base method.
+func (m *MockDiff) GetSubnetManager(arg0 ids.ID) (ids.ID, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetManager", arg0)
+	ret0, _ := ret[0].(ids.ID)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSubnetManager indicates an expected call of GetSubnetManager.
+func (mr *MockDiffMockRecorder) GetSubnetManager(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetManager", reflect.TypeOf((*MockDiff)(nil).GetSubnetManager), arg0)
+}
+
+// GetSubnetOnlyValidator mocks base method.
+func (m *MockDiff) GetSubnetOnlyValidator(arg0 ids.ID) (SubnetOnlyValidator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetOnlyValidator", arg0)
+	ret0, _ := ret[0].(SubnetOnlyValidator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetOnlyValidator indicates an expected call of GetSubnetOnlyValidator.
+func (mr *MockDiffMockRecorder) GetSubnetOnlyValidator(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetOnlyValidator", reflect.TypeOf((*MockDiff)(nil).GetSubnetOnlyValidator), arg0)
+}
+
+// GetSubnetOnlyValidators mocks base method.
+func (m *MockDiff) GetSubnetOnlyValidators(arg0 ids.ID) ([]SubnetOnlyValidator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetOnlyValidators", arg0)
+	ret0, _ := ret[0].([]SubnetOnlyValidator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetOnlyValidators indicates an expected call of GetSubnetOnlyValidators.
+func (mr *MockDiffMockRecorder) GetSubnetOnlyValidators(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetOnlyValidators", reflect.TypeOf((*MockDiff)(nil).GetSubnetOnlyValidators), arg0)
+}
+
 // GetSubnetOwner mocks base method.
 func (m *MockDiff) GetSubnetOwner(arg0 ids.ID) (fx.Owner, error) {
 	m.ctrl.T.Helper()
@@ -880,6 +1129,21 @@ func (mr *MockDiffMockRecorder) GetUTXO(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUTXO", reflect.TypeOf((*MockDiff)(nil).GetUTXO), arg0)
 }
 
+// HasSubnetOnlyValidator mocks base method.
+func (m *MockDiff) HasSubnetOnlyValidator(arg0 ids.ID, arg1 ids.NodeID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasSubnetOnlyValidator", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasSubnetOnlyValidator indicates an expected call of HasSubnetOnlyValidator.
+func (mr *MockDiffMockRecorder) HasSubnetOnlyValidator(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasSubnetOnlyValidator", reflect.TypeOf((*MockDiff)(nil).HasSubnetOnlyValidator), arg0, arg1)
+}
+
 // PutCurrentDelegator mocks base method.
 func (m *MockDiff) PutCurrentDelegator(arg0 *Staker) {
 	m.ctrl.T.Helper()
@@ -928,6 +1192,32 @@ func (mr *MockDiffMockRecorder) PutPendingValidator(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutPendingValidator", reflect.TypeOf((*MockDiff)(nil).PutPendingValidator), arg0)
 }
 
+// PutSubnetOnlyValidator mocks base method.
+func (m *MockDiff) PutSubnetOnlyValidator(arg0 SubnetOnlyValidator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutSubnetOnlyValidator", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutSubnetOnlyValidator indicates an expected call of PutSubnetOnlyValidator.
+func (mr *MockDiffMockRecorder) PutSubnetOnlyValidator(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSubnetOnlyValidator", reflect.TypeOf((*MockDiff)(nil).PutSubnetOnlyValidator), arg0)
+}
+
+// SetAccruedFees mocks base method.
+func (m *MockDiff) SetAccruedFees(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAccruedFees", arg0)
+}
+
+// SetAccruedFees indicates an expected call of SetAccruedFees.
+func (mr *MockDiffMockRecorder) SetAccruedFees(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccruedFees", reflect.TypeOf((*MockDiff)(nil).SetAccruedFees), arg0)
+}
+
 // SetCurrentSupply mocks base method.
 func (m *MockDiff) SetCurrentSupply(arg0 ids.ID, arg1 uint64) {
 	m.ctrl.T.Helper()
@@ -966,6 +1256,18 @@ func (mr *MockDiffMockRecorder) SetFeeState(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFeeState", reflect.TypeOf((*MockDiff)(nil).SetFeeState), arg0)
 }
 
+// SetSubnetManager mocks base method.
+func (m *MockDiff) SetSubnetManager(arg0, arg1 ids.ID, arg2 []byte) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetSubnetManager", arg0, arg1, arg2)
+}
+
+// SetSubnetManager indicates an expected call of SetSubnetManager.
+func (mr *MockDiffMockRecorder) SetSubnetManager(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubnetManager", reflect.TypeOf((*MockDiff)(nil).SetSubnetManager), arg0, arg1, arg2)
+}
+
 // SetSubnetOwner mocks base method.
 func (m *MockDiff) SetSubnetOwner(arg0 ids.ID, arg1 fx.Owner) {
 	m.ctrl.T.Helper()
@@ -990,6 +1292,21 @@ func (mr *MockDiffMockRecorder) SetTimestamp(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTimestamp", reflect.TypeOf((*MockDiff)(nil).SetTimestamp), arg0)
 }
 
+// WeightOfSubnetOnlyValidators mocks base method.
+func (m *MockDiff) WeightOfSubnetOnlyValidators(arg0 ids.ID) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WeightOfSubnetOnlyValidators", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WeightOfSubnetOnlyValidators indicates an expected call of WeightOfSubnetOnlyValidators.
+func (mr *MockDiffMockRecorder) WeightOfSubnetOnlyValidators(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WeightOfSubnetOnlyValidators", reflect.TypeOf((*MockDiff)(nil).WeightOfSubnetOnlyValidators), arg0)
+}
+
 // MockState is a mock of State interface.
 type MockState struct {
 	ctrl     *gomock.Controller
@@ -1037,6 +1354,18 @@ func (mr *MockStateMockRecorder) AddChain(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddChain", reflect.TypeOf((*MockState)(nil).AddChain), arg0)
 }
 
+// AddRewardRecord mocks base method.
+func (m *MockState) AddRewardRecord(arg0 *RewardRecord) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddRewardRecord", arg0)
+}
+
+// AddRewardRecord indicates an expected call of AddRewardRecord.
+func (mr *MockStateMockRecorder) AddRewardRecord(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRewardRecord", reflect.TypeOf((*MockState)(nil).AddRewardRecord), arg0)
+}
+
 // AddRewardUTXO mocks base method.
 func (m *MockState) AddRewardUTXO(arg0 ids.ID, arg1 *avax.UTXO) {
 	m.ctrl.T.Helper()
@@ -1110,17 +1439,17 @@ func (mr *MockStateMockRecorder) AddUTXO(arg0 any) *gomock.Call {
 }
 
 // ApplyValidatorPublicKeyDiffs mocks base method.
-func (m *MockState) ApplyValidatorPublicKeyDiffs(arg0 context.Context, arg1 map[ids.NodeID]*validators.GetValidatorOutput, arg2, arg3 uint64) error {
+func (m *MockState) ApplyValidatorPublicKeyDiffs(arg0 context.Context, arg1 map[ids.NodeID]*validators.GetValidatorOutput, arg2, arg3 uint64, arg4 ids.ID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ApplyValidatorPublicKeyDiffs", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "ApplyValidatorPublicKeyDiffs", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ApplyValidatorPublicKeyDiffs indicates an expected call of ApplyValidatorPublicKeyDiffs.
-func (mr *MockStateMockRecorder) ApplyValidatorPublicKeyDiffs(arg0, arg1, arg2, arg3 any) *gomock.Call {
+func (mr *MockStateMockRecorder) ApplyValidatorPublicKeyDiffs(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyValidatorPublicKeyDiffs", reflect.TypeOf((*MockState)(nil).ApplyValidatorPublicKeyDiffs), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyValidatorPublicKeyDiffs", reflect.TypeOf((*MockState)(nil).ApplyValidatorPublicKeyDiffs), arg0, arg1, arg2, arg3, arg4)
 }
 
 // ApplyValidatorWeightDiffs mocks base method.
@@ -1137,6 +1466,20 @@ func (mr *MockStateMockRecorder) ApplyValidatorWeightDiffs(arg0, arg1, arg2, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyValidatorWeightDiffs", reflect.TypeOf((*MockState)(nil).ApplyValidatorWeightDiffs), arg0, arg1, arg2, arg3, arg4)
 }
 
+// BackfillRewardHistory mocks base method.
+func (m *MockState) BackfillRewardHistory(arg0 sync.Locker, arg1 logging.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillRewardHistory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BackfillRewardHistory indicates an expected call of BackfillRewardHistory.
+func (mr *MockStateMockRecorder) BackfillRewardHistory(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillRewardHistory", reflect.TypeOf((*MockState)(nil).BackfillRewardHistory), arg0, arg1)
+}
+
 // Checksum mocks base method.
 func (m *MockState) Checksum() ids.ID {
 	m.ctrl.T.Helper()
@@ -1254,6 +1597,50 @@ func (mr *MockStateMockRecorder) DeleteUTXO(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUTXO", reflect.TypeOf((*MockState)(nil).DeleteUTXO), arg0)
 }
 
+// GetAccruedFees mocks base method.
+func (m *MockState) GetAccruedFees() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccruedFees")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetAccruedFees indicates an expected call of GetAccruedFees.
+func (mr *MockStateMockRecorder) GetAccruedFees() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccruedFees", reflect.TypeOf((*MockState)(nil).GetAccruedFees))
+}
+
+// GetActiveSubnetOnlyValidators mocks base method.
+func (m *MockState) GetActiveSubnetOnlyValidators() ([]SubnetOnlyValidator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSubnetOnlyValidators")
+	ret0, _ := ret[0].([]SubnetOnlyValidator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSubnetOnlyValidators indicates an expected call of GetActiveSubnetOnlyValidators.
+func (mr *MockStateMockRecorder) GetActiveSubnetOnlyValidators() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSubnetOnlyValidators", reflect.TypeOf((*MockState)(nil).GetActiveSubnetOnlyValidators))
+}
+
+// GetAddressRewardHistory mocks base method.
+func (m *MockState) GetAddressRewardHistory(arg0 ids.ShortID, arg1 RewardHistoryIndex, arg2 int) ([]*RewardRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddressRewardHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*RewardRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddressRewardHistory indicates an expected call of GetAddressRewardHistory.
+func (mr *MockStateMockRecorder) GetAddressRewardHistory(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressRewardHistory", reflect.TypeOf((*MockState)(nil).GetAddressRewardHistory), arg0, arg1, arg2)
+}
+
 // GetBlockIDAtHeight mocks base method.
 func (m *MockState) GetBlockIDAtHeight(arg0 uint64) (ids.ID, error) {
 	m.ctrl.T.Helper()
@@ -1344,6 +1731,21 @@ func (mr *MockStateMockRecorder) GetCurrentValidator(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentValidator", reflect.TypeOf((*MockState)(nil).GetCurrentValidator), arg0, arg1)
 }
 
+// GetCurrentValidators mocks base method.
+func (m *MockState) GetCurrentValidators(arg0 ids.ID) ([]*Staker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentValidators", arg0)
+	ret0, _ := ret[0].([]*Staker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentValidators indicates an expected call of GetCurrentValidators.
+func (mr *MockStateMockRecorder) GetCurrentValidators(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentValidators", reflect.TypeOf((*MockState)(nil).GetCurrentValidators), arg0)
+}
+
 // GetDelegateeReward mocks base method.
 func (m *MockState) GetDelegateeReward(arg0 ids.ID, arg1 ids.NodeID) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -1387,6 +1789,36 @@ func (mr *MockStateMockRecorder) GetLastAccepted() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAccepted", reflect.TypeOf((*MockState)(nil).GetLastAccepted))
 }
 
+// GetModifiedValidators mocks base method.
+func (m *MockState) GetModifiedValidators(arg0 context.Context, arg1 ids.ID, arg2, arg3 uint64, arg4 ids.NodeID, arg5 int) ([]ids.NodeID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModifiedValidators", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]ids.NodeID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModifiedValidators indicates an expected call of GetModifiedValidators.
+func (mr *MockStateMockRecorder) GetModifiedValidators(arg0, arg1, arg2, arg3, arg4, arg5 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModifiedValidators", reflect.TypeOf((*MockState)(nil).GetModifiedValidators), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// GetNodeIDRewardHistory mocks base method.
+func (m *MockState) GetNodeIDRewardHistory(arg0 ids.NodeID, arg1 RewardHistoryIndex, arg2 int) ([]*RewardRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodeIDRewardHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*RewardRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNodeIDRewardHistory indicates an expected call of GetNodeIDRewardHistory.
+func (mr *MockStateMockRecorder) GetNodeIDRewardHistory(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeIDRewardHistory", reflect.TypeOf((*MockState)(nil).GetNodeIDRewardHistory), arg0, arg1, arg2)
+}
+
 // GetPendingDelegatorIterator mocks base method.
 func (m *MockState) GetPendingDelegatorIterator(arg0 ids.ID, arg1 ids.NodeID) (StakerIterator, error) {
 	m.ctrl.T.Helper()
@@ -1492,6 +1924,52 @@ func (mr *MockStateMockRecorder) GetSubnetIDs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetIDs", reflect.TypeOf((*MockState)(nil).GetSubnetIDs))
 }
 
+// GetSubnetManager mocks base method.
+func (m *MockState) GetSubnetManager(arg0 ids.ID) (ids.ID, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetManager", arg0)
+	ret0, _ := ret[0].(ids.ID)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSubnetManager indicates an expected call of GetSubnetManager.
+func (mr *MockStateMockRecorder) GetSubnetManager(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetManager", reflect.TypeOf((*MockState)(nil).GetSubnetManager), arg0)
+}
+
+// GetSubnetOnlyValidator mocks base method.
+func (m *MockState) GetSubnetOnlyValidator(arg0 ids.ID) (SubnetOnlyValidator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetOnlyValidator", arg0)
+	ret0, _ := ret[0].(SubnetOnlyValidator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetOnlyValidator indicates an expected call of GetSubnetOnlyValidator.
+func (mr *MockStateMockRecorder) GetSubnetOnlyValidator(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetOnlyValidator", reflect.TypeOf((*MockState)(nil).GetSubnetOnlyValidator), arg0)
+}
+
+// GetSubnetOnlyValidators mocks base method.
+func (m *MockState) GetSubnetOnlyValidators(arg0 ids.ID) ([]SubnetOnlyValidator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetOnlyValidators", arg0)
+	ret0, _ := ret[0].([]SubnetOnlyValidator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetOnlyValidators indicates an expected call of GetSubnetOnlyValidators.
+func (mr *MockStateMockRecorder) GetSubnetOnlyValidators(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetOnlyValidators", reflect.TypeOf((*MockState)(nil).GetSubnetOnlyValidators), arg0)
+}
+
 // GetSubnetOwner mocks base method.
 func (m *MockState) GetSubnetOwner(arg0 ids.ID) (fx.Owner, error) {
 	m.ctrl.T.Helper()
@@ -1583,6 +2061,65 @@ func (mr *MockStateMockRecorder) GetUptime(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUptime", reflect.TypeOf((*MockState)(nil).GetUptime), arg0, arg1)
 }
 
+// GetValidatorWeightDiffs mocks base method.
+func (m *MockState) GetValidatorWeightDiffs(arg0 context.Context, arg1 set.Set[ids.NodeID], arg2, arg3 uint64, arg4 ids.ID) (map[ids.NodeID]*ValidatorWeightDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidatorWeightDiffs", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(map[ids.NodeID]*ValidatorWeightDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValidatorWeightDiffs indicates an expected call of GetValidatorWeightDiffs.
+func (mr *MockStateMockRecorder) GetValidatorWeightDiffs(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidatorWeightDiffs", reflect.TypeOf((*MockState)(nil).GetValidatorWeightDiffs), arg0, arg1, arg2, arg3, arg4)
+}
+
+// HasSubnetOnlyValidator mocks base method.
+func (m *MockState) HasSubnetOnlyValidator(arg0 ids.ID, arg1 ids.NodeID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasSubnetOnlyValidator", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasSubnetOnlyValidator indicates an expected call of HasSubnetOnlyValidator.
+func (mr *MockStateMockRecorder) HasSubnetOnlyValidator(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasSubnetOnlyValidator", reflect.TypeOf((*MockState)(nil).HasSubnetOnlyValidator), arg0, arg1)
+}
+
+// IsRewardHistoryBackfilled mocks base method.
+func (m *MockState) IsRewardHistoryBackfilled() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRewardHistoryBackfilled")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRewardHistoryBackfilled indicates an expected call of IsRewardHistoryBackfilled.
+func (mr *MockStateMockRecorder) IsRewardHistoryBackfilled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRewardHistoryBackfilled", reflect.TypeOf((*MockState)(nil).IsRewardHistoryBackfilled))
+}
+
+// NewUTXOIterator mocks base method.
+func (m *MockState) NewUTXOIterator() avax.UTXOIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewUTXOIterator")
+	ret0, _ := ret[0].(avax.UTXOIterator)
+	return ret0
+}
+
+// NewUTXOIterator indicates an expected call of NewUTXOIterator.
+func (mr *MockStateMockRecorder) NewUTXOIterator() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewUTXOIterator", reflect.TypeOf((*MockState)(nil).NewUTXOIterator))
+}
+
 // PutCurrentDelegator mocks base method.
 func (m *MockState) PutCurrentDelegator(arg0 *Staker) {
 	m.ctrl.T.Helper()
@@ -1631,6 +2168,20 @@ func (mr *MockStateMockRecorder) PutPendingValidator(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutPendingValidator", reflect.TypeOf((*MockState)(nil).PutPendingValidator), arg0)
 }
 
+// PutSubnetOnlyValidator mocks base method.
+func (m *MockState) PutSubnetOnlyValidator(arg0 SubnetOnlyValidator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutSubnetOnlyValidator", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutSubnetOnlyValidator indicates an expected call of PutSubnetOnlyValidator.
+func (mr *MockStateMockRecorder) PutSubnetOnlyValidator(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSubnetOnlyValidator", reflect.TypeOf((*MockState)(nil).PutSubnetOnlyValidator), arg0)
+}
+
 // ReindexBlocks mocks base method.
 func (m *MockState) ReindexBlocks(arg0 sync.Locker, arg1 logging.Logger) error {
 	m.ctrl.T.Helper()
@@ -1645,6 +2196,18 @@ func (mr *MockStateMockRecorder) ReindexBlocks(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReindexBlocks", reflect.TypeOf((*MockState)(nil).ReindexBlocks), arg0, arg1)
 }
 
+// SetAccruedFees mocks base method.
+func (m *MockState) SetAccruedFees(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAccruedFees", arg0)
+}
+
+// SetAccruedFees indicates an expected call of SetAccruedFees.
+func (mr *MockStateMockRecorder) SetAccruedFees(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccruedFees", reflect.TypeOf((*MockState)(nil).SetAccruedFees), arg0)
+}
+
 // SetCurrentSupply mocks base method.
 func (m *MockState) SetCurrentSupply(arg0 ids.ID, arg1 uint64) {
 	m.ctrl.T.Helper()
@@ -1707,6 +2270,18 @@ func (mr *MockStateMockRecorder) SetLastAccepted(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLastAccepted", reflect.TypeOf((*MockState)(nil).SetLastAccepted), arg0)
 }
 
+// SetSubnetManager mocks base method.
+func (m *MockState) SetSubnetManager(arg0, arg1 ids.ID, arg2 []byte) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetSubnetManager", arg0, arg1, arg2)
+}
+
+// SetSubnetManager indicates an expected call of SetSubnetManager.
+func (mr *MockStateMockRecorder) SetSubnetManager(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubnetManager", reflect.TypeOf((*MockState)(nil).SetSubnetManager), arg0, arg1, arg2)
+}
+
 // SetSubnetOwner mocks base method.
 func (m *MockState) SetSubnetOwner(arg0 ids.ID, arg1 fx.Owner) {
 	m.ctrl.T.Helper()
@@ -1760,6 +2335,21 @@ func (mr *MockStateMockRecorder) UTXOIDs(arg0, arg1, arg2 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UTXOIDs", reflect.TypeOf((*MockState)(nil).UTXOIDs), arg0, arg1, arg2)
 }
 
+// WeightOfSubnetOnlyValidators mocks base method.
+func (m *MockState) WeightOfSubnetOnlyValidators(arg0 ids.ID) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WeightOfSubnetOnlyValidators", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WeightOfSubnetOnlyValidators indicates an expected call of WeightOfSubnetOnlyValidators.
+func (mr *MockStateMockRecorder) WeightOfSubnetOnlyValidators(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WeightOfSubnetOnlyValidators", reflect.TypeOf((*MockState)(nil).WeightOfSubnetOnlyValidators), arg0)
+}
+
 // MockVersions is a mock of Versions interface.
 type MockVersions struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,680 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/block"
+	"github.com/ava-labs/avalanchego/vms/platformvm/fx"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// rewardHistoryKey = [nodeID or address] + [height] + [txID]
+const rewardHistoryKeyLength = ids.ShortIDLen + database.Uint64Size + ids.IDLen
+
+var (
+	rewardRecordPrefix       = []byte("record")
+	rewardNodeIDIndexPrefix  = []byte("nodeID")
+	rewardAddressIndexPrefix = []byte("address")
+
+	ErrRewardHistoryNotIndexed = errors.New("reward history is not indexed")
+
+	errStakerNotFound = errors.New("staker not found")
+)
+
+// RewardRecord describes the outcome of a staking period.
+type RewardRecord struct {
+	// ID of the tx that added the staker
+	TxID ids.ID `serialize:"true"`
+	// ID of the RewardValidatorTx that removed the staker
+	RewardTxID ids.ID `serialize:"true"`
+	// Height of the block that decided whether the staker was rewarded
+	Height      uint64     `serialize:"true"`
+	SubnetID    ids.ID     `serialize:"true"`
+	NodeID      ids.NodeID `serialize:"true"`
+	IsDelegator bool       `serialize:"true"`
+	// Unix time, in seconds, of the start of the staking period
+	StartTime uint64 `serialize:"true"`
+	// Unix time, in seconds, of the end of the staking period
+	EndTime  uint64 `serialize:"true"`
+	Weight   uint64 `serialize:"true"`
+	Rewarded bool   `serialize:"true"`
+	// Reward paid to the rewards owner of the staker
+	Reward uint64 `serialize:"true"`
+	// Reward the staker would have been paid if it had been rewarded. This is
+	// only known if the record was indexed when the block was accepted.
+	ForfeitedReward uint64 `serialize:"true"`
+	// For validators, the delegation fees earned from their delegators. For
+	// delegators, the delegation fee paid to their validator. The fee paid by
+	// a delegator after Cortina is only known if the record was indexed when
+	// the block was accepted.
+	DelegationFee uint64 `serialize:"true"`
+	// Addresses of the owners of the rewards of the staker
+	Addresses []ids.ShortID `serialize:"true"`
+}
+
+// RewardHistoryIndex is the position of a record in the reward history of a
+// node ID or address.
+type RewardHistoryIndex struct {
+	Height uint64
+	TxID   ids.ID
+}
+
+// NewRewardRecord returns the record of the staker that is removed by [tx].
+// [chain] must be the state the reward was decided on, before the staker was
+// removed.
+func NewRewardRecord(
+	chain Chain,
+	rewardTxID ids.ID,
+	tx *txs.RewardValidatorTx,
+	rewarded bool,
+	height uint64,
+) (*RewardRecord, error) {
+	stakerTx, _, err := chain.GetTx(tx.TxID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staker tx %s: %w", tx.TxID, err)
+	}
+
+	record := &RewardRecord{
+		TxID:       tx.TxID,
+		RewardTxID: rewardTxID,
+		Height:     height,
+		Rewarded:   rewarded,
+	}
+	switch uStakerTx := stakerTx.Unsigned.(type) {
+	case txs.ValidatorTx:
+		staker, err := chain.GetCurrentValidator(uStakerTx.SubnetID(), uStakerTx.NodeID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get validator %s: %w", uStakerTx.NodeID(), err)
+		}
+		if staker.TxID != tx.TxID {
+			return nil, fmt.Errorf("%w: %s", errStakerNotFound, tx.TxID)
+		}
+
+		delegationFee, err := chain.GetDelegateeReward(staker.SubnetID, staker.NodeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get delegatee reward: %w", err)
+		}
+
+		record.setStaker(staker)
+		record.setRewards(rewarded, staker.PotentialReward, delegationFee)
+		record.setAddresses(
+			uStakerTx.ValidationRewardsOwner(),
+			uStakerTx.DelegationRewardsOwner(),
+		)
+	case txs.DelegatorTx:
+		staker, err := getCurrentDelegator(chain, uStakerTx.SubnetID(), uStakerTx.NodeID(), tx.TxID)
+		if err != nil {
+			return nil, err
+		}
+
+		validator, err := chain.GetCurrentValidator(staker.SubnetID, staker.NodeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get validator %s: %w", staker.NodeID, err)
+		}
+		validatorTx, _, err := chain.GetTx(validator.TxID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get validator tx %s: %w", validator.TxID, err)
+		}
+		uValidatorTx, ok := validatorTx.Unsigned.(txs.ValidatorTx)
+		if !ok {
+			return nil, fmt.Errorf("unexpected validator tx type %T", validatorTx.Unsigned)
+		}
+
+		delegationFee, delegatorReward := reward.Split(staker.PotentialReward, uValidatorTx.Shares())
+		record.IsDelegator = true
+		record.setStaker(staker)
+		record.setRewards(rewarded, delegatorReward, 0)
+		if rewarded {
+			record.DelegationFee = delegationFee
+		}
+		record.setAddresses(uStakerTx.RewardsOwner())
+	default:
+		return nil, fmt.Errorf("unexpected staker tx type %T", stakerTx.Unsigned)
+	}
+	return record, nil
+}
+
+func getCurrentDelegator(chain Chain, subnetID ids.ID, nodeID ids.NodeID, txID ids.ID) (*Staker, error) {
+	iter, err := chain.GetCurrentDelegatorIterator(subnetID, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegators of %s: %w", nodeID, err)
+	}
+	defer iter.Release()
+
+	for iter.Next() {
+		if staker := iter.Value(); staker.TxID == txID {
+			return staker, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", errStakerNotFound, txID)
+}
+
+func (r *RewardRecord) setStaker(staker *Staker) {
+	r.SubnetID = staker.SubnetID
+	r.NodeID = staker.NodeID
+	r.StartTime = uint64(staker.StartTime.Unix())
+	r.EndTime = uint64(staker.EndTime.Unix())
+	r.Weight = staker.Weight
+}
+
+func (r *RewardRecord) setRewards(rewarded bool, potentialReward uint64, delegationFee uint64) {
+	if rewarded {
+		r.Reward = potentialReward
+	} else {
+		r.ForfeitedReward = potentialReward
+	}
+	r.DelegationFee = delegationFee
+}
+
+func (r *RewardRecord) setAddresses(owners ...fx.Owner) {
+	addrs := set.Set[ids.ShortID]{}
+	for _, owner := range owners {
+		if owner, ok := owner.(*secp256k1fx.OutputOwners); ok {
+			addrs.Add(owner.Addrs...)
+		}
+	}
+	r.Addresses = addrs.List()
+}
+
+func marshalRewardHistoryKey(id []byte, height uint64, txID ids.ID) []byte {
+	key := make([]byte, rewardHistoryKeyLength)
+	copy(key, id)
+	binary.BigEndian.PutUint64(key[ids.ShortIDLen:], height)
+	copy(key[ids.ShortIDLen+database.Uint64Size:], txID[:])
+	return key
+}
+
+func (s *state) AddRewardRecord(record *RewardRecord) {
+	s.addedRewardRecords = append(s.addedRewardRecords, record)
+}
+
+func (s *state) GetNodeIDRewardHistory(
+	nodeID ids.NodeID,
+	start RewardHistoryIndex,
+	limit int,
+) ([]*RewardRecord, error) {
+	return s.getRewardHistory(s.rewardNodeIDIndexDB, nodeID.Bytes(), start, limit)
+}
+
+func (s *state) GetAddressRewardHistory(
+	addr ids.ShortID,
+	start RewardHistoryIndex,
+	limit int,
+) ([]*RewardRecord, error) {
+	return s.getRewardHistory(s.rewardAddressIndexDB, addr.Bytes(), start, limit)
+}
+
+func (s *state) getRewardHistory(
+	db database.Database,
+	id []byte,
+	start RewardHistoryIndex,
+	limit int,
+) ([]*RewardRecord, error) {
+	indexed, err := s.singletonDB.Has(RewardHistoryStartHeightKey)
+	if err != nil {
+		return nil, err
+	}
+	if !indexed {
+		return nil, ErrRewardHistoryNotIndexed
+	}
+
+	startKey := marshalRewardHistoryKey(id, start.Height, start.TxID)
+	iter := db.NewIteratorWithStartAndPrefix(startKey, id)
+	defer iter.Release()
+
+	var records []*RewardRecord
+	for len(records) < limit && iter.Next() {
+		key := iter.Key()
+		if len(key) != rewardHistoryKeyLength {
+			return nil, fmt.Errorf("expected reward history key length %d but got %d", rewardHistoryKeyLength, len(key))
+		}
+
+		// The start index is exclusive.
+		txID, err := ids.ToID(key[ids.ShortIDLen+database.Uint64Size:])
+		if err != nil {
+			return nil, err
+		}
+		height := binary.BigEndian.Uint64(key[ids.ShortIDLen:])
+		if height == start.Height && txID == start.TxID {
+			continue
+		}
+
+		recordBytes, err := s.rewardRecordDB.Get(txID[:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reward record %s: %w", txID, err)
+		}
+		record := &RewardRecord{}
+		if _, err := block.GenesisCodec.Unmarshal(recordBytes, record); err != nil {
+			return nil, fmt.Errorf("failed to parse reward record %s: %w", txID, err)
+		}
+		records = append(records, record)
+	}
+	return records, iter.Error()
+}
+
+func (s *state) writeRewardHistory() error {
+	for _, record := range s.addedRewardRecords {
+		recordBytes, err := block.GenesisCodec.Marshal(block.CodecVersion, record)
+		if err != nil {
+			return fmt.Errorf("failed to serialize reward record: %w", err)
+		}
+		if err := s.rewardRecordDB.Put(record.TxID[:], recordBytes); err != nil {
+			return fmt.Errorf("failed to write reward record: %w", err)
+		}
+
+		nodeIDKey := marshalRewardHistoryKey(record.NodeID.Bytes(), record.Height, record.TxID)
+		if err := s.rewardNodeIDIndexDB.Put(nodeIDKey, nil); err != nil {
+			return fmt.Errorf("failed to index reward record: %w", err)
+		}
+		for _, addr := range record.Addresses {
+			addrKey := marshalRewardHistoryKey(addr.Bytes(), record.Height, record.TxID)
+			if err := s.rewardAddressIndexDB.Put(addrKey, nil); err != nil {
+				return fmt.Errorf("failed to index reward record: %w", err)
+			}
+		}
+	}
+	s.addedRewardRecords = nil
+	return nil
+}
+
+// initRewardHistory marks the height from which the reward history is indexed
+// when blocks are accepted. The history of the prior heights is backfilled by
+// [BackfillRewardHistory].
+//
+// If the index is disabled, the markers are removed so that the reward history
+// is rebuilt the next time the index is enabled.
+func (s *state) initRewardHistory(enabled bool) error {
+	has, err := s.singletonDB.Has(RewardHistoryStartHeightKey)
+	if err != nil || has == enabled {
+		return err
+	}
+
+	if !enabled {
+		return errors.Join(
+			s.singletonDB.Delete(RewardHistoryStartHeightKey),
+			s.singletonDB.Delete(RewardHistoryBackfilledKey),
+			s.Commit(),
+		)
+	}
+
+	lastAccepted, err := s.GetStatelessBlock(s.GetLastAccepted())
+	if err != nil {
+		return err
+	}
+	return errors.Join(
+		database.PutUInt64(s.singletonDB, RewardHistoryStartHeightKey, lastAccepted.Height()+1),
+		s.Commit(),
+	)
+}
+
+func (s *state) BackfillRewardHistory(lock sync.Locker, log logging.Logger) error {
+	lock.Lock()
+	endHeight, err := database.GetUInt64(s.singletonDB, RewardHistoryStartHeightKey)
+	lock.Unlock()
+	if err == database.ErrNotFound {
+		// The reward history isn't indexed.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	lock.Lock()
+	has, err := s.singletonDB.Has(RewardHistoryBackfilledKey)
+	lock.Unlock()
+	if err != nil {
+		return err
+	}
+	if has {
+		log.Info("reward history already backfilled")
+		return nil
+	}
+
+	log.Info("starting reward history backfill",
+		zap.Uint64("endHeight", endHeight),
+	)
+
+	var (
+		startTime  = time.Now()
+		lastCommit = startTime
+		nextUpdate = startTime.Add(indexLogFrequency)
+		numRecords = 0
+		parent     block.Block
+		stakers    = newBackfilledStakers()
+	)
+	for height := uint64(1); height < endHeight; height++ {
+		lock.Lock()
+		blk, numAdded, err := s.backfillRewardHistoryAt(height, parent, stakers)
+		lock.Unlock()
+		if err != nil {
+			return fmt.Errorf("failed to backfill reward history at height %d: %w", height, err)
+		}
+		parent = blk
+		numRecords += numAdded
+
+		now := time.Now()
+		if now.After(nextUpdate) {
+			nextUpdate = now.Add(indexLogFrequency)
+
+			log.Info("backfilling reward history",
+				zap.Uint64("height", height),
+				zap.Uint64("endHeight", endHeight),
+				zap.Int("numRecords", numRecords),
+			)
+		}
+
+		if height%indexIterationLimit == 0 {
+			// We must hold the lock during committing to make sure we don't
+			// attempt to commit to disk while a block is concurrently being
+			// accepted.
+			lock.Lock()
+			err := s.Commit()
+			lock.Unlock()
+			if err != nil {
+				return err
+			}
+
+			indexDuration := now.Sub(lastCommit)
+			sleepDuration := min(
+				indexIterationSleepMultiplier*indexDuration,
+				indexIterationSleepCap,
+			)
+			time.Sleep(sleepDuration)
+
+			// Make sure not to include the sleep duration into the next index
+			// duration.
+			lastCommit = time.Now()
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := s.singletonDB.Put(RewardHistoryBackfilledKey, nil); err != nil {
+		return fmt.Errorf("failed to mark reward history as backfilled: %w", err)
+	}
+	if err := s.Commit(); err != nil {
+		return err
+	}
+
+	log.Info("finished reward history backfill",
+		zap.Int("numRecords", numRecords),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+	return nil
+}
+
+func (s *state) IsRewardHistoryBackfilled() (bool, error) {
+	return s.singletonDB.Has(RewardHistoryBackfilledKey)
+}
+
+// backfilledStakers tracks the information about stakers that is needed to
+// backfill their records but that isn't kept once they are removed.
+type backfilledStakers struct {
+	// txID -> start time of the stakers that were added after Durango, as
+	// their start time is the time of the block that added them
+	start map[ids.ID]time.Time
+	// subnetID + nodeID -> the tx that most recently added the validator
+	validators map[subnetIDNodeID]*txs.Tx
+	// txIDs of the validators that accrued delegation fees that are paid
+	// when they are rewarded
+	accruedFees set.Set[ids.ID]
+}
+
+func newBackfilledStakers() *backfilledStakers {
+	return &backfilledStakers{
+		start:      make(map[ids.ID]time.Time),
+		validators: make(map[subnetIDNodeID]*txs.Tx),
+	}
+}
+
+func (b *backfilledStakers) addValidator(tx *txs.Tx) {
+	if validatorTx, ok := tx.Unsigned.(txs.ValidatorTx); ok {
+		b.validators[subnetIDNodeID{
+			subnetID: validatorTx.SubnetID(),
+			nodeID:   validatorTx.NodeID(),
+		}] = tx
+	}
+}
+
+// startTime returns the start of the staking period added by [tx].
+func (b *backfilledStakers) startTime(tx *txs.Tx) time.Time {
+	if startTime, ok := b.start[tx.ID()]; ok {
+		return startTime
+	}
+	if scheduledStaker, ok := tx.Unsigned.(txs.ScheduledStaker); ok {
+		return scheduledStaker.StartTime()
+	}
+	return time.Time{}
+}
+
+// backfillRewardHistoryAt adds the record of the staker whose reward was
+// decided by the block at [height], if any.
+func (s *state) backfillRewardHistoryAt(
+	height uint64,
+	parent block.Block,
+	stakers *backfilledStakers,
+) (block.Block, int, error) {
+	blkID, err := s.GetBlockIDAtHeight(height)
+	if err != nil {
+		return nil, 0, err
+	}
+	blk, err := s.GetStatelessBlock(blkID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if banffBlk, ok := blk.(block.BanffBlock); ok {
+		timestamp := banffBlk.Timestamp()
+		isDurangoActive := s.cfg.UpgradeConfig.IsDurangoActivated(timestamp)
+		for _, tx := range blk.Txs() {
+			if _, ok := tx.Unsigned.(txs.Staker); ok && isDurangoActive {
+				stakers.start[tx.ID()] = timestamp
+			}
+			stakers.addValidator(tx)
+		}
+	}
+
+	var rewarded bool
+	switch blk.(type) {
+	case *block.BanffCommitBlock, *block.ApricotCommitBlock:
+		rewarded = true
+	case *block.BanffAbortBlock, *block.ApricotAbortBlock:
+	default:
+		return blk, 0, nil
+	}
+
+	var proposalTx *txs.Tx
+	switch parent := parent.(type) {
+	case *block.BanffProposalBlock:
+		proposalTx = parent.Tx
+	case *block.ApricotProposalBlock:
+		proposalTx = parent.Tx
+	default:
+		return nil, 0, fmt.Errorf("unexpected parent block type %T", parent)
+	}
+
+	tx, ok := proposalTx.Unsigned.(*txs.RewardValidatorTx)
+	if !ok {
+		// Prior to Banff, validators were added by proposal blocks.
+		if rewarded {
+			stakers.addValidator(proposalTx)
+		}
+		return blk, 0, nil
+	}
+
+	record, err := s.newBackfilledRewardRecord(proposalTx.ID(), tx, rewarded, height, stakers)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.AddRewardRecord(record)
+	return blk, 1, nil
+}
+
+// newBackfilledRewardRecord returns the record of the staker that was removed
+// by [tx] based on the reward UTXOs of the staker.
+func (s *state) newBackfilledRewardRecord(
+	rewardTxID ids.ID,
+	tx *txs.RewardValidatorTx,
+	rewarded bool,
+	height uint64,
+	stakers *backfilledStakers,
+) (*RewardRecord, error) {
+	stakerTx, _, err := s.GetTx(tx.TxID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staker tx %s: %w", tx.TxID, err)
+	}
+	uStakerTx, ok := stakerTx.Unsigned.(txs.PermissionlessStaker)
+	if !ok {
+		return nil, fmt.Errorf("unexpected staker tx type %T", stakerTx.Unsigned)
+	}
+
+	startTime := stakers.startTime(stakerTx)
+	delete(stakers.start, tx.TxID)
+
+	rewardUTXOs, err := s.GetRewardUTXOs(tx.TxID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reward UTXOs of %s: %w", tx.TxID, err)
+	}
+
+	record := &RewardRecord{
+		TxID:       tx.TxID,
+		RewardTxID: rewardTxID,
+		Height:     height,
+		SubnetID:   uStakerTx.SubnetID(),
+		NodeID:     uStakerTx.NodeID(),
+		StartTime:  uint64(startTime.Unix()),
+		EndTime:    uint64(uStakerTx.EndTime().Unix()),
+		Weight:     uStakerTx.Weight(),
+		Rewarded:   rewarded,
+	}
+	switch uStakerTx := uStakerTx.(type) {
+	case txs.ValidatorTx:
+		// The delegation fees accrued since Cortina are paid along with the
+		// reward of the validator.
+		hasFee := stakers.accruedFees.Contains(tx.TxID)
+		stakers.accruedFees.Remove(tx.TxID)
+
+		record.Reward, record.DelegationFee = splitBackfilledRewards(
+			rewardUTXOs,
+			rewarded,
+			uStakerTx.ValidationRewardsOwner(),
+			uStakerTx.DelegationRewardsOwner(),
+			hasFee,
+		)
+		record.setAddresses(
+			uStakerTx.ValidationRewardsOwner(),
+			uStakerTx.DelegationRewardsOwner(),
+		)
+	case txs.DelegatorTx:
+		var (
+			validatorTx = stakers.validators[subnetIDNodeID{
+				subnetID: record.SubnetID,
+				nodeID:   record.NodeID,
+			}]
+			feeOwner     fx.Owner
+			shares       uint32
+			isFeeAccrued bool
+		)
+		if validatorTx != nil {
+			if uValidatorTx, ok := validatorTx.Unsigned.(txs.ValidatorTx); ok {
+				feeOwner = uValidatorTx.DelegationRewardsOwner()
+				shares = uValidatorTx.Shares()
+				isFeeAccrued = s.cfg.UpgradeConfig.IsCortinaActivated(stakers.startTime(validatorTx))
+			}
+		}
+
+		// If the fee isn't accrued by the validator, it is paid along with the
+		// reward of the delegator. The delegator is only paid nothing while
+		// paying a fee if the validator takes all of the reward.
+		hasFee := !isFeeAccrued && shares == reward.PercentDenominator
+		record.IsDelegator = true
+		record.Reward, record.DelegationFee = splitBackfilledRewards(
+			rewardUTXOs,
+			rewarded,
+			uStakerTx.RewardsOwner(),
+			feeOwner,
+			hasFee,
+		)
+		record.setAddresses(uStakerTx.RewardsOwner())
+
+		// The validator accrued a fee if the delegator was paid a reward, as
+		// the validator takes a non-zero share of it.
+		if rewarded && isFeeAccrued && shares > 0 && (record.Reward > 0 || shares == reward.PercentDenominator) {
+			stakers.accruedFees.Add(validatorTx.ID())
+		}
+	}
+	return record, nil
+}
+
+// splitBackfilledRewards returns the amount of [rewardUTXOs] that was paid as
+// the reward of the staker and the amount that was paid as a delegation fee.
+//
+// If the staker was rewarded, the reward of the staker is paid before the
+// delegation fee. Either is only paid if it is non-zero, so a single UTXO is
+// attributed to the owner it was paid to. If both owners are the same,
+// [hasFee] reports whether a non-zero delegation fee was paid.
+func splitBackfilledRewards(
+	rewardUTXOs []*avax.UTXO,
+	rewarded bool,
+	stakerOwner fx.Owner,
+	feeOwner fx.Owner,
+	hasFee bool,
+) (uint64, uint64) {
+	rewardUTXOs = slices.Clone(rewardUTXOs)
+	slices.SortFunc(rewardUTXOs, func(a, b *avax.UTXO) int {
+		return cmp.Compare(a.OutputIndex, b.OutputIndex)
+	})
+
+	var (
+		stakerReward  uint64
+		delegationFee uint64
+	)
+	for i, utxo := range rewardUTXOs {
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			continue
+		}
+
+		isStakerReward := rewarded && i == 0
+		if isStakerReward && len(rewardUTXOs) == 1 {
+			paidToStaker := isOwnedBy(&out.OutputOwners, stakerOwner)
+			paidAsFee := isOwnedBy(&out.OutputOwners, feeOwner)
+			if paidToStaker == paidAsFee {
+				isStakerReward = !hasFee
+			} else {
+				isStakerReward = paidToStaker
+			}
+		}
+
+		if isStakerReward {
+			stakerReward += out.Amount()
+		} else {
+			delegationFee += out.Amount()
+		}
+	}
+	return stakerReward, delegationFee
+}
+
+func isOwnedBy(owners *secp256k1fx.OutputOwners, owner fx.Owner) bool {
+	expected, ok := owner.(*secp256k1fx.OutputOwners)
+	return ok && owners.Equals(expected)
+}
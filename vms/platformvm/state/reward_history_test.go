@@ -0,0 +1,291 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestNewRewardRecord(t *testing.T) {
+	require := require.New(t)
+
+	s := newInitializedState(require)
+
+	staker, err := s.GetCurrentValidator(constants.PrimaryNetworkID, initialNodeID)
+	require.NoError(err)
+
+	rewardTxID := ids.GenerateTestID()
+	tx := &txs.RewardValidatorTx{TxID: staker.TxID}
+
+	record, err := NewRewardRecord(s, rewardTxID, tx, true /*=rewarded*/, 5)
+	require.NoError(err)
+	require.Equal(
+		&RewardRecord{
+			TxID:        staker.TxID,
+			RewardTxID:  rewardTxID,
+			Height:      5,
+			SubnetID:    constants.PrimaryNetworkID,
+			NodeID:      initialNodeID,
+			IsDelegator: false,
+			StartTime:   uint64(initialTime.Unix()),
+			EndTime:     uint64(initialValidatorEndTime.Unix()),
+			Weight:      units.Avax,
+			Rewarded:    true,
+			Reward:      staker.PotentialReward,
+			Addresses:   []ids.ShortID{},
+		},
+		record,
+	)
+
+	record, err = NewRewardRecord(s, rewardTxID, tx, false /*=rewarded*/, 5)
+	require.NoError(err)
+	require.False(record.Rewarded)
+	require.Zero(record.Reward)
+	require.Equal(staker.PotentialReward, record.ForfeitedReward)
+
+	_, err = NewRewardRecord(s, rewardTxID, &txs.RewardValidatorTx{TxID: ids.GenerateTestID()}, true /*=rewarded*/, 5)
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
+func TestRewardHistory(t *testing.T) {
+	require := require.New(t)
+
+	s, db := newUninitializedState(require)
+	initializeState(require, s)
+
+	var (
+		nodeID0 = ids.GenerateTestNodeID()
+		nodeID1 = ids.GenerateTestNodeID()
+		addr0   = ids.GenerateTestShortID()
+		addr1   = ids.GenerateTestShortID()
+
+		record0 = &RewardRecord{
+			TxID:      ids.ID{1},
+			Height:    2,
+			NodeID:    nodeID0,
+			Weight:    units.Avax,
+			Rewarded:  true,
+			Reward:    units.MilliAvax,
+			Addresses: []ids.ShortID{addr0},
+		}
+		record1 = &RewardRecord{
+			TxID:            ids.ID{2},
+			Height:          3,
+			NodeID:          nodeID0,
+			Weight:          units.Avax,
+			ForfeitedReward: units.MilliAvax,
+			Addresses:       []ids.ShortID{addr0, addr1},
+		}
+		record2 = &RewardRecord{
+			TxID:          ids.ID{3},
+			Height:        3,
+			NodeID:        nodeID1,
+			IsDelegator:   true,
+			Weight:        units.Avax,
+			Rewarded:      true,
+			Reward:        units.MilliAvax,
+			DelegationFee: units.MicroAvax,
+			Addresses:     []ids.ShortID{addr1},
+		}
+	)
+
+	_, err := s.GetNodeIDRewardHistory(nodeID0, RewardHistoryIndex{}, 10)
+	require.ErrorIs(err, ErrRewardHistoryNotIndexed)
+
+	require.NoError(s.initRewardHistory(true))
+	backfilled, err := s.IsRewardHistoryBackfilled()
+	require.NoError(err)
+	require.False(backfilled)
+
+	require.NoError(s.BackfillRewardHistory(&sync.Mutex{}, logging.NoLog{}))
+	backfilled, err = s.IsRewardHistoryBackfilled()
+	require.NoError(err)
+	require.True(backfilled)
+
+	for _, record := range []*RewardRecord{record0, record1, record2} {
+		s.AddRewardRecord(record)
+	}
+	require.NoError(s.Commit())
+
+	// Verify that the reward history is correctly loaded from disk.
+	s = newStateFromDB(require, db)
+
+	tests := []struct {
+		name     string
+		nodeID   ids.NodeID
+		addr     ids.ShortID
+		start    RewardHistoryIndex
+		limit    int
+		expected []*RewardRecord
+	}{
+		{
+			name:     "node ID",
+			nodeID:   nodeID0,
+			limit:    10,
+			expected: []*RewardRecord{record0, record1},
+		},
+		{
+			name:     "node ID with limit",
+			nodeID:   nodeID0,
+			limit:    1,
+			expected: []*RewardRecord{record0},
+		},
+		{
+			name:   "node ID after start",
+			nodeID: nodeID0,
+			start: RewardHistoryIndex{
+				Height: record0.Height,
+				TxID:   record0.TxID,
+			},
+			limit:    10,
+			expected: []*RewardRecord{record1},
+		},
+		{
+			name:     "address",
+			addr:     addr1,
+			limit:    10,
+			expected: []*RewardRecord{record1, record2},
+		},
+		{
+			name:  "unknown address",
+			addr:  ids.GenerateTestShortID(),
+			limit: 10,
+		},
+	}
+	for _, test := range tests {
+		var records []*RewardRecord
+		if test.addr != ids.ShortEmpty {
+			records, err = s.GetAddressRewardHistory(test.addr, test.start, test.limit)
+		} else {
+			records, err = s.GetNodeIDRewardHistory(test.nodeID, test.start, test.limit)
+		}
+		require.NoError(err, test.name)
+		require.Equal(test.expected, records, test.name)
+	}
+
+	// Disabling the index removes the reward history markers.
+	require.NoError(s.initRewardHistory(false))
+	_, err = s.GetAddressRewardHistory(addr0, RewardHistoryIndex{}, 10)
+	require.ErrorIs(err, ErrRewardHistoryNotIndexed)
+
+	backfilled, err = s.IsRewardHistoryBackfilled()
+	require.NoError(err)
+	require.False(backfilled)
+}
+
+func TestSplitBackfilledRewards(t *testing.T) {
+	var (
+		stakerOwner = &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{{1}},
+		}
+		feeOwner = &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{{2}},
+		}
+	)
+	newUTXO := func(outputIndex uint32, amount uint64, owner *secp256k1fx.OutputOwners) *avax.UTXO {
+		return &avax.UTXO{
+			UTXOID: avax.UTXOID{
+				OutputIndex: outputIndex,
+			},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:          amount,
+				OutputOwners: *owner,
+			},
+		}
+	}
+
+	tests := []struct {
+		name                  string
+		rewardUTXOs           []*avax.UTXO
+		rewarded              bool
+		feeOwner              *secp256k1fx.OutputOwners
+		hasFee                bool
+		expectedReward        uint64
+		expectedDelegationFee uint64
+	}{
+		{
+			name: "reward and fee",
+			rewardUTXOs: []*avax.UTXO{
+				newUTXO(3, 2, feeOwner),
+				newUTXO(2, 1, stakerOwner),
+			},
+			rewarded:              true,
+			feeOwner:              feeOwner,
+			expectedReward:        1,
+			expectedDelegationFee: 2,
+		},
+		{
+			name: "only reward",
+			rewardUTXOs: []*avax.UTXO{
+				newUTXO(2, 1, stakerOwner),
+			},
+			rewarded:       true,
+			feeOwner:       feeOwner,
+			expectedReward: 1,
+		},
+		{
+			name: "zero reward",
+			rewardUTXOs: []*avax.UTXO{
+				newUTXO(2, 2, feeOwner),
+			},
+			rewarded:              true,
+			feeOwner:              feeOwner,
+			expectedDelegationFee: 2,
+		},
+		{
+			name: "zero reward with the same owners",
+			rewardUTXOs: []*avax.UTXO{
+				newUTXO(2, 2, stakerOwner),
+			},
+			rewarded:              true,
+			feeOwner:              stakerOwner,
+			hasFee:                true,
+			expectedDelegationFee: 2,
+		},
+		{
+			name: "zero fee with the same owners",
+			rewardUTXOs: []*avax.UTXO{
+				newUTXO(2, 1, stakerOwner),
+			},
+			rewarded:       true,
+			feeOwner:       stakerOwner,
+			expectedReward: 1,
+		},
+		{
+			name: "not rewarded",
+			rewardUTXOs: []*avax.UTXO{
+				newUTXO(2, 2, stakerOwner),
+			},
+			feeOwner:              stakerOwner,
+			expectedDelegationFee: 2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reward, delegationFee := splitBackfilledRewards(
+				test.rewardUTXOs,
+				test.rewarded,
+				stakerOwner,
+				test.feeOwner,
+				test.hasFee,
+			)
+			require.Equal(t, test.expectedReward, reward)
+			require.Equal(t, test.expectedDelegationFee, delegationFee)
+		})
+	}
+}
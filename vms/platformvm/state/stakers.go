@@ -21,6 +21,10 @@ type CurrentStakers interface {
 	// [database.ErrNotFound] is returned.
 	GetCurrentValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker, error)
 
+	// GetCurrentValidators returns the [staker]s describing the validators on
+	// [subnetID] in no particular order.
+	GetCurrentValidators(subnetID ids.ID) ([]*Staker, error)
+
 	// PutCurrentValidator adds the [staker] describing a validator to the
 	// staker set.
 	//
@@ -131,6 +135,17 @@ func (v *baseStakers) GetValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker,
 	return validator.validator, nil
 }
 
+func (v *baseStakers) GetValidators(subnetID ids.ID) []*Staker {
+	subnetValidators := v.validators[subnetID]
+	validators := make([]*Staker, 0, len(subnetValidators))
+	for _, validator := range subnetValidators {
+		if validator.validator != nil {
+			validators = append(validators, validator.validator)
+		}
+	}
+	return validators
+}
+
 func (v *baseStakers) PutValidator(staker *Staker) {
 	validator := v.getOrCreateValidator(staker.SubnetID, staker.NodeID)
 	validator.validator = staker
@@ -288,6 +303,26 @@ func (s *diffStakers) GetValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker,
 	return nil, validatorDiff.validatorStatus
 }
 
+// GetValidators returns the validators of [subnetID] after applying this diff
+// to the [parentValidators] of the subnet.
+// Invariant: Assumes that the validator will never be removed and then added.
+func (s *diffStakers) GetValidators(parentValidators []*Staker, subnetID ids.ID) []*Staker {
+	subnetValidatorDiffs := s.validatorDiffs[subnetID]
+	validators := make([]*Staker, 0, len(parentValidators))
+	for _, validator := range parentValidators {
+		if validatorDiff, ok := subnetValidatorDiffs[validator.NodeID]; ok && validatorDiff.validatorStatus == deleted {
+			continue
+		}
+		validators = append(validators, validator)
+	}
+	for _, validatorDiff := range subnetValidatorDiffs {
+		if validatorDiff.validatorStatus == added {
+			validators = append(validators, validatorDiff.validator)
+		}
+	}
+	return validators
+}
+
 func (s *diffStakers) PutValidator(staker *Staker) {
 	validatorDiff := s.getOrCreateDiff(staker.SubnetID, staker.NodeID)
 	validatorDiff.validatorStatus = added
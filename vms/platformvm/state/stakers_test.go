@@ -83,6 +83,8 @@ func TestBaseStakersValidator(t *testing.T) {
 	_, err = v.GetValidator(delegator.SubnetID, delegator.NodeID)
 	require.ErrorIs(err, database.ErrNotFound)
 
+	require.Empty(v.GetValidators(delegator.SubnetID))
+
 	stakerIterator := v.GetStakerIterator()
 	assertIteratorsEqual(t, NewSliceIterator(delegator), stakerIterator)
 
@@ -92,6 +94,8 @@ func TestBaseStakersValidator(t *testing.T) {
 	require.NoError(err)
 	require.Equal(staker, returnedStaker)
 
+	require.Equal([]*Staker{staker}, v.GetValidators(staker.SubnetID))
+
 	v.DeleteDelegator(delegator)
 
 	stakerIterator = v.GetStakerIterator()
@@ -102,6 +106,8 @@ func TestBaseStakersValidator(t *testing.T) {
 	_, err = v.GetValidator(staker.SubnetID, staker.NodeID)
 	require.ErrorIs(err, database.ErrNotFound)
 
+	require.Empty(v.GetValidators(staker.SubnetID))
+
 	stakerIterator = v.GetStakerIterator()
 	assertIteratorsEqual(t, EmptyIterator, stakerIterator)
 }
@@ -178,6 +184,34 @@ func TestDiffStakersValidator(t *testing.T) {
 	assertIteratorsEqual(t, NewSliceIterator(delegator), stakerIterator)
 }
 
+func TestDiffStakersGetValidators(t *testing.T) {
+	require := require.New(t)
+
+	subnetID := ids.GenerateTestID()
+	newSubnetStaker := func() *Staker {
+		staker := newTestStaker()
+		staker.SubnetID = subnetID
+		return staker
+	}
+	var (
+		deletedStaker   = newSubnetStaker()
+		unchangedStaker = newSubnetStaker()
+		addedStaker     = newSubnetStaker()
+		otherStaker     = newTestStaker()
+	)
+
+	v := diffStakers{}
+	v.DeleteValidator(deletedStaker)
+	v.PutValidator(addedStaker)
+	v.PutValidator(otherStaker)
+
+	require.ElementsMatch(
+		[]*Staker{unchangedStaker, addedStaker},
+		v.GetValidators([]*Staker{deletedStaker, unchangedStaker}, subnetID),
+	)
+	require.Equal([]*Staker{otherStaker}, v.GetValidators(nil, otherStaker.SubnetID))
+}
+
 func TestDiffStakersDeleteValidator(t *testing.T) {
 	require := require.New(t)
 	staker := newTestStaker()
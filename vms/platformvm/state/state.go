@@ -8,12 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/google/btree"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
 
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/cache/metercacher"
@@ -29,7 +31,9 @@ import (
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/heap"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -76,23 +80,30 @@ var (
 	SubnetPrefix                  = []byte("subnet")
 	SubnetOwnerPrefix             = []byte("subnetOwner")
 	TransformedSubnetPrefix       = []byte("transformedSubnet")
+	SubnetManagerPrefix           = []byte("subnetManager")
+	SubnetOnlyValidatorsPrefix    = []byte("subnetOnlyValidators")
+	RewardHistoryPrefix           = []byte("rewardHistory")
 	SupplyPrefix                  = []byte("supply")
 	ChainPrefix                   = []byte("chain")
 	SingletonPrefix               = []byte("singleton")
 
-	TimestampKey       = []byte("timestamp")
-	FeeStateKey        = []byte("fee state")
-	CurrentSupplyKey   = []byte("current supply")
-	LastAcceptedKey    = []byte("last accepted")
-	HeightsIndexedKey  = []byte("heights indexed")
-	InitializedKey     = []byte("initialized")
-	BlocksReindexedKey = []byte("blocks reindexed")
+	TimestampKey                = []byte("timestamp")
+	FeeStateKey                 = []byte("fee state")
+	AccruedFeesKey              = []byte("accrued fees")
+	CurrentSupplyKey            = []byte("current supply")
+	LastAcceptedKey             = []byte("last accepted")
+	HeightsIndexedKey           = []byte("heights indexed")
+	InitializedKey              = []byte("initialized")
+	BlocksReindexedKey          = []byte("blocks reindexed")
+	RewardHistoryStartHeightKey = []byte("reward history start height")
+	RewardHistoryBackfilledKey  = []byte("reward history backfilled")
 )
 
 // Chain collects all methods to manage the state of the chain for block
 // execution.
 type Chain interface {
 	Stakers
+	SubnetOnlyValidators
 	avax.UTXOAdder
 	avax.UTXOGetter
 	avax.UTXODeleter
@@ -103,6 +114,11 @@ type Chain interface {
 	GetFeeState() fee.State
 	SetFeeState(f fee.State)
 
+	// GetAccruedFees returns the total amount of fees that a subnet-only
+	// validator would have paid if it had been active since the Etna upgrade.
+	GetAccruedFees() uint64
+	SetAccruedFees(f uint64)
+
 	GetCurrentSupply(subnetID ids.ID) (uint64, error)
 	SetCurrentSupply(subnetID ids.ID, cs uint64)
 
@@ -116,6 +132,12 @@ type Chain interface {
 	GetSubnetTransformation(subnetID ids.ID) (*txs.Tx, error)
 	AddSubnetTransformation(transformSubnetTx *txs.Tx)
 
+	// GetSubnetManager returns the chain and the address of the validator
+	// manager of [subnetID]. If the subnet wasn't converted,
+	// [database.ErrNotFound] is returned.
+	GetSubnetManager(subnetID ids.ID) (ids.ID, []byte, error)
+	SetSubnetManager(subnetID ids.ID, chainID ids.ID, addr []byte)
+
 	AddChain(createChainTx *txs.Tx)
 
 	GetTx(txID ids.ID) (*txs.Tx, status.Status, error)
@@ -138,6 +160,14 @@ type State interface {
 	GetBlockIDAtHeight(height uint64) (ids.ID, error)
 
 	GetRewardUTXOs(txID ids.ID) ([]*avax.UTXO, error)
+
+	// NewUTXOIterator returns an iterator over the committed UTXOs ordered by
+	// ID. The iterator reads the UTXOs that were committed when it was
+	// created, so the state may be modified while iterating.
+	//
+	// Invariant: There are no uncommitted UTXO modifications.
+	NewUTXOIterator() avax.UTXOIterator
+
 	GetSubnetIDs() ([]ids.ID, error)
 	GetChains(subnetID ids.ID) ([]*txs.Tx, error)
 
@@ -176,13 +206,82 @@ type State interface {
 		validators map[ids.NodeID]*validators.GetValidatorOutput,
 		startHeight uint64,
 		endHeight uint64,
+		subnetID ids.ID,
 	) error
 
+	// GetValidatorWeightDiffs returns the net change to the weights on
+	// [subnetID] of the nodes in [nodeIDs] made by the blocks with heights in
+	// [endHeight, startHeight]. Nodes whose weight wasn't modified are not
+	// included.
+	//
+	// Note: Because this function iterates towards the genesis, [startHeight]
+	// will typically be greater than or equal to [endHeight]. If [startHeight]
+	// is less than [endHeight], no diffs will be returned.
+	GetValidatorWeightDiffs(
+		ctx context.Context,
+		nodeIDs set.Set[ids.NodeID],
+		startHeight uint64,
+		endHeight uint64,
+		subnetID ids.ID,
+	) (map[ids.NodeID]*ValidatorWeightDiff, error)
+
+	// GetModifiedValidators returns, in sorted order, at most [limit] of the
+	// IDs greater than [startNodeID] of the nodes whose weight or public key
+	// on [subnetID] may have been modified by the blocks with heights in
+	// [startHeight, endHeight].
+	GetModifiedValidators(
+		ctx context.Context,
+		subnetID ids.ID,
+		startHeight uint64,
+		endHeight uint64,
+		startNodeID ids.NodeID,
+		limit int,
+	) ([]ids.NodeID, error)
+
 	SetHeight(height uint64)
 
 	// Discard uncommitted changes to the database.
 	Abort()
 
+	// AddRewardRecord adds [record] to the reward history. The record is
+	// persisted on the next commit.
+	AddRewardRecord(record *RewardRecord)
+
+	// GetNodeIDRewardHistory returns up to [limit] records of the staking
+	// periods of [nodeID] after [start], ordered by height.
+	//
+	// Returns [ErrRewardHistoryNotIndexed] if the reward history isn't
+	// enabled.
+	GetNodeIDRewardHistory(
+		nodeID ids.NodeID,
+		start RewardHistoryIndex,
+		limit int,
+	) ([]*RewardRecord, error)
+
+	// GetAddressRewardHistory returns up to [limit] records of the staking
+	// periods rewarding [addr] after [start], ordered by height.
+	//
+	// Returns [ErrRewardHistoryNotIndexed] if the reward history isn't
+	// enabled.
+	GetAddressRewardHistory(
+		addr ids.ShortID,
+		start RewardHistoryIndex,
+		limit int,
+	) ([]*RewardRecord, error)
+
+	// BackfillRewardHistory indexes the staking periods that ended before the
+	// reward history was enabled. If the reward history isn't enabled, or was
+	// already backfilled, this is a noop.
+	//
+	// Note: [lock] is grabbed while accessing the state, so this function can
+	// be called concurrently with block acceptance.
+	BackfillRewardHistory(lock sync.Locker, log logging.Logger) error
+
+	// IsRewardHistoryBackfilled returns true if the staking periods that ended
+	// before the reward history was enabled have been indexed. Until then, the
+	// reward history only contains the staking periods that ended afterwards.
+	IsRewardHistoryBackfilled() (bool, error)
+
 	// ReindexBlocks converts any block indices using the legacy storage format
 	// to the new format. If this database has already updated the indices,
 	// this function will return immediately, without iterating over the
@@ -246,6 +345,8 @@ type stateBlk struct {
  * | | '-- subnet+height+nodeID -> weightChange
  * | '-. pub key diffs
  * |   '-- subnet+height+nodeID -> uncompressed public key or nil
+ * |-. subnetOnlyValidators
+ * | '-- validationID -> subnetOnlyValidator
  * |-. blockIDs
  * | '-- height -> blockID
  * |-. blocks
@@ -263,6 +364,8 @@ type stateBlk struct {
  * |   '-- txID -> nil
  * |-. subnetOwners
  * | '-. subnetID -> owner
+ * |-. subnetManagers
+ * | '-. subnetID -> manager chainID + address
  * |-. chains
  * | '-. subnetID
  * |   '-. list
@@ -272,6 +375,7 @@ type stateBlk struct {
  *   |-- blocksReindexedKey -> nil
  *   |-- timestampKey -> timestamp
  *   |-- feeStateKey -> feeState
+ *   |-- accruedFeesKey -> accruedFees
  *   |-- currentSupplyKey -> currentSupply
  *   |-- lastAcceptedKey -> lastAccepted
  *   '-- heightsIndexKey -> startIndexHeight + endIndexHeight
@@ -323,6 +427,18 @@ type state struct {
 	validatorWeightDiffsDB    database.Database
 	validatorPublicKeyDiffsDB database.Database
 
+	subnetOnlyValidatorsDiff *subnetOnlyValidatorsDiff
+	// validationID -> validator; only contains persisted validators that
+	// haven't been removed
+	subnetOnlyValidators map[ids.ID]SubnetOnlyValidator
+	// subnetID -> nodeID -> validationID of the persisted validators that
+	// haven't been removed
+	subnetOnlyValidatorIDs map[ids.ID]map[ids.NodeID]ids.ID
+	// subnetID -> total weight of the persisted validators that haven't been
+	// removed
+	subnetOnlyValidatorWeights map[ids.ID]uint64
+	subnetOnlyValidatorsDB     database.Database
+
 	addedTxs map[ids.ID]*txAndStatus            // map of txID -> {*txs.Tx, Status}
 	txCache  cache.Cacher[ids.ID, *txAndStatus] // txID -> {*txs.Tx, Status}; if the entry is nil, it is not in the database
 	txDB     database.Database
@@ -331,6 +447,12 @@ type state struct {
 	rewardUTXOsCache cache.Cacher[ids.ID, []*avax.UTXO] // txID -> []*UTXO
 	rewardUTXODB     database.Database
 
+	addedRewardRecords   []*RewardRecord
+	rewardHistoryDB      database.Database
+	rewardRecordDB       database.Database // staker txID -> record
+	rewardNodeIDIndexDB  database.Database // nodeID + height + staker txID -> nil
+	rewardAddressIndexDB database.Database // address + height + staker txID -> nil
+
 	modifiedUTXOs map[ids.ID]*avax.UTXO // map of modified UTXOID -> *UTXO; if the UTXO is nil, it has been removed
 	utxoDB        database.Database
 	utxoState     avax.UTXOState
@@ -344,6 +466,9 @@ type state struct {
 	subnetOwnerCache cache.Cacher[ids.ID, fxOwnerAndSize] // cache of subnetID -> owner; if the entry is nil, it is not in the database
 	subnetOwnerDB    database.Database
 
+	subnetManagers  map[ids.ID]subnetManager // map of subnetID -> manager of the subnet
+	subnetManagerDB database.Database
+
 	transformedSubnets     map[ids.ID]*txs.Tx            // map of subnetID -> transformSubnetTx
 	transformedSubnetCache cache.Cacher[ids.ID, *txs.Tx] // cache of subnetID -> transformSubnetTx; if the entry is nil, it is not in the database
 	transformedSubnetDB    database.Database
@@ -360,6 +485,7 @@ type state struct {
 	// The persisted fields represent the current database value
 	timestamp, persistedTimestamp         time.Time
 	feeState, persistedFeeState           fee.State
+	accruedFees, persistedAccruedFees     uint64
 	currentSupply, persistedCurrentSupply uint64
 	// [lastAccepted] is the most recently accepted block.
 	lastAccepted, persistedLastAccepted ids.ID
@@ -409,6 +535,11 @@ type txAndStatus struct {
 	status status.Status
 }
 
+type subnetManager struct {
+	ChainID ids.ID `serialize:"true"`
+	Addr    []byte `serialize:"true"`
+}
+
 type fxOwnerAndSize struct {
 	owner fx.Owner
 	size  int
@@ -465,6 +596,13 @@ func New(
 		return nil, err
 	}
 
+	if err := s.initRewardHistory(execCfg.IndexRewardHistory); err != nil {
+		// Drop any errors on close to return the first error
+		_ = s.Close()
+
+		return nil, err
+	}
+
 	return s, nil
 }
 
@@ -533,6 +671,8 @@ func newState(
 		return nil, err
 	}
 
+	rewardHistoryDB := prefixdb.New(RewardHistoryPrefix, baseDB)
+
 	utxoDB := prefixdb.New(UTXOPrefix, baseDB)
 	utxoState, err := avax.NewMeteredUTXOState(utxoDB, txs.GenesisCodec, metricsReg, execCfg.ChecksumsEnabled)
 	if err != nil {
@@ -632,6 +772,12 @@ func newState(
 		validatorWeightDiffsDB:       validatorWeightDiffsDB,
 		validatorPublicKeyDiffsDB:    validatorPublicKeyDiffsDB,
 
+		subnetOnlyValidatorsDiff:   newSubnetOnlyValidatorsDiff(),
+		subnetOnlyValidators:       make(map[ids.ID]SubnetOnlyValidator),
+		subnetOnlyValidatorIDs:     make(map[ids.ID]map[ids.NodeID]ids.ID),
+		subnetOnlyValidatorWeights: make(map[ids.ID]uint64),
+		subnetOnlyValidatorsDB:     prefixdb.New(SubnetOnlyValidatorsPrefix, baseDB),
+
 		addedTxs: make(map[ids.ID]*txAndStatus),
 		txDB:     prefixdb.New(TxPrefix, baseDB),
 		txCache:  txCache,
@@ -640,6 +786,11 @@ func newState(
 		rewardUTXODB:     rewardUTXODB,
 		rewardUTXOsCache: rewardUTXOsCache,
 
+		rewardHistoryDB:      rewardHistoryDB,
+		rewardRecordDB:       prefixdb.New(rewardRecordPrefix, rewardHistoryDB),
+		rewardNodeIDIndexDB:  prefixdb.New(rewardNodeIDIndexPrefix, rewardHistoryDB),
+		rewardAddressIndexDB: prefixdb.New(rewardAddressIndexPrefix, rewardHistoryDB),
+
 		modifiedUTXOs: make(map[ids.ID]*avax.UTXO),
 		utxoDB:        utxoDB,
 		utxoState:     utxoState,
@@ -651,6 +802,9 @@ func newState(
 		subnetOwnerDB:    subnetOwnerDB,
 		subnetOwnerCache: subnetOwnerCache,
 
+		subnetManagers:  make(map[ids.ID]subnetManager),
+		subnetManagerDB: prefixdb.New(SubnetManagerPrefix, baseDB),
+
 		transformedSubnets:     make(map[ids.ID]*txs.Tx),
 		transformedSubnetCache: transformedSubnetCache,
 		transformedSubnetDB:    prefixdb.New(TransformedSubnetPrefix, baseDB),
@@ -672,6 +826,10 @@ func (s *state) GetCurrentValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker
 	return s.currentStakers.GetValidator(subnetID, nodeID)
 }
 
+func (s *state) GetCurrentValidators(subnetID ids.ID) ([]*Staker, error) {
+	return s.currentStakers.GetValidators(subnetID), nil
+}
+
 func (s *state) PutCurrentValidator(staker *Staker) {
 	s.currentStakers.PutValidator(staker)
 }
@@ -696,6 +854,52 @@ func (s *state) GetCurrentStakerIterator() (StakerIterator, error) {
 	return s.currentStakers.GetStakerIterator(), nil
 }
 
+func (s *state) GetActiveSubnetOnlyValidators() ([]SubnetOnlyValidator, error) {
+	active := make([]SubnetOnlyValidator, 0, len(s.subnetOnlyValidators))
+	for _, sov := range s.subnetOnlyValidators {
+		if sov.IsActive() {
+			active = append(active, sov)
+		}
+	}
+	return s.subnetOnlyValidatorsDiff.getActive(active), nil
+}
+
+func (s *state) GetSubnetOnlyValidator(validationID ids.ID) (SubnetOnlyValidator, error) {
+	if sov, modified := s.subnetOnlyValidatorsDiff.modified[validationID]; modified {
+		return sov, nil
+	}
+	if sov, ok := s.subnetOnlyValidators[validationID]; ok {
+		return sov, nil
+	}
+	// Removed validators are only kept on disk.
+	return getSubnetOnlyValidator(s.subnetOnlyValidatorsDB, validationID)
+}
+
+func (s *state) HasSubnetOnlyValidator(subnetID ids.ID, nodeID ids.NodeID) (bool, error) {
+	if has, modified := s.subnetOnlyValidatorsDiff.hasSubnetOnlyValidator(subnetID, nodeID); modified {
+		return has, nil
+	}
+	_, has := s.subnetOnlyValidatorIDs[subnetID][nodeID]
+	return has, nil
+}
+
+func (s *state) GetSubnetOnlyValidators(subnetID ids.ID) ([]SubnetOnlyValidator, error) {
+	validationIDs := s.subnetOnlyValidatorIDs[subnetID]
+	sovs := make([]SubnetOnlyValidator, 0, len(validationIDs))
+	for _, validationID := range validationIDs {
+		sovs = append(sovs, s.subnetOnlyValidators[validationID])
+	}
+	return s.subnetOnlyValidatorsDiff.getSubnet(subnetID, sovs), nil
+}
+
+func (s *state) WeightOfSubnetOnlyValidators(subnetID ids.ID) (uint64, error) {
+	return s.subnetOnlyValidatorsDiff.weightOfSubnetOnlyValidators(subnetID, s.subnetOnlyValidatorWeights[subnetID])
+}
+
+func (s *state) PutSubnetOnlyValidator(sov SubnetOnlyValidator) error {
+	return s.subnetOnlyValidatorsDiff.putSubnetOnlyValidator(s, sov)
+}
+
 func (s *state) GetPendingValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker, error) {
 	return s.pendingStakers.GetValidator(subnetID, nodeID)
 }
@@ -814,6 +1018,30 @@ func (s *state) SetSubnetOwner(subnetID ids.ID, owner fx.Owner) {
 	s.subnetOwners[subnetID] = owner
 }
 
+func (s *state) GetSubnetManager(subnetID ids.ID) (ids.ID, []byte, error) {
+	if manager, exists := s.subnetManagers[subnetID]; exists {
+		return manager.ChainID, manager.Addr, nil
+	}
+
+	managerBytes, err := s.subnetManagerDB.Get(subnetID[:])
+	if err != nil {
+		return ids.Empty, nil, err
+	}
+
+	var manager subnetManager
+	if _, err := block.GenesisCodec.Unmarshal(managerBytes, &manager); err != nil {
+		return ids.Empty, nil, err
+	}
+	return manager.ChainID, manager.Addr, nil
+}
+
+func (s *state) SetSubnetManager(subnetID ids.ID, chainID ids.ID, addr []byte) {
+	s.subnetManagers[subnetID] = subnetManager{
+		ChainID: chainID,
+		Addr:    addr,
+	}
+}
+
 func (s *state) GetSubnetTransformation(subnetID ids.ID) (*txs.Tx, error) {
 	if tx, exists := s.transformedSubnets[subnetID]; exists {
 		return tx, nil
@@ -988,6 +1216,10 @@ func (s *state) UTXOIDs(addr []byte, start ids.ID, limit int) ([]ids.ID, error)
 	return s.utxoState.UTXOIDs(addr, start, limit)
 }
 
+func (s *state) NewUTXOIterator() avax.UTXOIterator {
+	return s.utxoState.NewUTXOIterator()
+}
+
 func (s *state) AddUTXO(utxo *avax.UTXO) {
 	s.modifiedUTXOs[utxo.InputID()] = utxo
 }
@@ -1020,6 +1252,14 @@ func (s *state) SetFeeState(feeState fee.State) {
 	s.feeState = feeState
 }
 
+func (s *state) GetAccruedFees() uint64 {
+	return s.accruedFees
+}
+
+func (s *state) SetAccruedFees(accruedFees uint64) {
+	s.accruedFees = accruedFees
+}
+
 func (s *state) GetLastAccepted() ids.ID {
 	return s.lastAccepted
 }
@@ -1164,10 +1404,11 @@ func (s *state) ApplyValidatorPublicKeyDiffs(
 	validators map[ids.NodeID]*validators.GetValidatorOutput,
 	startHeight uint64,
 	endHeight uint64,
+	subnetID ids.ID,
 ) error {
 	diffIter := s.validatorPublicKeyDiffsDB.NewIteratorWithStartAndPrefix(
-		marshalStartDiffKey(constants.PrimaryNetworkID, startHeight),
-		constants.PrimaryNetworkID[:],
+		marshalStartDiffKey(subnetID, startHeight),
+		subnetID[:],
 	)
 	defer diffIter.Release()
 
@@ -1208,6 +1449,123 @@ func (s *state) ApplyValidatorPublicKeyDiffs(
 	return diffIter.Error()
 }
 
+func (s *state) GetValidatorWeightDiffs(
+	ctx context.Context,
+	nodeIDs set.Set[ids.NodeID],
+	startHeight uint64,
+	endHeight uint64,
+	subnetID ids.ID,
+) (map[ids.NodeID]*ValidatorWeightDiff, error) {
+	diffIter := s.validatorWeightDiffsDB.NewIteratorWithStartAndPrefix(
+		marshalStartDiffKey(subnetID, startHeight),
+		subnetID[:],
+	)
+	defer diffIter.Release()
+
+	weightDiffs := make(map[ids.NodeID]*ValidatorWeightDiff)
+	for diffIter.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		_, parsedHeight, nodeID, err := unmarshalDiffKey(diffIter.Key())
+		if err != nil {
+			return nil, err
+		}
+		// If the parsedHeight is less than our target endHeight, then we have
+		// fully processed the diffs from startHeight through endHeight.
+		if parsedHeight < endHeight {
+			break
+		}
+		if !nodeIDs.Contains(nodeID) {
+			continue
+		}
+
+		weightDiff, err := unmarshalWeightDiff(diffIter.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		netDiff, ok := weightDiffs[nodeID]
+		if !ok {
+			netDiff = &ValidatorWeightDiff{}
+			weightDiffs[nodeID] = netDiff
+		}
+		if err := netDiff.Add(weightDiff.Decrease, weightDiff.Amount); err != nil {
+			return nil, err
+		}
+	}
+	return weightDiffs, diffIter.Error()
+}
+
+func (s *state) GetModifiedValidators(
+	ctx context.Context,
+	subnetID ids.ID,
+	startHeight uint64,
+	endHeight uint64,
+	startNodeID ids.NodeID,
+	limit int,
+) ([]ids.NodeID, error) {
+	// [nodeIDs] is a max heap, so the largest node ID can be evicted once more
+	// than [limit] node IDs have been found.
+	nodeIDs := heap.NewSet(func(a, b ids.NodeID) bool {
+		return a.Compare(b) > 0
+	})
+	addModifiedValidators := func(db database.Iteratee, diffSubnetID ids.ID) error {
+		diffIter := db.NewIteratorWithStartAndPrefix(
+			marshalStartDiffKey(diffSubnetID, endHeight),
+			diffSubnetID[:],
+		)
+		defer diffIter.Release()
+
+		for diffIter.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			_, parsedHeight, nodeID, err := unmarshalDiffKey(diffIter.Key())
+			if err != nil {
+				return err
+			}
+			// Because the heights are iterated in decreasing order, all the
+			// remaining diffs were made prior to [startHeight].
+			if parsedHeight < startHeight {
+				break
+			}
+			if nodeID.Compare(startNodeID) <= 0 {
+				continue
+			}
+
+			nodeIDs.Push(nodeID)
+			if nodeIDs.Len() > limit {
+				_, _ = nodeIDs.Pop()
+			}
+		}
+		return diffIter.Error()
+	}
+
+	if err := addModifiedValidators(s.validatorWeightDiffsDB, subnetID); err != nil {
+		return nil, err
+	}
+	// Subnet-only validators record their public key diffs on their subnet.
+	if err := addModifiedValidators(s.validatorPublicKeyDiffsDB, subnetID); err != nil {
+		return nil, err
+	}
+	// Legacy subnet validators use their primary network public keys, which
+	// may change without the weight of the validator on the subnet changing.
+	if subnetID != constants.PrimaryNetworkID {
+		if err := addModifiedValidators(s.validatorPublicKeyDiffsDB, constants.PrimaryNetworkID); err != nil {
+			return nil, err
+		}
+	}
+
+	nodeIDList := make([]ids.NodeID, nodeIDs.Len())
+	for i := len(nodeIDList) - 1; i >= 0; i-- {
+		nodeIDList[i], _ = nodeIDs.Pop()
+	}
+	return nodeIDList, nil
+}
+
 func (s *state) syncGenesis(genesisBlk block.Block, genesis *genesis.Genesis) error {
 	genesisBlkID := genesisBlk.ID()
 	s.SetLastAccepted(genesisBlkID)
@@ -1290,6 +1648,7 @@ func (s *state) load() error {
 		s.loadMetadata(),
 		s.loadCurrentValidators(),
 		s.loadPendingValidators(),
+		s.loadSubnetOnlyValidators(),
 		s.initValidatorSets(),
 	)
 }
@@ -1309,6 +1668,16 @@ func (s *state) loadMetadata() error {
 	s.persistedFeeState = feeState
 	s.SetFeeState(feeState)
 
+	// Prior to Etna, no fees have been accrued.
+	accruedFees, err := database.GetUInt64(s.singletonDB, AccruedFeesKey)
+	if err == database.ErrNotFound {
+		accruedFees = 0
+	} else if err != nil {
+		return err
+	}
+	s.persistedAccruedFees = accruedFees
+	s.SetAccruedFees(accruedFees)
+
 	currentSupply, err := database.GetUInt64(s.singletonDB, CurrentSupplyKey)
 	if err != nil {
 		return err
@@ -1606,8 +1975,37 @@ func (s *state) loadPendingValidators() error {
 	)
 }
 
-// Invariant: initValidatorSets requires loadCurrentValidators to have already
-// been called.
+func (s *state) loadSubnetOnlyValidators() error {
+	it := s.subnetOnlyValidatorsDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		validationID, err := ids.ToID(it.Key())
+		if err != nil {
+			return err
+		}
+
+		sov := SubnetOnlyValidator{
+			ValidationID: validationID,
+		}
+		if _, err := block.GenesisCodec.Unmarshal(it.Value(), &sov); err != nil {
+			return fmt.Errorf("failed to unmarshal SubnetOnlyValidator: %w", err)
+		}
+		if sov.IsRemoved() {
+			continue
+		}
+
+		s.subnetOnlyValidators[validationID] = sov
+		s.putSubnetOnlyValidatorID(sov)
+		s.subnetOnlyValidatorWeights[sov.SubnetID], err = safemath.Add(s.subnetOnlyValidatorWeights[sov.SubnetID], sov.Weight)
+		if err != nil {
+			return fmt.Errorf("failed to load weight of subnet %s: %w", sov.SubnetID, err)
+		}
+	}
+	return it.Error()
+}
+
+// Invariant: initValidatorSets requires loadCurrentValidators and
+// loadSubnetOnlyValidators to have already been called.
 func (s *state) initValidatorSets() error {
 	for subnetID, validators := range s.currentStakers.validators {
 		if s.validators.Count(subnetID) != 0 {
@@ -1633,6 +2031,17 @@ func (s *state) initValidatorSets() error {
 		}
 	}
 
+	for _, sov := range s.subnetOnlyValidators {
+		if !sov.IsActive() {
+			continue
+		}
+
+		pk := bls.PublicKeyFromValidUncompressedBytes(sov.PublicKey)
+		if err := s.validators.AddStaker(sov.SubnetID, sov.NodeID, pk, sov.ValidationID, sov.Weight); err != nil {
+			return err
+		}
+	}
+
 	s.metrics.SetLocalStake(s.validators.GetWeight(constants.PrimaryNetworkID, s.ctx.NodeID))
 	totalWeight, err := s.validators.TotalWeight(constants.PrimaryNetworkID)
 	if err != nil {
@@ -1653,11 +2062,15 @@ func (s *state) write(updateValidators bool, height uint64) error {
 		s.writeCurrentStakers(updateValidators, height, codecVersion),
 		s.writePendingStakers(),
 		s.WriteValidatorMetadata(s.currentValidatorList, s.currentSubnetValidatorList, codecVersion), // Must be called after writeCurrentStakers
+		// Must be called after writeCurrentStakers
+		s.writeSubnetOnlyValidators(updateValidators, height),
 		s.writeTXs(),
 		s.writeRewardUTXOs(),
+		s.writeRewardHistory(),
 		s.writeUTXOs(),
 		s.writeSubnets(),
 		s.writeSubnetOwners(),
+		s.writeSubnetManagers(),
 		s.writeTransformedSubnets(),
 		s.writeSubnetSupplies(),
 		s.writeChains(),
@@ -1678,10 +2091,16 @@ func (s *state) Close() error {
 		s.currentValidatorBaseDB.Close(),
 		s.currentValidatorsDB.Close(),
 		s.validatorsDB.Close(),
+		s.subnetOnlyValidatorsDB.Close(),
 		s.txDB.Close(),
 		s.rewardUTXODB.Close(),
+		s.rewardRecordDB.Close(),
+		s.rewardNodeIDIndexDB.Close(),
+		s.rewardAddressIndexDB.Close(),
+		s.rewardHistoryDB.Close(),
 		s.utxoDB.Close(),
 		s.subnetBaseDB.Close(),
+		s.subnetManagerDB.Close(),
 		s.transformedSubnetDB.Close(),
 		s.supplyDB.Close(),
 		s.chainDB.Close(),
@@ -2036,6 +2455,148 @@ func (s *state) writeCurrentStakers(updateValidators bool, height uint64, codecV
 	return nil
 }
 
+func (s *state) writeSubnetOnlyValidators(updateValidators bool, height uint64) error {
+	// The validators are sorted so that the validator set is modified
+	// deterministically. Removed validators are written first so that a
+	// subnetID + nodeID pair can be removed and re-added in the same block.
+	modified := maps.Values(s.subnetOnlyValidatorsDiff.modified)
+	slices.SortFunc(modified, func(a, b SubnetOnlyValidator) int {
+		if a.IsRemoved() != b.IsRemoved() {
+			if a.IsRemoved() {
+				return -1
+			}
+			return 1
+		}
+		return a.ValidationID.Compare(b.ValidationID)
+	})
+	s.subnetOnlyValidatorsDiff = newSubnetOnlyValidatorsDiff()
+
+	for _, sov := range modified {
+		var prevSOV SubnetOnlyValidator
+		if prev, ok := s.subnetOnlyValidators[sov.ValidationID]; ok {
+			prevSOV = prev
+		}
+
+		if err := putSubnetOnlyValidator(s.subnetOnlyValidatorsDB, sov); err != nil {
+			return err
+		}
+
+		if sov.IsRemoved() {
+			delete(s.subnetOnlyValidators, sov.ValidationID)
+			s.deleteSubnetOnlyValidatorID(sov)
+		} else {
+			s.subnetOnlyValidators[sov.ValidationID] = sov
+			s.putSubnetOnlyValidatorID(sov)
+		}
+
+		// The total weight was verified when the validators were put, so it
+		// is only updated here. Wrapping is fine because the final total is
+		// known to fit even if an intermediate value doesn't.
+		subnetWeight := s.subnetOnlyValidatorWeights[sov.SubnetID] - prevSOV.Weight + sov.Weight
+		if subnetWeight == 0 {
+			delete(s.subnetOnlyValidatorWeights, sov.SubnetID)
+		} else {
+			s.subnetOnlyValidatorWeights[sov.SubnetID] = subnetWeight
+		}
+
+		prevWeight := prevSOV.activeWeight()
+		newWeight := sov.activeWeight()
+		if prevWeight == newWeight {
+			continue
+		}
+
+		// A validator with the same subnetID + nodeID pair may have been
+		// removed in this block, so the weight diff must be merged with any
+		// diff that was already written at this height.
+		diffKey := marshalDiffKey(sov.SubnetID, height, sov.NodeID)
+		weightDiff := &ValidatorWeightDiff{}
+		weightDiffBytes, err := s.validatorWeightDiffsDB.Get(diffKey)
+		switch err {
+		case nil:
+			weightDiff, err = unmarshalWeightDiff(weightDiffBytes)
+			if err != nil {
+				return err
+			}
+		case database.ErrNotFound:
+		default:
+			return err
+		}
+		if err := weightDiff.Add(true, prevWeight); err != nil {
+			return err
+		}
+		if err := weightDiff.Add(false, newWeight); err != nil {
+			return err
+		}
+		if weightDiff.Amount == 0 {
+			err = s.validatorWeightDiffsDB.Delete(diffKey)
+		} else {
+			err = s.validatorWeightDiffsDB.Put(diffKey, marshalWeightDiff(weightDiff))
+		}
+		if err != nil {
+			return err
+		}
+
+		// If a public key diff was already written at this height, it holds the
+		// public key prior to this block, so it must not be overwritten.
+		hasPublicKeyDiff, err := s.validatorPublicKeyDiffsDB.Has(diffKey)
+		if err != nil {
+			return err
+		}
+		switch {
+		case hasPublicKeyDiff:
+		case prevWeight == 0:
+			// Record that the public key for the validator is being added.
+			// This means the prior value for the public key was nil.
+			err = s.validatorPublicKeyDiffsDB.Put(diffKey, nil)
+		case newWeight == 0:
+			// Record that the public key for the validator is being removed.
+			// This means we must record the prior value of the public key.
+			err = s.validatorPublicKeyDiffsDB.Put(diffKey, prevSOV.PublicKey)
+		}
+		if err != nil {
+			return err
+		}
+
+		// TODO: Move the validator set management out of the state package
+		if !updateValidators {
+			continue
+		}
+
+		switch {
+		case prevWeight == 0:
+			pk := bls.PublicKeyFromValidUncompressedBytes(sov.PublicKey)
+			err = s.validators.AddStaker(sov.SubnetID, sov.NodeID, pk, sov.ValidationID, newWeight)
+		case newWeight == 0:
+			err = s.validators.RemoveWeight(sov.SubnetID, sov.NodeID, prevWeight)
+		case prevWeight < newWeight:
+			err = s.validators.AddWeight(sov.SubnetID, sov.NodeID, newWeight-prevWeight)
+		default:
+			err = s.validators.RemoveWeight(sov.SubnetID, sov.NodeID, prevWeight-newWeight)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update subnet-only validator weight: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *state) putSubnetOnlyValidatorID(sov SubnetOnlyValidator) {
+	validationIDs, ok := s.subnetOnlyValidatorIDs[sov.SubnetID]
+	if !ok {
+		validationIDs = make(map[ids.NodeID]ids.ID)
+		s.subnetOnlyValidatorIDs[sov.SubnetID] = validationIDs
+	}
+	validationIDs[sov.NodeID] = sov.ValidationID
+}
+
+func (s *state) deleteSubnetOnlyValidatorID(sov SubnetOnlyValidator) {
+	validationIDs := s.subnetOnlyValidatorIDs[sov.SubnetID]
+	delete(validationIDs, sov.NodeID)
+	if len(validationIDs) == 0 {
+		delete(s.subnetOnlyValidatorIDs, sov.SubnetID)
+	}
+}
+
 func writeCurrentDelegatorDiff(
 	currentDelegatorList linkeddb.LinkedDB,
 	weightDiff *ValidatorWeightDiff,
@@ -2233,6 +2794,23 @@ func (s *state) writeSubnetOwners() error {
 	return nil
 }
 
+func (s *state) writeSubnetManagers() error {
+	for subnetID, manager := range s.subnetManagers {
+		subnetID := subnetID
+		manager := manager
+		delete(s.subnetManagers, subnetID)
+
+		managerBytes, err := block.GenesisCodec.Marshal(block.CodecVersion, &manager)
+		if err != nil {
+			return fmt.Errorf("failed to marshal subnet manager: %w", err)
+		}
+		if err := s.subnetManagerDB.Put(subnetID[:], managerBytes); err != nil {
+			return fmt.Errorf("failed to write subnet manager: %w", err)
+		}
+	}
+	return nil
+}
+
 func (s *state) writeTransformedSubnets() error {
 	for subnetID, tx := range s.transformedSubnets {
 		txID := tx.ID()
@@ -2289,6 +2867,12 @@ func (s *state) writeMetadata() error {
 		}
 		s.persistedFeeState = s.feeState
 	}
+	if s.accruedFees != s.persistedAccruedFees {
+		if err := database.PutUInt64(s.singletonDB, AccruedFeesKey, s.accruedFees); err != nil {
+			return fmt.Errorf("failed to write accrued fees: %w", err)
+		}
+		s.persistedAccruedFees = s.accruedFees
+	}
 	if s.persistedCurrentSupply != s.currentSupply {
 		if err := database.PutUInt64(s.singletonDB, CurrentSupplyKey, s.currentSupply); err != nil {
 			return fmt.Errorf("failed to write current supply: %w", err)
@@ -26,6 +26,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -1244,6 +1245,7 @@ func TestStateAddRemoveValidator(t *testing.T) {
 				primaryValidatorSet,
 				currentHeight,
 				prevHeight+1,
+				constants.PrimaryNetworkID,
 			))
 			requireEqualPublicKeysValidatorSet(require, prevDiff.expectedPrimaryValidatorSet, primaryValidatorSet)
 
@@ -1585,3 +1587,153 @@ func TestGetFeeStateErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestGetModifiedValidators(t *testing.T) {
+	var (
+		subnetID = ids.GenerateTestID()
+		nodeID0  = ids.BuildTestNodeID([]byte{1})
+		nodeID1  = ids.BuildTestNodeID([]byte{2})
+		nodeID2  = ids.BuildTestNodeID([]byte{3})
+		nodeID3  = ids.BuildTestNodeID([]byte{4})
+		nodeID4  = ids.BuildTestNodeID([]byte{5})
+	)
+	tests := []struct {
+		name        string
+		subnetID    ids.ID
+		startHeight uint64
+		endHeight   uint64
+		startNodeID ids.NodeID
+		limit       int
+		expected    []ids.NodeID
+	}{
+		{
+			name:        "all heights",
+			subnetID:    subnetID,
+			startHeight: 1,
+			endHeight:   4,
+			limit:       10,
+			expected:    []ids.NodeID{nodeID0, nodeID1, nodeID2, nodeID3, nodeID4},
+		},
+		{
+			name:        "height range",
+			subnetID:    subnetID,
+			startHeight: 2,
+			endHeight:   3,
+			limit:       10,
+			expected:    []ids.NodeID{nodeID1, nodeID2, nodeID3},
+		},
+		{
+			name:        "limit",
+			subnetID:    subnetID,
+			startHeight: 1,
+			endHeight:   4,
+			limit:       2,
+			expected:    []ids.NodeID{nodeID0, nodeID1},
+		},
+		{
+			name:        "after start node ID",
+			subnetID:    subnetID,
+			startHeight: 1,
+			endHeight:   4,
+			startNodeID: nodeID1,
+			limit:       2,
+			expected:    []ids.NodeID{nodeID2, nodeID3},
+		},
+		{
+			name:        "primary network",
+			subnetID:    constants.PrimaryNetworkID,
+			startHeight: 1,
+			endHeight:   4,
+			limit:       10,
+			expected:    []ids.NodeID{nodeID2, nodeID4},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			s, _ := newUninitializedState(require)
+
+			weightDiff := marshalWeightDiff(&ValidatorWeightDiff{
+				Amount: 1,
+			})
+
+			// Subnet weight diffs
+			require.NoError(s.validatorWeightDiffsDB.Put(marshalDiffKey(subnetID, 1, nodeID0), weightDiff))
+			require.NoError(s.validatorWeightDiffsDB.Put(marshalDiffKey(subnetID, 2, nodeID3), weightDiff))
+			require.NoError(s.validatorWeightDiffsDB.Put(marshalDiffKey(subnetID, 3, nodeID1), weightDiff))
+			// Primary network public key diffs, which modify the public keys
+			// of legacy subnet validators
+			require.NoError(s.validatorPublicKeyDiffsDB.Put(marshalDiffKey(constants.PrimaryNetworkID, 2, nodeID2), nil))
+			require.NoError(s.validatorPublicKeyDiffsDB.Put(marshalDiffKey(constants.PrimaryNetworkID, 4, nodeID4), nil))
+
+			nodeIDs, err := s.GetModifiedValidators(
+				context.Background(),
+				test.subnetID,
+				test.startHeight,
+				test.endHeight,
+				test.startNodeID,
+				test.limit,
+			)
+			require.NoError(err)
+			require.Equal(test.expected, nodeIDs)
+		})
+	}
+}
+
+func TestGetValidatorWeightDiffs(t *testing.T) {
+	require := require.New(t)
+
+	s, _ := newUninitializedState(require)
+
+	var (
+		subnetID = ids.GenerateTestID()
+		nodeID0  = ids.GenerateTestNodeID()
+		nodeID1  = ids.GenerateTestNodeID()
+	)
+	require.NoError(s.validatorWeightDiffsDB.Put(
+		marshalDiffKey(subnetID, 1, nodeID0),
+		marshalWeightDiff(&ValidatorWeightDiff{
+			Amount: 5,
+		}),
+	))
+	require.NoError(s.validatorWeightDiffsDB.Put(
+		marshalDiffKey(subnetID, 2, nodeID0),
+		marshalWeightDiff(&ValidatorWeightDiff{
+			Decrease: true,
+			Amount:   2,
+		}),
+	))
+	require.NoError(s.validatorWeightDiffsDB.Put(
+		marshalDiffKey(subnetID, 2, nodeID1),
+		marshalWeightDiff(&ValidatorWeightDiff{
+			Amount: 1,
+		}),
+	))
+
+	weightDiffs, err := s.GetValidatorWeightDiffs(context.Background(), set.Of(nodeID0), 2, 1, subnetID)
+	require.NoError(err)
+	require.Equal(
+		map[ids.NodeID]*ValidatorWeightDiff{
+			nodeID0: {
+				Amount: 3,
+			},
+		},
+		weightDiffs,
+	)
+
+	weightDiffs, err = s.GetValidatorWeightDiffs(context.Background(), set.Of(nodeID0, nodeID1), 2, 2, subnetID)
+	require.NoError(err)
+	require.Equal(
+		map[ids.NodeID]*ValidatorWeightDiff{
+			nodeID0: {
+				Decrease: true,
+				Amount:   2,
+			},
+			nodeID1: {
+				Amount: 1,
+			},
+		},
+		weightDiffs,
+	)
+}
@@ -0,0 +1,311 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/block"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/message"
+)
+
+var (
+	ErrMutatedSubnetOnlyValidator     = errors.New("subnet-only validator contains mutated constant fields")
+	ErrConflictingSubnetOnlyValidator = errors.New("subnet-only validator contains conflicting subnetID + nodeID pair")
+)
+
+type SubnetOnlyValidators interface {
+	// GetActiveSubnetOnlyValidators returns the active subnet-only validators
+	// of all subnets, ordered by the accrued fees at which they will be
+	// deactivated.
+	GetActiveSubnetOnlyValidators() ([]SubnetOnlyValidator, error)
+
+	// GetSubnetOnlyValidator returns the validator with [validationID] if it
+	// exists. If the validator does not exist, [database.ErrNotFound] is
+	// returned. Removed validators are returned with a weight of 0.
+	GetSubnetOnlyValidator(validationID ids.ID) (SubnetOnlyValidator, error)
+
+	// GetSubnetOnlyValidators returns the validators of [subnetID] that
+	// haven't been removed, including the validators that are currently
+	// inactive.
+	GetSubnetOnlyValidators(subnetID ids.ID) ([]SubnetOnlyValidator, error)
+
+	// HasSubnetOnlyValidator returns true if [nodeID] is a subnet-only
+	// validator of [subnetID] that hasn't been removed.
+	HasSubnetOnlyValidator(subnetID ids.ID, nodeID ids.NodeID) (bool, error)
+
+	// WeightOfSubnetOnlyValidators returns the total weight of the
+	// subnet-only validators of [subnetID] that haven't been removed,
+	// including the validators that are currently inactive.
+	WeightOfSubnetOnlyValidators(subnetID ids.ID) (uint64, error)
+
+	// PutSubnetOnlyValidator inserts [sov] as a validator. If the weight of the
+	// validator is 0, the validator is removed.
+	//
+	// If inserting this validator attempts to modify any of the constant
+	// fields of the subnet-only validator struct, an error will be returned.
+	//
+	// If inserting this validator would cause the mapping of subnetID+nodeID
+	// to validationID to be non-unique, an error will be returned.
+	PutSubnetOnlyValidator(sov SubnetOnlyValidator) error
+}
+
+// SubnetOnlyValidator is a validator of a subnet that was converted to be
+// managed by a validator manager. Unlike the other validators, it doesn't need
+// to validate the primary network. Instead, it pays a continuous fee out of its
+// balance.
+//
+// Removed validators are kept with a weight of 0 so that the message that
+// registered them can not be replayed.
+type SubnetOnlyValidator struct {
+	// ValidationID is not serialized because it is used as the key in the
+	// database, so it doesn't need to be stored in the value.
+	ValidationID ids.ID `json:"validationID"`
+
+	SubnetID ids.ID     `serialize:"true" json:"subnetID"`
+	NodeID   ids.NodeID `serialize:"true" json:"nodeID"`
+
+	// PublicKey is the uncompressed BLS public key of the validator. It is
+	// guaranteed to be populated.
+	PublicKey []byte `serialize:"true" json:"publicKey"`
+
+	// RemainingBalanceOwner is the owner that will be issued the balance of
+	// the validator when it is removed.
+	RemainingBalanceOwner message.PChainOwner `serialize:"true" json:"remainingBalanceOwner"`
+
+	// StartTime is the unix timestamp, in seconds, when this validator was
+	// added to the set.
+	StartTime uint64 `serialize:"true" json:"startTime"`
+
+	// Weight of this validator. It can be updated when the MinNonce is
+	// increased. If the weight is being set to 0, the validator is removed.
+	Weight uint64 `serialize:"true" json:"weight"`
+
+	// MinNonce is the smallest nonce that can be used to modify this
+	// validator's weight. It is updated when the validator weight is modified.
+	MinNonce uint64 `serialize:"true" json:"minNonce"`
+
+	// EndAccumulatedFee is the amount of globally accrued fees that can accrue
+	// before this validator must be deactivated. If this value is 0, the
+	// validator is inactive.
+	EndAccumulatedFee uint64 `serialize:"true" json:"endAccumulatedFee"`
+}
+
+// Compare orders validators by the accrued fees at which they will be
+// deactivated.
+func (v SubnetOnlyValidator) Compare(o SubnetOnlyValidator) int {
+	switch {
+	case v.EndAccumulatedFee < o.EndAccumulatedFee:
+		return -1
+	case o.EndAccumulatedFee < v.EndAccumulatedFee:
+		return 1
+	default:
+		return v.ValidationID.Compare(o.ValidationID)
+	}
+}
+
+// IsActive returns true if the validator is currently paying fees and is
+// therefore included in the validator set.
+func (v SubnetOnlyValidator) IsActive() bool {
+	return v.Weight != 0 && v.EndAccumulatedFee != 0
+}
+
+// IsRemoved returns true if the validator was removed from the subnet.
+func (v SubnetOnlyValidator) IsRemoved() bool {
+	return v.Weight == 0
+}
+
+// activeWeight is the weight this validator contributes to the validator set.
+func (v SubnetOnlyValidator) activeWeight() uint64 {
+	if v.IsActive() {
+		return v.Weight
+	}
+	return 0
+}
+
+// constantsAreUnmodified returns true if the constant fields of [v] and [o]
+// are equal.
+func (v SubnetOnlyValidator) constantsAreUnmodified(o SubnetOnlyValidator) bool {
+	return v.ValidationID == o.ValidationID &&
+		v.SubnetID == o.SubnetID &&
+		v.NodeID == o.NodeID &&
+		bytes.Equal(v.PublicKey, o.PublicKey) &&
+		v.RemainingBalanceOwner.Threshold == o.RemainingBalanceOwner.Threshold &&
+		slices.Equal(v.RemainingBalanceOwner.Addresses, o.RemainingBalanceOwner.Addresses) &&
+		v.StartTime == o.StartTime
+}
+
+func getSubnetOnlyValidator(db database.KeyValueReader, validationID ids.ID) (SubnetOnlyValidator, error) {
+	bytes, err := db.Get(validationID[:])
+	if err != nil {
+		return SubnetOnlyValidator{}, err
+	}
+
+	vdr := SubnetOnlyValidator{
+		ValidationID: validationID,
+	}
+	if _, err := block.GenesisCodec.Unmarshal(bytes, &vdr); err != nil {
+		return SubnetOnlyValidator{}, fmt.Errorf("failed to unmarshal SubnetOnlyValidator: %w", err)
+	}
+	return vdr, nil
+}
+
+func putSubnetOnlyValidator(db database.KeyValueWriter, vdr SubnetOnlyValidator) error {
+	bytes, err := block.GenesisCodec.Marshal(block.CodecVersion, vdr)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SubnetOnlyValidator: %w", err)
+	}
+	return db.Put(vdr.ValidationID[:], bytes)
+}
+
+type subnetIDNodeID struct {
+	subnetID ids.ID
+	nodeID   ids.NodeID
+}
+
+// subnetOnlyValidatorsDiff tracks the subnet-only validators modified on top
+// of a parent.
+type subnetOnlyValidatorsDiff struct {
+	// validationID -> modified validator
+	modified map[ids.ID]SubnetOnlyValidator
+	// subnetID + nodeID -> validationID of the validators that are added or
+	// removed in this diff
+	modifiedSubnetIDNodeIDs map[subnetIDNodeID]ids.ID
+	// subnetID -> change of the total weight of the subnet's validators
+	weightDiffs map[ids.ID]*ValidatorWeightDiff
+}
+
+func newSubnetOnlyValidatorsDiff() *subnetOnlyValidatorsDiff {
+	return &subnetOnlyValidatorsDiff{
+		modified:                make(map[ids.ID]SubnetOnlyValidator),
+		modifiedSubnetIDNodeIDs: make(map[subnetIDNodeID]ids.ID),
+		weightDiffs:             make(map[ids.ID]*ValidatorWeightDiff),
+	}
+}
+
+// getActive returns the active validators after applying this diff to the
+// active validators of the parent.
+func (d *subnetOnlyValidatorsDiff) getActive(parentActive []SubnetOnlyValidator) []SubnetOnlyValidator {
+	active := make([]SubnetOnlyValidator, 0, len(parentActive)+len(d.modified))
+	for _, sov := range parentActive {
+		if _, ok := d.modified[sov.ValidationID]; !ok {
+			active = append(active, sov)
+		}
+	}
+	for _, sov := range d.modified {
+		if sov.IsActive() {
+			active = append(active, sov)
+		}
+	}
+	slices.SortFunc(active, SubnetOnlyValidator.Compare)
+	return active
+}
+
+// getSubnet returns the validators of [subnetID] that haven't been removed
+// after applying this diff to the [parent] validators of the subnet.
+func (d *subnetOnlyValidatorsDiff) getSubnet(subnetID ids.ID, parent []SubnetOnlyValidator) []SubnetOnlyValidator {
+	sovs := make([]SubnetOnlyValidator, 0, len(parent))
+	for _, sov := range parent {
+		if _, ok := d.modified[sov.ValidationID]; !ok {
+			sovs = append(sovs, sov)
+		}
+	}
+	for _, sov := range d.modified {
+		if sov.SubnetID == subnetID && !sov.IsRemoved() {
+			sovs = append(sovs, sov)
+		}
+	}
+	slices.SortFunc(sovs, SubnetOnlyValidator.Compare)
+	return sovs
+}
+
+// hasSubnetOnlyValidator returns whether the subnetID + nodeID pair is
+// registered and whether the answer was determined by this diff.
+func (d *subnetOnlyValidatorsDiff) hasSubnetOnlyValidator(subnetID ids.ID, nodeID ids.NodeID) (bool, bool) {
+	validationID, modified := d.modifiedSubnetIDNodeIDs[subnetIDNodeID{
+		subnetID: subnetID,
+		nodeID:   nodeID,
+	}]
+	if !modified {
+		return false, false
+	}
+	return !d.modified[validationID].IsRemoved(), true
+}
+
+// weightOfSubnetOnlyValidators returns the total weight of the validators of
+// [subnetID] after applying this diff to the [parentWeight].
+func (d *subnetOnlyValidatorsDiff) weightOfSubnetOnlyValidators(subnetID ids.ID, parentWeight uint64) (uint64, error) {
+	weightDiff, ok := d.weightDiffs[subnetID]
+	switch {
+	case !ok:
+		return parentWeight, nil
+	case weightDiff.Decrease:
+		return math.Sub(parentWeight, weightDiff.Amount)
+	default:
+		return math.Add(parentWeight, weightDiff.Amount)
+	}
+}
+
+// putSubnetOnlyValidator verifies that [sov] can be written on top of
+// [parent] and records the modification.
+func (d *subnetOnlyValidatorsDiff) putSubnetOnlyValidator(parent SubnetOnlyValidators, sov SubnetOnlyValidator) error {
+	prevSOV, ok := d.modified[sov.ValidationID]
+	if !ok {
+		var err error
+		prevSOV, err = parent.GetSubnetOnlyValidator(sov.ValidationID)
+		switch {
+		case err == nil:
+			ok = true
+		case errors.Is(err, database.ErrNotFound):
+		default:
+			return err
+		}
+	}
+
+	subnetIDNodeID := subnetIDNodeID{
+		subnetID: sov.SubnetID,
+		nodeID:   sov.NodeID,
+	}
+	switch {
+	case ok && !sov.constantsAreUnmodified(prevSOV):
+		return ErrMutatedSubnetOnlyValidator
+	case ok && prevSOV.IsRemoved():
+		// Removed validators can never be modified again.
+		return fmt.Errorf("%w: %s was removed", ErrMutatedSubnetOnlyValidator, sov.ValidationID)
+	case !ok:
+		has, modified := d.hasSubnetOnlyValidator(sov.SubnetID, sov.NodeID)
+		if !modified {
+			var err error
+			has, err = parent.HasSubnetOnlyValidator(sov.SubnetID, sov.NodeID)
+			if err != nil {
+				return err
+			}
+		}
+		if has {
+			return ErrConflictingSubnetOnlyValidator
+		}
+	}
+
+	var weightDiff ValidatorWeightDiff
+	if prevWeightDiff, ok := d.weightDiffs[sov.SubnetID]; ok {
+		weightDiff = *prevWeightDiff
+	}
+	if err := weightDiff.Add(true, prevSOV.Weight); err != nil {
+		return err
+	}
+	if err := weightDiff.Add(false, sov.Weight); err != nil {
+		return err
+	}
+
+	d.modified[sov.ValidationID] = sov
+	d.modifiedSubnetIDNodeIDs[subnetIDNodeID] = sov.ValidationID
+	d.weightDiffs[sov.SubnetID] = &weightDiff
+	return nil
+}
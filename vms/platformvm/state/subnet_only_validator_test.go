@@ -0,0 +1,275 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/message"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+func newTestSubnetOnlyValidator(require *require.Assertions, subnetID ids.ID) SubnetOnlyValidator {
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	pk := bls.PublicFromSecretKey(sk)
+
+	return SubnetOnlyValidator{
+		ValidationID: ids.GenerateTestID(),
+		SubnetID:     subnetID,
+		NodeID:       ids.GenerateTestNodeID(),
+		PublicKey:    bls.PublicKeyToUncompressedBytes(pk),
+		RemainingBalanceOwner: message.PChainOwner{
+			Threshold: 1,
+			Addresses: []ids.ShortID{ids.GenerateTestShortID()},
+		},
+		StartTime:         1,
+		Weight:            2,
+		MinNonce:          3,
+		EndAccumulatedFee: 4,
+	}
+}
+
+func TestSubnetOnlyValidatorCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		v        SubnetOnlyValidator
+		o        SubnetOnlyValidator
+		expected int
+	}{
+		{
+			name: "v.EndAccumulatedFee < o.EndAccumulatedFee",
+			v: SubnetOnlyValidator{
+				ValidationID:      ids.ID{1},
+				EndAccumulatedFee: 1,
+			},
+			o: SubnetOnlyValidator{
+				ValidationID:      ids.ID{0},
+				EndAccumulatedFee: 2,
+			},
+			expected: -1,
+		},
+		{
+			name: "v.EndAccumulatedFee = o.EndAccumulatedFee, v.ValidationID < o.ValidationID",
+			v: SubnetOnlyValidator{
+				ValidationID:      ids.ID{0},
+				EndAccumulatedFee: 1,
+			},
+			o: SubnetOnlyValidator{
+				ValidationID:      ids.ID{1},
+				EndAccumulatedFee: 1,
+			},
+			expected: -1,
+		},
+		{
+			name: "v = o",
+			v: SubnetOnlyValidator{
+				ValidationID:      ids.ID{0},
+				EndAccumulatedFee: 1,
+			},
+			o: SubnetOnlyValidator{
+				ValidationID:      ids.ID{0},
+				EndAccumulatedFee: 1,
+			},
+			expected: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			require.Equal(test.expected, test.v.Compare(test.o))
+			require.Equal(-test.expected, test.o.Compare(test.v))
+		})
+	}
+}
+
+func TestSubnetOnlyValidatorsDiffPutErrors(t *testing.T) {
+	require := require.New(t)
+
+	state := newInitializedState(require)
+
+	sov := newTestSubnetOnlyValidator(require, ids.GenerateTestID())
+	require.NoError(state.PutSubnetOnlyValidator(sov))
+
+	mutatedSOV := sov
+	mutatedSOV.StartTime++
+	err := state.PutSubnetOnlyValidator(mutatedSOV)
+	require.ErrorIs(err, ErrMutatedSubnetOnlyValidator)
+
+	conflictingSOV := sov
+	conflictingSOV.ValidationID = ids.GenerateTestID()
+	err = state.PutSubnetOnlyValidator(conflictingSOV)
+	require.ErrorIs(err, ErrConflictingSubnetOnlyValidator)
+
+	removedSOV := sov
+	removedSOV.Weight = 0
+	require.NoError(state.PutSubnetOnlyValidator(removedSOV))
+
+	// After removing the validator, the subnetID + nodeID pair can be used
+	// again.
+	require.NoError(state.PutSubnetOnlyValidator(conflictingSOV))
+
+	// Removed validators can not be modified.
+	err = state.PutSubnetOnlyValidator(sov)
+	require.ErrorIs(err, ErrMutatedSubnetOnlyValidator)
+}
+
+func TestSubnetOnlyValidatorsWeight(t *testing.T) {
+	require := require.New(t)
+
+	s, db := newUninitializedState(require)
+	initializeState(require, s)
+
+	var (
+		subnetID    = ids.GenerateTestID()
+		sov         = newTestSubnetOnlyValidator(require, subnetID)
+		inactiveSOV = newTestSubnetOnlyValidator(require, subnetID)
+		otherSOV    = newTestSubnetOnlyValidator(require, ids.GenerateTestID())
+	)
+	inactiveSOV.EndAccumulatedFee = 0
+
+	d, err := NewDiffOn(s)
+	require.NoError(err)
+	for _, sov := range []SubnetOnlyValidator{sov, inactiveSOV, otherSOV} {
+		require.NoError(d.PutSubnetOnlyValidator(sov))
+	}
+
+	sovs, err := d.GetSubnetOnlyValidators(subnetID)
+	require.NoError(err)
+	require.ElementsMatch([]SubnetOnlyValidator{sov, inactiveSOV}, sovs)
+
+	// Inactive validators count towards the weight of the subnet.
+	weight, err := d.WeightOfSubnetOnlyValidators(subnetID)
+	require.NoError(err)
+	require.Equal(sov.Weight+inactiveSOV.Weight, weight)
+
+	weight, err = s.WeightOfSubnetOnlyValidators(subnetID)
+	require.NoError(err)
+	require.Zero(weight)
+
+	require.NoError(d.Apply(s))
+	require.NoError(s.Commit())
+
+	weight, err = s.WeightOfSubnetOnlyValidators(subnetID)
+	require.NoError(err)
+	require.Equal(sov.Weight+inactiveSOV.Weight, weight)
+
+	d, err = NewDiffOn(s)
+	require.NoError(err)
+
+	updatedSOV := sov
+	updatedSOV.Weight++
+	require.NoError(d.PutSubnetOnlyValidator(updatedSOV))
+	removedSOV := inactiveSOV
+	removedSOV.Weight = 0
+	require.NoError(d.PutSubnetOnlyValidator(removedSOV))
+
+	weight, err = d.WeightOfSubnetOnlyValidators(subnetID)
+	require.NoError(err)
+	require.Equal(updatedSOV.Weight, weight)
+
+	sovs, err = d.GetSubnetOnlyValidators(subnetID)
+	require.NoError(err)
+	require.Equal([]SubnetOnlyValidator{updatedSOV}, sovs)
+
+	require.NoError(d.Apply(s))
+	require.NoError(s.Commit())
+
+	// Verify that the weight is correctly loaded from disk.
+	s = newStateFromDB(require, db)
+	require.NoError(s.load())
+
+	weight, err = s.WeightOfSubnetOnlyValidators(subnetID)
+	require.NoError(err)
+	require.Equal(updatedSOV.Weight, weight)
+
+	weight, err = s.WeightOfSubnetOnlyValidators(otherSOV.SubnetID)
+	require.NoError(err)
+	require.Equal(otherSOV.Weight, weight)
+
+	// Overflowing the weight of a subnet is reported.
+	d, err = NewDiffOn(s)
+	require.NoError(err)
+
+	overflowSOV := newTestSubnetOnlyValidator(require, subnetID)
+	overflowSOV.Weight = math.MaxUint64
+	require.NoError(d.PutSubnetOnlyValidator(overflowSOV))
+
+	_, err = d.WeightOfSubnetOnlyValidators(subnetID)
+	require.ErrorIs(err, safemath.ErrOverflow)
+}
+
+func TestStateSubnetOnlyValidators(t *testing.T) {
+	require := require.New(t)
+
+	s, db := newUninitializedState(require)
+	initializeState(require, s)
+
+	var (
+		subnetID  = ids.GenerateTestID()
+		activeSOV = newTestSubnetOnlyValidator(require, subnetID)
+		removeSOV = newTestSubnetOnlyValidator(require, subnetID)
+	)
+	inactiveSOV := newTestSubnetOnlyValidator(require, subnetID)
+	inactiveSOV.EndAccumulatedFee = 0
+
+	d, err := NewDiffOn(s)
+	require.NoError(err)
+	for _, sov := range []SubnetOnlyValidator{activeSOV, removeSOV, inactiveSOV} {
+		require.NoError(d.PutSubnetOnlyValidator(sov))
+	}
+	require.NoError(d.Apply(s))
+	require.NoError(s.Commit())
+
+	weight, err := s.validators.TotalWeight(subnetID)
+	require.NoError(err)
+	require.Equal(activeSOV.Weight+removeSOV.Weight, weight)
+
+	removedSOV := removeSOV
+	removedSOV.Weight = 0
+	removedSOV.EndAccumulatedFee = 0
+	require.NoError(s.PutSubnetOnlyValidator(removedSOV))
+	require.NoError(s.Commit())
+
+	weight, err = s.validators.TotalWeight(subnetID)
+	require.NoError(err)
+	require.Equal(activeSOV.Weight, weight)
+
+	// Verify that the state is correctly loaded from disk.
+	s = newStateFromDB(require, db)
+	require.NoError(s.load())
+
+	active, err := s.GetActiveSubnetOnlyValidators()
+	require.NoError(err)
+	require.Equal([]SubnetOnlyValidator{activeSOV}, active)
+
+	sovs, err := s.GetSubnetOnlyValidators(subnetID)
+	require.NoError(err)
+	require.ElementsMatch([]SubnetOnlyValidator{activeSOV, inactiveSOV}, sovs)
+
+	for _, expected := range []SubnetOnlyValidator{activeSOV, removedSOV, inactiveSOV} {
+		sov, err := s.GetSubnetOnlyValidator(expected.ValidationID)
+		require.NoError(err)
+		require.Equal(expected, sov)
+
+		has, err := s.HasSubnetOnlyValidator(expected.SubnetID, expected.NodeID)
+		require.NoError(err)
+		require.Equal(!expected.IsRemoved(), has)
+	}
+
+	_, err = s.GetSubnetOnlyValidator(ids.GenerateTestID())
+	require.ErrorIs(err, database.ErrNotFound)
+
+	weight, err = s.validators.TotalWeight(subnetID)
+	require.NoError(err)
+	require.Equal(activeSOV.Weight, weight)
+}
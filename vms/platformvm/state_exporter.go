@@ -0,0 +1,171 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/platformvm/block"
+	"github.com/ava-labs/avalanchego/vms/platformvm/export"
+)
+
+// exportsDir is the directory, relative to the chain data directory, that
+// exports are written to.
+const exportsDir = "exports"
+
+var (
+	errChainNotInitialized = errors.New("P-chain hasn't been initialized")
+	errHeightNotExportable = errors.New("height is above the last accepted height")
+)
+
+// StateExporter exports the state of the P-chain created by the [Factory] it
+// was provided to.
+type StateExporter struct {
+	lock sync.RWMutex
+	vm   *VM
+}
+
+func (e *StateExporter) setVM(vm *VM) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.vm = vm
+}
+
+// ExportState writes the state of the P-chain at [height], or at its last
+// accepted block if [height] is nil, to a new directory in the chain data
+// directory.
+//
+// Only the validator sets are available at heights prior to the last accepted
+// height, so only the validators are exported at such heights.
+func (e *StateExporter) ExportState(format export.Format, height *uint64) (string, *export.Metadata, error) {
+	e.lock.RLock()
+	vm := e.vm
+	e.lock.RUnlock()
+
+	if vm == nil {
+		return "", nil, errChainNotInitialized
+	}
+	return vm.exportState(format, height)
+}
+
+func (vm *VM) exportState(format export.Format, height *uint64) (string, *export.Metadata, error) {
+	dir, snapshot, err := vm.snapshotState(format, height)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The snapshot is written without holding the context lock, so blocks
+	// can be accepted while the export is being written.
+	metadata, err := snapshot.Write()
+	return dir, metadata, err
+}
+
+// snapshotState returns a snapshot of the state at [height], or at the last
+// accepted block if [height] is nil, and the directory it should be written
+// to.
+func (vm *VM) snapshotState(format export.Format, height *uint64) (string, *export.Snapshot, error) {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	lastAcceptedID := vm.state.GetLastAccepted()
+	lastAccepted, err := vm.state.GetStatelessBlock(lastAcceptedID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get last accepted block: %w", err)
+	}
+
+	lastAcceptedHeight := lastAccepted.Height()
+	switch {
+	case height == nil || *height == lastAcceptedHeight:
+	case *height > lastAcceptedHeight:
+		return "", nil, fmt.Errorf("%w: requested height %d but last accepted height is %d",
+			errHeightNotExportable,
+			*height,
+			lastAcceptedHeight,
+		)
+	default:
+		return vm.snapshotValidatorSets(format, *height)
+	}
+
+	dir := filepath.Join(vm.ctx.ChainDataDir, exportsDir, fmt.Sprintf("%d-%s", lastAcceptedHeight, format))
+	vm.ctx.Log.Info("exporting state",
+		zap.Uint64("height", lastAcceptedHeight),
+		zap.Stringer("blkID", lastAcceptedID),
+		zap.String("dir", dir),
+	)
+
+	snapshot, err := export.NewSnapshot(vm.state, export.Config{
+		NetworkID: vm.ctx.NetworkID,
+		Height:    lastAcceptedHeight,
+		BlockID:   lastAcceptedID,
+		Format:    format,
+		Dir:       dir,
+	})
+	return dir, snapshot, err
+}
+
+// snapshotValidatorSets returns a snapshot of the validator sets at [height],
+// which must be below the last accepted height, and the directory it should be
+// written to.
+//
+// Invariant: The context lock must be held.
+func (vm *VM) snapshotValidatorSets(format export.Format, height uint64) (string, *export.Snapshot, error) {
+	blkID, err := vm.state.GetBlockIDAtHeight(height)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get block ID at height %d: %w", height, err)
+	}
+	blk, err := vm.state.GetStatelessBlock(blkID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get block %s: %w", blkID, err)
+	}
+	// Blocks prior to Banff don't have a timestamp.
+	var timestamp uint64
+	if banffBlk, ok := blk.(block.BanffBlock); ok {
+		timestamp = uint64(banffBlk.Timestamp().Unix())
+	}
+
+	subnetIDs, err := vm.state.GetSubnetIDs()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get subnet IDs: %w", err)
+	}
+	subnetIDs = append([]ids.ID{constants.PrimaryNetworkID}, subnetIDs...)
+
+	validatorSets := make(map[ids.ID]map[ids.NodeID]*validators.GetValidatorOutput, len(subnetIDs))
+	for _, subnetID := range subnetIDs {
+		validatorSet, err := vm.GetValidatorSet(context.TODO(), height, subnetID)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get validator set of subnet %s at height %d: %w", subnetID, height, err)
+		}
+		validatorSets[subnetID] = validatorSet
+	}
+
+	dir := filepath.Join(vm.ctx.ChainDataDir, exportsDir, fmt.Sprintf("%d-%s", height, format))
+	vm.ctx.Log.Info("exporting validator sets",
+		zap.Uint64("height", height),
+		zap.Stringer("blkID", blkID),
+		zap.String("dir", dir),
+	)
+
+	snapshot, err := export.NewValidatorSetsSnapshot(
+		export.Config{
+			NetworkID: vm.ctx.NetworkID,
+			Height:    height,
+			BlockID:   blkID,
+			Format:    format,
+			Dir:       dir,
+		},
+		timestamp,
+		validatorSets,
+	)
+	return dir, snapshot, err
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/export"
+)
+
+func TestStateExporter(t *testing.T) {
+	require := require.New(t)
+
+	vm, _, _, _ := defaultVM(t, latestFork)
+	vm.ctx.ChainDataDir = t.TempDir()
+
+	exporter := &StateExporter{}
+	_, _, err := exporter.ExportState(export.JSON, nil)
+	require.ErrorIs(err, errChainNotInitialized)
+
+	exporter.setVM(vm)
+	dir, metadata, err := exporter.ExportState(export.JSON, nil)
+	require.NoError(err)
+	require.Equal(filepath.Join(vm.ctx.ChainDataDir, exportsDir, "1-json"), dir)
+	require.Equal(vm.ctx.NetworkID, metadata.NetworkID)
+	require.Equal(uint64(1), metadata.Height)
+	require.Equal(vm.state.GetLastAccepted(), metadata.BlockID)
+	require.Equal(uint64(len(genesisNodeIDs)), metadata.NumStakers)
+	require.Equal(uint64(2), metadata.NumSubnets) // primary network and testSubnet1
+	require.Zero(metadata.NumChains)
+	require.NotZero(metadata.NumUTXOs)
+
+	height := metadata.Height
+	_, _, err = exporter.ExportState(export.JSON, &height)
+	require.NoError(err)
+
+	height++
+	_, _, err = exporter.ExportState(export.JSON, &height)
+	require.ErrorIs(err, errHeightNotExportable)
+
+	// Only the validator sets are exported at prior heights.
+	height = 0
+	genesisDir, genesisMetadata, err := exporter.ExportState(export.JSON, &height)
+	require.NoError(err)
+	require.Equal(filepath.Join(vm.ctx.ChainDataDir, exportsDir, "0-json"), genesisDir)
+	require.True(genesisMetadata.ValidatorsOnly)
+	require.Zero(genesisMetadata.Height)
+	require.Equal(uint64(len(genesisNodeIDs)), genesisMetadata.NumStakers)
+	require.Zero(genesisMetadata.NumSubnets)
+	require.Zero(genesisMetadata.NumUTXOs)
+	require.NoFileExists(filepath.Join(genesisDir, export.UTXOsFile+"."+string(export.JSON)))
+
+	_, err = export.GenesisConfig(genesisDir, export.GenesisArgs{})
+	require.ErrorIs(err, export.ErrGenesisRequiresUTXOs)
+
+	readMetadata, err := export.ReadMetadata(dir)
+	require.NoError(err)
+	require.Equal(metadata, readMetadata)
+
+	nodeID := ids.GenerateTestNodeID()
+	config, err := export.GenesisConfig(dir, export.GenesisArgs{
+		NodeIDs: []ids.NodeID{nodeID},
+	})
+	require.NoError(err)
+	require.Len(config.InitialStakers, 1)
+	require.Equal(nodeID, config.InitialStakers[0].NodeID)
+	require.Len(config.InitialStakedFunds, len(genesisNodeIDs))
+
+	// The weight of the kept validator is scaled to preserve the total stake,
+	// and the stake of every validator is allocated to its reward address.
+	require.Equal(uint64(len(genesisNodeIDs))*defaultWeight, config.InitialStakers[0].Weight)
+	supply, err := config.InitialSupply()
+	require.NoError(err)
+	require.GreaterOrEqual(supply, uint64(len(genesisNodeIDs))*defaultWeight)
+
+	_, _, err = exporter.ExportState(export.CSV, nil)
+	require.NoError(err)
+	_, err = export.GenesisConfig(filepath.Join(vm.ctx.ChainDataDir, exportsDir, "1-csv"), export.GenesisArgs{})
+	require.ErrorIs(err, export.ErrGenesisRequiresJSON)
+
+	// The state can be modified once the snapshot is taken.
+	_, snapshot, err := vm.snapshotState(export.JSON, nil)
+	require.NoError(err)
+
+	vm.ctx.Lock.Lock()
+	utxoIt := vm.state.NewUTXOIterator()
+	require.True(utxoIt.Next())
+	vm.state.DeleteUTXO(utxoIt.Value().InputID())
+	utxoIt.Release()
+	require.NoError(vm.state.Commit())
+	vm.ctx.Lock.Unlock()
+
+	snapshotMetadata, err := snapshot.Write()
+	require.NoError(err)
+	require.Equal(metadata.NumUTXOs, snapshotMetadata.NumUTXOs)
+}
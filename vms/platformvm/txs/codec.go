@@ -42,7 +42,10 @@ func init() {
 
 		c.SkipRegistrations(4)
 
-		errs.Add(RegisterDurangoUnsignedTxsTypes(c))
+		errs.Add(
+			RegisterDurangoUnsignedTxsTypes(c),
+			RegisterEtnaUnsignedTxsTypes(c),
+		)
 	}
 
 	Codec = codec.NewDefaultManager()
@@ -109,3 +112,12 @@ func RegisterDurangoUnsignedTxsTypes(targetCodec linearcodec.Codec) error {
 		targetCodec.RegisterType(&BaseTx{}),
 	)
 }
+
+func RegisterEtnaUnsignedTxsTypes(targetCodec linearcodec.Codec) error {
+	return errors.Join(
+		targetCodec.RegisterType(&ConvertSubnetTx{}),
+		targetCodec.RegisterType(&RegisterSubnetValidatorTx{}),
+		targetCodec.RegisterType(&SetSubnetValidatorWeightTx{}),
+		targetCodec.RegisterType(&IncreaseBalanceTx{}),
+	)
+}
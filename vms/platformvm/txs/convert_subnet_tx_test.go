@@ -0,0 +1,178 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/message"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestConvertSubnetTxSyntacticVerify(t *testing.T) {
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+
+	var (
+		networkID = uint32(1337)
+		chainID   = ids.GenerateTestID()
+		ctx       = &snow.Context{
+			ChainID:   chainID,
+			NetworkID: networkID,
+		}
+		validBaseTx = BaseTx{
+			BaseTx: avax.BaseTx{
+				NetworkID:    networkID,
+				BlockchainID: chainID,
+			},
+		}
+		validSubnetID  = ids.GenerateTestID()
+		validValidator = &ConvertSubnetValidator{
+			NodeID:  ids.GenerateTestNodeID(),
+			Weight:  1,
+			Balance: 1,
+			Signer:  *signer.NewProofOfPossession(sk),
+			RemainingBalanceOwner: message.PChainOwner{
+				Threshold: 1,
+				Addresses: []ids.ShortID{ids.GenerateTestShortID()},
+			},
+		}
+		validSubnetAuth = &secp256k1fx.Input{}
+	)
+
+	tests := []struct {
+		name        string
+		tx          *ConvertSubnetTx
+		expectedErr error
+	}{
+		{
+			name:        "nil tx",
+			tx:          nil,
+			expectedErr: ErrNilTx,
+		},
+		{
+			name: "already verified",
+			tx: &ConvertSubnetTx{
+				BaseTx: BaseTx{
+					SyntacticallyVerified: true,
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "invalid subnetID",
+			tx: &ConvertSubnetTx{
+				BaseTx:     validBaseTx,
+				Subnet:     constants.PrimaryNetworkID,
+				Validators: []*ConvertSubnetValidator{validValidator},
+				SubnetAuth: validSubnetAuth,
+			},
+			expectedErr: ErrConvertPermissionlessSubnet,
+		},
+		{
+			name: "address too long",
+			tx: &ConvertSubnetTx{
+				BaseTx:     validBaseTx,
+				Subnet:     validSubnetID,
+				Address:    make([]byte, MaxSubnetAddressLength+1),
+				Validators: []*ConvertSubnetValidator{validValidator},
+				SubnetAuth: validSubnetAuth,
+			},
+			expectedErr: ErrAddressTooLong,
+		},
+		{
+			name: "no validators",
+			tx: &ConvertSubnetTx{
+				BaseTx:     validBaseTx,
+				Subnet:     validSubnetID,
+				SubnetAuth: validSubnetAuth,
+			},
+			expectedErr: ErrConvertMustIncludeValidators,
+		},
+		{
+			name: "duplicate validators",
+			tx: &ConvertSubnetTx{
+				BaseTx:     validBaseTx,
+				Subnet:     validSubnetID,
+				Validators: []*ConvertSubnetValidator{validValidator, validValidator},
+				SubnetAuth: validSubnetAuth,
+			},
+			expectedErr: ErrConvertValidatorsNotSortedAndUnique,
+		},
+		{
+			name: "invalid BaseTx",
+			tx: &ConvertSubnetTx{
+				Subnet:     validSubnetID,
+				Validators: []*ConvertSubnetValidator{validValidator},
+				SubnetAuth: validSubnetAuth,
+			},
+			expectedErr: avax.ErrWrongNetworkID,
+		},
+		{
+			name: "zero weight",
+			tx: &ConvertSubnetTx{
+				BaseTx: validBaseTx,
+				Subnet: validSubnetID,
+				Validators: []*ConvertSubnetValidator{
+					{
+						NodeID:                validValidator.NodeID,
+						Signer:                validValidator.Signer,
+						RemainingBalanceOwner: validValidator.RemainingBalanceOwner,
+					},
+				},
+				SubnetAuth: validSubnetAuth,
+			},
+			expectedErr: ErrZeroWeight,
+		},
+		{
+			name: "invalid remaining balance owner",
+			tx: &ConvertSubnetTx{
+				BaseTx: validBaseTx,
+				Subnet: validSubnetID,
+				Validators: []*ConvertSubnetValidator{
+					{
+						NodeID: validValidator.NodeID,
+						Weight: 1,
+						Signer: validValidator.Signer,
+						RemainingBalanceOwner: message.PChainOwner{
+							Threshold: 1,
+						},
+					},
+				},
+				SubnetAuth: validSubnetAuth,
+			},
+			expectedErr: message.ErrInvalidOwner,
+		},
+		{
+			name: "passes verification",
+			tx: &ConvertSubnetTx{
+				BaseTx:     validBaseTx,
+				Subnet:     validSubnetID,
+				Validators: []*ConvertSubnetValidator{validValidator},
+				SubnetAuth: validSubnetAuth,
+			},
+			expectedErr: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			err := test.tx.SyntacticVerify(ctx)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				return
+			}
+			require.True(test.tx.SyntacticallyVerified)
+		})
+	}
+}
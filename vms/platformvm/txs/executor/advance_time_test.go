@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/snowtest"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
@@ -979,3 +980,59 @@ func addPendingValidator(
 	}
 	return addPendingValidatorTx, nil
 }
+
+// Ensure that advancing the time charges the subnet-only validators and
+// deactivates the validators whose balance was exhausted.
+func TestAdvanceTimeToSubnetOnlyValidatorFees(t *testing.T) {
+	require := require.New(t)
+	env := newEnvironment(t, etna)
+	env.ctx.Lock.Lock()
+	defer env.ctx.Lock.Unlock()
+
+	env.config.ValidatorFeeRate = 2
+
+	subnetID := testSubnet1.ID()
+	newSOV := func(endAccumulatedFee uint64) state.SubnetOnlyValidator {
+		sk, err := bls.NewSecretKey()
+		require.NoError(err)
+
+		return state.SubnetOnlyValidator{
+			ValidationID:      ids.GenerateTestID(),
+			SubnetID:          subnetID,
+			NodeID:            ids.GenerateTestNodeID(),
+			PublicKey:         bls.PublicKeyToUncompressedBytes(bls.PublicFromSecretKey(sk)),
+			Weight:            1,
+			EndAccumulatedFee: endAccumulatedFee,
+		}
+	}
+	var (
+		expiringSOV  = newSOV(10)
+		remainingSOV = newSOV(100)
+	)
+	require.NoError(env.state.PutSubnetOnlyValidator(expiringSOV))
+	require.NoError(env.state.PutSubnetOnlyValidator(remainingSOV))
+
+	onAcceptState, err := state.NewDiff(lastAcceptedID, env)
+	require.NoError(err)
+
+	newChainTime := onAcceptState.GetTimestamp().Add(5 * time.Second)
+	changed, err := AdvanceTimeTo(&env.backend, onAcceptState, newChainTime)
+	require.NoError(err)
+	require.True(changed)
+	require.Equal(uint64(10), onAcceptState.GetAccruedFees())
+
+	active, err := onAcceptState.GetActiveSubnetOnlyValidators()
+	require.NoError(err)
+	require.Equal([]state.SubnetOnlyValidator{remainingSOV}, active)
+
+	sov, err := onAcceptState.GetSubnetOnlyValidator(expiringSOV.ValidationID)
+	require.NoError(err)
+	require.False(sov.IsActive())
+	require.False(sov.IsRemoved())
+
+	newChainTime = newChainTime.Add(time.Second)
+	changed, err = AdvanceTimeTo(&env.backend, onAcceptState, newChainTime)
+	require.NoError(err)
+	require.False(changed)
+	require.Equal(uint64(12), onAcceptState.GetAccruedFees())
+}
@@ -12,14 +12,20 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs/fee"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/message"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
 
 var (
@@ -28,8 +34,17 @@ var (
 	errEmptyNodeID                = errors.New("validator nodeID cannot be empty")
 	errMaxStakeDurationTooLarge   = errors.New("max stake duration must be less than or equal to the global max stake duration")
 	errMissingStartTimePreDurango = errors.New("staker transactions must have a StartTime pre-Durango")
+	errWarpMessageExpired         = errors.New("warp message expired")
+	errWarpMessageNotYetAllowed   = errors.New("warp message not yet allowed")
+	errWarpMessageAlreadyIssued   = errors.New("warp message already issued")
+	errValidatorRemoved           = errors.New("validator was removed")
+	errStaleNonce                 = errors.New("stale nonce")
 )
 
+// RegisterSubnetValidatorTxExpiryWindow is the maximum amount of time into the
+// future that the expiry of a RegisterSubnetValidator message can be set to.
+const RegisterSubnetValidatorTxExpiryWindow = 24 * time.Hour
+
 type StandardTxExecutor struct {
 	// inputs, to be filled before visitor methods are called
 	*Backend
@@ -613,6 +628,407 @@ func (e *StandardTxExecutor) BaseTx(tx *txs.BaseTx) error {
 	return nil
 }
 
+// ConvertSubnetTx converts [tx.Subnet] into a subnet that is managed by the
+// validator manager at [tx.ChainID] and [tx.Address]. The initial validators
+// are added as subnet-only validators funded by their provided balances.
+func (e *StandardTxExecutor) ConvertSubnetTx(tx *txs.ConvertSubnetTx) error {
+	var (
+		currentTimestamp = e.State.GetTimestamp()
+		upgrades         = e.Backend.Config.UpgradeConfig
+	)
+	if !upgrades.IsEtnaActivated(currentTimestamp) {
+		return ErrEtnaUpgradeNotActive
+	}
+
+	if err := e.Tx.SyntacticVerify(e.Ctx); err != nil {
+		return err
+	}
+
+	if err := avax.VerifyMemoFieldLength(tx.Memo, true /*=isDurangoActive*/); err != nil {
+		return err
+	}
+
+	baseTxCreds, err := verifyPoASubnetAuthorization(e.Backend, e.State, e.Tx, tx.Subnet, tx.SubnetAuth)
+	if err != nil {
+		return err
+	}
+
+	var (
+		startTime   = uint64(currentTimestamp.Unix())
+		accruedFees = e.State.GetAccruedFees()
+		fee         uint64
+	)
+	fee, err = e.FeeCalculator.CalculateFee(tx)
+	if err != nil {
+		return err
+	}
+	for i, vdr := range tx.Validators {
+		if err := verifyNotLegacySubnetValidator(e.State, tx.Subnet, vdr.NodeID); err != nil {
+			return err
+		}
+
+		var endAccumulatedFee uint64
+		if vdr.Balance != 0 {
+			endAccumulatedFee, err = math.Add(accruedFees, vdr.Balance)
+			if err != nil {
+				return err
+			}
+		}
+
+		sov := state.SubnetOnlyValidator{
+			ValidationID:          tx.ValidationID(i),
+			SubnetID:              tx.Subnet,
+			NodeID:                vdr.NodeID,
+			PublicKey:             bls.PublicKeyToUncompressedBytes(vdr.Signer.Key()),
+			RemainingBalanceOwner: vdr.RemainingBalanceOwner,
+			StartTime:             startTime,
+			Weight:                vdr.Weight,
+			MinNonce:              0,
+			EndAccumulatedFee:     endAccumulatedFee,
+		}
+		if err := e.State.PutSubnetOnlyValidator(sov); err != nil {
+			return err
+		}
+
+		fee, err = math.Add(fee, vdr.Balance)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := e.Backend.FlowChecker.VerifySpend(
+		tx,
+		e.State,
+		tx.Ins,
+		tx.Outs,
+		baseTxCreds,
+		map[ids.ID]uint64{
+			e.Ctx.AVAXAssetID: fee,
+		},
+	); err != nil {
+		return err
+	}
+
+	txID := e.Tx.ID()
+	avax.Consume(e.State, tx.Ins)
+	avax.Produce(e.State, txID, tx.Outs)
+	e.State.SetSubnetManager(tx.Subnet, tx.ChainID, tx.Address)
+	return nil
+}
+
+// RegisterSubnetValidatorTx adds the subnet-only validator described by the
+// RegisterSubnetValidator warp message in [tx.Message]. The message must have
+// been sent by the validator manager of the subnet.
+func (e *StandardTxExecutor) RegisterSubnetValidatorTx(tx *txs.RegisterSubnetValidatorTx) error {
+	var (
+		currentTimestamp = e.State.GetTimestamp()
+		upgrades         = e.Backend.Config.UpgradeConfig
+	)
+	if !upgrades.IsEtnaActivated(currentTimestamp) {
+		return ErrEtnaUpgradeNotActive
+	}
+
+	if err := e.Tx.SyntacticVerify(e.Ctx); err != nil {
+		return err
+	}
+
+	if err := avax.VerifyMemoFieldLength(tx.Memo, true /*=isDurangoActive*/); err != nil {
+		return err
+	}
+
+	warpMessage, addressedCall, err := verifyWarpMessage(e.Backend, e.State, tx.Message)
+	if err != nil {
+		return err
+	}
+	msg, err := message.ParseRegisterSubnetValidator(addressedCall.Payload)
+	if err != nil {
+		return err
+	}
+	if err := msg.Verify(); err != nil {
+		return err
+	}
+	if err := verifySubnetManager(e.State, msg.SubnetID, warpMessage.SourceChainID, addressedCall.SourceAddress); err != nil {
+		return err
+	}
+
+	now := uint64(currentTimestamp.Unix())
+	if msg.Expiry <= now {
+		return fmt.Errorf("%w: expiry %d <= current time %d", errWarpMessageExpired, msg.Expiry, now)
+	}
+	maxExpiry := now + uint64(RegisterSubnetValidatorTxExpiryWindow/time.Second)
+	if msg.Expiry > maxExpiry {
+		return fmt.Errorf("%w: expiry %d > max expiry %d", errWarpMessageNotYetAllowed, msg.Expiry, maxExpiry)
+	}
+
+	validationID := msg.ValidationID()
+	_, err = e.State.GetSubnetOnlyValidator(validationID)
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: validationID %s", errWarpMessageAlreadyIssued, validationID)
+	case !errors.Is(err, database.ErrNotFound):
+		return err
+	}
+
+	pop := signer.ProofOfPossession{
+		PublicKey:         msg.BLSPublicKey,
+		ProofOfPossession: tx.ProofOfPossession,
+	}
+	if err := pop.Verify(); err != nil {
+		return err
+	}
+
+	if err := verifyNotLegacySubnetValidator(e.State, msg.SubnetID, msg.NodeID); err != nil {
+		return err
+	}
+
+	subnetWeight, err := e.State.WeightOfSubnetOnlyValidators(msg.SubnetID)
+	if err != nil {
+		return err
+	}
+	if err := msg.VerifyTotalWeight(subnetWeight); err != nil {
+		return err
+	}
+
+	var endAccumulatedFee uint64
+	if tx.Balance != 0 {
+		endAccumulatedFee, err = math.Add(e.State.GetAccruedFees(), tx.Balance)
+		if err != nil {
+			return err
+		}
+	}
+
+	sov := state.SubnetOnlyValidator{
+		ValidationID:          validationID,
+		SubnetID:              msg.SubnetID,
+		NodeID:                msg.NodeID,
+		PublicKey:             bls.PublicKeyToUncompressedBytes(pop.Key()),
+		RemainingBalanceOwner: msg.RemainingBalanceOwner,
+		StartTime:             now,
+		Weight:                msg.Weight,
+		MinNonce:              0,
+		EndAccumulatedFee:     endAccumulatedFee,
+	}
+	if err := e.State.PutSubnetOnlyValidator(sov); err != nil {
+		return err
+	}
+
+	fee, err := e.FeeCalculator.CalculateFee(tx)
+	if err != nil {
+		return err
+	}
+	fee, err = math.Add(fee, tx.Balance)
+	if err != nil {
+		return err
+	}
+	if err := e.FlowChecker.VerifySpend(
+		tx,
+		e.State,
+		tx.Ins,
+		tx.Outs,
+		e.Tx.Creds,
+		map[ids.ID]uint64{
+			e.Ctx.AVAXAssetID: fee,
+		},
+	); err != nil {
+		return err
+	}
+
+	txID := e.Tx.ID()
+	avax.Consume(e.State, tx.Ins)
+	avax.Produce(e.State, txID, tx.Outs)
+	return nil
+}
+
+// SetSubnetValidatorWeightTx modifies the weight of a subnet-only validator as
+// described by the SubnetValidatorWeight warp message in [tx.Message]. Setting
+// the weight to 0 removes the validator and refunds its remaining balance to
+// its RemainingBalanceOwner.
+func (e *StandardTxExecutor) SetSubnetValidatorWeightTx(tx *txs.SetSubnetValidatorWeightTx) error {
+	var (
+		currentTimestamp = e.State.GetTimestamp()
+		upgrades         = e.Backend.Config.UpgradeConfig
+	)
+	if !upgrades.IsEtnaActivated(currentTimestamp) {
+		return ErrEtnaUpgradeNotActive
+	}
+
+	if err := e.Tx.SyntacticVerify(e.Ctx); err != nil {
+		return err
+	}
+
+	if err := avax.VerifyMemoFieldLength(tx.Memo, true /*=isDurangoActive*/); err != nil {
+		return err
+	}
+
+	warpMessage, addressedCall, err := verifyWarpMessage(e.Backend, e.State, tx.Message)
+	if err != nil {
+		return err
+	}
+	msg, err := message.ParseSubnetValidatorWeight(addressedCall.Payload)
+	if err != nil {
+		return err
+	}
+	if err := msg.Verify(); err != nil {
+		return err
+	}
+
+	sov, err := e.State.GetSubnetOnlyValidator(msg.ValidationID)
+	if err != nil {
+		return fmt.Errorf("failed to get validator %s: %w", msg.ValidationID, err)
+	}
+	if sov.IsRemoved() {
+		return fmt.Errorf("%w: %s", errValidatorRemoved, msg.ValidationID)
+	}
+	if err := verifySubnetManager(e.State, sov.SubnetID, warpMessage.SourceChainID, addressedCall.SourceAddress); err != nil {
+		return err
+	}
+	if msg.Nonce < sov.MinNonce {
+		return fmt.Errorf("%w: nonce %d < min nonce %d", errStaleNonce, msg.Nonce, sov.MinNonce)
+	}
+
+	subnetWeight, err := e.State.WeightOfSubnetOnlyValidators(sov.SubnetID)
+	if err != nil {
+		return err
+	}
+	if err := msg.VerifyTotalWeight(subnetWeight, sov.Weight); err != nil {
+		return err
+	}
+
+	txID := e.Tx.ID()
+	if msg.Weight == 0 {
+		if sov.IsActive() {
+			accruedFees := e.State.GetAccruedFees()
+			remainingBalance := sov.EndAccumulatedFee - accruedFees
+			utxo := &avax.UTXO{
+				UTXOID: avax.UTXOID{
+					TxID:        txID,
+					OutputIndex: uint32(len(tx.Outs)),
+				},
+				Asset: avax.Asset{
+					ID: e.Ctx.AVAXAssetID,
+				},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: remainingBalance,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: sov.RemainingBalanceOwner.Threshold,
+						Addrs:     sov.RemainingBalanceOwner.Addresses,
+					},
+				},
+			}
+			e.State.AddUTXO(utxo)
+		}
+		sov.EndAccumulatedFee = 0
+	} else {
+		sov.MinNonce = msg.Nonce + 1
+	}
+	sov.Weight = msg.Weight
+	if err := e.State.PutSubnetOnlyValidator(sov); err != nil {
+		return err
+	}
+
+	fee, err := e.FeeCalculator.CalculateFee(tx)
+	if err != nil {
+		return err
+	}
+	if err := e.FlowChecker.VerifySpend(
+		tx,
+		e.State,
+		tx.Ins,
+		tx.Outs,
+		e.Tx.Creds,
+		map[ids.ID]uint64{
+			e.Ctx.AVAXAssetID: fee,
+		},
+	); err != nil {
+		return err
+	}
+
+	avax.Consume(e.State, tx.Ins)
+	avax.Produce(e.State, txID, tx.Outs)
+	return nil
+}
+
+// IncreaseBalanceTx adds [tx.Balance] to the balance of the subnet-only
+// validator [tx.ValidationID]. If the validator was deactivated because its
+// balance ran out, it is re-activated.
+func (e *StandardTxExecutor) IncreaseBalanceTx(tx *txs.IncreaseBalanceTx) error {
+	var (
+		currentTimestamp = e.State.GetTimestamp()
+		upgrades         = e.Backend.Config.UpgradeConfig
+	)
+	if !upgrades.IsEtnaActivated(currentTimestamp) {
+		return ErrEtnaUpgradeNotActive
+	}
+
+	if err := e.Tx.SyntacticVerify(e.Ctx); err != nil {
+		return err
+	}
+
+	if err := avax.VerifyMemoFieldLength(tx.Memo, true /*=isDurangoActive*/); err != nil {
+		return err
+	}
+
+	sov, err := e.State.GetSubnetOnlyValidator(tx.ValidationID)
+	if err != nil {
+		return fmt.Errorf("failed to get validator %s: %w", tx.ValidationID, err)
+	}
+	if sov.IsRemoved() {
+		return fmt.Errorf("%w: %s", errValidatorRemoved, tx.ValidationID)
+	}
+
+	startingFee := sov.EndAccumulatedFee
+	if !sov.IsActive() {
+		startingFee = e.State.GetAccruedFees()
+	}
+	sov.EndAccumulatedFee, err = math.Add(startingFee, tx.Balance)
+	if err != nil {
+		return err
+	}
+	if err := e.State.PutSubnetOnlyValidator(sov); err != nil {
+		return err
+	}
+
+	fee, err := e.FeeCalculator.CalculateFee(tx)
+	if err != nil {
+		return err
+	}
+	fee, err = math.Add(fee, tx.Balance)
+	if err != nil {
+		return err
+	}
+	if err := e.FlowChecker.VerifySpend(
+		tx,
+		e.State,
+		tx.Ins,
+		tx.Outs,
+		e.Tx.Creds,
+		map[ids.ID]uint64{
+			e.Ctx.AVAXAssetID: fee,
+		},
+	); err != nil {
+		return err
+	}
+
+	txID := e.Tx.ID()
+	avax.Consume(e.State, tx.Ins)
+	avax.Produce(e.State, txID, tx.Outs)
+	return nil
+}
+
+// verifyNotLegacySubnetValidator returns an error if [nodeID] is a current or
+// pending validator of [subnetID] that was added with an AddSubnetValidatorTx.
+func verifyNotLegacySubnetValidator(chainState state.Chain, subnetID ids.ID, nodeID ids.NodeID) error {
+	_, err := GetValidator(chainState, subnetID, nodeID)
+	switch {
+	case err == nil:
+		return fmt.Errorf("%s %w of %s", nodeID, ErrAlreadyValidator, subnetID)
+	case errors.Is(err, database.ErrNotFound):
+		return nil
+	default:
+		return err
+	}
+}
+
 // Creates the staker as defined in [stakerTx] and adds it to [e.State].
 func (e *StandardTxExecutor) putStaker(stakerTx txs.Staker) error {
 	var (
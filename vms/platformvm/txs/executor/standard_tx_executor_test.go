@@ -2170,6 +2170,7 @@ func TestStandardExecutorTransformSubnetTx(t *testing.T) {
 				env.state.EXPECT().GetTimestamp().Return(env.latestForkTime).AnyTimes()
 				env.state.EXPECT().GetSubnetOwner(env.unsignedTx.Subnet).Return(subnetOwner, nil)
 				env.state.EXPECT().GetSubnetTransformation(env.unsignedTx.Subnet).Return(nil, database.ErrNotFound).Times(1)
+				env.state.EXPECT().GetSubnetManager(env.unsignedTx.Subnet).Return(ids.Empty, nil, database.ErrNotFound).Times(1)
 				env.fx.EXPECT().VerifyPermission(gomock.Any(), env.unsignedTx.SubnetAuth, env.tx.Creds[len(env.tx.Creds)-1], subnetOwner).Return(nil)
 				env.flowChecker.EXPECT().VerifySpend(
 					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
@@ -2206,6 +2207,7 @@ func TestStandardExecutorTransformSubnetTx(t *testing.T) {
 				env.state.EXPECT().GetTimestamp().Return(env.latestForkTime).AnyTimes()
 				env.state.EXPECT().GetSubnetOwner(env.unsignedTx.Subnet).Return(subnetOwner, nil).Times(1)
 				env.state.EXPECT().GetSubnetTransformation(env.unsignedTx.Subnet).Return(nil, database.ErrNotFound).Times(1)
+				env.state.EXPECT().GetSubnetManager(env.unsignedTx.Subnet).Return(ids.Empty, nil, database.ErrNotFound).Times(1)
 				env.fx.EXPECT().VerifyPermission(env.unsignedTx, env.unsignedTx.SubnetAuth, env.tx.Creds[len(env.tx.Creds)-1], subnetOwner).Return(nil).Times(1)
 				env.flowChecker.EXPECT().VerifySpend(
 					env.unsignedTx, env.state, env.unsignedTx.Ins, env.unsignedTx.Outs, env.tx.Creds[:len(env.tx.Creds)-1], gomock.Any(),
@@ -2285,3 +2287,92 @@ func defaultTestConfig(t *testing.T, f fork, tm time.Time) *config.Config {
 
 	return c
 }
+
+func TestStandardExecutorConvertSubnetTx(t *testing.T) {
+	require := require.New(t)
+	env := newEnvironment(t, etna)
+	env.ctx.Lock.Lock()
+	defer env.ctx.Lock.Unlock()
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+
+	var (
+		subnetID = testSubnet1.ID()
+		chainID  = ids.GenerateTestID()
+		address  = utils.RandomBytes(32)
+		balance  = 5 * units.MilliAvax
+		vdr      = &txs.ConvertSubnetValidator{
+			NodeID:  ids.GenerateTestNodeID(),
+			Weight:  1,
+			Balance: balance,
+			Signer:  *signer.NewProofOfPossession(sk),
+		}
+	)
+
+	builder, txSigner := env.factory.NewWallet(testSubnet1ControlKeys...)
+	utx, err := builder.NewConvertSubnetTx(
+		subnetID,
+		chainID,
+		address,
+		[]*txs.ConvertSubnetValidator{vdr},
+	)
+	require.NoError(err)
+	tx, err := walletsigner.SignUnsigned(context.Background(), txSigner, utx)
+	require.NoError(err)
+
+	onAcceptState, err := state.NewDiff(lastAcceptedID, env)
+	require.NoError(err)
+
+	executor := StandardTxExecutor{
+		Backend:       &env.backend,
+		State:         onAcceptState,
+		FeeCalculator: state.PickFeeCalculator(env.config, onAcceptState),
+		Tx:            tx,
+	}
+	require.NoError(tx.Unsigned.Visit(&executor))
+
+	managerChainID, managerAddress, err := onAcceptState.GetSubnetManager(subnetID)
+	require.NoError(err)
+	require.Equal(chainID, managerChainID)
+	require.Equal(address, managerAddress)
+
+	validationID := utx.ValidationID(0)
+	sov, err := onAcceptState.GetSubnetOnlyValidator(validationID)
+	require.NoError(err)
+	require.Equal(
+		state.SubnetOnlyValidator{
+			ValidationID:      validationID,
+			SubnetID:          subnetID,
+			NodeID:            vdr.NodeID,
+			PublicKey:         bls.PublicKeyToUncompressedBytes(vdr.Signer.Key()),
+			StartTime:         uint64(onAcceptState.GetTimestamp().Unix()),
+			Weight:            vdr.Weight,
+			EndAccumulatedFee: balance,
+		},
+		sov,
+	)
+
+	// The subnet can no longer be modified by the subnet owner.
+	_, err = verifyPoASubnetAuthorization(&env.backend, onAcceptState, tx, subnetID, utx.SubnetAuth)
+	require.ErrorIs(err, errIsImmutable)
+
+	// The balance of the validator can be increased by anyone.
+	builder, txSigner = env.factory.NewWallet(preFundedKeys[4])
+	increaseUTX, err := builder.NewIncreaseBalanceTx(validationID, balance)
+	require.NoError(err)
+	increaseTx, err := walletsigner.SignUnsigned(context.Background(), txSigner, increaseUTX)
+	require.NoError(err)
+
+	executor = StandardTxExecutor{
+		Backend:       &env.backend,
+		State:         onAcceptState,
+		FeeCalculator: state.PickFeeCalculator(env.config, onAcceptState),
+		Tx:            increaseTx,
+	}
+	require.NoError(increaseTx.Unsigned.Visit(&executor))
+
+	sov, err = onAcceptState.GetSubnetOnlyValidator(validationID)
+	require.NoError(err)
+	require.Equal(2*balance, sov.EndAccumulatedFee)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
@@ -165,6 +166,12 @@ func AdvanceTimeTo(
 		changed = true
 	}
 
+	deactivated, err := advanceSubnetOnlyValidatorFees(backend, parentState.GetTimestamp(), newChainTime, changes)
+	if err != nil {
+		return false, err
+	}
+	changed = changed || deactivated
+
 	if err := changes.Apply(parentState); err != nil {
 		return false, err
 	}
@@ -173,6 +180,60 @@ func AdvanceTimeTo(
 	return changed, nil
 }
 
+// advanceSubnetOnlyValidatorFees charges the continuous fee of the
+// subnet-only validators for the time between [parentTime] and [newChainTime]
+// and deactivates the validators whose balance has been exhausted.
+//
+// Validators are deactivated lazily, by the first block whose timestamp is at
+// or after the time their balance runs out.
+//
+// Returns true iff a validator was deactivated.
+func advanceSubnetOnlyValidatorFees(
+	backend *Backend,
+	parentTime time.Time,
+	newChainTime time.Time,
+	changes state.Chain,
+) (bool, error) {
+	etnaTime := backend.Config.UpgradeConfig.EtnaTime
+	if newChainTime.Before(etnaTime) {
+		return false, nil
+	}
+	if parentTime.Before(etnaTime) {
+		parentTime = etnaTime
+	}
+
+	secondsElapsed := uint64(newChainTime.Unix() - parentTime.Unix())
+	fee, err := math.Mul(backend.Config.ValidatorFeeRate, secondsElapsed)
+	if err != nil {
+		return false, err
+	}
+	accruedFees, err := math.Add(changes.GetAccruedFees(), fee)
+	if err != nil {
+		return false, err
+	}
+	changes.SetAccruedFees(accruedFees)
+
+	sovs, err := changes.GetActiveSubnetOnlyValidators()
+	if err != nil {
+		return false, err
+	}
+
+	var deactivated bool
+	for _, sov := range sovs {
+		// Invariant: [sovs] is sorted by EndAccumulatedFee.
+		if sov.EndAccumulatedFee > accruedFees {
+			break
+		}
+
+		sov.EndAccumulatedFee = 0
+		if err := changes.PutSubnetOnlyValidator(sov); err != nil {
+			return false, err
+		}
+		deactivated = true
+	}
+	return deactivated, nil
+}
+
 func GetRewardsCalculator(
 	backend *Backend,
 	parentState state.Chain,
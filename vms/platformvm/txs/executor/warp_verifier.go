@@ -0,0 +1,178 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+)
+
+const (
+	// WarpQuorumNumerator and WarpQuorumDenominator define the fraction of the
+	// weight of a subnet that must sign a warp message for the P-chain to
+	// accept it.
+	WarpQuorumNumerator   = 67
+	WarpQuorumDenominator = 100
+)
+
+var (
+	_ validators.State = (*chainValidatorState)(nil)
+
+	errWrongWarpMessageSourceChainID = errors.New("wrong warp message source chainID")
+	errWrongWarpMessageSourceAddress = errors.New("wrong warp message source address")
+	errNotABlockchain                = errors.New("not a blockchain")
+)
+
+// verifyWarpMessage verifies that [msgBytes] is a warp message containing an
+// addressed call that was signed by the current validators of the subnet that
+// the source chain belongs to. The sender of the message is not verified, see
+// [verifySubnetManager].
+func verifyWarpMessage(
+	backend *Backend,
+	chainState state.Chain,
+	msgBytes []byte,
+) (*warp.Message, *payload.AddressedCall, error) {
+	msg, err := warp.ParseMessage(msgBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	addressedCall, err := payload.ParseAddressedCall(msg.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The message is verified against the validator set in [chainState]
+	// rather than against a historical P-chain height so that verification is
+	// deterministic.
+	err = msg.Signature.Verify(
+		context.TODO(),
+		&msg.UnsignedMessage,
+		backend.Ctx.NetworkID,
+		&chainValidatorState{chain: chainState},
+		0,
+		WarpQuorumNumerator,
+		WarpQuorumDenominator,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, addressedCall, nil
+}
+
+// verifySubnetManager verifies that [sourceChainID] and [sourceAddress]
+// identify the validator manager of [subnetID].
+func verifySubnetManager(
+	chainState state.Chain,
+	subnetID ids.ID,
+	sourceChainID ids.ID,
+	sourceAddress []byte,
+) error {
+	managerChainID, managerAddress, err := chainState.GetSubnetManager(subnetID)
+	if err != nil {
+		return fmt.Errorf("failed to get manager of subnet %s: %w", subnetID, err)
+	}
+	if sourceChainID != managerChainID {
+		return fmt.Errorf("%w: expected %s but got %s",
+			errWrongWarpMessageSourceChainID,
+			managerChainID,
+			sourceChainID,
+		)
+	}
+	if !bytes.Equal(sourceAddress, managerAddress) {
+		return fmt.Errorf("%w: expected %x but got %x",
+			errWrongWarpMessageSourceAddress,
+			managerAddress,
+			sourceAddress,
+		)
+	}
+	return nil
+}
+
+// chainValidatorState exposes the current validator sets of [chain] as a
+// [validators.State]. The requested height is ignored.
+type chainValidatorState struct {
+	chain state.Chain
+}
+
+func (*chainValidatorState) GetMinimumHeight(context.Context) (uint64, error) {
+	return 0, nil
+}
+
+func (*chainValidatorState) GetCurrentHeight(context.Context) (uint64, error) {
+	return 0, nil
+}
+
+func (s *chainValidatorState) GetSubnetID(_ context.Context, chainID ids.ID) (ids.ID, error) {
+	if chainID == constants.PlatformChainID {
+		return constants.PrimaryNetworkID, nil
+	}
+
+	chainTx, _, err := s.chain.GetTx(chainID)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("problem retrieving blockchain %q: %w", chainID, err)
+	}
+	chain, ok := chainTx.Unsigned.(*txs.CreateChainTx)
+	if !ok {
+		return ids.Empty, fmt.Errorf("%q %w", chainID, errNotABlockchain)
+	}
+	return chain.SubnetID, nil
+}
+
+// GetValidatorSet returns the active subnet-only validators of [subnetID] and
+// the legacy validators of [subnetID]. Legacy validators use their primary
+// network public key.
+func (s *chainValidatorState) GetValidatorSet(
+	_ context.Context,
+	_ uint64,
+	subnetID ids.ID,
+) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	sovs, err := s.chain.GetSubnetOnlyValidators(subnetID)
+	if err != nil {
+		return nil, err
+	}
+	legacyValidators, err := s.chain.GetCurrentValidators(subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	vdrs := make(map[ids.NodeID]*validators.GetValidatorOutput, len(sovs)+len(legacyValidators))
+	for _, sov := range sovs {
+		if !sov.IsActive() {
+			continue
+		}
+		vdrs[sov.NodeID] = &validators.GetValidatorOutput{
+			NodeID:    sov.NodeID,
+			PublicKey: bls.PublicKeyFromValidUncompressedBytes(sov.PublicKey),
+			Weight:    sov.Weight,
+		}
+	}
+
+	for _, staker := range legacyValidators {
+		vdr := &validators.GetValidatorOutput{
+			NodeID: staker.NodeID,
+			Weight: staker.Weight,
+		}
+		primaryValidator, err := s.chain.GetCurrentValidator(constants.PrimaryNetworkID, staker.NodeID)
+		switch {
+		case err == nil:
+			vdr.PublicKey = primaryValidator.PublicKey
+		case !errors.Is(err, database.ErrNotFound):
+			return nil, err
+		}
+		vdrs[staker.NodeID] = vdr
+	}
+	return vdrs, nil
+}
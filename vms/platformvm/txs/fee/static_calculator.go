@@ -110,6 +110,26 @@ func (c *staticVisitor) BaseTx(*txs.BaseTx) error {
 	return nil
 }
 
+func (c *staticVisitor) ConvertSubnetTx(*txs.ConvertSubnetTx) error {
+	c.fee = c.config.TxFee
+	return nil
+}
+
+func (c *staticVisitor) RegisterSubnetValidatorTx(*txs.RegisterSubnetValidatorTx) error {
+	c.fee = c.config.TxFee
+	return nil
+}
+
+func (c *staticVisitor) SetSubnetValidatorWeightTx(*txs.SetSubnetValidatorWeightTx) error {
+	c.fee = c.config.TxFee
+	return nil
+}
+
+func (c *staticVisitor) IncreaseBalanceTx(*txs.IncreaseBalanceTx) error {
+	c.fee = c.config.TxFee
+	return nil
+}
+
 func (c *staticVisitor) ImportTx(*txs.ImportTx) error {
 	c.fee = c.config.TxFee
 	return nil
@@ -20,4 +20,8 @@ type Visitor interface {
 	AddPermissionlessDelegatorTx(*AddPermissionlessDelegatorTx) error
 	TransferSubnetOwnershipTx(*TransferSubnetOwnershipTx) error
 	BaseTx(*BaseTx) error
+	ConvertSubnetTx(*ConvertSubnetTx) error
+	RegisterSubnetValidatorTx(*RegisterSubnetValidatorTx) error
+	SetSubnetValidatorWeightTx(*SetSubnetValidatorWeightTx) error
+	IncreaseBalanceTx(*IncreaseBalanceTx) error
 }
@@ -85,6 +85,7 @@ type State interface {
 		validators map[ids.NodeID]*validators.GetValidatorOutput,
 		startHeight uint64,
 		endHeight uint64,
+		subnetID ids.ID,
 	) error
 }
 
@@ -282,6 +283,7 @@ func (m *manager) makePrimaryNetworkValidatorSet(
 		validatorSet,
 		currentHeight,
 		lastDiffHeight,
+		constants.PrimaryNetworkID,
 	)
 	return validatorSet, currentHeight, err
 }
@@ -330,24 +332,44 @@ func (m *manager) makeSubnetValidatorSet(
 		return nil, 0, err
 	}
 
-	// Update the subnet validator set to include the public keys at
-	// [currentHeight]. When we apply the public key diffs, we will convert
-	// these keys to represent the public keys at [targetHeight]. If the subnet
-	// validator is not currently a primary network validator, it doesn't have a
-	// key at [currentHeight].
+	// Subnet-only validators register their own public keys, which are
+	// converted to represent the public keys at [targetHeight] by applying the
+	// public key diffs of the subnet. Any validator without a public key after
+	// this point is a legacy subnet validator at [targetHeight].
+	err = m.state.ApplyValidatorPublicKeyDiffs(
+		ctx,
+		subnetValidatorSet,
+		currentHeight,
+		lastDiffHeight,
+		subnetID,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Update the legacy subnet validators to include their primary network
+	// public keys at [currentHeight]. When we apply the primary network public
+	// key diffs, we will convert these keys to represent the public keys at
+	// [targetHeight]. If the subnet validator is not currently a primary
+	// network validator, it doesn't have a key at [currentHeight].
+	legacyValidatorSet := make(map[ids.NodeID]*validators.GetValidatorOutput)
 	for nodeID, vdr := range subnetValidatorSet {
+		if vdr.PublicKey != nil {
+			continue
+		}
+
+		legacyValidatorSet[nodeID] = vdr
 		if primaryVdr, ok := primaryValidatorSet[nodeID]; ok {
 			vdr.PublicKey = primaryVdr.PublicKey
-		} else {
-			vdr.PublicKey = nil
 		}
 	}
 
 	err = m.state.ApplyValidatorPublicKeyDiffs(
 		ctx,
-		subnetValidatorSet,
+		legacyValidatorSet,
 		currentHeight,
 		lastDiffHeight,
+		constants.PrimaryNetworkID,
 	)
 	return subnetValidatorSet, currentHeight, err
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/platformvm/block"
+	"github.com/ava-labs/avalanchego/vms/platformvm/block/builder/policy"
 	"github.com/ava-labs/avalanchego/vms/platformvm/config"
 	"github.com/ava-labs/avalanchego/vms/platformvm/fx"
 	"github.com/ava-labs/avalanchego/vms/platformvm/network"
@@ -85,6 +86,9 @@ type VM struct {
 
 	manager blockexecutor.Manager
 
+	// Optional exporter that is bound to this VM once it is initialized
+	stateExporter *StateExporter
+
 	// Cancelled on shutdown
 	onShutdownCtx context.Context
 	// Call [onShutdownCtxCancel] to cancel [onShutdownCtx] during Shutdown()
@@ -177,6 +181,7 @@ func (vm *VM) Initialize(
 		vm.state,
 		txExecutorBackend,
 		validatorManager,
+		execConfig.IndexRewardHistory,
 	)
 
 	txVerifier := network.NewLockedTxVerifier(&txExecutorBackend.Ctx.Lock, vm.manager)
@@ -205,10 +210,15 @@ func (vm *VM) Initialize(
 	go vm.Network.PushGossip(vm.onShutdownCtx)
 	go vm.Network.PullGossip(vm.onShutdownCtx)
 
+	blockBuilderPolicy, err := policy.New(execConfig.BlockBuilderPolicy, vm.ctx.AVAXAssetID)
+	if err != nil {
+		return fmt.Errorf("failed to initialize block builder policy: %w", err)
+	}
 	vm.Builder = blockbuilder.New(
 		mempool,
 		txExecutorBackend,
 		vm.manager,
+		blockBuilderPolicy,
 	)
 
 	// Create all of the chains that the database says exist
@@ -240,6 +250,23 @@ func (vm *VM) Initialize(
 		}
 	}()
 
+	if execConfig.IndexRewardHistory {
+		// The reward history is only marked as backfilled once the backfill
+		// succeeds, so a failed backfill is retried the next time the VM is
+		// initialized.
+		go func() {
+			err := vm.state.BackfillRewardHistory(&vm.ctx.Lock, vm.ctx.Log)
+			if err != nil {
+				vm.ctx.Log.Warn("backfilling reward history failed",
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+
+	if vm.stateExporter != nil {
+		vm.stateExporter.setVM(vm)
+	}
 	return nil
 }
 
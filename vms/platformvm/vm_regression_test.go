@@ -13,6 +13,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/chains/atomic"
@@ -21,7 +22,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network/p2p"
-	"github.com/ava-labs/avalanchego/network/p2p/gossip"
+	"github.com/ava-labs/avalanchego/proto/pb/sdk"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/snowtest"
@@ -2433,10 +2434,10 @@ func TestValidatorSetRaceCondition(t *testing.T) {
 	nodeID := ids.GenerateTestNodeID()
 	require.NoError(vm.Connected(context.Background(), nodeID, version.CurrentApp))
 
-	protocolAppRequestBytest, err := gossip.MarshalAppRequest(
-		bloom.EmptyFilter.Marshal(),
-		ids.Empty[:],
-	)
+	protocolAppRequestBytest, err := proto.Marshal(&sdk.PullGossipRequest{
+		Filter: bloom.EmptyFilter.Marshal(),
+		Salt:   ids.Empty[:],
+	})
 	require.NoError(err)
 
 	appRequestBytes := p2p.PrefixMessage(
@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errWrongType = errors.New("wrong payload type")
+
+// Payload provides a common interface for all payloads implemented by this
+// package.
+type Payload interface {
+	// Bytes returns the binary representation of this payload.
+	Bytes() []byte
+
+	// initialize the payload with the provided binary representation.
+	initialize(b []byte)
+}
+
+type payload struct {
+	bytes []byte
+}
+
+func (p *payload) Bytes() []byte {
+	return p.bytes
+}
+
+func (p *payload) initialize(bytes []byte) {
+	p.bytes = bytes
+}
+
+func Parse(bytes []byte) (Payload, error) {
+	var p Payload
+	if _, err := Codec.Unmarshal(bytes, &p); err != nil {
+		return nil, err
+	}
+	p.initialize(bytes)
+	return p, nil
+}
+
+func initialize(p Payload) error {
+	bytes, err := Codec.Marshal(CodecVersion, &p)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal %T payload: %w", p, err)
+	}
+	p.initialize(bytes)
+	return nil
+}
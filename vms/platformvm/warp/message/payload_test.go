@@ -0,0 +1,161 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+)
+
+var junkBytes = []byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88}
+
+func TestParseJunk(t *testing.T) {
+	_, err := Parse(junkBytes)
+	require.ErrorIs(t, err, codec.ErrUnknownVersion)
+}
+
+func TestParseWrongPayloadType(t *testing.T) {
+	require := require.New(t)
+
+	weight, err := NewSubnetValidatorWeight(ids.GenerateTestID(), 1, 2)
+	require.NoError(err)
+
+	_, err = ParseRegisterSubnetValidator(weight.Bytes())
+	require.ErrorIs(err, errWrongType)
+}
+
+func TestRegisterSubnetValidator(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	var pk [bls.PublicKeyLen]byte
+	copy(pk[:], bls.PublicKeyToCompressedBytes(bls.PublicFromSecretKey(sk)))
+
+	msg, err := NewRegisterSubnetValidator(
+		ids.GenerateTestID(),
+		ids.GenerateTestNodeID(),
+		pk,
+		1,
+		PChainOwner{
+			Threshold: 1,
+			Addresses: []ids.ShortID{ids.GenerateTestShortID()},
+		},
+		2,
+	)
+	require.NoError(err)
+	require.NoError(msg.Verify())
+
+	parsed, err := ParseRegisterSubnetValidator(msg.Bytes())
+	require.NoError(err)
+	require.Equal(msg, parsed)
+	require.Equal(msg.ValidationID(), parsed.ValidationID())
+}
+
+func TestRegisterSubnetValidatorVerify(t *testing.T) {
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	var pk [bls.PublicKeyLen]byte
+	copy(pk[:], bls.PublicKeyToCompressedBytes(bls.PublicFromSecretKey(sk)))
+
+	tests := []struct {
+		name        string
+		msg         *RegisterSubnetValidator
+		expectedErr error
+	}{
+		{
+			name: "empty subnetID",
+			msg: &RegisterSubnetValidator{
+				BLSPublicKey: pk,
+				Weight:       1,
+			},
+			expectedErr: ErrInvalidSubnetID,
+		},
+		{
+			name: "zero weight",
+			msg: &RegisterSubnetValidator{
+				SubnetID:     ids.GenerateTestID(),
+				BLSPublicKey: pk,
+			},
+			expectedErr: ErrInvalidWeight,
+		},
+		{
+			name: "unsatisfiable owner",
+			msg: &RegisterSubnetValidator{
+				SubnetID:     ids.GenerateTestID(),
+				BLSPublicKey: pk,
+				RemainingBalanceOwner: PChainOwner{
+					Threshold: 1,
+				},
+				Weight: 1,
+			},
+			expectedErr: ErrInvalidOwner,
+		},
+		{
+			name: "valid",
+			msg: &RegisterSubnetValidator{
+				SubnetID:     ids.GenerateTestID(),
+				BLSPublicKey: pk,
+				Weight:       1,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.ErrorIs(t, test.msg.Verify(), test.expectedErr)
+		})
+	}
+}
+
+func TestRegisterSubnetValidatorVerifyTotalWeight(t *testing.T) {
+	require := require.New(t)
+
+	msg := &RegisterSubnetValidator{
+		Weight: 2,
+	}
+	require.NoError(msg.VerifyTotalWeight(math.MaxUint64 - 2))
+
+	err := msg.VerifyTotalWeight(math.MaxUint64 - 1)
+	require.ErrorIs(err, ErrTotalWeightOverflow)
+}
+
+func TestSubnetValidatorWeight(t *testing.T) {
+	require := require.New(t)
+
+	msg, err := NewSubnetValidatorWeight(ids.GenerateTestID(), 1, 2)
+	require.NoError(err)
+	require.NoError(msg.Verify())
+
+	parsed, err := ParseSubnetValidatorWeight(msg.Bytes())
+	require.NoError(err)
+	require.Equal(msg, parsed)
+
+	removal, err := NewSubnetValidatorWeight(ids.GenerateTestID(), math.MaxUint64, 0)
+	require.NoError(err)
+	require.NoError(removal.Verify())
+
+	invalid, err := NewSubnetValidatorWeight(ids.GenerateTestID(), math.MaxUint64, 1)
+	require.NoError(err)
+	require.ErrorIs(invalid.Verify(), ErrNonceReservedForRemoval)
+}
+
+func TestSubnetValidatorWeightVerifyTotalWeight(t *testing.T) {
+	require := require.New(t)
+
+	msg := &SubnetValidatorWeight{
+		Weight: 3,
+	}
+	// The previous weight of the validator is replaced.
+	require.NoError(msg.VerifyTotalWeight(math.MaxUint64, 3))
+	require.NoError(msg.VerifyTotalWeight(math.MaxUint64-1, 2))
+
+	err := msg.VerifyTotalWeight(math.MaxUint64, 2)
+	require.ErrorIs(err, ErrTotalWeightOverflow)
+}
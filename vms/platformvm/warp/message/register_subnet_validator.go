@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/math"
+)
+
+var (
+	_ Payload = (*RegisterSubnetValidator)(nil)
+
+	ErrInvalidSubnetID = errors.New("invalid subnet ID")
+	ErrInvalidWeight   = errors.New("invalid weight")
+	ErrInvalidOwner    = errors.New("invalid owner")
+
+	ErrTotalWeightOverflow = errors.New("total weight of the subnet overflows")
+)
+
+// PChainOwner is the owner of the balance that remains when a subnet-only
+// validator is removed.
+type PChainOwner struct {
+	// The threshold number of [Addresses] that must provide a signature in
+	// order for the owner to be considered valid.
+	Threshold uint32 `serialize:"true" json:"threshold"`
+	// The addresses that are allowed to sign to authenticate the owner.
+	Addresses []ids.ShortID `serialize:"true" json:"addresses"`
+}
+
+// Verify returns an error if the owner can never be satisfied or if its
+// addresses are not sorted and unique.
+func (o *PChainOwner) Verify() error {
+	switch {
+	case o.Threshold > uint32(len(o.Addresses)):
+		return fmt.Errorf("%w: threshold %d exceeds %d addresses", ErrInvalidOwner, o.Threshold, len(o.Addresses))
+	case o.Threshold == 0 && len(o.Addresses) > 0:
+		return fmt.Errorf("%w: addresses are provided without a threshold", ErrInvalidOwner)
+	case !utils.IsSortedAndUnique(o.Addresses):
+		return fmt.Errorf("%w: addresses are not sorted and unique", ErrInvalidOwner)
+	default:
+		return nil
+	}
+}
+
+// RegisterSubnetValidator adds a validator to the subnet.
+type RegisterSubnetValidator struct {
+	payload
+
+	SubnetID     ids.ID                 `serialize:"true" json:"subnetID"`
+	NodeID       ids.NodeID             `serialize:"true" json:"nodeID"`
+	BLSPublicKey [bls.PublicKeyLen]byte `serialize:"true" json:"blsPublicKey"`
+	// Expiry is the unix timestamp after which this message may no longer be
+	// issued to the P-chain.
+	Expiry                uint64      `serialize:"true" json:"expiry"`
+	RemainingBalanceOwner PChainOwner `serialize:"true" json:"remainingBalanceOwner"`
+	Weight                uint64      `serialize:"true" json:"weight"`
+}
+
+// NewRegisterSubnetValidator creates a new initialized
+// RegisterSubnetValidator.
+func NewRegisterSubnetValidator(
+	subnetID ids.ID,
+	nodeID ids.NodeID,
+	blsPublicKey [bls.PublicKeyLen]byte,
+	expiry uint64,
+	remainingBalanceOwner PChainOwner,
+	weight uint64,
+) (*RegisterSubnetValidator, error) {
+	msg := &RegisterSubnetValidator{
+		SubnetID:              subnetID,
+		NodeID:                nodeID,
+		BLSPublicKey:          blsPublicKey,
+		Expiry:                expiry,
+		RemainingBalanceOwner: remainingBalanceOwner,
+		Weight:                weight,
+	}
+	return msg, initialize(msg)
+}
+
+// ParseRegisterSubnetValidator parses bytes into an initialized
+// RegisterSubnetValidator.
+func ParseRegisterSubnetValidator(b []byte) (*RegisterSubnetValidator, error) {
+	payloadIntf, err := Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	payload, ok := payloadIntf.(*RegisterSubnetValidator)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errWrongType, payloadIntf)
+	}
+	return payload, nil
+}
+
+// ValidationID uniquely identifies the validation period created by this
+// message.
+func (r *RegisterSubnetValidator) ValidationID() ids.ID {
+	return hashing.ComputeHash256Array(r.Bytes())
+}
+
+// Verify returns an error if the message is malformed.
+func (r *RegisterSubnetValidator) Verify() error {
+	switch {
+	case r.SubnetID == ids.Empty:
+		return ErrInvalidSubnetID
+	case r.Weight == 0:
+		return ErrInvalidWeight
+	}
+	if err := r.RemainingBalanceOwner.Verify(); err != nil {
+		return err
+	}
+	if _, err := bls.PublicKeyFromCompressedBytes(r.BLSPublicKey[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// VerifyTotalWeight returns an error if registering this validator would
+// overflow the total weight of the subnet, whose validators currently have a
+// total weight of [subnetWeight].
+func (r *RegisterSubnetValidator) VerifyTotalWeight(subnetWeight uint64) error {
+	if _, err := math.Add(subnetWeight, r.Weight); err != nil {
+		return fmt.Errorf("%w: %w", ErrTotalWeightOverflow, err)
+	}
+	return nil
+}
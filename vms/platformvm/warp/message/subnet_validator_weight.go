@@ -0,0 +1,83 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var (
+	_ Payload = (*SubnetValidatorWeight)(nil)
+
+	ErrNonceReservedForRemoval = errors.New("maxUint64 nonce is reserved for removal")
+)
+
+// SubnetValidatorWeight updates the weight of the validator identified by
+// [ValidationID]. A weight of zero removes the validator.
+type SubnetValidatorWeight struct {
+	payload
+
+	ValidationID ids.ID `serialize:"true" json:"validationID"`
+	// Nonce must be at least the nonce of the last weight update applied to
+	// the validator plus one. Messages with a lower nonce are stale.
+	Nonce  uint64 `serialize:"true" json:"nonce"`
+	Weight uint64 `serialize:"true" json:"weight"`
+}
+
+// NewSubnetValidatorWeight creates a new initialized SubnetValidatorWeight.
+func NewSubnetValidatorWeight(
+	validationID ids.ID,
+	nonce uint64,
+	weight uint64,
+) (*SubnetValidatorWeight, error) {
+	msg := &SubnetValidatorWeight{
+		ValidationID: validationID,
+		Nonce:        nonce,
+		Weight:       weight,
+	}
+	return msg, initialize(msg)
+}
+
+// ParseSubnetValidatorWeight parses bytes into an initialized
+// SubnetValidatorWeight.
+func ParseSubnetValidatorWeight(b []byte) (*SubnetValidatorWeight, error) {
+	payloadIntf, err := Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	payload, ok := payloadIntf.(*SubnetValidatorWeight)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errWrongType, payloadIntf)
+	}
+	return payload, nil
+}
+
+// Verify returns an error if the message is malformed.
+func (s *SubnetValidatorWeight) Verify() error {
+	if s.Nonce == math.MaxUint64 && s.Weight != 0 {
+		return ErrNonceReservedForRemoval
+	}
+	return nil
+}
+
+// VerifyTotalWeight returns an error if setting the weight of the validator,
+// whose weight is currently [prevWeight], would overflow the total weight of
+// the subnet, whose validators currently have a total weight of
+// [subnetWeight].
+func (s *SubnetValidatorWeight) VerifyTotalWeight(subnetWeight uint64, prevWeight uint64) error {
+	otherWeight, err := safemath.Sub(subnetWeight, prevWeight)
+	if err != nil {
+		return err
+	}
+	if _, err := safemath.Add(otherWeight, s.Weight); err != nil {
+		return fmt.Errorf("%w: %w", ErrTotalWeightOverflow, err)
+	}
+	return nil
+}
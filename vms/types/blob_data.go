@@ -19,3 +19,20 @@ func (b JSONByteSlice) MarshalJSON() ([]byte, error) {
 	}
 	return json.Marshal(hexData)
 }
+
+func (b *JSONByteSlice) UnmarshalJSON(jsonBytes []byte) error {
+	if string(jsonBytes) == "null" {
+		return nil
+	}
+
+	var hexData string
+	if err := json.Unmarshal(jsonBytes, &hexData); err != nil {
+		return err
+	}
+	v, err := formatting.Decode(formatting.HexNC, hexData)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -147,6 +148,58 @@ type Builder interface {
 		options ...common.Option,
 	) (*txs.TransferSubnetOwnershipTx, error)
 
+	// NewConvertSubnetTx converts the subnet to a subnet that is managed by
+	// the validator manager at [chainID] and [address]. The subnet can no
+	// longer be modified with the subnet owner after this transaction.
+	//
+	// - [subnetID] specifies the subnet to be converted.
+	// - [chainID] specifies the chain where the validator manager lives.
+	// - [address] specifies the address of the validator manager.
+	// - [validators] specifies the initial subnet-only validators of the
+	//   subnet. Their balances are paid by this transaction.
+	NewConvertSubnetTx(
+		subnetID ids.ID,
+		chainID ids.ID,
+		address []byte,
+		validators []*txs.ConvertSubnetValidator,
+		options ...common.Option,
+	) (*txs.ConvertSubnetTx, error)
+
+	// NewRegisterSubnetValidatorTx adds a subnet-only validator to a subnet
+	// that was converted with a ConvertSubnetTx.
+	//
+	// - [balance] specifies the initial balance of the validator.
+	// - [proofOfPossession] is the BLS proof of possession of the public key
+	//   included in [message].
+	// - [message] is the signed warp message containing the
+	//   RegisterSubnetValidator message.
+	NewRegisterSubnetValidatorTx(
+		balance uint64,
+		proofOfPossession [bls.SignatureLen]byte,
+		message []byte,
+		options ...common.Option,
+	) (*txs.RegisterSubnetValidatorTx, error)
+
+	// NewSetSubnetValidatorWeightTx sets the weight of a subnet-only
+	// validator. Setting the weight to 0 removes the validator.
+	//
+	// - [message] is the signed warp message containing the
+	//   SubnetValidatorWeight message.
+	NewSetSubnetValidatorWeightTx(
+		message []byte,
+		options ...common.Option,
+	) (*txs.SetSubnetValidatorWeightTx, error)
+
+	// NewIncreaseBalanceTx increases the balance of a subnet-only validator.
+	//
+	// - [validationID] specifies the validator to fund.
+	// - [balance] specifies the amount to add to the validator's balance.
+	NewIncreaseBalanceTx(
+		validationID ids.ID,
+		balance uint64,
+		options ...common.Option,
+	) (*txs.IncreaseBalanceTx, error)
+
 	// NewImportTx creates an import transaction that attempts to consume all
 	// the available UTXOs and import the funds to [to].
 	//
@@ -585,6 +638,149 @@ func (b *builder) NewTransferSubnetOwnershipTx(
 	return tx, b.initCtx(tx)
 }
 
+func (b *builder) NewConvertSubnetTx(
+	subnetID ids.ID,
+	chainID ids.ID,
+	address []byte,
+	validators []*txs.ConvertSubnetValidator,
+	options ...common.Option,
+) (*txs.ConvertSubnetTx, error) {
+	toBurnAmount := b.context.StaticFeeConfig.TxFee
+	for _, vdr := range validators {
+		var err error
+		toBurnAmount, err = math.Add(toBurnAmount, vdr.Balance)
+		if err != nil {
+			return nil, err
+		}
+	}
+	toBurn := map[ids.ID]uint64{
+		b.context.AVAXAssetID: toBurnAmount,
+	}
+	toStake := map[ids.ID]uint64{}
+	ops := common.NewOptions(options)
+	inputs, outputs, _, err := b.spend(toBurn, toStake, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	subnetAuth, err := b.authorizeSubnet(subnetID, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.Sort(validators)
+	tx := &txs.ConvertSubnetTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    b.context.NetworkID,
+			BlockchainID: constants.PlatformChainID,
+			Ins:          inputs,
+			Outs:         outputs,
+			Memo:         ops.Memo(),
+		}},
+		Subnet:     subnetID,
+		ChainID:    chainID,
+		Address:    address,
+		Validators: validators,
+		SubnetAuth: subnetAuth,
+	}
+	return tx, b.initCtx(tx)
+}
+
+func (b *builder) NewRegisterSubnetValidatorTx(
+	balance uint64,
+	proofOfPossession [bls.SignatureLen]byte,
+	message []byte,
+	options ...common.Option,
+) (*txs.RegisterSubnetValidatorTx, error) {
+	toBurnAmount, err := math.Add(b.context.StaticFeeConfig.TxFee, balance)
+	if err != nil {
+		return nil, err
+	}
+	toBurn := map[ids.ID]uint64{
+		b.context.AVAXAssetID: toBurnAmount,
+	}
+	toStake := map[ids.ID]uint64{}
+	ops := common.NewOptions(options)
+	inputs, outputs, _, err := b.spend(toBurn, toStake, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &txs.RegisterSubnetValidatorTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    b.context.NetworkID,
+			BlockchainID: constants.PlatformChainID,
+			Ins:          inputs,
+			Outs:         outputs,
+			Memo:         ops.Memo(),
+		}},
+		Balance:           balance,
+		ProofOfPossession: proofOfPossession,
+		Message:           message,
+	}
+	return tx, b.initCtx(tx)
+}
+
+func (b *builder) NewSetSubnetValidatorWeightTx(
+	message []byte,
+	options ...common.Option,
+) (*txs.SetSubnetValidatorWeightTx, error) {
+	toBurn := map[ids.ID]uint64{
+		b.context.AVAXAssetID: b.context.StaticFeeConfig.TxFee,
+	}
+	toStake := map[ids.ID]uint64{}
+	ops := common.NewOptions(options)
+	inputs, outputs, _, err := b.spend(toBurn, toStake, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &txs.SetSubnetValidatorWeightTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    b.context.NetworkID,
+			BlockchainID: constants.PlatformChainID,
+			Ins:          inputs,
+			Outs:         outputs,
+			Memo:         ops.Memo(),
+		}},
+		Message: message,
+	}
+	return tx, b.initCtx(tx)
+}
+
+func (b *builder) NewIncreaseBalanceTx(
+	validationID ids.ID,
+	balance uint64,
+	options ...common.Option,
+) (*txs.IncreaseBalanceTx, error) {
+	toBurnAmount, err := math.Add(b.context.StaticFeeConfig.TxFee, balance)
+	if err != nil {
+		return nil, err
+	}
+	toBurn := map[ids.ID]uint64{
+		b.context.AVAXAssetID: toBurnAmount,
+	}
+	toStake := map[ids.ID]uint64{}
+	ops := common.NewOptions(options)
+	inputs, outputs, _, err := b.spend(toBurn, toStake, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &txs.IncreaseBalanceTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    b.context.NetworkID,
+			BlockchainID: constants.PlatformChainID,
+			Ins:          inputs,
+			Outs:         outputs,
+			Memo:         ops.Memo(),
+		}},
+		ValidationID: validationID,
+		Balance:      balance,
+	}
+	return tx, b.initCtx(tx)
+}
+
 func (b *builder) NewImportTx(
 	sourceChainID ids.ID,
 	to *secp256k1fx.OutputOwners,
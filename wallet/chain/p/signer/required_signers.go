@@ -0,0 +1,53 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/keychain"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+var ErrUnknownSigner = errors.New("unknown signer")
+
+// RequiredSigners returns, for each credential of [utx], the addresses that
+// must sign [utx] in signature index order. It also returns whether the
+// signatures are over the hash of [utx] rather than its bytes.
+//
+// [backend] must know about all the UTXOs consumed by [utx] and all the
+// subnets it modifies.
+func RequiredSigners(
+	ctx context.Context,
+	backend Backend,
+	utx txs.UnsignedTx,
+) ([][]ids.ShortID, bool, error) {
+	v := &visitor{
+		kc:      keychain.NewAddressKeychain(),
+		backend: backend,
+		ctx:     ctx,
+	}
+	if err := utx.Visit(v); err != nil {
+		return nil, false, err
+	}
+
+	signers := make([][]ids.ShortID, len(v.txSigners))
+	for credIndex, inputSigners := range v.txSigners {
+		signers[credIndex] = make([]ids.ShortID, len(inputSigners))
+		for sigIndex, signer := range inputSigners {
+			if signer == nil {
+				return nil, false, fmt.Errorf("%w of credential %d at index %d",
+					ErrUnknownSigner,
+					credIndex,
+					sigIndex,
+				)
+			}
+			signers[credIndex][sigIndex] = signer.Address()
+		}
+	}
+	return signers, v.signHash, nil
+}
@@ -45,12 +45,15 @@ func New(kc keychain.Keychain, backend Backend) Signer {
 }
 
 func (s *txSigner) Sign(ctx stdcontext.Context, tx *txs.Tx) error {
-	return tx.Unsigned.Visit(&visitor{
+	v := &visitor{
 		kc:      s.kc,
 		backend: s.backend,
 		ctx:     ctx,
-		tx:      tx,
-	})
+	}
+	if err := tx.Unsigned.Visit(v); err != nil {
+		return err
+	}
+	return sign(tx, v.signHash, v.txSigners)
 }
 
 func SignUnsigned(
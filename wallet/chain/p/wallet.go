@@ -5,9 +5,14 @@ package p
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/gorilla/rpc/v2/json2"
+
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
@@ -19,8 +24,13 @@ import (
 	walletsigner "github.com/ava-labs/avalanchego/wallet/chain/p/signer"
 )
 
+// methodNotFoundPrefix prefixes the error returned by the node when the
+// requested API method doesn't exist.
+const methodNotFoundPrefix = "rpc: can't find method"
+
 var (
-	ErrNotCommitted = errors.New("not committed")
+	ErrNotCommitted     = errors.New("not committed")
+	ErrSimulationFailed = errors.New("simulation failed")
 
 	_ Wallet = (*wallet)(nil)
 )
@@ -131,6 +141,59 @@ type Wallet interface {
 		options ...common.Option,
 	) (*txs.Tx, error)
 
+	// IssueConvertSubnetTx creates, signs, and issues a transaction that
+	// converts the subnet to a subnet that is managed by the validator manager
+	// at [chainID] and [address].
+	//
+	// - [subnetID] specifies the subnet to be converted.
+	// - [chainID] specifies the chain where the validator manager lives.
+	// - [address] specifies the address of the validator manager.
+	// - [validators] specifies the initial subnet-only validators of the
+	//   subnet. Their balances are paid by this transaction.
+	IssueConvertSubnetTx(
+		subnetID ids.ID,
+		chainID ids.ID,
+		address []byte,
+		validators []*txs.ConvertSubnetValidator,
+		options ...common.Option,
+	) (*txs.Tx, error)
+
+	// IssueRegisterSubnetValidatorTx creates, signs, and issues a transaction
+	// that adds a subnet-only validator to a converted subnet.
+	//
+	// - [balance] specifies the initial balance of the validator.
+	// - [proofOfPossession] is the BLS proof of possession of the public key
+	//   included in [message].
+	// - [message] is the signed warp message containing the
+	//   RegisterSubnetValidator message.
+	IssueRegisterSubnetValidatorTx(
+		balance uint64,
+		proofOfPossession [bls.SignatureLen]byte,
+		message []byte,
+		options ...common.Option,
+	) (*txs.Tx, error)
+
+	// IssueSetSubnetValidatorWeightTx creates, signs, and issues a transaction
+	// that sets the weight of a subnet-only validator.
+	//
+	// - [message] is the signed warp message containing the
+	//   SubnetValidatorWeight message.
+	IssueSetSubnetValidatorWeightTx(
+		message []byte,
+		options ...common.Option,
+	) (*txs.Tx, error)
+
+	// IssueIncreaseBalanceTx creates, signs, and issues a transaction that
+	// increases the balance of a subnet-only validator.
+	//
+	// - [validationID] specifies the validator to fund.
+	// - [balance] specifies the amount to add to the validator's balance.
+	IssueIncreaseBalanceTx(
+		validationID ids.ID,
+		balance uint64,
+		options ...common.Option,
+	) (*txs.Tx, error)
+
 	// IssueImportTx creates, signs, and issues an import transaction that
 	// attempts to consume all the available UTXOs and import the funds to [to].
 	//
@@ -248,6 +311,11 @@ type Wallet interface {
 	) (*txs.Tx, error)
 
 	// IssueTx issues the signed tx.
+	//
+	// Unless [common.WithSkipSimulation] is provided, the tx is simulated
+	// before being issued and [ErrSimulationFailed] is returned if it would
+	// fail verification. If the node doesn't support simulating txs, the tx
+	// is issued without being simulated.
 	IssueTx(
 		tx *txs.Tx,
 		options ...common.Option,
@@ -380,6 +448,56 @@ func (w *wallet) IssueTransferSubnetOwnershipTx(
 	return w.IssueUnsignedTx(utx, options...)
 }
 
+func (w *wallet) IssueConvertSubnetTx(
+	subnetID ids.ID,
+	chainID ids.ID,
+	address []byte,
+	validators []*txs.ConvertSubnetValidator,
+	options ...common.Option,
+) (*txs.Tx, error) {
+	utx, err := w.builder.NewConvertSubnetTx(subnetID, chainID, address, validators, options...)
+	if err != nil {
+		return nil, err
+	}
+	return w.IssueUnsignedTx(utx, options...)
+}
+
+func (w *wallet) IssueRegisterSubnetValidatorTx(
+	balance uint64,
+	proofOfPossession [bls.SignatureLen]byte,
+	message []byte,
+	options ...common.Option,
+) (*txs.Tx, error) {
+	utx, err := w.builder.NewRegisterSubnetValidatorTx(balance, proofOfPossession, message, options...)
+	if err != nil {
+		return nil, err
+	}
+	return w.IssueUnsignedTx(utx, options...)
+}
+
+func (w *wallet) IssueSetSubnetValidatorWeightTx(
+	message []byte,
+	options ...common.Option,
+) (*txs.Tx, error) {
+	utx, err := w.builder.NewSetSubnetValidatorWeightTx(message, options...)
+	if err != nil {
+		return nil, err
+	}
+	return w.IssueUnsignedTx(utx, options...)
+}
+
+func (w *wallet) IssueIncreaseBalanceTx(
+	validationID ids.ID,
+	balance uint64,
+	options ...common.Option,
+) (*txs.Tx, error) {
+	utx, err := w.builder.NewIncreaseBalanceTx(validationID, balance, options...)
+	if err != nil {
+		return nil, err
+	}
+	return w.IssueUnsignedTx(utx, options...)
+}
+
 func (w *wallet) IssueImportTx(
 	sourceChainID ids.ID,
 	to *secp256k1fx.OutputOwners,
@@ -506,6 +624,19 @@ func (w *wallet) IssueTx(
 ) error {
 	ops := common.NewOptions(options)
 	ctx := ops.Context()
+	if !ops.SkipSimulation() {
+		simulatedTx, err := w.client.SimulateTx(ctx, tx.Bytes())
+		switch {
+		case isMethodNotFound(err):
+			// The node predates transaction simulation, so the tx is issued
+			// directly.
+		case err != nil:
+			return err
+		case !simulatedTx.Valid:
+			return fmt.Errorf("%w: %s", ErrSimulationFailed, simulatedTx.Error)
+		}
+	}
+
 	txID, err := w.client.IssueTx(ctx, tx.Bytes())
 	if err != nil {
 		return err
@@ -525,3 +656,10 @@ func (w *wallet) IssueTx(
 
 	return w.Backend.AcceptTx(ctx, tx)
 }
+
+// isMethodNotFound returns true if [err] was returned because the node doesn't
+// implement the requested API method.
+func isMethodNotFound(err error) bool {
+	var rpcErr *json2.Error
+	return errors.As(err, &rpcErr) && strings.HasPrefix(rpcErr.Message, methodNotFoundPrefix)
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gorilla/rpc/v2/json2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary/common"
+)
+
+var (
+	errMethodNotFound = fmt.Errorf("failed to decode client response: %w", &json2.Error{
+		Code:    json2.E_SERVER,
+		Message: `rpc: can't find method "platform.SimulateTx"`,
+	})
+	errTestSimulation = errors.New("test simulation error")
+)
+
+type testClient struct {
+	platformvm.Client
+
+	simulateTxReply *platformvm.SimulateTxReply
+	simulateTxErr   error
+	simulatedTxs    int
+	issuedTxs       int
+}
+
+func (c *testClient) SimulateTx(context.Context, []byte, ...rpc.Option) (*platformvm.SimulateTxReply, error) {
+	c.simulatedTxs++
+	return c.simulateTxReply, c.simulateTxErr
+}
+
+func (c *testClient) IssueTx(context.Context, []byte, ...rpc.Option) (ids.ID, error) {
+	c.issuedTxs++
+	return ids.GenerateTestID(), nil
+}
+
+type testBackend struct {
+	Backend
+
+	acceptedTxs int
+}
+
+func (b *testBackend) AcceptTx(context.Context, *txs.Tx) error {
+	b.acceptedTxs++
+	return nil
+}
+
+func TestIssueTxSimulation(t *testing.T) {
+	tests := []struct {
+		name                 string
+		simulateTxReply      *platformvm.SimulateTxReply
+		simulateTxErr        error
+		options              []common.Option
+		expectedErr          error
+		expectedSimulatedTxs int
+		expectedIssuedTxs    int
+		expectedAcceptedTxs  int
+	}{
+		{
+			name:                 "simulation not supported",
+			simulateTxErr:        errMethodNotFound,
+			expectedSimulatedTxs: 1,
+			expectedIssuedTxs:    1,
+			expectedAcceptedTxs:  1,
+		},
+		{
+			name:                 "simulation errored",
+			simulateTxErr:        errTestSimulation,
+			expectedErr:          errTestSimulation,
+			expectedSimulatedTxs: 1,
+		},
+		{
+			name: "simulation failed",
+			simulateTxReply: &platformvm.SimulateTxReply{
+				Error: "insufficient funds",
+			},
+			expectedErr:          ErrSimulationFailed,
+			expectedSimulatedTxs: 1,
+		},
+		{
+			name: "simulation succeeded",
+			simulateTxReply: &platformvm.SimulateTxReply{
+				Valid: true,
+			},
+			expectedSimulatedTxs: 1,
+			expectedIssuedTxs:    1,
+			expectedAcceptedTxs:  1,
+		},
+		{
+			name: "simulation skipped",
+			simulateTxReply: &platformvm.SimulateTxReply{
+				Error: "insufficient funds",
+			},
+			options:             []common.Option{common.WithSkipSimulation()},
+			expectedIssuedTxs:   1,
+			expectedAcceptedTxs: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			client := &testClient{
+				simulateTxReply: test.simulateTxReply,
+				simulateTxErr:   test.simulateTxErr,
+			}
+			backend := &testBackend{}
+			w := NewWallet(nil, nil, client, backend)
+
+			options := append(test.options, common.WithAssumeDecided())
+			err := w.IssueTx(&txs.Tx{Unsigned: &txs.BaseTx{}}, options...)
+			require.ErrorIs(err, test.expectedErr)
+			require.Equal(test.expectedSimulatedTxs, client.simulatedTxs)
+			require.Equal(test.expectedIssuedTxs, client.issuedTxs)
+			require.Equal(test.expectedAcceptedTxs, backend.acceptedTxs)
+		})
+	}
+}
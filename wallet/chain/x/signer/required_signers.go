@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/keychain"
+	"github.com/ava-labs/avalanchego/vms/avm/txs"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var ErrUnknownSigner = errors.New("unknown signer")
+
+// RequiredSigners returns, for each credential of [utx], the ID of the fx the
+// credential belongs to and the addresses that must sign [utx] in signature
+// index order.
+//
+// [backend] must know about all the UTXOs consumed by [utx].
+func RequiredSigners(
+	ctx context.Context,
+	backend Backend,
+	utx txs.UnsignedTx,
+) ([]ids.ID, [][]ids.ShortID, error) {
+	v := &visitor{
+		kc:      keychain.NewAddressKeychain(),
+		backend: backend,
+		ctx:     ctx,
+	}
+	if err := utx.Visit(v); err != nil {
+		return nil, nil, err
+	}
+
+	fxIDs := make([]ids.ID, len(v.txCreds))
+	for credIndex, cred := range v.txCreds {
+		switch cred.(type) {
+		case *secp256k1fx.Credential:
+			fxIDs[credIndex] = secp256k1fx.ID
+		case *nftfx.Credential:
+			fxIDs[credIndex] = nftfx.ID
+		case *propertyfx.Credential:
+			fxIDs[credIndex] = propertyfx.ID
+		default:
+			return nil, nil, ErrUnknownCredentialType
+		}
+	}
+
+	signers := make([][]ids.ShortID, len(v.txSigners))
+	for credIndex, inputSigners := range v.txSigners {
+		signers[credIndex] = make([]ids.ShortID, len(inputSigners))
+		for sigIndex, signer := range inputSigners {
+			if signer == nil {
+				return nil, nil, fmt.Errorf("%w of credential %d at index %d",
+					ErrUnknownSigner,
+					credIndex,
+					sigIndex,
+				)
+			}
+			signers[credIndex][sigIndex] = signer.Address()
+		}
+	}
+	return fxIDs, signers, nil
+}
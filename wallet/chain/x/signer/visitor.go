@@ -35,12 +35,16 @@ var (
 	emptySig [secp256k1.SignatureLen]byte
 )
 
-// visitor handles signing transactions for the signer
+// visitor finds the signers of transactions for the signer
 type visitor struct {
+	// inputs
 	kc      keychain.Keychain
 	backend Backend
 	ctx     context.Context
-	tx      *txs.Tx
+
+	// outputs
+	txCreds   []verify.Verifiable
+	txSigners [][]keychain.Signer
 }
 
 func (s *visitor) BaseTx(tx *txs.BaseTx) error {
@@ -48,7 +52,7 @@ func (s *visitor) BaseTx(tx *txs.BaseTx) error {
 	if err != nil {
 		return err
 	}
-	return sign(s.tx, txCreds, txSigners)
+	return s.setSigners(txCreds, txSigners)
 }
 
 func (s *visitor) CreateAssetTx(tx *txs.CreateAssetTx) error {
@@ -56,7 +60,7 @@ func (s *visitor) CreateAssetTx(tx *txs.CreateAssetTx) error {
 	if err != nil {
 		return err
 	}
-	return sign(s.tx, txCreds, txSigners)
+	return s.setSigners(txCreds, txSigners)
 }
 
 func (s *visitor) OperationTx(tx *txs.OperationTx) error {
@@ -70,7 +74,7 @@ func (s *visitor) OperationTx(tx *txs.OperationTx) error {
 	}
 	txCreds = append(txCreds, txOpsCreds...)
 	txSigners = append(txSigners, txOpsSigners...)
-	return sign(s.tx, txCreds, txSigners)
+	return s.setSigners(txCreds, txSigners)
 }
 
 func (s *visitor) ImportTx(tx *txs.ImportTx) error {
@@ -84,7 +88,7 @@ func (s *visitor) ImportTx(tx *txs.ImportTx) error {
 	}
 	txCreds = append(txCreds, txImportCreds...)
 	txSigners = append(txSigners, txImportSigners...)
-	return sign(s.tx, txCreds, txSigners)
+	return s.setSigners(txCreds, txSigners)
 }
 
 func (s *visitor) ExportTx(tx *txs.ExportTx) error {
@@ -92,7 +96,13 @@ func (s *visitor) ExportTx(tx *txs.ExportTx) error {
 	if err != nil {
 		return err
 	}
-	return sign(s.tx, txCreds, txSigners)
+	return s.setSigners(txCreds, txSigners)
+}
+
+func (s *visitor) setSigners(txCreds []verify.Verifiable, txSigners [][]keychain.Signer) error {
+	s.txCreds = txCreds
+	s.txSigners = txSigners
+	return nil
 }
 
 func (s *visitor) getSigners(ctx context.Context, sourceChainID ids.ID, ins []*avax.TransferableInput) ([]verify.Verifiable, [][]keychain.Signer, error) {
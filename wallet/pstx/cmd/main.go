@@ -0,0 +1,308 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/pstx"
+)
+
+const (
+	KeyFileKey = "key-file"
+	OutputKey  = "output"
+)
+
+var errNoKeys = errors.New("key file doesn't contain any keys")
+
+func main() {
+	cmd := &cobra.Command{
+		Use:   "pstx",
+		Short: "Signs, merges and finalizes partially-signed transactions offline",
+	}
+	cmd.AddCommand(
+		inspectCommand(),
+		signCommand(),
+		mergeCommand(),
+		statusCommand(),
+		finalizeCommand(),
+	)
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "command failed %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func inspectCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "inspect <file>",
+		Short: "Prints the decoded unsigned transaction of a partially-signed transaction",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			tx, err := readTx(args[0])
+			if err != nil {
+				return err
+			}
+			return printSummary(tx)
+		},
+	}
+}
+
+func signCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "sign <file>",
+		Short: "Adds the signatures of the keys in a key file to a partially-signed transaction",
+		Args:  cobra.ExactArgs(1),
+		RunE:  signFunc,
+	}
+	flags := c.Flags()
+	flags.String(KeyFileKey, "", "File with one PrivateKey-... per line")
+	flags.String(OutputKey, "", "File to write the partially-signed transaction to. If empty, the input file is overwritten")
+	return c
+}
+
+func signFunc(c *cobra.Command, args []string) error {
+	flags := c.Flags()
+	keyFile, err := flags.GetString(KeyFileKey)
+	if err != nil {
+		return err
+	}
+	output, err := flags.GetString(OutputKey)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = args[0]
+	}
+
+	keys, err := readKeys(keyFile)
+	if err != nil {
+		return err
+	}
+	tx, err := readTx(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Print the transaction being signed so that the signer can review it.
+	if err := printSummary(tx); err != nil {
+		return err
+	}
+
+	numAdded, err := tx.Sign(secp256k1fx.NewKeychain(keys...))
+	if err != nil {
+		return err
+	}
+	if err := writeTx(output, tx); err != nil {
+		return err
+	}
+	fmt.Printf("added %d signatures\n", numAdded)
+	return printStatus(tx)
+}
+
+func mergeCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "merge <file>...",
+		Short: "Merges the signatures of partially-signed transactions of the same transaction",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  mergeFunc,
+	}
+	flags := c.Flags()
+	flags.String(OutputKey, "", "File to write the merged partially-signed transaction to")
+	return c
+}
+
+func mergeFunc(c *cobra.Command, args []string) error {
+	output, err := c.Flags().GetString(OutputKey)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		return fmt.Errorf("--%s must be provided", OutputKey)
+	}
+
+	txs := make([]*pstx.Tx, len(args))
+	for i, file := range args {
+		txs[i], err = readTx(file)
+		if err != nil {
+			return err
+		}
+	}
+
+	merged, err := pstx.Merge(txs...)
+	if err != nil {
+		return err
+	}
+	if err := writeTx(output, merged); err != nil {
+		return err
+	}
+	return printStatus(merged)
+}
+
+func statusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status <file>",
+		Short: "Prints the signers whose signatures are missing from a partially-signed transaction",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			tx, err := readTx(args[0])
+			if err != nil {
+				return err
+			}
+			return printStatus(tx)
+		},
+	}
+}
+
+func finalizeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "finalize <file>",
+		Short: "Prints the signed transaction of a complete partially-signed transaction, ready to be issued",
+		Args:  cobra.ExactArgs(1),
+		RunE:  finalizeFunc,
+	}
+}
+
+func finalizeFunc(_ *cobra.Command, args []string) error {
+	tx, err := readTx(args[0])
+	if err != nil {
+		return err
+	}
+
+	var (
+		txID    ids.ID
+		txBytes []byte
+	)
+	switch tx.Chain {
+	case pstx.PChain:
+		signedTx, err := tx.PChainTx()
+		if err != nil {
+			return err
+		}
+		txID, txBytes = signedTx.ID(), signedTx.Bytes()
+	case pstx.XChain:
+		signedTx, err := tx.XChainTx()
+		if err != nil {
+			return err
+		}
+		txID, txBytes = signedTx.ID(), signedTx.Bytes()
+	default:
+		return fmt.Errorf("%w: %q", pstx.ErrUnknownChain, tx.Chain)
+	}
+
+	txStr, err := formatting.Encode(formatting.Hex, txBytes)
+	if err != nil {
+		return err
+	}
+	return printJSON(struct {
+		Chain    pstx.Chain          `json:"chain"`
+		TxID     ids.ID              `json:"txID"`
+		Tx       string              `json:"tx"`
+		Encoding formatting.Encoding `json:"encoding"`
+	}{
+		Chain:    tx.Chain,
+		TxID:     txID,
+		Tx:       txStr,
+		Encoding: formatting.Hex,
+	})
+}
+
+func printSummary(tx *pstx.Tx) error {
+	summary, err := tx.Summary()
+	if err != nil {
+		return err
+	}
+	return printJSON(summary)
+}
+
+func printStatus(tx *pstx.Tx) error {
+	collected, required := tx.NumSignatures()
+	return printJSON(struct {
+		Chain              pstx.Chain    `json:"chain"`
+		Complete           bool          `json:"complete"`
+		NumSignatures      int           `json:"numSignatures"`
+		RequiredSignatures int           `json:"requiredSignatures"`
+		MissingSigners     []ids.ShortID `json:"missingSigners"`
+	}{
+		Chain:              tx.Chain,
+		Complete:           collected == required,
+		NumSignatures:      collected,
+		RequiredSignatures: required,
+		MissingSigners:     tx.Missing(),
+	})
+}
+
+func readKeys(file string) ([]*secp256k1.PrivateKey, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		keys    []*secp256k1.PrivateKey
+		scanner = bufio.NewScanner(f)
+	)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		key := new(secp256k1.PrivateKey)
+		if err := key.UnmarshalJSON([]byte(strconv.Quote(line))); err != nil {
+			return nil, fmt.Errorf("couldn't parse key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errNoKeys
+	}
+	return keys, nil
+}
+
+func readTx(file string) (*pstx.Tx, error) {
+	txBytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	tx := &pstx.Tx{}
+	if err := json.Unmarshal(txBytes, tx); err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", file, err)
+	}
+	return tx, tx.Verify()
+}
+
+func writeTx(file string, tx *pstx.Tx) error {
+	txBytes, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return perms.WriteFile(file, txBytes, perms.ReadWrite)
+}
+
+func printJSON(v interface{}) error {
+	bytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(string(bytes))
+	return err
+}
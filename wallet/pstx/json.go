@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pstx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+)
+
+var (
+	_ json.Marshaler   = (*Tx)(nil)
+	_ json.Unmarshaler = (*Tx)(nil)
+
+	errWrongSignatureLen = errors.New("wrong signature length")
+)
+
+// jsonTx is the portable representation of a [Tx]. The unsigned tx is hex
+// encoded with a checksum. Signatures are hex encoded and missing signatures
+// are empty strings.
+type jsonTx struct {
+	Chain       Chain            `json:"chain"`
+	UnsignedTx  string           `json:"unsignedTx"`
+	SignHash    bool             `json:"signHash"`
+	Credentials []jsonCredential `json:"credentials"`
+}
+
+type jsonCredential struct {
+	FxID       ids.ID        `json:"fxID"`
+	Signers    []ids.ShortID `json:"signers"`
+	Signatures []string      `json:"signatures"`
+}
+
+func (t *Tx) MarshalJSON() ([]byte, error) {
+	unsignedTx, err := formatting.Encode(formatting.Hex, t.Unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't encode unsigned tx: %w", err)
+	}
+
+	j := jsonTx{
+		Chain:       t.Chain,
+		UnsignedTx:  unsignedTx,
+		SignHash:    t.SignHash,
+		Credentials: make([]jsonCredential, len(t.Credentials)),
+	}
+	for i, cred := range t.Credentials {
+		signatures := make([]string, len(cred.Sigs))
+		for sigIndex, sig := range cred.Sigs {
+			if sig == emptySig {
+				continue
+			}
+			signatures[sigIndex], err = formatting.Encode(formatting.HexNC, sig[:])
+			if err != nil {
+				return nil, fmt.Errorf("couldn't encode signature: %w", err)
+			}
+		}
+		j.Credentials[i] = jsonCredential{
+			FxID:       cred.FxID,
+			Signers:    cred.Signers,
+			Signatures: signatures,
+		}
+	}
+	return json.Marshal(j)
+}
+
+func (t *Tx) UnmarshalJSON(b []byte) error {
+	var j jsonTx
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+
+	unsigned, err := formatting.Decode(formatting.Hex, j.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("couldn't decode unsigned tx: %w", err)
+	}
+
+	credentials := make([]*Credential, len(j.Credentials))
+	for i, jCred := range j.Credentials {
+		cred := &Credential{
+			FxID:    jCred.FxID,
+			Signers: jCred.Signers,
+			Sigs:    make([][secp256k1.SignatureLen]byte, len(jCred.Signatures)),
+		}
+		for sigIndex, signature := range jCred.Signatures {
+			if signature == "" {
+				continue
+			}
+			sig, err := formatting.Decode(formatting.HexNC, signature)
+			if err != nil {
+				return fmt.Errorf("couldn't decode signature: %w", err)
+			}
+			if len(sig) != secp256k1.SignatureLen {
+				return fmt.Errorf("%w: expected %d bytes but got %d",
+					errWrongSignatureLen,
+					secp256k1.SignatureLen,
+					len(sig),
+				)
+			}
+			copy(cred.Sigs[sigIndex][:], sig)
+		}
+		credentials[i] = cred
+	}
+
+	*t = Tx{
+		Chain:       j.Chain,
+		Unsigned:    unsigned,
+		SignHash:    j.SignHash,
+		Credentials: credentials,
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pstx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/chain/p/signer"
+)
+
+// NewPChainTx returns a partially-signed tx without any signatures for the
+// P-chain tx [utx].
+//
+// [backend] must know about all the UTXOs consumed by [utx] and the owners of
+// all the subnets it modifies.
+func NewPChainTx(ctx context.Context, backend signer.Backend, utx txs.UnsignedTx) (*Tx, error) {
+	signers, signHash, err := signer.RequiredSigners(ctx, backend, utx)
+	if err != nil {
+		return nil, err
+	}
+	unsignedBytes, err := txs.Codec.Marshal(txs.CodecVersion, &utx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal unsigned tx: %w", err)
+	}
+
+	tx := &Tx{
+		Chain:       PChain,
+		Unsigned:    unsignedBytes,
+		SignHash:    signHash,
+		Credentials: make([]*Credential, len(signers)),
+	}
+	for i, credSigners := range signers {
+		tx.Credentials[i] = newCredential(secp256k1fx.ID, credSigners)
+	}
+	return tx, nil
+}
+
+// PChainTx returns the signed P-chain tx. All the required signatures must
+// have been collected.
+func (t *Tx) PChainTx() (*txs.Tx, error) {
+	if t.Chain != PChain {
+		return nil, fmt.Errorf("%w: expected %s but got %s", ErrWrongChain, PChain, t.Chain)
+	}
+	sigs, err := t.sigs()
+	if err != nil {
+		return nil, err
+	}
+
+	var utx txs.UnsignedTx
+	if _, err := txs.Codec.Unmarshal(t.Unsigned, &utx); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal unsigned tx: %w", err)
+	}
+
+	tx := &txs.Tx{
+		Unsigned: utx,
+		Creds:    make([]verify.Verifiable, len(sigs)),
+	}
+	for i, credSigs := range sigs {
+		tx.Creds[i] = &secp256k1fx.Credential{
+			Sigs: credSigs,
+		}
+	}
+	return tx, tx.Initialize(txs.Codec)
+}
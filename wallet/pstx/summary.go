@@ -0,0 +1,281 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pstx
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/message"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ava-labs/avalanchego/wallet/chain/x/builder"
+
+	avmtxs "github.com/ava-labs/avalanchego/vms/avm/txs"
+	platformtxs "github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+var (
+	_ platformtxs.Visitor = (*pChainSummarizer)(nil)
+	_ avmtxs.Visitor      = (*xChainSummarizer)(nil)
+)
+
+// Summary describes the unsigned tx of a partially-signed tx so that signers
+// can review what they are signing.
+type Summary struct {
+	Chain Chain `json:"chain"`
+	// TxID is the ID of the signed tx. The ID depends on the signatures, so it
+	// is only known once all the signatures have been collected.
+	TxID *ids.ID `json:"txID,omitempty"`
+	// UnsignedTxHash is the hash of the unsigned tx, which is what the signers
+	// sign.
+	UnsignedTxHash ids.ID `json:"unsignedTxHash"`
+	// Type is the type of the unsigned tx, such as "CreateChainTx"
+	Type string `json:"type"`
+	// SubnetID is the subnet the tx modifies, if any
+	SubnetID *ids.ID `json:"subnetID,omitempty"`
+	// Inputs consumed by the tx, including the inputs imported from other
+	// chains
+	Inputs []*avax.TransferableInput `json:"inputs"`
+	// Outputs produced by the tx, including the outputs exported to other
+	// chains and the staked outputs
+	Outputs []*avax.TransferableOutput `json:"outputs"`
+	// UnsignedTx is the decoded unsigned tx
+	UnsignedTx any `json:"unsignedTx"`
+}
+
+// Summary decodes the unsigned tx with the codec of its chain and returns its
+// summary.
+func (t *Tx) Summary() (*Summary, error) {
+	summary := &Summary{
+		Chain:          t.Chain,
+		UnsignedTxHash: hashing.ComputeHash256Array(t.Unsigned),
+		Inputs:         []*avax.TransferableInput{},
+		Outputs:        []*avax.TransferableOutput{},
+	}
+
+	switch t.Chain {
+	case PChain:
+		var utx platformtxs.UnsignedTx
+		if _, err := platformtxs.Codec.Unmarshal(t.Unsigned, &utx); err != nil {
+			return nil, fmt.Errorf("couldn't unmarshal unsigned tx: %w", err)
+		}
+		if err := utx.Visit(&pChainSummarizer{summary: summary}); err != nil {
+			return nil, err
+		}
+		summary.Type = typeName(utx)
+		summary.UnsignedTx = utx
+		if t.Complete() {
+			tx, err := t.PChainTx()
+			if err != nil {
+				return nil, err
+			}
+			txID := tx.ID()
+			summary.TxID = &txID
+		}
+	case XChain:
+		var utx avmtxs.UnsignedTx
+		if _, err := builder.Parser.Codec().Unmarshal(t.Unsigned, &utx); err != nil {
+			return nil, fmt.Errorf("couldn't unmarshal unsigned tx: %w", err)
+		}
+		if err := utx.Visit(&xChainSummarizer{summary: summary}); err != nil {
+			return nil, err
+		}
+		summary.Type = typeName(utx)
+		summary.UnsignedTx = utx
+		if t.Complete() {
+			tx, err := t.XChainTx()
+			if err != nil {
+				return nil, err
+			}
+			txID := tx.ID()
+			summary.TxID = &txID
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownChain, t.Chain)
+	}
+	return summary, nil
+}
+
+func typeName(utx any) string {
+	return reflect.Indirect(reflect.ValueOf(utx)).Type().Name()
+}
+
+// pChainSummarizer adds the inputs, outputs and subnet of a P-chain tx to its
+// summary.
+type pChainSummarizer struct {
+	summary *Summary
+}
+
+func (s *pChainSummarizer) AddValidatorTx(tx *platformtxs.AddValidatorTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.summary.Outputs = append(s.summary.Outputs, tx.StakeOuts...)
+	return nil
+}
+
+func (s *pChainSummarizer) AddSubnetValidatorTx(tx *platformtxs.AddSubnetValidatorTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.setSubnetID(tx.SubnetValidator.Subnet)
+	return nil
+}
+
+func (s *pChainSummarizer) AddDelegatorTx(tx *platformtxs.AddDelegatorTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.summary.Outputs = append(s.summary.Outputs, tx.StakeOuts...)
+	return nil
+}
+
+func (s *pChainSummarizer) CreateChainTx(tx *platformtxs.CreateChainTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.setSubnetID(tx.SubnetID)
+	return nil
+}
+
+func (s *pChainSummarizer) CreateSubnetTx(tx *platformtxs.CreateSubnetTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	return nil
+}
+
+func (s *pChainSummarizer) ImportTx(tx *platformtxs.ImportTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.summary.Inputs = append(s.summary.Inputs, tx.ImportedInputs...)
+	return nil
+}
+
+func (s *pChainSummarizer) ExportTx(tx *platformtxs.ExportTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.summary.Outputs = append(s.summary.Outputs, tx.ExportedOutputs...)
+	return nil
+}
+
+func (*pChainSummarizer) AdvanceTimeTx(*platformtxs.AdvanceTimeTx) error {
+	return nil
+}
+
+func (*pChainSummarizer) RewardValidatorTx(*platformtxs.RewardValidatorTx) error {
+	return nil
+}
+
+func (s *pChainSummarizer) RemoveSubnetValidatorTx(tx *platformtxs.RemoveSubnetValidatorTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.setSubnetID(tx.Subnet)
+	return nil
+}
+
+func (s *pChainSummarizer) TransformSubnetTx(tx *platformtxs.TransformSubnetTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.setSubnetID(tx.Subnet)
+	return nil
+}
+
+func (s *pChainSummarizer) AddPermissionlessValidatorTx(tx *platformtxs.AddPermissionlessValidatorTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.setSubnetID(tx.Subnet)
+	s.summary.Outputs = append(s.summary.Outputs, tx.StakeOuts...)
+	return nil
+}
+
+func (s *pChainSummarizer) AddPermissionlessDelegatorTx(tx *platformtxs.AddPermissionlessDelegatorTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.setSubnetID(tx.Subnet)
+	s.summary.Outputs = append(s.summary.Outputs, tx.StakeOuts...)
+	return nil
+}
+
+func (s *pChainSummarizer) TransferSubnetOwnershipTx(tx *platformtxs.TransferSubnetOwnershipTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.setSubnetID(tx.Subnet)
+	return nil
+}
+
+func (s *pChainSummarizer) BaseTx(tx *platformtxs.BaseTx) error {
+	s.addBaseTx(tx)
+	return nil
+}
+
+func (s *pChainSummarizer) ConvertSubnetTx(tx *platformtxs.ConvertSubnetTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.setSubnetID(tx.Subnet)
+	return nil
+}
+
+func (s *pChainSummarizer) RegisterSubnetValidatorTx(tx *platformtxs.RegisterSubnetValidatorTx) error {
+	s.addBaseTx(&tx.BaseTx)
+
+	// The subnet is only specified by the warp message.
+	warpMessage, err := warp.ParseMessage(tx.Message)
+	if err != nil {
+		return fmt.Errorf("couldn't parse warp message: %w", err)
+	}
+	addressedCall, err := payload.ParseAddressedCall(warpMessage.Payload)
+	if err != nil {
+		return fmt.Errorf("couldn't parse warp payload: %w", err)
+	}
+	msg, err := message.ParseRegisterSubnetValidator(addressedCall.Payload)
+	if err != nil {
+		return fmt.Errorf("couldn't parse register subnet validator message: %w", err)
+	}
+	s.setSubnetID(msg.SubnetID)
+	return nil
+}
+
+func (s *pChainSummarizer) SetSubnetValidatorWeightTx(tx *platformtxs.SetSubnetValidatorWeightTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	return nil
+}
+
+func (s *pChainSummarizer) IncreaseBalanceTx(tx *platformtxs.IncreaseBalanceTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	return nil
+}
+
+func (s *pChainSummarizer) addBaseTx(tx *platformtxs.BaseTx) {
+	s.summary.Inputs = append(s.summary.Inputs, tx.Ins...)
+	s.summary.Outputs = append(s.summary.Outputs, tx.Outs...)
+}
+
+func (s *pChainSummarizer) setSubnetID(subnetID ids.ID) {
+	s.summary.SubnetID = &subnetID
+}
+
+// xChainSummarizer adds the inputs and outputs of an X-chain tx to its
+// summary.
+type xChainSummarizer struct {
+	summary *Summary
+}
+
+func (s *xChainSummarizer) BaseTx(tx *avmtxs.BaseTx) error {
+	s.addBaseTx(tx)
+	return nil
+}
+
+func (s *xChainSummarizer) CreateAssetTx(tx *avmtxs.CreateAssetTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	return nil
+}
+
+func (s *xChainSummarizer) OperationTx(tx *avmtxs.OperationTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	return nil
+}
+
+func (s *xChainSummarizer) ImportTx(tx *avmtxs.ImportTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.summary.Inputs = append(s.summary.Inputs, tx.ImportedIns...)
+	return nil
+}
+
+func (s *xChainSummarizer) ExportTx(tx *avmtxs.ExportTx) error {
+	s.addBaseTx(&tx.BaseTx)
+	s.summary.Outputs = append(s.summary.Outputs, tx.ExportedOuts...)
+	return nil
+}
+
+func (s *xChainSummarizer) addBaseTx(tx *avmtxs.BaseTx) {
+	s.summary.Inputs = append(s.summary.Inputs, tx.Ins...)
+	s.summary.Outputs = append(s.summary.Outputs, tx.Outs...)
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pstx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	ptxs "github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+func TestPChainTxSummary(t *testing.T) {
+	require := require.New(t)
+
+	keys := newKeys(t, 4)
+	tx := newPChainTx(t, keys)
+
+	summary, err := tx.Summary()
+	require.NoError(err)
+	require.Equal(PChain, summary.Chain)
+	require.Nil(summary.TxID)
+	require.Equal(ids.ID(hashing.ComputeHash256Array(tx.Unsigned)), summary.UnsignedTxHash)
+	require.Equal("CreateChainTx", summary.Type)
+	require.IsType(&ptxs.CreateChainTx{}, summary.UnsignedTx)
+
+	utx := summary.UnsignedTx.(*ptxs.CreateChainTx)
+	require.NotNil(summary.SubnetID)
+	require.Equal(utx.SubnetID, *summary.SubnetID)
+	require.Equal(utx.Ins, summary.Inputs)
+	require.Empty(summary.Outputs)
+
+	_, err = json.Marshal(summary)
+	require.NoError(err)
+
+	// The tx ID is known once all the signatures have been collected.
+	_, err = tx.Sign(secp256k1fx.NewKeychain(keys...))
+	require.NoError(err)
+	require.True(tx.Complete())
+
+	summary, err = tx.Summary()
+	require.NoError(err)
+	signedTx, err := tx.PChainTx()
+	require.NoError(err)
+	require.NotNil(summary.TxID)
+	require.Equal(signedTx.ID(), *summary.TxID)
+}
+
+func TestSummaryInvalidUnsignedTx(t *testing.T) {
+	require := require.New(t)
+
+	tx := newPChainTx(t, newKeys(t, 4))
+	tx.Unsigned = tx.Unsigned[:len(tx.Unsigned)-1]
+
+	_, err := tx.Summary()
+	require.ErrorIs(err, wrappers.ErrInsufficientLength)
+}
@@ -0,0 +1,308 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package pstx implements partially-signed transactions, which allow the
+// owners of multisig UTXOs and subnets to sign transactions from different
+// machines.
+//
+// A partially-signed transaction is created by a party that knows the UTXOs
+// and subnet owners referenced by the transaction. It can then be signed
+// offline by every signer, merged, and finalized into a transaction that can be
+// issued once enough signatures have been collected.
+package pstx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/keychain"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+const (
+	PChain Chain = "P"
+	XChain Chain = "X"
+)
+
+var (
+	ErrUnknownChain          = errors.New("unknown chain")
+	ErrWrongChain            = errors.New("wrong chain")
+	ErrMismatchedTxs         = errors.New("partially-signed txs don't sign the same tx")
+	ErrConflictingSignatures = errors.New("conflicting signatures")
+	ErrInvalidSignature      = errors.New("invalid signature")
+	ErrIncomplete            = errors.New("tx is missing signatures")
+	ErrNoTxs                 = errors.New("no txs to merge")
+
+	errWrongNumSignatures = errors.New("wrong number of signatures")
+
+	emptySig [secp256k1.SignatureLen]byte
+)
+
+// Chain is the alias of the chain a partially-signed tx is issued on.
+type Chain string
+
+func (c Chain) Verify() error {
+	switch c {
+	case PChain, XChain:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownChain, c)
+	}
+}
+
+// Tx is an unsigned tx together with the signatures that have been collected
+// for it so far.
+type Tx struct {
+	Chain Chain
+	// Unsigned is the serialized unsigned tx
+	Unsigned []byte
+	// SignHash is true if signers must sign the hash of [Unsigned] rather than
+	// [Unsigned]. Both produce the same signature, but some hardware wallets
+	// only support signing hashes of some txs.
+	SignHash bool
+	// Credentials of the tx, in the order they are expected by the chain
+	Credentials []*Credential
+}
+
+// Credential lists the signatures required to spend an input of a tx.
+type Credential struct {
+	// FxID is the ID of the fx this credential belongs to
+	FxID ids.ID
+	// Signers are the addresses that must sign the tx, in signature index
+	// order
+	Signers []ids.ShortID
+	// Sigs are the signatures of the [Signers]. Missing signatures are empty.
+	Sigs [][secp256k1.SignatureLen]byte
+}
+
+func newCredential(fxID ids.ID, signers []ids.ShortID) *Credential {
+	return &Credential{
+		FxID:    fxID,
+		Signers: signers,
+		Sigs:    make([][secp256k1.SignatureLen]byte, len(signers)),
+	}
+}
+
+// Verify checks that the tx is well-formed and that every signature that has
+// been collected was produced by its signer.
+func (t *Tx) Verify() error {
+	if err := t.Chain.Verify(); err != nil {
+		return err
+	}
+
+	hash := hashing.ComputeHash256(t.Unsigned)
+	for credIndex, cred := range t.Credentials {
+		if len(cred.Sigs) != len(cred.Signers) {
+			return fmt.Errorf("%w of credential %d: expected %d but got %d",
+				errWrongNumSignatures,
+				credIndex,
+				len(cred.Signers),
+				len(cred.Sigs),
+			)
+		}
+		for sigIndex, sig := range cred.Sigs {
+			if sig == emptySig {
+				continue
+			}
+			pk, err := secp256k1.RecoverPublicKeyFromHash(hash, sig[:])
+			if err != nil {
+				return fmt.Errorf("%w of credential %d at index %d: %w",
+					ErrInvalidSignature,
+					credIndex,
+					sigIndex,
+					err,
+				)
+			}
+			if signer := cred.Signers[sigIndex]; pk.Address() != signer {
+				return fmt.Errorf("%w of credential %d at index %d: expected signer %s but got %s",
+					ErrInvalidSignature,
+					credIndex,
+					sigIndex,
+					signer,
+					pk.Address(),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// Sign adds the missing signatures that can be produced by [kc] and returns
+// the number of signatures that were added.
+func (t *Tx) Sign(kc keychain.Keychain) (int, error) {
+	if err := t.Verify(); err != nil {
+		return 0, err
+	}
+
+	var (
+		hash     = hashing.ComputeHash256(t.Unsigned)
+		sigCache = make(map[ids.ShortID][secp256k1.SignatureLen]byte)
+		numAdded int
+	)
+	for _, cred := range t.Credentials {
+		for sigIndex, addr := range cred.Signers {
+			if cred.Sigs[sigIndex] != emptySig {
+				continue
+			}
+
+			if sig, ok := sigCache[addr]; ok {
+				cred.Sigs[sigIndex] = sig
+				numAdded++
+				continue
+			}
+
+			signer, ok := kc.Get(addr)
+			if !ok {
+				continue
+			}
+
+			var (
+				sig []byte
+				err error
+			)
+			if t.SignHash {
+				sig, err = signer.SignHash(hash)
+			} else {
+				sig, err = signer.Sign(t.Unsigned)
+			}
+			if err != nil {
+				return numAdded, fmt.Errorf("problem signing tx: %w", err)
+			}
+			copy(cred.Sigs[sigIndex][:], sig)
+			sigCache[addr] = cred.Sigs[sigIndex]
+			numAdded++
+		}
+	}
+	return numAdded, nil
+}
+
+// NumSignatures returns the number of signatures that have been collected
+// and the number of signatures that are required.
+func (t *Tx) NumSignatures() (int, int) {
+	var collected, required int
+	for _, cred := range t.Credentials {
+		for _, sig := range cred.Sigs {
+			if sig != emptySig {
+				collected++
+			}
+		}
+		required += len(cred.Sigs)
+	}
+	return collected, required
+}
+
+// Missing returns the addresses whose signatures are still missing, in the
+// order they first appear in the credentials.
+func (t *Tx) Missing() []ids.ShortID {
+	var (
+		missing []ids.ShortID
+		seen    set.Set[ids.ShortID]
+	)
+	for _, cred := range t.Credentials {
+		for sigIndex, sig := range cred.Sigs {
+			addr := cred.Signers[sigIndex]
+			if sig != emptySig || seen.Contains(addr) {
+				continue
+			}
+			seen.Add(addr)
+			missing = append(missing, addr)
+		}
+	}
+	return missing
+}
+
+// Complete returns true if all the required signatures have been collected.
+func (t *Tx) Complete() bool {
+	collected, required := t.NumSignatures()
+	return collected == required
+}
+
+// Merge returns a tx with the signatures collected by all of [txs]. The
+// provided txs aren't modified.
+func Merge(txs ...*Tx) (*Tx, error) {
+	if len(txs) == 0 {
+		return nil, ErrNoTxs
+	}
+
+	merged := txs[0].clone()
+	for i, tx := range txs[1:] {
+		if !merged.signsSameTx(tx) {
+			return nil, fmt.Errorf("%w: tx %d differs from tx 0", ErrMismatchedTxs, i+1)
+		}
+
+		for credIndex, cred := range tx.Credentials {
+			mergedCred := merged.Credentials[credIndex]
+			for sigIndex, sig := range cred.Sigs {
+				switch mergedSig := mergedCred.Sigs[sigIndex]; {
+				case sig == emptySig:
+				case mergedSig == emptySig:
+					mergedCred.Sigs[sigIndex] = sig
+				case mergedSig != sig:
+					return nil, fmt.Errorf("%w of credential %d at index %d",
+						ErrConflictingSignatures,
+						credIndex,
+						sigIndex,
+					)
+				}
+			}
+		}
+	}
+	return merged, merged.Verify()
+}
+
+func (t *Tx) signsSameTx(o *Tx) bool {
+	if t.Chain != o.Chain ||
+		!bytes.Equal(t.Unsigned, o.Unsigned) ||
+		t.SignHash != o.SignHash ||
+		len(t.Credentials) != len(o.Credentials) {
+		return false
+	}
+	for i, cred := range t.Credentials {
+		oCred := o.Credentials[i]
+		if cred.FxID != oCred.FxID ||
+			!slices.Equal(cred.Signers, oCred.Signers) ||
+			len(cred.Sigs) != len(oCred.Sigs) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Tx) clone() *Tx {
+	clone := &Tx{
+		Chain:       t.Chain,
+		Unsigned:    slices.Clone(t.Unsigned),
+		SignHash:    t.SignHash,
+		Credentials: make([]*Credential, len(t.Credentials)),
+	}
+	for i, cred := range t.Credentials {
+		clone.Credentials[i] = &Credential{
+			FxID:    cred.FxID,
+			Signers: slices.Clone(cred.Signers),
+			Sigs:    slices.Clone(cred.Sigs),
+		}
+	}
+	return clone
+}
+
+// sigs returns the signatures of the credentials, failing if any signature is
+// missing.
+func (t *Tx) sigs() ([][][secp256k1.SignatureLen]byte, error) {
+	if err := t.Verify(); err != nil {
+		return nil, err
+	}
+	if missing := t.Missing(); len(missing) != 0 {
+		return nil, fmt.Errorf("%w from %d signers", ErrIncomplete, len(missing))
+	}
+
+	sigs := make([][][secp256k1.SignatureLen]byte, len(t.Credentials))
+	for i, cred := range t.Credentials {
+		sigs[i] = slices.Clone(cred.Sigs)
+	}
+	return sigs, nil
+}
@@ -0,0 +1,340 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pstx
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/fx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	avmtxs "github.com/ava-labs/avalanchego/vms/avm/txs"
+	ptxs "github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	psigner "github.com/ava-labs/avalanchego/wallet/chain/p/signer"
+)
+
+type backend struct {
+	utxos  map[ids.ID]*avax.UTXO
+	owners map[ids.ID]fx.Owner
+}
+
+func (b *backend) GetUTXO(_ context.Context, _, utxoID ids.ID) (*avax.UTXO, error) {
+	utxo, ok := b.utxos[utxoID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return utxo, nil
+}
+
+func (b *backend) GetSubnetOwner(_ context.Context, subnetID ids.ID) (fx.Owner, error) {
+	owner, ok := b.owners[subnetID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return owner, nil
+}
+
+func newKeys(t *testing.T, n int) []*secp256k1.PrivateKey {
+	keys := make([]*secp256k1.PrivateKey, n)
+	for i := range keys {
+		key, err := secp256k1.NewPrivateKey()
+		require.NoError(t, err)
+		keys[i] = key
+	}
+	return keys
+}
+
+func addrs(keys ...*secp256k1.PrivateKey) []ids.ShortID {
+	addrs := make([]ids.ShortID, len(keys))
+	for i, key := range keys {
+		addrs[i] = key.Address()
+	}
+	return addrs
+}
+
+// newPChainTx returns a partially-signed CreateChainTx that spends a 2-of-3
+// UTXO owned by [keys] 0, 1 and 2 and is authorized by a 2-of-2 subnet owner
+// made of [keys] 2 and 3.
+func newPChainTx(t *testing.T, keys []*secp256k1.PrivateKey) *Tx {
+	require := require.New(t)
+
+	var (
+		avaxAssetID = ids.GenerateTestID()
+		subnetID    = ids.GenerateTestID()
+		utxo        = &avax.UTXO{
+			UTXOID: avax.UTXOID{
+				TxID: ids.GenerateTestID(),
+			},
+			Asset: avax.Asset{ID: avaxAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 1_000,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 2,
+					Addrs:     addrs(keys[0], keys[1], keys[2]),
+				},
+			},
+		}
+		b = &backend{
+			utxos: map[ids.ID]*avax.UTXO{
+				utxo.InputID(): utxo,
+			},
+			owners: map[ids.ID]fx.Owner{
+				subnetID: &secp256k1fx.OutputOwners{
+					Threshold: 2,
+					Addrs:     addrs(keys[2], keys[3]),
+				},
+			},
+		}
+		utx = &ptxs.CreateChainTx{
+			BaseTx: ptxs.BaseTx{
+				BaseTx: avax.BaseTx{
+					NetworkID:    constants.UnitTestID,
+					BlockchainID: constants.PlatformChainID,
+					Ins: []*avax.TransferableInput{{
+						UTXOID: utxo.UTXOID,
+						Asset:  utxo.Asset,
+						In: &secp256k1fx.TransferInput{
+							Amt: 1_000,
+							Input: secp256k1fx.Input{
+								SigIndices: []uint32{0, 2},
+							},
+						},
+					}},
+				},
+			},
+			SubnetID:  subnetID,
+			ChainName: "chain",
+			VMID:      ids.GenerateTestID(),
+			SubnetAuth: &secp256k1fx.Input{
+				SigIndices: []uint32{0, 1},
+			},
+		}
+	)
+
+	tx, err := NewPChainTx(context.Background(), b, utx)
+	require.NoError(err)
+	return tx
+}
+
+func TestPChainTx(t *testing.T) {
+	require := require.New(t)
+
+	keys := newKeys(t, 4)
+	tx := newPChainTx(t, keys)
+	require.Equal(PChain, tx.Chain)
+	require.Len(tx.Credentials, 2)
+	require.Equal(addrs(keys[0], keys[2]), tx.Credentials[0].Signers)
+	require.Equal(addrs(keys[2], keys[3]), tx.Credentials[1].Signers)
+	require.Equal(addrs(keys[0], keys[2], keys[3]), tx.Missing())
+	require.False(tx.Complete())
+
+	_, err := tx.PChainTx()
+	require.ErrorIs(err, ErrIncomplete)
+
+	// Each signer signs its own copy of the tx.
+	signed := make([]*Tx, 0, 3)
+	for _, key := range []*secp256k1.PrivateKey{keys[0], keys[2], keys[3]} {
+		txCopy := tx.clone()
+		numAdded, err := txCopy.Sign(secp256k1fx.NewKeychain(key))
+		require.NoError(err)
+		require.Positive(numAdded)
+		signed = append(signed, txCopy)
+	}
+
+	// key 1 isn't a required signer.
+	numAdded, err := tx.clone().Sign(secp256k1fx.NewKeychain(keys[1]))
+	require.NoError(err)
+	require.Zero(numAdded)
+
+	merged, err := Merge(signed[0], signed[1])
+	require.NoError(err)
+	collected, required := merged.NumSignatures()
+	require.Equal(3, collected)
+	require.Equal(4, required)
+	require.Equal(addrs(keys[3]), merged.Missing())
+
+	merged, err = Merge(merged, signed[2])
+	require.NoError(err)
+	require.True(merged.Complete())
+
+	signedTx, err := merged.PChainTx()
+	require.NoError(err)
+	require.Len(signedTx.Creds, 2)
+
+	parsedTx, err := ptxs.Parse(ptxs.Codec, signedTx.Bytes())
+	require.NoError(err)
+	require.Equal(signedTx.ID(), parsedTx.ID())
+
+	_, err = merged.XChainTx()
+	require.ErrorIs(err, ErrWrongChain)
+}
+
+func TestXChainTx(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		keys = newKeys(t, 2)
+		utxo = &avax.UTXO{
+			UTXOID: avax.UTXOID{
+				TxID: ids.GenerateTestID(),
+			},
+			Asset: avax.Asset{ID: ids.GenerateTestID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 1_000,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 2,
+					Addrs:     addrs(keys...),
+				},
+			},
+		}
+		b = &backend{
+			utxos: map[ids.ID]*avax.UTXO{
+				utxo.InputID(): utxo,
+			},
+		}
+		utx = &avmtxs.BaseTx{
+			BaseTx: avax.BaseTx{
+				NetworkID:    constants.UnitTestID,
+				BlockchainID: ids.GenerateTestID(),
+				Ins: []*avax.TransferableInput{{
+					UTXOID: utxo.UTXOID,
+					Asset:  utxo.Asset,
+					In: &secp256k1fx.TransferInput{
+						Amt: 1_000,
+						Input: secp256k1fx.Input{
+							SigIndices: []uint32{0, 1},
+						},
+					},
+				}},
+			},
+		}
+	)
+
+	tx, err := NewXChainTx(context.Background(), b, utx)
+	require.NoError(err)
+	require.Equal(XChain, tx.Chain)
+	require.Len(tx.Credentials, 1)
+	require.Equal(secp256k1fx.ID, tx.Credentials[0].FxID)
+
+	numAdded, err := tx.Sign(secp256k1fx.NewKeychain(keys...))
+	require.NoError(err)
+	require.Equal(2, numAdded)
+	require.True(tx.Complete())
+
+	signedTx, err := tx.XChainTx()
+	require.NoError(err)
+	require.Len(signedTx.Creds, 1)
+	require.Equal(secp256k1fx.ID, signedTx.Creds[0].FxID)
+
+	summary, err := tx.Summary()
+	require.NoError(err)
+	require.Equal(XChain, summary.Chain)
+	require.Equal(signedTx.ID(), *summary.TxID)
+	require.Equal("BaseTx", summary.Type)
+	require.Nil(summary.SubnetID)
+	require.IsType(&avmtxs.BaseTx{}, summary.UnsignedTx)
+	require.Equal(summary.UnsignedTx.(*avmtxs.BaseTx).Ins, summary.Inputs)
+	require.Len(summary.Inputs, 1)
+	require.Empty(summary.Outputs)
+}
+
+func TestNewTxUnknownUTXO(t *testing.T) {
+	utx := &ptxs.BaseTx{
+		BaseTx: avax.BaseTx{
+			Ins: []*avax.TransferableInput{{
+				UTXOID: avax.UTXOID{
+					TxID: ids.GenerateTestID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{0},
+					},
+				},
+			}},
+		},
+	}
+	_, err := NewPChainTx(context.Background(), &backend{}, utx)
+	require.ErrorIs(t, err, psigner.ErrUnknownSigner)
+}
+
+func TestMergeErrors(t *testing.T) {
+	keys := newKeys(t, 4)
+	tx := newPChainTx(t, keys)
+
+	tests := []struct {
+		name        string
+		modify      func(*Tx)
+		expectedErr error
+	}{
+		{
+			name: "different tx",
+			modify: func(tx *Tx) {
+				tx.Unsigned = append(tx.Unsigned, 0)
+			},
+			expectedErr: ErrMismatchedTxs,
+		},
+		{
+			name: "different signers",
+			modify: func(tx *Tx) {
+				tx.Credentials[0].Signers[0] = ids.GenerateTestShortID()
+			},
+			expectedErr: ErrMismatchedTxs,
+		},
+		{
+			name: "conflicting signatures",
+			modify: func(tx *Tx) {
+				tx.Credentials[0].Sigs[0][0]++
+			},
+			expectedErr: ErrConflictingSignatures,
+		},
+		{
+			name: "invalid signature",
+			modify: func(tx *Tx) {
+				tx.Credentials[1].Sigs[1] = tx.Credentials[0].Sigs[0]
+			},
+			expectedErr: ErrInvalidSignature,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			signed := tx.clone()
+			_, err := signed.Sign(secp256k1fx.NewKeychain(keys[0]))
+			require.NoError(err)
+
+			modified := signed.clone()
+			test.modify(modified)
+
+			_, err = Merge(signed, modified)
+			require.ErrorIs(err, test.expectedErr)
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	require := require.New(t)
+
+	keys := newKeys(t, 4)
+	tx := newPChainTx(t, keys)
+	_, err := tx.Sign(secp256k1fx.NewKeychain(keys[2]))
+	require.NoError(err)
+
+	txJSON, err := json.Marshal(tx)
+	require.NoError(err)
+
+	parsedTx := &Tx{}
+	require.NoError(json.Unmarshal(txJSON, parsedTx))
+	require.Equal(tx, parsedTx)
+	require.NoError(parsedTx.Verify())
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pstx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/vms/avm/fxs"
+	"github.com/ava-labs/avalanchego/vms/avm/txs"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/chain/x/builder"
+	"github.com/ava-labs/avalanchego/wallet/chain/x/signer"
+)
+
+var ErrUnknownFx = errors.New("unknown fx")
+
+// NewXChainTx returns a partially-signed tx without any signatures for the
+// X-chain tx [utx].
+//
+// [backend] must know about all the UTXOs consumed by [utx].
+func NewXChainTx(ctx context.Context, backend signer.Backend, utx txs.UnsignedTx) (*Tx, error) {
+	fxIDs, signers, err := signer.RequiredSigners(ctx, backend, utx)
+	if err != nil {
+		return nil, err
+	}
+	unsignedBytes, err := builder.Parser.Codec().Marshal(txs.CodecVersion, &utx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal unsigned tx: %w", err)
+	}
+
+	tx := &Tx{
+		Chain:       XChain,
+		Unsigned:    unsignedBytes,
+		Credentials: make([]*Credential, len(signers)),
+	}
+	for i, credSigners := range signers {
+		tx.Credentials[i] = newCredential(fxIDs[i], credSigners)
+	}
+	return tx, nil
+}
+
+// XChainTx returns the signed X-chain tx. All the required signatures must
+// have been collected.
+func (t *Tx) XChainTx() (*txs.Tx, error) {
+	if t.Chain != XChain {
+		return nil, fmt.Errorf("%w: expected %s but got %s", ErrWrongChain, XChain, t.Chain)
+	}
+	sigs, err := t.sigs()
+	if err != nil {
+		return nil, err
+	}
+
+	codec := builder.Parser.Codec()
+	var utx txs.UnsignedTx
+	if _, err := codec.Unmarshal(t.Unsigned, &utx); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal unsigned tx: %w", err)
+	}
+
+	tx := &txs.Tx{
+		Unsigned: utx,
+		Creds:    make([]*fxs.FxCredential, len(sigs)),
+	}
+	for i, credSigs := range sigs {
+		fxID := t.Credentials[i].FxID
+		cred := secp256k1fx.Credential{
+			Sigs: credSigs,
+		}
+
+		var credIntf verify.Verifiable
+		switch fxID {
+		case secp256k1fx.ID:
+			credIntf = &cred
+		case nftfx.ID:
+			credIntf = &nftfx.Credential{Credential: cred}
+		case propertyfx.ID:
+			credIntf = &propertyfx.Credential{Credential: cred}
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFx, fxID)
+		}
+		tx.Creds[i] = &fxs.FxCredential{
+			FxID:       fxID,
+			Credential: credIntf,
+		}
+	}
+	return tx, tx.Initialize(codec)
+}
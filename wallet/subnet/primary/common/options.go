@@ -45,6 +45,8 @@ type Options struct {
 
 	assumeDecided bool
 
+	skipSimulation bool
+
 	pollFrequencySet bool
 	pollFrequency    time.Duration
 
@@ -125,6 +127,10 @@ func (o *Options) AssumeDecided() bool {
 	return o.assumeDecided
 }
 
+func (o *Options) SkipSimulation() bool {
+	return o.skipSimulation
+}
+
 func (o *Options) PollFrequency() time.Duration {
 	if o.pollFrequencySet {
 		return o.pollFrequency
@@ -193,6 +199,15 @@ func WithAssumeDecided() Option {
 	}
 }
 
+// WithSkipSimulation issues transactions without first simulating their
+// execution on the node. Transactions are also issued without being simulated
+// if the node doesn't support simulating transactions.
+func WithSkipSimulation() Option {
+	return func(o *Options) {
+		o.skipSimulation = true
+	}
+}
+
 func WithPollFrequency(pollFrequency time.Duration) Option {
 	return func(o *Options) {
 		o.pollFrequencySet = true
@@ -111,14 +111,9 @@ func (c *client) GetChangeProof(
 	req *pb.SyncGetChangeProofRequest,
 	db DB,
 ) (*merkledb.ChangeOrRangeProof, error) {
-	parseFn := func(ctx context.Context, responseBytes []byte) (*merkledb.ChangeOrRangeProof, error) {
-		if len(responseBytes) > int(req.BytesLimit) {
-			return nil, fmt.Errorf("%w: (%d) > %d)", errTooManyBytes, len(responseBytes), req.BytesLimit)
-		}
-
-		var changeProofResp pb.SyncGetChangeProofResponse
-		if err := proto.Unmarshal(responseBytes, &changeProofResp); err != nil {
-			return nil, err
+	parseFn := func(ctx context.Context, changeProofResp *pb.SyncGetChangeProofResponse) (*merkledb.ChangeOrRangeProof, error) {
+		if responseLen := proto.Size(changeProofResp); responseLen > int(req.BytesLimit) {
+			return nil, fmt.Errorf("%w: (%d) > %d)", errTooManyBytes, responseLen, req.BytesLimit)
 		}
 
 		startKey := maybeBytesToMaybe(req.StartKey)
@@ -193,15 +188,14 @@ func (c *client) GetChangeProof(
 		}
 	}
 
-	reqBytes, err := proto.Marshal(&pb.Request{
-		Message: &pb.Request_ChangeProofRequest{
-			ChangeProofRequest: req,
-		},
-	})
-	if err != nil {
-		return nil, err
-	}
-	return getAndParse(ctx, c, reqBytes, parseFn)
+	return getAndParse(
+		ctx,
+		c,
+		c.networkClient.ChangeProofRequestAny,
+		c.networkClient.ChangeProofRequest,
+		req,
+		parseFn,
+	)
 }
 
 // Verify [rangeProof] is a valid range proof for keys in [start, end] for
@@ -250,21 +244,16 @@ func (c *client) GetRangeProof(
 	ctx context.Context,
 	req *pb.SyncGetRangeProofRequest,
 ) (*merkledb.RangeProof, error) {
-	parseFn := func(ctx context.Context, responseBytes []byte) (*merkledb.RangeProof, error) {
-		if len(responseBytes) > int(req.BytesLimit) {
+	parseFn := func(ctx context.Context, rangeProofProto *pb.RangeProof) (*merkledb.RangeProof, error) {
+		if responseLen := proto.Size(rangeProofProto); responseLen > int(req.BytesLimit) {
 			return nil, fmt.Errorf(
 				"%w: (%d) > %d)",
-				errTooManyBytes, len(responseBytes), req.BytesLimit,
+				errTooManyBytes, responseLen, req.BytesLimit,
 			)
 		}
 
-		var rangeProofProto pb.RangeProof
-		if err := proto.Unmarshal(responseBytes, &rangeProofProto); err != nil {
-			return nil, err
-		}
-
 		var rangeProof merkledb.RangeProof
-		if err := rangeProof.UnmarshalProto(&rangeProofProto); err != nil {
+		if err := rangeProof.UnmarshalProto(rangeProofProto); err != nil {
 			return nil, err
 		}
 
@@ -283,30 +272,31 @@ func (c *client) GetRangeProof(
 		return &rangeProof, nil
 	}
 
-	reqBytes, err := proto.Marshal(&pb.Request{
-		Message: &pb.Request_RangeProofRequest{
-			RangeProofRequest: req,
-		},
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	return getAndParse(ctx, c, reqBytes, parseFn)
+	return getAndParse(
+		ctx,
+		c,
+		c.networkClient.RangeProofRequestAny,
+		c.networkClient.RangeProofRequest,
+		req,
+		parseFn,
+	)
 }
 
-// getAndParse uses [client] to send [request] to an arbitrary peer.
+// getAndParse uses [client] to send [request] to a peer with [requestAny] or,
+// if state sync nodes are configured, with [request].
 // Returns the response to the request.
-// [parseFn] parses the raw response.
+// [parseFn] parses and verifies the response.
 // If the request is unsuccessful or the response can't be parsed,
 // retries the request to a different peer until [ctx] expires.
 // Returns [errAppSendFailed] if we fail to send an AppRequest/AppResponse.
 // This should be treated as a fatal error.
-func getAndParse[T any](
+func getAndParse[Req, Resp proto.Message, T any](
 	ctx context.Context,
 	client *client,
-	request []byte,
-	parseFn func(context.Context, []byte) (*T, error),
+	requestAny func(context.Context, Req) (ids.NodeID, Resp, error),
+	request func(context.Context, ids.NodeID, Req) (Resp, error),
+	req Req,
+	parseFn func(context.Context, Resp) (*T, error),
 ) (*T, error) {
 	var (
 		lastErr  error
@@ -314,9 +304,9 @@ func getAndParse[T any](
 	)
 	// Loop until the context is cancelled or we get a valid response.
 	for attempt := 1; ; attempt++ {
-		nodeID, responseBytes, err := client.get(ctx, request)
+		nodeID, resp, err := get(ctx, client, requestAny, request, req)
 		if err == nil {
-			if response, err = parseFn(ctx, responseBytes); err == nil {
+			if response, err = parseFn(ctx, resp); err == nil {
 				return response, nil
 			}
 		}
@@ -340,6 +330,11 @@ func getAndParse[T any](
 		if retryWait > maxRetryWait || retryWait < 0 { // Handle overflows with negative check.
 			retryWait = maxRetryWait
 		}
+		if errors.Is(err, ErrInsufficientHistory) {
+			// The peer is healthy but can't serve this request, so the request
+			// is promptly sent to another peer rather than backed off.
+			retryWait = initialRetryWait
+		}
 
 		select {
 		case <-ctx.Done():
@@ -356,16 +351,22 @@ func getAndParse[T any](
 	}
 }
 
-// get sends [request] to an arbitrary peer and blocks
+// get sends [req] to an arbitrary peer and blocks
 // until the node receives a response, failure notification
 // or [ctx] is canceled.
 // Returns the peer's NodeID and response.
 // Returns [errAppSendFailed] if we failed to send an AppRequest/AppResponse.
 // This should be treated as fatal.
 // It's safe to call this method multiple times concurrently.
-func (c *client) get(ctx context.Context, request []byte) (ids.NodeID, []byte, error) {
+func get[Req, Resp proto.Message](
+	ctx context.Context,
+	c *client,
+	requestAny func(context.Context, Req) (ids.NodeID, Resp, error),
+	request func(context.Context, ids.NodeID, Req) (Resp, error),
+	req Req,
+) (ids.NodeID, Resp, error) {
 	var (
-		response []byte
+		response Resp
 		nodeID   ids.NodeID
 		err      error
 	)
@@ -373,14 +374,14 @@ func (c *client) get(ctx context.Context, request []byte) (ids.NodeID, []byte, e
 	c.metrics.RequestMade()
 
 	if len(c.stateSyncNodes) == 0 {
-		nodeID, response, err = c.networkClient.RequestAny(ctx, request)
+		nodeID, response, err = requestAny(ctx, req)
 	} else {
 		// Get the next nodeID to query using the [nodeIdx] offset.
 		// If we're out of nodes, loop back to 0.
 		// We do this try to query a different node each time if possible.
 		nodeIdx := atomic.AddUint32(&c.stateSyncNodeIdx, 1)
 		nodeID = c.stateSyncNodes[nodeIdx%uint32(len(c.stateSyncNodes))]
-		response, err = c.networkClient.Request(ctx, nodeID, request)
+		response, err = request(ctx, nodeID, req)
 	}
 	if err != nil {
 		c.metrics.RequestFailed()
@@ -17,7 +17,7 @@ import (
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/network/p2p"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/maybe"
@@ -61,22 +61,17 @@ func sendRangeProofRequest(
 		// Number of calls from the client to the server so far.
 		numAttempts int
 
-		// Sends messages from server to client.
-		sender = common.NewMockSender(ctrl)
-
 		// Serves the range proof.
-		server = NewNetworkServer(sender, serverDB, logging.NoLog{})
+		server = p2p.NewTypedHandler(NewGetRangeProofHandler(logging.NoLog{}, serverDB), logging.NoLog{})
 
 		clientNodeID, serverNodeID = ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
 
 		// "Sends" the request from the client to the server and
 		// "receives" the response from the server. In reality,
-		// it just invokes the server's method and receives
-		// the response on [serverResponseChan].
+		// it just invokes the server's method and returns its
+		// response.
 		networkClient = NewMockNetworkClient(ctrl)
 
-		serverResponseChan = make(chan []byte, 1)
-
 		// The context used in client.GetRangeProof.
 		// Canceled after the first response is received because
 		// the client will keep sending requests until its context
@@ -95,37 +90,18 @@ func sendRangeProofRequest(
 	})
 	require.NoError(err)
 
-	networkClient.EXPECT().RequestAny(
+	networkClient.EXPECT().RangeProofRequestAny(
 		gomock.Any(), // ctx
 		gomock.Any(), // request
 	).DoAndReturn(
-		func(_ context.Context, request []byte) (ids.NodeID, []byte, error) {
-			go func() {
-				// Get response from server
-				require.NoError(server.AppRequest(context.Background(), clientNodeID, 0, time.Now().Add(time.Hour), request))
-			}()
-
-			// Wait for response from server
-			serverResponse := <-serverResponseChan
-
-			numAttempts++
-
-			if numAttempts >= maxAttempts {
-				defer cancel()
-			}
+		func(_ context.Context, request *pb.SyncGetRangeProofRequest) (ids.NodeID, *pb.RangeProof, error) {
+			requestBytes, err := proto.Marshal(request)
+			require.NoError(err)
 
-			return serverNodeID, serverResponse, nil
-		},
-	).AnyTimes()
+			// Get response from server
+			responseBytes, appErr := server.AppRequest(context.Background(), clientNodeID, time.Now().Add(time.Hour), requestBytes)
+			require.Nil(appErr)
 
-	// The server should expect to "send" a response to the client.
-	sender.EXPECT().SendAppResponse(
-		gomock.Any(), // ctx
-		clientNodeID,
-		gomock.Any(), // requestID
-		gomock.Any(), // responseBytes
-	).DoAndReturn(
-		func(_ context.Context, _ ids.NodeID, _ uint32, responseBytes []byte) error {
 			// deserialize the response so we can modify it if needed.
 			var responseProto pb.RangeProof
 			require.NoError(proto.Unmarshal(responseBytes, &responseProto))
@@ -138,13 +114,13 @@ func sendRangeProofRequest(
 				modifyResponse(&response)
 			}
 
-			// reserialize the response and pass it to the client to complete the handling.
-			responseBytes, err := proto.Marshal(response.ToProto())
-			require.NoError(err)
+			numAttempts++
 
-			serverResponseChan <- responseBytes
+			if numAttempts >= maxAttempts {
+				defer cancel()
+			}
 
-			return nil
+			return serverNodeID, response.ToProto(), nil
 		},
 	).AnyTimes()
 
@@ -360,22 +336,17 @@ func sendChangeProofRequest(
 		// Number of calls from the client to the server so far.
 		numAttempts int
 
-		// Sends messages from server to client.
-		sender = common.NewMockSender(ctrl)
-
 		// Serves the change proof.
-		server = NewNetworkServer(sender, serverDB, logging.NoLog{})
+		server = p2p.NewTypedHandler(NewGetChangeProofHandler(logging.NoLog{}, serverDB), logging.NoLog{})
 
 		clientNodeID, serverNodeID = ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
 
 		// "Sends" the request from the client to the server and
 		// "receives" the response from the server. In reality,
-		// it just invokes the server's method and receives
-		// the response on [serverResponseChan].
+		// it just invokes the server's method and returns its
+		// response.
 		networkClient = NewMockNetworkClient(ctrl)
 
-		serverResponseChan = make(chan []byte, 1)
-
 		// The context used in client.GetChangeProof.
 		// Canceled after the first response is received because
 		// the client will keep sending requests until its context
@@ -394,18 +365,17 @@ func sendChangeProofRequest(
 
 	defer cancel() // avoid leaking a goroutine
 
-	networkClient.EXPECT().RequestAny(
+	networkClient.EXPECT().ChangeProofRequestAny(
 		gomock.Any(), // ctx
 		gomock.Any(), // request
 	).DoAndReturn(
-		func(_ context.Context, request []byte) (ids.NodeID, []byte, error) {
-			go func() {
-				// Get response from server
-				require.NoError(server.AppRequest(context.Background(), clientNodeID, 0, time.Now().Add(time.Hour), request))
-			}()
+		func(_ context.Context, request *pb.SyncGetChangeProofRequest) (ids.NodeID, *pb.SyncGetChangeProofResponse, error) {
+			requestBytes, err := proto.Marshal(request)
+			require.NoError(err)
 
-			// Wait for response from server
-			serverResponse := <-serverResponseChan
+			// Get response from server
+			responseBytes, appErr := server.AppRequest(context.Background(), clientNodeID, time.Now().Add(time.Hour), requestBytes)
+			require.Nil(appErr)
 
 			numAttempts++
 
@@ -413,18 +383,6 @@ func sendChangeProofRequest(
 				defer cancel()
 			}
 
-			return serverNodeID, serverResponse, nil
-		},
-	).AnyTimes()
-
-	// Expect server (serverDB) to send app response to client (clientDB)
-	sender.EXPECT().SendAppResponse(
-		gomock.Any(), // ctx
-		clientNodeID,
-		gomock.Any(), // requestID
-		gomock.Any(), // responseBytes
-	).DoAndReturn(
-		func(_ context.Context, _ ids.NodeID, _ uint32, responseBytes []byte) error {
 			// deserialize the response so we can modify it if needed.
 			var responseProto pb.SyncGetChangeProofResponse
 			require.NoError(proto.Unmarshal(responseBytes, &responseProto))
@@ -439,17 +397,11 @@ func sendChangeProofRequest(
 					modifyChangeProof(&changeProof)
 				}
 
-				// reserialize the response and pass it to the client to complete the handling.
-				responseBytes, err := proto.Marshal(&pb.SyncGetChangeProofResponse{
+				return serverNodeID, &pb.SyncGetChangeProofResponse{
 					Response: &pb.SyncGetChangeProofResponse_ChangeProof{
 						ChangeProof: changeProof.ToProto(),
 					},
-				})
-				require.NoError(err)
-
-				serverResponseChan <- responseBytes
-
-				return nil
+				}, nil
 			}
 
 			// Server responded with a range proof
@@ -461,17 +413,11 @@ func sendChangeProofRequest(
 				modifyRangeProof(&rangeProof)
 			}
 
-			// reserialize the response and pass it to the client to complete the handling.
-			responseBytes, err := proto.Marshal(&pb.SyncGetChangeProofResponse{
+			return serverNodeID, &pb.SyncGetChangeProofResponse{
 				Response: &pb.SyncGetChangeProofResponse_RangeProof{
 					RangeProof: rangeProof.ToProto(),
 				},
-			})
-			require.NoError(err)
-
-			serverResponseChan <- responseBytes
-
-			return nil
+			}, nil
 		},
 	).AnyTimes()
 
@@ -757,10 +703,14 @@ func TestAppRequestSendFailed(t *testing.T) {
 	require.NoError(err)
 
 	// Mock failure to send app request
-	networkClient.EXPECT().RequestAny(
+	networkClient.EXPECT().ChangeProofRequestAny(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(ids.EmptyNodeID, nil, errAppSendFailed)
+	networkClient.EXPECT().RangeProofRequestAny(
 		gomock.Any(),
 		gomock.Any(),
-	).Return(ids.EmptyNodeID, nil, errAppSendFailed).Times(2)
+	).Return(ids.EmptyNodeID, nil, errAppSendFailed)
 
 	_, err = client.GetChangeProof(
 		context.Background(),
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	ids "github.com/ava-labs/avalanchego/ids"
+	sync "github.com/ava-labs/avalanchego/proto/pb/sync"
 	version "github.com/ava-labs/avalanchego/version"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,32 +42,35 @@ func (m *MockNetworkClient) EXPECT() *MockNetworkClientMockRecorder {
 	return m.recorder
 }
 
-// AppRequestFailed mocks base method.
-func (m *MockNetworkClient) AppRequestFailed(arg0 context.Context, arg1 ids.NodeID, arg2 uint32) error {
+// ChangeProofRequest mocks base method.
+func (m *MockNetworkClient) ChangeProofRequest(arg0 context.Context, arg1 ids.NodeID, arg2 *sync.SyncGetChangeProofRequest) (*sync.SyncGetChangeProofResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AppRequestFailed", arg0, arg1, arg2)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ChangeProofRequest", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*sync.SyncGetChangeProofResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// AppRequestFailed indicates an expected call of AppRequestFailed.
-func (mr *MockNetworkClientMockRecorder) AppRequestFailed(arg0, arg1, arg2 any) *gomock.Call {
+// ChangeProofRequest indicates an expected call of ChangeProofRequest.
+func (mr *MockNetworkClientMockRecorder) ChangeProofRequest(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppRequestFailed", reflect.TypeOf((*MockNetworkClient)(nil).AppRequestFailed), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeProofRequest", reflect.TypeOf((*MockNetworkClient)(nil).ChangeProofRequest), arg0, arg1, arg2)
 }
 
-// AppResponse mocks base method.
-func (m *MockNetworkClient) AppResponse(arg0 context.Context, arg1 ids.NodeID, arg2 uint32, arg3 []byte) error {
+// ChangeProofRequestAny mocks base method.
+func (m *MockNetworkClient) ChangeProofRequestAny(arg0 context.Context, arg1 *sync.SyncGetChangeProofRequest) (ids.NodeID, *sync.SyncGetChangeProofResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AppResponse", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ChangeProofRequestAny", arg0, arg1)
+	ret0, _ := ret[0].(ids.NodeID)
+	ret1, _ := ret[1].(*sync.SyncGetChangeProofResponse)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-// AppResponse indicates an expected call of AppResponse.
-func (mr *MockNetworkClientMockRecorder) AppResponse(arg0, arg1, arg2, arg3 any) *gomock.Call {
+// ChangeProofRequestAny indicates an expected call of ChangeProofRequestAny.
+func (mr *MockNetworkClientMockRecorder) ChangeProofRequestAny(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppResponse", reflect.TypeOf((*MockNetworkClient)(nil).AppResponse), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeProofRequestAny", reflect.TypeOf((*MockNetworkClient)(nil).ChangeProofRequestAny), arg0, arg1)
 }
 
 // Connected mocks base method.
@@ -97,33 +101,33 @@ func (mr *MockNetworkClientMockRecorder) Disconnected(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disconnected", reflect.TypeOf((*MockNetworkClient)(nil).Disconnected), arg0, arg1)
 }
 
-// Request mocks base method.
-func (m *MockNetworkClient) Request(arg0 context.Context, arg1 ids.NodeID, arg2 []byte) ([]byte, error) {
+// RangeProofRequest mocks base method.
+func (m *MockNetworkClient) RangeProofRequest(arg0 context.Context, arg1 ids.NodeID, arg2 *sync.SyncGetRangeProofRequest) (*sync.RangeProof, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Request", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]byte)
+	ret := m.ctrl.Call(m, "RangeProofRequest", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*sync.RangeProof)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Request indicates an expected call of Request.
-func (mr *MockNetworkClientMockRecorder) Request(arg0, arg1, arg2 any) *gomock.Call {
+// RangeProofRequest indicates an expected call of RangeProofRequest.
+func (mr *MockNetworkClientMockRecorder) RangeProofRequest(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockNetworkClient)(nil).Request), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeProofRequest", reflect.TypeOf((*MockNetworkClient)(nil).RangeProofRequest), arg0, arg1, arg2)
 }
 
-// RequestAny mocks base method.
-func (m *MockNetworkClient) RequestAny(arg0 context.Context, arg1 []byte) (ids.NodeID, []byte, error) {
+// RangeProofRequestAny mocks base method.
+func (m *MockNetworkClient) RangeProofRequestAny(arg0 context.Context, arg1 *sync.SyncGetRangeProofRequest) (ids.NodeID, *sync.RangeProof, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RequestAny", arg0, arg1)
+	ret := m.ctrl.Call(m, "RangeProofRequestAny", arg0, arg1)
 	ret0, _ := ret[0].(ids.NodeID)
-	ret1, _ := ret[1].([]byte)
+	ret1, _ := ret[1].(*sync.RangeProof)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// RequestAny indicates an expected call of RequestAny.
-func (mr *MockNetworkClientMockRecorder) RequestAny(arg0, arg1 any) *gomock.Call {
+// RangeProofRequestAny indicates an expected call of RangeProofRequestAny.
+func (mr *MockNetworkClientMockRecorder) RangeProofRequestAny(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestAny", reflect.TypeOf((*MockNetworkClient)(nil).RequestAny), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeProofRequestAny", reflect.TypeOf((*MockNetworkClient)(nil).RangeProofRequestAny), arg0, arg1)
 }
@@ -7,23 +7,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"golang.org/x/sync/semaphore"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network/p2p"
-	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/network/reputation"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/version"
-)
 
-// Minimum amount of time to handle a request
-const minRequestHandlingDuration = 100 * time.Millisecond
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+)
 
 var (
 	_ NetworkClient = (*networkClient)(nil)
@@ -35,34 +34,44 @@ var (
 
 // NetworkClient defines ability to send request / response through the Network
 type NetworkClient interface {
-	// RequestAny synchronously sends request to an arbitrary peer with a
-	// node version greater than or equal to minVersion.
-	// Returns response bytes, the ID of the chosen peer, and ErrRequestFailed if
+	// RangeProofRequestAny synchronously sends [request] to an arbitrary peer
+	// with a node version greater than or equal to minVersion.
+	// Returns the ID of the chosen peer, the response, and ErrRequestFailed if
 	// the request should be retried.
-	RequestAny(
+	RangeProofRequestAny(
 		ctx context.Context,
-		request []byte,
-	) (ids.NodeID, []byte, error)
+		request *pb.SyncGetRangeProofRequest,
+	) (ids.NodeID, *pb.RangeProof, error)
 
 	// Sends [request] to [nodeID] and returns the response.
 	// Blocks until the number of outstanding requests is
 	// below the limit before sending the request.
-	Request(
+	RangeProofRequest(
 		ctx context.Context,
 		nodeID ids.NodeID,
-		request []byte,
-	) ([]byte, error)
+		request *pb.SyncGetRangeProofRequest,
+	) (*pb.RangeProof, error)
 
-	// The following declarations allow this interface to be embedded in the VM
-	// to handle incoming responses from peers.
+	// ChangeProofRequestAny synchronously sends [request] to an arbitrary peer
+	// with a node version greater than or equal to minVersion.
+	// Returns the ID of the chosen peer, the response, and ErrRequestFailed if
+	// the request should be retried.
+	ChangeProofRequestAny(
+		ctx context.Context,
+		request *pb.SyncGetChangeProofRequest,
+	) (ids.NodeID, *pb.SyncGetChangeProofResponse, error)
 
-	// Always returns nil because the engine considers errors
-	// returned from this function as fatal.
-	AppResponse(context.Context, ids.NodeID, uint32, []byte) error
+	// Sends [request] to [nodeID] and returns the response.
+	// Blocks until the number of outstanding requests is
+	// below the limit before sending the request.
+	ChangeProofRequest(
+		ctx context.Context,
+		nodeID ids.NodeID,
+		request *pb.SyncGetChangeProofRequest,
+	) (*pb.SyncGetChangeProofResponse, error)
 
-	// Always returns nil because the engine considers errors
-	// returned from this function as fatal.
-	AppRequestFailed(context.Context, ids.NodeID, uint32) error
+	// The following declarations allow this interface to be embedded in the VM
+	// to track the peers that requests can be sent to.
 
 	// Adds the given [nodeID] to the peer
 	// list so that it can receive messages.
@@ -74,22 +83,24 @@ type NetworkClient interface {
 }
 
 type networkClient struct {
-	lock sync.Mutex
-	log  logging.Logger
-	// requestID counter used to track outbound requests
-	requestID uint32
-	// requestID => handler for the response/failure
-	outstandingRequestHandlers map[uint32]ResponseHandler
+	log logging.Logger
 	// controls maximum number of active outbound requests
 	activeRequests *semaphore.Weighted
 	// tracking of peers & bandwidth usage
 	peers *p2p.PeerTracker
-	// For sending messages to peers
-	appSender common.AppSender
+	// For sending requests to peers that are served by a handler created with
+	// NewGetRangeProofHandler
+	rangeProofClient *p2p.TypedClient[*pb.SyncGetRangeProofRequest, *pb.RangeProof]
+	// For sending requests to peers that are served by a handler created with
+	// NewGetChangeProofHandler
+	changeProofClient *p2p.TypedClient[*pb.SyncGetChangeProofRequest, *pb.SyncGetChangeProofResponse]
 }
 
+// NewNetworkClient returns a NetworkClient that sends range proof requests
+// with [rangeProofClient] and change proof requests with [changeProofClient].
 func NewNetworkClient(
-	appSender common.AppSender,
+	rangeProofClient *p2p.Client,
+	changeProofClient *p2p.Client,
 	myNodeID ids.NodeID,
 	maxActiveRequests int64,
 	log logging.Logger,
@@ -103,252 +114,175 @@ func NewNetworkClient(
 		registerer,
 		set.Of(myNodeID),
 		minVersion,
+		reputation.NoTracker{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create peer tracker: %w", err)
 	}
 
 	return &networkClient{
-		appSender:                  appSender,
-		outstandingRequestHandlers: make(map[uint32]ResponseHandler),
-		activeRequests:             semaphore.NewWeighted(maxActiveRequests),
-		peers:                      peerTracker,
-		log:                        log,
+		log:               log,
+		activeRequests:    semaphore.NewWeighted(maxActiveRequests),
+		peers:             peerTracker,
+		rangeProofClient:  p2p.NewTypedClient[*pb.SyncGetRangeProofRequest, *pb.RangeProof](rangeProofClient),
+		changeProofClient: p2p.NewTypedClient[*pb.SyncGetChangeProofRequest, *pb.SyncGetChangeProofResponse](changeProofClient),
 	}, nil
 }
 
-func (c *networkClient) AppResponse(
-	_ context.Context,
-	nodeID ids.NodeID,
-	requestID uint32,
-	response []byte,
-) error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	c.log.Info(
-		"received AppResponse from peer",
-		zap.Stringer("nodeID", nodeID),
-		zap.Uint32("requestID", requestID),
-		zap.Int("responseLen", len(response)),
-	)
-
-	handler, exists := c.getRequestHandler(requestID)
-	if !exists {
-		// Should never happen since the engine
-		// should be managing outstanding requests
-		c.log.Warn(
-			"received response to unknown request",
-			zap.Stringer("nodeID", nodeID),
-			zap.Uint32("requestID", requestID),
-			zap.Int("responseLen", len(response)),
-		)
-		return nil
-	}
-	handler.OnResponse(response)
-	return nil
+// If [errAppSendFailed] is returned this should be considered fatal.
+func (c *networkClient) RangeProofRequestAny(
+	ctx context.Context,
+	request *pb.SyncGetRangeProofRequest,
+) (ids.NodeID, *pb.RangeProof, error) {
+	return requestAny(ctx, c, c.rangeProofClient, request)
 }
 
-func (c *networkClient) AppRequestFailed(
-	_ context.Context,
+// If [errAppSendFailed] is returned this should be considered fatal.
+func (c *networkClient) RangeProofRequest(
+	ctx context.Context,
 	nodeID ids.NodeID,
-	requestID uint32,
-) error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	c.log.Info(
-		"received AppRequestFailed from peer",
-		zap.Stringer("nodeID", nodeID),
-		zap.Uint32("requestID", requestID),
-	)
+	request *pb.SyncGetRangeProofRequest,
+) (*pb.RangeProof, error) {
+	return sendRequest(ctx, c, c.rangeProofClient, nodeID, request)
+}
 
-	handler, exists := c.getRequestHandler(requestID)
-	if !exists {
-		// Should never happen since the engine
-		// should be managing outstanding requests
-		c.log.Warn(
-			"received request failed to unknown request",
-			zap.Stringer("nodeID", nodeID),
-			zap.Uint32("requestID", requestID),
-		)
-		return nil
-	}
-	handler.OnFailure()
-	return nil
+// If [errAppSendFailed] is returned this should be considered fatal.
+func (c *networkClient) ChangeProofRequestAny(
+	ctx context.Context,
+	request *pb.SyncGetChangeProofRequest,
+) (ids.NodeID, *pb.SyncGetChangeProofResponse, error) {
+	return requestAny(ctx, c, c.changeProofClient, request)
 }
 
-// Returns the handler for [requestID] and marks the request as fulfilled.
-// Returns false if there's no outstanding request with [requestID].
-// Assumes [c.lock] is held.
-func (c *networkClient) getRequestHandler(requestID uint32) (ResponseHandler, bool) {
-	handler, exists := c.outstandingRequestHandlers[requestID]
-	if !exists {
-		return nil, false
-	}
-	// mark message as processed, release activeRequests slot
-	delete(c.outstandingRequestHandlers, requestID)
-	return handler, true
+// If [errAppSendFailed] is returned this should be considered fatal.
+func (c *networkClient) ChangeProofRequest(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	request *pb.SyncGetChangeProofRequest,
+) (*pb.SyncGetChangeProofResponse, error) {
+	return sendRequest(ctx, c, c.changeProofClient, nodeID, request)
 }
 
+// requestAny sends [request] to an arbitrary peer with [client] and returns
+// the chosen peer and its response.
+//
 // If [errAppSendFailed] is returned this should be considered fatal.
-func (c *networkClient) RequestAny(
+func requestAny[Req, Resp proto.Message](
 	ctx context.Context,
-	request []byte,
-) (ids.NodeID, []byte, error) {
+	c *networkClient,
+	client *p2p.TypedClient[Req, Resp],
+	request Req,
+) (ids.NodeID, Resp, error) {
+	var empty Resp
+
 	// Take a slot from total [activeRequests] and block until a slot becomes available.
 	if err := c.activeRequests.Acquire(ctx, 1); err != nil {
-		return ids.EmptyNodeID, nil, errAcquiringSemaphore
+		return ids.EmptyNodeID, empty, errAcquiringSemaphore
 	}
 	defer c.activeRequests.Release(1)
 
-	nodeID, responseChan, err := c.sendRequestAny(ctx, request)
-	if err != nil {
-		return ids.EmptyNodeID, nil, err
-	}
-
-	response, err := c.awaitResponse(ctx, nodeID, responseChan)
-	return nodeID, response, err
-}
-
-func (c *networkClient) sendRequestAny(
-	ctx context.Context,
-	request []byte,
-) (ids.NodeID, chan []byte, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	nodeID, ok := c.peers.SelectPeer()
 	if !ok {
 		numPeers := c.peers.Size()
-		return ids.EmptyNodeID, nil, fmt.Errorf("no peers found from %d peers", numPeers)
+		return ids.EmptyNodeID, empty, fmt.Errorf("no peers found from %d peers", numPeers)
 	}
 
-	responseChan, err := c.sendRequestLocked(ctx, nodeID, request)
-	return nodeID, responseChan, err
+	response, err := sendAndWait(ctx, c, client, nodeID, request)
+	return nodeID, response, err
 }
 
+// sendRequest sends [request] to [nodeID] with [client] and returns the
+// response.
+//
 // If [errAppSendFailed] is returned this should be considered fatal.
-func (c *networkClient) Request(
+func sendRequest[Req, Resp proto.Message](
 	ctx context.Context,
+	c *networkClient,
+	client *p2p.TypedClient[Req, Resp],
 	nodeID ids.NodeID,
-	request []byte,
-) ([]byte, error) {
+	request Req,
+) (Resp, error) {
 	// Take a slot from total [activeRequests]
 	// and block until a slot becomes available.
 	if err := c.activeRequests.Acquire(ctx, 1); err != nil {
-		return nil, errAcquiringSemaphore
+		var empty Resp
+		return empty, errAcquiringSemaphore
 	}
 	defer c.activeRequests.Release(1)
 
-	responseChan, err := c.sendRequest(ctx, nodeID, request)
-	if err != nil {
-		return nil, err
-	}
-
-	return c.awaitResponse(ctx, nodeID, responseChan)
+	return sendAndWait(ctx, c, client, nodeID, request)
 }
 
-func (c *networkClient) sendRequest(
-	ctx context.Context,
-	nodeID ids.NodeID,
-	request []byte,
-) (chan []byte, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	return c.sendRequestLocked(ctx, nodeID, request)
+type result[Resp proto.Message] struct {
+	response Resp
+	err      error
 }
 
-// Sends [request] to [nodeID] and returns a channel that will populate the
-// response.
+// sendAndWait sends [req] to [nodeID] and blocks until the response is received,
+// the request fails, or [ctx] is canceled.
 //
 // If [errAppSendFailed] is returned this should be considered fatal.
 //
 // Assumes [nodeID] is never [c.myNodeID] since we guarantee [c.myNodeID] will
 // not be added to [c.peers].
 //
-// Assumes [c.lock] is held.
-func (c *networkClient) sendRequestLocked(
+// Assumes a slot of [c.activeRequests] is held.
+func sendAndWait[Req, Resp proto.Message](
 	ctx context.Context,
+	c *networkClient,
+	client *p2p.TypedClient[Req, Resp],
 	nodeID ids.NodeID,
-	request []byte,
-) (chan []byte, error) {
-	requestID := c.requestID
-	c.requestID++
+	req Req,
+) (Resp, error) {
+	var empty Resp
 
 	c.log.Debug("sending request to peer",
 		zap.Stringer("nodeID", nodeID),
-		zap.Uint32("requestID", requestID),
-		zap.Int("requestLen", len(request)),
+		zap.Int("requestLen", proto.Size(req)),
 	)
 	c.peers.RegisterRequest(nodeID)
 
-	// Send an app request to the peer.
-	nodeIDs := set.Of(nodeID)
-	// Cancellation is removed from this context to avoid erroring unexpectedly.
-	// SendAppRequest should be non-blocking and any error other than context
-	// cancellation is unexpected.
-	//
-	// This guarantees that the network should never receive an unexpected
-	// AppResponse.
-	ctxWithoutCancel := context.WithoutCancel(ctx)
-	if err := c.appSender.SendAppRequest(ctxWithoutCancel, nodeIDs, requestID, request); err != nil {
-		c.lock.Unlock()
+	// The callback is called exactly once, so sends to [results] never block.
+	results := make(chan result[Resp], 1)
+	onResponse := func(_ context.Context, _ ids.NodeID, response Resp, err error) {
+		results <- result[Resp]{
+			response: response,
+			err:      err,
+		}
+	}
+
+	startTime := time.Now()
+	if err := client.AppRequest(ctx, set.Of(nodeID), req, onResponse); err != nil {
 		c.log.Fatal("failed to send app request",
 			zap.Stringer("nodeID", nodeID),
-			zap.Uint32("requestID", requestID),
-			zap.Int("requestLen", len(request)),
+			zap.Int("requestLen", proto.Size(req)),
 			zap.Error(err),
 		)
-		return nil, fmt.Errorf("%w: %w", errAppSendFailed, err)
+		return empty, fmt.Errorf("%w: %w", errAppSendFailed, err)
 	}
 
-	handler := newResponseHandler()
-	c.outstandingRequestHandlers[requestID] = handler
-	return handler.responseChan, nil
-}
-
-// awaitResponse from [nodeID] and returns the response.
-//
-// Returns an error if the request failed or [ctx] is canceled.
-//
-// Blocks until a response is received or the [ctx] is canceled fails.
-//
-// Assumes [nodeID] is never [c.myNodeID] since we guarantee [c.myNodeID] will
-// not be added to [c.peers].
-//
-// Assumes [c.lock] is not held.
-func (c *networkClient) awaitResponse(
-	ctx context.Context,
-	nodeID ids.NodeID,
-	responseChan chan []byte,
-) ([]byte, error) {
-	var (
-		response  []byte
-		responded bool
-		startTime = time.Now()
-	)
+	var res result[Resp]
 	select {
 	case <-ctx.Done():
 		c.peers.RegisterFailure(nodeID)
-		return nil, ctx.Err()
-	case response, responded = <-responseChan:
+		return empty, ctx.Err()
+	case res = <-results:
 	}
-	if !responded {
+	if res.err != nil {
 		c.peers.RegisterFailure(nodeID)
-		return nil, errRequestFailed
+		return empty, fmt.Errorf("%w: %w", errRequestFailed, res.err)
 	}
 
+	responseLen := proto.Size(res.response)
 	elapsedSeconds := time.Since(startTime).Seconds()
-	bandwidth := float64(len(response)) / (elapsedSeconds + epsilon)
+	bandwidth := float64(responseLen) / (elapsedSeconds + epsilon)
 	c.peers.RegisterResponse(nodeID, bandwidth)
 
 	c.log.Debug("received response from peer",
 		zap.Stringer("nodeID", nodeID),
-		zap.Int("responseLen", len(response)),
+		zap.Int("responseLen", responseLen),
 	)
-	return response, nil
+	return res.response, nil
 }
 
 func (c *networkClient) Connected(
@@ -16,6 +16,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/p2p"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/hashing"
@@ -37,11 +38,36 @@ const (
 	// TODO: refine this estimate. This is almost certainly a large overestimate.
 	estimatedMessageOverhead = 4 * units.KiB
 	maxByteSizeLimit         = constants.DefaultMaxMessageSize - estimatedMessageOverhead
+
+	// Minimum amount of time to handle a request
+	minRequestHandlingDuration = 100 * time.Millisecond
+	// Amount of time before the deadline of a request that is reserved for
+	// the response to reach the requester.
+	deadlineBuffer = 500 * time.Millisecond
 )
 
 var (
+	_ p2p.TypedHandler[*pb.SyncGetRangeProofRequest, *pb.RangeProof]                  = (*GetRangeProofHandler)(nil)
+	_ p2p.RequestValidator[*pb.SyncGetRangeProofRequest]                              = (*GetRangeProofHandler)(nil)
+	_ p2p.TypedHandler[*pb.SyncGetChangeProofRequest, *pb.SyncGetChangeProofResponse] = (*GetChangeProofHandler)(nil)
+	_ p2p.RequestValidator[*pb.SyncGetChangeProofRequest]                             = (*GetChangeProofHandler)(nil)
+
 	ErrMinProofSizeIsTooLarge = errors.New("cannot generate any proof within the requested limit")
 
+	// ErrInsufficientHistory is returned to requesters when the server doesn't
+	// have the history needed to serve the request. The request should be
+	// sent to another peer.
+	ErrInsufficientHistory = &common.AppError{
+		Code:    1,
+		Message: "insufficient history",
+	}
+	// ErrDeadlineExceeded is returned to requesters when the server doesn't
+	// have enough time left to serve the request before its deadline.
+	ErrDeadlineExceeded = &common.AppError{
+		Code:    2,
+		Message: "deadline exceeded",
+	}
+
 	errInvalidBytesLimit    = errors.New("bytes limit must be greater than 0")
 	errInvalidKeyLimit      = errors.New("key limit must be greater than 0")
 	errInvalidStartRootHash = fmt.Errorf("start root hash must have length %d", hashing.HashLen)
@@ -52,12 +78,308 @@ var (
 	errInvalidRootHash      = fmt.Errorf("root hash must have length %d", hashing.HashLen)
 )
 
+func maybeBytesToMaybe(mb *pb.MaybeBytes) maybe.Maybe[[]byte] {
+	if mb != nil && !mb.IsNothing {
+		return maybe.Some(mb.Value)
+	}
+	return maybe.Nothing[[]byte]()
+}
+
+// NewGetRangeProofHandler returns a handler that serves range proofs from
+// [db]. It should be registered with p2p.NewTypedHandler.
+func NewGetRangeProofHandler(log logging.Logger, db DB) *GetRangeProofHandler {
+	return &GetRangeProofHandler{
+		log: log,
+		db:  db,
+	}
+}
+
+type GetRangeProofHandler struct {
+	log logging.Logger
+	db  DB
+}
+
+func (*GetRangeProofHandler) ValidateRequest(req *pb.SyncGetRangeProofRequest) error {
+	return validateRangeProofRequest(req)
+}
+
+func (g *GetRangeProofHandler) AppRequest(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	deadline time.Time,
+	req *pb.SyncGetRangeProofRequest,
+) (*pb.RangeProof, *common.AppError) {
+	ctx, cancel, ok := withRequestDeadline(ctx, deadline)
+	if !ok {
+		g.log.Debug("deadline to get range proof has expired",
+			zap.Stringer("nodeID", nodeID),
+			zap.Stringer("req", req),
+		)
+		return nil, ErrDeadlineExceeded
+	}
+	defer cancel()
+
+	proof, err := getRangeProof(ctx, g.db, req)
+	if err != nil {
+		g.log.Debug("failed to get range proof",
+			zap.Stringer("nodeID", nodeID),
+			zap.Stringer("req", req),
+			zap.Error(err),
+		)
+		return nil, toAppError(err)
+	}
+	if proof == nil {
+		g.log.Debug("insufficient history to get range proof",
+			zap.Stringer("nodeID", nodeID),
+			zap.Stringer("req", req),
+		)
+		return nil, ErrInsufficientHistory
+	}
+	return proof, nil
+}
+
+// NewGetChangeProofHandler returns a handler that serves change proofs from
+// [db]. It should be registered with p2p.NewTypedHandler.
+func NewGetChangeProofHandler(log logging.Logger, db DB) *GetChangeProofHandler {
+	return &GetChangeProofHandler{
+		log: log,
+		db:  db,
+	}
+}
+
+type GetChangeProofHandler struct {
+	log logging.Logger
+	db  DB
+}
+
+func (*GetChangeProofHandler) ValidateRequest(req *pb.SyncGetChangeProofRequest) error {
+	return validateChangeProofRequest(req)
+}
+
+func (g *GetChangeProofHandler) AppRequest(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	deadline time.Time,
+	req *pb.SyncGetChangeProofRequest,
+) (*pb.SyncGetChangeProofResponse, *common.AppError) {
+	ctx, cancel, ok := withRequestDeadline(ctx, deadline)
+	if !ok {
+		g.log.Debug("deadline to get change proof has expired",
+			zap.Stringer("nodeID", nodeID),
+			zap.Stringer("req", req),
+		)
+		return nil, ErrDeadlineExceeded
+	}
+	defer cancel()
+
+	response, err := getChangeProof(ctx, g.db, req)
+	if err != nil {
+		g.log.Debug("failed to get change proof",
+			zap.Stringer("nodeID", nodeID),
+			zap.Stringer("req", req),
+			zap.Error(err),
+		)
+		return nil, toAppError(err)
+	}
+	if response == nil {
+		g.log.Debug("insufficient history to get change proof",
+			zap.Stringer("nodeID", nodeID),
+			zap.Stringer("req", req),
+		)
+		return nil, ErrInsufficientHistory
+	}
+	return response, nil
+}
+
+// withRequestDeadline returns a context that expires [deadlineBuffer] before
+// [deadline], so that the response has a reasonable chance of reaching the
+// requester in time. Returns false if less than [minRequestHandlingDuration]
+// would be left to handle the request.
+func withRequestDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc, bool) {
+	bufferedDeadline := deadline.Add(-deadlineBuffer)
+	if time.Until(bufferedDeadline) < minRequestHandlingDuration {
+		return nil, nil, false
+	}
+	ctx, cancel := context.WithDeadline(ctx, bufferedDeadline)
+	return ctx, cancel, true
+}
+
+// toAppError converts an error that occurred while generating a proof into the
+// error returned to the requester.
+func toAppError(err error) *common.AppError {
+	switch {
+	case errors.Is(err, merkledb.ErrNoEndRoot):
+		// The end root isn't in the history of the db.
+		return ErrInsufficientHistory
+	case isTimeout(err):
+		return ErrDeadlineExceeded
+	default:
+		return p2p.ErrUnexpected
+	}
+}
+
+// Get the change proof specified by [req].
+// If [db] doesn't have sufficient history to generate the change proof, a
+// range proof of the end root is returned instead.
+// If the generated proof is too large, the key limit is reduced
+// and the proof is regenerated. This process is repeated until
+// the proof is smaller than [req.BytesLimit].
+// If [db] doesn't have [req.EndRootHash] in its history, returns
+// [merkledb.ErrNoEndRoot].
+// If no sufficiently small proof can be generated, returns [ErrMinProofSizeIsTooLarge].
+func getChangeProof(
+	ctx context.Context,
+	db DB,
+	req *pb.SyncGetChangeProofRequest,
+) (*pb.SyncGetChangeProofResponse, error) {
+	// override limits if they exceed caps
+	var (
+		keyLimit   = min(req.KeyLimit, maxKeyValuesLimit)
+		bytesLimit = min(int(req.BytesLimit), maxByteSizeLimit)
+		start      = maybeBytesToMaybe(req.StartKey)
+		end        = maybeBytesToMaybe(req.EndKey)
+	)
+
+	startRoot, err := ids.ToID(req.StartRootHash)
+	if err != nil {
+		return nil, err
+	}
+
+	endRoot, err := ids.ToID(req.EndRootHash)
+	if err != nil {
+		return nil, err
+	}
+
+	for keyLimit > 0 {
+		changeProof, err := db.GetChangeProof(ctx, startRoot, endRoot, start, end, int(keyLimit))
+		if err != nil {
+			if !errors.Is(err, merkledb.ErrInsufficientHistory) || errors.Is(err, merkledb.ErrNoEndRoot) {
+				// We should only fail to get a change proof if we have insufficient history.
+				// Other errors are unexpected.
+				return nil, err
+			}
+
+			// [db] doesn't have sufficient history to generate change proof.
+			// Generate a range proof for the end root ID instead.
+			return getProof(
+				ctx,
+				db,
+				&pb.SyncGetRangeProofRequest{
+					RootHash:   req.EndRootHash,
+					StartKey:   req.StartKey,
+					EndKey:     req.EndKey,
+					KeyLimit:   req.KeyLimit,
+					BytesLimit: req.BytesLimit,
+				},
+				func(rangeProof *merkledb.RangeProof) *pb.SyncGetChangeProofResponse {
+					return &pb.SyncGetChangeProofResponse{
+						Response: &pb.SyncGetChangeProofResponse_RangeProof{
+							RangeProof: rangeProof.ToProto(),
+						},
+					}
+				},
+			)
+		}
+
+		// We generated a change proof. See if it's small enough.
+		response := &pb.SyncGetChangeProofResponse{
+			Response: &pb.SyncGetChangeProofResponse_ChangeProof{
+				ChangeProof: changeProof.ToProto(),
+			},
+		}
+		if proto.Size(response) < bytesLimit {
+			return response, nil
+		}
+
+		// The proof was too large. Try to shrink it.
+		keyLimit = uint32(len(changeProof.KeyChanges)) / 2
+	}
+	return nil, ErrMinProofSizeIsTooLarge
+}
+
+// Get the range proof specified by [req].
+// See getProof for more docs.
+func getRangeProof(
+	ctx context.Context,
+	db DB,
+	req *pb.SyncGetRangeProofRequest,
+) (*pb.RangeProof, error) {
+	// override limits if they exceed caps
+	req.KeyLimit = min(req.KeyLimit, maxKeyValuesLimit)
+	req.BytesLimit = min(req.BytesLimit, maxByteSizeLimit)
+
+	return getProof(
+		ctx,
+		db,
+		req,
+		(*merkledb.RangeProof).ToProto,
+	)
+}
+
+// Get the range proof specified by [req], converted to a message with
+// [toProto].
+// If the generated proof is too large, the key limit is reduced
+// and the proof is regenerated. This process is repeated until
+// the proof is smaller than [req.BytesLimit].
+// When a sufficiently small proof is generated, returns it.
+// If [db] doesn't have sufficient history to generate the proof, returns an
+// empty message.
+// If no sufficiently small proof can be generated, returns [ErrMinProofSizeIsTooLarge].
+// TODO improve range proof generation so we don't need to iteratively
+// reduce the key limit.
+func getProof[T proto.Message](
+	ctx context.Context,
+	db DB,
+	req *pb.SyncGetRangeProofRequest,
+	toProto func(*merkledb.RangeProof) T,
+) (T, error) {
+	var empty T
+	root, err := ids.ToID(req.RootHash)
+	if err != nil {
+		return empty, err
+	}
+
+	keyLimit := int(req.KeyLimit)
+
+	for keyLimit > 0 {
+		rangeProof, err := db.GetRangeProofAtRoot(
+			ctx,
+			root,
+			maybeBytesToMaybe(req.StartKey),
+			maybeBytesToMaybe(req.EndKey),
+			keyLimit,
+		)
+		if err != nil {
+			if errors.Is(err, merkledb.ErrInsufficientHistory) {
+				return empty, nil // drop request
+			}
+			return empty, err
+		}
+
+		proof := toProto(rangeProof)
+		if proto.Size(proof) < int(req.BytesLimit) {
+			return proof, nil
+		}
+
+		// The proof was too large. Try to shrink it.
+		keyLimit = len(rangeProof.KeyValues) / 2
+	}
+	return empty, ErrMinProofSizeIsTooLarge
+}
+
+// NetworkServer serves the legacy sync.Request messages sent by peers that
+// don't use the typed handlers.
+//
+// Deprecated: Proofs are served by the handlers returned by
+// NewGetRangeProofHandler and NewGetChangeProofHandler. NetworkServer will be
+// removed in a future release.
 type NetworkServer struct {
 	appSender common.AppSender // Used to respond to peer requests via AppResponse.
 	db        DB
 	log       logging.Logger
 }
 
+// Deprecated: See NetworkServer.
 func NewNetworkServer(appSender common.AppSender, db DB, log logging.Logger) *NetworkServer {
 	return &NetworkServer{
 		appSender: appSender,
@@ -93,14 +415,8 @@ func (s *NetworkServer) AppRequest(
 		zap.Uint32("requestID", requestID),
 	)
 
-	// bufferedDeadline is half the time till actual deadline so that the message has a
-	// reasonable chance of completing its processing and sending the response to the peer.
-	timeTillDeadline := time.Until(deadline)
-	bufferedDeadline := time.Now().Add(timeTillDeadline / 2)
-
-	// check if we have enough time to handle this request.
-	// TODO danlaine: Do we need this? Why?
-	if time.Until(bufferedDeadline) < minRequestHandlingDuration {
+	ctx, cancel, ok := withRequestDeadline(ctx, deadline)
+	if !ok {
 		// Drop the request if we already missed the deadline to respond.
 		s.log.Info(
 			"deadline to process AppRequest has expired, skipping",
@@ -109,8 +425,6 @@ func (s *NetworkServer) AppRequest(
 		)
 		return nil
 	}
-
-	ctx, cancel := context.WithDeadline(ctx, bufferedDeadline)
 	defer cancel()
 
 	var err error
@@ -148,13 +462,6 @@ func (s *NetworkServer) AppRequest(
 	return nil
 }
 
-func maybeBytesToMaybe(mb *pb.MaybeBytes) maybe.Maybe[[]byte] {
-	if mb != nil && !mb.IsNothing {
-		return maybe.Some(mb.Value)
-	}
-	return maybe.Nothing[[]byte]()
-}
-
 // Generates a change proof and sends it to [nodeID].
 // If [errAppSendFailed] is returned, this should be considered fatal.
 func (s *NetworkServer) HandleChangeProofRequest(
@@ -174,103 +481,16 @@ func (s *NetworkServer) HandleChangeProofRequest(
 		return nil // dropping request
 	}
 
-	// override limits if they exceed caps
-	var (
-		keyLimit   = min(req.KeyLimit, maxKeyValuesLimit)
-		bytesLimit = min(int(req.BytesLimit), maxByteSizeLimit)
-		start      = maybeBytesToMaybe(req.StartKey)
-		end        = maybeBytesToMaybe(req.EndKey)
-	)
-
-	startRoot, err := ids.ToID(req.StartRootHash)
-	if err != nil {
-		return err
+	response, err := getChangeProof(ctx, s.db, req)
+	if errors.Is(err, merkledb.ErrNoEndRoot) {
+		// [s.db] doesn't have [endRoot] in its history.
+		// We can't generate a change/range proof. Drop this request.
+		return nil
 	}
-
-	endRoot, err := ids.ToID(req.EndRootHash)
 	if err != nil {
 		return err
 	}
-
-	for keyLimit > 0 {
-		changeProof, err := s.db.GetChangeProof(ctx, startRoot, endRoot, start, end, int(keyLimit))
-		if err != nil {
-			if !errors.Is(err, merkledb.ErrInsufficientHistory) {
-				// We should only fail to get a change proof if we have insufficient history.
-				// Other errors are unexpected.
-				return err
-			}
-			if errors.Is(err, merkledb.ErrNoEndRoot) {
-				// [s.db] doesn't have [endRoot] in its history.
-				// We can't generate a change/range proof. Drop this request.
-				return nil
-			}
-
-			// [s.db] doesn't have sufficient history to generate change proof.
-			// Generate a range proof for the end root ID instead.
-			proofBytes, err := getRangeProof(
-				ctx,
-				s.db,
-				&pb.SyncGetRangeProofRequest{
-					RootHash:   req.EndRootHash,
-					StartKey:   req.StartKey,
-					EndKey:     req.EndKey,
-					KeyLimit:   req.KeyLimit,
-					BytesLimit: req.BytesLimit,
-				},
-				func(rangeProof *merkledb.RangeProof) ([]byte, error) {
-					return proto.Marshal(&pb.SyncGetChangeProofResponse{
-						Response: &pb.SyncGetChangeProofResponse_RangeProof{
-							RangeProof: rangeProof.ToProto(),
-						},
-					})
-				},
-			)
-			if err != nil {
-				return err
-			}
-
-			if err := s.appSender.SendAppResponse(ctx, nodeID, requestID, proofBytes); err != nil {
-				s.log.Fatal(
-					"failed to send app response",
-					zap.Stringer("nodeID", nodeID),
-					zap.Uint32("requestID", requestID),
-					zap.Int("responseLen", len(proofBytes)),
-					zap.Error(err),
-				)
-				return fmt.Errorf("%w: %w", errAppSendFailed, err)
-			}
-			return nil
-		}
-
-		// We generated a change proof. See if it's small enough.
-		proofBytes, err := proto.Marshal(&pb.SyncGetChangeProofResponse{
-			Response: &pb.SyncGetChangeProofResponse_ChangeProof{
-				ChangeProof: changeProof.ToProto(),
-			},
-		})
-		if err != nil {
-			return err
-		}
-
-		if len(proofBytes) < bytesLimit {
-			if err := s.appSender.SendAppResponse(ctx, nodeID, requestID, proofBytes); err != nil {
-				s.log.Fatal(
-					"failed to send app response",
-					zap.Stringer("nodeID", nodeID),
-					zap.Uint32("requestID", requestID),
-					zap.Int("responseLen", len(proofBytes)),
-					zap.Error(err),
-				)
-				return fmt.Errorf("%w: %w", errAppSendFailed, err)
-			}
-			return nil
-		}
-
-		// The proof was too large. Try to shrink it.
-		keyLimit = uint32(len(changeProof.KeyChanges)) / 2
-	}
-	return ErrMinProofSizeIsTooLarge
+	return s.sendResponse(ctx, nodeID, requestID, response)
 }
 
 // Generates a range proof and sends it to [nodeID].
@@ -292,27 +512,32 @@ func (s *NetworkServer) HandleRangeProofRequest(
 		return nil // drop request
 	}
 
-	// override limits if they exceed caps
-	req.KeyLimit = min(req.KeyLimit, maxKeyValuesLimit)
-	req.BytesLimit = min(req.BytesLimit, maxByteSizeLimit)
+	response, err := getRangeProof(ctx, s.db, req)
+	if err != nil {
+		return err
+	}
+	return s.sendResponse(ctx, nodeID, requestID, response)
+}
 
-	proofBytes, err := getRangeProof(
-		ctx,
-		s.db,
-		req,
-		func(rangeProof *merkledb.RangeProof) ([]byte, error) {
-			return proto.Marshal(rangeProof.ToProto())
-		},
-	)
+// sendResponse sends [response] to [nodeID].
+// If [errAppSendFailed] is returned, this should be considered fatal.
+func (s *NetworkServer) sendResponse(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	requestID uint32,
+	response proto.Message,
+) error {
+	responseBytes, err := proto.Marshal(response)
 	if err != nil {
 		return err
 	}
-	if err := s.appSender.SendAppResponse(ctx, nodeID, requestID, proofBytes); err != nil {
+
+	if err := s.appSender.SendAppResponse(ctx, nodeID, requestID, responseBytes); err != nil {
 		s.log.Fatal(
 			"failed to send app response",
 			zap.Stringer("nodeID", nodeID),
 			zap.Uint32("requestID", requestID),
-			zap.Int("responseLen", len(proofBytes)),
+			zap.Int("responseLen", len(responseBytes)),
 			zap.Error(err),
 		)
 		return fmt.Errorf("%w: %w", errAppSendFailed, err)
@@ -320,57 +545,6 @@ func (s *NetworkServer) HandleRangeProofRequest(
 	return nil
 }
 
-// Get the range proof specified by [req].
-// If the generated proof is too large, the key limit is reduced
-// and the proof is regenerated. This process is repeated until
-// the proof is smaller than [req.BytesLimit].
-// When a sufficiently small proof is generated, returns it.
-// If no sufficiently small proof can be generated, returns [ErrMinProofSizeIsTooLarge].
-// TODO improve range proof generation so we don't need to iteratively
-// reduce the key limit.
-func getRangeProof(
-	ctx context.Context,
-	db DB,
-	req *pb.SyncGetRangeProofRequest,
-	marshalFunc func(*merkledb.RangeProof) ([]byte, error),
-) ([]byte, error) {
-	root, err := ids.ToID(req.RootHash)
-	if err != nil {
-		return nil, err
-	}
-
-	keyLimit := int(req.KeyLimit)
-
-	for keyLimit > 0 {
-		rangeProof, err := db.GetRangeProofAtRoot(
-			ctx,
-			root,
-			maybeBytesToMaybe(req.StartKey),
-			maybeBytesToMaybe(req.EndKey),
-			keyLimit,
-		)
-		if err != nil {
-			if errors.Is(err, merkledb.ErrInsufficientHistory) {
-				return nil, nil // drop request
-			}
-			return nil, err
-		}
-
-		proofBytes, err := marshalFunc(rangeProof)
-		if err != nil {
-			return nil, err
-		}
-
-		if len(proofBytes) < int(req.BytesLimit) {
-			return proofBytes, nil
-		}
-
-		// The proof was too large. Try to shrink it.
-		keyLimit = len(rangeProof.KeyValues) / 2
-	}
-	return nil, ErrMinProofSizeIsTooLarge
-}
-
 // isTimeout returns true if err is a timeout from a context cancellation
 // or a context cancellation over grpc.
 func isTimeout(err error) bool {
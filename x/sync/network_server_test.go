@@ -15,6 +15,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/p2p"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/x/merkledb"
@@ -34,11 +35,10 @@ func Test_Server_GetRangeProof(t *testing.T) {
 
 	tests := map[string]struct {
 		request                  *pb.SyncGetRangeProofRequest
-		expectedErr              error
+		expectedErr              *common.AppError
 		expectedResponseLen      int
 		expectedMaxResponseBytes int
 		nodeID                   ids.NodeID
-		proofNil                 bool
 	}{
 		"proof too large": {
 			request: &pb.SyncGetRangeProofRequest{
@@ -46,8 +46,7 @@ func Test_Server_GetRangeProof(t *testing.T) {
 				KeyLimit:   defaultRequestKeyLimit,
 				BytesLimit: 1000,
 			},
-			proofNil:    true,
-			expectedErr: ErrMinProofSizeIsTooLarge,
+			expectedErr: p2p.ErrUnexpected,
 		},
 		"byteslimit is 0": {
 			request: &pb.SyncGetRangeProofRequest{
@@ -55,7 +54,7 @@ func Test_Server_GetRangeProof(t *testing.T) {
 				KeyLimit:   defaultRequestKeyLimit,
 				BytesLimit: 0,
 			},
-			proofNil: true,
+			expectedErr: p2p.ErrInvalidRequest,
 		},
 		"keylimit is 0": {
 			request: &pb.SyncGetRangeProofRequest{
@@ -63,7 +62,7 @@ func Test_Server_GetRangeProof(t *testing.T) {
 				KeyLimit:   defaultRequestKeyLimit,
 				BytesLimit: 0,
 			},
-			proofNil: true,
+			expectedErr: p2p.ErrInvalidRequest,
 		},
 		"keys out of order": {
 			request: &pb.SyncGetRangeProofRequest{
@@ -73,7 +72,7 @@ func Test_Server_GetRangeProof(t *testing.T) {
 				StartKey:   &pb.MaybeBytes{Value: []byte{1}},
 				EndKey:     &pb.MaybeBytes{Value: []byte{0}},
 			},
-			proofNil: true,
+			expectedErr: p2p.ErrInvalidRequest,
 		},
 		"key limit too large": {
 			request: &pb.SyncGetRangeProofRequest{
@@ -97,46 +96,31 @@ func Test_Server_GetRangeProof(t *testing.T) {
 				KeyLimit:   defaultRequestKeyLimit,
 				BytesLimit: defaultRequestByteSizeLimit,
 			},
-			proofNil: true,
+			expectedErr: p2p.ErrInvalidRequest,
 		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			require := require.New(t)
-			ctrl := gomock.NewController(t)
-			sender := common.NewMockSender(ctrl)
-			var proof *merkledb.RangeProof
-			sender.EXPECT().SendAppResponse(
-				gomock.Any(), // ctx
-				gomock.Any(), // nodeID
-				gomock.Any(), // requestID
-				gomock.Any(), // responseBytes
-			).DoAndReturn(
-				func(_ context.Context, _ ids.NodeID, _ uint32, responseBytes []byte) error {
-					// grab a copy of the proof so we can inspect it later
-					if !test.proofNil {
-						var proofProto pb.RangeProof
-						require.NoError(proto.Unmarshal(responseBytes, &proofProto))
-
-						var p merkledb.RangeProof
-						require.NoError(p.UnmarshalProto(&proofProto))
-						proof = &p
-					}
-					return nil
-				},
-			).AnyTimes()
-			handler := NewNetworkServer(sender, smallTrieDB, logging.NoLog{})
-			err := handler.HandleRangeProofRequest(context.Background(), test.nodeID, 0, test.request)
-			require.ErrorIs(err, test.expectedErr)
+			handler := p2p.NewTypedHandler(
+				NewGetRangeProofHandler(logging.NoLog{}, smallTrieDB),
+				logging.NoLog{},
+			)
+			requestBytes, err := proto.Marshal(test.request)
+			require.NoError(err)
+
+			responseBytes, appErr := handler.AppRequest(context.Background(), test.nodeID, time.Now().Add(time.Hour), requestBytes)
+			require.Equal(test.expectedErr, appErr)
 			if test.expectedErr != nil {
 				return
 			}
-			if test.proofNil {
-				require.Nil(proof)
-				return
-			}
-			require.NotNil(proof)
+
+			var proofProto pb.RangeProof
+			require.NoError(proto.Unmarshal(responseBytes, &proofProto))
+
+			var proof merkledb.RangeProof
+			require.NoError(proof.UnmarshalProto(&proofProto))
 			if test.expectedResponseLen > 0 {
 				require.LessOrEqual(len(proof.KeyValues), test.expectedResponseLen)
 			}
@@ -200,11 +184,10 @@ func Test_Server_GetChangeProof(t *testing.T) {
 
 	tests := map[string]struct {
 		request                  *pb.SyncGetChangeProofRequest
-		expectedErr              error
+		expectedErr              *common.AppError
 		expectedResponseLen      int
 		expectedMaxResponseBytes int
 		nodeID                   ids.NodeID
-		proofNil                 bool
 		expectRangeProof         bool // Otherwise expect change proof
 	}{
 		"byteslimit is 0": {
@@ -214,7 +197,7 @@ func Test_Server_GetChangeProof(t *testing.T) {
 				KeyLimit:      defaultRequestKeyLimit,
 				BytesLimit:    0,
 			},
-			proofNil: true,
+			expectedErr: p2p.ErrInvalidRequest,
 		},
 		"keylimit is 0": {
 			request: &pb.SyncGetChangeProofRequest{
@@ -223,7 +206,7 @@ func Test_Server_GetChangeProof(t *testing.T) {
 				KeyLimit:      defaultRequestKeyLimit,
 				BytesLimit:    0,
 			},
-			proofNil: true,
+			expectedErr: p2p.ErrInvalidRequest,
 		},
 		"keys out of order": {
 			request: &pb.SyncGetChangeProofRequest{
@@ -234,7 +217,7 @@ func Test_Server_GetChangeProof(t *testing.T) {
 				StartKey:      &pb.MaybeBytes{Value: []byte{1}},
 				EndKey:        &pb.MaybeBytes{Value: []byte{0}},
 			},
-			proofNil: true,
+			expectedErr: p2p.ErrInvalidRequest,
 		},
 		"key limit too large": {
 			request: &pb.SyncGetChangeProofRequest{
@@ -275,8 +258,7 @@ func Test_Server_GetChangeProof(t *testing.T) {
 				KeyLimit:      defaultRequestKeyLimit,
 				BytesLimit:    defaultRequestByteSizeLimit,
 			},
-			expectedMaxResponseBytes: defaultRequestByteSizeLimit,
-			proofNil:                 true,
+			expectedErr: ErrInsufficientHistory,
 		},
 		"empt proof": {
 			request: &pb.SyncGetChangeProofRequest{
@@ -285,54 +267,28 @@ func Test_Server_GetChangeProof(t *testing.T) {
 				KeyLimit:      defaultRequestKeyLimit,
 				BytesLimit:    defaultRequestByteSizeLimit,
 			},
-			expectedMaxResponseBytes: defaultRequestByteSizeLimit,
-			proofNil:                 true,
+			expectedErr: p2p.ErrInvalidRequest,
 		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			require := require.New(t)
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			// Store proof returned by server in [proofResult]
-			var proofResult *pb.SyncGetChangeProofResponse
-			var proofBytes []byte
-			sender := common.NewMockSender(ctrl)
-			sender.EXPECT().SendAppResponse(
-				gomock.Any(), // ctx
-				gomock.Any(), // nodeID
-				gomock.Any(), // requestID
-				gomock.Any(), // responseBytes
-			).DoAndReturn(
-				func(_ context.Context, _ ids.NodeID, _ uint32, responseBytes []byte) error {
-					if test.proofNil {
-						return nil
-					}
-					proofBytes = responseBytes
-
-					// grab a copy of the proof so we can inspect it later
-					var responseProto pb.SyncGetChangeProofResponse
-					require.NoError(proto.Unmarshal(responseBytes, &responseProto))
-					proofResult = &responseProto
-
-					return nil
-				},
-			).AnyTimes()
+			handler := p2p.NewTypedHandler(
+				NewGetChangeProofHandler(logging.NoLog{}, trieDB),
+				logging.NoLog{},
+			)
+			requestBytes, err := proto.Marshal(test.request)
+			require.NoError(err)
 
-			handler := NewNetworkServer(sender, trieDB, logging.NoLog{})
-			err := handler.HandleChangeProofRequest(context.Background(), test.nodeID, 0, test.request)
-			require.ErrorIs(err, test.expectedErr)
+			proofBytes, appErr := handler.AppRequest(context.Background(), test.nodeID, time.Now().Add(time.Hour), requestBytes)
+			require.Equal(test.expectedErr, appErr)
 			if test.expectedErr != nil {
 				return
 			}
 
-			if test.proofNil {
-				require.Nil(proofResult)
-				return
-			}
-			require.NotNil(proofResult)
+			var proofResult pb.SyncGetChangeProofResponse
+			require.NoError(proto.Unmarshal(proofBytes, &proofResult))
 
 			if test.expectRangeProof {
 				require.NotNil(proofResult.GetRangeProof())
@@ -348,7 +304,6 @@ func Test_Server_GetChangeProof(t *testing.T) {
 				}
 			}
 
-			require.NoError(err)
 			require.LessOrEqual(len(proofBytes), int(test.request.BytesLimit))
 			if test.expectedMaxResponseBytes > 0 {
 				require.LessOrEqual(len(proofBytes), test.expectedMaxResponseBytes)
@@ -357,7 +312,7 @@ func Test_Server_GetChangeProof(t *testing.T) {
 	}
 }
 
-// Test that AppRequest returns a non-nil error if we fail to send
+// Test that the legacy AppRequest returns a non-nil error if we fail to send
 // an AppRequest or AppResponse.
 func TestAppRequestErrAppSendFailed(t *testing.T) {
 	startRootID := ids.GenerateTestID()
@@ -465,3 +420,96 @@ func TestAppRequestErrAppSendFailed(t *testing.T) {
 		})
 	}
 }
+
+func TestTypedGetRangeProofHandler(t *testing.T) {
+	now := time.Now().UnixNano()
+	t.Logf("seed: %d", now)
+	r := rand.New(rand.NewSource(now)) // #nosec G404
+
+	db, _, err := generateTrieWithMinKeyLen(t, r, defaultRequestKeyLimit, 1)
+	require.NoError(t, err)
+	root, err := db.GetMerkleRoot(context.Background())
+	require.NoError(t, err)
+
+	unknownRoot := ids.GenerateTestID()
+	tests := []struct {
+		name        string
+		request     *pb.SyncGetRangeProofRequest
+		deadline    time.Time
+		expectedErr *common.AppError
+	}{
+		{
+			name: "valid request",
+			request: &pb.SyncGetRangeProofRequest{
+				RootHash:   root[:],
+				KeyLimit:   defaultRequestKeyLimit,
+				BytesLimit: defaultRequestByteSizeLimit,
+			},
+			deadline: time.Now().Add(time.Hour),
+		},
+		{
+			name: "invalid request",
+			request: &pb.SyncGetRangeProofRequest{
+				RootHash: root[:],
+				KeyLimit: defaultRequestKeyLimit,
+			},
+			deadline:    time.Now().Add(time.Hour),
+			expectedErr: p2p.ErrInvalidRequest,
+		},
+		{
+			name: "proof too large",
+			request: &pb.SyncGetRangeProofRequest{
+				RootHash:   root[:],
+				KeyLimit:   defaultRequestKeyLimit,
+				BytesLimit: 1000,
+			},
+			deadline:    time.Now().Add(time.Hour),
+			expectedErr: p2p.ErrUnexpected,
+		},
+		{
+			name: "insufficient history",
+			request: &pb.SyncGetRangeProofRequest{
+				RootHash:   unknownRoot[:],
+				KeyLimit:   defaultRequestKeyLimit,
+				BytesLimit: defaultRequestByteSizeLimit,
+			},
+			deadline:    time.Now().Add(time.Hour),
+			expectedErr: ErrInsufficientHistory,
+		},
+		{
+			name: "deadline expired",
+			request: &pb.SyncGetRangeProofRequest{
+				RootHash:   root[:],
+				KeyLimit:   defaultRequestKeyLimit,
+				BytesLimit: defaultRequestByteSizeLimit,
+			},
+			deadline:    time.Now().Add(deadlineBuffer),
+			expectedErr: ErrDeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			handler := p2p.NewTypedHandler(
+				NewGetRangeProofHandler(logging.NoLog{}, db),
+				logging.NoLog{},
+			)
+
+			requestBytes, err := proto.Marshal(tt.request)
+			require.NoError(err)
+
+			responseBytes, appErr := handler.AppRequest(context.Background(), ids.EmptyNodeID, tt.deadline, requestBytes)
+			require.Equal(tt.expectedErr, appErr)
+			if tt.expectedErr != nil {
+				return
+			}
+
+			proof := &pb.RangeProof{}
+			require.NoError(proto.Unmarshal(responseBytes, proof))
+			require.NotEmpty(proof.KeyValues)
+			require.Less(len(responseBytes), int(tt.request.BytesLimit))
+		})
+	}
+}